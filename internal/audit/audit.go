@@ -0,0 +1,52 @@
+// Package audit provides a durable record of every automated intervention
+// and every alert notification cpra sent, with its outcome. The result
+// systems hand events to a Sink from inside the ECS tick; sinks buffer and
+// persist asynchronously, so recording never blocks the tick. When the
+// buffer is full the event is dropped and counted rather than stalling the
+// scheduler — the audit log is a compliance record, not a delivery
+// guarantee.
+//
+// SQLiteSink is the built-in implementation. Building with the noaudit tag
+// strips the SQLite driver; the sink constructor then returns an error.
+package audit
+
+import "time"
+
+// Event kinds recorded by the result systems.
+const (
+	KindIntervention = "intervention"
+	KindCode         = "code"
+)
+
+// Event is one audited outcome: an intervention that ran or a notification
+// that was sent (or failed to send).
+type Event struct {
+	// Time is when the result was applied, not when the job started.
+	Time time.Time `json:"time"`
+	// Monitor is the monitor name; EntityID its ECS entity for debugging
+	// across reloads (entities are recycled, names are stable).
+	Monitor  string `json:"monitor"`
+	EntityID uint32 `json:"entity_id"`
+	// Kind is KindIntervention or KindCode.
+	Kind string `json:"kind"`
+	// Action is the intervention action ("restart_container", ...) or the
+	// alert color ("red", "green") depending on Kind.
+	Action string `json:"action"`
+	// Success reports the job outcome; Error carries the failure text.
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// Attempts is how many times the driver ran, retries included; zero
+	// means untracked. Latency is wall time from first attempt to outcome.
+	Attempts int           `json:"attempts"`
+	Latency  time.Duration `json:"latency"`
+}
+
+// Sink receives audit events from the result systems. Record must never
+// block: implementations buffer internally and drop (counting) when full.
+type Sink interface {
+	Record(Event)
+	// Dropped returns how many events were discarded on a full buffer.
+	Dropped() uint64
+	// Close flushes buffered events and releases the backing store.
+	Close() error
+}
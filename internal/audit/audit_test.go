@@ -0,0 +1,194 @@
+//go:build !noaudit
+
+package audit
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitForEvents polls Recent until the monitor has at least n committed rows.
+// The writer goroutine persists asynchronously, so tests must wait for the
+// batch to land rather than assert immediately after Record.
+func waitForEvents(t *testing.T, s *SQLiteSink, monitor string, n int) []Event {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		events, err := s.Recent(monitor, 0)
+		if err != nil {
+			t.Fatalf("Recent failed: %v", err)
+		}
+		if len(events) >= n {
+			return events
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d events committed for %q, want %d", len(events), monitor, n)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+// TestSQLiteSink_RecordAndRecent round-trips events through the writer and
+// asserts Recent returns them newest-first with every column intact.
+func TestSQLiteSink_RecordAndRecent(t *testing.T) {
+	t.Parallel()
+
+	sink, err := NewSQLiteSink(":memory:", 0)
+	if err != nil {
+		t.Fatalf("NewSQLiteSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	base := time.Unix(1_700_000_000, 0)
+	sink.Record(Event{
+		Time: base, Monitor: "payments-api", EntityID: 7,
+		Kind: KindIntervention, Action: "restart_container",
+		Success: false, Error: "container not found", Attempts: 3,
+		Latency: 1500 * time.Millisecond,
+	})
+	sink.Record(Event{
+		Time: base.Add(time.Minute), Monitor: "payments-api", EntityID: 7,
+		Kind: KindCode, Action: "red", Success: true,
+	})
+	// A second monitor's events must not leak into the query.
+	sink.Record(Event{Time: base, Monitor: "edge-lb", Kind: KindCode, Action: "green", Success: true})
+
+	events := waitForEvents(t, sink, "payments-api", 2)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+
+	// Newest first: the code alert, then the intervention.
+	if events[0].Kind != KindCode || events[0].Action != "red" || !events[0].Success {
+		t.Errorf("events[0] = %+v, want successful red code alert", events[0])
+	}
+	got := events[1]
+	if got.Monitor != "payments-api" || got.EntityID != 7 || got.Kind != KindIntervention {
+		t.Errorf("identity fields = %+v, want payments-api/7/intervention", got)
+	}
+	if got.Action != "restart_container" || got.Success || got.Error != "container not found" {
+		t.Errorf("outcome fields = %+v, want failed restart_container", got)
+	}
+	if got.Attempts != 3 || got.Latency != 1500*time.Millisecond {
+		t.Errorf("attempts/latency = %d/%v, want 3/1.5s", got.Attempts, got.Latency)
+	}
+	if !got.Time.Equal(base) {
+		t.Errorf("time = %v, want %v", got.Time, base)
+	}
+
+	if d := sink.Dropped(); d != 0 {
+		t.Errorf("Dropped = %d, want 0", d)
+	}
+}
+
+// TestSQLiteSink_RecentLimit verifies the limit parameter and its default.
+func TestSQLiteSink_RecentLimit(t *testing.T) {
+	t.Parallel()
+
+	sink, err := NewSQLiteSink(":memory:", 0)
+	if err != nil {
+		t.Fatalf("NewSQLiteSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	for i := 0; i < 60; i++ {
+		sink.Record(Event{
+			Time: time.Unix(int64(i), 0), Monitor: "payments-api",
+			Kind: KindCode, Action: "red", Error: fmt.Sprintf("failure %d", i),
+		})
+	}
+	waitForEvents(t, sink, "payments-api", 50)
+
+	events, err := sink.Recent("payments-api", 2)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Recent(2) returned %d events, want 2", len(events))
+	}
+
+	// n <= 0 falls back to the default limit of 50, not everything.
+	events = waitForEvents(t, sink, "payments-api", 50)
+	if len(events) != 50 {
+		t.Errorf("Recent(0) returned %d events, want the default 50", len(events))
+	}
+}
+
+// TestSQLiteSink_CloseFlushes records against a file-backed database and
+// asserts Close persists every buffered event before closing: a fresh sink on
+// the same file sees all rows.
+func TestSQLiteSink_CloseFlushes(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.db")
+	sink, err := NewSQLiteSink(path, 0)
+	if err != nil {
+		t.Fatalf("NewSQLiteSink failed: %v", err)
+	}
+	for i := 0; i < 500; i++ {
+		sink.Record(Event{Time: time.Unix(int64(i), 0), Monitor: "payments-api", Kind: KindIntervention, Action: "exec"})
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if d := sink.Dropped(); d != 0 {
+		t.Fatalf("Dropped = %d, want 0 (buffer was large enough)", d)
+	}
+
+	reopened, err := NewSQLiteSink(path, 0)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer reopened.Close()
+	events, err := reopened.Recent("payments-api", 1000)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(events) != 500 {
+		t.Errorf("reopened db has %d events, want 500", len(events))
+	}
+}
+
+// TestSQLiteSink_DropOnFull fills the buffer with no writer draining it and
+// asserts Record drops and counts instead of blocking.
+func TestSQLiteSink_DropOnFull(t *testing.T) {
+	t.Parallel()
+
+	// Hand-built sink without the writer goroutine: the buffer stays full,
+	// which is how a stalled database looks to Record.
+	s := &SQLiteSink{
+		events:  make(chan Event, 2),
+		done:    make(chan struct{}),
+		flushed: make(chan struct{}),
+	}
+	for i := 0; i < 5; i++ {
+		s.Record(Event{Monitor: "payments-api", Kind: KindCode})
+	}
+	if d := s.Dropped(); d != 3 {
+		t.Errorf("Dropped = %d, want 3 (buffer of 2, 5 records)", d)
+	}
+}
+
+// TestSQLiteSink_RecordAfterClose verifies a closed sink counts late events
+// as drops rather than panicking on the closed channel.
+func TestSQLiteSink_RecordAfterClose(t *testing.T) {
+	t.Parallel()
+
+	sink, err := NewSQLiteSink(":memory:", 0)
+	if err != nil {
+		t.Fatalf("NewSQLiteSink failed: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	sink.Record(Event{Monitor: "payments-api", Kind: KindCode})
+	if d := sink.Dropped(); d != 1 {
+		t.Errorf("Dropped = %d, want 1", d)
+	}
+	// Close is idempotent.
+	if err := sink.Close(); err != nil {
+		t.Errorf("second Close failed: %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+//go:build !noaudit
+
+package audit
+
+import (
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go driver; no cgo toolchain needed
+)
+
+// DefaultBufferSize is the event buffer between the ECS tick and the writer
+// goroutine. At one event per intervention or notification this absorbs a
+// large alert storm before drops start.
+const DefaultBufferSize = 4096
+
+// writeBatchSize caps how many buffered events one transaction inserts.
+const writeBatchSize = 256
+
+const schema = `
+CREATE TABLE IF NOT EXISTS audit_events (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	ts         INTEGER NOT NULL, -- unix nanoseconds
+	monitor    TEXT    NOT NULL,
+	entity_id  INTEGER NOT NULL,
+	kind       TEXT    NOT NULL,
+	action     TEXT    NOT NULL,
+	success    INTEGER NOT NULL,
+	error      TEXT    NOT NULL DEFAULT '',
+	attempts   INTEGER NOT NULL DEFAULT 0,
+	latency_ms INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_audit_monitor ON audit_events(monitor, id);
+`
+
+// SQLiteSink persists audit events to a SQLite database through a buffered
+// channel and a dedicated writer goroutine. Record never blocks; a full
+// buffer drops the event and bumps the counter.
+type SQLiteSink struct {
+	db      *sql.DB
+	events  chan Event
+	done    chan struct{} // closed by Close to stop accepting
+	flushed chan struct{} // closed by the writer once drained
+	dropped atomic.Uint64
+}
+
+// NewSQLiteSink opens (creating if needed) the database at path and starts
+// the writer. Use ":memory:" for tests. A non-positive bufferSize uses
+// DefaultBufferSize.
+func NewSQLiteSink(path string, bufferSize int) (*SQLiteSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit database path is required")
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open audit db: %w", err)
+	}
+	// One connection: the writer goroutine is the only writer, and a single
+	// conn keeps ":memory:" databases from silently splitting per-conn.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create audit schema: %w", err)
+	}
+
+	s := &SQLiteSink{
+		db:      db,
+		events:  make(chan Event, bufferSize),
+		done:    make(chan struct{}),
+		flushed: make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Record implements Sink. It never blocks: events beyond the buffer are
+// dropped and counted.
+func (s *SQLiteSink) Record(ev Event) {
+	select {
+	case <-s.done:
+		s.dropped.Add(1)
+	default:
+		select {
+		case s.events <- ev:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// Dropped implements Sink.
+func (s *SQLiteSink) Dropped() uint64 { return s.dropped.Load() }
+
+// Close stops accepting events, flushes the buffer, and closes the database.
+func (s *SQLiteSink) Close() error {
+	select {
+	case <-s.done:
+		return nil // already closed
+	default:
+	}
+	close(s.done)
+	<-s.flushed
+	return s.db.Close()
+}
+
+// run drains the buffer into transactions until Close.
+func (s *SQLiteSink) run() {
+	defer close(s.flushed)
+	batch := make([]Event, 0, writeBatchSize)
+	for {
+		select {
+		case ev := <-s.events:
+			batch = append(batch[:0], ev)
+			s.fill(&batch)
+			s.write(batch)
+		case <-s.done:
+			// Final drain: whatever Record managed to buffer before Close.
+			for {
+				batch = batch[:0]
+				s.fill(&batch)
+				if len(batch) == 0 {
+					return
+				}
+				s.write(batch)
+			}
+		}
+	}
+}
+
+// fill moves buffered events into batch without blocking, up to the batch cap.
+func (s *SQLiteSink) fill(batch *[]Event) {
+	for len(*batch) < writeBatchSize {
+		select {
+		case ev := <-s.events:
+			*batch = append(*batch, ev)
+		default:
+			return
+		}
+	}
+}
+
+// write inserts one batch in a single transaction. Errors are counted as
+// drops: the audit trail must never wedge the writer.
+func (s *SQLiteSink) write(batch []Event) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.dropped.Add(uint64(len(batch)))
+		return
+	}
+	stmt, err := tx.Prepare(`INSERT INTO audit_events
+		(ts, monitor, entity_id, kind, action, success, error, attempts, latency_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		_ = tx.Rollback()
+		s.dropped.Add(uint64(len(batch)))
+		return
+	}
+	for _, ev := range batch {
+		success := 0
+		if ev.Success {
+			success = 1
+		}
+		if _, err := stmt.Exec(ev.Time.UnixNano(), ev.Monitor, int64(ev.EntityID), ev.Kind,
+			ev.Action, success, ev.Error, ev.Attempts, ev.Latency.Milliseconds()); err != nil {
+			s.dropped.Add(1)
+		}
+	}
+	_ = stmt.Close()
+	if err := tx.Commit(); err != nil {
+		s.dropped.Add(uint64(len(batch)))
+	}
+}
+
+// Recent returns the last n events recorded for the named monitor, newest
+// first. It reads committed rows only; events still in the buffer are not
+// visible. Intended for admin inspection surfaces.
+func (s *SQLiteSink) Recent(monitor string, n int) ([]Event, error) {
+	if n <= 0 {
+		n = 50
+	}
+	rows, err := s.db.Query(`SELECT ts, monitor, entity_id, kind, action, success, error, attempts, latency_ms
+		FROM audit_events WHERE monitor = ? ORDER BY id DESC LIMIT ?`, monitor, n)
+	if err != nil {
+		return nil, fmt.Errorf("query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var (
+			ev        Event
+			ts        int64
+			entityID  int64
+			success   int
+			latencyMs int64
+		)
+		if err := rows.Scan(&ts, &ev.Monitor, &entityID, &ev.Kind, &ev.Action,
+			&success, &ev.Error, &ev.Attempts, &latencyMs); err != nil {
+			return nil, fmt.Errorf("scan audit event: %w", err)
+		}
+		ev.Time = time.Unix(0, ts)
+		ev.EntityID = uint32(entityID)
+		ev.Success = success == 1
+		ev.Latency = time.Duration(latencyMs) * time.Millisecond
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
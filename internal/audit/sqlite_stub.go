@@ -0,0 +1,33 @@
+//go:build noaudit
+
+package audit
+
+import "fmt"
+
+// Stub for builds without the SQLite driver (noaudit tag). The type exists
+// so callers compile; constructing it always fails.
+
+// SQLiteSink is a stub when audit support is disabled.
+type SQLiteSink struct{}
+
+// ErrAuditDisabled is returned when audit support is compiled out.
+var ErrAuditDisabled = fmt.Errorf("audit support disabled (built with noaudit tag)")
+
+// NewSQLiteSink always returns ErrAuditDisabled.
+func NewSQLiteSink(path string, bufferSize int) (*SQLiteSink, error) {
+	return nil, ErrAuditDisabled
+}
+
+// Record is a no-op.
+func (s *SQLiteSink) Record(Event) {}
+
+// Dropped always returns zero.
+func (s *SQLiteSink) Dropped() uint64 { return 0 }
+
+// Close is a no-op.
+func (s *SQLiteSink) Close() error { return nil }
+
+// Recent always returns no events.
+func (s *SQLiteSink) Recent(monitor string, n int) ([]Event, error) {
+	return nil, ErrAuditDisabled
+}
@@ -0,0 +1,54 @@
+// Package clock abstracts time for the scheduling paths so tests can drive
+// interval, cooldown, and maintenance-window logic deterministically instead
+// of sleeping.
+//
+// Production code uses the System clock, which delegates to the time package.
+// Tests use a Manual clock whose time only moves when Advance is called, so a
+// one-hour monitor interval elapses in microseconds. Only scheduling
+// decisions go through an injected Clock; jobs keep real time for network
+// deadlines.
+package clock
+
+import "time"
+
+// Clock is the time source injected into the scheduling systems, the entity
+// manager, and the worker pool autoscaler.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTicker returns a ticker that delivers on every interval boundary.
+	// It panics if d is not positive, matching time.NewTicker.
+	NewTicker(d time.Duration) Ticker
+
+	// Sleep blocks until d has elapsed on this clock.
+	Sleep(d time.Duration)
+}
+
+// Ticker is the clock-agnostic subset of time.Ticker the schedulers use.
+type Ticker interface {
+	// C returns the delivery channel. Ticks are coalesced: a receiver that
+	// falls behind sees at most one pending tick, like time.Ticker.
+	C() <-chan time.Time
+
+	// Stop ends delivery. It does not close the channel.
+	Stop()
+}
+
+// System is the real clock backed by the time package.
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time        { return time.Now() }
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{t: time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (s systemTicker) C() <-chan time.Time { return s.t.C }
+func (s systemTicker) Stop()               { s.t.Stop() }
@@ -0,0 +1,124 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSystemClock(t *testing.T) {
+	before := time.Now()
+	got := System.Now()
+	after := time.Now()
+	if got.Before(before) || got.After(after) {
+		t.Errorf("System.Now() = %v, want between %v and %v", got, before, after)
+	}
+
+	ticker := System.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	select {
+	case <-ticker.C():
+	case <-time.After(time.Second):
+		t.Fatal("system ticker did not fire")
+	}
+}
+
+func TestManual_NowAndAdvance(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewManual(start)
+
+	if got := m.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want %v", got, start)
+	}
+
+	m.Advance(time.Hour)
+	if got := m.Now(); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("Now() after Advance = %v, want %v", got, start.Add(time.Hour))
+	}
+
+	// Negative advances are ignored.
+	m.Advance(-time.Hour)
+	if got := m.Now(); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("Now() after negative Advance = %v, want %v", got, start.Add(time.Hour))
+	}
+}
+
+func TestManual_Sleep(t *testing.T) {
+	m := NewManual(time.Time{})
+
+	done := make(chan struct{})
+	go func() {
+		m.Sleep(time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Sleep returned before the clock advanced")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	m.Advance(time.Hour)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Sleep did not return after the clock advanced past the deadline")
+	}
+
+	// Non-positive sleeps return immediately without an Advance.
+	m.Sleep(0)
+	m.Sleep(-time.Second)
+}
+
+func TestManual_Ticker(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewManual(start)
+	ticker := m.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	select {
+	case tick := <-ticker.C():
+		t.Fatalf("unexpected tick %v before the clock advanced", tick)
+	default:
+	}
+
+	m.Advance(time.Minute)
+	select {
+	case tick := <-ticker.C():
+		if want := start.Add(time.Minute); !tick.Equal(want) {
+			t.Errorf("tick = %v, want %v", tick, want)
+		}
+	default:
+		t.Fatal("expected a tick after advancing one interval")
+	}
+
+	// Several elapsed intervals coalesce into a single pending tick.
+	m.Advance(5 * time.Minute)
+	<-ticker.C()
+	select {
+	case tick := <-ticker.C():
+		t.Fatalf("unexpected second pending tick %v; ticks should coalesce", tick)
+	default:
+	}
+}
+
+func TestManual_TickerStop(t *testing.T) {
+	m := NewManual(time.Time{})
+	ticker := m.NewTicker(time.Second)
+	ticker.Stop()
+
+	m.Advance(time.Minute)
+	select {
+	case tick := <-ticker.C():
+		t.Fatalf("stopped ticker fired %v", tick)
+	default:
+	}
+}
+
+func TestManual_NewTickerPanicsOnNonPositive(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTicker(0) should panic like time.NewTicker")
+		}
+	}()
+	NewManual(time.Time{}).NewTicker(0)
+}
@@ -0,0 +1,128 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Manual is a Clock whose time only moves when Advance is called. It is safe
+// for concurrent use: Advance wakes any goroutine blocked in Sleep whose
+// deadline has been reached and fires any ticker whose interval has elapsed.
+type Manual struct {
+	mu       sync.Mutex
+	now      time.Time
+	sleepers []*manualSleeper
+	tickers  []*manualTicker
+}
+
+type manualSleeper struct {
+	deadline time.Time
+	done     chan struct{}
+}
+
+// NewManual returns a Manual clock starting at the given time. A zero start
+// uses the current wall time, which keeps timestamps plausible in logs.
+func NewManual(start time.Time) *Manual {
+	if start.IsZero() {
+		start = time.Now()
+	}
+	return &Manual{now: start}
+}
+
+// Now returns the clock's current time.
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Advance moves the clock forward by d, firing due tickers and waking due
+// sleepers. Negative durations are ignored: time does not run backwards.
+func (m *Manual) Advance(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	m.mu.Lock()
+	m.now = m.now.Add(d)
+	now := m.now
+
+	for _, t := range m.tickers {
+		t.fireUpTo(now)
+	}
+
+	remaining := m.sleepers[:0]
+	for _, s := range m.sleepers {
+		if s.deadline.After(now) {
+			remaining = append(remaining, s)
+			continue
+		}
+		close(s.done)
+	}
+	m.sleepers = remaining
+	m.mu.Unlock()
+}
+
+// Sleep blocks until d has elapsed on this clock, i.e. until Advance has
+// moved time past the deadline. Non-positive durations return immediately.
+func (m *Manual) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	m.mu.Lock()
+	s := &manualSleeper{deadline: m.now.Add(d), done: make(chan struct{})}
+	m.sleepers = append(m.sleepers, s)
+	m.mu.Unlock()
+	<-s.done
+}
+
+// NewTicker returns a ticker driven by Advance. Like time.NewTicker it
+// panics when d is not positive.
+func (m *Manual) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for Manual.NewTicker")
+	}
+	m.mu.Lock()
+	t := &manualTicker{clock: m, interval: d, next: m.now.Add(d), ch: make(chan time.Time, 1)}
+	m.tickers = append(m.tickers, t)
+	m.mu.Unlock()
+	return t
+}
+
+type manualTicker struct {
+	clock    *Manual
+	interval time.Duration
+	next     time.Time
+	ch       chan time.Time
+	stopped  bool
+}
+
+func (t *manualTicker) C() <-chan time.Time { return t.ch }
+
+// Stop ends delivery and detaches the ticker from the clock.
+func (t *manualTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+	for i, other := range t.clock.tickers {
+		if other == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			break
+		}
+	}
+}
+
+// fireUpTo delivers every tick due at or before now. The caller holds the
+// clock's lock. Sends are non-blocking so a slow receiver coalesces ticks,
+// matching time.Ticker.
+func (t *manualTicker) fireUpTo(now time.Time) {
+	if t.stopped {
+		return
+	}
+	for !t.next.After(now) {
+		select {
+		case t.ch <- t.next:
+		default:
+		}
+		t.next = t.next.Add(t.interval)
+	}
+}
@@ -0,0 +1,426 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cpra/internal/audit"
+	"cpra/internal/controller/components"
+	"cpra/internal/interning"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// adminDefaultLimit caps list responses when no limit parameter is given.
+const adminDefaultLimit = 100
+
+// Errors returned by TriggerPulse when a check cannot be forced.
+var (
+	// ErrUnknownMonitor is returned when no monitor with the name exists.
+	ErrUnknownMonitor = errors.New("unknown monitor")
+	// ErrMonitorDisabled is returned when the monitor carries the Disabled tag.
+	ErrMonitorDisabled = errors.New("monitor is disabled")
+	// ErrPulseInFlight is returned when a pulse check is already pending.
+	ErrPulseInFlight = errors.New("pulse check already in flight")
+	// ErrHeartbeatMonitor is returned for push-based heartbeat monitors,
+	// which have no pulse job to force.
+	ErrHeartbeatMonitor = errors.New("heartbeat monitors are push-based and cannot be triggered")
+)
+
+// MonitorSnapshot is the JSON representation of one monitor's runtime state
+// served by the admin API.
+type MonitorSnapshot struct {
+	Name string `json:"name"`
+	// Labels is the monitor's manifest metadata (team, tier, runbook URL).
+	Labels              interning.Labels `json:"labels,omitempty"`
+	Enabled             bool             `json:"enabled"`
+	PulsePending        bool             `json:"pulse_pending"`
+	InterventionPending bool             `json:"intervention_pending"`
+	ConsecutiveFailures int              `json:"consecutive_failures"`
+	LastCheckTime       time.Time        `json:"last_check_time"`
+	NextCheckTime       time.Time        `json:"next_check_time"`
+	// History holds the monitor's recent result samples oldest-first; empty
+	// when history is disabled (Config.HistorySize <= 0).
+	History []components.ResultSample `json:"history,omitempty"`
+}
+
+// AdminHandler returns an http.Handler serving the admin API:
+//
+//	GET  /monitors                 list monitors (?status=failed|enabled|disabled, ?limit=N)
+//	GET  /monitors/{name}          one monitor
+//	GET  /monitors/{name}/audit    recent audit events (?limit=N)
+//	POST /monitors/{name}/enable   enable a monitor
+//	POST /monitors/{name}/disable  disable a monitor
+//	POST /monitors/{name}/check    force an immediate pulse check
+//
+// All reads and mutations run as commands inside the ECS tick, never from
+// the HTTP goroutine, so the handler is only useful while the controller is
+// running. Enable/disable return 404 for unknown names and 409 while the
+// monitor has an intervention in flight. As with HeartbeatHandler, the
+// caller is responsible for mounting the handler on a listener.
+func (c *Controller) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, "/monitors")
+		if rest == r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+		if rest == "" || rest == "/" {
+			if r.Method != http.MethodGet {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			c.adminListMonitors(w, r)
+			return
+		}
+
+		rest = strings.TrimPrefix(rest, "/")
+		if name, ok := strings.CutSuffix(rest, "/enable"); ok {
+			c.adminSetEnabled(w, r, name, true)
+			return
+		}
+		if name, ok := strings.CutSuffix(rest, "/disable"); ok {
+			c.adminSetEnabled(w, r, name, false)
+			return
+		}
+		if name, ok := strings.CutSuffix(rest, "/check"); ok {
+			c.adminTriggerPulse(w, r, name)
+			return
+		}
+		if name, ok := strings.CutSuffix(rest, "/audit"); ok {
+			c.adminAuditEvents(w, r, name)
+			return
+		}
+		if strings.Contains(rest, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		c.adminGetMonitor(w, r, rest)
+	})
+}
+
+// runInTick runs fn inside the ECS tick via the command system and waits for
+// it to finish, or for the context to be cancelled.
+func (c *Controller) runInTick(ctx context.Context, fn func(w *ecs.World)) error {
+	done := make(chan struct{})
+	if err := c.commands.Submit(func(w *ecs.World) {
+		defer close(done)
+		fn(w)
+	}); err != nil {
+		return err
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// snapshotMonitor builds a snapshot from live components. Must run inside
+// the tick.
+func (c *Controller) snapshotMonitor(entity ecs.Entity, state *components.MonitorState) MonitorSnapshot {
+	meta := c.mapper.GetMonitorMeta(entity)
+	snap := MonitorSnapshot{
+		Name:                meta.Name,
+		Labels:              meta.Labels,
+		Enabled:             !c.mapper.Disabled.HasAll(entity),
+		PulsePending:        state.IsPulsePending(),
+		InterventionPending: state.IsInterventionPending(),
+		ConsecutiveFailures: state.ConsecutiveFailures,
+		LastCheckTime:       state.LastPulseCheckTime,
+		NextCheckTime:       state.NextCheckTime,
+	}
+	if c.mapper.History.HasAll(entity) {
+		snap.History = c.mapper.History.Get(entity).Snapshot()
+	}
+	return snap
+}
+
+func (c *Controller) adminListMonitors(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", "failed", "enabled", "disabled":
+	default:
+		http.Error(w, "unknown status filter: "+status, http.StatusBadRequest)
+		return
+	}
+	limit := adminDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit: "+v, http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	snapshots := make([]MonitorSnapshot, 0, min(limit, 1024))
+	err := c.runInTick(r.Context(), func(world *ecs.World) {
+		filter := ecs.NewFilter1[components.MonitorState](world)
+		query := filter.Query()
+		for query.Next() {
+			if len(snapshots) >= limit {
+				// Keep draining: the query must be iterated to completion.
+				continue
+			}
+			snap := c.snapshotMonitor(query.Entity(), query.Get())
+			switch status {
+			case "failed":
+				if snap.ConsecutiveFailures == 0 {
+					continue
+				}
+			case "enabled":
+				if !snap.Enabled {
+					continue
+				}
+			case "disabled":
+				if snap.Enabled {
+					continue
+				}
+			}
+			snapshots = append(snapshots, snap)
+		}
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, snapshots)
+}
+
+func (c *Controller) adminGetMonitor(w http.ResponseWriter, r *http.Request, name string) {
+	entity, ok := c.mapper.LookupMonitor(name)
+	if !ok {
+		http.Error(w, "unknown monitor", http.StatusNotFound)
+		return
+	}
+	var snap MonitorSnapshot
+	var alive bool
+	err := c.runInTick(r.Context(), func(world *ecs.World) {
+		if !world.Alive(entity) {
+			return
+		}
+		state := c.mapper.MonitorState.Get(entity)
+		if state == nil {
+			return
+		}
+		snap = c.snapshotMonitor(entity, state)
+		alive = true
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if !alive {
+		http.Error(w, "unknown monitor", http.StatusNotFound)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, snap)
+}
+
+func (c *Controller) adminSetEnabled(w http.ResponseWriter, r *http.Request, name string, enable bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	entity, ok := c.mapper.LookupMonitor(name)
+	if !ok {
+		http.Error(w, "unknown monitor", http.StatusNotFound)
+		return
+	}
+
+	var snap MonitorSnapshot
+	var alive, conflict bool
+	err := c.runInTick(r.Context(), func(world *ecs.World) {
+		if !world.Alive(entity) {
+			return
+		}
+		state := c.mapper.MonitorState.Get(entity)
+		if state == nil {
+			return
+		}
+		alive = true
+		// Refuse to flip monitors mid-intervention: the result system would
+		// apply the outcome to a monitor whose enablement changed under it.
+		if state.IsInterventionPending() {
+			conflict = true
+			return
+		}
+		if enable {
+			if c.mapper.Disabled.HasAll(entity) {
+				c.mapper.EnableMonitor(entity)
+			}
+		} else {
+			// Disable even when a window already suppresses the monitor:
+			// the explicit marker keeps the window exit edge from
+			// re-enabling it.
+			if !c.mapper.ExplicitlyDisabled.HasAll(entity) {
+				c.disableMonitorTracked(entity)
+			}
+		}
+		snap = c.snapshotMonitor(entity, state)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if !alive {
+		http.Error(w, "unknown monitor", http.StatusNotFound)
+		return
+	}
+	if conflict {
+		http.Error(w, "monitor has an intervention in flight", http.StatusConflict)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, snap)
+}
+
+// TriggerPulse forces an immediate pulse check for the named monitor by
+// moving its NextCheckTime to now from inside the ECS tick. Dispatch and the
+// result still flow through the normal batch pulse systems, so state
+// transitions stay consistent; the check fires on the monitor's next shard
+// turn rather than waiting out its interval. Returns ErrUnknownMonitor,
+// ErrMonitorDisabled, ErrPulseInFlight, or ErrHeartbeatMonitor when the
+// check cannot be forced.
+func (c *Controller) TriggerPulse(ctx context.Context, name string) error {
+	entity, ok := c.mapper.LookupMonitor(name)
+	if !ok {
+		return ErrUnknownMonitor
+	}
+	var trigErr error
+	err := c.runInTick(ctx, func(world *ecs.World) {
+		if !world.Alive(entity) {
+			trigErr = ErrUnknownMonitor
+			return
+		}
+		state := c.mapper.MonitorState.Get(entity)
+		if state == nil {
+			trigErr = ErrUnknownMonitor
+			return
+		}
+		if c.mapper.Disabled.HasAll(entity) {
+			trigErr = ErrMonitorDisabled
+			return
+		}
+		if state.IsPulsePending() {
+			trigErr = ErrPulseInFlight
+			return
+		}
+		if c.mapper.Heartbeat.HasAll(entity) {
+			trigErr = ErrHeartbeatMonitor
+			return
+		}
+		state.NextCheckTime = time.Now()
+	})
+	if err != nil {
+		return err
+	}
+	return trigErr
+}
+
+// GetHistory returns the named monitor's recent result samples oldest-first.
+// Returns ErrUnknownMonitor when no monitor with the name exists; an empty
+// slice when history is disabled or no results have arrived yet. The read
+// runs inside the ECS tick like all other admin reads.
+func (c *Controller) GetHistory(ctx context.Context, name string) ([]components.ResultSample, error) {
+	entity, ok := c.mapper.LookupMonitor(name)
+	if !ok {
+		return nil, ErrUnknownMonitor
+	}
+	var samples []components.ResultSample
+	var alive bool
+	err := c.runInTick(ctx, func(world *ecs.World) {
+		if !world.Alive(entity) {
+			return
+		}
+		alive = true
+		if c.mapper.History.HasAll(entity) {
+			samples = c.mapper.History.Get(entity).Snapshot()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !alive {
+		return nil, ErrUnknownMonitor
+	}
+	return samples, nil
+}
+
+// ErrAuditingDisabled is returned by AuditEvents when no audit database is
+// configured (Config.AuditDB is empty).
+var ErrAuditingDisabled = errors.New("auditing is disabled")
+
+// AuditEvents returns the last n audit events recorded for the named monitor,
+// newest first (see internal/audit). Unlike the other admin reads this does
+// not enter the ECS tick: it queries committed rows in the audit database, so
+// events still buffered in the sink are not yet visible. n <= 0 uses the
+// sink's default limit.
+func (c *Controller) AuditEvents(name string, n int) ([]audit.Event, error) {
+	if c.auditLog == nil {
+		return nil, ErrAuditingDisabled
+	}
+	return c.auditLog.Recent(name, n)
+}
+
+func (c *Controller) adminAuditEvents(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit: "+v, http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	events, err := c.AuditEvents(name, limit)
+	switch {
+	case err == nil:
+		if events == nil {
+			events = []audit.Event{}
+		}
+		writeAdminJSON(w, http.StatusOK, events)
+	case errors.Is(err, ErrAuditingDisabled):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	}
+}
+
+func (c *Controller) adminTriggerPulse(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch err := c.TriggerPulse(r.Context(), name); {
+	case err == nil:
+		writeAdminJSON(w, http.StatusAccepted, map[string]string{"status": "triggered"})
+	case errors.Is(err, ErrUnknownMonitor):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrMonitorDisabled), errors.Is(err, ErrPulseInFlight), errors.Is(err, ErrHeartbeatMonitor):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	}
+}
+
+// writeAdminJSON writes v as a JSON response with the given status code.
+func writeAdminJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,1159 @@
+// Package components defines the consolidated Entity-Component-System (ECS) components
+// for the CPRA monitoring application. This design follows the principles of data-oriented
+// design to maximize performance and minimize memory usage, as required for handling
+// over one million concurrent monitors.
+//
+// By consolidating state, configuration, and jobs into a few coarse-grained components,
+// we dramatically reduce the number of archetypes in the ECS world. This leads to:
+//   - Improved cache locality and iteration speed.
+//   - Reduced memory fragmentation.
+//   - Simplified system logic by avoiding complex component additions/removals for state transitions.
+//
+// State management is handled via a bitfield in the MonitorState component, allowing for
+// efficient, atomic updates to an entity's status without changing its archetype.
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cpra/internal/interning"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// DefaultShardSlots defines the baseline number of time-partition slots used to spread work across ticks.
+// Each monitor is assigned to a shard [0, shardSlots), and only one shard is processed per tick.
+const DefaultShardSlots = 100
+
+// Shard is a lightweight component that stores the shard assignment for a monitor.
+type Shard struct {
+	ID uint8
+}
+
+// Disabled is a zero-size tag component marking an entity as disabled.
+// Using a tag allows filters to exclude disabled entities efficiently at the archetype level.
+type Disabled struct{}
+
+// ExplicitlyDisabled is a zero-size tag recording that the Disabled tag was
+// applied outright — by the admin API, a manifest with enabled: false, or the
+// panic auto-disable — rather than by a schedule or maintenance window edge.
+// The window systems must not remove Disabled while this marker is present;
+// only EnableMonitor releases an explicit disable.
+type ExplicitlyDisabled struct{}
+
+// ProbeFollower is a zero-size tag marking a monitor whose outbound probe is
+// executed by another monitor sharing the same probe key (see the shared
+// probe registry in the systems package). Followers are excluded from pulse
+// scheduling and dispatch; the pulse result system fans the leader's results
+// out to them instead.
+type ProbeFollower struct{}
+
+// Heartbeat marks a push-based monitor and tracks its most recent beat.
+// Heartbeat entities are excluded from the pulse dispatch path; the
+// HeartbeatSystem evaluates them against interval+grace instead of probing.
+type Heartbeat struct {
+	// LastSeen is the time of the most recent accepted heartbeat.
+	// Initialized to load time so a monitor that never reports fails
+	// once interval+grace elapses after startup.
+	LastSeen time.Time
+	// Grace is extra slack on top of the expected interval before a
+	// missed beat counts as a failure.
+	Grace time.Duration
+}
+
+// highFrequencyRingSize is the number of scheduling-accuracy samples kept per
+// high-frequency monitor.
+const highFrequencyRingSize = 64
+
+// DependsOn lists the monitors this monitor sits behind, resolved from the
+// manifest's depends_on names at load time. While any parent is failed, code
+// alerts for this monitor are suppressed so a dead upstream does not page
+// for everything behind it.
+type DependsOn struct {
+	Parents []ecs.Entity
+}
+
+// HighFrequency marks a monitor explicitly opted into sub-second intervals.
+// These entities are dispatched by the dedicated fast-path system every tick,
+// bypassing shard scheduling and the bulk batch systems, and carry a small
+// ring of scheduling-accuracy samples (actual dispatch time minus intended
+// fire time).
+type HighFrequency struct {
+	// OffsetsMs holds recent scheduling offsets in milliseconds. Positive
+	// values mean the check fired late.
+	OffsetsMs [highFrequencyRingSize]int32
+	Index     uint8
+	Count     uint8
+}
+
+// Record adds one scheduling offset to the ring.
+func (h *HighFrequency) Record(offset time.Duration) {
+	ms := offset.Milliseconds()
+	if ms > int64(^uint32(0)>>1) {
+		ms = int64(^uint32(0) >> 1)
+	}
+	h.OffsetsMs[h.Index] = int32(ms)
+	h.Index = (h.Index + 1) % highFrequencyRingSize
+	if h.Count < highFrequencyRingSize {
+		h.Count++
+	}
+}
+
+// Samples appends the recorded offsets (unordered) to dst and returns it.
+func (h *HighFrequency) Samples(dst []int32) []int32 {
+	for i := uint8(0); i < h.Count; i++ {
+		dst = append(dst, h.OffsetsMs[i])
+	}
+	return dst
+}
+
+// Maintenance recurrence kinds for compiled windows.
+const (
+	MaintenanceOnce uint8 = iota
+	MaintenanceDaily
+	MaintenanceWeekly
+)
+
+// MaintenanceWindow is the compiled form of a schema maintenance window:
+// timestamps, offsets and the timezone are resolved once at load time so
+// the per-tick containment check does no string parsing.
+type MaintenanceWindow struct {
+	// Location the window is evaluated in; never nil after compilation.
+	Location *time.Location
+	// Start and End bound a one-off window.
+	Start time.Time
+	End   time.Time
+	// StartOffset and EndOffset are offsets into the day (daily) or the
+	// week with a Sunday 00:00 origin (weekly) for recurring windows.
+	StartOffset time.Duration
+	EndOffset   time.Duration
+	Recurrence  uint8
+}
+
+// CompileMaintenanceWindow resolves a schema maintenance window into its
+// compiled form, validating the start/end formats along the way.
+func CompileMaintenanceWindow(w schema.MaintenanceWindow) (MaintenanceWindow, error) {
+	loc := time.UTC
+	if w.Timezone != "" {
+		l, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("invalid maintenance timezone %q: %w", w.Timezone, err)
+		}
+		loc = l
+	}
+	out := MaintenanceWindow{Location: loc}
+
+	switch w.Recurrence {
+	case "":
+		start, err := time.ParseInLocation(time.RFC3339, w.Start, loc)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("invalid maintenance start %q: %w", w.Start, err)
+		}
+		end, err := time.ParseInLocation(time.RFC3339, w.End, loc)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("invalid maintenance end %q: %w", w.End, err)
+		}
+		if !end.After(start) {
+			return MaintenanceWindow{}, fmt.Errorf("maintenance end %q is not after start %q", w.End, w.Start)
+		}
+		out.Start, out.End = start, end
+	case "daily":
+		out.Recurrence = MaintenanceDaily
+		start, err := parseDayOffset(w.Start)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("invalid maintenance start %q: %w", w.Start, err)
+		}
+		end, err := parseDayOffset(w.End)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("invalid maintenance end %q: %w", w.End, err)
+		}
+		if start == end {
+			return MaintenanceWindow{}, fmt.Errorf("maintenance start and end %q must differ", w.Start)
+		}
+		out.StartOffset, out.EndOffset = start, end
+	case "weekly":
+		out.Recurrence = MaintenanceWeekly
+		start, err := parseWeekOffset(w.Start)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("invalid maintenance start %q: %w", w.Start, err)
+		}
+		end, err := parseWeekOffset(w.End)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("invalid maintenance end %q: %w", w.End, err)
+		}
+		if start == end {
+			return MaintenanceWindow{}, fmt.Errorf("maintenance start and end %q must differ", w.Start)
+		}
+		out.StartOffset, out.EndOffset = start, end
+	default:
+		return MaintenanceWindow{}, fmt.Errorf("invalid maintenance recurrence %q (want daily or weekly)", w.Recurrence)
+	}
+	return out, nil
+}
+
+// parseDayOffset converts a "15:04" time of day into an offset from midnight.
+func parseDayOffset(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseWeekOffset converts a "Sat 22:00" weekday plus time of day into an
+// offset from Sunday 00:00.
+func parseWeekOffset(s string) (time.Duration, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("want weekday and time of day, e.g. %q", "Sat 22:00")
+	}
+	// time.Parse does not carry a weekday without a full date, so match
+	// the day name ourselves (both "Sat" and "Saturday" forms).
+	day := time.Weekday(-1)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(fields[0], d.String()) || strings.EqualFold(fields[0], d.String()[:3]) {
+			day = d
+			break
+		}
+	}
+	if day < 0 {
+		return 0, fmt.Errorf("unknown weekday %q", fields[0])
+	}
+	off, err := parseDayOffset(fields[1])
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(day)*24*time.Hour + off, nil
+}
+
+// Contains reports whether now falls inside the window, evaluated in the
+// window's timezone.
+func (w *MaintenanceWindow) Contains(now time.Time) bool {
+	t := now.In(w.Location)
+	switch w.Recurrence {
+	case MaintenanceDaily:
+		return offsetInWindow(dayOffset(t), w.StartOffset, w.EndOffset)
+	case MaintenanceWeekly:
+		return offsetInWindow(weekOffset(t), w.StartOffset, w.EndOffset)
+	default:
+		return !t.Before(w.Start) && t.Before(w.End)
+	}
+}
+
+// offsetInWindow reports whether off lies in [start, end). An end at or
+// before the start wraps past the period boundary (e.g. 23:00-01:00 daily).
+func offsetInWindow(off, start, end time.Duration) bool {
+	if start < end {
+		return off >= start && off < end
+	}
+	return off >= start || off < end
+}
+
+func dayOffset(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+}
+
+func weekOffset(t time.Time) time.Duration {
+	return time.Duration(t.Weekday())*24*time.Hour + dayOffset(t)
+}
+
+// Maintenance holds a monitor's compiled maintenance windows plus whether
+// the monitor is currently inside one. Active is owned by the
+// MaintenanceSystem, which compares it against the clock each tick to
+// detect window entry and exit edges.
+type Maintenance struct {
+	Windows []MaintenanceWindow
+	Active  bool
+}
+
+// InWindow reports whether now falls inside any window; overlapping
+// windows behave as their union.
+func (m *Maintenance) InWindow(now time.Time) bool {
+	for i := range m.Windows {
+		if m.Windows[i].Contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Copy returns a deep copy of the Maintenance component.
+func (m *Maintenance) Copy() *Maintenance {
+	if m == nil {
+		return nil
+	}
+	c := *m
+	c.Windows = append([]MaintenanceWindow(nil), m.Windows...)
+	return &c
+}
+
+// Schedule is the compiled form of a pulse schedule block: checks only run
+// inside the active hours on the active days, evaluated in Location. The
+// parsing happens once at load time so the per-tick containment check does
+// no string work.
+type Schedule struct {
+	// Location the window is evaluated in; never nil after compilation.
+	Location *time.Location
+	// StartOffset and EndOffset bound the active hours within the local
+	// day; an end at or before the start wraps past midnight.
+	StartOffset time.Duration
+	EndOffset   time.Duration
+	// Days is a weekday bitmask with bit 0 = Sunday.
+	Days uint8
+	// Active is whether the monitor was inside its window last tick; owned
+	// by the ActiveHoursSystem for edge detection. Compilation starts it
+	// true so monitors loaded outside their hours fire an exit edge on the
+	// first tick.
+	Active bool
+}
+
+// CompileSchedule resolves a schema schedule block into its compiled form,
+// validating the hour, day, and timezone strings along the way.
+func CompileSchedule(s schema.Schedule) (Schedule, error) {
+	loc := time.UTC
+	if s.Timezone != "" {
+		l, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid schedule timezone %q: %w", s.Timezone, err)
+		}
+		loc = l
+	}
+	start, end, err := s.ActiveWindow()
+	if err != nil {
+		return Schedule{}, err
+	}
+	days, err := s.DayMask()
+	if err != nil {
+		return Schedule{}, err
+	}
+	return Schedule{
+		Location:    loc,
+		StartOffset: start,
+		EndOffset:   end,
+		Days:        days,
+		Active:      true,
+	}, nil
+}
+
+// InWindow reports whether now falls inside the active hours on an active
+// day, evaluated in the schedule's timezone. Days are matched against the
+// local calendar day, so the after-midnight tail of a wrapped window
+// belongs to the following day.
+func (s *Schedule) InWindow(now time.Time) bool {
+	t := now.In(s.Location)
+	if s.Days&(1<<uint(t.Weekday())) == 0 {
+		return false
+	}
+	return offsetInWindow(dayOffset(t), s.StartOffset, s.EndOffset)
+}
+
+// SLO bucket geometry. Two fixed rings bound the per-monitor memory: minute
+// buckets back the short burn-rate windows (5m/30m/1h) and hour buckets back
+// the long windows and the compliance window itself.
+const (
+	SLOMinuteBuckets = 60
+	SLOHourBuckets   = 720 // 30 days; longer manifest windows are capped here
+)
+
+// SLOBucket counts the check outcomes that landed in one time bucket.
+type SLOBucket struct {
+	Total uint32
+	Bad   uint32 // failed checks
+	Slow  uint32 // successful checks over the latency threshold
+}
+
+// SLO holds a monitor's compiled service-level objective plus the rolling
+// outcome counters it is evaluated against. The counters live in two fixed
+// ring buffers (per-minute and per-hour), so memory per monitor is bounded
+// regardless of check rate or window length. The BatchPulseResultSystem
+// feeds the rings; the SLOSystem reads burn rates off them.
+type SLO struct {
+	// Objective, compiled from the manifest slo block.
+	Target           float64       // availability objective as a fraction, e.g. 0.999
+	Window           time.Duration // rolling compliance window
+	LatencyThreshold time.Duration // zero when no latency objective is configured
+	LatencyTarget    float64       // latency objective as a fraction; zero when absent
+
+	Minutes [SLOMinuteBuckets]SLOBucket
+	Hours   [SLOHourBuckets]SLOBucket
+	// CurMinute/CurHour are the unix minute/hour the head buckets cover;
+	// zero until the first sample arrives.
+	CurMinute  int64
+	CurHour    int64
+	MinuteHead int32
+	HourHead   int32
+
+	// AlertLevel is the burn severity last dispatched by the SLOSystem
+	// (0 none, 1 warning, 2 critical). Alerts fire only when it rises.
+	AlertLevel uint8
+}
+
+// CompileSLO parses a manifest slo block into its component, capping the
+// window at what the hour ring can cover.
+func CompileSLO(s schema.SLO) (*SLO, error) {
+	window, err := time.ParseDuration(s.Window)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slo window %q: %w", s.Window, err)
+	}
+	if window > SLOHourBuckets*time.Hour {
+		window = SLOHourBuckets * time.Hour
+	}
+	out := &SLO{
+		Target: s.Availability / 100,
+		Window: window,
+	}
+	if s.Latency != nil {
+		threshold, err := time.ParseDuration(s.Latency.Threshold)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slo latency threshold %q: %w", s.Latency.Threshold, err)
+		}
+		out.LatencyThreshold = threshold
+		out.LatencyTarget = s.Latency.Target / 100
+	}
+	return out, nil
+}
+
+// Record counts one completed check in the current minute and hour buckets.
+// A failed check is bad; a successful one may still be slow.
+func (s *SLO) Record(now time.Time, success, slow bool) {
+	s.advance(now)
+	mb, hb := &s.Minutes[s.MinuteHead], &s.Hours[s.HourHead]
+	mb.Total++
+	hb.Total++
+	if !success {
+		mb.Bad++
+		hb.Bad++
+	} else if slow {
+		mb.Slow++
+		hb.Slow++
+	}
+}
+
+// Burn returns the error-budget burn rate over the trailing window: the
+// observed bad fraction divided by the fraction the objective allows, so a
+// burn of 1.0 spends the budget exactly over the compliance window. With a
+// latency objective configured the worse of the two burns is returned,
+// along with the number of checks the window covers. Rings are advanced to
+// now first, so an idle monitor's old errors age out.
+func (s *SLO) Burn(now time.Time, window time.Duration) (float64, uint64) {
+	s.advance(now)
+	var bad, slow, total uint64
+	if window <= SLOMinuteBuckets*time.Minute {
+		n := int((window + time.Minute - 1) / time.Minute)
+		bad, slow, total = sumSLORing(s.Minutes[:], s.MinuteHead, n)
+	} else {
+		n := int((window + time.Hour - 1) / time.Hour)
+		bad, slow, total = sumSLORing(s.Hours[:], s.HourHead, n)
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	burn := budgetBurn(float64(bad)/float64(total), 1-s.Target)
+	if s.LatencyThreshold > 0 {
+		if lb := budgetBurn(float64(slow)/float64(total), 1-s.LatencyTarget); lb > burn {
+			burn = lb
+		}
+	}
+	return burn, total
+}
+
+// advance rotates both rings so their head buckets cover now, zeroing any
+// buckets skipped while the monitor was idle.
+func (s *SLO) advance(now time.Time) {
+	advanceSLORing(s.Minutes[:], &s.MinuteHead, &s.CurMinute, now.Unix()/60)
+	advanceSLORing(s.Hours[:], &s.HourHead, &s.CurHour, now.Unix()/3600)
+}
+
+func advanceSLORing(buckets []SLOBucket, head *int32, cur *int64, now int64) {
+	if *cur == 0 {
+		*cur = now
+		return
+	}
+	steps := now - *cur
+	if steps <= 0 {
+		return
+	}
+	if steps > int64(len(buckets)) {
+		steps = int64(len(buckets))
+	}
+	for ; steps > 0; steps-- {
+		*head = (*head + 1) % int32(len(buckets))
+		buckets[*head] = SLOBucket{}
+	}
+	*cur = now
+}
+
+// sumSLORing sums the n most recent buckets, newest first from the head.
+func sumSLORing(buckets []SLOBucket, head int32, n int) (bad, slow, total uint64) {
+	if n > len(buckets) {
+		n = len(buckets)
+	}
+	for i := 0; i < n; i++ {
+		b := &buckets[(int(head)-i+len(buckets))%len(buckets)]
+		bad += uint64(b.Bad)
+		slow += uint64(b.Slow)
+		total += uint64(b.Total)
+	}
+	return bad, slow, total
+}
+
+// budgetBurn divides the observed bad fraction by the allowed budget; a
+// zero budget (target of 100%) never happens past validation but is kept
+// safe regardless.
+func budgetBurn(fraction, budget float64) float64 {
+	if budget <= 0 {
+		return 0
+	}
+	return fraction / budget
+}
+
+// maxSampleErrorLen caps the error string stored per result sample; longer
+// errors are truncated so one chatty check cannot bloat a million rings.
+const maxSampleErrorLen = 128
+
+// DefaultHistorySize is the number of result samples kept per monitor when
+// no explicit history size is configured.
+const DefaultHistorySize = 20
+
+// ResultSample is one completed pulse check in a monitor's result history.
+// Kept deliberately compact (no maps, truncated error) so rings stay cheap
+// at 1M monitors.
+type ResultSample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMs float32   `json:"latency_ms"`
+	Success   bool      `json:"success"`
+}
+
+// ResultHistory keeps the last N result samples for a monitor in a
+// fixed-size ring buffer. Oldest samples are evicted FIFO once the ring is
+// full. The zero value has no storage and drops all appends.
+type ResultHistory struct {
+	Samples []ResultSample
+	Head    int // next write index
+	Count   int // filled entries, up to cap(Samples)
+}
+
+// NewResultHistory returns a history ring holding up to n samples.
+func NewResultHistory(n int) ResultHistory {
+	if n <= 0 {
+		return ResultHistory{}
+	}
+	return ResultHistory{Samples: make([]ResultSample, n)}
+}
+
+// Append records a sample, evicting the oldest once the ring is full.
+// The sample's error string is truncated to maxSampleErrorLen bytes.
+func (h *ResultHistory) Append(s ResultSample) {
+	if cap(h.Samples) == 0 {
+		return
+	}
+	if len(s.Error) > maxSampleErrorLen {
+		s.Error = s.Error[:maxSampleErrorLen]
+	}
+	h.Samples[h.Head] = s
+	h.Head = (h.Head + 1) % cap(h.Samples)
+	if h.Count < cap(h.Samples) {
+		h.Count++
+	}
+}
+
+// Snapshot returns the recorded samples oldest-first as a fresh slice.
+func (h *ResultHistory) Snapshot() []ResultSample {
+	if h.Count == 0 {
+		return nil
+	}
+	out := make([]ResultSample, 0, h.Count)
+	start := h.Head - h.Count
+	if start < 0 {
+		start += cap(h.Samples)
+	}
+	for i := 0; i < h.Count; i++ {
+		out = append(out, h.Samples[(start+i)%cap(h.Samples)])
+	}
+	return out
+}
+
+// Copy returns a deep copy of the ResultHistory component.
+func (h *ResultHistory) Copy() *ResultHistory {
+	if h == nil {
+		return nil
+	}
+	c := *h
+	c.Samples = append([]ResultSample(nil), h.Samples...)
+	return &c
+}
+
+// MonitorSchedule is the hot half of a monitor's state: the flags, deadlines
+// and streak counters the per-tick systems scan for every entity, every tick.
+// It is deliberately fixed-size with no strings or interfaces, so the schedule
+// scan strides over plain data instead of dragging string headers and
+// rarely-read timestamps through cache. Identification and reporting-only
+// fields live in MonitorMeta, which the systems fetch only when they actually
+// dispatch, log or record a result for a monitor.
+type MonitorSchedule struct {
+	LastPulseCheckTime time.Time
+	NextCheckTime      time.Time
+	// PendingSince is when the newest pending flag was set. The pending
+	// recovery watchdog compares it against the job timeout to detect
+	// in-flight jobs whose result never came back.
+	PendingSince         time.Time
+	ConsecutiveFailures  int
+	PulseFailures        int
+	InterventionFailures int
+	// StreakFailedAttempts totals the individual failed attempts (retries
+	// included) behind PulseFailures' current accumulation. It resets with
+	// PulseFailures so the attempt-volume threshold paces escalations the
+	// same way the check-level threshold does.
+	StreakFailedAttempts int
+	// EscalationStep is how many escalation-chain levels have run since the
+	// monitor was last healthy; the next dispatch runs step EscalationStep-1
+	// or escalates one level when the threshold is met.
+	EscalationStep  int
+	RecoveryStreak  int
+	VerifyRemaining int
+	// DegradedLatencyStreak counts consecutive successful checks whose
+	// EWMA exceeded the configured degraded_latency threshold.
+	DegradedLatencyStreak int
+	Flags                 uint32
+	PendingColor          ColorCode
+}
+
+// MonitorState is the historical name of the hot component, kept as an alias
+// while call sites migrate to the schedule/meta split.
+type MonitorState = MonitorSchedule
+
+// MonitorMeta is the cold half of a monitor's state: identification and
+// diagnostic fields that are read when dispatching jobs, recording results or
+// serving admin queries, but never by the per-tick scans. Splitting them off
+// keeps the MonitorSchedule archetype columns dense; the two components are
+// always created and removed together.
+type MonitorMeta struct {
+	Name string
+	// Labels is the monitor's metadata from the manifest (team, tier,
+	// runbook URL), shared via interning since many monitors carry
+	// identical sets. Immutable per load; replaced wholesale on reload.
+	Labels        interning.Labels
+	LastError     error
+	LastEventTime time.Time
+	// LastSuccessTime is when the last healthy pulse result was recorded.
+	LastSuccessTime time.Time
+	// LatencyEWMA is an exponentially weighted moving average of pulse
+	// check durations (alpha 1/8); LatencyMax is the slowest check seen.
+	LatencyEWMA time.Duration
+	LatencyMax  time.Duration
+	// LastInterventionTime is when the last intervention was dispatched;
+	// the cooldown window is measured from it.
+	LastInterventionTime time.Time
+	// InterventionWindowStart and InterventionWindowCount implement the
+	// hourly dispatch cap: the count of interventions dispatched since the
+	// window opened, reset once the window is an hour old.
+	InterventionWindowStart time.Time
+	InterventionWindowCount int
+	// InterventionsSuppressed counts dispatches skipped by the cooldown or
+	// the hourly cap.
+	InterventionsSuppressed int
+	// PanicStreak counts consecutive pulse results synthesized from a
+	// panicked job; any non-panic result resets it. At the result system's
+	// threshold the monitor is auto-disabled.
+	PanicStreak int
+}
+
+// StatePulseNeeded is a state flag constant; additional related flags follow in this block.
+const (
+	// Disabled moved to a tag component (components.Disabled)
+	StatePulseNeeded         uint32 = 1 << 1
+	StatePulsePending        uint32 = 1 << 2
+	StatePulseFirstCheck     uint32 = 1 << 3
+	StateInterventionNeeded  uint32 = 1 << 5
+	StateInterventionPending uint32 = 1 << 6
+	StateCodeNeeded          uint32 = 1 << 7
+	StateCodePending         uint32 = 1 << 8
+	StateIncidentOpen        uint32 = 1 << 9
+	StateVerifying           uint32 = 1 << 10
+	// StateDependencySuppressed marks a monitor whose code alerts are being
+	// held back because a monitor it depends on is currently failed. The
+	// BatchCodeSystem sets it instead of dispatching and clears it once all
+	// dependencies recover, re-raising the alert if the monitor is still
+	// failing.
+	StateDependencySuppressed uint32 = 1 << 11
+	// Room for more states without adding components
+)
+
+// ColorCode represents a color for alert codes.
+type ColorCode uint8
+
+// Color constants for fixed array indexing
+const (
+	ColorRed    ColorCode = 0
+	ColorOrange ColorCode = 1
+	ColorYellow ColorCode = 2
+	ColorGreen  ColorCode = 3
+	ColorCyan   ColorCode = 4
+	ColorBlue   ColorCode = 5
+	ColorPurple ColorCode = 6
+	ColorGray   ColorCode = 7
+	// MaxColors must match the number of supported colors
+	MaxColors ColorCode = 8
+	// ColorNone indicates no pending color (sentinel value)
+	ColorNone ColorCode = 255
+)
+
+// colorPriority defines alert priority (higher = more urgent)
+var colorPriority = [MaxColors]uint8{
+	5, // red - highest
+	4, // orange
+	4, // yellow
+	2, // green
+	2, // cyan
+	1, // blue
+	1, // purple
+	0, // gray - lowest
+}
+
+// Priority returns the alert priority of this color (0-5, higher = more urgent)
+func (c ColorCode) Priority() uint8 {
+	if c >= MaxColors {
+		return 0
+	}
+	return colorPriority[c]
+}
+
+// HigherPriorityThan returns true if this color has higher priority than other
+func (c ColorCode) HigherPriorityThan(other ColorCode) bool {
+	return c.Priority() > other.Priority()
+}
+
+// String returns the color name
+func (c ColorCode) String() string {
+	if c >= MaxColors {
+		if c == ColorNone {
+			return "none"
+		}
+		return "unknown"
+	}
+	return IndexToColor[c]
+}
+
+// ColorToIndex converts a color name to its ColorCode.
+// Returns ColorNone if the color is not recognized.
+func ColorToIndex(color string) ColorCode {
+	switch color {
+	case "red":
+		return ColorRed
+	case "orange":
+		return ColorOrange
+	case "yellow":
+		return ColorYellow
+	case "green":
+		return ColorGreen
+	case "cyan":
+		return ColorCyan
+	case "blue":
+		return ColorBlue
+	case "purple":
+		return ColorPurple
+	case "gray":
+		return ColorGray
+	default:
+		return ColorNone
+	}
+}
+
+// IndexToColor converts an index to its color name.
+var IndexToColor = [MaxColors]string{
+	"red", "orange", "yellow", "green", "cyan", "blue", "purple", "gray",
+}
+
+// IsPulseNeeded reports whether a pulse is needed for the monitor; related helpers follow.
+func (m *MonitorSchedule) IsPulseNeeded() bool         { return m.Flags&StatePulseNeeded != 0 }
+func (m *MonitorSchedule) IsPulsePending() bool        { return m.Flags&StatePulsePending != 0 }
+func (m *MonitorSchedule) IsPulseFirstCheck() bool     { return m.Flags&StatePulseFirstCheck != 0 }
+func (m *MonitorSchedule) IsInterventionNeeded() bool  { return m.Flags&StateInterventionNeeded != 0 }
+func (m *MonitorSchedule) IsInterventionPending() bool { return m.Flags&StateInterventionPending != 0 }
+func (m *MonitorSchedule) IsCodeNeeded() bool          { return m.Flags&StateCodeNeeded != 0 }
+func (m *MonitorSchedule) IsCodePending() bool         { return m.Flags&StateCodePending != 0 }
+
+func (m *MonitorSchedule) SetPulseNeeded(needed bool) {
+	if needed {
+		m.Flags |= StatePulseNeeded
+	} else {
+		m.Flags &^= StatePulseNeeded
+	}
+}
+
+func (m *MonitorSchedule) SetPulsePending(pending bool) {
+	if pending {
+		m.Flags |= StatePulsePending
+	} else {
+		m.Flags &^= StatePulsePending
+	}
+}
+
+func (m *MonitorSchedule) SetPulseFirstCheck(firstCheck bool) {
+	if firstCheck {
+		m.Flags |= StatePulseFirstCheck
+	} else {
+		m.Flags &^= StatePulseFirstCheck
+	}
+}
+
+func (m *MonitorSchedule) SetInterventionNeeded(needed bool) {
+	if needed {
+		m.Flags |= StateInterventionNeeded
+	} else {
+		m.Flags &^= StateInterventionNeeded
+	}
+}
+
+func (m *MonitorSchedule) SetInterventionPending(pending bool) {
+	if pending {
+		m.Flags |= StateInterventionPending
+	} else {
+		m.Flags &^= StateInterventionPending
+	}
+}
+
+func (m *MonitorSchedule) SetCodeNeeded(needed bool) {
+	if needed {
+		m.Flags |= StateCodeNeeded
+	} else {
+		m.Flags &^= StateCodeNeeded
+	}
+}
+
+func (m *MonitorSchedule) SetCodePending(pending bool) {
+	if pending {
+		m.Flags |= StateCodePending
+	} else {
+		m.Flags &^= StateCodePending
+	}
+}
+
+// PulseConfig consolidates pulse configuration
+type PulseConfig struct {
+	Config             schema.PulseConfig
+	Type               string
+	Timeout            time.Duration
+	Interval           time.Duration
+	Retries            int
+	UnhealthyThreshold int
+	// UnhealthyAttemptThreshold, when positive, trips the unhealthy
+	// escalation once the bad streak's failed attempt volume (retries
+	// included) reaches it, even before UnhealthyThreshold failed checks.
+	UnhealthyAttemptThreshold int
+	HealthyThreshold          int
+	// DegradedLatency, when positive, fires the yellow code path once the
+	// monitor's rolling latency EWMA stays above it for consecutive
+	// successful checks.
+	DegradedLatency time.Duration
+	// Invert flips the result interpretation for expected-failure checks:
+	// a probe that succeeds counts as a failure and vice versa. The flip
+	// happens in the result system, so job payloads still record the wire
+	// outcome.
+	Invert bool
+	// Generation is the manifest generation this config was written from.
+	// All config components of an entity should carry the same generation;
+	// a mismatch means an update was interrupted between component writes.
+	Generation uint32
+	// NoDedupe mirrors an explicit `dedupe: false` in the manifest: the
+	// monitor never joins a shared-probe group even when the controller
+	// enables deduplication.
+	NoDedupe bool
+	// Trace opts this monitor's checks into OpenTelemetry spans; inert
+	// unless the controller has a trace exporter configured.
+	Trace bool
+	// CountRateLimitAsFailure counts rate-limited responses (HTTP 429)
+	// toward the failure thresholds; by default they are exempt.
+	CountRateLimitAsFailure bool
+}
+
+func (c *PulseConfig) Copy() *PulseConfig {
+	if c == nil {
+		return nil
+	}
+	cpy := &PulseConfig{
+		Type:                      strings.Clone(c.Type),
+		Timeout:                   c.Timeout,
+		Interval:                  c.Interval,
+		Retries:                   c.Retries,
+		UnhealthyThreshold:        c.UnhealthyThreshold,
+		UnhealthyAttemptThreshold: c.UnhealthyAttemptThreshold,
+		HealthyThreshold:          c.HealthyThreshold,
+		DegradedLatency:           c.DegradedLatency,
+		Invert:                    c.Invert,
+		Generation:                c.Generation,
+		NoDedupe:                  c.NoDedupe,
+		Trace:                     c.Trace,
+		CountRateLimitAsFailure:   c.CountRateLimitAsFailure,
+	}
+
+	if c.Config != nil {
+		cpy.Config = c.Config.Copy()
+	}
+	return cpy
+}
+
+// InterventionStep is one level of an intervention escalation chain.
+type InterventionStep struct {
+	Target schema.InterventionTarget
+	Action string
+	// AfterFailures is how many consecutive failures must accumulate after
+	// the previous step ran before this step is tried.
+	AfterFailures int
+}
+
+// InterventionConfig consolidates intervention configuration
+type InterventionConfig struct {
+	Target      schema.InterventionTarget
+	Action      string
+	MaxFailures int
+	// Cooldown suppresses dispatching another intervention within the
+	// window after the last one fired. Zero disables it.
+	Cooldown time.Duration
+	// MaxPerHour caps interventions dispatched per hour. Zero means
+	// unlimited.
+	MaxPerHour int
+	// Steps holds the escalation chain; Action/Target mirror step 0.
+	Steps []InterventionStep
+	// Generation is the manifest generation this config was written from.
+	Generation uint32
+}
+
+func (c *InterventionConfig) Copy() *InterventionConfig {
+	if c == nil {
+		return nil
+	}
+	cpy := &InterventionConfig{
+		Action:      strings.Clone(c.Action),
+		MaxFailures: c.MaxFailures,
+		Cooldown:    c.Cooldown,
+		MaxPerHour:  c.MaxPerHour,
+		Generation:  c.Generation,
+	}
+
+	if len(c.Steps) > 0 {
+		cpy.Steps = make([]InterventionStep, len(c.Steps))
+		for n, step := range c.Steps {
+			cpy.Steps[n] = InterventionStep{
+				Action:        strings.Clone(step.Action),
+				AfterFailures: step.AfterFailures,
+			}
+			if step.Target != nil {
+				cpy.Steps[n].Target = step.Target.Copy()
+			}
+		}
+	}
+
+	if c.Target != nil {
+		cpy.Target = c.Target.Copy()
+	}
+	return cpy
+}
+
+// CodeConfig consolidates all code configurations using a fixed array.
+// This single component replaces separate map-based configurations, enabling value semantics.
+type CodeConfig struct {
+	// Fixed array configuration - Value Type, Zero Allocation
+	Configs [MaxColors]ConfigID
+	// Extra holds additional per-color targets resolved from notify
+	// groups. The first target for each color lives in Configs, so the
+	// common single-target case stays a flat array; only fan-out
+	// allocates.
+	Extra []ColorConfigID
+	// Generation is the manifest generation this config was written from.
+	Generation uint32
+}
+
+// ColorConfigID pairs a fan-out notification target with its color.
+type ColorConfigID struct {
+	Color ColorCode
+	ID    ConfigID
+}
+
+type ColorCodeConfig struct {
+	Config      schema.CodeNotification
+	Notify      string
+	MaxFailures int
+	// Cooldown suppresses repeat alerts of this color fired within the
+	// window. Zero disables suppression.
+	Cooldown time.Duration
+	Dispatch bool
+}
+
+func (c *ColorCodeConfig) Copy() *ColorCodeConfig {
+	if c == nil {
+		return nil
+	}
+	cpy := &ColorCodeConfig{
+		Dispatch:    c.Dispatch,
+		MaxFailures: c.MaxFailures,
+		Cooldown:    c.Cooldown,
+		Notify:      strings.Clone(c.Notify),
+	}
+	if c.Config != nil {
+		cpy.Config = c.Config.Copy()
+	}
+	return cpy
+}
+
+func (c *CodeConfig) Copy() *CodeConfig {
+	if c == nil {
+		return nil
+	}
+	// Value copy of the ID array
+	cpy := &CodeConfig{Configs: c.Configs, Generation: c.Generation}
+	if len(c.Extra) > 0 {
+		cpy.Extra = make([]ColorConfigID, len(c.Extra))
+		copy(cpy.Extra, c.Extra)
+	}
+	return cpy
+}
+
+// Get returns a pointer to the config for the given color, or nil if invalid.
+func (c *CodeConfig) Get(color ColorCode) *ColorCodeConfig {
+	// Deprecated: CodeConfig now stores ConfigIDs. Use Resolve with a registry instead.
+	return nil
+}
+
+// CodeStatus consolidates all code status using a fixed array.
+type CodeStatus struct {
+	// Fixed array status - Value Type
+	Status [MaxColors]ColorCodeStatus
+}
+
+// Status flags for ColorCodeStatus
+const (
+	StatusSuccess  uint8 = 1 << 0 // Last operation succeeded
+	StatusHasError uint8 = 1 << 1 // Error occurred (check error log for details)
+	StatusLogOnly  uint8 = 1 << 2 // Transition recorded without dispatch (dispatch: false)
+)
+
+// ColorCodeStatus uses compact representation:
+// - int64 Unix timestamps instead of time.Time (24 bytes -> 8 bytes each)
+// - uint8 bitfield instead of string (16 bytes -> 1 byte)
+// - uint16 instead of int (8 bytes -> 2 bytes)
+// Total: ~80 bytes -> ~19 bytes per color
+type ColorCodeStatus struct {
+	LastAlertTime       int64  // Unix timestamp
+	LastSuccessTime     int64  // Unix timestamp
+	ConsecutiveFailures uint16 // Max 65535 failures
+	Flags               uint8  // Bitfield: StatusSuccess, StatusHasError
+}
+
+func (s *ColorCodeStatus) SetSuccess(t time.Time) {
+	s.Flags = StatusSuccess
+	s.ConsecutiveFailures = 0
+	s.LastSuccessTime = t.Unix()
+	s.LastAlertTime = t.Unix()
+}
+
+// SetLogOnly records a color transition that was logged without sending the
+// external notification (dispatch: false). LastAlertTime is deliberately left
+// untouched: a log-only record never delivered anything, so it must not start
+// a cooldown window that would hold back the first real alert after the
+// operator flips dispatch back on.
+func (s *ColorCodeStatus) SetLogOnly(t time.Time) {
+	s.Flags = StatusSuccess | StatusLogOnly
+	s.ConsecutiveFailures = 0
+	s.LastSuccessTime = t.Unix()
+}
+
+func (s *ColorCodeStatus) SetFailure(_ error) {
+	s.Flags = StatusHasError
+	if s.ConsecutiveFailures < 65535 {
+		s.ConsecutiveFailures++
+	}
+}
+
+// IsSuccess returns true if last status was success
+func (s *ColorCodeStatus) IsSuccess() bool {
+	return s.Flags&StatusSuccess != 0
+}
+
+// GetLastAlertTime returns LastAlertTime as time.Time
+func (s *ColorCodeStatus) GetLastAlertTime() time.Time {
+	if s.LastAlertTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(s.LastAlertTime, 0)
+}
+
+// GetLastSuccessTime returns LastSuccessTime as time.Time
+func (s *ColorCodeStatus) GetLastSuccessTime() time.Time {
+	if s.LastSuccessTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(s.LastSuccessTime, 0)
+}
+
+func (s *ColorCodeStatus) Copy() *ColorCodeStatus {
+	if s == nil {
+		return nil
+	}
+	cpy := &ColorCodeStatus{
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		Flags:               s.Flags,
+		LastAlertTime:       s.LastAlertTime,
+		LastSuccessTime:     s.LastSuccessTime,
+	}
+	return cpy
+}
+
+func (c *CodeStatus) Copy() *CodeStatus {
+	if c == nil {
+		return nil
+	}
+	cpy := &CodeStatus{}
+	for i := ColorCode(0); i < MaxColors; i++ {
+		cpy.Status[i] = *c.Status[i].Copy()
+	}
+	return cpy
+}
+
+// Get returns a pointer to the status for the given color, or nil if invalid.
+func (c *CodeStatus) Get(color string) *ColorCodeStatus {
+	idx := ColorToIndex(color)
+	if idx >= MaxColors {
+		return nil
+	}
+	return &c.Status[idx]
+}
+
+// JobStorage consolidates all job storage instead of separate job components.
+// This single component replaces PulseJob, InterventionJob, CodeJob, etc.
+type JobStorage struct {
+	PulseJob        jobs.Job
+	InterventionJob jobs.Job
+	// InterventionJobs holds one job per escalation step; InterventionJob
+	// mirrors step 0 for single-step consumers.
+	InterventionJobs []jobs.Job
+}
+
+func (j *JobStorage) Copy() *JobStorage {
+	if j == nil {
+		return nil
+	}
+	cpy := &JobStorage{}
+	if j.PulseJob != nil {
+		cpy.PulseJob = j.PulseJob.Copy()
+	}
+	if j.InterventionJob != nil {
+		cpy.InterventionJob = j.InterventionJob.Copy()
+	}
+	if len(j.InterventionJobs) > 0 {
+		cpy.InterventionJobs = make([]jobs.Job, len(j.InterventionJobs))
+		for n, job := range j.InterventionJobs {
+			if job != nil {
+				cpy.InterventionJobs[n] = job.Copy()
+			}
+		}
+	}
+	return cpy
+}
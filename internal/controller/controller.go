@@ -0,0 +1,1744 @@
+// Package controller provides the core ECS-based controller for managing monitors
+// in the CPRA (Cloud Platform Reliability Automation) system.
+//
+// The controller orchestrates the Entity Component System (ECS) architecture,
+// managing monitor lifecycle, job queuing, worker pools, and system coordination.
+// It is designed to handle large-scale deployments (1M+ monitors) efficiently
+// through batch processing, adaptive queuing, and optimized memory management.
+//
+// # Architecture
+//
+// The controller uses the ark ECS library to manage monitor entities and their
+// components. Key architectural decisions:
+//
+//   - Batch Processing: Systems process entities in batches to maximize throughput
+//   - Queue Abstraction: Multiple queue implementations (Hybrid, Adaptive, Workiva)
+//     can be used based on workload characteristics
+//   - Worker Pools: Dynamic worker pools with automatic scaling for pulse, intervention,
+//     and code alert processing
+//   - Streaming Loader: Efficient YAML/JSON parsing for large monitor configurations
+//
+// # Components
+//
+// The controller manages three primary job types:
+//
+//   - Pulse: Health checks (HTTP, TCP, ICMP) performed at regular intervals
+//   - Intervention: Automated recovery actions (e.g., Docker container restarts)
+//   - Code: Alert notifications (Red, Yellow, Green, Cyan, Gray) sent via various channels
+//
+// # Systems
+//
+// The controller coordinates multiple ECS systems:
+//
+//   - BatchPulseScheduleSystem: Schedules pulse checks based on monitor intervals
+//   - BatchPulseSystem: Enqueues pulse jobs for execution
+//   - BatchPulseResultSystem: Processes pulse results and updates monitor state
+//   - BatchInterventionSystem: Enqueues intervention jobs when thresholds are exceeded
+//   - BatchInterventionResultSystem: Processes intervention results
+//   - BatchCodeSystem: Enqueues code alert jobs
+//   - BatchCodeResultSystem: Processes code alert results
+//
+// # Queue Management
+//
+// The controller supports dynamic queue switching based on entity count thresholds.
+// When the entity count exceeds a configured threshold, the system can automatically
+// switch from HybridQueue to AdaptiveQueue for better performance at scale.
+//
+// # Worker Sizing
+//
+// The controller includes pre-computation of optimal worker pool sizes based on:
+//
+//   - Arrival rate (λ): Computed from monitor pulse intervals
+//   - Service time (τ): Expected job execution time
+//   - SLO target (W): Maximum acceptable end-to-end latency
+//
+// This uses M/M/c queueing theory to determine minimum workers needed and applies
+// a configurable headroom percentage for safety margins.
+//
+// # GC Tuning for Large Deployments
+//
+// For deployments with 1M+ monitors, GC tuning can significantly impact performance:
+//
+//   - GOMEMLIMIT: Set to 70-80% of container memory limit (e.g., GOMEMLIMIT=3200MiB for 4GiB container)
+//   - GOGC: Start with default (100), then tune based on workload:
+//   - Low-latency requirements: Try GOGC=150-200 (fewer, longer collections)
+//   - Memory-constrained: Try GOGC=50-75 (more frequent, shorter collections)
+//
+// Measure before tuning:
+//
+//	GODEBUG=gctrace=1 ./cpra  // GC trace output
+//	go tool pprof http://localhost:6060/debug/pprof/heap  // Heap profile
+//
+// The controller already uses value-oriented programming to minimize GC pressure:
+//   - Components are value types, not pointers
+//   - Batch processing amortizes allocation overhead
+//   - World.Shrink() reclaims memory after loading
+//
+// # Example
+//
+//	config := controller.DefaultConfig()
+//	config.Debug = true
+//	config.BatchSize = 1000
+//	oc := controller.NewController(config)
+//
+//	ctx := context.Background()
+//	if err := oc.LoadMonitors(ctx, "monitors.yaml"); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if err := oc.Start(); err != nil {
+//		log.Fatal(err)
+//	}
+//	defer oc.Stop()
+package controller
+
+import (
+	"context"
+	"cpra/internal/audit"
+	"cpra/internal/clock"
+	"cpra/internal/controller/systems"
+	"cpra/internal/events"
+	"cpra/internal/loader"
+	"cpra/internal/logger"
+	"cpra/internal/queue"
+	"cpra/internal/sinks"
+	"cpra/internal/tracing"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/controller/entities"
+	"cpra/internal/interning"
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/mlange-42/ark/ecs/stats"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// by defaultECSCapacity is the initial entity capacity for the ECS world.
+	defaultECSCapacity = 1024
+
+	// by defaultTPS is the ticks per second for the ark-tools scheduler.
+	defaultTPS = 10
+
+	// defaultServiceTime is the assumed job execution time for worker sizing.
+	defaultServiceTime = 20 * time.Millisecond
+
+	// by defaultSLO is the target end-to-end latency for worker sizing.
+	defaultSLO = 200 * time.Millisecond
+
+	// defaultHeadroom is the safety margin percentage for worker sizing.
+	defaultHeadroom = 0.15
+
+	// defaultSizingRamp is how long the pulse pool takes to climb from its
+	// starting size to the pre-computed recommendation after a load. Ramping
+	// instead of jumping keeps the first wave of checks from slamming
+	// downstream targets all at once.
+	defaultSizingRamp = 30 * time.Second
+
+	// defaultStatsReportInterval is how often the running controller logs
+	// periodic queue and pool stats when the interval is not configured.
+	defaultStatsReportInterval = time.Minute
+
+	// interventionPoolRatio is the fraction of pulse pool size for intervention workers.
+	interventionPoolRatio = 0.25
+
+	// codePoolRatio is the fraction of pulse pool size for code workers.
+	codePoolRatio = 0.125
+
+	// highFrequencyQueueCapacity sizes the dedicated high-frequency pulse queue.
+	// The loader caps the high-frequency population, so a small queue suffices
+	// and keeps dispatch-to-execution latency low.
+	highFrequencyQueueCapacity = 1024
+
+	// shutdownTimeout is the maximum wait time for a graceful shutdown.
+	shutdownTimeout = 5 * time.Second
+
+	// defaultDrainTimeout bounds phase two of shutdown: how long Stop waits
+	// for in-flight intervention and code jobs to finish before abandoning
+	// them. Overridable via the context passed to StopContext.
+	defaultDrainTimeout = 30 * time.Second
+
+	// shrinkBudget is the time budget per incremental memory shrink pass.
+	shrinkBudget = 10 * time.Millisecond
+)
+
+// keepConstantsReferenced guards against staticcheck false positives on const usage.
+var (
+	_ = defaultServiceTime
+	_ = defaultSLO
+	_ = defaultHeadroom
+	_ = interventionPoolRatio
+	_ = codePoolRatio
+	_ = shutdownTimeout
+)
+
+// calculateShardSlots determines shard slots based on TPS and desired sweep duration,
+// unless an explicit override is provided.
+func calculateShardSlots(tps float64, targetSweep time.Duration, override int) int {
+	if override > 0 {
+		return override
+	}
+
+	// Default sweep of the 10s if unset or non-positive
+	if targetSweep <= 0 {
+		targetSweep = 10 * time.Second
+	}
+
+	slots := int(math.Ceil(tps * targetSweep.Seconds()))
+	if slots < 1 {
+		slots = 1
+	}
+
+	// Clamp to a reasonable upper bound to prevent runaway slot counts.
+	const maxSlots = 20000
+	if slots > maxSlots {
+		slots = maxSlots
+	}
+	return slots
+}
+
+// adaptiveTicksPerInterval is how many ticks the adaptive mode aims to run
+// per shortest pulse interval, so a due check is dispatched no more than
+// about a tenth of its interval late.
+const adaptiveTicksPerInterval = 10
+
+// adaptiveTPS computes the tick rate for the shortest loaded pulse interval:
+// adaptiveTicksPerInterval ticks per interval, never below 1 tick/s and
+// never above the configured ceiling. A non-positive interval (nothing
+// loaded yet) keeps the ceiling.
+func adaptiveTPS(minInterval time.Duration, ceiling float64) float64 {
+	if ceiling <= 0 {
+		ceiling = defaultTPS
+	}
+	if minInterval <= 0 {
+		return ceiling
+	}
+	tps := adaptiveTicksPerInterval / minInterval.Seconds()
+	if tps < 1 {
+		return 1
+	}
+	if tps > ceiling {
+		return ceiling
+	}
+	return tps
+}
+
+// createWorkerPool creates a dynamic worker pool for the given queue.
+func createWorkerPool(name string, kind jobs.JobKind, q queue.Queue, config queue.WorkerPoolConfig) (*queue.DynamicWorkerPool, error) {
+	config.Name = name
+	config.Kind = kind
+	logger := log.New(os.Stdout, fmt.Sprintf("[%sPool] ", name), log.LstdFlags)
+	pool, err := queue.NewDynamicWorkerPool(context.Background(), q, config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s worker pool: %w", name, err)
+	}
+	return pool, nil
+}
+
+// createQueue creates a named job queue from its settings after validating
+// them, falling back to a hybrid queue with the given drop policy and
+// capacity where settings are unset. Conflation collapses duplicate jobs per
+// entity and is only safe for the pulse queues: a re-queued pulse is
+// idempotent, while every intervention or code job carries distinct intent.
+// The adaptive implementation has no conflation; it simply rejects when full.
+func createQueue(name string, settings QueueSettings, defPolicy queue.DropPolicy, defCapacity uint64, conflate bool) (queue.Queue, error) {
+	if err := settings.validate(name); err != nil {
+		return nil, err
+	}
+	capacity := settings.Capacity
+	if capacity == 0 {
+		capacity = defCapacity
+	}
+
+	if settings.Implementation == QueueImplAdaptive {
+		q, err := queue.NewQueue(queue.QueueConfig{
+			Name:     name,
+			Type:     queue.QueueTypeAdaptive,
+			Capacity: int(capacity),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s AdaptiveQueue: %w", name, err)
+		}
+		return q, nil
+	}
+
+	cfg := queue.DefaultQueueConfig()
+	cfg.Name = name
+	cfg.HybridConfig.Name = name
+	cfg.HybridConfig.DropPolicy = defPolicy
+	if settings.DropPolicy != "" {
+		// Already validated above; the parse cannot fail here.
+		cfg.HybridConfig.DropPolicy, _ = queue.ParseDropPolicy(settings.DropPolicy)
+	}
+	if settings.OverflowCapacity > 0 {
+		cfg.HybridConfig.OverflowCapacity = settings.OverflowCapacity
+	}
+	if settings.SoftWatermark > 0 {
+		cfg.HybridConfig.SoftWatermark = settings.SoftWatermark
+	}
+	if settings.HardWatermark > 0 {
+		cfg.HybridConfig.HardWatermark = settings.HardWatermark
+	}
+	if conflate {
+		cfg.HybridConfig.ConflateKey = queue.DefaultPulseConflateKey
+	}
+	// Wire the capacity from controller config to queue config
+	if capacity > 0 {
+		cfg.Capacity = int(capacity)
+		cfg.HybridConfig.RingCapacity = int(capacity)
+	}
+	q, err := queue.NewQueue(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s HybridQueue: %w", name, err)
+	}
+	return q, nil
+}
+
+// Controller manages the ECS world and its systems using ark-tools.
+//
+// Controller coordinates all aspects of monitor management, including
+// entity lifecycle, job queuing, worker pool management, and system execution.
+// It provides a high-level API for loading monitors, starting/stopping the system,
+// and accessing the underlying ECS world for testing and debugging.
+//
+// The controller is thread-safe for concurrent access to read-only operations
+// like World(). Start() and Stop() should be called from a single goroutine
+// or with proper synchronization.
+type Controller struct {
+	world             *ecs.World
+	app               *app.App
+	mapper            *entities.EntityManager
+	heartbeats        *systems.HeartbeatIngestor
+	logger            *zap.SugaredLogger
+	stateLogger       *systems.StateLogger
+	terminationSys    *systems.TerminationSystem // System to handle graceful shutdown
+	commands          *systems.CommandSystem     // External world mutations, applied inside the tick
+	pulsePool         *queue.DynamicWorkerPool
+	interventionPool  *queue.DynamicWorkerPool
+	codePool          *queue.DynamicWorkerPool
+	hfPool            *queue.DynamicWorkerPool
+	pulseQueue        queue.Queue
+	interventionQueue queue.Queue
+	codeQueue         queue.Queue
+	hfQueue           queue.Queue
+	hfSystem          *systems.HighFrequencyPulseSystem
+	pulseSystem       *systems.BatchPulseSystem
+	pulseResults      *systems.BatchPulseResultSystem
+	hfResults         *systems.BatchPulseResultSystem
+	interventionRes   *systems.BatchInterventionResultSystem
+	codeResults       *systems.BatchCodeResultSystem
+	subs              *resultSubscribers
+	eventBus          *events.Bus
+	faults            *systems.FaultInjector // nil unless soak mode is enabled
+	invariants        *systems.InvariantSystem
+	consistency       *systems.ConfigConsistencySystem
+	pendingRecovery   *systems.PendingRecoverySystem
+	auditLog          *audit.SQLiteSink            // nil unless Config.AuditDB is set
+	sharedProbes      *systems.SharedProbeRegistry // nil unless Config.DedupeProbes is set
+	traceProvider     *sdktrace.TracerProvider     // nil unless Config.Tracing.Endpoint is set
+	haGate            *systems.StandbyGate         // nil unless HA mode is on
+	isLeader          atomic.Bool                  // current leadership; false while standby
+	manifestGen       atomic.Uint32                // bumped per successful load/reload
+	manifestPath      string                       // last file given to LoadMonitors; watched in watch mode
+	reloadsAttempted  atomic.Uint64
+	reloadsSucceeded  atomic.Uint64
+	lastReload        atomic.Int64 // unix nanos of the last successful reload
+	started           atomic.Int64 // unix nanos of Start; zero until first started
+
+	// Registered filters for Snapshot's monitor counts: registration keeps
+	// the matching archetypes cached, so counting is independent of fleet
+	// size.
+	monitorCountFilter  *ecs.Filter1[components.MonitorState]
+	disabledCountFilter *ecs.Filter1[components.Disabled]
+	runDone             chan struct{}
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	drainCancel         context.CancelFunc // releases the intervention/code pools after phase two of Stop
+	drainReport         DrainReport        // outcome of the last shutdown drain
+	config              Config
+	currentTPS          float64    // last tick rate applied by AdaptiveTPS; guarded by mu on the reload path
+	mu                  sync.Mutex // Protects state transitions during Start/Stop
+	running             atomic.Bool
+}
+
+// Stats ControllerStats aggregates runtime statistics for queues, worker pools, and the ECS world.
+type Stats struct {
+	PulseQueue          queue.Stats                  `json:"pulse_queue"`
+	InterventionQueue   queue.Stats                  `json:"intervention_queue"`
+	CodeQueue           queue.Stats                  `json:"code_queue"`
+	PulseWorkers        queue.WorkerPoolStats        `json:"pulse_workers"`
+	InterventionWorkers queue.WorkerPoolStats        `json:"intervention_workers"`
+	CodeWorkers         queue.WorkerPoolStats        `json:"code_workers"`
+	World               *stats.World                 `json:"world"`
+	ConfigConsistency   systems.ConsistencyStats     `json:"config_consistency"`
+	PendingRecovery     systems.PendingRecoveryStats `json:"pending_recovery"`
+	HighFrequency       systems.AccuracyStats        `json:"high_frequency_accuracy"`
+	Reloads             ReloadStats                  `json:"reloads"`
+	HA                  HAStats                      `json:"ha"`
+	Subscribers         []SubscriberStats            `json:"subscribers,omitempty"`
+}
+
+// Stats return a snapshot of controller runtime statistics.
+func (c *Controller) Stats() Stats {
+	return Stats{
+		PulseQueue:          c.pulseQueue.Stats(),
+		InterventionQueue:   c.interventionQueue.Stats(),
+		CodeQueue:           c.codeQueue.Stats(),
+		PulseWorkers:        c.pulsePool.Stats(),
+		InterventionWorkers: c.interventionPool.Stats(),
+		CodeWorkers:         c.codePool.Stats(),
+		World:               c.world.Stats(),
+		ConfigConsistency:   c.consistency.Stats(),
+		PendingRecovery:     c.pendingRecovery.Stats(),
+		HighFrequency:       c.hfSystem.Accuracy(),
+		Reloads:             c.ReloadStats(),
+		HA:                  HAStats{Enabled: c.config.HA.Elector != nil, Leader: c.IsLeader()},
+		Subscribers:         c.subs.stats(),
+	}
+}
+
+// Config holds all configuration for the controller.
+//
+// Configuration can be set programmatically or via environment variables
+// for sizing parameters (CPRA_SIZING_TAU_MS, CPRA_SIZING_SLO_MS, CPRA_SIZING_HEADROOM_PCT).
+//
+// Default values are optimized for large-scale deployments but can be adjusted
+// based on workload characteristics and resource constraints.
+type Config struct {
+	Logger *zap.SugaredLogger
+
+	// Clock is the time source for scheduling decisions (pulse intervals,
+	// intervention cooldowns, maintenance windows, pool autoscaling). Nil
+	// uses the system clock. Tests inject clock.Manual to drive interval
+	// edge cases deterministically; jobs keep real time for network
+	// deadlines either way.
+	Clock clock.Clock
+
+	// Per-pool worker configurations. The three workloads differ enough to
+	// tune separately: pulses are high-volume short checks, interventions
+	// are rare but long (docker restarts), and code jobs are outbound
+	// notification calls. The high-frequency pulse pool shares
+	// PulseWorkerConfig. Defaults are identical for all three.
+	PulseWorkerConfig        queue.WorkerPoolConfig
+	InterventionWorkerConfig queue.WorkerPoolConfig
+	CodeWorkerConfig         queue.WorkerPoolConfig
+
+	PipelineConfig    loader.PipelineConfig
+	QueueCapacity     uint64
+	BatchSize         int
+	UpdateInterval    time.Duration
+	SizingServiceTime time.Duration
+	SizingSLO         time.Duration
+	SizingHeadroomPct float64
+	// SizingRamp spreads the post-load pulse pool resize over this duration
+	// in steps instead of one jump; zero or negative keeps the 30s default.
+	SizingRamp time.Duration
+	Debug      bool
+
+	// Shard tuning
+	ShardSlots       int           // Explicit shard slot count; if <=0, auto-calculated
+	ShardTargetSweep time.Duration // Desired full sweep duration across all shards; used when ShardSlots <= 0
+
+	// PulseQueue, InterventionQueue, and CodeQueue tune each job queue
+	// individually: the implementation (hybrid or adaptive), a per-queue
+	// capacity overriding QueueCapacity, the hybrid overflow sizing and
+	// watermarks, and the saturation drop policy by name. Zero values keep
+	// the established defaults; invalid settings fail NewController. The
+	// high-frequency pulse queue shares PulseQueue's implementation and
+	// policy but keeps its own small fixed capacity.
+	PulseQueue        QueueSettings
+	InterventionQueue QueueSettings
+	CodeQueue         QueueSettings
+
+	// AdaptiveQueueEntityThreshold switches the main pulse queue to the
+	// lock-free AdaptiveQueue once a load brings the fleet to this many
+	// monitors, trading the hybrid queue's overflow and conflation features
+	// for lower per-enqueue overhead at scale. The switch happens after
+	// LoadMonitors and only before Start; an explicit
+	// PulseQueue.Implementation always wins. Zero disables the switch.
+	AdaptiveQueueEntityThreshold int
+
+	// TPS is the tick rate of the ECS scheduler. Zero or negative keeps the
+	// default (10). Higher rates tighten scheduling granularity at the cost
+	// of idle CPU; shard sweep and time-wheel precision both scale with it.
+	TPS float64
+
+	// AdaptiveTPS lowers the tick rate to match the shortest pulse interval
+	// actually loaded: a fleet of 5-minute checks does not need 10 scans a
+	// second. The rate is recomputed after every load and reload, bounded
+	// below by 1 tick/s and above by TPS.
+	AdaptiveTPS bool
+
+	// PulseTimeWheel switches pulse dispatch from the per-shard filter scan
+	// to a bucketed time-wheel keyed by next check time, so per-tick cost
+	// tracks the number of due monitors instead of the fleet size. Off by
+	// default while the two schedulers are being A/B compared.
+	PulseTimeWheel bool
+
+	// WatchManifest enables automatic diff-reloads when the manifest file
+	// changes on disk. WatchDebounce is the quiet period after the last
+	// change before the reload fires; zero uses DefaultWatchDebounce.
+	WatchManifest bool
+	WatchDebounce time.Duration
+
+	// StatsReportInterval is how often the running controller logs compact
+	// queue, pool, and world stats with deltas since the previous report.
+	// Zero disables periodic reporting; shutdown metrics still print.
+	StatsReportInterval time.Duration
+
+	// HTTPTransport tunes connection pooling for the shared net/http
+	// transports (MaxIdleConnsPerHost, IdleConnTimeout). Zero fields keep
+	// the jobs package defaults.
+	HTTPTransport jobs.HTTPTransportConfig
+
+	// HTTPProxy sets a default proxy for HTTP pulse checks (monitors can
+	// override per check) and the hosts that bypass proxying entirely.
+	HTTPProxy jobs.HTTPProxyConfig
+
+	// DialLimits tunes the global dial limiter shared by all outbound checks
+	// (semaphore size, dials per second, burst, acquire timeout). Zero fields
+	// keep the jobs package defaults; the first dial freezes the limits.
+	DialLimits jobs.DialLimiterConfig
+
+	// Tracing enables OpenTelemetry spans around the pulse job lifecycle
+	// (dispatch, queue wait, attempts, result application) for monitors
+	// flagged trace: true or sampled at Tracing.SampleRate. Off unless an
+	// exporter endpoint is set.
+	Tracing tracing.Config
+
+	// Logging re-initializes the component loggers from an explicit config
+	// (format: text|json, level, output path, sampling). The zero value
+	// keeps whatever InitializeLoggers already set up.
+	Logging logger.LoggerConfig
+
+	// HistorySize is the number of recent pulse results kept per monitor in
+	// a fixed-size ring for flap debugging (see GetHistory and the admin
+	// API). Zero or negative disables history entirely.
+	HistorySize int
+
+	// GroupLimits caps in-flight outbound checks per concurrency group (the
+	// first entry of a monitor's pulse groups). The "default" key applies to
+	// groups without an explicit entry; zero or missing means unlimited.
+	// Saturated groups have their checks re-enqueued with a small delay.
+	GroupLimits map[string]int
+
+	// Consistency configures the config-generation consistency sweep that
+	// detects entities left with components from different manifest versions.
+	Consistency systems.ConsistencyConfig
+
+	// PendingRecovery configures the watchdog that recovers monitors stuck
+	// in a pending state because their job result never arrived (crashed
+	// worker, dropped result batch).
+	PendingRecovery systems.PendingRecoveryConfig
+
+	// Faults enables soak-test fault injection when Faults.Enabled is true.
+	// Never enable in production: faults are injected into the live pulse
+	// pipeline. Enabling it also runs the invariant checker (see Invariants).
+	Faults     systems.FaultConfig
+	Invariants systems.InvariantConfig
+
+	// HA enables active/standby leader election when HA.Elector is set.
+	// Only the elected leader dispatches work; the standby keeps loading
+	// and watching the manifest so failover starts from a warm world.
+	HA HAConfig
+
+	// DryRunInterventions forces every intervention into dry-run mode
+	// regardless of per-monitor dry_run flags: actions are evaluated and
+	// logged, and a cyan notification fires, but nothing is restarted or
+	// executed. A global safety switch for initial rollouts.
+	DryRunInterventions bool
+
+	// AuditDB is the path of a SQLite database recording every intervention
+	// and code notification with its outcome (":memory:" in tests). Empty
+	// disables auditing. Writes are asynchronous and never block the tick; a
+	// full buffer drops events and counts them instead.
+	AuditDB string
+
+	// ResultSinks streams every routed job result to external outputs —
+	// see the sinks package for the built-in rotating NDJSON file writer
+	// and statsd/UDP emitter. Sinks observe batches off the result hot
+	// path through their own bounded queues; Stop flushes and closes them.
+	ResultSinks []sinks.ResultSink
+
+	// DedupeProbes enables shared-probe deduplication: monitors whose
+	// checks are wire-identical (same type, normalized target, method, and
+	// timeout) probe once per group at the group's smallest interval, and
+	// the single result fans out to every member. Off by default; monitors
+	// opt out individually with `dedupe: false`.
+	DedupeProbes bool
+}
+
+// DefaultConfig returns a default configuration optimized for large-scale deployments.
+//
+// The default configuration uses:
+//   - Queue capacity of 65536 (must be power of 2)
+//   - Batch size of 1000 entities per system update
+//   - Default worker pool configuration
+//   - Streaming loader defaults optimized for large files
+//
+// These defaults can be overridden based on specific deployment requirements.
+func DefaultConfig() Config {
+	return Config{
+		PipelineConfig:           loader.DefaultPipelineConfig(),
+		QueueCapacity:            8192, // Reduced from 65536 to save ~25MB memory per queue instance
+		PulseWorkerConfig:        queue.DefaultWorkerPoolConfig(),
+		InterventionWorkerConfig: queue.DefaultWorkerPoolConfig(),
+		CodeWorkerConfig:         queue.DefaultWorkerPoolConfig(),
+		BatchSize:                1000,
+		// UpdateInterval removed - ark-tools TPS=100 controls all timing
+		SizingServiceTime:   0,
+		SizingSLO:           0,
+		SizingHeadroomPct:   0,
+		SizingRamp:          0,
+		ShardSlots:          0,
+		ShardTargetSweep:    10 * time.Second, // aim for ~10s sweep by default
+		TPS:                 defaultTPS,
+		StatsReportInterval: defaultStatsReportInterval,
+		HistorySize:         components.DefaultHistorySize,
+		Consistency:         systems.DefaultConsistencyConfig(),
+		PendingRecovery:     systems.DefaultPendingRecoveryConfig(),
+	}
+}
+
+// NewController creates a new controller with the refactored systems using ark-tools.
+//
+// NewController initializes:
+//   - an ECS world with initial capacity
+//   - Three queue instances (pulse, intervention, code) with HybridQueue by default
+//   - Three dynamic worker pools for job execution
+//   - All batch processing systems
+//   - Entity mapper for monitor management
+//
+// The controller is created in a stopped state. Call Start() to begin processing.
+//
+// Returns an error if queue or worker pool creation fails.
+func NewController(config Config) (*Controller, error) {
+	// Re-initialize loggers when an explicit logging config is provided, so
+	// everything the controller logs from here on uses it.
+	if config.Logging != (logger.LoggerConfig{}) {
+		InitializeLoggersWithConfig(config.Logging)
+	}
+
+	// One clock for every scheduling decision; the worker pools inherit it
+	// unless their config already carries one.
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+	if config.PulseWorkerConfig.Clock == nil {
+		config.PulseWorkerConfig.Clock = config.Clock
+	}
+	if config.InterventionWorkerConfig.Clock == nil {
+		config.InterventionWorkerConfig.Clock = config.Clock
+	}
+	if config.CodeWorkerConfig.Clock == nil {
+		config.CodeWorkerConfig.Clock = config.Clock
+	}
+
+	// Apply HTTP transport tuning before any client is created; the first
+	// client freezes the configuration.
+	if config.HTTPTransport != (jobs.HTTPTransportConfig{}) {
+		jobs.SetHTTPTransportConfig(config.HTTPTransport)
+	}
+
+	// The proxy default has to land before the loader builds jobs: each job
+	// resolves its proxy (override, default, no-proxy bypass) at creation.
+	if config.HTTPProxy.Proxy != "" || len(config.HTTPProxy.NoProxy) > 0 {
+		jobs.SetHTTPProxyConfig(config.HTTPProxy)
+	}
+
+	// Per-group concurrency limits guard downstream services shared by many
+	// monitors; applied before any pool starts dispatching.
+	if len(config.GroupLimits) > 0 {
+		jobs.SetGroupLimits(config.GroupLimits)
+	}
+
+	// Dial limiter overrides must land before the first check acquires a
+	// slot; the limiter is a create-once singleton.
+	if config.DialLimits != (jobs.DialLimiterConfig{}) {
+		jobs.SetDialLimiterConfig(config.DialLimits)
+	}
+
+	// Create ark-tools app with initial capacity
+	arkApp := app.New(defaultECSCapacity)
+	arkApp.TPS = config.TPS // Modest by default to lower CPU utilization; shard scheduling keeps precision
+	if arkApp.TPS <= 0 {
+		arkApp.TPS = defaultTPS
+	}
+	world := &arkApp.World
+	shardSlots := calculateShardSlots(arkApp.TPS, config.ShardTargetSweep, config.ShardSlots)
+	mapper := entities.NewEntityManager(world)
+	mapper.SetShardSlots(shardSlots)
+	mapper.SetHistorySize(config.HistorySize)
+	mapper.SetClock(config.Clock)
+	mapper.SetInterventionDryRun(config.DryRunInterventions)
+
+	// Heartbeat ingestion: token registry plus thread-safe handoff from the
+	// HTTP endpoint into the tick loop.
+	heartbeats := systems.NewHeartbeatIngestor()
+	mapper.SetHeartbeatRegistrar(heartbeats)
+
+	// Hybrid queues per queue class by default, using configured capacity;
+	// per-queue settings select the implementation and override the policy.
+	pulseQueue, err := createQueue("pulse", config.PulseQueue, queue.DropPolicyDropNewest, config.QueueCapacity, true)
+	if err != nil {
+		return nil, err
+	}
+	interventionQueue, err := createQueue("intervention", config.InterventionQueue, queue.DropPolicyDropOldest, config.QueueCapacity, false)
+	if err != nil {
+		return nil, err
+	}
+	codeQueue, err := createQueue("code", config.CodeQueue, queue.DropPolicyDropNewest, config.QueueCapacity, false)
+	if err != nil {
+		return nil, err
+	}
+	// High-frequency monitors get their own small queue and worker set so a
+	// saturated bulk pulse queue cannot delay sub-second checks. It follows
+	// the pulse queue's settings except for the capacity, which stays fixed.
+	hfSettings := config.PulseQueue
+	hfSettings.Capacity = 0
+	hfQueue, err := createQueue("pulse-hf", hfSettings, queue.DropPolicyDropNewest, highFrequencyQueueCapacity, true)
+	if err != nil {
+		return nil, err
+	}
+
+	pulsePool, err := createWorkerPool("Pulse", jobs.KindPulse, pulseQueue, config.PulseWorkerConfig)
+	if err != nil {
+		return nil, err
+	}
+	hfPool, err := createWorkerPool("PulseHF", jobs.KindPulse, hfQueue, config.PulseWorkerConfig)
+	if err != nil {
+		return nil, err
+	}
+	interventionPool, err := createWorkerPool("Intervention", jobs.KindIntervention, interventionQueue, config.InterventionWorkerConfig)
+	if err != nil {
+		return nil, err
+	}
+	codePool, err := createWorkerPool("Code", jobs.KindCode, codeQueue, config.CodeWorkerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	stateLogger := systems.NewStateLogger(config.Debug)
+
+	// Use the provided logger or create one for CONTROLLER component
+	ctrlLogger := config.Logger
+	if ctrlLogger == nil {
+		cfg := logger.DefaultConfig()
+		if config.Debug {
+			cfg = logger.DevelopmentConfig()
+		}
+		var err error
+		ctrlLogger, err = logger.NewSugaredLoggerWithComponent("CONTROLLER", cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create controller logger: %w", err)
+		}
+	}
+
+	// Surface socket exhaustion watchdog transitions in the controller log.
+	jobs.GetDialMetrics().SetWatchdogCallback(func(ev jobs.DialWatchdogEvent) {
+		if ev.Active {
+			ctrlLogger.Warnf("Dial watchdog: socket exhaustion detected (class=%s errors_in_window=%d); exhaustion-classed failures are exempt from monitor failure counting", ev.Class, ev.ErrorsInWindow)
+		} else {
+			ctrlLogger.Infof("Dial watchdog: socket exhaustion cleared")
+		}
+	})
+
+	// Instantiate the refactored systems with dedicated queues and worker pools.
+	pulseRouter := pulsePool.GetRouter()
+	interventionRouter := interventionPool.GetRouter()
+	codeRouter := codePool.GetRouter()
+	hfRouter := hfPool.GetRouter()
+
+	// Fan routed results out to external subscribers (see Subscribe).
+	// Installed on every router before the pools start; publish never
+	// blocks, so the result hot path is unaffected.
+	subs := newResultSubscribers()
+
+	// Result sinks tap the same routed batches; each sink copies and
+	// buffers internally, so the tap never blocks. Sinks that tag their
+	// output with monitor names get the mapper's reverse index.
+	for _, sink := range config.ResultSinks {
+		if ra, ok := sink.(sinks.ResolverAware); ok {
+			ra.SetResolver(func(ent ecs.Entity) (string, interning.Labels, bool) {
+				info, ok := mapper.LookupMonitorInfo(ent)
+				return info.Name, info.Labels, ok
+			})
+		}
+	}
+
+	observe := func(label string, results []jobs.Result) {
+		if kind, ok := resultKindFromLabel(label); ok {
+			subs.publish(kind, results)
+		}
+		for _, sink := range config.ResultSinks {
+			sink.Accept(results)
+		}
+	}
+	pulseRouter.SetResultObserver(observe)
+	interventionRouter.SetResultObserver(observe)
+	codeRouter.SetResultObserver(observe)
+	hfRouter.SetResultObserver(observe)
+
+	pulseSystem := systems.NewBatchPulseSystem(world, pulseQueue, config.BatchSize, ctrlLogger, stateLogger, shardSlots)
+	hfSystem := systems.NewHighFrequencyPulseSystem(world, hfQueue, ctrlLogger, stateLogger)
+	heartbeatSystem := systems.NewHeartbeatSystem(world, heartbeats, pulseRouter.PulseResultChan, ctrlLogger, stateLogger)
+	pulseResultSystem := systems.NewBatchPulseResultSystem(world, pulseRouter.PulseResultChan, ctrlLogger, stateLogger)
+	hfResultSystem := systems.NewBatchPulseResultSystem(world, hfRouter.PulseResultChan, ctrlLogger, stateLogger)
+
+	interventionSystem := systems.NewBatchInterventionSystem(world, interventionQueue, config.BatchSize, ctrlLogger, stateLogger)
+	interventionResultSystem := systems.NewBatchInterventionResultSystem(world, interventionRouter.InterventionResultChan, ctrlLogger, stateLogger)
+
+	codeSystem := systems.NewBatchCodeSystem(world, codeQueue, config.BatchSize, ctrlLogger, stateLogger)
+	codeResultSystem := systems.NewBatchCodeResultSystem(world, codeRouter.CodeResultChan, ctrlLogger, stateLogger)
+
+	// Semantic transition events for embedders (see Events). The result
+	// systems publish from inside the tick; the bus never blocks them.
+	eventBus := events.NewBus()
+	interventionResultSystem.SetEventBus(eventBus)
+	codeResultSystem.SetEventBus(eventBus)
+
+	// MaintenanceSystem toggles scheduled suppression windows; its gray
+	// announcements go out through the code queue.
+	maintenanceSystem := systems.NewMaintenanceSystem(world, codeQueue, ctrlLogger, stateLogger)
+
+	// ActiveHoursSystem suppresses monitors outside their check schedule.
+	activeHoursSystem := systems.NewActiveHoursSystem(world, ctrlLogger, stateLogger)
+
+	// SLOSystem turns error-budget burn into yellow/red codes; like the
+	// maintenance grays, its alerts go out through the code queue directly.
+	sloSystem := systems.NewSLOSystem(world, codeQueue, ctrlLogger, stateLogger)
+
+	// Scheduling decisions in every system run on the injected clock.
+	pulseSystem.SetClock(config.Clock)
+	hfSystem.SetClock(config.Clock)
+	heartbeatSystem.SetClock(config.Clock)
+	interventionSystem.SetClock(config.Clock)
+	codeSystem.SetClock(config.Clock)
+	maintenanceSystem.SetClock(config.Clock)
+	activeHoursSystem.SetClock(config.Clock)
+	sloSystem.SetClock(config.Clock)
+	if config.PulseTimeWheel {
+		pulseSystem.EnableTimeWheel()
+	}
+
+	// HA mode: every dispatch system starts gated and only opens while this
+	// instance holds leadership. Result systems stay ungated so a late
+	// result from before a handover still lands.
+	var haGate *systems.StandbyGate
+	if config.HA.Elector != nil {
+		haGate = systems.NewStandbyGate()
+		pulseSystem.SetStandbyGate(haGate)
+		hfSystem.SetStandbyGate(haGate)
+		heartbeatSystem.SetStandbyGate(haGate)
+		interventionSystem.SetStandbyGate(haGate)
+		codeSystem.SetStandbyGate(haGate)
+		maintenanceSystem.SetStandbyGate(haGate)
+		sloSystem.SetStandbyGate(haGate)
+	}
+
+	// Audit log: the result systems hand every intervention and notification
+	// outcome to the sink; the sink's own goroutine persists them so the tick
+	// never touches SQLite.
+	var auditLog *audit.SQLiteSink
+	if config.AuditDB != "" {
+		var err error
+		auditLog, err = audit.NewSQLiteSink(config.AuditDB, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		interventionResultSystem.SetAuditSink(auditLog)
+		codeResultSystem.SetAuditSink(auditLog)
+	}
+
+	// Consistency sweep: detects entities whose config components span
+	// manifest generations after an interrupted update. Incremental and
+	// bounded per tick, so it is always on.
+	consistencySystem := systems.NewConfigConsistencySystem(world, config.Consistency, ctrlLogger)
+
+	// TerminationSystem monitors the context and signals termination from within the ECS loop
+	// This avoids race conditions with external writers to the Termination resource
+	terminationSystem := systems.NewTerminationSystem(nil) // Context set in Start()
+
+	// CommandSystem applies admin API mutations from inside the tick.
+	commandSystem := systems.NewCommandSystem(systems.DefaultCommandBuffer)
+
+	// Repeatedly panicking checks auto-disable their monitor; the disable
+	// goes through the command buffer so the tag add lands between ticks,
+	// and the announcing red code is enqueued directly.
+	pulseResultSystem.SetPanicAutoDisable(commandSystem, codeQueue, systems.DefaultPanicDisableThreshold)
+	hfResultSystem.SetPanicAutoDisable(commandSystem, codeQueue, systems.DefaultPanicDisableThreshold)
+
+	// Stuck-pending watchdog: recovers monitors whose in-flight job result
+	// never came back. Its state writes go through the command buffer, so
+	// they land at the top of a later tick rather than mid-query.
+	pendingRecoverySystem := systems.NewPendingRecoverySystem(world, config.PendingRecovery, commandSystem, ctrlLogger, stateLogger)
+	pendingRecoverySystem.SetClock(config.Clock)
+
+	// Soak mode: fault injection plus invariant checking. Guarded behind an
+	// explicit config switch (and the --unsafe-fault-injection flag in main).
+	var faultInjector *systems.FaultInjector
+	var invariantSystem *systems.InvariantSystem
+	if config.Faults.Enabled {
+		faultInjector = systems.NewFaultInjector(config.Faults, ctrlLogger)
+		pulseSystem.SetFaultInjector(faultInjector)
+		pulseResultSystem.SetFaultInjector(faultInjector)
+
+		invariantSystem = systems.NewInvariantSystem(world, config.Invariants, ctrlLogger)
+		invariantSystem.AttachQueue("pulse", pulseQueue)
+		invariantSystem.AttachQueue("intervention", interventionQueue)
+		invariantSystem.AttachQueue("code", codeQueue)
+		invariantSystem.AttachPool("pulse", pulsePool)
+		invariantSystem.AttachPool("intervention", interventionPool)
+		invariantSystem.AttachPool("code", codePool)
+	}
+
+	arkApp.AddSystem(terminationSystem) // Add first so it runs early in the tick
+	arkApp.AddSystem(commandSystem)     // External mutations land before dispatch systems read state
+	arkApp.AddSystem(maintenanceSystem) // Window edges settle before dispatch systems read state
+	arkApp.AddSystem(activeHoursSystem) // Schedule edges likewise, so suppression lands before scheduling
+	arkApp.AddSystem(hfSystem)          // Before the bulk systems to minimize dispatch jitter
+	arkApp.AddSystem(pulseSystem)
+	arkApp.AddSystem(heartbeatSystem)
+	arkApp.AddSystem(interventionSystem)
+	arkApp.AddSystem(codeSystem)
+	arkApp.AddSystem(pulseResultSystem)
+	arkApp.AddSystem(hfResultSystem)
+	arkApp.AddSystem(interventionResultSystem)
+	arkApp.AddSystem(codeResultSystem)
+	arkApp.AddSystem(sloSystem) // Burn rates read the rings the result systems just fed
+	arkApp.AddSystem(consistencySystem)
+	arkApp.AddSystem(pendingRecoverySystem) // After the result systems, so fresh results clear pending first
+	if invariantSystem != nil {
+		// Last so it observes the state the other systems settled on this tick.
+		arkApp.AddSystem(invariantSystem)
+	}
+
+	// Shared-probe dedup: the registry groups wire-identical checks after
+	// each load, and the bulk result system fans leader results out. The
+	// high-frequency path keeps per-monitor probes; its accuracy contract
+	// is per entity.
+	var sharedProbes *systems.SharedProbeRegistry
+	if config.DedupeProbes {
+		sharedProbes = systems.NewSharedProbeRegistry()
+		pulseResultSystem.SetSharedProbes(sharedProbes)
+	}
+
+	// Optional trace pipeline: dispatch opens root spans, jobs record queue
+	// wait and attempts, the result system closes the tree. The exporter
+	// dials lazily, so an absent collector costs dropped spans, not startup.
+	var traceProvider *sdktrace.TracerProvider
+	if config.Tracing.Endpoint != "" {
+		var err error
+		traceProvider, err = tracing.Setup(context.Background(), config.Tracing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up tracing: %w", err)
+		}
+		tracer := traceProvider.Tracer("cpra/controller")
+		pulseSystem.SetTracer(tracer, config.Tracing.SampleRate)
+		pulseResultSystem.SetTracer(tracer)
+	}
+
+	return &Controller{
+		app:                 arkApp,
+		world:               world,
+		mapper:              mapper,
+		monitorCountFilter:  ecs.NewFilter1[components.MonitorState](world).Register(),
+		disabledCountFilter: ecs.NewFilter1[components.Disabled](world).Register(),
+		heartbeats:          heartbeats,
+		terminationSys:      terminationSystem,
+		commands:            commandSystem,
+		pulseQueue:          pulseQueue,
+		interventionQueue:   interventionQueue,
+		codeQueue:           codeQueue,
+		hfQueue:             hfQueue,
+		pulsePool:           pulsePool,
+		interventionPool:    interventionPool,
+		codePool:            codePool,
+		hfPool:              hfPool,
+		hfSystem:            hfSystem,
+		pulseSystem:         pulseSystem,
+		pulseResults:        pulseResultSystem,
+		hfResults:           hfResultSystem,
+		interventionRes:     interventionResultSystem,
+		codeResults:         codeResultSystem,
+		config:              config,
+		stateLogger:         stateLogger,
+		logger:              ctrlLogger,
+		subs:                subs,
+		eventBus:            eventBus,
+		faults:              faultInjector,
+		auditLog:            auditLog,
+		sharedProbes:        sharedProbes,
+		traceProvider:       traceProvider,
+		currentTPS:          arkApp.TPS,
+		haGate:              haGate,
+		invariants:          invariantSystem,
+		consistency:         consistencySystem,
+		pendingRecovery:     pendingRecoverySystem,
+	}, nil
+}
+
+// LoadMonitors loads monitors from a YAML file or directory using the loader.
+//
+// LoadMonitors parses the file concurrently, creates ECS entities for each monitor,
+// and initializes all required components. It supports YAML formats with optional
+// gzip compression (.gz extension). A directory path loads its *.yaml/*.yml
+// files in sorted order as one manifest; duplicate names across files abort
+// the load.
+//
+// After loading completes, the controller:
+//   - Pre-computes optimal worker pool sizing for pulse jobs
+//
+// The context can be used to cancel the loading operation.
+//
+// Returns an error if file parsing or entity creation fails.
+func (c *Controller) LoadMonitors(ctx context.Context, filename string) error {
+	// Get total manifest size for progress reporting (summed for directories)
+	var totalBytes int64
+	if files, err := loader.ManifestFiles(filename); err == nil {
+		for _, f := range files {
+			if stat, err := os.Stat(f); err == nil {
+				totalBytes += stat.Size()
+			}
+		}
+	}
+
+	// Set up progress reporting to stderr. A caller-provided callback (e.g.
+	// the -debug progress printer wired in main) is chained after it.
+	pipelineConfig := c.config.PipelineConfig
+	userCallback := pipelineConfig.ProgressCallback
+	progressCallback, progressComplete := loader.DefaultProgressCallback(os.Stderr, totalBytes)
+	if userCallback != nil {
+		reporterCallback := progressCallback
+		progressCallback = func(progress loader.LoadProgress) {
+			reporterCallback(progress)
+			userCallback(progress)
+		}
+	}
+	pipelineConfig.ProgressCallback = progressCallback
+
+	// Stamp all components written by this load with the next manifest
+	// generation; the counter only advances if the load succeeds.
+	generation := c.manifestGen.Load() + 1
+	c.mapper.SetGeneration(generation)
+
+	pipeline := loader.NewPipeline(c.world, c.mapper, pipelineConfig)
+	stats, err := pipeline.Load(ctx, filename)
+
+	// Complete the progress bar
+	progressComplete()
+
+	if err != nil {
+		// A cancelled load still returns partial stats: entity creation stops
+		// at a batch boundary, so report what made it into the world.
+		if stats != nil && stats.EntitiesCreated > 0 {
+			c.logger.Warnf("Load aborted after %d of %d monitors: %v",
+				stats.EntitiesCreated, stats.TotalMonitors, err)
+		}
+		return fmt.Errorf("failed to load monitors: %w", err)
+	}
+	c.manifestGen.Store(generation)
+	c.manifestPath = filename
+	c.logger.Infof("Successfully loaded %d monitors in %v (%.0f monitors/sec)",
+		stats.EntitiesCreated, stats.LoadingTime, stats.CreationRate)
+	// %v prints maps key-sorted, so the breakdown line is stable across runs.
+	c.logger.Infof("Load breakdown: types=%v codes=%v interventions=%d warnings=%d dropped=%d",
+		stats.PulseTypes, stats.CodeColors, stats.WithInterventions, len(stats.Warnings), stats.WarningsDropped)
+	for _, w := range stats.Warnings {
+		c.logger.Warnf("Load warning for monitor %q (%s line %d): %s", w.Monitor, w.File, w.Line, w.Message)
+	}
+
+	if c.sharedProbes != nil {
+		probeStats := c.sharedProbes.Rebuild(c.world)
+		if probeStats.Groups > 0 {
+			c.logger.Infof("Shared-probe dedup: %d groups cover %d follower monitors",
+				probeStats.Groups, probeStats.Followers)
+		}
+	}
+
+	// Shrink the world incrementally to reclaim over-allocated memory.
+	// We use a small time budget per pass to allow context cancellation.
+	shrinkPasses := 0
+	for c.world.Shrink(shrinkBudget) {
+		shrinkPasses++
+		if ctx.Err() != nil {
+			return fmt.Errorf("loading cancelled during memory shrink: %w", ctx.Err())
+		}
+	}
+	c.logger.Infof("Shrunk world memory in %d passes", shrinkPasses+1)
+
+	// Explicitly trigger GC and release memory to OS to clear fragmentation from loading/shrinking
+	runtime.GC()
+	debug.FreeOSMemory()
+
+	// Log archetype stats for reflect.New analysis
+	worldStats := c.world.Stats()
+	c.logger.Infof("ECS Archetypes: %d (more archetypes = more reflect.New)", len(worldStats.Archetypes))
+	for i, arch := range worldStats.Archetypes {
+		c.logger.Infof("  Archetype[%d]: entities=%d components=%v", i, arch.Size, arch.ComponentTypeNames)
+	}
+
+	// Pre-calculate worker sizing from initial configuration/world (Pulse only)
+	c.precomputeSizingFromConfig()
+
+	c.applyAdaptiveTPS()
+	c.applyAdaptiveQueue()
+	return nil
+}
+
+// minPulseInterval returns the shortest interval among enabled pulse
+// monitors, or zero when none are loaded.
+func (c *Controller) minPulseInterval() time.Duration {
+	var min time.Duration
+	filter := ecs.NewFilter1[components.PulseConfig](c.world).Without(ecs.C[components.Disabled]())
+	query := filter.Query()
+	for query.Next() {
+		if iv := query.Get().Interval; iv > 0 && (min == 0 || iv < min) {
+			min = iv
+		}
+	}
+	return min
+}
+
+// applyAdaptiveTPS recomputes the tick rate from the loaded fleet and
+// applies it when AdaptiveTPS is on. Before Start the app field is written
+// directly; afterwards the write is submitted to the command system so it
+// happens on the tick goroutine that reads it.
+func (c *Controller) applyAdaptiveTPS() {
+	if !c.config.AdaptiveTPS {
+		return
+	}
+	minInterval := c.minPulseInterval()
+	target := adaptiveTPS(minInterval, c.config.TPS)
+	if target == c.currentTPS {
+		return
+	}
+	previous := c.currentTPS
+	c.currentTPS = target
+	if c.running.Load() {
+		if err := c.commands.Submit(func(_ *ecs.World) { c.app.TPS = target }); err != nil {
+			c.logger.Warnf("Adaptive TPS change not applied: %v", err)
+			c.currentTPS = previous
+			return
+		}
+	} else {
+		c.app.TPS = target
+	}
+	c.logger.Infof("Adaptive TPS: %.2f -> %.2f ticks/s (shortest pulse interval %v)",
+		previous, target, minInterval)
+}
+
+// applyAdaptiveQueue swaps the main pulse queue to the lock-free
+// AdaptiveQueue once the loaded fleet reaches the configured entity
+// threshold. The swap only happens before Start: the dispatch system reads
+// its queue without synchronization once the scheduler is ticking, so a
+// reload that crosses the threshold mid-run logs and keeps the current
+// queue. An explicit PulseQueue.Implementation wins over the threshold.
+func (c *Controller) applyAdaptiveQueue() {
+	threshold := c.config.AdaptiveQueueEntityThreshold
+	if threshold <= 0 || c.config.PulseQueue.Implementation != "" {
+		return
+	}
+	if _, ok := c.pulseQueue.(*queue.AdaptiveQueue); ok {
+		return
+	}
+	mq := c.monitorCountFilter.Query()
+	count := mq.Count()
+	mq.Close()
+	if count < threshold {
+		return
+	}
+	if c.started.Load() != 0 {
+		c.logger.Warnf("Adaptive queue threshold reached (%d >= %d monitors) after Start; keeping the hybrid pulse queue", count, threshold)
+		return
+	}
+	capacity := c.config.PulseQueue.Capacity
+	if capacity == 0 {
+		capacity = c.config.QueueCapacity
+	}
+	adaptive, err := queue.NewAdaptiveQueue(capacity)
+	if err != nil {
+		c.logger.Warnf("Adaptive queue switch failed: %v", err)
+		return
+	}
+	c.pulsePool.ReplaceQueue(adaptive)
+	c.pulseQueue = adaptive
+	c.pulseSystem.SetQueue(adaptive)
+	if c.invariants != nil {
+		c.invariants.AttachQueue("pulse", adaptive)
+	}
+	c.logger.Infof("Adaptive queue: pulse queue switched to AdaptiveQueue at %d monitors (threshold %d)", count, threshold)
+}
+
+// precomputeSizingFromConfig computes a recommended worker count from initial world contents
+// and configured (or env) service time and latency SLO. It currently targets the Pulse pool only.
+func (c *Controller) precomputeSizingFromConfig() {
+	// Determine τ (service time) and W_slo from env or config; fallback to sane defaults
+	tau := c.config.SizingServiceTime
+	if v := os.Getenv("CPRA_SIZING_TAU_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			tau = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if tau <= 0 {
+		tau = defaultServiceTime
+	}
+	wSLO := c.config.SizingSLO
+	if v := os.Getenv("CPRA_SIZING_SLO_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			wSLO = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if wSLO <= 0 {
+		wSLO = defaultSLO
+	}
+
+	// Compute λ for Pulse from world: sum over active monitors of 1/Interval
+	lambda := computePulseLambda(c.world)
+	if lambda <= 0 {
+		c.logger.Warnf("[Pre-Sizing] No active pulse workload detected; skipping sizing")
+		return
+	}
+
+	cMin, w, err := queue.FindCForSLO(lambda, tau.Seconds(), wSLO.Seconds(), 0, 0, 0)
+	if err != nil {
+		c.logger.Warnf("[Pre-Sizing] Could not compute Pulse workers: %v", err)
+		return
+	}
+	// Determine safe headroom: env CPRA_SIZING_HEADROOM_PCT (e.g., 0.15 or 15), or config, default 0.15
+	headroom := c.config.SizingHeadroomPct
+	if v := os.Getenv("CPRA_SIZING_HEADROOM_PCT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+			// Accept both 0.xx and percentage like 15 or 15.0
+			if f > 1.0 {
+				headroom = f / 100.0
+			} else {
+				headroom = f
+			}
+		}
+	}
+	if headroom <= 0 {
+		headroom = defaultHeadroom
+	} // default 15%%
+	// Compute a safe recommended c with headroom
+	cSafe := int(math.Ceil(float64(cMin) * (1.0 + headroom)))
+	if cSafe <= cMin {
+		cSafe = cMin + 1
+	}
+	// Predict W for cSafe (informational)
+	mu := 1.0 / tau.Seconds()
+	_, wSafe, errSafe := queue.MmcWait(lambda, mu, cSafe, 0, 0)
+	if errSafe != nil {
+		wSafe = w
+	} // fallback
+	c.logger.Infof("[Pre-Sizing] Pulse: λ=%.2f/s τ=%.3fs W_slo=%.3fs => c_min=%d (W≈%.3fs), recommended c_safe=%d (+%.0f%%) (predicted W≈%.3fs)",
+		lambda, tau.Seconds(), wSLO.Seconds(), cMin, w, cSafe, headroom*100.0, wSafe)
+
+	// Ramp duration: env CPRA_SIZING_RAMP_MS, or config, default 30s
+	ramp := c.config.SizingRamp
+	if v := os.Getenv("CPRA_SIZING_RAMP_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			ramp = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if ramp <= 0 {
+		ramp = defaultSizingRamp
+	}
+
+	// APPLY the calculated sizing to worker pools (not just log!)
+	// Only tune if calculated size exceeds current minimum
+	if cSafe > c.config.PulseWorkerConfig.MinWorkers {
+		// The pulse pool climbs in steps rather than jumping straight to
+		// c_safe, so startup does not release every worker at once.
+		c.pulsePool.TuneRamp(cSafe, ramp)
+		c.logger.Infof("[Pre-Sizing] Ramping Pulse pool to c_safe=%d over %v", cSafe, ramp)
+
+		// Scale Intervention and Code pools proportionally (typically lower volume)
+		// Use ratio of pulse pool as baseline - these handle triggered actions
+		interventionSize := int(math.Ceil(float64(cSafe) * interventionPoolRatio))
+		if interventionSize < c.config.InterventionWorkerConfig.MinWorkers {
+			interventionSize = c.config.InterventionWorkerConfig.MinWorkers
+		}
+		c.interventionPool.Tune(interventionSize)
+		c.logger.Infof("[Pre-Sizing] Applied c_safe=%d to Intervention pool (25%% of pulse)", interventionSize)
+
+		// Code evaluations are even less frequent
+		codeSize := int(math.Ceil(float64(cSafe) * codePoolRatio))
+		if codeSize < c.config.CodeWorkerConfig.MinWorkers {
+			codeSize = c.config.CodeWorkerConfig.MinWorkers
+		}
+		c.codePool.Tune(codeSize)
+		c.logger.Infof("[Pre-Sizing] Applied c_safe=%d to Code pool (12.5%% of pulse)", codeSize)
+	}
+}
+
+// computePulseLambda estimates arrival rate (jobs/sec) from Pulse intervals of enabled monitors.
+func computePulseLambda(world *ecs.World) float64 {
+	f := ecs.NewFilter2[components.MonitorState, components.PulseConfig](world).
+		Without(ecs.C[components.Disabled]())
+	q := f.Query()
+	sum := 0.0
+	for q.Next() {
+		_, cfg := q.Get()
+		if cfg == nil || cfg.Interval <= 0 {
+			continue
+		}
+		sum += 1.0 / cfg.Interval.Seconds()
+	}
+	return sum
+}
+
+// Start begins the main processing loop of the controller.
+//
+// Start initializes all worker pools and begins the ark-tools app execution loop.
+// The controller runs at 10 TPS (ticks per second) for updates, combined with shard
+// scheduling to achieve sub-second precision for monitor intervals.
+//
+// This method is idempotent - calling Start() multiple times returns an error
+// if the controller is already running.
+//
+// Returns an error if the controller is already running or if startup fails.
+func (c *Controller) Start(ctx context.Context) error {
+	if !c.running.CompareAndSwap(false, true) {
+		return fmt.Errorf("controller already running")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.runDone = make(chan struct{})
+	c.started.Store(time.Now().UnixNano())
+
+	// Set context on termination system - it will signal termination from within the ECS loop
+	c.terminationSys.SetContext(c.ctx)
+
+	// Intervention and code pools get a context that outlives the run
+	// context: phase two of Stop lets them finish in-flight jobs after
+	// pulse scheduling has already been cancelled.
+	drainCtx, drainCancel := context.WithCancel(context.Background())
+	c.drainCancel = drainCancel
+
+	c.pulsePool.SetContext(c.ctx)
+	c.interventionPool.SetContext(drainCtx)
+	c.codePool.SetContext(drainCtx)
+	c.hfPool.SetContext(c.ctx)
+	c.pulsePool.Start()
+	c.interventionPool.Start()
+	c.codePool.Start()
+	c.hfPool.Start()
+	if c.config.HA.Elector != nil {
+		// Start as standby: pools pause and dispatch stays gated until the
+		// leadership loop wins its first campaign.
+		c.pulsePool.Pause()
+		c.interventionPool.Pause()
+		c.codePool.Pause()
+		c.hfPool.Pause()
+		go c.runLeadershipLoop(c.ctx)
+		c.logger.Infof("HA mode: campaigning for leadership every %v", c.haLeaseInterval())
+	}
+	go func() {
+		defer close(c.runDone)
+		c.app.Run()
+	}()
+	if c.faults != nil && c.config.Faults.PoolDisruptInterval > 0 {
+		go c.runPoolDisrupter(c.ctx)
+	}
+	if c.config.WatchManifest && c.manifestPath != "" {
+		go c.watchManifest(c.ctx, c.manifestPath)
+		c.logger.Infof("Watching manifest %s for changes", c.manifestPath)
+	}
+	if c.config.StatsReportInterval > 0 {
+		go c.runStatsReporter(c.ctx)
+	}
+	c.logger.Infof("Controller started successfully")
+	return nil
+}
+
+// runPoolDisrupter periodically shrinks the pulse worker pool and restores it,
+// injecting a worker outage for soak testing. Queue replacement and hard pool
+// kills are not injectable with the current pool API, so capacity churn is the
+// closest available disruption.
+func (c *Controller) runPoolDisrupter(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Faults.PoolDisruptInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			restore := c.pulsePool.Stats().CurrentCapacity
+			c.faults.RecordPoolDisrupt()
+			c.logger.Warnf("Fault: shrinking pulse pool from %d to 1 worker for %v", restore, c.config.Faults.PoolDisruptFor)
+			c.pulsePool.Tune(1)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.config.Faults.PoolDisruptFor):
+			}
+			c.pulsePool.Tune(restore)
+			c.logger.Infof("Fault: restored pulse pool to %d workers", restore)
+		}
+	}
+}
+
+// HighFrequencyAccuracy returns the latest scheduling-accuracy snapshot for
+// high-frequency monitors. All-zero when no monitor opted into high_frequency.
+func (c *Controller) HighFrequencyAccuracy() systems.AccuracyStats {
+	return c.hfSystem.Accuracy()
+}
+
+// ManifestGeneration returns the generation of the last successfully loaded
+// manifest. It starts at zero and advances once per successful load/reload.
+func (c *Controller) ManifestGeneration() uint32 {
+	return c.manifestGen.Load()
+}
+
+// FaultCounts returns injected fault counts per kind, or nil when fault
+// injection is disabled.
+func (c *Controller) FaultCounts() map[string]uint64 {
+	if c.faults == nil {
+		return nil
+	}
+	return c.faults.Counts()
+}
+
+// InvariantViolations returns invariant violations recorded during a soak,
+// or nil when soak mode is disabled.
+func (c *Controller) InvariantViolations() []string {
+	if c.invariants == nil {
+		return nil
+	}
+	return c.invariants.Violations()
+}
+
+// DrainReport describes the outcome of the shutdown drain: whether the
+// intervention and code pipelines emptied before the deadline, how long the
+// wait took, and how many jobs were abandoned per queue when they did not.
+type DrainReport struct {
+	Completed bool           `json:"completed"`
+	Elapsed   time.Duration  `json:"elapsed"`
+	Abandoned map[string]int `json:"abandoned,omitempty"`
+}
+
+// Stop gracefully shuts down the controller with the default drain deadline.
+// See StopContext.
+func (c *Controller) Stop() {
+	c.StopContext(context.Background())
+}
+
+// StopContext gracefully shuts down the controller in two phases.
+//
+// Phase one stops scheduling: the run context is cancelled, the ECS app
+// exits (no new jobs are enqueued), and the pulse pools are drained — a
+// dropped pulse is re-checked on the next start, so pulses get only the
+// pools' short grace window.
+//
+// Phase two protects the jobs that must not be cut off: in-flight
+// interventions and code alerts. It waits for the intervention and code
+// queues to empty and their pools to finish all submitted work, up to
+// defaultDrainTimeout or the context's earlier deadline, before stopping
+// the pools and closing the queues.
+//
+// The returned DrainReport says whether the drain completed and what was
+// abandoned; it is also folded into the shutdown metrics.
+//
+// This method is idempotent - calling it multiple times is safe. After it
+// completes, the controller cannot be restarted; create a new controller
+// instance if needed.
+func (c *Controller) StopContext(ctx context.Context) DrainReport {
+	if !c.running.Swap(false) {
+		return c.drainReport
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c.logger.Infof("Stopping controller...")
+
+	// Phase 1a: Cancel the run context (signals pulse pools and TerminationSystem)
+	// The TerminationSystem will set Termination.Terminate from within the ECS loop,
+	// avoiding a data race with the app's read of that flag.
+	if c.cancel != nil {
+		c.cancel()
+	}
+
+	// Phase 1b: Wait for the app.Run() goroutine to exit
+	// This ensures no concurrent access to ECS resources after this point
+	runFinalized := false
+	if done := c.runDone; done != nil {
+		select {
+		case <-done:
+			runFinalized = true
+			c.logger.Infof("  [1/5] ECS app exited cleanly")
+		case <-time.After(shutdownTimeout):
+			c.logger.Warnf("  [1/5] ECS app did not exit within timeout, forcing finalize")
+		}
+	}
+
+	// Finalize ECS systems if app didn't exit cleanly
+	// Now safe to call - app goroutine is either done or we timed out
+	if !runFinalized {
+		c.app.Finalize()
+	}
+
+	// Phase 1c: Drain the pulse pools. Their contexts descend from the run
+	// context, so workers are already winding down; DrainAndStop's grace
+	// window covers results still in flight.
+	c.logger.Infof("  [2/5] Draining pulse pools...")
+	c.pulsePool.DrainAndStop()
+	c.hfPool.DrainAndStop()
+
+	// Phase 2: The ECS loop is gone, so nothing enqueues new interventions
+	// or alerts; wait for what is already in the pipeline to finish.
+	c.logger.Infof("  [3/5] Draining intervention and code pipelines...")
+	report := c.waitForProtectedDrain(ctx)
+	if report.Completed {
+		c.logger.Infof("    - Drain completed in %v", report.Elapsed.Round(time.Millisecond))
+	} else {
+		c.logger.Warnf("    - Drain deadline exceeded after %v; abandoning jobs (intervention=%d, code=%d)",
+			report.Elapsed.Round(time.Millisecond), report.Abandoned["intervention"], report.Abandoned["code"])
+	}
+	c.interventionPool.DrainAndStop()
+	c.codePool.DrainAndStop()
+	if c.drainCancel != nil {
+		c.drainCancel()
+	}
+
+	// Interventions are done; release the shared Docker clients and stop
+	// their daemon health probes.
+	jobs.CloseDockerClients()
+
+	// Pools are drained, so no more results will be routed; detach and
+	// close all external result subscribers and event consumers.
+	c.subs.closeAll()
+	c.eventBus.Close()
+
+	// Result sinks see nothing more once the pools are drained; flush and
+	// close them, reporting drops the way the audit log does below.
+	for _, sink := range c.config.ResultSinks {
+		if dropped := sink.Dropped(); dropped > 0 {
+			c.logger.Warnf("Result sink dropped %d results on a full queue", dropped)
+		}
+		if err := sink.Close(); err != nil {
+			c.logger.Warnf("Failed to close result sink: %v", err)
+		}
+	}
+
+	// Log pending jobs that will be dropped on close. Pulse jobs are cheap
+	// to abandon (the monitor is re-checked on restart) but still reported.
+	pulseStats := c.pulseQueue.Stats()
+	hfStats := c.hfQueue.Stats()
+	if pulseStats.QueueDepth > 0 {
+		report.Abandoned["pulse"] = pulseStats.QueueDepth
+	}
+	if hfStats.QueueDepth > 0 {
+		report.Abandoned["pulse-hf"] = hfStats.QueueDepth
+	}
+	totalAbandoned := 0
+	for _, n := range report.Abandoned {
+		totalAbandoned += n
+	}
+	if totalAbandoned > 0 {
+		c.logger.Warnf("Shutdown: dropping %d pending jobs (pulse=%d, intervention=%d, code=%d, pulse-hf=%d)",
+			totalAbandoned, report.Abandoned["pulse"], report.Abandoned["intervention"],
+			report.Abandoned["code"], report.Abandoned["pulse-hf"])
+	}
+	c.drainReport = report
+
+	// Close queues (no more enqueue/dequeue operations)
+	c.logger.Infof("  [4/5] Closing queues...")
+	c.pulseQueue.Close()
+	c.interventionQueue.Close()
+	c.codeQueue.Close()
+	c.hfQueue.Close()
+
+	// The result systems are gone, so no more audit events arrive; flush
+	// whatever the sink buffered and close the database.
+	if c.auditLog != nil {
+		if dropped := c.auditLog.Dropped(); dropped > 0 {
+			c.logger.Warnf("Audit log dropped %d events on a full buffer", dropped)
+		}
+		if err := c.auditLog.Close(); err != nil {
+			c.logger.Warnf("Failed to close audit log: %v", err)
+		}
+	}
+
+	// Flush any buffered spans before the process winds down; bounded so an
+	// unreachable collector cannot stall shutdown.
+	if c.traceProvider != nil {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := c.traceProvider.Shutdown(flushCtx); err != nil {
+			c.logger.Warnf("Trace exporter shutdown: %v", err)
+		}
+		flushCancel()
+	}
+
+	// Package-level job housekeeping: no more ICMP checks will execute, so
+	// the per-host stats janitor can go too.
+	jobs.StopICMPStatsJanitor()
+
+	// Print final metrics (after everything is stopped for accurate stats)
+	c.logger.Infof("  [5/5] Collecting final metrics...")
+	c.PrintShutdownMetrics()
+
+	c.logger.Infof("Controller stopped successfully")
+	return report
+}
+
+// waitForProtectedDrain polls until the intervention and code queues are
+// empty and their pools report every submitted task completed, or until the
+// drain deadline passes. The deadline is defaultDrainTimeout, tightened by
+// the context's own deadline when that is earlier.
+func (c *Controller) waitForProtectedDrain(ctx context.Context) DrainReport {
+	start := time.Now()
+	deadline := start.Add(defaultDrainTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	drained := func() bool {
+		if c.interventionQueue.Stats().QueueDepth > 0 || c.codeQueue.Stats().QueueDepth > 0 {
+			return false
+		}
+		intStats := c.interventionPool.Stats()
+		codeStats := c.codePool.Stats()
+		return intStats.TasksSubmitted == intStats.TasksCompleted &&
+			codeStats.TasksSubmitted == codeStats.TasksCompleted
+	}
+
+	completed := drained()
+	for !completed && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(25 * time.Millisecond):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		completed = drained()
+	}
+
+	report := DrainReport{
+		Completed: completed,
+		Elapsed:   time.Since(start),
+		Abandoned: map[string]int{},
+	}
+	if !completed {
+		intPool := c.interventionPool.Stats()
+		codePool := c.codePool.Stats()
+		report.Abandoned["intervention"] = c.interventionQueue.Stats().QueueDepth +
+			int(intPool.TasksSubmitted-intPool.TasksCompleted)
+		report.Abandoned["code"] = c.codeQueue.Stats().QueueDepth +
+			int(codePool.TasksSubmitted-codePool.TasksCompleted)
+	}
+	return report
+}
+
+// PrintShutdownMetrics logs queue, worker pool, and world statistics at shutdown.
+func (c *Controller) PrintShutdownMetrics() {
+	// Helpers for friendly placeholders on unset values
+	formatTS := func(t time.Time) string {
+		if t.IsZero() || t.Unix() == 0 {
+			return "never"
+		}
+		return t.Format(time.RFC3339)
+	}
+	formatDur := func(d time.Duration) string {
+		if d <= 0 {
+			return "N/A"
+		}
+		return d.String()
+	}
+
+	logQueue := func(label string, stats queue.Stats) {
+		c.logger.Infof("%s Queue: depth=%d/%d enqueued=%d dequeued=%d dropped=%d", label, stats.QueueDepth, stats.Capacity, stats.Enqueued, stats.Dequeued, stats.Dropped)
+		c.logger.Infof("%s Queue timings: avg_wait=%s max_wait=%s window=%s", label, formatDur(stats.AvgQueueTime), formatDur(stats.MaxQueueTime), formatDur(stats.SampleWindow))
+		c.logger.Infof("%s Queue wait percentiles: p50=%s p90=%s p99=%s p999=%s", label, formatDur(stats.P50QueueTime), formatDur(stats.P90QueueTime), formatDur(stats.P99QueueTime), formatDur(stats.P999QueueTime))
+		c.logger.Infof("%s Queue rates: arrival=%.2f/s service=%.2f/s last_enqueue=%s last_dequeue=%s", label, stats.EnqueueRate, stats.DequeueRate, formatTS(stats.LastEnqueue), formatTS(stats.LastDequeue))
+	}
+	logWorkers := func(label string, stats queue.WorkerPoolStats) {
+		c.logger.Infof("%s Workers: running=%d capacity=%d target=%d min=%d max=%d waiting=%d", label, stats.RunningWorkers, stats.CurrentCapacity, stats.TargetWorkers, stats.MinWorkers, stats.MaxWorkers, stats.WaitingTasks)
+		c.logger.Infof("%s Tasks: submitted=%d completed=%d pending_results=%d scaling_events=%d last_scale=%s", label, stats.TasksSubmitted, stats.TasksCompleted, stats.PendingResults, stats.ScalingEvents, formatTS(stats.LastScaleTime))
+	}
+
+	c.logger.Infof("=== SHUTDOWN METRICS ===")
+
+	pulseQ := c.pulseQueue.Stats()
+	intQ := c.interventionQueue.Stats()
+	codeQ := c.codeQueue.Stats()
+	logQueue("Pulse", pulseQ)
+	logQueue("Intervention", intQ)
+	logQueue("Code", codeQ)
+	if hfQ := c.hfQueue.Stats(); hfQ.Enqueued > 0 {
+		logQueue("PulseHF", hfQ)
+		acc := c.hfSystem.Accuracy()
+		c.logger.Infof("PulseHF accuracy: monitors=%d samples=%d p50=%dms p95=%dms p99=%dms max=%dms",
+			acc.Monitors, acc.Samples, acc.P50Ms, acc.P95Ms, acc.P99Ms, acc.MaxMs)
+	}
+
+	pulseWP := c.pulsePool.Stats()
+	intWP := c.interventionPool.Stats()
+	codeWP := c.codePool.Stats()
+	logWorkers("Pulse", pulseWP)
+	logWorkers("Intervention", intWP)
+	logWorkers("Code", codeWP)
+
+	worldStats := c.world.Stats()
+	c.logger.Infof("World: entities_used=%d recycled=%d total=%d archetypes=%d components=%d filters=%d locked=%t",
+		worldStats.Entities.Used, worldStats.Entities.Recycled, worldStats.Entities.Total,
+		len(worldStats.Archetypes), len(worldStats.ComponentTypes), worldStats.CachedFilters, worldStats.Locked)
+	c.logger.Infof("World memory: reserved=%dB used=%dB", worldStats.Memory, worldStats.MemoryUsed)
+
+	if c.drainReport.Elapsed > 0 {
+		if c.drainReport.Completed {
+			c.logger.Infof("Drain: completed in %v", c.drainReport.Elapsed.Round(time.Millisecond))
+		} else {
+			c.logger.Warnf("Drain: timed out after %v, abandoned=%v",
+				c.drainReport.Elapsed.Round(time.Millisecond), c.drainReport.Abandoned)
+		}
+	}
+
+	if c.faults != nil {
+		c.logger.Infof("Faults injected (seed=%d): %v", c.faults.Seed(), c.faults.Counts())
+	}
+
+	dialStats := jobs.GetDialMetrics().Stats()
+	c.logger.Infof("Dial: new_conns=%d reused_conns=%d open_est=%d fd=%d/%d exhaustion_active=%t errors_by_class=%v",
+		dialStats.NewConns, dialStats.ReusedConns, dialStats.OpenConns,
+		dialStats.FDCount, dialStats.FDLimit, dialStats.ExhaustionActive, dialStats.ErrorsByClass)
+	c.logger.Infof("=========================")
+}
+
+// World returns the ECS world for external access (e.g., testing, debugging).
+//
+// World provides direct access to the underlying ECS world. This is useful for:
+//   - Testing: Inspecting entities and components in tests
+//   - Debugging: Querying entity state during development
+//   - Metrics: Accessing world statistics
+//
+// The returned world should not be modified directly while the controller is running,
+// as this may cause race conditions with the ECS systems.
+func (c *Controller) World() *ecs.World {
+	return c.world
+}
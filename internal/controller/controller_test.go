@@ -0,0 +1,735 @@
+package controller
+
+import (
+	"context"
+	"cpra/internal/jobs"
+	"cpra/internal/queue"
+
+	"github.com/mlange-42/ark/ecs"
+	"testing"
+	"time"
+)
+
+// TestDefaultConfig tests that DefaultConfig returns correct defaults
+func TestDefaultConfig(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+
+	if cfg.QueueCapacity != 8192 {
+		t.Errorf("QueueCapacity = %d, want 8192", cfg.QueueCapacity)
+	}
+	if cfg.BatchSize != 1000 {
+		t.Errorf("BatchSize = %d, want 1000", cfg.BatchSize)
+	}
+	if cfg.ShardTargetSweep != 10*time.Second {
+		t.Errorf("ShardTargetSweep = %v, want 10s", cfg.ShardTargetSweep)
+	}
+	// WorkerConfig should have sensible defaults
+	if cfg.PulseWorkerConfig.MinWorkers < 1 {
+		t.Error("WorkerConfig.MinWorkers should be at least 1")
+	}
+}
+
+// TestNewController tests basic controller creation
+func TestNewController(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	if ctrl == nil {
+		t.Fatal("NewController returned nil controller")
+	}
+
+	// Check world is initialized
+	if ctrl.World() == nil {
+		t.Error("World() returned nil")
+	}
+
+	// Check queues are initialized
+	if ctrl.pulseQueue == nil {
+		t.Error("pulseQueue is nil")
+	}
+	if ctrl.interventionQueue == nil {
+		t.Error("interventionQueue is nil")
+	}
+	if ctrl.codeQueue == nil {
+		t.Error("codeQueue is nil")
+	}
+
+	// Check worker pools are initialized
+	if ctrl.pulsePool == nil {
+		t.Error("pulsePool is nil")
+	}
+	if ctrl.interventionPool == nil {
+		t.Error("interventionPool is nil")
+	}
+	if ctrl.codePool == nil {
+		t.Error("codePool is nil")
+	}
+}
+
+// TestNewController_PerPoolWorkerConfigs verifies each pool is built from
+// its own worker config, not a shared one.
+func TestNewController_PerPoolWorkerConfigs(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.PulseWorkerConfig.MinWorkers = 11
+	cfg.PulseWorkerConfig.MaxWorkers = 110
+	cfg.InterventionWorkerConfig.MinWorkers = 2
+	cfg.InterventionWorkerConfig.MaxWorkers = 8
+	cfg.CodeWorkerConfig.MinWorkers = 3
+	cfg.CodeWorkerConfig.MaxWorkers = 30
+
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	stats := ctrl.Stats()
+	if stats.PulseWorkers.MinWorkers != 11 || stats.PulseWorkers.MaxWorkers != 110 {
+		t.Errorf("Pulse pool = %d/%d, want 11/110",
+			stats.PulseWorkers.MinWorkers, stats.PulseWorkers.MaxWorkers)
+	}
+	if stats.InterventionWorkers.MinWorkers != 2 || stats.InterventionWorkers.MaxWorkers != 8 {
+		t.Errorf("Intervention pool = %d/%d, want 2/8",
+			stats.InterventionWorkers.MinWorkers, stats.InterventionWorkers.MaxWorkers)
+	}
+	if stats.CodeWorkers.MinWorkers != 3 || stats.CodeWorkers.MaxWorkers != 30 {
+		t.Errorf("Code pool = %d/%d, want 3/30",
+			stats.CodeWorkers.MinWorkers, stats.CodeWorkers.MaxWorkers)
+	}
+}
+
+// TestController_Stats tests that Stats returns valid data
+func TestController_Stats(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	stats := ctrl.Stats()
+
+	// Stats should have valid values
+	if stats.World == nil {
+		t.Error("Stats.World is nil")
+	}
+}
+
+// TestCalculateShardSlots tests shard slot calculation
+func TestCalculateShardSlots(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		tps         float64
+		targetSweep time.Duration
+		override    int
+		wantMin     int
+		wantMax     int
+	}{
+		{
+			name:        "override value",
+			tps:         10,
+			targetSweep: 10 * time.Second,
+			override:    50,
+			wantMin:     50,
+			wantMax:     50,
+		},
+		{
+			name:        "calculated from TPS",
+			tps:         10,
+			targetSweep: 10 * time.Second,
+			override:    0,
+			wantMin:     100,
+			wantMax:     100,
+		},
+		{
+			name:        "default sweep if zero",
+			tps:         10,
+			targetSweep: 0,
+			override:    0,
+			wantMin:     100,
+			wantMax:     100,
+		},
+		{
+			name:        "minimum of 1",
+			tps:         0.01,
+			targetSweep: 100 * time.Millisecond,
+			override:    0,
+			wantMin:     1,
+			wantMax:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateShardSlots(tt.tps, tt.targetSweep, tt.override)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("calculateShardSlots() = %d, want between %d and %d", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+// TestAdaptiveTPS tests the adaptive tick-rate computation. As an idle-CPU
+// reference: the default 10 TPS executes 600 system sweeps a minute whether
+// or not anything is due, while a fleet of 5-minute checks under adaptive
+// mode drops to the 1 TPS floor — 60 sweeps a minute.
+func TestAdaptiveTPS(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		minInterval time.Duration
+		ceiling     float64
+		want        float64
+	}{
+		{name: "1s intervals saturate the default ceiling", minInterval: time.Second, ceiling: 10, want: 10},
+		{name: "2s intervals halve the rate", minInterval: 2 * time.Second, ceiling: 10, want: 5},
+		{name: "30s intervals hit the 1 TPS floor", minInterval: 30 * time.Second, ceiling: 10, want: 1},
+		{name: "5m edge fleet idles at the floor", minInterval: 5 * time.Minute, ceiling: 100, want: 1},
+		{name: "sub-second intervals clamp to the ceiling", minInterval: 250 * time.Millisecond, ceiling: 10, want: 10},
+		{name: "raised ceiling admits higher rates", minInterval: 250 * time.Millisecond, ceiling: 100, want: 40},
+		{name: "empty world keeps the ceiling", minInterval: 0, ceiling: 10, want: 10},
+		{name: "zero ceiling falls back to the default", minInterval: 0, ceiling: 0, want: defaultTPS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := adaptiveTPS(tt.minInterval, tt.ceiling); got != tt.want {
+				t.Errorf("adaptiveTPS(%v, %v) = %v, want %v", tt.minInterval, tt.ceiling, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestController_StartStop tests controller lifecycle
+func TestController_StartStop(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	// Should not be running initially
+	if ctrl.running.Load() {
+		t.Error("Controller should not be running initially")
+	}
+
+	// Stop should be safe to call before start (idempotent)
+	ctrl.Stop()
+
+	// After stop (even if never started), should remain stopped
+	if ctrl.running.Load() {
+		t.Error("Controller should not be running after Stop()")
+	}
+}
+
+// TestController_DoubleStart tests that double-start returns error
+func TestController_DoubleStart(t *testing.T) {
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	defer ctrl.Stop()
+
+	// First start should succeed
+	if err := ctrl.Start(nil); err != nil {
+		t.Fatalf("First Start failed: %v", err)
+	}
+
+	// Second start should fail
+	if err := ctrl.Start(nil); err == nil {
+		t.Error("Expected error on double-start, got nil")
+	}
+}
+
+// TestConfig_SizingDefaults tests that sizing defaults are correct
+func TestConfig_SizingDefaults(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+
+	// Default sizing values should be zero (will use env or package defaults)
+	if cfg.SizingServiceTime != 0 {
+		t.Errorf("SizingServiceTime = %v, want 0 (uses default)", cfg.SizingServiceTime)
+	}
+	if cfg.SizingSLO != 0 {
+		t.Errorf("SizingSLO = %v, want 0 (uses default)", cfg.SizingSLO)
+	}
+	if cfg.SizingHeadroomPct != 0 {
+		t.Errorf("SizingHeadroomPct = %f, want 0 (uses default)", cfg.SizingHeadroomPct)
+	}
+}
+
+// TestController_World tests World() accessor
+func TestController_World(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	world := ctrl.World()
+	if world == nil {
+		t.Fatal("World() returned nil")
+	}
+
+	// World should have valid stats
+	stats := world.Stats()
+	if stats == nil {
+		t.Error("World.Stats() returned nil")
+	}
+}
+
+// TestController_LoadMonitors tests loading monitors from YAML file
+func TestController_LoadMonitors(t *testing.T) {
+	// Note: Not using t.Parallel() to avoid race with controller internal state
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	ctx := context.Background()
+	err = ctrl.LoadMonitors(ctx, "testdata/test_monitors.yaml")
+	if err != nil {
+		t.Fatalf("LoadMonitors failed: %v", err)
+	}
+
+	// Check that entities were created
+	worldStats := ctrl.World().Stats()
+	if worldStats.Entities.Used < 2 {
+		t.Errorf("Expected at least 2 entities (monitors), got %d", worldStats.Entities.Used)
+	}
+}
+
+// TestController_LoadMonitors_NonExistent tests loading from non-existent file
+func TestController_LoadMonitors_NonExistent(t *testing.T) {
+	// Note: Not using t.Parallel() to avoid race with controller internal state
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	ctx := context.Background()
+	err = ctrl.LoadMonitors(ctx, "testdata/nonexistent.yaml")
+	if err == nil {
+		t.Error("Expected error for non-existent file, got nil")
+	}
+}
+
+// TestController_Stats_QueueStats tests queue statistics
+func TestController_Stats_QueueStats(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	stats := ctrl.Stats()
+
+	// All queues should have non-negative capacity
+	if stats.PulseQueue.Capacity <= 0 {
+		t.Error("PulseQueue.Capacity should be positive")
+	}
+	if stats.InterventionQueue.Capacity <= 0 {
+		t.Error("InterventionQueue.Capacity should be positive")
+	}
+	if stats.CodeQueue.Capacity <= 0 {
+		t.Error("CodeQueue.Capacity should be positive")
+	}
+}
+
+// TestController_Stats_WorkerStats tests worker pool statistics
+func TestController_Stats_WorkerStats(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	stats := ctrl.Stats()
+
+	// Worker pools should have min workers configured
+	if stats.PulseWorkers.MinWorkers <= 0 {
+		t.Error("PulseWorkers.MinWorkers should be positive")
+	}
+	if stats.InterventionWorkers.MinWorkers <= 0 {
+		t.Error("InterventionWorkers.MinWorkers should be positive")
+	}
+	if stats.CodeWorkers.MinWorkers <= 0 {
+		t.Error("CodeWorkers.MinWorkers should be positive")
+	}
+}
+
+// TestCalculateShardSlots_MaxSlots tests shard slot clamping
+func TestCalculateShardSlots_MaxSlots(t *testing.T) {
+	t.Parallel()
+	// Very high TPS should clamp to maxSlots (20000)
+	got := calculateShardSlots(10000, 10*time.Second, 0)
+	if got > 20000 {
+		t.Errorf("calculateShardSlots() = %d, should be clamped to 20000", got)
+	}
+}
+
+// TestController_StartStop_WithContext tests start with context
+func TestController_StartStop_WithContext(t *testing.T) {
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Start with context
+	if err := ctrl.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// Should be running
+	if !ctrl.running.Load() {
+		t.Error("Controller should be running after Start()")
+	}
+
+	// Give the controller time to fully initialize its goroutines
+	time.Sleep(100 * time.Millisecond)
+
+	// Stop
+	ctrl.Stop()
+
+	// Should not be running after stop
+	if ctrl.running.Load() {
+		t.Error("Controller should not be running after Stop()")
+	}
+}
+
+// TestController_EntityManager tests entity manager access
+func TestController_EntityManager(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	// Entity manager should be accessible
+	em := ctrl.mapper
+	if em == nil {
+		t.Error("EntityManager is nil")
+	}
+}
+
+// TestController_QueueTypes tests queue types are initialized correctly
+func TestController_QueueTypes(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	// All queues should be non-nil
+	if ctrl.pulseQueue == nil {
+		t.Error("pulseQueue is nil")
+	}
+	if ctrl.interventionQueue == nil {
+		t.Error("interventionQueue is nil")
+	}
+	if ctrl.codeQueue == nil {
+		t.Error("codeQueue is nil")
+	}
+}
+
+// TestController_PoolConfigs tests worker pool configurations
+func TestController_PoolConfigs(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	// Check pulse pool is initialized
+	if ctrl.pulsePool == nil {
+		t.Error("PulsePool is nil")
+	}
+	if ctrl.interventionPool == nil {
+		t.Error("InterventionPool is nil")
+	}
+	if ctrl.codePool == nil {
+		t.Error("CodePool is nil")
+	}
+}
+
+// TestDefaultConfig_WorkerConfig tests worker config defaults
+func TestDefaultConfig_WorkerConfig(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+
+	if cfg.PulseWorkerConfig.MinWorkers < 1 {
+		t.Error("WorkerConfig.MinWorkers should be at least 1")
+	}
+	if cfg.PulseWorkerConfig.MaxWorkers < cfg.PulseWorkerConfig.MinWorkers {
+		t.Error("WorkerConfig.MaxWorkers should be >= MinWorkers")
+	}
+	if cfg.PulseWorkerConfig.AdjustmentInterval <= 0 {
+		t.Error("WorkerConfig.AdjustmentInterval should be positive")
+	}
+	if cfg.PulseWorkerConfig.ResultBatchSize <= 0 {
+		t.Error("WorkerConfig.ResultBatchSize should be positive")
+	}
+}
+
+// TestController_StatsFields tests Stats returns all expected fields
+func TestController_StatsFields(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	stats := ctrl.Stats()
+
+	// World should be initialized
+	if stats.World == nil {
+		t.Error("Stats.World is nil")
+	}
+
+	// Queue stats should have valid capacities
+	if stats.PulseQueue.Capacity == 0 {
+		t.Error("Stats.PulseQueue.Capacity is 0")
+	}
+	if stats.InterventionQueue.Capacity == 0 {
+		t.Error("Stats.InterventionQueue.Capacity is 0")
+	}
+	if stats.CodeQueue.Capacity == 0 {
+		t.Error("Stats.CodeQueue.Capacity is 0")
+	}
+}
+
+// slowJob blocks in Execute until its delay elapses or the worker context is
+// cancelled, standing in for a long-running intervention or notification.
+type slowJob struct {
+	delay       time.Duration
+	enqueueTime time.Time
+	startTime   time.Time
+}
+
+func (j *slowJob) Execute(ctx context.Context) jobs.Result {
+	select {
+	case <-time.After(j.delay):
+	case <-ctx.Done():
+	}
+	return jobs.Result{}
+}
+func (j *slowJob) Copy() jobs.Job             { c := *j; return &c }
+func (j *slowJob) GetEnqueueTime() time.Time  { return j.enqueueTime }
+func (j *slowJob) SetEnqueueTime(t time.Time) { j.enqueueTime = t }
+func (j *slowJob) GetStartTime() time.Time    { return j.startTime }
+func (j *slowJob) SetStartTime(t time.Time)   { j.startTime = t }
+func (j *slowJob) GetEntity() ecs.Entity      { return ecs.Entity{} }
+func (j *slowJob) IsNil() bool                { return false }
+
+// TestController_StopContext_CleanDrain verifies that in-flight intervention
+// jobs finish before shutdown closes their queue and pool.
+func TestController_StopContext_CleanDrain(t *testing.T) {
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	if err := ctrl.Start(nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	const jobCount = 3
+	for i := 0; i < jobCount; i++ {
+		if err := ctrl.interventionQueue.Enqueue(&slowJob{delay: 200 * time.Millisecond}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	report := ctrl.StopContext(context.Background())
+
+	if !report.Completed {
+		t.Errorf("Expected clean drain, got timeout after %v (abandoned=%v)", report.Elapsed, report.Abandoned)
+	}
+	if n := report.Abandoned["intervention"]; n != 0 {
+		t.Errorf("Clean drain abandoned %d intervention jobs", n)
+	}
+	stats := ctrl.interventionPool.Stats()
+	if stats.TasksCompleted < jobCount {
+		t.Errorf("Expected at least %d completed intervention tasks, got %d", jobCount, stats.TasksCompleted)
+	}
+}
+
+// TestController_StopContext_DeadlineExceeded verifies that a drain blocked
+// by jobs slower than the deadline reports the timeout and the abandoned
+// count instead of waiting forever.
+func TestController_StopContext_DeadlineExceeded(t *testing.T) {
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	if err := ctrl.Start(nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// Jobs far slower than the drain deadline; they unblock via context
+	// cancellation once the pools force-stop.
+	for i := 0; i < 2; i++ {
+		if err := ctrl.interventionQueue.Enqueue(&slowJob{delay: time.Minute}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	report := ctrl.StopContext(ctx)
+
+	if report.Completed {
+		t.Error("Expected drain timeout, got clean drain")
+	}
+	if n := report.Abandoned["intervention"]; n < 1 {
+		t.Errorf("Expected abandoned intervention jobs reported, got %d (abandoned=%v)", n, report.Abandoned)
+	}
+	// The last report stays available after shutdown.
+	if got := ctrl.StopContext(context.Background()); got.Completed {
+		t.Error("Repeated Stop should return the recorded timeout report")
+	}
+}
+
+// TestQueueSettings_Validate exercises the startup validation of per-queue
+// settings: implementation names, power-of-two capacity, drop policy names,
+// and watermark sanity.
+func TestQueueSettings_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		settings QueueSettings
+		wantErr  bool
+	}{
+		{name: "zero value keeps defaults", settings: QueueSettings{}},
+		{name: "explicit hybrid", settings: QueueSettings{Implementation: QueueImplHybrid}},
+		{name: "explicit adaptive", settings: QueueSettings{Implementation: QueueImplAdaptive, Capacity: 1024}},
+		{name: "full hybrid tuning", settings: QueueSettings{Capacity: 4096, OverflowCapacity: 512, SoftWatermark: 0.5, HardWatermark: 0.9, DropPolicy: "drop_oldest"}},
+		{name: "unknown implementation", settings: QueueSettings{Implementation: "bounded"}, wantErr: true},
+		{name: "capacity not power of two", settings: QueueSettings{Capacity: 1000}, wantErr: true},
+		{name: "unknown drop policy", settings: QueueSettings{DropPolicy: "discard"}, wantErr: true},
+		{name: "soft watermark above one", settings: QueueSettings{SoftWatermark: 1.5}, wantErr: true},
+		{name: "soft above hard", settings: QueueSettings{SoftWatermark: 0.9, HardWatermark: 0.5}, wantErr: true},
+		{name: "adaptive rejects hybrid knobs", settings: QueueSettings{Implementation: QueueImplAdaptive, DropPolicy: "reject"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.validate("pulse")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNewController_QueueImplementations verifies the per-queue settings
+// select the queue implementation and that invalid settings fail
+// construction instead of surfacing later.
+func TestNewController_QueueImplementations(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.PulseQueue.Implementation = QueueImplAdaptive
+	cfg.CodeQueue.Implementation = QueueImplHybrid
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	if _, ok := ctrl.pulseQueue.(*queue.AdaptiveQueue); !ok {
+		t.Errorf("pulse queue = %T, want *queue.AdaptiveQueue", ctrl.pulseQueue)
+	}
+	if _, ok := ctrl.hfQueue.(*queue.AdaptiveQueue); !ok {
+		t.Errorf("high-frequency queue = %T, want *queue.AdaptiveQueue", ctrl.hfQueue)
+	}
+	if _, ok := ctrl.codeQueue.(*queue.HybridQueue); !ok {
+		t.Errorf("code queue = %T, want *queue.HybridQueue", ctrl.codeQueue)
+	}
+	if _, ok := ctrl.interventionQueue.(*queue.HybridQueue); !ok {
+		t.Errorf("intervention queue = %T, want *queue.HybridQueue", ctrl.interventionQueue)
+	}
+
+	bad := DefaultConfig()
+	bad.InterventionQueue.Capacity = 1000
+	if _, err := NewController(bad); err == nil {
+		t.Error("Expected error for non-power-of-two capacity, got nil")
+	}
+}
+
+// TestController_PulseQueueDropOldest configures the pulse queue with the
+// drop_oldest policy and a tiny capacity, drives it past saturation, and
+// asserts the policy through Stats: every enqueue is admitted while the
+// oldest overflow jobs are evicted and counted as drops.
+func TestController_PulseQueueDropOldest(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.PulseQueue = QueueSettings{Capacity: 8, OverflowCapacity: 8, DropPolicy: "drop_oldest"}
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	// Workers only start with the controller, so everything enqueued here
+	// stays queued. Ring (8) plus overflow (8) hold 16 jobs; the rest must
+	// evict the oldest instead of rejecting.
+	world := ecs.NewWorld()
+	const total = 24
+	for i := 0; i < total; i++ {
+		job := &jobs.PulseHTTPJob{Entity: world.NewEntity()}
+		if err := ctrl.pulseQueue.Enqueue(job); err != nil {
+			t.Fatalf("Enqueue %d failed: %v", i, err)
+		}
+	}
+
+	stats := ctrl.pulseQueue.Stats()
+	if stats.QueueDepth != 16 {
+		t.Errorf("QueueDepth = %d, want 16", stats.QueueDepth)
+	}
+	if stats.Dropped != total-16 {
+		t.Errorf("Dropped = %d, want %d", stats.Dropped, total-16)
+	}
+}
+
+// TestController_AdaptiveQueueEntityThreshold verifies the documented
+// threshold switch: once a load reaches the configured monitor count, the
+// pulse queue is replaced with an AdaptiveQueue before Start.
+func TestController_AdaptiveQueueEntityThreshold(t *testing.T) {
+	// Note: Not using t.Parallel() to avoid race with controller internal state
+	cfg := DefaultConfig()
+	cfg.AdaptiveQueueEntityThreshold = 2
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	if _, ok := ctrl.pulseQueue.(*queue.AdaptiveQueue); ok {
+		t.Fatal("pulse queue should start hybrid below the threshold")
+	}
+
+	if err := ctrl.LoadMonitors(context.Background(), "testdata/test_monitors.yaml"); err != nil {
+		t.Fatalf("LoadMonitors failed: %v", err)
+	}
+	if _, ok := ctrl.pulseQueue.(*queue.AdaptiveQueue); !ok {
+		t.Errorf("pulse queue = %T after crossing the threshold, want *queue.AdaptiveQueue", ctrl.pulseQueue)
+	}
+}
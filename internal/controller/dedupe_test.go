@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TestSharedProbeDedup loads three monitors on one URL with probe dedup
+// enabled and asserts the group probes once per cycle while every member
+// still records its own results.
+func TestSharedProbeDedup(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	var b strings.Builder
+	b.WriteString("monitors:\n")
+	for i := 0; i < 3; i++ {
+		fmt.Fprintf(&b, `  - name: dup-%d
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 1s
+      timeout: 500ms
+      config:
+        url: %s
+`, i, server.URL)
+	}
+	file := filepath.Join(t.TempDir(), "monitors.yaml")
+	if err := os.WriteFile(file, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("Failed to write monitors: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Logger = zap.NewNop().Sugar()
+	cfg.DedupeProbes = true
+	// Sweep every shard each second so 1s intervals dispatch on time.
+	cfg.ShardTargetSweep = time.Second
+
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		ctrl.Stop()
+	})
+	if err := ctrl.LoadMonitors(ctx, file); err != nil {
+		t.Fatalf("LoadMonitors failed: %v", err)
+	}
+	if err := ctrl.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// Wait until every monitor has recorded a result: the leader from its
+	// own check, the followers from the fan-out.
+	names := []string{"dup-0", "dup-1", "dup-2"}
+	deadline := time.Now().Add(15 * time.Second)
+	for _, name := range names {
+		for {
+			samples, err := ctrl.GetHistory(ctx, name)
+			if err != nil {
+				t.Fatalf("GetHistory(%s) failed: %v", name, err)
+			}
+			if len(samples) > 0 {
+				if !samples[0].Success {
+					t.Errorf("monitor %s recorded failure %q, want success", name, samples[0].Error)
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("monitor %s recorded no result before deadline", name)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	// With the group warmed up, only the leader should hit the target: at a
+	// 1s interval a 3s window sees 2-4 requests, where three independent
+	// monitors would make 6-12.
+	before := hits.Load()
+	time.Sleep(3 * time.Second)
+	window := hits.Load() - before
+	if window > 5 {
+		t.Errorf("observed %d requests in 3s window, want at most 5 (one probe per cycle)", window)
+	}
+	if window == 0 {
+		t.Error("observed no requests in 3s window; leader stopped probing")
+	}
+}
@@ -0,0 +1,1132 @@
+package entities
+
+import (
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/interning"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// HeartbeatRegistrar receives heartbeat monitors as their entities are created,
+// so the ingestion endpoint can map per-monitor tokens to entities.
+type HeartbeatRegistrar interface {
+	// Register maps the monitor to its ingestion token and returns it.
+	// An empty token asks the registrar to generate one.
+	Register(monitorName, token string, ent ecs.Entity) string
+}
+
+// EntityManager uses the new consolidated component design.
+// This dramatically reduces the number of archetypes and improves performance.
+type EntityManager struct {
+	// Core consolidated components - only a few archetypes instead of dozens.
+	MonitorState       *ecs.Map1[components.MonitorState]
+	MonitorMeta        *ecs.Map1[components.MonitorMeta]
+	PulseConfig        *ecs.Map1[components.PulseConfig]
+	InterventionConfig *ecs.Map1[components.InterventionConfig]
+	CodeConfig         *ecs.Map1[components.CodeConfig]
+	CodeStatus         *ecs.Map1[components.CodeStatus]
+	JobStorage         *ecs.Map1[components.JobStorage]
+	Shard              *ecs.Map1[components.Shard]
+	Heartbeat          *ecs.Map1[components.Heartbeat]
+	HighFrequency      *ecs.Map1[components.HighFrequency]
+	Maintenance        *ecs.Map1[components.Maintenance]
+	Schedule           *ecs.Map1[components.Schedule]
+	History            *ecs.Map1[components.ResultHistory]
+	SLO                *ecs.Map1[components.SLO]
+	DependsOn          *ecs.Map1[components.DependsOn]
+
+	// Grouped mappers to minimize archetype moves during creation
+	baseMapper *ecs.Map5[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.JobStorage, components.Shard]
+	codePair   *ecs.Map2[components.CodeConfig, components.CodeStatus]
+	Disabled   *ecs.Map1[components.Disabled]
+	// ExplicitlyDisabled marks disables requested outright (admin API,
+	// enabled: false, panic auto-disable) so the window systems leave them
+	// alone; see components.ExplicitlyDisabled.
+	ExplicitlyDisabled *ecs.Map1[components.ExplicitlyDisabled]
+
+	// nextShard tracks round-robin shard assignment across entity creations.
+	nextShard uint32
+	// shardSlots determines the modulus for shard assignment.
+	shardSlots uint32
+
+	// historySize is the per-monitor result history ring capacity; 0
+	// disables history so no component is added at creation.
+	historySize int
+
+	// generation is stamped onto every config component written by this
+	// manager, so an interrupted update (components from different manifest
+	// versions on one entity) can be detected afterwards.
+	generation uint32
+
+	// heartbeatReg, if set, is notified for each heartbeat monitor created.
+	heartbeatReg HeartbeatRegistrar
+
+	// nameIndex maps monitor names to their entities for by-name lookups
+	// (admin API, reload diffing). Guarded by nameMu because lookups come
+	// from HTTP goroutines while creation runs on the loader/ECS side.
+	// infoIndex is the reverse view — entity to name and labels — for
+	// callers that hold a result entity outside the tick (result sinks)
+	// and cannot touch ECS storage.
+	nameMu    sync.RWMutex
+	nameIndex map[string]ecs.Entity
+	infoIndex map[ecs.Entity]MonitorInfo
+
+	// pendingDeps records each monitor's depends_on names as entities are
+	// created or updated; ResolveDependencies consumes it once the whole
+	// manifest is in the world, since dependencies may point forward to
+	// monitors in later batches. Loader-side only, so no lock.
+	pendingDeps map[string][]string
+
+	// interventionDryRun forces dry-run wrapping on every intervention job
+	// built by this manager (the controller's global override).
+	interventionDryRun bool
+
+	now func() time.Time // overridable for fake-clock tests
+}
+
+// NewEntityManager creates a new consolidated entity manager.
+func NewEntityManager(world *ecs.World) *EntityManager {
+	return &EntityManager{
+		MonitorState:       ecs.NewMap1[components.MonitorState](world),
+		MonitorMeta:        ecs.NewMap1[components.MonitorMeta](world),
+		PulseConfig:        ecs.NewMap1[components.PulseConfig](world),
+		InterventionConfig: ecs.NewMap1[components.InterventionConfig](world),
+		CodeConfig:         ecs.NewMap1[components.CodeConfig](world),
+		CodeStatus:         ecs.NewMap1[components.CodeStatus](world),
+		JobStorage:         ecs.NewMap1[components.JobStorage](world),
+		Shard:              ecs.NewMap1[components.Shard](world),
+		Heartbeat:          ecs.NewMap1[components.Heartbeat](world),
+		HighFrequency:      ecs.NewMap1[components.HighFrequency](world),
+		Maintenance:        ecs.NewMap1[components.Maintenance](world),
+		Schedule:           ecs.NewMap1[components.Schedule](world),
+		History:            ecs.NewMap1[components.ResultHistory](world),
+		SLO:                ecs.NewMap1[components.SLO](world),
+		DependsOn:          ecs.NewMap1[components.DependsOn](world),
+		baseMapper:         ecs.NewMap5[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.JobStorage, components.Shard](world),
+		codePair:           ecs.NewMap2[components.CodeConfig, components.CodeStatus](world),
+		Disabled:           ecs.NewMap1[components.Disabled](world),
+		ExplicitlyDisabled: ecs.NewMap1[components.ExplicitlyDisabled](world),
+		shardSlots:         components.DefaultShardSlots,
+		nameIndex:          make(map[string]ecs.Entity),
+		infoIndex:          make(map[ecs.Entity]MonitorInfo),
+		pendingDeps:        make(map[string][]string),
+		now:                time.Now,
+	}
+}
+
+// SetClock routes the timestamps stamped onto created and updated monitor
+// state through c. A nil clock is ignored.
+func (e *EntityManager) SetClock(c clock.Clock) {
+	if c != nil {
+		e.now = c.Now
+	}
+}
+
+// SetHeartbeatRegistrar sets the registrar notified for heartbeat monitors.
+// Must be called before loading monitors.
+func (e *EntityManager) SetHeartbeatRegistrar(reg HeartbeatRegistrar) {
+	e.heartbeatReg = reg
+}
+
+// SetGeneration sets the manifest generation stamped onto config components
+// written from now on. The controller bumps this per successful load/reload.
+func (e *EntityManager) SetGeneration(gen uint32) {
+	e.generation = gen
+}
+
+// Generation returns the manifest generation currently being stamped.
+func (e *EntityManager) Generation() uint32 {
+	return e.generation
+}
+
+// SetShardSlots allows the controller to configure the number of shard slots dynamically.
+// Values less than 1 fall back to DefaultShardSlots.
+func (e *EntityManager) SetShardSlots(slots int) {
+	if slots <= 0 {
+		e.shardSlots = components.DefaultShardSlots
+		return
+	}
+	e.shardSlots = uint32(slots)
+}
+
+// SetHistorySize configures the per-monitor result history ring capacity
+// applied to entities created from now on. Values <= 0 disable history.
+func (e *EntityManager) SetHistorySize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	e.historySize = n
+}
+
+// CreateEntityFromMonitor creates an entity using the consolidated design.
+func (e *EntityManager) CreateEntityFromMonitor(
+	monitor *schema.Monitor,
+	world *ecs.World) error {
+
+	// Validation
+	if world == nil {
+		return fmt.Errorf("world cannot be nil")
+	}
+	if e == nil {
+		return fmt.Errorf("EntityManager cannot be nil")
+	}
+	if monitor.Name == "" {
+		fmt.Println(monitor, "name cannot be empty")
+		return fmt.Errorf("monitor name cannot be empty")
+	}
+
+	// Single time snapshot reused to avoid multiple now() calls
+	now := e.now()
+	reg := components.DefaultConfigRegistry()
+
+	// Create consolidated MonitorState component
+	monitorName := interning.Intern(monitor.Name)
+	monitorState := GetMonitorState()
+	*monitorState = components.MonitorState{}
+	monitorState.LastPulseCheckTime = now
+	monitorState.NextCheckTime = now
+	monitorMeta := GetMonitorMeta()
+	*monitorMeta = components.MonitorMeta{}
+	monitorMeta.Name = monitorName
+	monitorMeta.Labels = interning.InternLabels(monitor.Labels)
+	monitorMeta.LastEventTime = now
+	monitorMeta.LastSuccessTime = now
+
+	// Set initial state flags or Disabled tag
+	if monitor.Enabled {
+		monitorState.SetPulseFirstCheck(true)
+	}
+
+	// Prepare pulse configuration (added during entity creation)
+	// Map thresholds: prefer explicit unhealthy_threshold; loader maps legacy max_failures into it
+	pulseConfig := GetPulseConfig()
+	*pulseConfig = components.PulseConfig{}
+	pulseConfig.Type = interning.Intern(monitor.Pulse.Type)
+	pulseConfig.UnhealthyThreshold = monitor.Pulse.UnhealthyThreshold
+	pulseConfig.UnhealthyAttemptThreshold = monitor.Pulse.UnhealthyAttemptThreshold
+	pulseConfig.HealthyThreshold = monitor.Pulse.HealthyThreshold
+	pulseConfig.Timeout = monitor.Pulse.Timeout
+	pulseConfig.Interval = monitor.Pulse.Interval
+	pulseConfig.DegradedLatency = monitor.Pulse.DegradedLatency
+	pulseConfig.Invert = monitor.Pulse.Invert
+	pulseConfig.NoDedupe = monitor.Pulse.Dedupe != nil && !*monitor.Pulse.Dedupe
+	pulseConfig.Trace = monitor.Pulse.Trace
+	pulseConfig.CountRateLimitAsFailure = monitor.Pulse.CountRateLimitAsFailure
+	// Assign schema config directly; ownership is at an ECS component.
+	// Future updates should replace the component (copy-on-write), not mutate in place.
+	pulseConfig.Config = monitor.Pulse.Config
+	pulseConfig.Generation = e.generation
+	// Create consolidated job storage (empty at first; jobs filled after we have the entity ID)
+	// Pre-size maps based on number of codes to minimize rehashing/resizes
+	codeCount := len(monitor.Codes)
+	jobStorage := GetJobStorage()
+
+	// Assign shard in round-robin fashion to spread workload across ticks.
+	shardID := e.nextShard % e.shardSlots
+	e.nextShard = (e.nextShard + 1) % e.shardSlots
+
+	// Create an entity with base components in a single archetype transition
+	shard := &components.Shard{ID: uint8(shardID)}
+	entity := e.baseMapper.NewEntity(monitorState, monitorMeta, pulseConfig, jobStorage, shard)
+	if !world.Alive(entity) {
+		// Return pooled components on error
+		PutMonitorState(monitorState)
+		PutMonitorMeta(monitorMeta)
+		PutPulseConfig(pulseConfig)
+		PutJobStorage(jobStorage)
+		return fmt.Errorf("failed to create valid entity")
+	}
+
+	// Return base components to pools immediately after Ark copies the values
+	PutMonitorState(monitorState)
+	PutMonitorMeta(monitorMeta)
+	PutPulseConfig(pulseConfig)
+	PutJobStorage(jobStorage)
+
+	e.indexMonitor(monitorName, entity, interning.InternLabels(monitor.Labels))
+	e.pendingDeps[monitorName] = monitor.DependsOn
+
+	// Add a pulse job to existing JobStorage
+	pulseJob, err := jobs.CreatePulseJob(monitor.Pulse, entity)
+	if err != nil {
+		return err
+	}
+	if js := e.JobStorage.Get(entity); js != nil {
+		js.PulseJob = pulseJob
+	}
+
+	// Heartbeat monitors get a Heartbeat component and an ingestion token
+	if hb, ok := monitor.Pulse.Config.(*schema.PulseHeartbeatConfig); ok && hb != nil {
+		e.Heartbeat.Add(entity, &components.Heartbeat{LastSeen: now, Grace: hb.Grace})
+		if e.heartbeatReg != nil {
+			e.heartbeatReg.Register(monitorName, hb.Token, entity)
+		}
+	}
+
+	// High-frequency monitors are dispatched by the dedicated fast path
+	if monitor.Pulse.HighFrequency {
+		e.HighFrequency.Add(entity, &components.HighFrequency{})
+	}
+
+	// Maintenance windows are compiled once at load time
+	if windows, err := compileMaintenanceWindows(monitor); err != nil {
+		return err
+	} else if windows != nil {
+		e.Maintenance.Add(entity, &components.Maintenance{Windows: windows})
+	}
+
+	// Check schedules (active hours) are compiled once at load time
+	if sched, err := compileSchedule(monitor); err != nil {
+		return err
+	} else if sched != nil {
+		e.Schedule.Add(entity, sched)
+	}
+
+	// SLO objectives are compiled once at load time
+	if slo, err := compileSLO(monitor); err != nil {
+		return err
+	} else if slo != nil {
+		e.SLO.Add(entity, slo)
+	}
+
+	// Result history ring for flap debugging, when enabled
+	if e.historySize > 0 {
+		history := components.NewResultHistory(e.historySize)
+		e.History.Add(entity, &history)
+	}
+
+	// Add intervention if configured
+	var interventionConfig *components.InterventionConfig
+	if monitor.Intervention.Action != "" {
+		maxFailures := 1
+		if monitor.Intervention.MaxFailures > 0 {
+			maxFailures = monitor.Intervention.MaxFailures
+		}
+
+		// Build the escalation chain first so job errors surface before
+		// the component is added.
+		stepJobs, cfgSteps, err := e.buildInterventionChain(monitor.Intervention, entity)
+		if err != nil {
+			return err
+		}
+
+		interventionConfig = GetInterventionConfig()
+		*interventionConfig = components.InterventionConfig{}
+		interventionConfig.Action = interning.Intern(monitor.Intervention.Action)
+		// Assign a schema target directly; updates should replace the component (COW).
+		interventionConfig.Target = monitor.Intervention.Target
+		interventionConfig.MaxFailures = maxFailures
+		interventionConfig.Cooldown = monitor.Intervention.Cooldown
+		interventionConfig.MaxPerHour = monitor.Intervention.MaxPerHour
+		interventionConfig.Steps = cfgSteps
+		interventionConfig.Generation = e.generation
+		e.InterventionConfig.Add(entity, interventionConfig)
+		// Return to the pool after Ark copies the value
+		PutInterventionConfig(interventionConfig)
+
+		if js := e.JobStorage.Get(entity); js != nil {
+			js.InterventionJob = stepJobs[0]
+			js.InterventionJobs = stepJobs
+		}
+	}
+
+	// Add consolidated code configuration instead of separate color components
+	var codeConfig *components.CodeConfig
+	var codeStatus *components.CodeStatus
+	if codeCount > 0 {
+		codeConfig = GetCodeConfig(codeCount)
+		codeStatus = GetCodeStatus(codeCount)
+
+		for color, config := range monitor.Codes {
+			colorKey := interning.Intern(color)
+			idx := components.ColorToIndex(colorKey)
+			if idx == components.ColorNone {
+				continue
+			}
+
+			// Value type assignment
+			cc := components.ColorCodeConfig{
+				Dispatch: config.Dispatch,
+				Notify:   interning.Intern(config.Notify),
+				Cooldown: config.Cooldown,
+				Config:   config.Config, // Copy interface/pointer
+			}
+			codeConfig.Configs[idx] = reg.GetOrAdd(cc)
+			registerCodeExtras(codeConfig, idx, config.Extra, reg)
+
+			cs := components.ColorCodeStatus{
+				LastAlertTime: now.Unix(),
+			}
+			codeStatus.Status[idx] = cs
+		}
+		codeConfig.Generation = e.generation
+
+		// Add both code components in a single step
+		e.codePair.Add(entity, codeConfig, codeStatus)
+		// Pooling disabled, Put calls are no-ops but harmless
+		PutCodeConfig(codeConfig)
+		PutCodeStatus(codeStatus)
+	}
+
+	// Apply the Disabled tag after base creation if the monitor is disabled.
+	// The marker records that the disable came from the manifest, not a
+	// window edge, so the schedule systems leave it in place.
+	if !monitor.Enabled {
+		e.Disabled.Add(entity, &components.Disabled{})
+		e.ExplicitlyDisabled.Add(entity, &components.ExplicitlyDisabled{})
+	}
+
+	return nil
+}
+
+// pendingExtra holds components to be added after batch creation
+type pendingExtra struct {
+	InterventionConfig *components.InterventionConfig
+	CodeConfig         *components.CodeConfig
+	CodeStatus         *components.CodeStatus
+	Heartbeat          *components.Heartbeat
+	Maintenance        []components.MaintenanceWindow
+	Schedule           *components.Schedule
+	SLO                *components.SLO
+	MonitorName        string
+	HeartbeatToken     string
+	Entity             ecs.Entity
+	Disabled           bool
+	HighFrequency      bool
+}
+
+// CreateEntitiesFromMonitors creates entities in a batch using Ark's Map3.NewBatchFn to minimize
+// archetype transitions and reduce per-entity overhead. It mirrors CreateEntityFromMonitor logic
+// for each monitor without changing behavior. Job creation error is recorded and returned
+// after the batch completes; entities created before an error remain valid, identical to
+// one-by-one creation semantics.
+func (e *EntityManager) CreateEntitiesFromMonitors(world *ecs.World, monitors []schema.Monitor) error {
+	// Validation
+	if world == nil {
+		return fmt.Errorf("world cannot be nil")
+	}
+	if e == nil {
+		return fmt.Errorf("EntityManager cannot be nil")
+	}
+	if len(monitors) == 0 {
+		return nil
+	}
+
+	// Single time snapshot reused to avoid multiple now() calls across the batch
+	now := e.now()
+	reg := components.DefaultConfigRegistry()
+
+	// Capture extras to add AFTER batch creation to avoid "locked world" panic
+	pending := make([]pendingExtra, 0, min(len(monitors)/4, 4096))
+
+	// We use a captured index to provide per-monitor data to the batch callback.
+	i := 0
+	var firstErr error
+	shardCursor := e.nextShard
+
+	e.baseMapper.NewBatchFn(len(monitors), func(entity ecs.Entity, monitorState *components.MonitorState, monitorMeta *components.MonitorMeta, pulseConfig *components.PulseConfig, jobStorage *components.JobStorage, shard *components.Shard) {
+		// If an error was already encountered, skip heavy work but still leave components initialized.
+		if firstErr != nil {
+			return
+		}
+
+		monitor := monitors[i]
+		i++
+
+		// Monitor name and times
+		monitorName := interning.Intern(monitor.Name)
+		monitorMeta.Name = monitorName
+		monitorMeta.Labels = interning.InternLabels(monitor.Labels)
+		monitorMeta.LastEventTime = now
+		monitorMeta.LastSuccessTime = now
+		monitorState.LastPulseCheckTime = now
+		monitorState.NextCheckTime = now
+		e.indexMonitor(monitorName, entity, monitorMeta.Labels)
+		e.pendingDeps[monitorName] = monitor.DependsOn
+
+		// Assign shard in round-robin order
+		shardID := shardCursor % e.shardSlots
+		shardCursor = (shardCursor + 1) % e.shardSlots
+		if shard != nil {
+			shard.ID = uint8(shardID)
+		}
+
+		// Initial state flags or Disabled tag
+		if monitor.Enabled {
+			monitorState.SetPulseFirstCheck(true)
+		}
+
+		// Pulse configuration: prefer explicit unhealthy_threshold; loader fills from legacy max_failures if provided
+		pulseConfig.Type = interning.Intern(monitor.Pulse.Type)
+		pulseConfig.UnhealthyThreshold = monitor.Pulse.UnhealthyThreshold
+		pulseConfig.UnhealthyAttemptThreshold = monitor.Pulse.UnhealthyAttemptThreshold
+		pulseConfig.HealthyThreshold = monitor.Pulse.HealthyThreshold
+		pulseConfig.Timeout = monitor.Pulse.Timeout
+		pulseConfig.Interval = monitor.Pulse.Interval
+		pulseConfig.Invert = monitor.Pulse.Invert
+		pulseConfig.NoDedupe = monitor.Pulse.Dedupe != nil && !*monitor.Pulse.Dedupe
+		pulseConfig.Trace = monitor.Pulse.Trace
+		pulseConfig.CountRateLimitAsFailure = monitor.Pulse.CountRateLimitAsFailure
+		if monitor.Pulse.Config != nil {
+			// Assign schema config directly; future changes should replace component (COW).
+			pulseConfig.Config = monitor.Pulse.Config
+		} else {
+			pulseConfig.Config = nil
+		}
+		pulseConfig.Generation = e.generation
+
+		// Job storage: pre-size code jobs map based on the number of configured colors
+		jobStorage.PulseJob = nil
+		jobStorage.InterventionJob = nil
+
+		// Create a pulse job and attach to JobStorage
+		if pj, err := jobs.CreatePulseJob(monitor.Pulse, entity); err != nil {
+			firstErr = err
+			return
+		} else {
+			jobStorage.PulseJob = pj
+		}
+
+		// Prepare pending extra data
+		extra := pendingExtra{Entity: entity}
+		hasExtra := false
+
+		// Intervention configuration (optional)
+		if monitor.Intervention.Action != "" {
+			maxFailures := 1
+			if monitor.Intervention.MaxFailures > 0 {
+				maxFailures = monitor.Intervention.MaxFailures
+			}
+			interventionConfig := GetInterventionConfig()
+			*interventionConfig = components.InterventionConfig{}
+			interventionConfig.Action = interning.Intern(monitor.Intervention.Action)
+			// Assign a schema target directly; future changes should replace component (COW).
+			interventionConfig.Target = monitor.Intervention.Target
+			interventionConfig.MaxFailures = maxFailures
+			interventionConfig.Cooldown = monitor.Intervention.Cooldown
+			interventionConfig.MaxPerHour = monitor.Intervention.MaxPerHour
+			interventionConfig.Generation = e.generation
+
+			// Create one intervention job per escalation step and attach
+			if stepJobs, cfgSteps, err := e.buildInterventionChain(monitor.Intervention, entity); err != nil {
+				firstErr = err
+				// Note: we still might add intervention config if we don't return here,
+				// but strict error handling says we should abort.
+				// However, if we abort, we leak the pooled component from GetInterventionConfig above?
+				// No, we haven't added it to a pending list yet. We should Put it back if we fail.
+				PutInterventionConfig(interventionConfig)
+				return
+			} else {
+				interventionConfig.Steps = cfgSteps
+				jobStorage.InterventionJob = stepJobs[0]
+				jobStorage.InterventionJobs = stepJobs
+			}
+
+			extra.InterventionConfig = interventionConfig
+			hasExtra = true
+		}
+
+		// Consolidated code configuration & status
+		codeCount := len(monitor.Codes)
+		if codeCount > 0 {
+			codeConfig := GetCodeConfig(codeCount)
+			codeStatus := GetCodeStatus(codeCount)
+
+			for color, cfg := range monitor.Codes {
+				colorKey := interning.Intern(color)
+				idx := components.ColorToIndex(colorKey)
+				if idx == components.ColorNone {
+					continue
+				}
+
+				// Per-color config
+				cc := components.ColorCodeConfig{
+					Dispatch: cfg.Dispatch,
+					Notify:   interning.Intern(cfg.Notify),
+					Cooldown: cfg.Cooldown,
+					Config:   cfg.Config,
+				}
+				codeConfig.Configs[idx] = reg.GetOrAdd(cc)
+				registerCodeExtras(codeConfig, idx, cfg.Extra, reg)
+
+				// Per-color status
+				cs := components.ColorCodeStatus{
+					LastAlertTime: now.Unix(),
+				}
+				codeStatus.Status[idx] = cs
+			}
+			codeConfig.Generation = e.generation
+
+			extra.CodeConfig = codeConfig
+			extra.CodeStatus = codeStatus
+			hasExtra = true
+		}
+
+		// Heartbeat monitors get a Heartbeat component and an ingestion token
+		if hb, ok := monitor.Pulse.Config.(*schema.PulseHeartbeatConfig); ok && hb != nil {
+			extra.Heartbeat = &components.Heartbeat{LastSeen: now, Grace: hb.Grace}
+			extra.MonitorName = monitorName
+			extra.HeartbeatToken = hb.Token
+			hasExtra = true
+		}
+
+		// High-frequency monitors are dispatched by the dedicated fast path
+		if monitor.Pulse.HighFrequency {
+			extra.HighFrequency = true
+			hasExtra = true
+		}
+
+		// Maintenance windows are compiled once at load time
+		if windows, err := compileMaintenanceWindows(&monitor); err != nil {
+			firstErr = err
+			return
+		} else if windows != nil {
+			extra.Maintenance = windows
+			hasExtra = true
+		}
+
+		// Check schedules (active hours) are compiled once at load time
+		if sched, err := compileSchedule(&monitor); err != nil {
+			firstErr = err
+			return
+		} else if sched != nil {
+			extra.Schedule = sched
+			hasExtra = true
+		}
+
+		// SLO objectives are compiled once at load time
+		if slo, err := compileSLO(&monitor); err != nil {
+			firstErr = err
+			return
+		} else if slo != nil {
+			extra.SLO = slo
+			hasExtra = true
+		}
+
+		// Apply the Disabled tag after base creation if the monitor is disabled
+		if !monitor.Enabled {
+			extra.Disabled = true
+			hasExtra = true
+		}
+
+		// History applies to every entity; route them all through pending
+		// so the ring is added once the world is unlocked.
+		if e.historySize > 0 {
+			hasExtra = true
+		}
+
+		if hasExtra {
+			pending = append(pending, extra)
+		}
+	})
+
+	// Apply pending components after batch creation is done (world unlocked)
+	for _, p := range pending {
+		if p.InterventionConfig != nil {
+			e.InterventionConfig.Add(p.Entity, p.InterventionConfig)
+			PutInterventionConfig(p.InterventionConfig)
+		}
+		if p.CodeConfig != nil && p.CodeStatus != nil {
+			e.codePair.Add(p.Entity, p.CodeConfig, p.CodeStatus)
+			PutCodeConfig(p.CodeConfig)
+			PutCodeStatus(p.CodeStatus)
+		}
+		if p.Heartbeat != nil {
+			e.Heartbeat.Add(p.Entity, p.Heartbeat)
+			if e.heartbeatReg != nil {
+				e.heartbeatReg.Register(p.MonitorName, p.HeartbeatToken, p.Entity)
+			}
+		}
+		if p.HighFrequency {
+			e.HighFrequency.Add(p.Entity, &components.HighFrequency{})
+		}
+		if p.Maintenance != nil {
+			e.Maintenance.Add(p.Entity, &components.Maintenance{Windows: p.Maintenance})
+		}
+		if p.Schedule != nil {
+			e.Schedule.Add(p.Entity, p.Schedule)
+		}
+		if p.SLO != nil {
+			e.SLO.Add(p.Entity, p.SLO)
+		}
+		if p.Disabled {
+			e.Disabled.Add(p.Entity, &components.Disabled{})
+			e.ExplicitlyDisabled.Add(p.Entity, &components.ExplicitlyDisabled{})
+		}
+		if e.historySize > 0 {
+			history := components.NewResultHistory(e.historySize)
+			e.History.Add(p.Entity, &history)
+		}
+	}
+
+	// Persist shard cursor for later batches
+	e.nextShard = shardCursor
+
+	return firstErr
+}
+
+// ApplyMonitorConfig replaces an entity's config components with the given
+// monitor's configuration, all stamped with the manager's current generation.
+// It is used to heal entities whose components span manifest generations after
+// an interrupted update, and by future reload paths. Components are replaced
+// wholesale (copy-on-write), never mutated field by field.
+func (e *EntityManager) ApplyMonitorConfig(world *ecs.World, entity ecs.Entity, monitor *schema.Monitor) error {
+	if world == nil || !world.Alive(entity) {
+		return fmt.Errorf("entity is not alive")
+	}
+	now := e.now()
+	reg := components.DefaultConfigRegistry()
+
+	// Labels are immutable per load, so a reload replaces the set wholesale.
+	if meta := e.MonitorMeta.Get(entity); meta != nil {
+		meta.Labels = interning.InternLabels(monitor.Labels)
+		e.indexMonitor(meta.Name, entity, meta.Labels)
+	}
+
+	// Pulse config and job
+	if pc := e.PulseConfig.Get(entity); pc != nil {
+		*pc = components.PulseConfig{
+			Type:                      interning.Intern(monitor.Pulse.Type),
+			UnhealthyThreshold:        monitor.Pulse.UnhealthyThreshold,
+			UnhealthyAttemptThreshold: monitor.Pulse.UnhealthyAttemptThreshold,
+			HealthyThreshold:          monitor.Pulse.HealthyThreshold,
+			Timeout:                   monitor.Pulse.Timeout,
+			Interval:                  monitor.Pulse.Interval,
+			DegradedLatency:           monitor.Pulse.DegradedLatency,
+			Invert:                    monitor.Pulse.Invert,
+			NoDedupe:                  monitor.Pulse.Dedupe != nil && !*monitor.Pulse.Dedupe,
+			Trace:                     monitor.Pulse.Trace,
+			CountRateLimitAsFailure:   monitor.Pulse.CountRateLimitAsFailure,
+			Config:                    monitor.Pulse.Config,
+			Generation:                e.generation,
+		}
+	}
+	pulseJob, err := jobs.CreatePulseJob(monitor.Pulse, entity)
+	if err != nil {
+		return err
+	}
+	if js := e.JobStorage.Get(entity); js != nil {
+		js.PulseJob = pulseJob
+	}
+
+	// Intervention config and job
+	if monitor.Intervention.Action != "" {
+		maxFailures := 1
+		if monitor.Intervention.MaxFailures > 0 {
+			maxFailures = monitor.Intervention.MaxFailures
+		}
+		stepJobs, cfgSteps, err := e.buildInterventionChain(monitor.Intervention, entity)
+		if err != nil {
+			return err
+		}
+		cfg := components.InterventionConfig{
+			Action:      interning.Intern(monitor.Intervention.Action),
+			Target:      monitor.Intervention.Target,
+			MaxFailures: maxFailures,
+			Steps:       cfgSteps,
+			Generation:  e.generation,
+		}
+		// HasAll before Get: Ark's nil pointer for a missing component comes
+		// from unsafe arithmetic and a == nil check on it may be elided.
+		if e.InterventionConfig.HasAll(entity) {
+			*e.InterventionConfig.Get(entity) = cfg
+		} else {
+			e.InterventionConfig.Add(entity, &cfg)
+		}
+		if js := e.JobStorage.Get(entity); js != nil {
+			js.InterventionJob = stepJobs[0]
+			js.InterventionJobs = stepJobs
+		}
+	} else if e.InterventionConfig.HasAll(entity) {
+		e.InterventionConfig.Remove(entity)
+		if js := e.JobStorage.Get(entity); js != nil {
+			js.InterventionJob = nil
+			js.InterventionJobs = nil
+		}
+	}
+
+	// Code config; status is preserved where the entity already has one
+	if len(monitor.Codes) > 0 {
+		codeConfig := components.CodeConfig{Generation: e.generation}
+		for color, cfg := range monitor.Codes {
+			idx := components.ColorToIndex(interning.Intern(color))
+			if idx == components.ColorNone {
+				continue
+			}
+			cc := components.ColorCodeConfig{
+				Dispatch: cfg.Dispatch,
+				Notify:   interning.Intern(cfg.Notify),
+				Cooldown: cfg.Cooldown,
+				Config:   cfg.Config,
+			}
+			codeConfig.Configs[idx] = reg.GetOrAdd(cc)
+			registerCodeExtras(&codeConfig, idx, cfg.Extra, reg)
+		}
+		if e.CodeConfig.HasAll(entity) {
+			*e.CodeConfig.Get(entity) = codeConfig
+		} else {
+			codeStatus := components.CodeStatus{}
+			for idx := range codeStatus.Status {
+				codeStatus.Status[idx] = components.ColorCodeStatus{LastAlertTime: now.Unix()}
+			}
+			e.codePair.Add(entity, &codeConfig, &codeStatus)
+		}
+	} else if e.CodeConfig.HasAll(entity) {
+		e.codePair.Remove(entity)
+	}
+
+	// High-frequency opt-in may change across manifest versions
+	if monitor.Pulse.HighFrequency && !e.HighFrequency.HasAll(entity) {
+		e.HighFrequency.Add(entity, &components.HighFrequency{})
+	} else if !monitor.Pulse.HighFrequency && e.HighFrequency.HasAll(entity) {
+		e.HighFrequency.Remove(entity)
+	}
+
+	// Dependency lists may change across manifest versions. Only recorded
+	// here; resolution runs after the whole reload so forward references
+	// to monitors updated later in the manifest work.
+	e.pendingDeps[monitor.Name] = monitor.DependsOn
+
+	// Maintenance windows may change across manifest versions. Active is
+	// preserved on replacement so the MaintenanceSystem re-evaluates the new
+	// windows on its next tick and fires the exit edge if needed.
+	windows, err := compileMaintenanceWindows(monitor)
+	if err != nil {
+		return err
+	}
+	if windows != nil {
+		if e.Maintenance.HasAll(entity) {
+			e.Maintenance.Get(entity).Windows = windows
+		} else {
+			e.Maintenance.Add(entity, &components.Maintenance{Windows: windows})
+		}
+	} else if e.Maintenance.HasAll(entity) {
+		// Dropping the maintenance block mid-window must not leave the
+		// monitor suppressed forever. An explicit disable is not the
+		// window's to release, so it stays.
+		if e.Maintenance.Get(entity).Active && e.Disabled.HasAll(entity) && !e.ExplicitlyDisabled.HasAll(entity) {
+			e.EnableMonitor(entity)
+		}
+		e.Maintenance.Remove(entity)
+	}
+
+	// Check schedules may change across manifest versions. Active is
+	// preserved on replacement so the ActiveHoursSystem re-evaluates the
+	// new window on its next tick and fires the exit edge if needed.
+	sched, err := compileSchedule(monitor)
+	if err != nil {
+		return err
+	}
+	if sched != nil {
+		if e.Schedule.HasAll(entity) {
+			cur := e.Schedule.Get(entity)
+			sched.Active = cur.Active
+			*cur = *sched
+		} else {
+			e.Schedule.Add(entity, sched)
+		}
+	} else if e.Schedule.HasAll(entity) {
+		// Dropping the schedule block outside its hours must not leave the
+		// monitor suppressed forever. An explicit disable is not the
+		// window's to release, so it stays.
+		if !e.Schedule.Get(entity).Active && e.Disabled.HasAll(entity) && !e.ExplicitlyDisabled.HasAll(entity) {
+			e.EnableMonitor(entity)
+		}
+		e.Schedule.Remove(entity)
+	}
+
+	// SLO objectives may change across manifest versions. The outcome rings
+	// are preserved on replacement so a reload does not reset burn history;
+	// only the objective fields are swapped.
+	slo, err := compileSLO(monitor)
+	if err != nil {
+		return err
+	}
+	if slo != nil {
+		if e.SLO.HasAll(entity) {
+			cur := e.SLO.Get(entity)
+			cur.Target = slo.Target
+			cur.Window = slo.Window
+			cur.LatencyThreshold = slo.LatencyThreshold
+			cur.LatencyTarget = slo.LatencyTarget
+		} else {
+			e.SLO.Add(entity, slo)
+		}
+	} else if e.SLO.HasAll(entity) {
+		e.SLO.Remove(entity)
+	}
+
+	return nil
+}
+
+// ResolveDependencies resolves the depends_on lists recorded since the last
+// call into DependsOn components and validates the resulting graph. It must
+// run after the whole manifest is in the world, since names may point
+// forward to monitors created in later batches. Unknown names and dependency
+// cycles are load errors; the pending lists are consumed either way so a
+// rejected manifest does not leak into the next load.
+func (e *EntityManager) ResolveDependencies(world *ecs.World) error {
+	pending := e.pendingDeps
+	e.pendingDeps = make(map[string][]string)
+
+	for child, depNames := range pending {
+		entity, ok := e.LookupMonitor(child)
+		if !ok || !world.Alive(entity) {
+			// Monitor was removed after recording; nothing to resolve.
+			continue
+		}
+		if len(depNames) == 0 {
+			if e.DependsOn.HasAll(entity) {
+				e.DependsOn.Remove(entity)
+			}
+			continue
+		}
+		parents := make([]ecs.Entity, 0, len(depNames))
+		for _, name := range depNames {
+			parent, ok := e.LookupMonitor(name)
+			if !ok || !world.Alive(parent) {
+				return fmt.Errorf("monitor %q depends on unknown monitor %q", child, name)
+			}
+			parents = append(parents, parent)
+		}
+		if e.DependsOn.HasAll(entity) {
+			e.DependsOn.Get(entity).Parents = parents
+		} else {
+			e.DependsOn.Add(entity, &components.DependsOn{Parents: parents})
+		}
+	}
+
+	return e.checkDependencyCycles(world)
+}
+
+// checkDependencyCycles rejects dependency graphs containing a cycle; every
+// monitor in one would suppress the others and no alert could ever fire.
+func (e *EntityManager) checkDependencyCycles(world *ecs.World) error {
+	graph := make(map[ecs.Entity][]ecs.Entity)
+	filter := ecs.NewFilter1[components.DependsOn](world)
+	query := filter.Query()
+	for query.Next() {
+		graph[query.Entity()] = query.Get().Parents
+	}
+
+	const (
+		visiting = 1
+		resolved = 2
+	)
+	colors := make(map[ecs.Entity]uint8, len(graph))
+	var visit func(entity ecs.Entity, path []string) error
+	visit = func(entity ecs.Entity, path []string) error {
+		name := ""
+		if meta := e.MonitorMeta.Get(entity); meta != nil {
+			name = meta.Name
+		}
+		switch colors[entity] {
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		case resolved:
+			return nil
+		}
+		colors[entity] = visiting
+		for _, parent := range graph[entity] {
+			if !world.Alive(parent) {
+				continue
+			}
+			if err := visit(parent, append(path, name)); err != nil {
+				return err
+			}
+		}
+		colors[entity] = resolved
+		return nil
+	}
+	for entity := range graph {
+		if err := visit(entity, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnableMonitor enables a monitor using consolidated state flags
+func (e *EntityManager) EnableMonitor(entity ecs.Entity) {
+	// Remove the Disabled tag if present and schedule the first check
+	e.Disabled.Remove(entity)
+	if e.ExplicitlyDisabled.HasAll(entity) {
+		e.ExplicitlyDisabled.Remove(entity)
+	}
+	if state := e.MonitorState.Get(entity); state != nil {
+		state.SetPulseFirstCheck(true)
+	}
+}
+
+// DisableMonitor disables a monitor using consolidated state flags. The
+// disable is marked explicit so a schedule or maintenance window edge does
+// not lift it; it holds until EnableMonitor. Safe to call on a monitor a
+// window already suppressed — the marker is added alongside.
+func (e *EntityManager) DisableMonitor(entity ecs.Entity) {
+	// Add Disabled tag and clear pending flags
+	if !e.Disabled.HasAll(entity) {
+		e.Disabled.Add(entity, &components.Disabled{})
+	}
+	if !e.ExplicitlyDisabled.HasAll(entity) {
+		e.ExplicitlyDisabled.Add(entity, &components.ExplicitlyDisabled{})
+	}
+	if state := e.MonitorState.Get(entity); state != nil {
+		state.SetPulsePending(false)
+		state.SetInterventionPending(false)
+		state.SetCodePending(false)
+	}
+}
+
+// GetMonitorState provides easy access to consolidated state
+func (e *EntityManager) GetMonitorState(entity ecs.Entity) *components.MonitorState {
+	return e.MonitorState.Get(entity)
+}
+
+// GetMonitorMeta provides easy access to the cold monitor metadata.
+func (e *EntityManager) GetMonitorMeta(entity ecs.Entity) *components.MonitorMeta {
+	return e.MonitorMeta.Get(entity)
+}
+
+// MonitorInfo is the name-and-labels view of a monitor kept in the reverse
+// index for lookups from outside the tick.
+type MonitorInfo struct {
+	Name   string
+	Labels interning.Labels
+}
+
+// indexMonitor records the name-to-entity mapping for by-name lookups and
+// the reverse entity-to-info mapping for result sinks.
+func (e *EntityManager) indexMonitor(name string, entity ecs.Entity, labels interning.Labels) {
+	e.nameMu.Lock()
+	e.nameIndex[name] = entity
+	e.infoIndex[entity] = MonitorInfo{Name: name, Labels: labels}
+	e.nameMu.Unlock()
+}
+
+// LookupMonitorInfo returns the name and labels recorded for the entity.
+// Safe to call from any goroutine; like LookupMonitor, the index can
+// briefly trail entity removal during a reload.
+func (e *EntityManager) LookupMonitorInfo(entity ecs.Entity) (MonitorInfo, bool) {
+	e.nameMu.RLock()
+	info, ok := e.infoIndex[entity]
+	e.nameMu.RUnlock()
+	return info, ok
+}
+
+// LookupMonitor returns the entity created for the named monitor. Callers
+// must still check the entity is alive: the index can briefly trail entity
+// removal during a reload.
+func (e *EntityManager) LookupMonitor(name string) (ecs.Entity, bool) {
+	e.nameMu.RLock()
+	ent, ok := e.nameIndex[name]
+	e.nameMu.RUnlock()
+	return ent, ok
+}
+
+// ForgetMonitor drops the name-to-entity mapping. Called by reload paths
+// when the monitor's entity is removed from the world.
+func (e *EntityManager) ForgetMonitor(name string) {
+	e.nameMu.Lock()
+	if ent, ok := e.nameIndex[name]; ok {
+		delete(e.infoIndex, ent)
+	}
+	delete(e.nameIndex, name)
+	e.nameMu.Unlock()
+}
+
+// SetInterventionDryRun forces every intervention built from here on into
+// dry-run mode, regardless of per-monitor dry_run flags. Called before
+// loading when the controller's global override is set.
+func (e *EntityManager) SetInterventionDryRun(v bool) {
+	e.interventionDryRun = v
+}
+
+// buildInterventionChain creates one job per escalation step along with the
+// component representation of the chain. A legacy single-map intervention
+// yields a one-step chain.
+func (e *EntityManager) buildInterventionChain(intervention schema.Intervention, entity ecs.Entity) ([]jobs.Job, []components.InterventionStep, error) {
+	steps := intervention.StepList()
+	stepJobs := make([]jobs.Job, 0, len(steps))
+	cfgSteps := make([]components.InterventionStep, 0, len(steps))
+	for idx, step := range steps {
+		if e.interventionDryRun {
+			step.DryRun = true
+		}
+		job, err := jobs.CreateInterventionStepJob(step, idx, entity)
+		if err != nil {
+			return nil, nil, err
+		}
+		stepJobs = append(stepJobs, job)
+		cfgSteps = append(cfgSteps, components.InterventionStep{
+			Target:        step.Target,
+			Action:        interning.Intern(step.Action),
+			AfterFailures: step.AfterFailures,
+		})
+	}
+	return stepJobs, cfgSteps, nil
+}
+
+// registerCodeExtras registers the fan-out targets a notify group resolved
+// for one color and records them against the code config. The primary
+// target stays in the Configs array; see components.CodeConfig.Extra.
+func registerCodeExtras(codeConfig *components.CodeConfig, idx components.ColorCode, extras []schema.CodeConfig, reg *components.ConfigRegistry) {
+	for _, ex := range extras {
+		cc := components.ColorCodeConfig{
+			Dispatch: ex.Dispatch,
+			Notify:   interning.Intern(ex.Notify),
+			Cooldown: ex.Cooldown,
+			Config:   ex.Config,
+		}
+		codeConfig.Extra = append(codeConfig.Extra, components.ColorConfigID{Color: idx, ID: reg.GetOrAdd(cc)})
+	}
+}
+
+// compileMaintenanceWindows compiles the monitor's maintenance block, or
+// returns nil when no windows are configured.
+func compileMaintenanceWindows(monitor *schema.Monitor) ([]components.MaintenanceWindow, error) {
+	if len(monitor.Maintenance) == 0 {
+		return nil, nil
+	}
+	windows := make([]components.MaintenanceWindow, 0, len(monitor.Maintenance))
+	for i, w := range monitor.Maintenance {
+		cw, err := components.CompileMaintenanceWindow(w)
+		if err != nil {
+			return nil, fmt.Errorf("monitor %q maintenance window %d: %w", monitor.Name, i, err)
+		}
+		windows = append(windows, cw)
+	}
+	return windows, nil
+}
+
+// compileSchedule compiles the monitor's pulse schedule block, or returns
+// nil when the monitor runs around the clock.
+func compileSchedule(monitor *schema.Monitor) (*components.Schedule, error) {
+	if monitor.Pulse.Schedule == nil {
+		return nil, nil
+	}
+	sched, err := components.CompileSchedule(*monitor.Pulse.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("monitor %q: %w", monitor.Name, err)
+	}
+	return &sched, nil
+}
+
+// compileSLO compiles the monitor's slo block, or returns nil when no
+// objective is configured.
+func compileSLO(monitor *schema.Monitor) (*components.SLO, error) {
+	if monitor.SLO == nil {
+		return nil, nil
+	}
+	slo, err := components.CompileSLO(*monitor.SLO)
+	if err != nil {
+		return nil, fmt.Errorf("monitor %q: %w", monitor.Name, err)
+	}
+	return slo, nil
+}
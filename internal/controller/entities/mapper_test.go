@@ -0,0 +1,850 @@
+package entities
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Test helpers
+
+func newTestWorld() *ecs.World {
+	a := app.New(64)
+	return &a.World
+}
+
+func newTestMonitor(name string) *schema.Monitor {
+	return &schema.Monitor{
+		Name:    name,
+		Enabled: true,
+		Pulse: schema.Pulse{
+			Type:     "http",
+			Interval: 30 * time.Second,
+			Timeout:  5 * time.Second,
+			Config:   &schema.PulseHTTPConfig{Url: "http://localhost"},
+		},
+	}
+}
+
+// Pool function tests
+
+func TestGetPutMonitorState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GetReturnsValidPointer", func(t *testing.T) {
+		m := GetMonitorState()
+		if m == nil {
+			t.Fatal("GetMonitorState returned nil")
+		}
+	})
+
+	t.Run("PutHandlesNil", func(t *testing.T) {
+		// Should not panic
+		PutMonitorState(nil)
+	})
+
+	t.Run("PutResetsState", func(t *testing.T) {
+		m := GetMonitorState()
+		m.PulseFailures = 2
+		m.ConsecutiveFailures = 5
+		PutMonitorState(m)
+
+		// Get again - may or may not be the same instance
+		m2 := GetMonitorState()
+		if m2.PulseFailures != 0 {
+			t.Error("MonitorState.PulseFailures should be reset")
+		}
+		if m2.ConsecutiveFailures != 0 {
+			t.Error("MonitorState.ConsecutiveFailures should be reset")
+		}
+		PutMonitorState(m2)
+	})
+
+	t.Run("PutResetsMeta", func(t *testing.T) {
+		m := GetMonitorMeta()
+		m.Name = "test"
+		PutMonitorMeta(m)
+
+		m2 := GetMonitorMeta()
+		if m2.Name != "" {
+			t.Error("MonitorMeta.Name should be reset")
+		}
+		PutMonitorMeta(m2)
+	})
+}
+
+func TestGetPutPulseConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GetReturnsValidPointer", func(t *testing.T) {
+		p := GetPulseConfig()
+		if p == nil {
+			t.Fatal("GetPulseConfig returned nil")
+		}
+		PutPulseConfig(p)
+	})
+
+	t.Run("PutHandlesNil", func(t *testing.T) {
+		PutPulseConfig(nil)
+	})
+
+	t.Run("PutResetsConfig", func(t *testing.T) {
+		p := GetPulseConfig()
+		p.Type = "http"
+		p.Interval = 30 * time.Second
+		PutPulseConfig(p)
+
+		p2 := GetPulseConfig()
+		if p2.Type != "" {
+			t.Error("PulseConfig.Type should be reset")
+		}
+		if p2.Interval != 0 {
+			t.Error("PulseConfig.Interval should be reset")
+		}
+		PutPulseConfig(p2)
+	})
+}
+
+func TestGetPutInterventionConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GetReturnsValidPointer", func(t *testing.T) {
+		i := GetInterventionConfig()
+		if i == nil {
+			t.Fatal("GetInterventionConfig returned nil")
+		}
+		PutInterventionConfig(i)
+	})
+
+	t.Run("PutHandlesNil", func(t *testing.T) {
+		PutInterventionConfig(nil)
+	})
+
+	t.Run("PutResetsConfig", func(t *testing.T) {
+		i := GetInterventionConfig()
+		i.Action = "restart"
+		i.MaxFailures = 3
+		PutInterventionConfig(i)
+
+		i2 := GetInterventionConfig()
+		if i2.Action != "" {
+			t.Error("InterventionConfig.Action should be reset")
+		}
+		if i2.MaxFailures != 0 {
+			t.Error("InterventionConfig.MaxFailures should be reset")
+		}
+		PutInterventionConfig(i2)
+	})
+}
+
+func TestGetPutCodeConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GetReturnsValidPointer", func(t *testing.T) {
+		c := GetCodeConfig(4)
+		if c == nil {
+			t.Fatal("GetCodeConfig returned nil")
+		}
+		PutCodeConfig(c)
+	})
+
+	t.Run("PutHandlesNil", func(t *testing.T) {
+		PutCodeConfig(nil)
+	})
+
+	t.Run("GetReturnsZeroedArray", func(t *testing.T) {
+		c := GetCodeConfig(4)
+		for i := range c.Configs {
+			if c.Configs[i] != 0 {
+				t.Errorf("CodeConfig.Configs[%d] should be 0", i)
+			}
+		}
+		PutCodeConfig(c)
+	})
+}
+
+func TestGetPutCodeStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GetReturnsValidPointer", func(t *testing.T) {
+		s := GetCodeStatus(4)
+		if s == nil {
+			t.Fatal("GetCodeStatus returned nil")
+		}
+		PutCodeStatus(s)
+	})
+
+	t.Run("PutHandlesNil", func(t *testing.T) {
+		PutCodeStatus(nil)
+	})
+
+	t.Run("GetReturnsZeroedArray", func(t *testing.T) {
+		s := GetCodeStatus(4)
+		for i := range s.Status {
+			if s.Status[i].ConsecutiveFailures != 0 {
+				t.Errorf("CodeStatus.Status[%d].ConsecutiveFailures should be 0", i)
+			}
+		}
+		PutCodeStatus(s)
+	})
+}
+
+func TestGetPutJobStorage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GetReturnsValidPointer", func(t *testing.T) {
+		j := GetJobStorage()
+		if j == nil {
+			t.Fatal("GetJobStorage returned nil")
+		}
+		PutJobStorage(j)
+	})
+
+	t.Run("PutHandlesNil", func(t *testing.T) {
+		PutJobStorage(nil)
+	})
+
+	t.Run("GetReturnsEmptyStorage", func(t *testing.T) {
+		j := GetJobStorage()
+		if j.PulseJob != nil {
+			t.Error("JobStorage.PulseJob should be nil")
+		}
+		if j.InterventionJob != nil {
+			t.Error("JobStorage.InterventionJob should be nil")
+		}
+		PutJobStorage(j)
+	})
+}
+
+func TestGetPutColorCodeStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GetReturnsValidPointer", func(t *testing.T) {
+		c := GetColorCodeStatus()
+		if c == nil {
+			t.Fatal("GetColorCodeStatus returned nil")
+		}
+	})
+
+	t.Run("PutIsNoOp", func(t *testing.T) {
+		c := GetColorCodeStatus()
+		// Should not panic - this is a no-op
+		PutColorCodeStatus(c)
+		PutColorCodeStatus(nil)
+	})
+}
+
+func TestPoolConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const goroutines = 10
+	const iterations = 100
+
+	var wg sync.WaitGroup
+
+	// Test MonitorState pool concurrently
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				m := GetMonitorState()
+				m.ConsecutiveFailures = 1
+				PutMonitorState(m)
+			}
+		}()
+	}
+
+	// Test PulseConfig pool concurrently
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				p := GetPulseConfig()
+				p.Type = "http"
+				PutPulseConfig(p)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// EntityManager tests
+
+func TestNewEntityManager(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	if em == nil {
+		t.Fatal("NewEntityManager returned nil")
+	}
+
+	// Check all mappers are initialized
+	if em.MonitorState == nil {
+		t.Error("MonitorState mapper not initialized")
+	}
+	if em.PulseConfig == nil {
+		t.Error("PulseConfig mapper not initialized")
+	}
+	if em.InterventionConfig == nil {
+		t.Error("InterventionConfig mapper not initialized")
+	}
+	if em.CodeConfig == nil {
+		t.Error("CodeConfig mapper not initialized")
+	}
+	if em.CodeStatus == nil {
+		t.Error("CodeStatus mapper not initialized")
+	}
+	if em.JobStorage == nil {
+		t.Error("JobStorage mapper not initialized")
+	}
+	if em.Shard == nil {
+		t.Error("Shard mapper not initialized")
+	}
+	if em.Disabled == nil {
+		t.Error("Disabled mapper not initialized")
+	}
+
+	// Check default shard slots
+	if em.shardSlots != components.DefaultShardSlots {
+		t.Errorf("shardSlots = %d, want %d", em.shardSlots, components.DefaultShardSlots)
+	}
+}
+
+func TestSetShardSlots(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	t.Run("PositiveValue", func(t *testing.T) {
+		em.SetShardSlots(50)
+		if em.shardSlots != 50 {
+			t.Errorf("shardSlots = %d, want 50", em.shardSlots)
+		}
+	})
+
+	t.Run("ZeroFallsBackToDefault", func(t *testing.T) {
+		em.SetShardSlots(0)
+		if em.shardSlots != components.DefaultShardSlots {
+			t.Errorf("shardSlots = %d, want %d", em.shardSlots, components.DefaultShardSlots)
+		}
+	})
+
+	t.Run("NegativeFallsBackToDefault", func(t *testing.T) {
+		em.SetShardSlots(-10)
+		if em.shardSlots != components.DefaultShardSlots {
+			t.Errorf("shardSlots = %d, want %d", em.shardSlots, components.DefaultShardSlots)
+		}
+	})
+}
+
+func TestCreateEntityFromMonitor_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NilWorld", func(t *testing.T) {
+		world := newTestWorld()
+		em := NewEntityManager(world)
+		monitor := newTestMonitor("test")
+
+		err := em.CreateEntityFromMonitor(monitor, nil)
+		if err == nil {
+			t.Error("expected error for nil world")
+		}
+	})
+
+	t.Run("NilManager", func(t *testing.T) {
+		world := newTestWorld()
+		monitor := newTestMonitor("test")
+
+		var em *EntityManager
+		err := em.CreateEntityFromMonitor(monitor, world)
+		if err == nil {
+			t.Error("expected error for nil manager")
+		}
+	})
+
+	t.Run("EmptyName", func(t *testing.T) {
+		world := newTestWorld()
+		em := NewEntityManager(world)
+		monitor := newTestMonitor("")
+
+		err := em.CreateEntityFromMonitor(monitor, world)
+		if err == nil {
+			t.Error("expected error for empty name")
+		}
+	})
+}
+
+func TestCreateEntityFromMonitor_ValidMonitor(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+	monitor := newTestMonitor("test-monitor")
+
+	err := em.CreateEntityFromMonitor(monitor, world)
+	if err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+}
+
+func TestCreateEntityFromMonitor_DisabledMonitor(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+	monitor := newTestMonitor("disabled-monitor")
+	monitor.Enabled = false
+
+	err := em.CreateEntityFromMonitor(monitor, world)
+	if err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+}
+
+func TestCreateEntityFromMonitor_WithIntervention(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+	monitor := newTestMonitor("intervention-monitor")
+	monitor.Intervention = schema.Intervention{
+		Action:  "docker",
+		Retries: 3,
+		Target: &schema.InterventionTargetDocker{
+			Type:      "restart",
+			Container: "my-container",
+		},
+	}
+
+	err := em.CreateEntityFromMonitor(monitor, world)
+	if err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+}
+
+func TestCreateEntityFromMonitor_WithCodes(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+	monitor := newTestMonitor("code-monitor")
+	monitor.Codes = schema.Codes{
+		"red": {
+			Notify:   "log",
+			Dispatch: true,
+			Config:   &schema.CodeNotificationLog{File: "/var/log/alerts.log"},
+		},
+		"green": {
+			Notify:   "log",
+			Dispatch: false,
+			Config:   &schema.CodeNotificationLog{File: "/var/log/recovery.log"},
+		},
+	}
+
+	err := em.CreateEntityFromMonitor(monitor, world)
+	if err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+}
+
+func TestCreateEntitiesFromMonitors_Empty(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	err := em.CreateEntitiesFromMonitors(world, nil)
+	if err != nil {
+		t.Errorf("expected nil error for empty monitors, got %v", err)
+	}
+
+	err = em.CreateEntitiesFromMonitors(world, []schema.Monitor{})
+	if err != nil {
+		t.Errorf("expected nil error for empty slice, got %v", err)
+	}
+}
+
+func TestCreateEntitiesFromMonitors_Batch(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	monitors := []schema.Monitor{
+		*newTestMonitor("monitor-1"),
+		*newTestMonitor("monitor-2"),
+		*newTestMonitor("monitor-3"),
+	}
+
+	err := em.CreateEntitiesFromMonitors(world, monitors)
+	if err != nil {
+		t.Fatalf("CreateEntitiesFromMonitors failed: %v", err)
+	}
+}
+
+func TestCreateEntitiesFromMonitors_NilWorld(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	monitors := []schema.Monitor{
+		*newTestMonitor("monitor-1"),
+	}
+
+	err := em.CreateEntitiesFromMonitors(nil, monitors)
+	if err == nil {
+		t.Error("expected error for nil world")
+	}
+}
+
+func TestCreateEntitiesFromMonitors_NilManager(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+
+	monitors := []schema.Monitor{
+		*newTestMonitor("monitor-1"),
+	}
+
+	var em *EntityManager
+	err := em.CreateEntitiesFromMonitors(world, monitors)
+	if err == nil {
+		t.Error("expected error for nil manager")
+	}
+}
+
+// findEntityByName returns the entity whose MonitorMeta carries the name.
+func findEntityByName(world *ecs.World, name string) (ecs.Entity, bool) {
+	query := ecs.NewFilter1[components.MonitorMeta](world).Query()
+	for query.Next() {
+		if query.Get().Name == name {
+			ent := query.Entity()
+			query.Close()
+			return ent, true
+		}
+	}
+	return ecs.Entity{}, false
+}
+
+func TestCreateEntityFromMonitor_GenerationStamping(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+	em.SetGeneration(3)
+
+	monitor := newTestMonitor("stamped-monitor")
+	monitor.Intervention = schema.Intervention{
+		Action: "docker",
+		Target: &schema.InterventionTargetDocker{Type: "restart", Container: "c"},
+	}
+	monitor.Codes = schema.Codes{
+		"red": {Notify: "log", Dispatch: true},
+	}
+
+	if err := em.CreateEntityFromMonitor(monitor, world); err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+	ent, ok := findEntityByName(world, "stamped-monitor")
+	if !ok {
+		t.Fatal("entity not found")
+	}
+
+	if got := em.PulseConfig.Get(ent).Generation; got != 3 {
+		t.Errorf("PulseConfig generation = %d, want 3", got)
+	}
+	if got := em.InterventionConfig.Get(ent).Generation; got != 3 {
+		t.Errorf("InterventionConfig generation = %d, want 3", got)
+	}
+	if got := em.CodeConfig.Get(ent).Generation; got != 3 {
+		t.Errorf("CodeConfig generation = %d, want 3", got)
+	}
+}
+
+func TestApplyMonitorConfig_HealsInterruptedUpdate(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+	em.SetGeneration(1)
+
+	monitor := newTestMonitor("interrupted-monitor")
+	monitor.Codes = schema.Codes{
+		"red": {Notify: "log", Dispatch: true},
+	}
+	if err := em.CreateEntityFromMonitor(monitor, world); err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+	ent, ok := findEntityByName(world, "interrupted-monitor")
+	if !ok {
+		t.Fatal("entity not found")
+	}
+
+	// Simulate an update interrupted after the first component swap: the
+	// pulse config reached generation 2, the code config did not.
+	em.SetGeneration(2)
+	em.PulseConfig.Get(ent).Generation = 2
+	if got := em.CodeConfig.Get(ent).Generation; got != 1 {
+		t.Fatalf("CodeConfig generation = %d, want stale 1", got)
+	}
+
+	// Healing re-applies the latest manifest's config wholesale.
+	updated := newTestMonitor("interrupted-monitor")
+	updated.Pulse.Interval = 60 * time.Second
+	updated.Codes = schema.Codes{
+		"red": {Notify: "log", Dispatch: true},
+	}
+	if err := em.ApplyMonitorConfig(world, ent, updated); err != nil {
+		t.Fatalf("ApplyMonitorConfig failed: %v", err)
+	}
+
+	if got := em.PulseConfig.Get(ent).Generation; got != 2 {
+		t.Errorf("PulseConfig generation = %d, want 2", got)
+	}
+	if got := em.CodeConfig.Get(ent).Generation; got != 2 {
+		t.Errorf("CodeConfig generation = %d, want 2", got)
+	}
+	if got := em.PulseConfig.Get(ent).Interval; got != 60*time.Second {
+		t.Errorf("PulseConfig interval = %v, want 60s", got)
+	}
+}
+
+func TestApplyMonitorConfig_AddsAndRemovesOptionalComponents(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	if err := em.CreateEntityFromMonitor(newTestMonitor("optional-monitor"), world); err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+	ent, ok := findEntityByName(world, "optional-monitor")
+	if !ok {
+		t.Fatal("entity not found")
+	}
+
+	// Update adds an intervention the original monitor did not have.
+	withIntervention := newTestMonitor("optional-monitor")
+	withIntervention.Intervention = schema.Intervention{
+		Action: "docker",
+		Target: &schema.InterventionTargetDocker{Type: "restart", Container: "c"},
+	}
+	if err := em.ApplyMonitorConfig(world, ent, withIntervention); err != nil {
+		t.Fatalf("ApplyMonitorConfig failed: %v", err)
+	}
+	if !em.InterventionConfig.HasAll(ent) {
+		t.Fatal("expected intervention config to be added")
+	}
+
+	// A later update without intervention removes the component again.
+	if err := em.ApplyMonitorConfig(world, ent, newTestMonitor("optional-monitor")); err != nil {
+		t.Fatalf("ApplyMonitorConfig failed: %v", err)
+	}
+	if em.InterventionConfig.HasAll(ent) {
+		t.Error("expected intervention config to be removed")
+	}
+}
+
+func TestMonitorLabels_CreateAndReload(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	monitor := newTestMonitor("labeled-monitor")
+	monitor.Labels = map[string]string{"team": "payments", "tier": "1"}
+	if err := em.CreateEntityFromMonitor(monitor, world); err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+	ent, ok := findEntityByName(world, "labeled-monitor")
+	if !ok {
+		t.Fatal("entity not found")
+	}
+
+	meta := em.MonitorMeta.Get(ent)
+	if v, ok := meta.Labels.Get("team"); !ok || v != "payments" {
+		t.Errorf("labels after create = %v, want team=payments", meta.Labels)
+	}
+
+	// A reload replaces the set wholesale: dropped keys disappear.
+	updated := newTestMonitor("labeled-monitor")
+	updated.Labels = map[string]string{"team": "platform"}
+	if err := em.ApplyMonitorConfig(world, ent, updated); err != nil {
+		t.Fatalf("ApplyMonitorConfig failed: %v", err)
+	}
+	meta = em.MonitorMeta.Get(ent)
+	if v, ok := meta.Labels.Get("team"); !ok || v != "platform" {
+		t.Errorf("labels after reload = %v, want team=platform", meta.Labels)
+	}
+	if _, ok := meta.Labels.Get("tier"); ok {
+		t.Error("reload should drop labels removed from the manifest")
+	}
+
+	// A reload without labels clears them entirely.
+	if err := em.ApplyMonitorConfig(world, ent, newTestMonitor("labeled-monitor")); err != nil {
+		t.Fatalf("ApplyMonitorConfig failed: %v", err)
+	}
+	if got := em.MonitorMeta.Get(ent).Labels; got != nil {
+		t.Errorf("labels after unlabeled reload = %v, want nil", got)
+	}
+}
+
+// Dependency resolution tests
+
+func TestResolveDependencies_ResolvesNames(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	parent := newTestMonitor("router")
+	child := newTestMonitor("web-1")
+	child.DependsOn = []string{"router"}
+	// Forward reference: the child is created before its parent.
+	if err := em.CreateEntitiesFromMonitors(world, []schema.Monitor{*child, *parent}); err != nil {
+		t.Fatalf("CreateEntitiesFromMonitors failed: %v", err)
+	}
+	if err := em.ResolveDependencies(world); err != nil {
+		t.Fatalf("ResolveDependencies failed: %v", err)
+	}
+
+	childEnt, _ := em.LookupMonitor("web-1")
+	parentEnt, _ := em.LookupMonitor("router")
+	if !em.DependsOn.HasAll(childEnt) {
+		t.Fatal("child entity missing DependsOn component")
+	}
+	parents := em.DependsOn.Get(childEnt).Parents
+	if len(parents) != 1 || parents[0] != parentEnt {
+		t.Errorf("Parents = %v, want [%v]", parents, parentEnt)
+	}
+	if em.DependsOn.HasAll(parentEnt) {
+		t.Error("parent without depends_on must not get a DependsOn component")
+	}
+}
+
+func TestResolveDependencies_UnknownNameIsError(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	child := newTestMonitor("web-1")
+	child.DependsOn = []string{"no-such-monitor"}
+	if err := em.CreateEntityFromMonitor(child, world); err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+	if err := em.ResolveDependencies(world); err == nil {
+		t.Fatal("expected unknown dependency name to be a load error")
+	}
+}
+
+func TestResolveDependencies_CycleIsError(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	a := newTestMonitor("a")
+	a.DependsOn = []string{"b"}
+	b := newTestMonitor("b")
+	b.DependsOn = []string{"c"}
+	c := newTestMonitor("c")
+	c.DependsOn = []string{"a"}
+	if err := em.CreateEntitiesFromMonitors(world, []schema.Monitor{*a, *b, *c}); err != nil {
+		t.Fatalf("CreateEntitiesFromMonitors failed: %v", err)
+	}
+	if err := em.ResolveDependencies(world); err == nil {
+		t.Fatal("expected dependency cycle to be a load error")
+	}
+
+	// Self-dependency is the smallest cycle.
+	world2 := newTestWorld()
+	em2 := NewEntityManager(world2)
+	selfish := newTestMonitor("selfish")
+	selfish.DependsOn = []string{"selfish"}
+	if err := em2.CreateEntityFromMonitor(selfish, world2); err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+	if err := em2.ResolveDependencies(world2); err == nil {
+		t.Fatal("expected self-dependency to be a load error")
+	}
+}
+
+func TestResolveDependencies_ReloadDropsList(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+
+	parent := newTestMonitor("router")
+	child := newTestMonitor("web-1")
+	child.DependsOn = []string{"router"}
+	if err := em.CreateEntitiesFromMonitors(world, []schema.Monitor{*parent, *child}); err != nil {
+		t.Fatalf("CreateEntitiesFromMonitors failed: %v", err)
+	}
+	if err := em.ResolveDependencies(world); err != nil {
+		t.Fatalf("ResolveDependencies failed: %v", err)
+	}
+
+	childEnt, _ := em.LookupMonitor("web-1")
+	child.DependsOn = nil
+	if err := em.ApplyMonitorConfig(world, childEnt, child); err != nil {
+		t.Fatalf("ApplyMonitorConfig failed: %v", err)
+	}
+	if err := em.ResolveDependencies(world); err != nil {
+		t.Fatalf("ResolveDependencies after reload failed: %v", err)
+	}
+	if em.DependsOn.HasAll(childEnt) {
+		t.Error("dropping depends_on on reload must remove the component")
+	}
+}
+
+// TestSetInterventionDryRun_GlobalOverride verifies the controller-level
+// override wraps every intervention job in a dry-run shell even when the
+// monitor's manifest never asked for it.
+func TestSetInterventionDryRun_GlobalOverride(t *testing.T) {
+	t.Parallel()
+
+	world := newTestWorld()
+	em := NewEntityManager(world)
+	em.SetInterventionDryRun(true)
+
+	monitor := newTestMonitor("global-dry-run")
+	monitor.Intervention = schema.Intervention{
+		Action: "exec",
+		Target: &schema.InterventionTargetExec{Command: "echo", Args: []string{"restart"}},
+	}
+	if err := em.CreateEntityFromMonitor(monitor, world); err != nil {
+		t.Fatalf("CreateEntityFromMonitor failed: %v", err)
+	}
+
+	entity, ok := em.LookupMonitor("global-dry-run")
+	if !ok {
+		t.Fatal("monitor not indexed")
+	}
+	storage := em.JobStorage.Get(entity)
+	if storage == nil || len(storage.InterventionJobs) == 0 {
+		t.Fatal("no intervention jobs built")
+	}
+	stepJob, ok := storage.InterventionJobs[0].(*jobs.InterventionStepJob)
+	if !ok {
+		t.Fatalf("job is %T, want *jobs.InterventionStepJob", storage.InterventionJobs[0])
+	}
+	if _, ok := stepJob.Job.(*jobs.DryRunJob); !ok {
+		t.Errorf("wrapped job is %T, want *jobs.DryRunJob (global override)", stepJob.Job)
+	}
+}
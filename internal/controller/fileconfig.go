@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"cpra/internal/queue"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig mirrors the tunable subset of Config for the -config YAML file,
+// so deployments can adjust queue capacity, worker pool bounds, and sizing
+// SLOs without a recompile. Durations are strings in Go duration syntax
+// ("250ms", "10s"). Absent fields keep their defaults; the CPRA_SIZING_* env
+// vars are read at use time and still win over file values.
+type FileConfig struct {
+	QueueCapacity    uint64 `yaml:"queue_capacity"`
+	BatchSize        int    `yaml:"batch_size"`
+	HistorySize      *int   `yaml:"history_size"`
+	ShardSlots       int    `yaml:"shard_slots"`
+	ShardTargetSweep string `yaml:"shard_target_sweep"`
+	WatchDebounce    string `yaml:"watch_debounce"`
+	// "0s" disables periodic stats reporting entirely.
+	StatsReportInterval string `yaml:"stats_report_interval"`
+
+	Sizing struct {
+		ServiceTime string  `yaml:"service_time"`
+		SLO         string  `yaml:"slo"`
+		HeadroomPct float64 `yaml:"headroom_pct"`
+		Ramp        string  `yaml:"ramp"`
+	} `yaml:"sizing"`
+
+	Workers struct {
+		MinWorkers         int    `yaml:"min_workers"`
+		MaxWorkers         int    `yaml:"max_workers"`
+		AdjustmentInterval string `yaml:"adjustment_interval"`
+		ResultBatchSize    int    `yaml:"result_batch_size"`
+		TargetQueueLatency string `yaml:"target_queue_latency"`
+	} `yaml:"workers"`
+
+	Pipeline struct {
+		Workers              int   `yaml:"workers"`
+		BatchSize            int   `yaml:"batch_size"`
+		StreamingMode        *bool `yaml:"streaming_mode"`
+		StrictUnknownFields  *bool `yaml:"strict_unknown_fields"`
+		MaxGeneratedMonitors int   `yaml:"max_generated_monitors"`
+		HighFrequencyBudget  *int  `yaml:"high_frequency_budget"`
+	} `yaml:"pipeline"`
+
+	GroupLimits map[string]int `yaml:"group_limits"`
+}
+
+// Known keys per config file section, used to warn (not fail) on unknown
+// keys so older binaries tolerate config files written for newer ones.
+var configFileKeys = map[string]map[string]struct{}{
+	"": {
+		"queue_capacity": {}, "batch_size": {}, "history_size": {},
+		"shard_slots": {}, "shard_target_sweep": {}, "watch_debounce": {},
+		"stats_report_interval": {},
+		"sizing":                {}, "workers": {}, "pipeline": {}, "group_limits": {},
+	},
+	"sizing": {
+		"service_time": {}, "slo": {}, "headroom_pct": {}, "ramp": {},
+	},
+	"workers": {
+		"min_workers": {}, "max_workers": {}, "adjustment_interval": {},
+		"result_batch_size": {}, "target_queue_latency": {},
+	},
+	"pipeline": {
+		"workers": {}, "batch_size": {}, "streaming_mode": {},
+		"strict_unknown_fields": {}, "max_generated_monitors": {},
+		"high_frequency_budget": {},
+	},
+}
+
+// ApplyConfigFile overlays the YAML config file at path onto config. Only
+// fields present in the file are changed, so a partial file tunes a few
+// knobs and leaves the rest at DefaultConfig values. Out-of-range values
+// (non-power-of-two queue capacity, min workers above max) fail the load;
+// unknown keys only warn.
+func ApplyConfigFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	warnUnknownConfigKeys(&doc, "")
+
+	var fc FileConfig
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return fc.apply(config)
+}
+
+// warnUnknownConfigKeys walks one mapping level (and the known nested
+// sections) logging unknown keys, so typos are visible without making every
+// new key a hard break for older binaries.
+func warnUnknownConfigKeys(node *yaml.Node, section string) {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	known := configFileKeys[section]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if _, ok := known[key.Value]; !ok {
+			if SystemLogger != nil {
+				SystemLogger.Warnf("Config file: unknown key %q at line %d (ignored)", key.Value, key.Line)
+			}
+			continue
+		}
+		if _, nested := configFileKeys[key.Value]; nested && section == "" {
+			warnUnknownConfigKeys(value, key.Value)
+		}
+	}
+}
+
+// apply validates the file values and overlays them onto config.
+func (fc *FileConfig) apply(config *Config) error {
+	if fc.QueueCapacity > 0 {
+		if fc.QueueCapacity&(fc.QueueCapacity-1) != 0 {
+			return fmt.Errorf("config field \"queue_capacity\": %d is not a power of two", fc.QueueCapacity)
+		}
+		config.QueueCapacity = fc.QueueCapacity
+	}
+	if fc.BatchSize < 0 {
+		return fmt.Errorf("config field \"batch_size\": must not be negative")
+	}
+	if fc.BatchSize > 0 {
+		config.BatchSize = fc.BatchSize
+	}
+	if fc.HistorySize != nil {
+		config.HistorySize = *fc.HistorySize
+	}
+	if fc.ShardSlots > 0 {
+		config.ShardSlots = fc.ShardSlots
+	}
+	if err := setConfigDuration(&config.ShardTargetSweep, fc.ShardTargetSweep, "shard_target_sweep"); err != nil {
+		return err
+	}
+	if err := setConfigDuration(&config.WatchDebounce, fc.WatchDebounce, "watch_debounce"); err != nil {
+		return err
+	}
+	if err := setConfigDuration(&config.StatsReportInterval, fc.StatsReportInterval, "stats_report_interval"); err != nil {
+		return err
+	}
+
+	if err := setConfigDuration(&config.SizingServiceTime, fc.Sizing.ServiceTime, "sizing.service_time"); err != nil {
+		return err
+	}
+	if err := setConfigDuration(&config.SizingSLO, fc.Sizing.SLO, "sizing.slo"); err != nil {
+		return err
+	}
+	if fc.Sizing.HeadroomPct < 0 {
+		return fmt.Errorf("config field \"sizing.headroom_pct\": must not be negative")
+	}
+	if fc.Sizing.HeadroomPct > 0 {
+		config.SizingHeadroomPct = fc.Sizing.HeadroomPct
+	}
+	if err := setConfigDuration(&config.SizingRamp, fc.Sizing.Ramp, "sizing.ramp"); err != nil {
+		return err
+	}
+
+	// The workers section tunes all three pools alike; per-pool asymmetry
+	// is available programmatically via the Config fields.
+	if fc.Workers.MinWorkers < 0 || fc.Workers.MaxWorkers < 0 {
+		return fmt.Errorf("config fields \"workers.min_workers\"/\"workers.max_workers\": must not be negative")
+	}
+	for _, wc := range []*queue.WorkerPoolConfig{
+		&config.PulseWorkerConfig, &config.InterventionWorkerConfig, &config.CodeWorkerConfig,
+	} {
+		if fc.Workers.MinWorkers > 0 {
+			wc.MinWorkers = fc.Workers.MinWorkers
+		}
+		if fc.Workers.MaxWorkers > 0 {
+			wc.MaxWorkers = fc.Workers.MaxWorkers
+		}
+		if wc.MinWorkers > wc.MaxWorkers {
+			return fmt.Errorf("config field \"workers.min_workers\": %d exceeds max_workers %d",
+				wc.MinWorkers, wc.MaxWorkers)
+		}
+		if err := setConfigDuration(&wc.AdjustmentInterval, fc.Workers.AdjustmentInterval, "workers.adjustment_interval"); err != nil {
+			return err
+		}
+		if fc.Workers.ResultBatchSize > 0 {
+			wc.ResultBatchSize = fc.Workers.ResultBatchSize
+		}
+		if err := setConfigDuration(&wc.TargetQueueLatency, fc.Workers.TargetQueueLatency, "workers.target_queue_latency"); err != nil {
+			return err
+		}
+	}
+
+	if fc.Pipeline.Workers > 0 {
+		config.PipelineConfig.Workers = fc.Pipeline.Workers
+	}
+	if fc.Pipeline.BatchSize > 0 {
+		config.PipelineConfig.BatchSize = fc.Pipeline.BatchSize
+	}
+	if fc.Pipeline.StreamingMode != nil {
+		config.PipelineConfig.StreamingMode = *fc.Pipeline.StreamingMode
+	}
+	if fc.Pipeline.StrictUnknownFields != nil {
+		config.PipelineConfig.StrictUnknownFields = *fc.Pipeline.StrictUnknownFields
+	}
+	if fc.Pipeline.MaxGeneratedMonitors > 0 {
+		config.PipelineConfig.MaxGeneratedMonitors = fc.Pipeline.MaxGeneratedMonitors
+	}
+	if fc.Pipeline.HighFrequencyBudget != nil {
+		config.PipelineConfig.HighFrequencyBudget = *fc.Pipeline.HighFrequencyBudget
+	}
+
+	if len(fc.GroupLimits) > 0 {
+		config.GroupLimits = fc.GroupLimits
+	}
+	return nil
+}
+
+// setConfigDuration parses a Go duration string from the config file into
+// dst. An empty string leaves dst unchanged.
+func setConfigDuration(dst *time.Duration, value, field string) error {
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("config field %q: %w", field, err)
+	}
+	if d < 0 {
+		return fmt.Errorf("config field %q: must not be negative", field)
+	}
+	*dst = d
+	return nil
+}
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes a config file to a temp dir and returns its path.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cpra.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+// TestApplyConfigFile_FullRoundTrip covers every section of the config file.
+func TestApplyConfigFile_FullRoundTrip(t *testing.T) {
+	t.Parallel()
+	path := writeConfigFile(t, `queue_capacity: 16384
+batch_size: 2000
+history_size: 0
+shard_slots: 8
+shard_target_sweep: 20s
+watch_debounce: 3s
+sizing:
+  service_time: 50ms
+  slo: 250ms
+  headroom_pct: 0.25
+  ramp: 90s
+workers:
+  min_workers: 10
+  max_workers: 100
+  adjustment_interval: 10s
+  result_batch_size: 256
+  target_queue_latency: 200ms
+pipeline:
+  workers: 64
+  batch_size: 5000
+  streaming_mode: false
+  strict_unknown_fields: true
+  max_generated_monitors: 500
+  high_frequency_budget: 16
+group_limits:
+  default: 100
+  slow-backend: 5
+`)
+
+	config := DefaultConfig()
+	if err := ApplyConfigFile(path, &config); err != nil {
+		t.Fatalf("ApplyConfigFile failed: %v", err)
+	}
+
+	if config.QueueCapacity != 16384 {
+		t.Errorf("QueueCapacity = %d, want 16384", config.QueueCapacity)
+	}
+	if config.BatchSize != 2000 {
+		t.Errorf("BatchSize = %d, want 2000", config.BatchSize)
+	}
+	if config.HistorySize != 0 {
+		t.Errorf("HistorySize = %d, want 0 (explicitly disabled)", config.HistorySize)
+	}
+	if config.ShardSlots != 8 || config.ShardTargetSweep != 20*time.Second {
+		t.Errorf("Shard tuning = %d/%v, want 8/20s", config.ShardSlots, config.ShardTargetSweep)
+	}
+	if config.WatchDebounce != 3*time.Second {
+		t.Errorf("WatchDebounce = %v, want 3s", config.WatchDebounce)
+	}
+	if config.SizingServiceTime != 50*time.Millisecond || config.SizingSLO != 250*time.Millisecond || config.SizingHeadroomPct != 0.25 {
+		t.Errorf("Sizing = %v/%v/%v, want 50ms/250ms/0.25",
+			config.SizingServiceTime, config.SizingSLO, config.SizingHeadroomPct)
+	}
+	if config.SizingRamp != 90*time.Second {
+		t.Errorf("SizingRamp = %v, want 90s", config.SizingRamp)
+	}
+	if config.PulseWorkerConfig.MinWorkers != 10 || config.PulseWorkerConfig.MaxWorkers != 100 {
+		t.Errorf("Worker bounds = %d/%d, want 10/100",
+			config.PulseWorkerConfig.MinWorkers, config.PulseWorkerConfig.MaxWorkers)
+	}
+	if config.PulseWorkerConfig.AdjustmentInterval != 10*time.Second || config.PulseWorkerConfig.ResultBatchSize != 256 {
+		t.Errorf("Worker tuning = %v/%d, want 10s/256",
+			config.PulseWorkerConfig.AdjustmentInterval, config.PulseWorkerConfig.ResultBatchSize)
+	}
+	if config.PulseWorkerConfig.TargetQueueLatency != 200*time.Millisecond {
+		t.Errorf("TargetQueueLatency = %v, want 200ms", config.PulseWorkerConfig.TargetQueueLatency)
+	}
+	if config.PipelineConfig.Workers != 64 || config.PipelineConfig.BatchSize != 5000 {
+		t.Errorf("Pipeline = %d/%d, want 64/5000",
+			config.PipelineConfig.Workers, config.PipelineConfig.BatchSize)
+	}
+	if config.PipelineConfig.StreamingMode || !config.PipelineConfig.StrictUnknownFields {
+		t.Errorf("Pipeline flags = %v/%v, want false/true",
+			config.PipelineConfig.StreamingMode, config.PipelineConfig.StrictUnknownFields)
+	}
+	if config.PipelineConfig.MaxGeneratedMonitors != 500 || config.PipelineConfig.HighFrequencyBudget != 16 {
+		t.Errorf("Pipeline caps = %d/%d, want 500/16",
+			config.PipelineConfig.MaxGeneratedMonitors, config.PipelineConfig.HighFrequencyBudget)
+	}
+	if config.GroupLimits["default"] != 100 || config.GroupLimits["slow-backend"] != 5 {
+		t.Errorf("GroupLimits = %v, want default:100 slow-backend:5", config.GroupLimits)
+	}
+}
+
+// TestApplyConfigFile_PartialOverlay verifies a file that sets only a few
+// keys leaves everything else at its default.
+func TestApplyConfigFile_PartialOverlay(t *testing.T) {
+	t.Parallel()
+	path := writeConfigFile(t, `queue_capacity: 32768
+workers:
+  min_workers: 20
+`)
+
+	config := DefaultConfig()
+	defaults := DefaultConfig()
+	if err := ApplyConfigFile(path, &config); err != nil {
+		t.Fatalf("ApplyConfigFile failed: %v", err)
+	}
+
+	if config.QueueCapacity != 32768 {
+		t.Errorf("QueueCapacity = %d, want 32768", config.QueueCapacity)
+	}
+	if config.PulseWorkerConfig.MinWorkers != 20 {
+		t.Errorf("MinWorkers = %d, want 20", config.PulseWorkerConfig.MinWorkers)
+	}
+	if config.BatchSize != defaults.BatchSize {
+		t.Errorf("BatchSize changed to %d, should stay at default %d", config.BatchSize, defaults.BatchSize)
+	}
+	if config.PulseWorkerConfig.MaxWorkers != defaults.PulseWorkerConfig.MaxWorkers {
+		t.Errorf("MaxWorkers changed to %d, should stay at default %d",
+			config.PulseWorkerConfig.MaxWorkers, defaults.PulseWorkerConfig.MaxWorkers)
+	}
+	if config.HistorySize != defaults.HistorySize {
+		t.Errorf("HistorySize changed to %d, should stay at default %d", config.HistorySize, defaults.HistorySize)
+	}
+}
+
+// TestApplyConfigFile_ValidationFailures covers the range checks.
+func TestApplyConfigFile_ValidationFailures(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "queue capacity not power of two",
+			yaml:    "queue_capacity: 1000\n",
+			wantErr: "power of two",
+		},
+		{
+			name:    "min workers above max",
+			yaml:    "workers:\n  min_workers: 100\n  max_workers: 10\n",
+			wantErr: "exceeds max_workers",
+		},
+		{
+			name:    "bad duration",
+			yaml:    "sizing:\n  slo: not-a-duration\n",
+			wantErr: "sizing.slo",
+		},
+		{
+			name:    "negative duration",
+			yaml:    "watch_debounce: -5s\n",
+			wantErr: "must not be negative",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultConfig()
+			err := ApplyConfigFile(writeConfigFile(t, tc.yaml), &config)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestApplyConfigFile_UnknownKeysWarnOnly verifies forward compatibility:
+// keys this binary does not know are ignored rather than fatal.
+func TestApplyConfigFile_UnknownKeysWarnOnly(t *testing.T) {
+	t.Parallel()
+	path := writeConfigFile(t, `queue_capacity: 4096
+future_knob: true
+pipeline:
+  some_new_flag: 1
+`)
+
+	config := DefaultConfig()
+	if err := ApplyConfigFile(path, &config); err != nil {
+		t.Fatalf("Unknown keys should warn, not fail: %v", err)
+	}
+	if config.QueueCapacity != 4096 {
+		t.Errorf("QueueCapacity = %d, want 4096", config.QueueCapacity)
+	}
+}
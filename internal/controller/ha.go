@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"cpra/internal/ha"
+)
+
+// HAConfig configures active/standby leader election. HA mode is on when
+// Elector is non-nil: this instance campaigns every LeaseInterval, and only
+// the current leader dispatches checks, interventions, and notifications.
+// The standby still loads manifests, applies reloads, and watches the file,
+// so a failover starts from a warm world.
+type HAConfig struct {
+	// Elector decides leadership. Nil disables HA mode entirely; see
+	// ha.NewFileLockElector and ha.NewKVElector for the built-ins.
+	Elector ha.LeaderElector
+
+	// LeaseInterval is how often leadership is campaigned or renewed, and
+	// therefore the upper bound on how long a deposed leader keeps
+	// scheduling. Zero uses ha.DefaultLeaseInterval. For KV electors it
+	// must not exceed the lease TTL.
+	LeaseInterval time.Duration
+}
+
+// HAStats reports leader-election state for the stats endpoint.
+type HAStats struct {
+	Enabled bool `json:"enabled"`
+	Leader  bool `json:"leader"`
+}
+
+// IsLeader reports whether this instance currently holds HA leadership.
+// Always true when HA mode is disabled.
+func (c *Controller) IsLeader() bool {
+	if c.config.HA.Elector == nil {
+		return true
+	}
+	return c.isLeader.Load()
+}
+
+// haLeaseInterval returns the configured campaign interval with the default
+// applied.
+func (c *Controller) haLeaseInterval() time.Duration {
+	if c.config.HA.LeaseInterval > 0 {
+		return c.config.HA.LeaseInterval
+	}
+	return ha.DefaultLeaseInterval
+}
+
+// runLeadershipLoop campaigns once per lease interval and applies leadership
+// transitions. It runs for the lifetime of the run context and releases
+// leadership on shutdown so the standby takes over without waiting out the
+// lease.
+func (c *Controller) runLeadershipLoop(ctx context.Context) {
+	elector := c.config.HA.Elector
+	campaign := func() {
+		leader, err := elector.TryAcquire(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// A store error means leadership cannot be proven; schedule
+			// nothing rather than risk two active instances.
+			c.logger.Warnf("HA: campaign failed, staying standby: %v", err)
+			leader = false
+		}
+		c.applyLeadership(leader)
+	}
+
+	campaign()
+	ticker := c.config.Clock.NewTicker(c.haLeaseInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := elector.Release(); err != nil {
+				c.logger.Warnf("HA: releasing leadership on shutdown failed: %v", err)
+			}
+			return
+		case <-ticker.C():
+			campaign()
+		}
+	}
+}
+
+// applyLeadership opens or closes the dispatch gate and the worker pools on
+// a leadership transition. No-op when the state is unchanged.
+func (c *Controller) applyLeadership(leader bool) {
+	if c.isLeader.Swap(leader) == leader {
+		return
+	}
+	if leader {
+		c.logger.Warnf("HA: leadership acquired; resuming scheduling and worker pools")
+		c.pulsePool.Resume()
+		c.hfPool.Resume()
+		c.interventionPool.Resume()
+		c.codePool.Resume()
+		c.haGate.SetStandby(false)
+	} else {
+		c.logger.Warnf("HA: leadership lost; pausing scheduling and worker pools")
+		c.haGate.SetStandby(true)
+		c.pulsePool.Pause()
+		c.hfPool.Pause()
+		c.interventionPool.Pause()
+		c.codePool.Pause()
+	}
+}
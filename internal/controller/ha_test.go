@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/ha"
+)
+
+// TestController_HAFailover runs an active/standby pair against one
+// in-memory KV, kills the leader, and asserts the standby takes over while
+// the two instances never schedule at the same time.
+func TestController_HAFailover(t *testing.T) {
+	t.Parallel()
+
+	kv := ha.NewMemoryKV()
+	newHAController := func(holder string) *Controller {
+		t.Helper()
+		elector, err := ha.NewKVElector(kv, "cpra-leader", holder, 100*time.Millisecond)
+		if err != nil {
+			t.Fatalf("NewKVElector failed: %v", err)
+		}
+		cfg := DefaultConfig()
+		cfg.HA = HAConfig{Elector: elector, LeaseInterval: 10 * time.Millisecond}
+		ctrl, err := NewController(cfg)
+		if err != nil {
+			t.Fatalf("NewController failed: %v", err)
+		}
+		return ctrl
+	}
+
+	waitFor := func(cond func() bool, msg string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatal(msg)
+			}
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+
+	active := newHAController("instance-a")
+	standby := newHAController("instance-b")
+	defer standby.Stop()
+
+	if err := active.Start(nil); err != nil {
+		t.Fatalf("Start(active) failed: %v", err)
+	}
+	if err := standby.Start(nil); err != nil {
+		t.Fatalf("Start(standby) failed: %v", err)
+	}
+
+	// The first campaigner wins and opens its dispatch gate; the standby
+	// stays gated.
+	waitFor(active.IsLeader, "first instance never became leader")
+	waitFor(func() bool { return !active.haGate.Standby() }, "leader's dispatch gate never opened")
+	if standby.IsLeader() {
+		t.Fatal("standby must not hold leadership while the leader renews")
+	}
+	if !standby.haGate.Standby() {
+		t.Fatal("standby's dispatch gate must stay closed")
+	}
+
+	// Sample across several lease intervals: at no point may both schedule.
+	for i := 0; i < 25; i++ {
+		if active.IsLeader() && standby.IsLeader() {
+			t.Fatal("both instances held leadership at once")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	stats := active.Stats()
+	if !stats.HA.Enabled || !stats.HA.Leader {
+		t.Errorf("leader stats = %+v, want enabled and leader", stats.HA)
+	}
+	if s := standby.Stats(); !s.HA.Enabled || s.HA.Leader {
+		t.Errorf("standby stats = %+v, want enabled and not leader", s.HA)
+	}
+
+	// Kill the leader: its shutdown releases the lease and the standby must
+	// start scheduling within a few lease intervals.
+	active.Stop()
+	waitFor(standby.IsLeader, "standby never took over after the leader died")
+	waitFor(func() bool { return !standby.haGate.Standby() }, "new leader's dispatch gate never opened")
+	if s := standby.Stats(); !s.HA.Leader {
+		t.Errorf("stats after failover = %+v, want leader", s.HA)
+	}
+}
+
+// TestController_HADisabledAlwaysLeader verifies non-HA deployments are
+// unaffected: no gate, and IsLeader is always true.
+func TestController_HADisabledAlwaysLeader(t *testing.T) {
+	t.Parallel()
+
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	if !ctrl.IsLeader() {
+		t.Error("IsLeader must be true when HA is disabled")
+	}
+	if ctrl.haGate != nil {
+		t.Error("no standby gate must be created when HA is disabled")
+	}
+	if stats := ctrl.Stats(); stats.HA.Enabled || !stats.HA.Leader {
+		t.Errorf("stats = %+v, want disabled and leader", stats.HA)
+	}
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"cpra/internal/controller/systems"
+)
+
+// HeartbeatHandler returns an http.Handler serving POST /heartbeat/{token}
+// and POST /heartbeat/{monitor-name}.
+//
+// A path segment matching a registered token is a beat by itself; anything
+// else is treated as a monitor name and must carry the monitor's pre-shared
+// token in the Authorization header ("Bearer <token>") or X-Heartbeat-Token.
+// The handler records the beat in the heartbeat ingestor, which hands it off
+// to the ECS tick loop; it never touches the world directly. Responses:
+//
+//   - 202 Accepted: beat recorded
+//   - 401 Unauthorized: the presented token does not match the monitor's
+//   - 404 Not Found: neither a registered token nor a known monitor name
+//   - 429 Too Many Requests: beats for this token arrive faster than the
+//     per-token rate limit (e.g. a burst replayed after a partition)
+//
+// The caller is responsible for mounting the handler on a listener; the
+// controller itself does not run an HTTP server.
+func (c *Controller) HeartbeatHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		segment := strings.TrimPrefix(r.URL.Path, "/heartbeat/")
+		if segment == "" || segment == r.URL.Path || strings.Contains(segment, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		err := c.heartbeats.Record(segment, time.Now())
+		if errors.Is(err, systems.ErrUnknownHeartbeatToken) {
+			// Not a token: treat the segment as a monitor name with the
+			// pre-shared token in the request headers.
+			err = c.heartbeats.RecordNamed(segment, heartbeatRequestToken(r), time.Now())
+		}
+
+		switch {
+		case err == nil:
+			w.WriteHeader(http.StatusAccepted)
+		case errors.Is(err, systems.ErrHeartbeatBadToken):
+			http.Error(w, "invalid heartbeat token", http.StatusUnauthorized)
+		case errors.Is(err, systems.ErrUnknownHeartbeatMonitor):
+			http.Error(w, "unknown heartbeat monitor", http.StatusNotFound)
+		case errors.Is(err, systems.ErrHeartbeatRateLimited):
+			http.Error(w, "heartbeat rate limited", http.StatusTooManyRequests)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+	})
+}
+
+// heartbeatRequestToken extracts the pre-shared token from a name-addressed
+// beat: an Authorization bearer token, or the X-Heartbeat-Token header.
+func heartbeatRequestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.Header.Get("X-Heartbeat-Token")
+}
+
+// HeartbeatTokens returns the monitor-name -> token mapping for registered
+// heartbeat monitors, for inclusion in the effective configuration or an
+// admin surface.
+func (c *Controller) HeartbeatTokens() map[string]string {
+	return c.heartbeats.Tokens()
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestHeartbeatHandler_NamedWithToken covers the name-addressed ingestion
+// path: beats for POST /heartbeat/{monitor-name} must carry the monitor's
+// pre-shared token from the manifest.
+func TestHeartbeatHandler_NamedWithToken(t *testing.T) {
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	manifest := filepath.Join(t.TempDir(), "heartbeat.yaml")
+	if err := os.WriteFile(manifest, []byte(`monitors:
+  - name: batch-etl
+    pulse_check:
+      type: heartbeat
+      interval: 30s
+      config:
+        grace: 10s
+        token: s3cret
+`), 0o644); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+	if err := ctrl.LoadMonitors(context.Background(), manifest); err != nil {
+		t.Fatalf("LoadMonitors failed: %v", err)
+	}
+	if err := ctrl.Start(nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(ctrl.Stop)
+
+	handler := ctrl.HeartbeatHandler()
+	post := func(target, token string) int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", target, nil)
+		if token != "" {
+			req.Header.Set("X-Heartbeat-Token", token)
+		}
+		handler.ServeHTTP(recorder, req)
+		return recorder.Result().StatusCode
+	}
+
+	if got := post("/heartbeat/batch-etl", ""); got != http.StatusUnauthorized {
+		t.Errorf("beat without token: status = %d, want 401", got)
+	}
+	if got := post("/heartbeat/batch-etl", "wrong"); got != http.StatusUnauthorized {
+		t.Errorf("beat with wrong token: status = %d, want 401", got)
+	}
+	if got := post("/heartbeat/nope", "s3cret"); got != http.StatusNotFound {
+		t.Errorf("beat for unknown monitor: status = %d, want 404", got)
+	}
+	if got := post("/heartbeat/batch-etl", "s3cret"); got != http.StatusAccepted {
+		t.Errorf("authenticated beat: status = %d, want 202", got)
+	}
+	// The configured token still works as a path capability, sharing the
+	// per-token rate limit with the named path.
+	if got := post("/heartbeat/s3cret", ""); got != http.StatusTooManyRequests {
+		t.Errorf("replayed beat within rate limit: status = %d, want 429", got)
+	}
+}
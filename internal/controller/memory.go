@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// MemoryManager handles memory optimization and monitoring
+type MemoryManager struct {
+	lastGC         time.Time
+	memoryStats    runtime.MemStats
+	maxMemory      uint64
+	gcInterval     time.Duration
+	alertThreshold float64
+}
+
+func NewMemoryManager(maxMemoryGB uint64, gcIntervalSeconds int) *MemoryManager {
+	return &MemoryManager{
+		maxMemory:      maxMemoryGB << 30, // Convert GB to bytes
+		gcInterval:     time.Duration(gcIntervalSeconds) * time.Second,
+		alertThreshold: 0.8, // Alert at 80% memory usage
+	}
+}
+
+// MonitorMemory checks current memory usage and triggers cleanup if needed
+func (m *MemoryManager) MonitorMemory() {
+	runtime.ReadMemStats(&m.memoryStats)
+
+	currentUsage := m.memoryStats.Alloc
+	usagePercent := float64(currentUsage) / float64(m.maxMemory)
+
+	if usagePercent > m.alertThreshold {
+		if SystemLogger != nil {
+			SystemLogger.Warnf("HIGH MEMORY USAGE: %.2f%% (%d MB / %d MB)",
+				usagePercent*100,
+				currentUsage>>20,
+				m.maxMemory>>20)
+		}
+
+		// Force garbage collection
+		m.ForceGC()
+	}
+
+	// Periodic garbage collection
+	if time.Since(m.lastGC) > m.gcInterval {
+		runtime.GC()
+		m.lastGC = time.Now()
+	}
+}
+
+// ForceGC triggers immediate garbage collection with logging
+func (m *MemoryManager) ForceGC() {
+	before := m.memoryStats.Alloc
+	runtime.GC()
+	runtime.ReadMemStats(&m.memoryStats)
+	after := m.memoryStats.Alloc
+
+	freed := before - after
+	if SystemLogger != nil {
+		SystemLogger.Infof("Forced GC: freed %d MB (before: %d MB, after: %d MB)",
+			freed>>20, before>>20, after>>20)
+	}
+
+	m.lastGC = time.Now()
+}
+
+// GetMemoryStats returns current memory statistics
+func (m *MemoryManager) GetMemoryStats() runtime.MemStats {
+	runtime.ReadMemStats(&m.memoryStats)
+	return m.memoryStats
+}
+
+// SetMemoryLimit configures runtime memory limits
+func (m *MemoryManager) SetMemoryLimit() {
+	debug.SetMemoryLimit(int64(m.maxMemory))
+	if SystemLogger != nil {
+		SystemLogger.Infof("Memory limit set to: %d GB", m.maxMemory>>30)
+	}
+}
+
+// LogMemoryStats provides detailed memory information
+func (m *MemoryManager) LogMemoryStats() {
+	stats := m.GetMemoryStats()
+	if SystemLogger != nil {
+		SystemLogger.Infof("Memory Stats:")
+		SystemLogger.Infof("  Alloc: %d MB", stats.Alloc>>20)
+		SystemLogger.Infof("  TotalAlloc: %d MB", stats.TotalAlloc>>20)
+		SystemLogger.Infof("  Sys: %d MB", stats.Sys>>20)
+		SystemLogger.Infof("  NumGC: %d", stats.NumGC)
+		SystemLogger.Infof("  GCCPUFraction: %.4f", stats.GCCPUFraction)
+	}
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"net/http"
+
+	"cpra/internal/metrics"
+	"cpra/internal/queue"
+)
+
+// controllerMetrics mirrors queue, worker pool, and world statistics into
+// Prometheus families. Names are stable and prefixed cpra_; dashboards may
+// depend on them.
+type controllerMetrics struct {
+	registry *metrics.Registry
+
+	queueDepth    *metrics.Family
+	queueCapacity *metrics.Family
+	queueEnqueued *metrics.Family
+	queueDequeued *metrics.Family
+	queueDropped  *metrics.Family
+	queueAvgWait  *metrics.Family
+	queueMaxWait  *metrics.Family
+	queueP50Wait  *metrics.Family
+	queueP90Wait  *metrics.Family
+	queueP99Wait  *metrics.Family
+	queueP999Wait *metrics.Family
+
+	workersRunning  *metrics.Family
+	workersCapacity *metrics.Family
+	tasksSubmitted  *metrics.Family
+	tasksCompleted  *metrics.Family
+	scalingEvents   *metrics.Family
+	pendingResults  *metrics.Family
+
+	resultsConsumed *metrics.Family
+	resultsBacklog  *metrics.Family
+
+	entitiesUsed     *metrics.Family
+	entitiesRecycled *metrics.Family
+	archetypes       *metrics.Family
+
+	reloadsAttempted *metrics.Family
+	reloadsSucceeded *metrics.Family
+}
+
+func newControllerMetrics() *controllerMetrics {
+	r := metrics.NewRegistry()
+	return &controllerMetrics{
+		registry: r,
+
+		queueDepth:    r.Gauge("cpra_queue_depth", "Jobs currently waiting in the queue."),
+		queueCapacity: r.Gauge("cpra_queue_capacity", "Maximum number of jobs the queue can hold."),
+		queueEnqueued: r.Counter("cpra_queue_enqueued_total", "Jobs enqueued since startup."),
+		queueDequeued: r.Counter("cpra_queue_dequeued_total", "Jobs dequeued since startup."),
+		queueDropped:  r.Counter("cpra_queue_dropped_total", "Jobs dropped due to a full queue."),
+		queueAvgWait:  r.Gauge("cpra_queue_wait_avg_seconds", "Average time jobs spent queued in the sample window."),
+		queueMaxWait:  r.Gauge("cpra_queue_wait_max_seconds", "Maximum time a job spent queued in the sample window."),
+		queueP50Wait:  r.Gauge("cpra_queue_wait_p50_seconds", "Median queue wait over the latency window."),
+		queueP90Wait:  r.Gauge("cpra_queue_wait_p90_seconds", "90th percentile queue wait over the latency window."),
+		queueP99Wait:  r.Gauge("cpra_queue_wait_p99_seconds", "99th percentile queue wait over the latency window."),
+		queueP999Wait: r.Gauge("cpra_queue_wait_p999_seconds", "99.9th percentile queue wait over the latency window."),
+
+		workersRunning:  r.Gauge("cpra_workers_running", "Workers currently running in the pool."),
+		workersCapacity: r.Gauge("cpra_workers_capacity", "Current worker pool capacity."),
+		tasksSubmitted:  r.Counter("cpra_worker_tasks_submitted_total", "Tasks submitted to the pool since startup."),
+		tasksCompleted:  r.Counter("cpra_worker_tasks_completed_total", "Tasks completed by the pool since startup."),
+		scalingEvents:   r.Counter("cpra_worker_scaling_events_total", "Worker pool scaling events since startup."),
+		pendingResults:  r.Gauge("cpra_worker_pending_results", "Results awaiting routing back to the ECS loop."),
+
+		resultsConsumed: r.Gauge("cpra_results_consumed_per_tick", "Results the system applied during its last tick."),
+		resultsBacklog:  r.Gauge("cpra_results_backlog", "Received results still waiting to be applied."),
+
+		entitiesUsed:     r.Gauge("cpra_world_entities_used", "Live entities in the ECS world."),
+		entitiesRecycled: r.Gauge("cpra_world_entities_recycled", "Recycled entity slots in the ECS world."),
+		archetypes:       r.Gauge("cpra_world_archetypes", "Archetypes in the ECS world."),
+
+		reloadsAttempted: r.Counter("cpra_manifest_reloads_attempted_total", "Manifest reloads attempted since startup."),
+		reloadsSucceeded: r.Counter("cpra_manifest_reloads_succeeded_total", "Manifest reloads applied since startup."),
+	}
+}
+
+// setQueue mirrors one queue's stats under the given queue label.
+func (m *controllerMetrics) setQueue(label string, stats queue.Stats) {
+	labels := `queue="` + label + `"`
+	m.queueDepth.Set(labels, float64(stats.QueueDepth))
+	m.queueCapacity.Set(labels, float64(stats.Capacity))
+	m.queueEnqueued.Set(labels, float64(stats.Enqueued))
+	m.queueDequeued.Set(labels, float64(stats.Dequeued))
+	m.queueDropped.Set(labels, float64(stats.Dropped))
+	m.queueAvgWait.Set(labels, stats.AvgQueueTime.Seconds())
+	m.queueMaxWait.Set(labels, stats.MaxQueueTime.Seconds())
+	m.queueP50Wait.Set(labels, stats.P50QueueTime.Seconds())
+	m.queueP90Wait.Set(labels, stats.P90QueueTime.Seconds())
+	m.queueP99Wait.Set(labels, stats.P99QueueTime.Seconds())
+	m.queueP999Wait.Set(labels, stats.P999QueueTime.Seconds())
+}
+
+// setWorkers mirrors one worker pool's stats under the given pool label.
+func (m *controllerMetrics) setWorkers(label string, stats queue.WorkerPoolStats) {
+	labels := `pool="` + label + `"`
+	m.workersRunning.Set(labels, float64(stats.RunningWorkers))
+	m.workersCapacity.Set(labels, float64(stats.CurrentCapacity))
+	m.tasksSubmitted.Set(labels, float64(stats.TasksSubmitted))
+	m.tasksCompleted.Set(labels, float64(stats.TasksCompleted))
+	m.scalingEvents.Set(labels, float64(stats.ScalingEvents))
+	m.pendingResults.Set(labels, float64(stats.PendingResults))
+}
+
+// setResults mirrors one result system's drain counters under the given label.
+func (m *controllerMetrics) setResults(label string, consumed, backlog int64) {
+	labels := `system="` + label + `"`
+	m.resultsConsumed.Set(labels, float64(consumed))
+	m.resultsBacklog.Set(labels, float64(backlog))
+}
+
+// MetricsHandler returns an http.Handler serving controller statistics in the
+// Prometheus text format at scrape time. Values are refreshed from the same
+// Stats() sources PrintShutdownMetrics uses, so a scrape never reads stale
+// ticker state.
+func (c *Controller) MetricsHandler() http.Handler {
+	m := newControllerMetrics()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.collectMetrics(m)
+		m.registry.Handler().ServeHTTP(w, r)
+	})
+}
+
+// collectMetrics refreshes all families from live stats.
+func (c *Controller) collectMetrics(m *controllerMetrics) {
+	m.setQueue("pulse", c.pulseQueue.Stats())
+	m.setQueue("pulse_hf", c.hfQueue.Stats())
+	m.setQueue("intervention", c.interventionQueue.Stats())
+	m.setQueue("code", c.codeQueue.Stats())
+
+	m.setWorkers("pulse", c.pulsePool.Stats())
+	m.setWorkers("pulse_hf", c.hfPool.Stats())
+	m.setWorkers("intervention", c.interventionPool.Stats())
+	m.setWorkers("code", c.codePool.Stats())
+
+	m.setResults("pulse", c.pulseResults.ConsumedLastTick(), c.pulseResults.ResultBacklog())
+	m.setResults("pulse_hf", c.hfResults.ConsumedLastTick(), c.hfResults.ResultBacklog())
+	m.setResults("intervention", c.interventionRes.ConsumedLastTick(), c.interventionRes.ResultBacklog())
+	m.setResults("code", c.codeResults.ConsumedLastTick(), c.codeResults.ResultBacklog())
+
+	worldStats := c.world.Stats()
+	m.entitiesUsed.Set("", float64(worldStats.Entities.Used))
+	m.entitiesRecycled.Set("", float64(worldStats.Entities.Recycled))
+	m.archetypes.Set("", float64(len(worldStats.Archetypes)))
+
+	reloads := c.ReloadStats()
+	m.reloadsAttempted.Set("", float64(reloads.ReloadsAttempted))
+	m.reloadsSucceeded.Set("", float64(reloads.ReloadsSucceeded))
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+)
+
+// metricValue extracts the value of a metric line (exact name plus optional
+// labels) from a Prometheus scrape body, summing across label sets.
+func metricValue(t *testing.T, body, name string) float64 {
+	t.Helper()
+	re := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(name) + `(?:\{[^}]*\})? (\S+)$`)
+	matches := re.FindAllStringSubmatch(body, -1)
+	if len(matches) == 0 {
+		t.Fatalf("metric %s not found in scrape output", name)
+	}
+	var sum float64
+	for _, m := range matches {
+		v, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			t.Fatalf("metric %s has non-numeric value %q", name, m[1])
+		}
+		sum += v
+	}
+	return sum
+}
+
+// TestMetricsHandler_ScrapeAfterEnqueue tests that the /metrics endpoint
+// reports non-zero queue and world statistics once monitors are loaded and
+// jobs have passed through a queue.
+func TestMetricsHandler_ScrapeAfterEnqueue(t *testing.T) {
+	t.Parallel()
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := ctrl.LoadMonitors(ctx, "testdata/test_monitors.yaml"); err != nil {
+		t.Fatalf("LoadMonitors failed: %v", err)
+	}
+
+	// Push a few jobs through the pulse queue so enqueue counters move.
+	for i := 0; i < 3; i++ {
+		job := &jobs.PulseTCPJob{Host: "localhost", Port: 1, Timeout: time.Second}
+		if err := ctrl.pulseQueue.Enqueue(job); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	handler := ctrl.MetricsHandler()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	resp := recorder.Result()
+	if resp.StatusCode != 200 {
+		t.Fatalf("scrape status = %d, want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain exposition format", ct)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	body := string(raw)
+
+	if got := metricValue(t, body, "cpra_queue_enqueued_total"); got < 3 {
+		t.Errorf("cpra_queue_enqueued_total = %v, want at least the 3 enqueued jobs", got)
+	}
+	if got := metricValue(t, body, "cpra_queue_capacity"); got == 0 {
+		t.Error("cpra_queue_capacity should be non-zero")
+	}
+	if got := metricValue(t, body, "cpra_world_entities_used"); got == 0 {
+		t.Error("cpra_world_entities_used should be non-zero after loading monitors")
+	}
+	if got := metricValue(t, body, "cpra_workers_capacity"); got == 0 {
+		t.Error("cpra_workers_capacity should be non-zero")
+	}
+
+	// Names are part of the contract: every family carries HELP and TYPE.
+	if !strings.Contains(body, "# TYPE cpra_queue_depth gauge") {
+		t.Error("scrape output missing TYPE line for cpra_queue_depth")
+	}
+	if !strings.Contains(body, "# TYPE cpra_queue_enqueued_total counter") {
+		t.Error("scrape output missing TYPE line for cpra_queue_enqueued_total")
+	}
+}
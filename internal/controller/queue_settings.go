@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"fmt"
+
+	"cpra/internal/queue"
+)
+
+// Queue implementation names accepted by QueueSettings.Implementation.
+const (
+	// QueueImplHybrid is the default ring-plus-overflow HybridQueue with
+	// watermarks, drop policies, and optional conflation.
+	QueueImplHybrid = "hybrid"
+	// QueueImplAdaptive is the lock-free auto-scaling AdaptiveQueue. It has
+	// no overflow path: a full queue always rejects, so the overflow and
+	// drop-policy settings do not apply to it.
+	QueueImplAdaptive = "adaptive"
+)
+
+// QueueSettings tunes one of the controller's job queues. The zero value
+// keeps the established defaults for that queue (a hybrid queue sized by
+// Config.QueueCapacity with the queue's standard drop policy), so existing
+// configurations are unaffected.
+type QueueSettings struct {
+	// Implementation selects the queue type: "hybrid" (default) or
+	// "adaptive". Setting it explicitly also disables the
+	// AdaptiveQueueEntityThreshold switch for the pulse queue.
+	Implementation string
+
+	// Capacity overrides the ring capacity for this queue; zero falls back
+	// to Config.QueueCapacity. Must be a power of two, which both the
+	// hybrid ring and the adaptive queue require.
+	Capacity uint64
+
+	// OverflowCapacity, SoftWatermark, and HardWatermark size the hybrid
+	// queue's burst-absorbing overflow slice and its warning/drop
+	// thresholds (fractions of OverflowCapacity). Zero values keep the
+	// queue package defaults. Hybrid only.
+	OverflowCapacity int
+	SoftWatermark    float64
+	HardWatermark    float64
+
+	// DropPolicy names the behaviour once the hybrid queue is saturated:
+	// "reject", "drop_newest", or "drop_oldest". Empty keeps the queue's
+	// standard policy (drop_newest for pulse and code, drop_oldest for
+	// intervention). Hybrid only.
+	DropPolicy string
+}
+
+// validate checks the settings against the queue implementations' invariants
+// so misconfiguration fails controller construction instead of surfacing as
+// runtime queue errors.
+func (s QueueSettings) validate(name string) error {
+	switch s.Implementation {
+	case "", QueueImplHybrid, QueueImplAdaptive:
+	default:
+		return fmt.Errorf("%s queue: unknown implementation %q (want %q or %q)",
+			name, s.Implementation, QueueImplHybrid, QueueImplAdaptive)
+	}
+	if s.Capacity > 0 && s.Capacity&(s.Capacity-1) != 0 {
+		return fmt.Errorf("%s queue: capacity %d is not a power of two", name, s.Capacity)
+	}
+	if s.Implementation == QueueImplAdaptive {
+		if s.OverflowCapacity != 0 || s.SoftWatermark != 0 || s.HardWatermark != 0 || s.DropPolicy != "" {
+			return fmt.Errorf("%s queue: overflow, watermark, and drop policy settings only apply to the hybrid implementation", name)
+		}
+		return nil
+	}
+	if s.DropPolicy != "" {
+		if _, err := queue.ParseDropPolicy(s.DropPolicy); err != nil {
+			return fmt.Errorf("%s queue: %w", name, err)
+		}
+	}
+	if s.OverflowCapacity < 0 {
+		return fmt.Errorf("%s queue: overflow capacity %d is negative", name, s.OverflowCapacity)
+	}
+	soft, hard := s.SoftWatermark, s.HardWatermark
+	if soft < 0 || soft > 1 {
+		return fmt.Errorf("%s queue: soft watermark %g is outside (0, 1]", name, soft)
+	}
+	if hard < 0 || hard > 1 {
+		return fmt.Errorf("%s queue: hard watermark %g is outside (0, 1]", name, hard)
+	}
+	if soft > 0 && hard > 0 && soft > hard {
+		return fmt.Errorf("%s queue: soft watermark %g exceeds hard watermark %g", name, soft, hard)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/loader"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// ReloadStats aggregates manifest reload counters for the metrics output.
+type ReloadStats struct {
+	ReloadsAttempted uint64    `json:"reloads_attempted"`
+	ReloadsSucceeded uint64    `json:"reloads_succeeded"`
+	LastReloadTime   time.Time `json:"last_reload_time"`
+}
+
+// ReloadStats returns a snapshot of the manifest reload counters.
+func (c *Controller) ReloadStats() ReloadStats {
+	stats := ReloadStats{
+		ReloadsAttempted: c.reloadsAttempted.Load(),
+		ReloadsSucceeded: c.reloadsSucceeded.Load(),
+	}
+	if nanos := c.lastReload.Load(); nanos != 0 {
+		stats.LastReloadTime = time.Unix(0, nanos)
+	}
+	return stats
+}
+
+// ReloadMonitors diff-reloads the manifest against the running world: new
+// monitors are created, existing ones have their config components replaced
+// (state and status are preserved), and monitors missing from the new
+// manifest have their entities removed.
+//
+// The load is fail-closed: a manifest that does not parse or validate leaves
+// the running world untouched, and the returned error carries the monitor
+// name and line number from the parser.
+func (c *Controller) ReloadMonitors(ctx context.Context, filename string) error {
+	c.reloadsAttempted.Add(1)
+
+	monitors, err := loader.LoadManifestFile(filename)
+	if err != nil {
+		return fmt.Errorf("manifest reload rejected: %w", err)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Stamp components written by this reload with the next generation; the
+	// counter only advances if the whole reload succeeds.
+	generation := c.manifestGen.Load() + 1
+	c.mapper.SetGeneration(generation)
+
+	// Index live monitors by name. Entities are collected before any change:
+	// the world must not be mutated while a query is open.
+	existing := make(map[string]ecs.Entity)
+	filter := ecs.NewFilter1[components.MonitorMeta](c.world)
+	query := filter.Query()
+	for query.Next() {
+		existing[query.Get().Name] = query.Entity()
+	}
+
+	var updated int
+	var toCreate []schema.Monitor
+	seen := make(map[string]struct{}, len(monitors))
+	for i := range monitors {
+		name := monitors[i].Name
+		seen[name] = struct{}{}
+		ent, ok := existing[name]
+		if !ok {
+			toCreate = append(toCreate, monitors[i])
+			continue
+		}
+		if err := c.mapper.ApplyMonitorConfig(c.world, ent, &monitors[i]); err != nil {
+			return fmt.Errorf("failed to update monitor %q: %w", name, err)
+		}
+		// ApplyMonitorConfig replaces configs; the enabled flag is a tag
+		// component handled through the enable/disable path. Only explicit
+		// disables are the manifest's to flip — a monitor suppressed by a
+		// schedule or maintenance window keeps its tag either way.
+		if monitors[i].Enabled && c.mapper.ExplicitlyDisabled.HasAll(ent) {
+			c.mapper.EnableMonitor(ent)
+		} else if !monitors[i].Enabled && !c.mapper.ExplicitlyDisabled.HasAll(ent) {
+			c.disableMonitorTracked(ent)
+		}
+		updated++
+	}
+	if len(toCreate) > 0 {
+		if err := c.mapper.CreateEntitiesFromMonitors(c.world, toCreate); err != nil {
+			return fmt.Errorf("failed to create monitors: %w", err)
+		}
+	}
+	var removed int
+	for name, ent := range existing {
+		if _, ok := seen[name]; !ok {
+			// Balance the snapshot counters for any work the removed
+			// monitor still had in flight.
+			if st := c.mapper.GetMonitorState(ent); st != nil {
+				c.stateLogger.Counters().Release(st.Flags)
+			}
+			c.world.RemoveEntity(ent)
+			c.mapper.ForgetMonitor(name)
+			removed++
+		}
+	}
+
+	// Dependencies resolve after creates, updates and removals so names may
+	// point anywhere in the new manifest. Unknown names and cycles reject
+	// the reload here; earlier diff work has already been applied, matching
+	// the per-monitor update errors above.
+	if err := c.mapper.ResolveDependencies(c.world); err != nil {
+		return fmt.Errorf("manifest reload rejected: %w", err)
+	}
+
+	// Regroup shared probes: the diff may have added, removed, or retargeted
+	// group members, including leaders.
+	if c.sharedProbes != nil {
+		c.sharedProbes.Rebuild(c.world)
+	}
+
+	// The shortest interval may have changed with the fleet.
+	c.applyAdaptiveTPS()
+
+	c.manifestGen.Store(generation)
+	c.reloadsSucceeded.Add(1)
+	c.lastReload.Store(time.Now().UnixNano())
+	c.logger.Infof("Reloaded manifest: %d added, %d updated, %d removed (generation %d)",
+		len(toCreate), updated, removed, generation)
+	return nil
+}
+
+// manifestPollInterval is how often the watcher checks the manifest mtime.
+const manifestPollInterval = time.Second
+
+// DefaultWatchDebounce is the quiet period required after the last observed
+// change before a reload fires, so editors that write a file several times
+// (swap files, atomic rename chains) cause a single reload.
+const DefaultWatchDebounce = 2 * time.Second
+
+// watchManifest polls the loaded manifest's mtime and triggers a diff-reload
+// once the file has been quiet for the debounce window. Polling is used
+// instead of inotify to keep behavior identical across platforms and NFS.
+// Failed reloads are logged and not retried until the file changes again.
+func (c *Controller) watchManifest(ctx context.Context, filename string) {
+	debounce := c.config.WatchDebounce
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	applied := time.Time{}
+	if stat, err := os.Stat(filename); err == nil {
+		applied = stat.ModTime()
+	}
+	lastSeen := applied
+	var changedAt time.Time
+
+	ticker := time.NewTicker(manifestPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stat, err := os.Stat(filename)
+		if err != nil {
+			// Transient during atomic renames; keep polling.
+			continue
+		}
+		if mod := stat.ModTime(); !mod.Equal(lastSeen) {
+			lastSeen = mod
+			changedAt = time.Now()
+		}
+		if lastSeen.Equal(applied) || changedAt.IsZero() || time.Since(changedAt) < debounce {
+			continue
+		}
+
+		applied = lastSeen
+		changedAt = time.Time{}
+		if err := c.ReloadMonitors(ctx, filename); err != nil {
+			c.logger.Errorf("Manifest watch: %v", err)
+		}
+	}
+}
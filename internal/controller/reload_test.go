@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+const reloadManifestV1 = `monitors:
+  - name: web-1
+    enabled: true
+    pulse_check:
+      type: tcp
+      interval: 30s
+      timeout: 2s
+      config:
+        host: localhost
+        port: 8080
+
+  - name: web-2
+    enabled: true
+    pulse_check:
+      type: tcp
+      interval: 30s
+      timeout: 2s
+      config:
+        host: localhost
+        port: 8081
+`
+
+const reloadManifestV2 = `monitors:
+  - name: web-1
+    enabled: true
+    pulse_check:
+      type: tcp
+      interval: 60s
+      timeout: 2s
+      config:
+        host: localhost
+        port: 9090
+
+  - name: web-3
+    enabled: true
+    pulse_check:
+      type: tcp
+      interval: 30s
+      timeout: 2s
+      config:
+        host: localhost
+        port: 8082
+`
+
+// writeManifest writes content to path, failing the test on error.
+func writeManifest(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+}
+
+// monitorNames returns the names of all live monitor entities.
+func monitorNames(ctrl *Controller) map[string]bool {
+	names := make(map[string]bool)
+	filter := ecs.NewFilter1[components.MonitorMeta](ctrl.world)
+	query := filter.Query()
+	for query.Next() {
+		names[query.Get().Name] = true
+	}
+	return names
+}
+
+// TestReloadMonitors_AddUpdateRemove tests the diff against a running world:
+// a changed monitor is updated in place, a new one appears, a removed one's
+// entity is deleted, and the manifest generation advances.
+func TestReloadMonitors_AddUpdateRemove(t *testing.T) {
+	t.Parallel()
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "monitors.yaml")
+	writeManifest(t, path, reloadManifestV1)
+	ctx := context.Background()
+	if err := ctrl.LoadMonitors(ctx, path); err != nil {
+		t.Fatalf("LoadMonitors failed: %v", err)
+	}
+	genBefore := ctrl.ManifestGeneration()
+
+	writeManifest(t, path, reloadManifestV2)
+	if err := ctrl.ReloadMonitors(ctx, path); err != nil {
+		t.Fatalf("ReloadMonitors failed: %v", err)
+	}
+
+	names := monitorNames(ctrl)
+	if !names["web-1"] || !names["web-3"] || names["web-2"] {
+		t.Errorf("monitors after reload = %v, want web-1 and web-3 only", names)
+	}
+	if got := ctrl.ManifestGeneration(); got != genBefore+1 {
+		t.Errorf("ManifestGeneration = %d, want %d", got, genBefore+1)
+	}
+
+	stats := ctrl.ReloadStats()
+	if stats.ReloadsAttempted != 1 || stats.ReloadsSucceeded != 1 {
+		t.Errorf("ReloadStats = %+v, want one attempted and one succeeded", stats)
+	}
+	if stats.LastReloadTime.IsZero() {
+		t.Error("LastReloadTime should be set after a successful reload")
+	}
+}
+
+// TestReloadMonitors_BadManifestLeavesWorldUntouched tests that a manifest
+// that fails to parse is rejected with line information and changes nothing.
+func TestReloadMonitors_BadManifestLeavesWorldUntouched(t *testing.T) {
+	t.Parallel()
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "monitors.yaml")
+	writeManifest(t, path, reloadManifestV1)
+	ctx := context.Background()
+	if err := ctrl.LoadMonitors(ctx, path); err != nil {
+		t.Fatalf("LoadMonitors failed: %v", err)
+	}
+	genBefore := ctrl.ManifestGeneration()
+
+	// An unknown field fails the strict node walk with the offending line.
+	writeManifest(t, path, strings.Replace(reloadManifestV1, "enabled: true", "enable: true", 1))
+	err = ctrl.ReloadMonitors(ctx, path)
+	if err == nil {
+		t.Fatal("expected error for manifest with unknown field")
+	}
+	if !strings.Contains(err.Error(), "line") {
+		t.Errorf("error should carry a line number: %v", err)
+	}
+
+	names := monitorNames(ctrl)
+	if !names["web-1"] || !names["web-2"] || len(names) != 2 {
+		t.Errorf("monitors after rejected reload = %v, want unchanged web-1 and web-2", names)
+	}
+	if got := ctrl.ManifestGeneration(); got != genBefore {
+		t.Errorf("ManifestGeneration = %d, want unchanged %d", got, genBefore)
+	}
+	stats := ctrl.ReloadStats()
+	if stats.ReloadsAttempted != 1 || stats.ReloadsSucceeded != 0 {
+		t.Errorf("ReloadStats = %+v, want one attempted and none succeeded", stats)
+	}
+}
+
+// TestWatchManifest_PicksUpChanges tests that modifying the watched file
+// makes the new monitor appear without a restart.
+func TestWatchManifest_PicksUpChanges(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.WatchDebounce = 100 * time.Millisecond
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "monitors.yaml")
+	writeManifest(t, path, reloadManifestV1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := ctrl.LoadMonitors(ctx, path); err != nil {
+		t.Fatalf("LoadMonitors failed: %v", err)
+	}
+
+	go ctrl.watchManifest(ctx, path)
+
+	// Sleep past one poll so the watcher has the initial mtime, then modify.
+	time.Sleep(manifestPollInterval + 100*time.Millisecond)
+	writeManifest(t, path, reloadManifestV2)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if names := monitorNames(ctrl); names["web-3"] && !names["web-2"] {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Errorf("watcher did not apply the manifest change, monitors = %v", monitorNames(ctrl))
+}
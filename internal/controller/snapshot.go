@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/queue"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// StateCounts breaks the monitor population down by lifecycle state. The
+// pending counts come from counters maintained at every state transition and
+// the ready/disabled counts from registered filters, so producing them never
+// scans the fleet. A monitor with a job in flight still counts as ready; the
+// categories overlap by design.
+type StateCounts struct {
+	Ready               int64 `json:"ready"`
+	PulsePending        int64 `json:"pulse_pending"`
+	InterventionPending int64 `json:"intervention_pending"`
+	CodePending         int64 `json:"code_pending"`
+	Disabled            int64 `json:"disabled"`
+}
+
+// ControllerSnapshot is a point-in-time view of the running controller for
+// embedding applications that run cpra as a library. Unlike Stats it carries
+// no pointers into live structures: every field is plain data, and the JSON
+// field names are part of the API - existing keys keep their names and types
+// across releases, new ones only get added.
+type ControllerSnapshot struct {
+	Taken time.Time `json:"taken"`
+	// Uptime is the time since Start, in nanoseconds; zero before the
+	// controller has started.
+	Uptime time.Duration `json:"uptime"`
+
+	PulseQueue         queue.Stats `json:"pulse_queue"`
+	InterventionQueue  queue.Stats `json:"intervention_queue"`
+	CodeQueue          queue.Stats `json:"code_queue"`
+	HighFrequencyQueue queue.Stats `json:"high_frequency_queue"`
+
+	PulseWorkers        queue.WorkerPoolStats `json:"pulse_workers"`
+	InterventionWorkers queue.WorkerPoolStats `json:"intervention_workers"`
+	CodeWorkers         queue.WorkerPoolStats `json:"code_workers"`
+
+	Monitors StateCounts `json:"monitors"`
+
+	// Config echoes, so a consumer does not need the Config that built the
+	// controller to interpret the numbers above.
+	BatchSize      int     `json:"batch_size"`
+	TicksPerSecond float64 `json:"ticks_per_second"`
+}
+
+// Snapshot returns the current controller state as one serializable struct.
+// It is cheap regardless of fleet size: queue and pool stats are counter
+// reads, the pending counts are maintained by the systems at each state
+// transition, and the ready/disabled counts come from the registered filters,
+// which track matching archetypes instead of iterating entities.
+func (c *Controller) Snapshot() ControllerSnapshot {
+	now := time.Now()
+
+	var uptime time.Duration
+	if started := c.started.Load(); started > 0 {
+		uptime = now.Sub(time.Unix(0, started))
+	}
+
+	// Close each query right after counting: an open query locks the world
+	// against structural changes.
+	mq := c.monitorCountFilter.Query()
+	total := int64(mq.Count())
+	mq.Close()
+	dq := c.disabledCountFilter.Query()
+	disabled := int64(dq.Count())
+	dq.Close()
+
+	counters := c.stateLogger.Counters()
+	return ControllerSnapshot{
+		Taken:              now,
+		Uptime:             uptime,
+		PulseQueue:         c.pulseQueue.Stats(),
+		InterventionQueue:  c.interventionQueue.Stats(),
+		CodeQueue:          c.codeQueue.Stats(),
+		HighFrequencyQueue: c.hfQueue.Stats(),
+
+		PulseWorkers:        c.pulsePool.Stats(),
+		InterventionWorkers: c.interventionPool.Stats(),
+		CodeWorkers:         c.codePool.Stats(),
+
+		Monitors: StateCounts{
+			Ready:               total - disabled,
+			PulsePending:        counters.PulsePending(),
+			InterventionPending: counters.InterventionPending(),
+			CodePending:         counters.CodePending(),
+			Disabled:            disabled,
+		},
+
+		BatchSize:      c.config.BatchSize,
+		TicksPerSecond: c.app.TPS,
+	}
+}
+
+// disableMonitorTracked disables a monitor and logs the resulting state
+// transition: DisableMonitor drops the pending flags, and without the
+// transition the snapshot counters would keep counting them.
+func (c *Controller) disableMonitorTracked(ent ecs.Entity) {
+	var old components.MonitorState
+	if st := c.mapper.GetMonitorState(ent); st != nil {
+		old = *st
+	}
+	c.mapper.DisableMonitor(ent)
+	if st := c.mapper.GetMonitorState(ent); st != nil {
+		c.stateLogger.LogTransition(ent, old, *st)
+	}
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// TestSnapshot_CountersFollowTransitions drives monitor state transitions
+// through the same path the systems use and checks the snapshot counts move
+// with them, including the disable path that drops pending flags silently.
+func TestSnapshot_CountersFollowTransitions(t *testing.T) {
+	t.Parallel()
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	stateMapper := ecs.NewMap2[components.MonitorState, components.MonitorMeta](ctrl.world)
+	a := stateMapper.NewEntity(&components.MonitorState{}, &components.MonitorMeta{Name: "a"})
+	b := stateMapper.NewEntity(&components.MonitorState{}, &components.MonitorMeta{Name: "b"})
+
+	snap := ctrl.Snapshot()
+	if snap.Monitors.Ready != 2 || snap.Monitors.PulsePending != 0 {
+		t.Fatalf("initial counts = %+v, want 2 ready, nothing pending", snap.Monitors)
+	}
+
+	// A dispatches a pulse; B dispatches a code alert.
+	idle := components.MonitorState{}
+	pulsePending := components.MonitorState{Flags: components.StatePulsePending}
+	codePending := components.MonitorState{Flags: components.StateCodePending}
+	flagsA, _ := stateMapper.Get(a)
+	flagsA.Flags = pulsePending.Flags
+	ctrl.stateLogger.LogTransition(a, idle, pulsePending)
+	flagsB, _ := stateMapper.Get(b)
+	flagsB.Flags = codePending.Flags
+	ctrl.stateLogger.LogTransition(b, idle, codePending)
+
+	snap = ctrl.Snapshot()
+	if snap.Monitors.PulsePending != 1 || snap.Monitors.CodePending != 1 {
+		t.Errorf("counts after dispatch = %+v, want 1 pulse-pending and 1 code-pending", snap.Monitors)
+	}
+
+	// A's result comes back and escalates into an intervention.
+	interventionPending := components.MonitorState{Flags: components.StateInterventionPending}
+	flagsA, _ = stateMapper.Get(a)
+	flagsA.Flags = interventionPending.Flags
+	ctrl.stateLogger.LogTransition(a, pulsePending, interventionPending)
+
+	snap = ctrl.Snapshot()
+	if snap.Monitors.PulsePending != 0 || snap.Monitors.InterventionPending != 1 {
+		t.Errorf("counts after escalation = %+v, want the pulse handed over to an intervention", snap.Monitors)
+	}
+
+	// Disabling B drops its pending code job; the counter must follow even
+	// though DisableMonitor clears the flag outside any system.
+	ctrl.disableMonitorTracked(b)
+	snap = ctrl.Snapshot()
+	if snap.Monitors.CodePending != 0 {
+		t.Errorf("CodePending after disable = %d, want 0", snap.Monitors.CodePending)
+	}
+	if snap.Monitors.Disabled != 1 || snap.Monitors.Ready != 1 {
+		t.Errorf("counts after disable = %+v, want 1 disabled and 1 ready", snap.Monitors)
+	}
+}
+
+// TestSnapshot_JSONKeysStable pins the JSON shape: the field names are part
+// of the embedding API, so a rename shows up here before it ships.
+func TestSnapshot_JSONKeysStable(t *testing.T) {
+	t.Parallel()
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	raw, err := json.Marshal(ctrl.Snapshot())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	for _, key := range []string{
+		"taken", "uptime",
+		"pulse_queue", "intervention_queue", "code_queue", "high_frequency_queue",
+		"pulse_workers", "intervention_workers", "code_workers",
+		"monitors", "batch_size", "ticks_per_second",
+	} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("snapshot JSON missing key %q", key)
+		}
+	}
+
+	var monitors map[string]int64
+	if err := json.Unmarshal(decoded["monitors"], &monitors); err != nil {
+		t.Fatalf("monitors block: %v", err)
+	}
+	for _, key := range []string{"ready", "pulse_pending", "intervention_pending", "code_pending", "disabled"} {
+		if _, ok := monitors[key]; !ok {
+			t.Errorf("monitors block missing key %q", key)
+		}
+	}
+}
+
+// TestSnapshot_CheapAtScale creates a large fleet and checks Snapshot does
+// not degrade into a per-entity scan: the counts come from counters and
+// registered filters, so one call stays in the low milliseconds.
+func TestSnapshot_CheapAtScale(t *testing.T) {
+	t.Parallel()
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	stateMapper := ecs.NewMap1[components.MonitorState](ctrl.world)
+	pairMapper := ecs.NewMap2[components.MonitorState, components.Disabled](ctrl.world)
+	const fleet = 50_000
+	for i := 0; i < fleet; i++ {
+		if i%10 == 0 {
+			pairMapper.NewEntity(&components.MonitorState{}, &components.Disabled{})
+		} else {
+			stateMapper.NewEntity(&components.MonitorState{})
+		}
+	}
+
+	// Best of a few runs, so a scheduler hiccup does not fail the test.
+	best := time.Hour
+	var snap ControllerSnapshot
+	for i := 0; i < 5; i++ {
+		start := time.Now()
+		snap = ctrl.Snapshot()
+		if d := time.Since(start); d < best {
+			best = d
+		}
+	}
+	if best > 10*time.Millisecond {
+		t.Errorf("Snapshot took %v at %d monitors, want low single-digit milliseconds", best, fleet)
+	}
+	if snap.Monitors.Disabled != fleet/10 || snap.Monitors.Ready != fleet-fleet/10 {
+		t.Errorf("counts = %+v, want %d disabled of %d", snap.Monitors, fleet/10, fleet)
+	}
+}
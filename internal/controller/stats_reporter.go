@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cpra/internal/queue"
+)
+
+// statsSnapshot captures the queue and pool counters at one point in time so
+// the periodic reporter can log deltas between consecutive snapshots instead
+// of lifetime averages.
+type statsSnapshot struct {
+	taken  time.Time
+	queues map[string]queue.Stats
+	pools  map[string]queue.WorkerPoolStats
+}
+
+// Report order, matching PrintShutdownMetrics.
+var (
+	statsQueueLabels = []string{"Pulse", "Intervention", "Code", "PulseHF"}
+	statsPoolLabels  = []string{"Pulse", "Intervention", "Code"}
+)
+
+// takeStatsSnapshot reads the current counters from every queue and pool.
+func (c *Controller) takeStatsSnapshot() statsSnapshot {
+	return statsSnapshot{
+		taken: time.Now(),
+		queues: map[string]queue.Stats{
+			"Pulse":        c.pulseQueue.Stats(),
+			"Intervention": c.interventionQueue.Stats(),
+			"Code":         c.codeQueue.Stats(),
+			"PulseHF":      c.hfQueue.Stats(),
+		},
+		pools: map[string]queue.WorkerPoolStats{
+			"Pulse":        c.pulsePool.Stats(),
+			"Intervention": c.interventionPool.Stats(),
+			"Code":         c.codePool.Stats(),
+		},
+	}
+}
+
+// runStatsReporter logs a compact stats report every StatsReportInterval
+// until ctx is cancelled. Rates come from the counter deltas between
+// consecutive reports, not the queues' lifetime averages, so a stall or a
+// burst shows up in the very next report instead of being averaged away.
+func (c *Controller) runStatsReporter(ctx context.Context) {
+	ticker := time.NewTicker(c.config.StatsReportInterval)
+	defer ticker.Stop()
+	prev := c.takeStatsSnapshot()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			prev = c.reportStats(prev)
+		}
+	}
+}
+
+// reportStats logs one line per queue and pool with the activity since prev
+// and returns the fresh snapshot for the next interval.
+func (c *Controller) reportStats(prev statsSnapshot) statsSnapshot {
+	cur := c.takeStatsSnapshot()
+	elapsed := cur.taken.Sub(prev.taken)
+
+	for _, label := range statsQueueLabels {
+		s := cur.queues[label]
+		// The HF queue only exists for monitors that opted in; skip the
+		// noise until something has used it.
+		if label == "PulseHF" && s.Enqueued == 0 {
+			continue
+		}
+		c.logger.Info(queueReportLine(label, prev.queues[label], s, elapsed))
+	}
+	for _, label := range statsPoolLabels {
+		c.logger.Info(poolReportLine(label, prev.pools[label], cur.pools[label]))
+	}
+
+	ws := c.world.Stats()
+	c.logger.Infof("Stats World: entities_used=%d recycled=%d total=%d",
+		ws.Entities.Used, ws.Entities.Recycled, ws.Entities.Total)
+	return cur
+}
+
+// queueReportLine formats one queue's activity since the previous snapshot.
+func queueReportLine(label string, prev, cur queue.Stats, elapsed time.Duration) string {
+	secs := elapsed.Seconds()
+	if secs <= 0 {
+		secs = 1
+	}
+	return fmt.Sprintf("Stats %s Queue: depth=%d/%d enqueued=%.1f/s dequeued=%.1f/s dropped_delta=%d",
+		label, cur.QueueDepth, cur.Capacity,
+		float64(cur.Enqueued-prev.Enqueued)/secs,
+		float64(cur.Dequeued-prev.Dequeued)/secs,
+		cur.Dropped-prev.Dropped)
+}
+
+// poolReportLine formats one worker pool's activity since the previous snapshot.
+func poolReportLine(label string, prev, cur queue.WorkerPoolStats) string {
+	return fmt.Sprintf("Stats %s Workers: running=%d capacity=%d waiting=%d submitted_delta=%d completed_delta=%d",
+		label, cur.RunningWorkers, cur.CurrentCapacity, cur.WaitingTasks,
+		cur.TasksSubmitted-prev.TasksSubmitted,
+		cur.TasksCompleted-prev.TasksCompleted)
+}
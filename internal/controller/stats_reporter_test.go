@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/queue"
+)
+
+// TestStatsReport_DeltasNotLifetimeAverages drives two report intervals over
+// synthetic counters and checks the second report reflects only the activity
+// of its own interval, not the lifetime totals.
+func TestStatsReport_DeltasNotLifetimeAverages(t *testing.T) {
+	start := queue.Stats{Capacity: 8192}
+
+	// First interval: 100 enqueued, 90 dequeued, 1 dropped over 10s. The
+	// queue's own lifetime EnqueueRate disagrees on purpose; the report must
+	// ignore it.
+	afterFirst := queue.Stats{
+		Enqueued: 100, Dequeued: 90, Dropped: 1,
+		QueueDepth: 10, Capacity: 8192, EnqueueRate: 42,
+	}
+	line := queueReportLine("Pulse", start, afterFirst, 10*time.Second)
+	want := "Stats Pulse Queue: depth=10/8192 enqueued=10.0/s dequeued=9.0/s dropped_delta=1"
+	if line != want {
+		t.Errorf("first report:\n got %q\nwant %q", line, want)
+	}
+
+	// Second interval: only 20 new enqueues and nothing dropped.
+	afterSecond := queue.Stats{
+		Enqueued: 120, Dequeued: 110, Dropped: 1,
+		QueueDepth: 10, Capacity: 8192, EnqueueRate: 42,
+	}
+	line = queueReportLine("Pulse", afterFirst, afterSecond, 10*time.Second)
+	want = "Stats Pulse Queue: depth=10/8192 enqueued=2.0/s dequeued=2.0/s dropped_delta=0"
+	if line != want {
+		t.Errorf("second report:\n got %q\nwant %q", line, want)
+	}
+
+	poolFirst := queue.WorkerPoolStats{
+		RunningWorkers: 8, CurrentCapacity: 16,
+		TasksSubmitted: 100, TasksCompleted: 95,
+	}
+	poolSecond := queue.WorkerPoolStats{
+		RunningWorkers: 8, CurrentCapacity: 16, WaitingTasks: 2,
+		TasksSubmitted: 130, TasksCompleted: 120,
+	}
+	line = poolReportLine("Pulse", poolFirst, poolSecond)
+	want = "Stats Pulse Workers: running=8 capacity=16 waiting=2 submitted_delta=30 completed_delta=25"
+	if line != want {
+		t.Errorf("pool report:\n got %q\nwant %q", line, want)
+	}
+}
+
+// TestStatsReporter_SnapshotAdvances checks reportStats returns a fresh
+// snapshot, so activity counted in one report is not counted again in the
+// next.
+func TestStatsReporter_SnapshotAdvances(t *testing.T) {
+	t.Parallel()
+	ctrl, err := NewController(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	prev := ctrl.takeStatsSnapshot()
+	for i := 0; i < 5; i++ {
+		if err := ctrl.pulseQueue.Enqueue(&slowJob{}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	snap := ctrl.reportStats(prev)
+	if got := snap.queues["Pulse"].Enqueued - prev.queues["Pulse"].Enqueued; got != 5 {
+		t.Fatalf("first interval enqueued delta = %d, want 5", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := ctrl.pulseQueue.Enqueue(&slowJob{}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	cur := ctrl.reportStats(snap)
+	if got := cur.queues["Pulse"].Enqueued - snap.queues["Pulse"].Enqueued; got != 3 {
+		t.Fatalf("second interval enqueued delta = %d, want only the 3 new jobs", got)
+	}
+}
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"sync"
+
+	"cpra/internal/events"
+	"cpra/internal/jobs"
+)
+
+// ResultKind selects which job results a subscriber receives.
+type ResultKind uint8
+
+const (
+	// ResultKindPulse delivers pulse check results.
+	ResultKindPulse ResultKind = iota
+	// ResultKindIntervention delivers intervention results.
+	ResultKindIntervention
+	// ResultKindCode delivers code alert results.
+	ResultKindCode
+	// ResultKindAll delivers results of every kind.
+	ResultKindAll
+)
+
+// String returns the kind name as used in result payloads and stats.
+func (k ResultKind) String() string {
+	switch k {
+	case ResultKindPulse:
+		return "pulse"
+	case ResultKindIntervention:
+		return "intervention"
+	case ResultKindCode:
+		return "code"
+	case ResultKindAll:
+		return "all"
+	default:
+		return "unknown"
+	}
+}
+
+// SubscriberStats describes one attached result subscriber.
+type SubscriberStats struct {
+	ID        uint64 `json:"id"`
+	Kind      string `json:"kind"`
+	Delivered uint64 `json:"delivered"`
+	Dropped   uint64 `json:"dropped"`
+}
+
+// defaultSubscriberBuffer is used when Subscribe is called with buffer <= 0.
+const defaultSubscriberBuffer = 256
+
+type resultSubscriber struct {
+	kind      ResultKind
+	ch        chan jobs.Result
+	delivered uint64
+	dropped   uint64
+}
+
+// resultSubscribers fans routed job results out to external subscribers.
+//
+// Delivery is best-effort: publish never blocks the result hot path, so a
+// subscriber that does not keep up has results dropped (counted per
+// subscriber). All channel sends and closes happen under the registry
+// mutex, which makes detaching a subscriber safe while results are
+// flowing.
+type resultSubscribers struct {
+	mu     sync.Mutex
+	nextID uint64
+	subs   map[uint64]*resultSubscriber
+	closed bool
+}
+
+func newResultSubscribers() *resultSubscribers {
+	return &resultSubscribers{subs: make(map[uint64]*resultSubscriber)}
+}
+
+// add registers a subscriber and returns its channel and a detach func.
+// The detach func is idempotent and closes the channel.
+func (s *resultSubscribers) add(kind ResultKind, buffer int) (<-chan jobs.Result, func()) {
+	if buffer <= 0 {
+		buffer = defaultSubscriberBuffer
+	}
+	sub := &resultSubscriber{kind: kind, ch: make(chan jobs.Result, buffer)}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		// The controller has already shut down; hand back a closed channel
+		// so the subscriber's receive loop terminates immediately.
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	s.nextID++
+	id := s.nextID
+	s.subs[id] = sub
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if sub, ok := s.subs[id]; ok {
+			delete(s.subs, id)
+			close(sub.ch)
+		}
+	}
+	return sub.ch, cancel
+}
+
+// publish delivers a result batch of the given kind to matching
+// subscribers without blocking; full subscriber channels drop.
+func (s *resultSubscribers) publish(kind ResultKind, results []jobs.Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || len(s.subs) == 0 {
+		return
+	}
+	for _, sub := range s.subs {
+		if sub.kind != ResultKindAll && sub.kind != kind {
+			continue
+		}
+		for _, result := range results {
+			select {
+			case sub.ch <- result:
+				sub.delivered++
+			default:
+				sub.dropped++
+			}
+		}
+	}
+}
+
+// stats returns a snapshot of all attached subscribers.
+func (s *resultSubscribers) stats() []SubscriberStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.subs) == 0 {
+		return nil
+	}
+	out := make([]SubscriberStats, 0, len(s.subs))
+	for id, sub := range s.subs {
+		out = append(out, SubscriberStats{
+			ID:        id,
+			Kind:      sub.kind.String(),
+			Delivered: sub.delivered,
+			Dropped:   sub.dropped,
+		})
+	}
+	return out
+}
+
+// closeAll closes every subscriber channel and rejects further adds.
+// Called once during controller shutdown, after the pools have drained.
+func (s *resultSubscribers) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for id, sub := range s.subs {
+		delete(s.subs, id)
+		close(sub.ch)
+	}
+}
+
+// resultKindFromLabel maps the router's payload-type label to a ResultKind.
+func resultKindFromLabel(label string) (ResultKind, bool) {
+	switch label {
+	case "pulse":
+		return ResultKindPulse, true
+	case "intervention":
+		return ResultKindIntervention, true
+	case "code":
+		return ResultKindCode, true
+	default:
+		return 0, false
+	}
+}
+
+// Subscribe attaches an external consumer to job results of the given kind.
+//
+// The returned channel receives copies of results as they are routed;
+// delivery is best-effort and never blocks job processing, so a slow
+// subscriber has results dropped (visible via Stats). The cancel func
+// detaches the subscriber and closes the channel; it is safe to call more
+// than once. Subscribing works before or after Start, and Stop closes all
+// remaining subscriber channels.
+func (c *Controller) Subscribe(kind ResultKind, buffer int) (<-chan jobs.Result, func()) {
+	return c.subs.add(kind, buffer)
+}
+
+// Events returns the bus of semantic monitor transition events (color
+// changes, intervention outcomes, recoveries). Consumers attach with
+// events.Bus.Subscribe; like result subscribers, delivery is best-effort
+// with per-subscriber drop counters, and Stop detaches all consumers.
+func (c *Controller) Events() *events.Bus {
+	return c.eventBus
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+)
+
+func pulseResults(n int) []jobs.Result {
+	results := make([]jobs.Result, n)
+	for i := range results {
+		results[i] = jobs.Result{Payload: jobs.Payload{Kind: jobs.KindPulse}}
+	}
+	return results
+}
+
+func TestResultKind_String(t *testing.T) {
+	t.Parallel()
+
+	cases := map[ResultKind]string{
+		ResultKindPulse:        "pulse",
+		ResultKindIntervention: "intervention",
+		ResultKindCode:         "code",
+		ResultKindAll:          "all",
+		ResultKind(42):         "unknown",
+	}
+	for kind, want := range cases {
+		if got := kind.String(); got != want {
+			t.Errorf("ResultKind(%d).String() = %q, want %q", kind, got, want)
+		}
+	}
+}
+
+func TestResultSubscribers_KindFiltering(t *testing.T) {
+	t.Parallel()
+
+	subs := newResultSubscribers()
+	pulseCh, _ := subs.add(ResultKindPulse, 10)
+	allCh, _ := subs.add(ResultKindAll, 10)
+
+	subs.publish(ResultKindPulse, pulseResults(2))
+	subs.publish(ResultKindCode, []jobs.Result{{Payload: jobs.Payload{Kind: jobs.KindCode}}})
+
+	if got := len(pulseCh); got != 2 {
+		t.Errorf("Pulse subscriber received %d results, want 2", got)
+	}
+	if got := len(allCh); got != 3 {
+		t.Errorf("All subscriber received %d results, want 3", got)
+	}
+}
+
+func TestResultSubscribers_DropsWhenFull(t *testing.T) {
+	t.Parallel()
+
+	subs := newResultSubscribers()
+	ch, _ := subs.add(ResultKindPulse, 1)
+
+	subs.publish(ResultKindPulse, pulseResults(3))
+
+	if got := len(ch); got != 1 {
+		t.Errorf("Subscriber channel holds %d results, want 1", got)
+	}
+	stats := subs.stats()
+	if len(stats) != 1 {
+		t.Fatalf("Expected 1 subscriber in stats, got %d", len(stats))
+	}
+	if stats[0].Delivered != 1 || stats[0].Dropped != 2 {
+		t.Errorf("Stats = delivered %d dropped %d, want 1/2", stats[0].Delivered, stats[0].Dropped)
+	}
+}
+
+func TestResultSubscribers_CancelIsIdempotent(t *testing.T) {
+	t.Parallel()
+
+	subs := newResultSubscribers()
+	ch, cancel := subs.add(ResultKindPulse, 4)
+
+	cancel()
+	cancel() // Double-cancel must not panic
+
+	if _, ok := <-ch; ok {
+		t.Error("Channel should be closed after cancel")
+	}
+
+	// Publishing after cancel must not panic or deliver.
+	subs.publish(ResultKindPulse, pulseResults(1))
+	if got := subs.stats(); got != nil {
+		t.Errorf("Expected no subscribers after cancel, got %v", got)
+	}
+}
+
+func TestResultSubscribers_CloseAll(t *testing.T) {
+	t.Parallel()
+
+	subs := newResultSubscribers()
+	ch1, cancel1 := subs.add(ResultKindPulse, 4)
+	ch2, _ := subs.add(ResultKindAll, 4)
+
+	subs.closeAll()
+
+	for _, ch := range []<-chan jobs.Result{ch1, ch2} {
+		if _, ok := <-ch; ok {
+			t.Error("Channel should be closed after closeAll")
+		}
+	}
+
+	// Cancel after closeAll must not double-close.
+	cancel1()
+
+	// Adds after closeAll hand back an already-closed channel.
+	ch3, _ := subs.add(ResultKindCode, 4)
+	if _, ok := <-ch3; ok {
+		t.Error("Channel from post-shutdown Subscribe should be closed")
+	}
+}
+
+// TestController_Subscribe_Lifecycle attaches two subscribers, detaches one
+// while results are flowing, and verifies that Stop closes the remaining
+// subscriber channel without panics.
+func TestController_Subscribe_Lifecycle(t *testing.T) {
+	cfg := DefaultConfig()
+	ctrl, err := NewController(cfg)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	ch1, cancel1 := ctrl.Subscribe(ResultKindPulse, 64)
+	ch2, _ := ctrl.Subscribe(ResultKindAll, 64)
+
+	if err := ctrl.Start(nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// Drain both subscribers until their channels close.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for _, ch := range []<-chan jobs.Result{ch1, ch2} {
+		go func(ch <-chan jobs.Result) {
+			defer wg.Done()
+			for range ch {
+			}
+		}(ch)
+	}
+
+	// Publish concurrently, as the routers would, while one subscriber
+	// detaches mid-run.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			ctrl.subs.publish(ResultKindPulse, pulseResults(5))
+			if i == 50 {
+				cancel1()
+			}
+		}
+	}()
+	<-done
+
+	ctrl.Stop()
+
+	// Stop must close the remaining channel so both drain goroutines exit.
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscriber channels were not closed on Stop")
+	}
+
+	// Subscribing after Stop yields a closed channel rather than a leak.
+	ch3, _ := ctrl.Subscribe(ResultKindCode, 4)
+	if _, ok := <-ch3; ok {
+		t.Error("Channel from post-Stop Subscribe should be closed")
+	}
+}
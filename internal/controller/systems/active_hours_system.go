@@ -0,0 +1,167 @@
+package systems
+
+import (
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// ActiveHoursSystem drives per-monitor check schedules. Each tick it
+// compares the clock against every scheduled monitor's compiled active
+// hours: leaving the window adds the Disabled tag so the dispatch systems
+// skip the monitor, and re-entering removes it, resets the failure streaks,
+// and schedules an immediate first check — time spent outside the window
+// never counts toward streaks. A monitor also held by an active maintenance
+// window stays suppressed until both release; the schedule still resets the
+// streaks, so it takes precedence for counting. Explicit disables — admin
+// API, enabled: false, panic auto-disable — are not the schedule's to lift,
+// so a window entry leaves them suppressed too.
+type ActiveHoursSystem struct {
+	logger      Logger
+	stateLogger *StateLogger
+	world       *ecs.World
+
+	filter            *ecs.Filter2[components.MonitorState, components.Schedule]
+	stateMapper       *ecs.Map1[components.MonitorState]
+	metaMapper        *ecs.Map1[components.MonitorMeta]
+	scheduleMapper    *ecs.Map1[components.Schedule]
+	maintenanceMapper *ecs.Map1[components.Maintenance]
+	disabledMapper    *ecs.Map1[components.Disabled]
+	explicitMapper    *ecs.Map1[components.ExplicitlyDisabled]
+
+	// now is replaceable so tests can cross window boundaries without
+	// sleeping through them.
+	now func() time.Time
+}
+
+// NewActiveHoursSystem creates a new ActiveHoursSystem.
+func NewActiveHoursSystem(world *ecs.World, logger Logger, stateLogger *StateLogger) *ActiveHoursSystem {
+	return &ActiveHoursSystem{
+		world:             world,
+		logger:            logger,
+		stateLogger:       stateLogger,
+		filter:            ecs.NewFilter2[components.MonitorState, components.Schedule](world),
+		stateMapper:       ecs.NewMap1[components.MonitorState](world),
+		metaMapper:        ecs.NewMap1[components.MonitorMeta](world),
+		scheduleMapper:    ecs.NewMap1[components.Schedule](world),
+		maintenanceMapper: ecs.NewMap1[components.Maintenance](world),
+		disabledMapper:    ecs.NewMap1[components.Disabled](world),
+		explicitMapper:    ecs.NewMap1[components.ExplicitlyDisabled](world),
+		now:               time.Now,
+	}
+}
+
+// SetClock routes the window-edge decisions through c. A nil clock is ignored.
+func (s *ActiveHoursSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+func (s *ActiveHoursSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+// Update detects window entry and exit edges and applies them.
+func (s *ActiveHoursSystem) Update(_ *ecs.World) {
+	now := s.now()
+
+	// Collect edge transitions first: the Disabled tag changes the entity's
+	// archetype, which must not happen while the query is open.
+	var entering, leaving []ecs.Entity
+	query := s.filter.Query()
+	for query.Next() {
+		_, sched := query.Get()
+		inWindow := sched.InWindow(now)
+		if inWindow == sched.Active {
+			continue
+		}
+		if inWindow {
+			entering = append(entering, query.Entity())
+		} else {
+			leaving = append(leaving, query.Entity())
+		}
+	}
+
+	for _, ent := range leaving {
+		s.leaveWindow(ent)
+	}
+	for _, ent := range entering {
+		s.enterWindow(ent)
+	}
+}
+
+// leaveWindow suppresses a monitor that is outside its active hours.
+func (s *ActiveHoursSystem) leaveWindow(ent ecs.Entity) {
+	if !s.world.Alive(ent) {
+		return
+	}
+	// Mutate the component before the tag add moves the entity to another
+	// archetype and invalidates outstanding component pointers.
+	s.scheduleMapper.Get(ent).Active = false
+	if !s.disabledMapper.HasAll(ent) {
+		s.disabledMapper.Add(ent, &components.Disabled{})
+	}
+
+	state := s.stateMapper.Get(ent)
+	if state == nil {
+		return
+	}
+	oldState := *state
+
+	// Mirror MaintenanceSystem: drop pending work so no job completes into
+	// a suppressed monitor.
+	state.SetPulsePending(false)
+	state.SetInterventionPending(false)
+	state.SetCodePending(false)
+
+	s.stateLogger.LogTransition(ent, oldState, *state)
+	s.logger.Infow("Monitor left its active hours", "monitor_name", s.metaMapper.Get(ent).Name)
+}
+
+// enterWindow re-enables a monitor whose active hours have begun. The
+// streaks restart from zero and the first check runs immediately, as if the
+// monitor had just been loaded.
+func (s *ActiveHoursSystem) enterWindow(ent ecs.Entity) {
+	if !s.world.Alive(ent) {
+		return
+	}
+	// Only release suppression the schedule owns: a monitor also held by a
+	// maintenance window or disabled explicitly stays suppressed.
+	s.scheduleMapper.Get(ent).Active = true
+	if s.disabledMapper.HasAll(ent) && !s.inMaintenance(ent) && !s.explicitMapper.HasAll(ent) {
+		s.disabledMapper.Remove(ent)
+	}
+
+	state := s.stateMapper.Get(ent)
+	if state == nil {
+		return
+	}
+	oldState := *state
+
+	// The suppressed period must not count toward any streak.
+	state.ConsecutiveFailures = 0
+	state.PulseFailures = 0
+	state.StreakFailedAttempts = 0
+	state.RecoveryStreak = 0
+	state.DegradedLatencyStreak = 0
+	state.SetPulseFirstCheck(true)
+
+	s.stateLogger.LogTransition(ent, oldState, *state)
+	s.logger.Infow("Monitor entered its active hours", "monitor_name", s.metaMapper.Get(ent).Name)
+}
+
+// inMaintenance reports whether the monitor is also held by an active
+// maintenance window, in which case the Disabled tag stays until the
+// MaintenanceSystem releases it.
+func (s *ActiveHoursSystem) inMaintenance(ent ecs.Entity) bool {
+	return s.maintenanceMapper.HasAll(ent) && s.maintenanceMapper.Get(ent).Active
+}
+
+// Finalize is a no-op for this system.
+func (s *ActiveHoursSystem) Finalize(_ *ecs.World) {}
@@ -0,0 +1,196 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// newScheduleTestEntity creates a monitor with the given compiled schedule.
+func newScheduleTestEntity(t *testing.T, world *ecs.World, block schema.Schedule) ecs.Entity {
+	t.Helper()
+
+	sched, err := components.CompileSchedule(block)
+	if err != nil {
+		t.Fatalf("CompileSchedule failed: %v", err)
+	}
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.Schedule](world)
+	return mapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "partner-api"},
+		&sched,
+	)
+}
+
+// TestActiveHoursSystem_WindowLifecycle crosses a business-hours boundary in
+// a non-UTC zone with an injected clock: scheduling stops when the window
+// closes, pending work and streaks are dropped, and re-entry behaves like a
+// fresh first check.
+func TestActiveHoursSystem_WindowLifecycle(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	system := NewActiveHoursSystem(&world, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+
+	// Tuesday 10:00 in Berlin (09:00 UTC): inside 08:00-20:00 mon-fri.
+	current := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	system.now = func() time.Time { return current }
+
+	ent := newScheduleTestEntity(t, &world, schema.Schedule{
+		ActiveHours: "08:00-20:00",
+		Days:        "mon-fri",
+		Timezone:    "Europe/Berlin",
+	})
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	disabled := ecs.NewMap1[components.Disabled](&world)
+	schedule := ecs.NewMap1[components.Schedule](&world)
+
+	// Inside the window nothing happens.
+	system.Update(&world)
+	if disabled.HasAll(ent) {
+		t.Fatal("monitor disabled inside its active hours")
+	}
+
+	// Accumulate a streak and pending work, then cross 20:00 Berlin time.
+	state := stateMapper.Get(ent)
+	state.PulseFailures = 2
+	state.ConsecutiveFailures = 2
+	state.Flags |= components.StatePulsePending
+	current = time.Date(2026, 3, 10, 20, 30, 0, 0, berlin)
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor not disabled after its active hours ended")
+	}
+	if schedule.Get(ent).Active {
+		t.Error("schedule still marked active outside the window")
+	}
+	state = stateMapper.Get(ent)
+	if state.Flags&components.StatePulsePending != 0 {
+		t.Error("pending pulse not dropped on window exit")
+	}
+
+	// 19:59 UTC the same evening is 20:59 in Berlin: still outside. A UTC
+	// evaluation would have re-enabled the monitor here.
+	current = time.Date(2026, 3, 10, 19, 59, 0, 0, time.UTC)
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor re-enabled by a UTC boundary; window must follow Europe/Berlin")
+	}
+
+	// Wednesday 08:05 Berlin: the window reopens with fresh streaks and an
+	// immediate first check.
+	current = time.Date(2026, 3, 11, 8, 5, 0, 0, berlin)
+	system.Update(&world)
+	if disabled.HasAll(ent) {
+		t.Fatal("monitor still disabled after its active hours began")
+	}
+	state = stateMapper.Get(ent)
+	if state.Flags&components.StatePulseFirstCheck == 0 {
+		t.Error("re-entry did not schedule an immediate first check")
+	}
+	if state.PulseFailures != 0 || state.ConsecutiveFailures != 0 {
+		t.Errorf("streaks not reset on re-entry: pulse=%d consecutive=%d",
+			state.PulseFailures, state.ConsecutiveFailures)
+	}
+
+	// Saturday 12:00 Berlin: right hours, wrong day.
+	current = time.Date(2026, 3, 14, 12, 0, 0, 0, berlin)
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor not disabled on an inactive weekday")
+	}
+}
+
+// TestActiveHoursSystem_MaintenanceKeepsSuppression checks that a window
+// re-entry does not release a monitor still held by an active maintenance
+// window, while the streak reset applies regardless.
+func TestActiveHoursSystem_MaintenanceKeepsSuppression(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	system := NewActiveHoursSystem(&world, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	// 22:30 UTC: outside 08:00-20:00.
+	current := time.Date(2026, 3, 10, 22, 30, 0, 0, time.UTC)
+	system.now = func() time.Time { return current }
+
+	sched, err := components.CompileSchedule(schema.Schedule{ActiveHours: "08:00-20:00"})
+	if err != nil {
+		t.Fatalf("CompileSchedule failed: %v", err)
+	}
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.Schedule, components.Maintenance](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "partner-api"},
+		&sched,
+		&components.Maintenance{Active: true},
+	)
+	disabled := ecs.NewMap1[components.Disabled](&world)
+
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor not disabled outside its active hours")
+	}
+
+	// The window reopens while maintenance is still active: the Disabled
+	// tag stays until the MaintenanceSystem releases it.
+	current = time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("active maintenance window did not keep the monitor suppressed")
+	}
+	state := ecs.NewMap[components.MonitorState](&world).Get(ent)
+	if state.Flags&components.StatePulseFirstCheck == 0 {
+		t.Error("re-entry did not mark the first check despite maintenance")
+	}
+}
+
+// TestActiveHoursSystem_ExplicitDisableKeptAcrossWindowOpen checks that a
+// window entry does not lift an explicit disable: a monitor disabled via the
+// admin API (or auto-disabled for crash-looping) while outside its hours
+// must still be disabled when the window opens.
+func TestActiveHoursSystem_ExplicitDisableKeptAcrossWindowOpen(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	system := NewActiveHoursSystem(&world, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	// 22:30 UTC: outside 08:00-20:00.
+	current := time.Date(2026, 3, 10, 22, 30, 0, 0, time.UTC)
+	system.now = func() time.Time { return current }
+
+	ent := newScheduleTestEntity(t, &world, schema.Schedule{ActiveHours: "08:00-20:00"})
+	disabled := ecs.NewMap1[components.Disabled](&world)
+	explicit := ecs.NewMap1[components.ExplicitlyDisabled](&world)
+
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor not disabled outside its active hours")
+	}
+
+	// The operator disables the monitor overnight, as DisableMonitor does
+	// when a window already holds the tag: only the marker is added.
+	explicit.Add(ent, &components.ExplicitlyDisabled{})
+
+	// The window reopens: the explicit disable is not the schedule's to
+	// release, so the monitor stays suppressed until EnableMonitor.
+	current = time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("window entry lifted an explicit disable")
+	}
+}
@@ -0,0 +1,218 @@
+package systems
+
+import (
+	"cpra/internal/audit"
+	"cpra/internal/controller/components"
+	"cpra/internal/events"
+	"cpra/internal/jobs"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// BatchCodeResultSystem processes the results of dispatched code alerts.
+// It processes batches of results passed directly from the result router.
+type BatchCodeResultSystem struct {
+	world       *ecs.World
+	logger      Logger
+	stateLogger *StateLogger
+
+	// Mappers for efficient component access
+	stateMapper  *ecs.Map[components.MonitorState]
+	metaMapper   *ecs.Map[components.MonitorMeta]
+	statusMapper *ecs.Map[components.CodeStatus]
+	ResultChan   <-chan []jobs.Result
+	drain        resultDrain
+	audit        audit.Sink  // nil unless auditing is configured
+	bus          *events.Bus // nil unless an event bus is attached
+}
+
+// NewBatchCodeResultSystem creates a new BatchCodeResultSystem.
+func NewBatchCodeResultSystem(world *ecs.World, results <-chan []jobs.Result, logger Logger, stateLogger *StateLogger) *BatchCodeResultSystem {
+	return &BatchCodeResultSystem{
+		world:        world,
+		logger:       logger,
+		stateLogger:  stateLogger,
+		stateMapper:  ecs.NewMap[components.MonitorState](world),
+		metaMapper:   ecs.NewMap[components.MonitorMeta](world),
+		statusMapper: ecs.NewMap[components.CodeStatus](world),
+		ResultChan:   results,
+		drain:        newResultDrain(),
+	}
+}
+
+func (s *BatchCodeResultSystem) Initialize(_ *ecs.World) {
+}
+
+// SetResultBudget overrides the per-tick result and time budgets; see
+// resultDrain. Non-positive values keep the defaults.
+func (s *BatchCodeResultSystem) SetResultBudget(maxResults int, maxTime time.Duration) {
+	s.drain.setBudget(maxResults, maxTime)
+}
+
+// SetAuditSink routes every applied notification outcome to the sink.
+func (s *BatchCodeResultSystem) SetAuditSink(sink audit.Sink) {
+	s.audit = sink
+}
+
+// SetEventBus publishes every delivered color transition to the bus.
+func (s *BatchCodeResultSystem) SetEventBus(bus *events.Bus) {
+	s.bus = bus
+}
+
+// ConsumedLastTick reports how many results the last Update applied.
+func (s *BatchCodeResultSystem) ConsumedLastTick() int64 { return s.drain.ConsumedLastTick() }
+
+// ResultBacklog reports how many received results await application.
+func (s *BatchCodeResultSystem) ResultBacklog() int64 { return s.drain.Backlog() }
+
+func (s *BatchCodeResultSystem) Update(_ *ecs.World) {
+	if s.ResultChan == nil {
+		// Channel closed; finish applying any carried-over batches.
+		s.drain.apply(s.ProcessBatch)
+		return
+	}
+
+loop:
+	for {
+		select {
+		case res, ok := <-s.ResultChan:
+			if !ok {
+				s.ResultChan = nil
+				break loop
+			}
+			s.drain.add(res)
+		default:
+			break loop
+		}
+	}
+
+	// Apply under the per-tick budget; the remainder carries over so a
+	// burst cannot consume the whole tick.
+	s.drain.apply(s.ProcessBatch)
+}
+
+// ProcessBatch processes a batch of code alert results.
+func (s *BatchCodeResultSystem) ProcessBatch(results []jobs.Result) {
+	startTime := time.Now()
+	processedCount := 0
+
+	for _, result := range results {
+		ent := result.Entity()
+		if !s.world.Alive(ent) {
+			continue
+		}
+
+		state := s.stateMapper.Get(ent)
+		if state == nil {
+			continue
+		}
+
+		// Ensure we are processing a pending code alert.
+		// Note: If entity is not in CodePending state, it means another system already
+		// processed or cancelled this job. This is expected behavior in concurrent systems.
+		if (state.Flags & components.StateCodePending) == 0 {
+			s.logger.Debugw("Entity received stale CodeResult (state already changed)", "entity_id", ent.ID(), "flags", state.Flags)
+			continue
+		}
+
+		processedCount++
+		oldState := *state
+		monitorName := s.metaMapper.Get(ent).Name
+
+		// Extract color from the result payload.
+		color := result.Payload.Color
+		if color == "" {
+			s.logger.Warnw("Entity has CodeResult with no color in payload", "entity_id", ent.ID())
+			continue
+		}
+
+		// Record the per-color outcome; BatchCodeSystem reads LastAlertTime
+		// to enforce cooldown windows.
+		var status *components.ColorCodeStatus
+		prevColor := ""
+		if cs := s.statusMapper.Get(ent); cs != nil {
+			status = cs.Get(color)
+			if s.bus != nil {
+				prevColor = lastDeliveredColor(cs)
+			}
+		}
+
+		if err := result.Error(); err != nil {
+			s.logger.Errorw("Monitor alert failed to send", "monitor_name", monitorName, "color", color, "error", err)
+			if status != nil {
+				status.SetFailure(err)
+			}
+			// On failure, re-flag for retry: clear Pending and set Needed.
+			state.Flags &^= components.StateCodePending
+			state.Flags |= components.StateCodeNeeded
+		} else {
+			s.logger.Infow("Monitor alert sent successfully", "monitor_name", monitorName, "color", color)
+			if status != nil {
+				status.SetSuccess(time.Now())
+			}
+			// On success, clear Pending and PendingColor.
+			state.Flags &^= components.StateCodePending
+			state.PendingColor = components.ColorNone
+
+			// A delivered color is a semantic transition; green means the
+			// monitor is healthy again.
+			if s.bus != nil {
+				kind := events.KindColorChange
+				if color == components.ColorGreen.String() {
+					kind = events.KindRecovered
+				}
+				s.bus.Publish(events.Event{
+					Time:     time.Now(),
+					Monitor:  monitorName,
+					EntityID: uint32(ent.ID()),
+					Kind:     kind,
+					From:     prevColor,
+					To:       color,
+					Labels:   s.metaMapper.Get(ent).Labels,
+				})
+			}
+		}
+		if s.audit != nil {
+			errText := ""
+			if result.Error() != nil {
+				errText = result.Error().Error()
+			}
+			s.audit.Record(audit.Event{
+				Time:     time.Now(),
+				Monitor:  monitorName,
+				EntityID: uint32(ent.ID()),
+				Kind:     audit.KindCode,
+				Action:   color,
+				Success:  result.Error() == nil,
+				Error:    errText,
+				Attempts: result.Payload.Attempts,
+				Latency:  result.Duration,
+			})
+		}
+		s.stateLogger.LogTransition(ent, oldState, *state)
+	}
+
+	if processedCount > 0 {
+		dur := time.Since(startTime)
+		LogSystemPerformance(s.logger, "BatchCodeResultSystem", processedCount, dur)
+	}
+}
+
+// lastDeliveredColor returns the most recently delivered alert color, or ""
+// when none has been delivered yet. Log-only records never stamp an alert
+// time, so they do not count as delivered.
+func lastDeliveredColor(cs *components.CodeStatus) string {
+	best := int64(0)
+	bestColor := ""
+	for i := range cs.Status {
+		if t := cs.Status[i].LastAlertTime; t > 0 && t >= best {
+			best = t
+			bestColor = components.ColorCode(i).String()
+		}
+	}
+	return bestColor
+}
+
+// Finalize is a no-op for this system.
+func (s *BatchCodeResultSystem) Finalize(_ *ecs.World) {}
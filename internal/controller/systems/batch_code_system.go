@@ -0,0 +1,471 @@
+package systems
+
+import (
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+	"cpra/internal/queue"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// jobInfo is a helper struct to associate a job with its entity and color for batch processing.
+type jobInfo struct {
+	Job      jobs.Job
+	Color    string
+	OldState components.MonitorState
+	Entity   ecs.Entity
+}
+
+// BatchCodeSystem processes entities that need a code alert dispatched.
+// It determines the correct color based on the entity's state and enqueues the job.
+type BatchCodeSystem struct {
+	queue       queue.Queue
+	logger      Logger
+	stateLogger *StateLogger
+	world       *ecs.World
+	filter      *ecs.Filter3[components.MonitorState, components.CodeConfig, components.CodeStatus]
+	stateMapper *ecs.Map1[components.MonitorState]
+	metaMapper  *ecs.Map1[components.MonitorMeta]
+	registry    *components.ConfigRegistry
+	jobInfoPool *sync.Pool
+	batchSize   int
+
+	// Dependency suppression: monitors behind a failed depends_on parent
+	// have their code alerts held instead of dispatched.
+	depsFilter        *ecs.Filter2[components.MonitorState, components.DependsOn]
+	depsMapper        *ecs.Map1[components.DependsOn]
+	pulseConfigMapper *ecs.Map1[components.PulseConfig]
+
+	// suppressed counts alerts skipped by a cooldown window.
+	suppressed atomic.Uint64
+	// depSuppressed counts alerts held back by a failed dependency.
+	depSuppressed atomic.Uint64
+	// logOnly counts transitions recorded without dispatch (dispatch: false).
+	logOnly atomic.Uint64
+	now     func() time.Time // overridable for fake-clock tests
+	gate    *StandbyGate     // nil unless HA mode is on; closed while standby
+}
+
+// NewBatchCodeSystem creates a new BatchCodeSystem.
+func NewBatchCodeSystem(world *ecs.World, q queue.Queue, batchSize int, logger Logger, stateLogger *StateLogger) *BatchCodeSystem {
+	return &BatchCodeSystem{
+		world:       world,
+		queue:       q,
+		logger:      logger,
+		stateLogger: stateLogger,
+		registry:    components.DefaultConfigRegistry(),
+		batchSize:   batchSize,
+		filter: ecs.NewFilter3[components.MonitorState, components.CodeConfig, components.CodeStatus](world).
+			Without(ecs.C[components.Disabled]()),
+		depsFilter: ecs.NewFilter2[components.MonitorState, components.DependsOn](world).
+			Without(ecs.C[components.Disabled]()),
+		depsMapper:        ecs.NewMap1[components.DependsOn](world),
+		pulseConfigMapper: ecs.NewMap1[components.PulseConfig](world),
+		stateMapper:       ecs.NewMap1[components.MonitorState](world),
+		metaMapper:        ecs.NewMap1[components.MonitorMeta](world),
+		now:               time.Now,
+		jobInfoPool: &sync.Pool{
+			New: func() interface{} {
+				s := make([]jobInfo, 0, batchSize)
+				return &s
+			},
+		},
+	}
+}
+
+// SuppressedCount returns how many code alerts were skipped by cooldown
+// windows since start.
+func (s *BatchCodeSystem) SuppressedCount() uint64 { return s.suppressed.Load() }
+
+// DependencySuppressedCount returns how many code alerts were held back by a
+// failed dependency since start.
+func (s *BatchCodeSystem) DependencySuppressedCount() uint64 { return s.depSuppressed.Load() }
+
+// LogOnlyCount returns how many color transitions were recorded without
+// dispatching a notification (dispatch: false) since start.
+func (s *BatchCodeSystem) LogOnlyCount() uint64 { return s.logOnly.Load() }
+
+// SetClock routes the cooldown decisions through c. A nil clock is ignored.
+func (s *BatchCodeSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+// SetStandbyGate makes dispatch honor the HA leadership gate. A nil gate
+// (the default) means always dispatch.
+func (s *BatchCodeSystem) SetStandbyGate(g *StandbyGate) {
+	s.gate = g
+}
+
+func (s *BatchCodeSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+	if s.depsFilter != nil {
+		s.depsFilter.Register()
+	}
+}
+
+// Update finds and processes all monitors that need a code alert.
+func (s *BatchCodeSystem) Update(_ *ecs.World) {
+	if s.gate != nil && s.gate.Standby() {
+		return
+	}
+	startTime := time.Now()
+	stats := s.queue.Stats()
+	if stats.Capacity > 0 && stats.QueueDepth >= int(float64(stats.Capacity)*0.9) {
+		s.logger.Debugw("Code queue saturated", "depth", stats.QueueDepth, "capacity", stats.Capacity)
+	}
+
+	// Recovered parents first, so released monitors alert this tick.
+	s.releaseDependencySuppressed()
+
+	query := s.filter.Query()
+
+	var tokens int
+	if stats.Capacity <= 0 {
+		tokens = s.batchSize
+		if tokens <= 0 {
+			tokens = 1
+		}
+	} else {
+		free := stats.Capacity - stats.QueueDepth
+		if free <= 0 {
+			return
+		}
+		tokens = int(float64(free) * 0.8)
+		if tokens <= 0 {
+			tokens = free
+		}
+	}
+
+	earlyExit := false
+	suppressedByDep := 0
+
+	jobInfoPtr := s.jobInfoPool.Get().(*[]jobInfo)
+	jobsToProcess := (*jobInfoPtr)[:0]
+	processedCount := 0
+
+	defer func() {
+		s.jobInfoPool.Put(jobInfoPtr)
+	}()
+
+	now := s.now()
+	for query.Next() {
+		ent := query.Entity()
+		state, codeConfig, codeStatus := query.Get()
+
+		// Process only entities that need a code alert.
+		if (state.Flags & components.StateCodeNeeded) == 0 {
+			continue
+		}
+
+		// Skip if a code job is already in flight to prevent race conditions.
+		// Per FSM pattern: only one job of a type should be in-flight per entity.
+		// The current job will complete, then the next color (if any) will be processed.
+		if (state.Flags & components.StateCodePending) != 0 {
+			continue
+		}
+
+		color := state.PendingColor
+		if color == components.ColorNone {
+			// This should not happen if StateCodeNeeded is set, but as a safeguard:
+			state.Flags &^= components.StateCodeNeeded
+			continue
+		}
+
+		// Honor dispatch flag and presence of color config before enqueuing
+		if color >= components.MaxColors {
+			state.Flags &^= components.StateCodeNeeded
+			continue
+		}
+
+		// Hold failure alerts while any dependency is down: the parent's own
+		// alert covers the outage, and the release pass re-raises this one if
+		// the monitor is still failing once the parent recovers. Green is
+		// never held so incident bookkeeping stays consistent.
+		if color != components.ColorGreen && s.depsMapper.HasAll(ent) {
+			if deps := s.depsMapper.Get(ent); s.dependencyFailed(deps.Parents) {
+				state.Flags |= components.StateDependencySuppressed
+				state.Flags &^= components.StateCodeNeeded
+				suppressedByDep++
+				s.depSuppressed.Add(1)
+				continue
+			}
+		}
+		cfg, ok := s.registry.Lookup(codeConfig.Configs[color])
+		if !ok || cfg.Notify == "" {
+			s.logger.Warnw("Entity missing code config; clearing pending code", "entity_id", ent.ID(), "color", color)
+			state.Flags &^= components.StateCodeNeeded
+			continue
+		}
+		// dispatch: false means "log only": the transition completes and is
+		// recorded here, but no external notification job is enqueued. The
+		// config is re-resolved from the registry every tick, so a reload
+		// flipping the flag takes effect on the next transition.
+		if !cfg.Dispatch {
+			oldState := *state
+			state.Flags &^= components.StateCodeNeeded
+			state.PendingColor = components.ColorNone
+			codeStatus.Status[color].SetLogOnly(now)
+			s.logOnly.Add(1)
+			s.logger.Infow("Code alert recorded without dispatch (dispatch disabled)",
+				"monitor_name", s.metaMapper.Get(ent).Name, "color", color, "dispatched", false)
+			s.stateLogger.LogTransition(ent, oldState, *state)
+			continue
+		}
+
+		// Suppress repeats of the same color delivered within the cooldown
+		// window, so a flapping monitor does not flood the notify target.
+		// Each color has its own timestamp, so a different color always
+		// breaks through. Failed deliveries clear StatusSuccess and are
+		// never suppressed, keeping retries intact.
+		if cfg.Cooldown > 0 {
+			st := &codeStatus.Status[color]
+			if st.Flags&components.StatusSuccess != 0 &&
+				now.Sub(st.GetLastAlertTime()) < cfg.Cooldown {
+				state.Flags &^= components.StateCodeNeeded
+				state.PendingColor = components.ColorNone
+				s.suppressed.Add(1)
+				s.logger.Debugw("Code alert suppressed by cooldown", "monitor_name", s.metaMapper.Get(ent).Name, "color", color)
+				continue
+			}
+		}
+
+		// Construct schema.CodeConfig from component to create job JIT.
+		schemaCfg := schema.CodeConfig{
+			Dispatch: cfg.Dispatch,
+			Notify:   cfg.Notify,
+			Config:   cfg.Config, // This is already the correct schema type (CodeNotification interface)
+		}
+
+		// Inverted monitors get alert templates describing an unexpected
+		// response rather than an outage.
+		inverted := s.pulseConfigMapper.HasAll(ent) && s.pulseConfigMapper.Get(ent).Invert
+		createJob := jobs.CreateCodeJob
+		if inverted {
+			createJob = jobs.CreateInvertedCodeJob
+		}
+		monitorName := s.metaMapper.Get(ent).Name
+		job, err := createJob(monitorName, schemaCfg, ent, color.String())
+		if err != nil {
+			s.logger.Errorw("Failed to create code job", "error", err, "entity_id", ent.ID())
+			state.Flags &^= components.StateCodeNeeded
+			continue
+		}
+		if job == nil || isNilJob(job) {
+			s.logger.Warnw("Entity needs code alert, but job creation returned nil", "entity_id", ent.ID(), "color", color)
+			// Clear the flag if no job is found to prevent spinning.
+			state.Flags &^= components.StateCodeNeeded
+			continue
+		}
+
+		// Notify groups can route this color to more than one target; wrap
+		// them into a single fan-out job so one result comes back per entity.
+		job = fanOutCodeJob(s.registry, codeConfig, job, monitorName, ent, color, inverted, s.logger)
+		jobs.ApplyLabels(job, s.metaMapper.Get(ent).Labels)
+
+		jobsToProcess = append(jobsToProcess, jobInfo{Entity: ent, Job: job, Color: color.String()})
+
+		if len(jobsToProcess) >= tokens {
+			s.processBatch(&jobsToProcess)
+			processedCount += len(jobsToProcess)
+			jobsToProcess = jobsToProcess[:0]
+			earlyExit = true
+			break
+		}
+	}
+
+	// Process any remaining entities
+	if earlyExit {
+		query.Close()
+	}
+
+	if len(jobsToProcess) > 0 {
+		s.processBatch(&jobsToProcess)
+		processedCount += len(jobsToProcess)
+	}
+
+	if suppressedByDep > 0 {
+		// One summary line instead of one notification per child.
+		s.logger.Infow("Code alerts suppressed by failed dependencies", "count", suppressedByDep)
+	}
+
+	if processedCount > 0 {
+		dur := time.Since(startTime)
+		LogSystemPerformance(s.logger, "BatchCodeSystem", processedCount, dur)
+	}
+
+}
+
+// dependencyFailed reports whether any of the given parent monitors is
+// currently failed: an open incident, an intervention in progress, or failed
+// checks at or past its own unhealthy threshold.
+func (s *BatchCodeSystem) dependencyFailed(parents []ecs.Entity) bool {
+	const failedFlags = components.StateIncidentOpen |
+		components.StateInterventionNeeded |
+		components.StateInterventionPending
+	for _, parent := range parents {
+		if !s.world.Alive(parent) {
+			continue
+		}
+		state := s.stateMapper.Get(parent)
+		if state == nil {
+			continue
+		}
+		if state.Flags&failedFlags != 0 {
+			return true
+		}
+		threshold := 1
+		if s.pulseConfigMapper.HasAll(parent) {
+			if cfg := s.pulseConfigMapper.Get(parent); cfg.UnhealthyThreshold > 0 {
+				threshold = cfg.UnhealthyThreshold
+			}
+		}
+		if state.ConsecutiveFailures >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseDependencySuppressed clears the suppression flag on monitors whose
+// dependencies have all recovered, re-raising the held alert when the monitor
+// itself is still failing.
+func (s *BatchCodeSystem) releaseDependencySuppressed() {
+	query := s.depsFilter.Query()
+	for query.Next() {
+		state, deps := query.Get()
+		if state.Flags&components.StateDependencySuppressed == 0 {
+			continue
+		}
+		if s.dependencyFailed(deps.Parents) {
+			continue
+		}
+		state.Flags &^= components.StateDependencySuppressed
+		stillFailing := state.Flags&components.StateIncidentOpen != 0 || state.ConsecutiveFailures > 0
+		if stillFailing && state.PendingColor != components.ColorNone && state.PendingColor != components.ColorGreen {
+			state.Flags |= components.StateCodeNeeded
+			s.logger.Infow("Dependency recovered; releasing suppressed code alert",
+				"monitor_name", s.metaMapper.Get(query.Entity()).Name, "color", state.PendingColor)
+		}
+	}
+}
+
+// fanOutCodeJob wraps the primary code job together with jobs for any extra
+// targets registered for this color (resolved from notify groups). With no
+// extras the primary job is returned unchanged, so the common single-target
+// case costs nothing. Targets that fail to build are logged and skipped
+// rather than blocking the others. The inverted flag keeps the extras'
+// templates in line with the primary job's.
+func fanOutCodeJob(reg *components.ConfigRegistry, codeConfig *components.CodeConfig, primary jobs.Job, monitorName string, ent ecs.Entity, color components.ColorCode, inverted bool, logger Logger) jobs.Job {
+	createJob := jobs.CreateCodeJob
+	if inverted {
+		createJob = jobs.CreateInvertedCodeJob
+	}
+	var extra []jobs.Job
+	for _, ex := range codeConfig.Extra {
+		if ex.Color != color {
+			continue
+		}
+		cfg, ok := reg.Lookup(ex.ID)
+		if !ok || cfg.Notify == "" || !cfg.Dispatch {
+			continue
+		}
+		schemaCfg := schema.CodeConfig{
+			Dispatch: cfg.Dispatch,
+			Notify:   cfg.Notify,
+			Config:   cfg.Config,
+		}
+		job, err := createJob(monitorName, schemaCfg, ent, color.String())
+		if err != nil || isNilJob(job) {
+			logger.Errorw("Failed to create fan-out code job", "monitor_name", monitorName, "color", color, "error", err)
+			continue
+		}
+		extra = append(extra, job)
+	}
+	if len(extra) == 0 {
+		return primary
+	}
+	return &jobs.CodeFanoutJob{Jobs: append([]jobs.Job{primary}, extra...), Entity: ent}
+}
+
+// processBatch attempts to enqueue a batch of jobs and updates entity states on success.
+func (s *BatchCodeSystem) processBatch(jobsInfo *[]jobInfo) {
+	stats := s.queue.Stats()
+	if stats.Capacity > 0 && stats.QueueDepth >= int(float64(stats.Capacity)*0.9) {
+		s.logger.Debugw("Code queue near capacity; skipping enqueue", "depth", stats.QueueDepth, "capacity", stats.Capacity)
+		return
+	}
+
+	items := make([]interface{}, 0, len(*jobsInfo))
+	submitted := make([]jobInfo, 0, len(*jobsInfo))
+	now := s.now()
+	for _, info := range *jobsInfo {
+		if isNilJob(info.Job) {
+			s.logger.Warnw("Code job became nil before enqueue; skipping", "entity_id", info.Entity.ID())
+			continue
+		}
+		if !s.world.Alive(info.Entity) {
+			continue
+		}
+		state := s.stateMapper.Get(info.Entity)
+		if state == nil {
+			continue
+		}
+
+		// State might have already been updated if another system intervened; double-check guard.
+		if state.Flags&components.StateCodeNeeded == 0 {
+			continue
+		}
+
+		info.OldState = *state
+		state.Flags &^= components.StateCodeNeeded
+		state.Flags |= components.StateCodePending
+		state.PendingSince = now
+
+		items = append(items, info.Job)
+		submitted = append(submitted, info)
+	}
+
+	if len(items) == 0 {
+		return
+	}
+
+	accepted, err := s.queue.EnqueueBatch(items)
+	if err != nil {
+		s.logger.Warnw("Code job batch not fully enqueued, queue may be full", "error", err)
+	}
+
+	for i, info := range submitted {
+		if !s.world.Alive(info.Entity) {
+			continue
+		}
+		state := s.stateMapper.Get(info.Entity)
+		if state == nil {
+			continue
+		}
+
+		// Revert entities whose job the queue did not admit so the dispatch
+		// retries on the next tick.
+		if i >= len(accepted) || !accepted[i] {
+			state.Flags &^= components.StateCodePending
+			state.Flags |= components.StateCodeNeeded
+			continue
+		}
+
+		s.stateLogger.LogTransition(info.Entity, info.OldState, *state)
+		s.logger.Infow("Code dispatched", "monitor_name", s.metaMapper.Get(info.Entity).Name, "color", info.Color)
+	}
+}
+
+// Finalize is a no-op for this system.
+func (s *BatchCodeSystem) Finalize(_ *ecs.World) {}
+
+func isNilJob(job jobs.Job) bool { return job == nil || job.IsNil() }
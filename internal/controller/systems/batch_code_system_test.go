@@ -0,0 +1,526 @@
+package systems
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cpra/internal/audit"
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// newCodeTestEntity creates a monitor entity with a log notification config
+// for red and green, registered with the given cooldown.
+func newCodeTestEntity(world *ecs.World, name, file string, cooldown time.Duration) ecs.Entity {
+	reg := components.DefaultConfigRegistry()
+	codeConfig := components.CodeConfig{}
+	for _, color := range []components.ColorCode{components.ColorRed, components.ColorGreen} {
+		codeConfig.Configs[color] = reg.GetOrAdd(components.ColorCodeConfig{
+			Notify:   "log",
+			Dispatch: true,
+			Cooldown: cooldown,
+			Config:   &schema.CodeNotificationLog{File: file},
+		})
+	}
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.CodeConfig, components.CodeStatus](world)
+	return mapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: name},
+		&codeConfig,
+		&components.CodeStatus{},
+	)
+}
+
+// flagCodeNeeded marks the entity as needing a code alert of the given color.
+func flagCodeNeeded(world *ecs.World, ent ecs.Entity, color components.ColorCode) {
+	state := ecs.NewMap[components.MonitorState](world).Get(ent)
+	state.Flags |= components.StateCodeNeeded
+	state.PendingColor = color
+}
+
+// TestBatchCodeSystem_CooldownSuppressesRepeats simulates a flapping monitor
+// and asserts enqueued versus suppressed counts across ticks.
+func TestBatchCodeSystem_CooldownSuppressesRepeats(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(1000)
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+
+	system := NewBatchCodeSystem(&world, mockQ, 100, logger, stateLogger)
+	system.Initialize(&world)
+
+	results := make(chan []jobs.Result, 8)
+	resultSystem := NewBatchCodeResultSystem(&world, results, logger, stateLogger)
+
+	ent := newCodeTestEntity(&world, "flappy", "/var/log/cooldown-test.log", 10*time.Minute)
+	statusMapper := ecs.NewMap[components.CodeStatus](&world)
+
+	// deliver acknowledges the in-flight job so the FSM and CodeStatus
+	// reflect a completed delivery.
+	deliver := func(color string, err error) {
+		results <- []jobs.Result{{Ent: ent, Err: err, Payload: jobs.Payload{Kind: jobs.KindCode, Color: color}}}
+		resultSystem.Update(&world)
+	}
+
+	// First red fires and is delivered.
+	flagCodeNeeded(&world, ent, components.ColorRed)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d, want 1", got)
+	}
+	deliver("red", nil)
+
+	// Flap: red needed again on the next three ticks, all inside the window.
+	for i := 0; i < 3; i++ {
+		flagCodeNeeded(&world, ent, components.ColorRed)
+		system.Update(&world)
+	}
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Errorf("enqueued = %d after flapping, want 1", got)
+	}
+	if got := system.SuppressedCount(); got != 3 {
+		t.Errorf("SuppressedCount() = %d, want 3", got)
+	}
+
+	// A different color always breaks through.
+	flagCodeNeeded(&world, ent, components.ColorGreen)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 2 {
+		t.Errorf("enqueued = %d after green, want 2", got)
+	}
+	deliver("green", nil)
+
+	// Once the window expires, red fires again.
+	statusMapper.Get(ent).Status[components.ColorRed].LastAlertTime = time.Now().Add(-11 * time.Minute).Unix()
+	flagCodeNeeded(&world, ent, components.ColorRed)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 3 {
+		t.Errorf("enqueued = %d after window expiry, want 3", got)
+	}
+	if got := system.SuppressedCount(); got != 3 {
+		t.Errorf("SuppressedCount() = %d, want 3 (no extra suppression)", got)
+	}
+}
+
+// TestBatchCodeSystem_FailedDeliveryNotSuppressed tests that the cooldown
+// never blocks the retry of a failed delivery.
+func TestBatchCodeSystem_FailedDeliveryNotSuppressed(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(1000)
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+
+	system := NewBatchCodeSystem(&world, mockQ, 100, logger, stateLogger)
+	system.Initialize(&world)
+
+	results := make(chan []jobs.Result, 8)
+	resultSystem := NewBatchCodeResultSystem(&world, results, logger, stateLogger)
+
+	ent := newCodeTestEntity(&world, "retry-me", "/var/log/cooldown-retry-test.log", 10*time.Minute)
+
+	flagCodeNeeded(&world, ent, components.ColorRed)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d, want 1", got)
+	}
+
+	// Delivery fails; the result system re-flags CodeNeeded.
+	results <- []jobs.Result{{Ent: ent, Err: jobs.ErrLogMarshalFailed, Payload: jobs.Payload{Kind: jobs.KindCode, Color: "red"}}}
+	resultSystem.Update(&world)
+
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 2 {
+		t.Errorf("enqueued = %d, want 2 (retry must not be suppressed)", got)
+	}
+	if got := system.SuppressedCount(); got != 0 {
+		t.Errorf("SuppressedCount() = %d, want 0", got)
+	}
+}
+
+// TestBatchCodeSystem_ZeroCooldownKeepsCurrentBehavior tests that the default
+// configuration never suppresses.
+func TestBatchCodeSystem_ZeroCooldownKeepsCurrentBehavior(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(1000)
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+
+	system := NewBatchCodeSystem(&world, mockQ, 100, logger, stateLogger)
+	system.Initialize(&world)
+
+	results := make(chan []jobs.Result, 8)
+	resultSystem := NewBatchCodeResultSystem(&world, results, logger, stateLogger)
+
+	ent := newCodeTestEntity(&world, "no-cooldown", "/var/log/no-cooldown-test.log", 0)
+
+	for i := 0; i < 3; i++ {
+		flagCodeNeeded(&world, ent, components.ColorRed)
+		system.Update(&world)
+		results <- []jobs.Result{{Ent: ent, Payload: jobs.Payload{Kind: jobs.KindCode, Color: "red"}}}
+		resultSystem.Update(&world)
+	}
+	if got := len(mockQ.getEnqueued()); got != 3 {
+		t.Errorf("enqueued = %d, want 3 (zero cooldown never suppresses)", got)
+	}
+	if got := system.SuppressedCount(); got != 0 {
+		t.Errorf("SuppressedCount() = %d, want 0", got)
+	}
+}
+
+// TestBatchCodeSystem_DependencySuppression kills the parent of a two-level
+// chain and verifies its 100 children are suppressed with one summary
+// counter instead of paging, then released once the parent recovers.
+func TestBatchCodeSystem_DependencySuppression(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(1000)
+	logger := zap.NewNop().Sugar()
+
+	system := NewBatchCodeSystem(&world, mockQ, 200, logger, newNoopStateLogger())
+	system.Initialize(&world)
+
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	depsMapper := ecs.NewMap[components.DependsOn](&world)
+
+	// The parent router: no code config needed, only state the children read.
+	parentMapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	parent := parentMapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "router"},
+		&components.PulseConfig{UnhealthyThreshold: 3, HealthyThreshold: 1},
+	)
+
+	const children = 100
+	ents := make([]ecs.Entity, 0, children)
+	for i := 0; i < children; i++ {
+		ent := newCodeTestEntity(&world, "web", "/var/log/dep-test.log", 0)
+		depsMapper.Add(ent, &components.DependsOn{Parents: []ecs.Entity{parent}})
+		ents = append(ents, ent)
+	}
+
+	// Parent dies; every child fails and wants to page red.
+	stateMapper.Get(parent).Flags |= components.StateIncidentOpen
+	for _, ent := range ents {
+		stateMapper.Get(ent).ConsecutiveFailures = 3
+		flagCodeNeeded(&world, ent, components.ColorRed)
+	}
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 0 {
+		t.Fatalf("enqueued = %d while parent is down, want 0", got)
+	}
+	if got := system.DependencySuppressedCount(); got != children {
+		t.Errorf("DependencySuppressedCount() = %d, want %d", got, children)
+	}
+	for _, ent := range ents {
+		state := stateMapper.Get(ent)
+		if state.Flags&components.StateDependencySuppressed == 0 {
+			t.Fatal("child not marked dependency-suppressed")
+		}
+		if state.Flags&components.StateCodeNeeded != 0 {
+			t.Fatal("suppressed child must not keep CodeNeeded set")
+		}
+	}
+
+	// While the parent is down nothing is re-dispatched on later ticks.
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 0 {
+		t.Fatalf("enqueued = %d on second tick, want 0", got)
+	}
+
+	// One child recovers while suppressed; its stale red must not fire.
+	stateMapper.Get(ents[0]).ConsecutiveFailures = 0
+
+	// Parent recovers: still-failing children must then alert.
+	stateMapper.Get(parent).Flags &^= components.StateIncidentOpen
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != children-1 {
+		t.Fatalf("enqueued = %d after parent recovery, want %d", got, children-1)
+	}
+	for _, ent := range ents {
+		if stateMapper.Get(ent).Flags&components.StateDependencySuppressed != 0 {
+			t.Fatal("suppression flag not cleared after parent recovery")
+		}
+	}
+}
+
+// TestBatchCodeSystem_DependencyFailedByThreshold tests that a parent counts
+// as failed once its failed checks reach its own unhealthy threshold, before
+// any incident opens, and that green alerts are never held.
+func TestBatchCodeSystem_DependencyFailedByThreshold(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(1000)
+	system := NewBatchCodeSystem(&world, mockQ, 100, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	depsMapper := ecs.NewMap[components.DependsOn](&world)
+
+	parentMapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	parent := parentMapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "router"},
+		&components.PulseConfig{UnhealthyThreshold: 3, HealthyThreshold: 1},
+	)
+	child := newCodeTestEntity(&world, "web", "/var/log/dep-threshold-test.log", 0)
+	depsMapper.Add(child, &components.DependsOn{Parents: []ecs.Entity{parent}})
+
+	// Two failed checks: below the parent's threshold, children still page.
+	stateMapper.Get(parent).ConsecutiveFailures = 2
+	flagCodeNeeded(&world, child, components.ColorRed)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d below parent threshold, want 1", got)
+	}
+
+	// Third failed check crosses it; the next red is held.
+	stateMapper.Get(parent).ConsecutiveFailures = 3
+	stateMapper.Get(child).Flags &^= components.StateCodePending
+	stateMapper.Get(child).ConsecutiveFailures = 1
+	flagCodeNeeded(&world, child, components.ColorRed)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d past parent threshold, want 1 (red held)", got)
+	}
+
+	// Green passes through even while the parent is down.
+	stateMapper.Get(child).Flags &^= components.StateDependencySuppressed
+	flagCodeNeeded(&world, child, components.ColorGreen)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 2 {
+		t.Fatalf("enqueued = %d after green, want 2 (green never held)", got)
+	}
+}
+
+// TestBatchCodeResultSystem_AuditTrail delivers a successful red and a failed
+// green notification result and asserts both land in the audit log with the
+// color as the action.
+func TestBatchCodeResultSystem_AuditTrail(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+
+	results := make(chan []jobs.Result, 8)
+	resultSystem := NewBatchCodeResultSystem(&world, results, logger, stateLogger)
+
+	sink, err := audit.NewSQLiteSink(":memory:", 0)
+	if err != nil {
+		t.Fatalf("NewSQLiteSink failed: %v", err)
+	}
+	defer sink.Close()
+	resultSystem.SetAuditSink(sink)
+
+	ent := newCodeTestEntity(&world, "audited", "/var/log/audit-trail-test.log", 0)
+	state := ecs.NewMap[components.MonitorState](&world).Get(ent)
+
+	// The result system only applies results to monitors with an alert in
+	// flight.
+	state.Flags |= components.StateCodePending
+	results <- []jobs.Result{{Ent: ent, Payload: jobs.Payload{Kind: jobs.KindCode, Color: "red"}}}
+	resultSystem.Update(&world)
+	state.Flags |= components.StateCodePending
+	results <- []jobs.Result{{Ent: ent, Err: jobs.ErrLogMarshalFailed, Payload: jobs.Payload{Kind: jobs.KindCode, Color: "green"}}}
+	resultSystem.Update(&world)
+
+	events := auditedEvents(t, sink, "audited", 2)
+	if len(events) != 2 {
+		t.Fatalf("got %d audit events, want 2", len(events))
+	}
+
+	// Newest first: the failed green, then the delivered red.
+	if events[0].Kind != audit.KindCode || events[0].Action != "green" || events[0].Success || events[0].Error == "" {
+		t.Errorf("events[0] = %+v, want failed green notification", events[0])
+	}
+	if events[1].Kind != audit.KindCode || events[1].Action != "red" || !events[1].Success || events[1].Error != "" {
+		t.Errorf("events[1] = %+v, want delivered red notification", events[1])
+	}
+}
+
+// TestBatchCodeSystem_NotifyGroupFanOut builds an entity whose red color
+// resolved to two targets (as two notify groups produce) and asserts one
+// fan-out job is enqueued that delivers to both.
+func TestBatchCodeSystem_NotifyGroupFanOut(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(100)
+	logger := zap.NewNop().Sugar()
+	system := NewBatchCodeSystem(&world, mockQ, 100, logger, newNoopStateLogger())
+	system.Initialize(&world)
+
+	dir := t.TempDir()
+	primary := filepath.Join(dir, "payments-red.log")
+	second := filepath.Join(dir, "oncall-red.log")
+
+	reg := components.DefaultConfigRegistry()
+	codeConfig := components.CodeConfig{}
+	codeConfig.Configs[components.ColorRed] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: true,
+		Config:   &schema.CodeNotificationLog{File: primary},
+	})
+	codeConfig.Extra = append(codeConfig.Extra, components.ColorConfigID{
+		Color: components.ColorRed,
+		ID: reg.GetOrAdd(components.ColorCodeConfig{
+			Notify:   "log",
+			Dispatch: true,
+			Config:   &schema.CodeNotificationLog{File: second},
+		}),
+	})
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.CodeConfig, components.CodeStatus](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "checkout"},
+		&codeConfig,
+		&components.CodeStatus{},
+	)
+
+	flagCodeNeeded(&world, ent, components.ColorRed)
+	system.Update(&world)
+
+	enqueued := mockQ.getEnqueued()
+	if len(enqueued) != 1 {
+		t.Fatalf("enqueued = %d, want 1", len(enqueued))
+	}
+	fanout, ok := enqueued[0].(*jobs.CodeFanoutJob)
+	if !ok {
+		t.Fatalf("enqueued job is %T, want *jobs.CodeFanoutJob", enqueued[0])
+	}
+	if len(fanout.Jobs) != 2 {
+		t.Fatalf("fan-out carries %d jobs, want 2", len(fanout.Jobs))
+	}
+
+	// Both targets are present, in route order.
+	for i, want := range []string{primary, second} {
+		logJob, ok := fanout.Jobs[i].(*jobs.CodeLogJob)
+		if !ok {
+			t.Fatalf("fan-out job %d is %T, want *jobs.CodeLogJob", i, fanout.Jobs[i])
+		}
+		if logJob.File != want {
+			t.Errorf("fan-out job %d targets %q, want %q", i, logJob.File, want)
+		}
+	}
+
+	// Executing the wrapper delivers one aggregated result for the entity.
+	res := fanout.Execute(context.Background())
+	if res.Err != nil {
+		t.Fatalf("fan-out execute failed: %v", res.Err)
+	}
+	if got := res.Payload.Extra("targets"); got != 2 {
+		t.Errorf("payload targets = %v, want 2", got)
+	}
+	if res.Payload.Color != "red" {
+		t.Errorf("payload color = %q, want red", res.Payload.Color)
+	}
+}
+
+// TestBatchCodeSystem_DispatchDisabledLogsOnly verifies that dispatch: false
+// completes the color transition and records it, but never enqueues the
+// notification job — and that flipping the flag back on takes effect on the
+// next transition without a cooldown holding the first real alert back.
+func TestBatchCodeSystem_DispatchDisabledLogsOnly(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(1000)
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+
+	system := NewBatchCodeSystem(&world, mockQ, 100, logger, stateLogger)
+	system.Initialize(&world)
+
+	reg := components.DefaultConfigRegistry()
+	codeConfig := components.CodeConfig{}
+	codeConfig.Configs[components.ColorRed] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: false,
+		Cooldown: 10 * time.Minute,
+		Config:   &schema.CodeNotificationLog{File: "/var/log/dispatch-test.log"},
+	})
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.CodeConfig, components.CodeStatus](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "muted"},
+		&codeConfig,
+		&components.CodeStatus{},
+	)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	statusMapper := ecs.NewMap[components.CodeStatus](&world)
+
+	// Red with dispatch disabled: transition completes, nothing enqueued.
+	flagCodeNeeded(&world, ent, components.ColorRed)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 0 {
+		t.Fatalf("enqueued = %d with dispatch disabled, want 0", got)
+	}
+	if got := system.LogOnlyCount(); got != 1 {
+		t.Errorf("LogOnlyCount() = %d, want 1", got)
+	}
+	state := stateMapper.Get(ent)
+	if state.Flags&(components.StateCodeNeeded|components.StateCodePending) != 0 {
+		t.Errorf("flags = %v, want CodeNeeded and CodePending cleared", state.Flags)
+	}
+	if state.PendingColor != components.ColorNone {
+		t.Errorf("PendingColor = %v, want ColorNone", state.PendingColor)
+	}
+	status := statusMapper.Get(ent).Status[components.ColorRed]
+	if status.Flags&components.StatusLogOnly == 0 {
+		t.Error("red status not marked log-only")
+	}
+	if status.LastAlertTime != 0 {
+		t.Errorf("LastAlertTime = %d, want 0 (log-only records never start cooldowns)", status.LastAlertTime)
+	}
+
+	// Reload flips dispatch on: the next red transition must fire despite
+	// the cooldown, because the log-only record never delivered anything.
+	codeConfig2 := ecs.NewMap[components.CodeConfig](&world).Get(ent)
+	codeConfig2.Configs[components.ColorRed] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: true,
+		Cooldown: 10 * time.Minute,
+		Config:   &schema.CodeNotificationLog{File: "/var/log/dispatch-test.log"},
+	})
+	flagCodeNeeded(&world, ent, components.ColorRed)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d after enabling dispatch, want 1", got)
+	}
+	if got := system.LogOnlyCount(); got != 1 {
+		t.Errorf("LogOnlyCount() = %d after enabling dispatch, want 1", got)
+	}
+	if state.Flags&components.StateCodePending == 0 {
+		t.Error("CodePending not set after dispatch-enabled enqueue")
+	}
+}
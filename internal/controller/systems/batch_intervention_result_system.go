@@ -0,0 +1,290 @@
+package systems
+
+import (
+	"cpra/internal/audit"
+	"cpra/internal/controller/components"
+	"cpra/internal/events"
+	"cpra/internal/jobs"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// BatchInterventionResultSystem processes completed intervention jobs.
+// It processes batches of results passed directly from the result router.
+type BatchInterventionResultSystem struct {
+	world       *ecs.World
+	logger      Logger
+	stateLogger *StateLogger
+
+	// Mappers for efficient component access
+	stateMapper              *ecs.Map[components.MonitorState]
+	metaMapper               *ecs.Map[components.MonitorMeta]
+	codeConfigMapper         *ecs.Map1[components.CodeConfig]
+	pulseConfigMapper        *ecs.Map1[components.PulseConfig]
+	interventionConfigMapper *ecs.Map1[components.InterventionConfig]
+	registry                 *components.ConfigRegistry
+	ResultChan               <-chan []jobs.Result
+	drain                    resultDrain
+	audit                    audit.Sink  // nil unless auditing is configured
+	bus                      *events.Bus // nil unless an event bus is attached
+}
+
+// NewBatchInterventionResultSystem creates a new BatchInterventionResultSystem.
+func NewBatchInterventionResultSystem(world *ecs.World, results <-chan []jobs.Result, logger Logger, stateLogger *StateLogger) *BatchInterventionResultSystem {
+	return &BatchInterventionResultSystem{
+		world:                    world,
+		logger:                   logger,
+		stateLogger:              stateLogger,
+		stateMapper:              ecs.NewMap[components.MonitorState](world),
+		metaMapper:               ecs.NewMap[components.MonitorMeta](world),
+		codeConfigMapper:         ecs.NewMap1[components.CodeConfig](world),
+		pulseConfigMapper:        ecs.NewMap1[components.PulseConfig](world),
+		interventionConfigMapper: ecs.NewMap1[components.InterventionConfig](world),
+		registry:                 components.DefaultConfigRegistry(),
+		ResultChan:               results,
+		drain:                    newResultDrain(),
+	}
+}
+
+func (s *BatchInterventionResultSystem) Initialize(_ *ecs.World) {
+}
+
+// SetResultBudget overrides the per-tick result and time budgets; see
+// resultDrain. Non-positive values keep the defaults.
+func (s *BatchInterventionResultSystem) SetResultBudget(maxResults int, maxTime time.Duration) {
+	s.drain.setBudget(maxResults, maxTime)
+}
+
+// SetAuditSink routes every applied intervention outcome to the sink.
+func (s *BatchInterventionResultSystem) SetAuditSink(sink audit.Sink) {
+	s.audit = sink
+}
+
+// SetEventBus publishes every applied intervention outcome to the bus.
+func (s *BatchInterventionResultSystem) SetEventBus(bus *events.Bus) {
+	s.bus = bus
+}
+
+// ConsumedLastTick reports how many results the last Update applied.
+func (s *BatchInterventionResultSystem) ConsumedLastTick() int64 { return s.drain.ConsumedLastTick() }
+
+// ResultBacklog reports how many received results await application.
+func (s *BatchInterventionResultSystem) ResultBacklog() int64 { return s.drain.Backlog() }
+
+func (s *BatchInterventionResultSystem) Update(_ *ecs.World) {
+	if s.ResultChan == nil {
+		// Channel closed; finish applying any carried-over batches.
+		s.drain.apply(s.ProcessBatch)
+		return
+	}
+
+loop:
+	for {
+		select {
+		case res, ok := <-s.ResultChan:
+			if !ok {
+				s.ResultChan = nil
+				break loop
+			}
+			s.drain.add(res)
+		default:
+			break loop
+		}
+	}
+
+	// Apply under the per-tick budget; the remainder carries over so a
+	// burst cannot consume the whole tick.
+	s.drain.apply(s.ProcessBatch)
+}
+
+// ProcessBatch processes a batch of intervention results.
+func (s *BatchInterventionResultSystem) ProcessBatch(results []jobs.Result) {
+	startTime := time.Now()
+	processedCount := 0
+
+	for i := range results {
+		result := &results[i]
+		ent := result.Entity()
+		if !s.world.Alive(ent) {
+			continue
+		}
+
+		state := s.stateMapper.Get(ent)
+		if state == nil {
+			continue
+		}
+
+		flags := state.Flags
+		// Ensure we are processing a pending intervention
+		if (flags & components.StateInterventionPending) == 0 {
+			s.logger.Warnw("Entity received InterventionResult but was not in InterventionPending state", "entity_id", ent.ID())
+			continue
+		}
+
+		processedCount++
+		oldState := *state
+		meta := s.metaMapper.Get(ent)
+		eventTime := time.Now()
+		meta.LastEventTime = eventTime
+
+		// Surface the throttle counters in the result payload; they live on
+		// MonitorMeta, so they survive the pending/result round trip.
+		result.Payload.SetExtra("interventions_this_hour", meta.InterventionWindowCount)
+		if meta.InterventionsSuppressed > 0 {
+			result.Payload.SetExtra("interventions_suppressed", meta.InterventionsSuppressed)
+		}
+
+		// Escalation chains stamp the step index into the result payload.
+		if stepIdx := result.Payload.Extra("step"); stepIdx != nil {
+			s.logger.Debugw("Intervention escalation step completed",
+				"monitor_name", meta.Name, "escalation_step", stepIdx, "error", result.Error())
+		}
+
+		dryRun, _ := result.Payload.Extra("dry_run").(bool)
+		if dryRun {
+			// --- DRY RUN ---
+			// Nothing actually ran, so no remediation bookkeeping: failure
+			// counters, LastError, and the verification window stay as they
+			// are. The cyan "would have intervened" notification still fires
+			// so operators see what a real run would have done.
+			s.logger.Infow("Dry-run intervention: action evaluated but not performed",
+				"monitor_name", meta.Name,
+				"would_be_action", result.Payload.Extra("would_be_action"),
+				"would_be_target", result.Payload.Extra("would_be_target"),
+				"consecutive_failures", state.ConsecutiveFailures)
+			s.triggerCode(ent, state, components.ColorCyan)
+		} else if result.Error() != nil {
+			// --- FAILURE ---
+			state.InterventionFailures++
+			meta.LastError = result.Error()
+			s.logger.Errorw("Monitor intervention failed", "monitor_name", meta.Name, "error", meta.LastError)
+
+			// Only trigger red alert if incident is NOT already open
+			if (flags & components.StateIncidentOpen) == 0 {
+				s.triggerCode(ent, state, components.ColorRed)
+				state.Flags |= components.StateIncidentOpen
+				s.logger.Infow("RED ALERT: incident opened", "monitor_name", meta.Name)
+			} else {
+				s.logger.Debugw("Intervention failed but incident already open, no duplicate red alert", "monitor_name", meta.Name)
+			}
+		} else {
+			// --- SUCCESS ---
+			s.logger.Infow("Monitor intervention succeeded", "monitor_name", meta.Name)
+			state.ConsecutiveFailures = 0
+			meta.LastError = nil
+			meta.LastSuccessTime = eventTime
+			// Begin verification window (Phase 2)
+			// Use pulse HealthyThreshold as verification count if available, else default
+			pulseCfg := s.pulseConfigMapper.Get(ent)
+			m := 3
+			if pulseCfg != nil && pulseCfg.HealthyThreshold > 0 {
+				m = pulseCfg.HealthyThreshold
+			}
+			state.VerifyRemaining = m
+			state.Flags |= components.StateVerifying
+			s.triggerCode(ent, state, components.ColorCyan)
+		}
+
+		if s.audit != nil {
+			action := ""
+			if s.interventionConfigMapper.HasAll(ent) {
+				action = s.interventionConfigMapper.Get(ent).Action
+			}
+			if dryRun {
+				// Mark skipped actions so the compliance record never reads
+				// as if remediation happened.
+				action = "dry-run:" + action
+			}
+			errText := ""
+			if result.Error() != nil {
+				errText = result.Error().Error()
+			}
+			s.audit.Record(audit.Event{
+				Time:     eventTime,
+				Monitor:  meta.Name,
+				EntityID: uint32(ent.ID()),
+				Kind:     audit.KindIntervention,
+				Action:   action,
+				Success:  result.Error() == nil,
+				Error:    errText,
+				Attempts: result.Payload.Attempts,
+				Latency:  result.Duration,
+			})
+		}
+
+		// Dry runs remediate nothing, so they make no semantic event.
+		if s.bus != nil && !dryRun {
+			ev := events.Event{
+				Time:     eventTime,
+				Monitor:  meta.Name,
+				EntityID: uint32(ent.ID()),
+				From:     "intervening",
+				Labels:   meta.Labels,
+			}
+			if s.interventionConfigMapper.HasAll(ent) {
+				ev.Details = s.interventionConfigMapper.Get(ent).Action
+			}
+			if err := result.Error(); err != nil {
+				ev.Kind = events.KindInterventionFailed
+				ev.To = "incident"
+				ev.Details = err.Error()
+			} else {
+				ev.Kind = events.KindInterventionSucceeded
+				ev.To = "verifying"
+			}
+			s.bus.Publish(ev)
+		}
+
+		// Unset the pending flag, regardless of outcome.
+		state.Flags &^= components.StateInterventionPending
+		s.stateLogger.LogTransition(ent, oldState, *state)
+	}
+
+	if processedCount > 0 {
+		dur := time.Since(startTime)
+		LogSystemPerformance(s.logger, "BatchInterventionResultSystem", processedCount, dur)
+	}
+}
+
+func (s *BatchInterventionResultSystem) triggerCode(entity ecs.Entity, state *components.MonitorState, color components.ColorCode) {
+	codeConfig := s.codeConfigMapper.Get(entity)
+	if codeConfig == nil {
+		return
+	}
+	monitorName := s.metaMapper.Get(entity).Name
+	if color >= components.MaxColors {
+		return
+	}
+	cfg, ok := s.registry.Lookup(codeConfig.Configs[color])
+	if !ok || cfg.Notify == "" {
+		s.logger.Warnw("Monitor has no code config; skipping alert flag", "monitor_name", monitorName, "color", color)
+		return
+	}
+	if !cfg.Dispatch {
+		s.logger.Infow("Code dispatch disabled; not flagging", "monitor_name", monitorName, "color", color)
+		return
+	}
+
+	// FSM guard: If a code job is already in-flight (Pending), don't overwrite.
+	if (state.Flags & components.StateCodePending) != 0 {
+		s.logger.Debugf("Monitor '%s' already has code in-flight; deferring %s trigger", monitorName, color)
+		return
+	}
+
+	// If CodeNeeded is already set, use priority to decide.
+	if (state.Flags&components.StateCodeNeeded) != 0 && state.PendingColor != components.ColorNone {
+		if !color.HigherPriorityThan(state.PendingColor) {
+			s.logger.Debugf("Monitor '%s' already has %s pending; %s has lower priority, skipping", monitorName, state.PendingColor, color)
+			return
+		}
+		s.logger.Debugf("Monitor '%s' upgrading pending code from %s to %s", monitorName, state.PendingColor, color)
+	}
+
+	state.PendingColor = color
+	state.Flags |= components.StateCodeNeeded
+	s.logger.Infow("Flagging for alert code", "monitor_name", monitorName, "color", color)
+}
+
+// Finalize is a no-op for this system.
+func (s *BatchInterventionResultSystem) Finalize(_ *ecs.World) {}
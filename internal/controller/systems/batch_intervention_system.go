@@ -0,0 +1,307 @@
+package systems
+
+import (
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/queue"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// BatchInterventionSystem processes entities that need an intervention.
+// It identifies entities with the StateInterventionNeeded flag, enqueues the corresponding job,
+// and transitions the entity state to StateInterventionPending.
+type BatchInterventionSystem struct {
+	queue                    queue.Queue
+	logger                   Logger
+	stateLogger              *StateLogger
+	world                    *ecs.World
+	filter                   *ecs.Filter3[components.MonitorState, components.InterventionConfig, components.JobStorage]
+	monitorStateMapper       *ecs.Map[components.MonitorState]
+	metaMapper               *ecs.Map[components.MonitorMeta]
+	interventionConfigMapper *ecs.Map[components.InterventionConfig]
+	jobPool                  *sync.Pool
+	entityPool               *sync.Pool
+	batchSize                int
+	// suppressed counts dispatches skipped by the cooldown or hourly cap.
+	suppressed atomic.Uint64
+	now        func() time.Time // overridable for fake-clock tests
+	gate       *StandbyGate     // nil unless HA mode is on; closed while standby
+}
+
+// NewBatchInterventionSystem creates a new BatchInterventionSystem.
+func NewBatchInterventionSystem(world *ecs.World, q queue.Queue, batchSize int, logger Logger, stateLogger *StateLogger) *BatchInterventionSystem {
+	return &BatchInterventionSystem{
+		world:       world,
+		queue:       q,
+		logger:      logger,
+		stateLogger: stateLogger,
+		batchSize:   batchSize,
+		filter: ecs.NewFilter3[components.MonitorState, components.InterventionConfig, components.JobStorage](world).
+			Without(ecs.C[components.Disabled]()),
+		monitorStateMapper:       ecs.NewMap[components.MonitorState](world),
+		metaMapper:               ecs.NewMap[components.MonitorMeta](world),
+		interventionConfigMapper: ecs.NewMap[components.InterventionConfig](world),
+		now:                      time.Now,
+		jobPool: &sync.Pool{
+			New: func() interface{} {
+				s := make([]interface{}, 0, batchSize)
+				return &s
+			},
+		},
+		entityPool: &sync.Pool{
+			New: func() interface{} {
+				s := make([]ecs.Entity, 0, batchSize)
+				return &s
+			},
+		},
+	}
+}
+
+func (s *BatchInterventionSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+// SuppressedCount returns how many intervention dispatches were skipped by
+// the cooldown or the hourly cap since startup.
+func (s *BatchInterventionSystem) SuppressedCount() uint64 { return s.suppressed.Load() }
+
+// SetClock routes the cooldown and hourly-cap decisions through c. A nil
+// clock is ignored.
+func (s *BatchInterventionSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+// SetStandbyGate makes dispatch honor the HA leadership gate. A nil gate
+// (the default) means always dispatch.
+func (s *BatchInterventionSystem) SetStandbyGate(g *StandbyGate) {
+	s.gate = g
+}
+
+// Update finds and processes all monitors that need an intervention.
+func (s *BatchInterventionSystem) Update(_ *ecs.World) {
+	if s.gate != nil && s.gate.Standby() {
+		return
+	}
+	startTime := time.Now()
+	stats := s.queue.Stats()
+	if stats.Capacity > 0 && stats.QueueDepth >= int(float64(stats.Capacity)*0.9) {
+		s.logger.Debugw("Intervention queue saturated", "depth", stats.QueueDepth, "capacity", stats.Capacity)
+	}
+
+	query := s.filter.Query()
+
+	var tokens int
+	if stats.Capacity <= 0 {
+		tokens = s.batchSize
+		if tokens <= 0 {
+			tokens = 1
+		}
+	} else {
+		free := stats.Capacity - stats.QueueDepth
+		if free <= 0 {
+			return
+		}
+		tokens = int(float64(free) * 0.8)
+		if tokens <= 0 {
+			tokens = free
+		}
+	}
+
+	earlyExit := false
+
+	jobsPtr := s.jobPool.Get().(*[]interface{})
+	entitiesPtr := s.entityPool.Get().(*[]ecs.Entity)
+	jobsToQueue := (*jobsPtr)[:0]
+	entitiesToUpdate := (*entitiesPtr)[:0]
+	processedCount := 0
+
+	defer func() {
+		s.jobPool.Put(jobsPtr)
+		s.entityPool.Put(entitiesPtr)
+	}()
+
+	for query.Next() {
+		ent := query.Entity()
+		state, config, jobStorage := query.Get()
+
+		// Process only entities that need an intervention.
+		if (state.Flags & components.StateInterventionNeeded) == 0 {
+			continue
+		}
+
+		// Safety valve: a persistently failing monitor must not be
+		// remediated in a loop. Dispatches inside the cooldown window or
+		// over the hourly cap are suppressed and escalate straight to red.
+		if s.suppressIntervention(ent, state, config, s.now()) {
+			continue
+		}
+
+		// Escalation chains run the step picked by nextEscalationStep;
+		// single-action interventions keep using the stored job.
+		job := jobStorage.InterventionJob
+		if len(config.Steps) > 1 && len(jobStorage.InterventionJobs) == len(config.Steps) {
+			job = jobStorage.InterventionJobs[nextEscalationStep(state, config)]
+		}
+
+		// Guard against typed-nil jobs (interfaces holding nil pointers)
+		if job == nil || job.IsNil() {
+			s.logger.Warnw("Entity has InterventionNeeded state but no valid InterventionJob", "entity_id", ent.ID())
+			continue
+		}
+
+		// Webhook interventions render the monitor name and failure count
+		// into the request body, so stamp them at dispatch time.
+		inner := job
+		if sj, ok := inner.(*jobs.InterventionStepJob); ok {
+			inner = sj.Job
+		}
+		if wj, ok := inner.(*jobs.InterventionWebhookJob); ok {
+			wj.Monitor = s.metaMapper.Get(ent).Name
+			wj.FailureCount = state.ConsecutiveFailures
+		}
+
+		jobsToQueue = append(jobsToQueue, job)
+		entitiesToUpdate = append(entitiesToUpdate, ent)
+
+		if len(jobsToQueue) >= tokens {
+			s.processBatch(&jobsToQueue, &entitiesToUpdate)
+			processedCount += len(jobsToQueue)
+			jobsToQueue = jobsToQueue[:0]
+			entitiesToUpdate = entitiesToUpdate[:0]
+			earlyExit = true
+			break
+		}
+	}
+
+	// Process any remaining entities
+	if earlyExit {
+		query.Close()
+	}
+
+	if len(jobsToQueue) > 0 {
+		s.processBatch(&jobsToQueue, &entitiesToUpdate)
+		processedCount += len(jobsToQueue)
+	}
+
+	if processedCount > 0 {
+		dur := time.Since(startTime)
+		LogSystemPerformance(s.logger, "BatchInterventionSystem", processedCount, dur)
+	}
+
+}
+
+// processBatch attempts to enqueue a batch of jobs and updates entity states on success.
+func (s *BatchInterventionSystem) processBatch(jobs *[]interface{}, entities *[]ecs.Entity) {
+	stats := s.queue.Stats()
+	if stats.Capacity > 0 && stats.QueueDepth >= int(float64(stats.Capacity)*0.9) {
+		s.logger.Debugw("Intervention queue near capacity", "depth", stats.QueueDepth, "capacity", stats.Capacity)
+		return
+	}
+	accepted, err := s.queue.EnqueueBatch(*jobs)
+	if err != nil {
+		s.logger.Warnw("Intervention job batch not fully enqueued, queue may be full", "error", err)
+		// Rejected entities keep their state and retry on the next tick.
+	}
+
+	// Transition only the entities whose job was actually admitted; with drop
+	// policies like DropOldest the accepted set is not necessarily a prefix.
+	now := s.now()
+	for i, ent := range *entities {
+		if i >= len(accepted) || !accepted[i] {
+			continue
+		}
+		if !s.world.Alive(ent) {
+			continue
+		}
+		state := s.monitorStateMapper.Get(ent)
+		if state == nil {
+			continue
+		}
+
+		// Transition from Needed -> Pending
+		if state.Flags&components.StateInterventionNeeded != 0 {
+			// Count the dispatch against the throttle windows.
+			meta := s.metaMapper.Get(ent)
+			meta.LastInterventionTime = now
+			if meta.InterventionWindowStart.IsZero() || now.Sub(meta.InterventionWindowStart) >= time.Hour {
+				meta.InterventionWindowStart = now
+				meta.InterventionWindowCount = 0
+			}
+			meta.InterventionWindowCount++
+			oldState := *state
+			// Record the escalation level that was dispatched so the next
+			// trigger resumes from it. The decision is deterministic, so
+			// re-running it here matches the job picked during collection.
+			if cfg := s.interventionConfigMapper.Get(ent); cfg != nil && len(cfg.Steps) > 1 {
+				state.EscalationStep = nextEscalationStep(state, cfg) + 1
+			}
+			state.Flags &^= components.StateInterventionNeeded
+			state.Flags |= components.StateInterventionPending
+			state.PendingSince = now
+			s.stateLogger.LogTransition(ent, oldState, *state)
+			s.logger.Infow("Intervention dispatched", "monitor_name", meta.Name)
+		}
+	}
+}
+
+// suppressIntervention applies the cooldown and max-per-hour safety valve.
+// A throttled dispatch clears the Needed flag, counts as suppressed, and
+// escalates straight to the red code (opening the incident) instead of
+// running yet another remediation that evidently is not helping.
+func (s *BatchInterventionSystem) suppressIntervention(ent ecs.Entity, state *components.MonitorState, cfg *components.InterventionConfig, now time.Time) bool {
+	meta := s.metaMapper.Get(ent)
+	inCooldown := cfg.Cooldown > 0 && !meta.LastInterventionTime.IsZero() &&
+		now.Sub(meta.LastInterventionTime) < cfg.Cooldown
+	overCap := cfg.MaxPerHour > 0 && !meta.InterventionWindowStart.IsZero() &&
+		now.Sub(meta.InterventionWindowStart) < time.Hour &&
+		meta.InterventionWindowCount >= cfg.MaxPerHour
+	if !inCooldown && !overCap {
+		return false
+	}
+
+	oldState := *state
+	state.Flags &^= components.StateInterventionNeeded
+	meta.InterventionsSuppressed++
+	s.suppressed.Add(1)
+	if (state.Flags & components.StateIncidentOpen) == 0 {
+		state.PendingColor = components.ColorRed
+		state.Flags |= components.StateCodeNeeded | components.StateIncidentOpen
+	}
+	s.stateLogger.LogTransition(ent, oldState, *state)
+	s.logger.Warnw("Intervention suppressed by safety valve",
+		"monitor_name", meta.Name,
+		"in_cooldown", inCooldown,
+		"over_hourly_cap", overCap,
+		"suppressed_total", meta.InterventionsSuppressed)
+	return true
+}
+
+// nextEscalationStep picks the chain step to run: re-run the last level
+// until the failure count crosses the next step's after_failures threshold,
+// then advance exactly one level. A monitor that has not escalated yet
+// always starts at step 0, so the chain never skips an untried level.
+func nextEscalationStep(state *components.MonitorState, cfg *components.InterventionConfig) int {
+	if state.EscalationStep <= 0 {
+		return 0
+	}
+	step := state.EscalationStep - 1
+	if step+1 < len(cfg.Steps) && state.ConsecutiveFailures >= cfg.Steps[step+1].AfterFailures {
+		step++
+	}
+	if step >= len(cfg.Steps) {
+		step = len(cfg.Steps) - 1
+	}
+	return step
+}
+
+// Finalize is a no-op for this system.
+func (s *BatchInterventionSystem) Finalize(_ *ecs.World) {}
@@ -0,0 +1,501 @@
+package systems
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cpra/internal/audit"
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// newChainTestEntity creates a monitor with a three-step escalation chain
+// (exec jobs so no external daemon is needed) with after_failures 0, 2, 4.
+func newChainTestEntity(t *testing.T, world *ecs.World) ecs.Entity {
+	t.Helper()
+
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.InterventionConfig, components.JobStorage](world)
+	entity := world.NewEntity()
+
+	steps := []schema.InterventionStep{
+		{Action: "exec", Target: &schema.InterventionTargetExec{Command: "echo", Args: []string{"restart container"}}},
+		{Action: "exec", Target: &schema.InterventionTargetExec{Command: "echo", Args: []string{"restart service"}}, AfterFailures: 2},
+		{Action: "exec", Target: &schema.InterventionTargetExec{Command: "echo", Args: []string{"page oncall"}}, AfterFailures: 4},
+	}
+
+	stepJobs := make([]jobs.Job, 0, len(steps))
+	cfgSteps := make([]components.InterventionStep, 0, len(steps))
+	for idx, step := range steps {
+		job, err := jobs.CreateInterventionStepJob(step, idx, entity)
+		if err != nil {
+			t.Fatalf("CreateInterventionStepJob failed: %v", err)
+		}
+		stepJobs = append(stepJobs, job)
+		cfgSteps = append(cfgSteps, components.InterventionStep{
+			Target:        step.Target,
+			Action:        step.Action,
+			AfterFailures: step.AfterFailures,
+		})
+	}
+
+	state := &components.MonitorState{}
+	meta := &components.MonitorMeta{Name: "payments-api"}
+	config := &components.InterventionConfig{Action: "exec", Target: steps[0].Target, MaxFailures: 1, Steps: cfgSteps}
+	storage := &components.JobStorage{InterventionJob: stepJobs[0], InterventionJobs: stepJobs}
+	mapper.Add(entity, state, meta, config, storage)
+
+	// The result system reads these via unchecked mappers.
+	ecs.NewMap[components.PulseConfig](world).Add(entity, &components.PulseConfig{HealthyThreshold: 1})
+	ecs.NewMap[components.CodeConfig](world).Add(entity, &components.CodeConfig{})
+	return entity
+}
+
+// dispatchedStep returns the escalation step of the n-th enqueued job.
+func dispatchedStep(t *testing.T, q *mockQueue, n int) int {
+	t.Helper()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.enqueued) <= n {
+		t.Fatalf("only %d jobs enqueued, want at least %d", len(q.enqueued), n+1)
+	}
+	stepJob, ok := q.enqueued[n].(*jobs.InterventionStepJob)
+	if !ok {
+		t.Fatalf("enqueued job %d is %T, want *jobs.InterventionStepJob", n, q.enqueued[n])
+	}
+	return stepJob.Step
+}
+
+// TestBatchInterventionSystem_EscalationChain drives a failing monitor
+// through all chain steps in order.
+func TestBatchInterventionSystem_EscalationChain(t *testing.T) {
+	world := ecs.NewWorld()
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+	q := newMockQueue(64)
+
+	system := NewBatchInterventionSystem(&world, q, 16, logger, stateLogger)
+	system.Initialize(&world)
+
+	resultChan := make(chan []jobs.Result, 4)
+	resultSystem := NewBatchInterventionResultSystem(&world, resultChan, logger, stateLogger)
+	resultSystem.Initialize(&world)
+
+	entity := newChainTestEntity(t, &world)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	state := stateMapper.Get(entity)
+
+	// First trigger: the chain starts at step 0 regardless of how many
+	// failures piled up before the intervention was tripped.
+	state.ConsecutiveFailures = 10
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := dispatchedStep(t, q, 0); got != 0 {
+		t.Fatalf("first dispatch ran step %d, want 0 (no skipping ahead)", got)
+	}
+	if state.Flags&components.StateInterventionPending == 0 {
+		t.Fatal("state not transitioned to InterventionPending")
+	}
+
+	// The intervention ran but the monitor keeps failing. Two more
+	// consecutive failures cross step 1's threshold.
+	resultChan <- []jobs.Result{{Ent: entity, Payload: jobs.Payload{Kind: jobs.KindIntervention, Extras: map[string]interface{}{"step": 0}}}}
+	resultSystem.Update(&world)
+	state.ConsecutiveFailures = 2
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := dispatchedStep(t, q, 1); got != 1 {
+		t.Fatalf("second dispatch ran step %d, want 1", got)
+	}
+
+	// Still failing: escalate to the final step.
+	resultChan <- []jobs.Result{{Ent: entity, Payload: jobs.Payload{Kind: jobs.KindIntervention, Extras: map[string]interface{}{"step": 1}}}}
+	resultSystem.Update(&world)
+	state.ConsecutiveFailures = 5
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := dispatchedStep(t, q, 2); got != 2 {
+		t.Fatalf("third dispatch ran step %d, want 2", got)
+	}
+
+	// The chain has no further levels; the last step repeats.
+	resultChan <- []jobs.Result{{Ent: entity, Payload: jobs.Payload{Kind: jobs.KindIntervention, Extras: map[string]interface{}{"step": 2}}}}
+	resultSystem.Update(&world)
+	state.ConsecutiveFailures = 50
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := dispatchedStep(t, q, 3); got != 2 {
+		t.Fatalf("fourth dispatch ran step %d, want 2 (last step repeats)", got)
+	}
+
+	// Recovery rewinds the chain to step 0.
+	state.EscalationStep = 0
+	state.ConsecutiveFailures = 0
+	state.Flags &^= components.StateInterventionPending
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := dispatchedStep(t, q, 4); got != 0 {
+		t.Fatalf("dispatch after recovery ran step %d, want 0", got)
+	}
+}
+
+// TestBatchInterventionSystem_StepBelowThreshold re-runs the current level
+// while the next step's after_failures threshold is not yet met.
+func TestBatchInterventionSystem_StepBelowThreshold(t *testing.T) {
+	world := ecs.NewWorld()
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+	q := newMockQueue(64)
+
+	system := NewBatchInterventionSystem(&world, q, 16, logger, stateLogger)
+	system.Initialize(&world)
+
+	entity := newChainTestEntity(t, &world)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	state := stateMapper.Get(entity)
+
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := dispatchedStep(t, q, 0); got != 0 {
+		t.Fatalf("first dispatch ran step %d, want 0", got)
+	}
+
+	// Only one more failure since step 0 ran: below step 1's threshold of
+	// 2, so step 0 runs again.
+	state.ConsecutiveFailures = 1
+	state.Flags &^= components.StateInterventionPending
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := dispatchedStep(t, q, 1); got != 0 {
+		t.Fatalf("second dispatch ran step %d, want 0 (threshold not met)", got)
+	}
+}
+
+// enqueuedCount returns how many jobs the mock queue has accepted so far.
+func enqueuedCount(q *mockQueue) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.enqueued)
+}
+
+// TestBatchInterventionSystem_HourlyCap simulates a persistently failing
+// monitor and asserts exactly max_per_hour interventions fire within the
+// hour, with the overflow suppressed and escalated straight to red.
+func TestBatchInterventionSystem_HourlyCap(t *testing.T) {
+	world := ecs.NewWorld()
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+	q := newMockQueue(64)
+
+	system := NewBatchInterventionSystem(&world, q, 16, logger, stateLogger)
+	system.Initialize(&world)
+
+	entity := newChainTestEntity(t, &world)
+	state := ecs.NewMap[components.MonitorState](&world).Get(entity)
+	config := ecs.NewMap[components.InterventionConfig](&world).Get(entity)
+	config.MaxPerHour = 3
+
+	// The monitor keeps failing: every tick re-trips the intervention.
+	for i := 0; i < 5; i++ {
+		state.Flags &^= components.StateInterventionPending
+		state.Flags |= components.StateInterventionNeeded
+		system.Update(&world)
+	}
+
+	if got := enqueuedCount(q); got != 3 {
+		t.Fatalf("%d interventions fired, want exactly max_per_hour (3)", got)
+	}
+	meta := ecs.NewMap[components.MonitorMeta](&world).Get(entity)
+	if meta.InterventionsSuppressed != 2 {
+		t.Errorf("InterventionsSuppressed = %d, want 2", meta.InterventionsSuppressed)
+	}
+	if system.SuppressedCount() != 2 {
+		t.Errorf("SuppressedCount = %d, want 2", system.SuppressedCount())
+	}
+	if state.PendingColor != components.ColorRed || state.Flags&components.StateCodeNeeded == 0 {
+		t.Error("suppressed dispatch should escalate straight to the red code")
+	}
+	if state.Flags&components.StateIncidentOpen == 0 {
+		t.Error("suppressed dispatch should open the incident")
+	}
+
+	// An hour later the window resets and interventions resume.
+	meta.InterventionWindowStart = meta.InterventionWindowStart.Add(-61 * time.Minute)
+	meta.LastInterventionTime = meta.LastInterventionTime.Add(-61 * time.Minute)
+	state.Flags &^= components.StateInterventionPending
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+
+	if got := enqueuedCount(q); got != 4 {
+		t.Fatalf("%d interventions fired after window reset, want 4", got)
+	}
+	if meta.InterventionWindowCount != 1 {
+		t.Errorf("InterventionWindowCount = %d, want 1 in the fresh window", meta.InterventionWindowCount)
+	}
+}
+
+// TestBatchInterventionSystem_Cooldown verifies the cooldown window
+// suppresses back-to-back dispatches and lifts once it expires. Time is
+// driven by a manual clock, so the 10-minute window elapses instantly.
+func TestBatchInterventionSystem_Cooldown(t *testing.T) {
+	world := ecs.NewWorld()
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+	q := newMockQueue(64)
+	clk := clock.NewManual(time.Time{})
+
+	system := NewBatchInterventionSystem(&world, q, 16, logger, stateLogger)
+	system.SetClock(clk)
+	system.Initialize(&world)
+
+	entity := newChainTestEntity(t, &world)
+	state := ecs.NewMap[components.MonitorState](&world).Get(entity)
+	config := ecs.NewMap[components.InterventionConfig](&world).Get(entity)
+	config.Cooldown = 10 * time.Minute
+
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := enqueuedCount(q); got != 1 {
+		t.Fatalf("%d interventions fired, want 1", got)
+	}
+
+	// Still failing right after the dispatch: inside the cooldown window.
+	state.Flags &^= components.StateInterventionPending
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := enqueuedCount(q); got != 1 {
+		t.Fatalf("%d interventions fired inside cooldown, want still 1", got)
+	}
+	meta := ecs.NewMap[components.MonitorMeta](&world).Get(entity)
+	if meta.InterventionsSuppressed != 1 {
+		t.Errorf("InterventionsSuppressed = %d, want 1", meta.InterventionsSuppressed)
+	}
+
+	// Once the cooldown expires the next dispatch goes through.
+	clk.Advance(11 * time.Minute)
+	state.Flags &^= components.StateInterventionPending
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := enqueuedCount(q); got != 2 {
+		t.Fatalf("%d interventions fired after cooldown, want 2", got)
+	}
+}
+
+// TestBatchInterventionSystem_HourlyCapWindow drives the max-per-hour
+// safety valve across a full window boundary on a manual clock.
+func TestBatchInterventionSystem_HourlyCapWindow(t *testing.T) {
+	world := ecs.NewWorld()
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+	q := newMockQueue(64)
+	clk := clock.NewManual(time.Time{})
+
+	system := NewBatchInterventionSystem(&world, q, 16, logger, stateLogger)
+	system.SetClock(clk)
+	system.Initialize(&world)
+
+	entity := newChainTestEntity(t, &world)
+	state := ecs.NewMap[components.MonitorState](&world).Get(entity)
+	config := ecs.NewMap[components.InterventionConfig](&world).Get(entity)
+	config.MaxPerHour = 2
+
+	// Two dispatches a few minutes apart fill the hourly budget.
+	for i := 0; i < 2; i++ {
+		state.Flags &^= components.StateInterventionPending
+		state.Flags |= components.StateInterventionNeeded
+		system.Update(&world)
+		clk.Advance(5 * time.Minute)
+	}
+	if got := enqueuedCount(q); got != 2 {
+		t.Fatalf("%d interventions fired, want 2", got)
+	}
+
+	// The third trigger inside the window is suppressed.
+	state.Flags &^= components.StateInterventionPending
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := enqueuedCount(q); got != 2 {
+		t.Fatalf("%d interventions fired over the hourly cap, want still 2", got)
+	}
+	if system.SuppressedCount() != 1 {
+		t.Errorf("SuppressedCount = %d, want 1", system.SuppressedCount())
+	}
+
+	// Once the window rolls over, dispatching resumes.
+	clk.Advance(time.Hour)
+	state.Flags &^= components.StateInterventionPending
+	state.Flags |= components.StateInterventionNeeded
+	system.Update(&world)
+	if got := enqueuedCount(q); got != 3 {
+		t.Fatalf("%d interventions fired after the window rolled, want 3", got)
+	}
+}
+
+// TestInterventionStepJob_PayloadCarriesStep executes a chain step and
+// checks the result payload reports the escalation level.
+func TestInterventionStepJob_PayloadCarriesStep(t *testing.T) {
+	t.Parallel()
+	step := schema.InterventionStep{
+		Action:        "exec",
+		Target:        &schema.InterventionTargetExec{Command: "echo", Args: []string{"restart service"}},
+		AfterFailures: 2,
+	}
+	job, err := jobs.CreateInterventionStepJob(step, 1, ecs.Entity{})
+	if err != nil {
+		t.Fatalf("CreateInterventionStepJob failed: %v", err)
+	}
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	if result.Payload.Extra("step") != 1 {
+		t.Errorf("payload step = %v, want 1", result.Payload.Extra("step"))
+	}
+}
+
+// auditedEvents polls the sink until the monitor has at least n committed
+// audit rows; the sink's writer goroutine persists asynchronously.
+func auditedEvents(t *testing.T, sink *audit.SQLiteSink, monitor string, n int) []audit.Event {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		events, err := sink.Recent(monitor, 0)
+		if err != nil {
+			t.Fatalf("Recent failed: %v", err)
+		}
+		if len(events) >= n {
+			return events
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d audit events for %q, want %d", len(events), monitor, n)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+// TestBatchInterventionResultSystem_AuditTrail delivers a failed and a
+// successful intervention result and asserts both land in the audit log with
+// action, attempts, and latency intact.
+func TestBatchInterventionResultSystem_AuditTrail(t *testing.T) {
+	world := ecs.NewWorld()
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+
+	resultChan := make(chan []jobs.Result, 4)
+	resultSystem := NewBatchInterventionResultSystem(&world, resultChan, logger, stateLogger)
+	resultSystem.Initialize(&world)
+
+	sink, err := audit.NewSQLiteSink(":memory:", 0)
+	if err != nil {
+		t.Fatalf("NewSQLiteSink failed: %v", err)
+	}
+	defer sink.Close()
+	resultSystem.SetAuditSink(sink)
+
+	entity := newChainTestEntity(t, &world)
+	state := ecs.NewMap[components.MonitorState](&world).Get(entity)
+
+	// The result system only applies results to monitors it believes have an
+	// intervention in flight.
+	state.Flags |= components.StateInterventionPending
+	resultChan <- []jobs.Result{{
+		Ent:      entity,
+		Err:      jobs.ErrExecUnexpectedExitCode,
+		Duration: 2 * time.Second,
+		Payload:  jobs.Payload{Kind: jobs.KindIntervention, Attempts: 2, Extras: map[string]interface{}{"step": 0}},
+	}}
+	resultSystem.Update(&world)
+	state.Flags |= components.StateInterventionPending
+	resultChan <- []jobs.Result{{
+		Ent:      entity,
+		Duration: 500 * time.Millisecond,
+		Payload:  jobs.Payload{Kind: jobs.KindIntervention, Extras: map[string]interface{}{"step": 0}},
+	}}
+	resultSystem.Update(&world)
+
+	events := auditedEvents(t, sink, "payments-api", 2)
+	if len(events) != 2 {
+		t.Fatalf("got %d audit events, want 2", len(events))
+	}
+
+	// Newest first: the success, then the failure.
+	success, failure := events[0], events[1]
+	if !success.Success || success.Error != "" || success.Latency != 500*time.Millisecond {
+		t.Errorf("success event = %+v, want success with 500ms latency", success)
+	}
+	if failure.Success || failure.Error == "" || failure.Attempts != 2 || failure.Latency != 2*time.Second {
+		t.Errorf("failure event = %+v, want failed with 2 attempts and 2s latency", failure)
+	}
+	for _, ev := range events {
+		if ev.Kind != audit.KindIntervention || ev.Action != "exec" || ev.Monitor != "payments-api" {
+			t.Errorf("event = %+v, want intervention/exec for payments-api", ev)
+		}
+		if ev.EntityID != uint32(entity.ID()) {
+			t.Errorf("entity id = %d, want %d", ev.EntityID, entity.ID())
+		}
+	}
+}
+
+// TestBatchInterventionResultSystem_DryRun delivers a dry-run result and
+// asserts nothing is treated as remediation: failure counters and the
+// verification window are untouched, while the cyan "would have intervened"
+// notification is still flagged.
+func TestBatchInterventionResultSystem_DryRun(t *testing.T) {
+	world := ecs.NewWorld()
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+
+	resultChan := make(chan []jobs.Result, 4)
+	resultSystem := NewBatchInterventionResultSystem(&world, resultChan, logger, stateLogger)
+	resultSystem.Initialize(&world)
+
+	entity := newChainTestEntity(t, &world)
+	state := ecs.NewMap[components.MonitorState](&world).Get(entity)
+
+	// Give the monitor a dispatchable cyan config so the dry-run
+	// notification can be flagged.
+	reg := components.DefaultConfigRegistry()
+	codeConfig := ecs.NewMap[components.CodeConfig](&world).Get(entity)
+	codeConfig.Configs[components.ColorCyan] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: true,
+		Config:   &schema.CodeNotificationLog{File: "/var/log/dry-run-test.log"},
+	})
+
+	lastErr := jobs.ErrExecUnexpectedExitCode
+	meta := ecs.NewMap[components.MonitorMeta](&world).Get(entity)
+	state.ConsecutiveFailures = 5
+	meta.LastError = lastErr
+	state.Flags |= components.StateInterventionPending
+
+	payload := jobs.Payload{Kind: jobs.KindIntervention, Driver: "docker"}
+	payload.SetExtra("dry_run", true)
+	payload.SetExtra("would_be_action", "docker")
+	payload.SetExtra("would_be_target", "docker restart payments-api")
+	resultChan <- []jobs.Result{{Ent: entity, Payload: payload}}
+	resultSystem.Update(&world)
+
+	if state.ConsecutiveFailures != 5 {
+		t.Errorf("ConsecutiveFailures = %d, want 5 (dry run must not reset)", state.ConsecutiveFailures)
+	}
+	if meta.LastError != lastErr {
+		t.Errorf("LastError = %v, want unchanged", meta.LastError)
+	}
+	if state.Flags&components.StateVerifying != 0 {
+		t.Error("dry run must not open a verification window")
+	}
+	if state.Flags&components.StateInterventionPending != 0 {
+		t.Error("pending flag must clear so the monitor is not stuck")
+	}
+	if state.Flags&components.StateCodeNeeded == 0 || state.PendingColor != components.ColorCyan {
+		t.Errorf("flags=%v color=%v, want cyan notification flagged", state.Flags, state.PendingColor)
+	}
+	if state.InterventionFailures != 0 {
+		t.Errorf("InterventionFailures = %d, want 0 (dry run is not a failure)", state.InterventionFailures)
+	}
+}
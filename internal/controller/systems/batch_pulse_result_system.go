@@ -0,0 +1,646 @@
+package systems
+
+import (
+	"errors"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+	"cpra/internal/queue"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultPanicDisableThreshold is how many consecutive panicked checks
+// auto-disable a monitor when panic auto-disable is wired.
+const DefaultPanicDisableThreshold = 3
+
+// BatchPulseResultSystem processes completed pulse checks.
+// It queries for entities with a PulseResult component and updates their state accordingly.
+type BatchPulseResultSystem struct {
+	world       *ecs.World
+	logger      Logger
+	stateLogger *StateLogger
+
+	// Mappers are used for efficient component access
+	stateMapper              *ecs.Map1[components.MonitorState]
+	metaMapper               *ecs.Map1[components.MonitorMeta]
+	configMapper             *ecs.Map1[components.PulseConfig]
+	codeConfigMapper         *ecs.Map1[components.CodeConfig]
+	interventionConfigMapper *ecs.Map1[components.InterventionConfig]
+	historyMapper            *ecs.Map1[components.ResultHistory]
+	sloMapper                *ecs.Map1[components.SLO]
+	registry                 *components.ConfigRegistry
+	ResultChan               <-chan []jobs.Result
+	faults                   *FaultInjector
+	drain                    resultDrain
+	sharedProbes             *SharedProbeRegistry                     // nil unless probe dedup is on
+	disabledMapper           *ecs.Map1[components.Disabled]           // set with sharedProbes or panic auto-disable
+	explicitMapper           *ecs.Map1[components.ExplicitlyDisabled] // set with commands
+	tracer                   trace.Tracer                             // nil unless tracing is on
+	jobStorageMapper         *ecs.Map1[components.JobStorage]         // set with tracer
+	commands                 *CommandSystem                           // nil unless panic auto-disable is on
+	codeQueue                queue.Queue                              // set with commands
+	panicThreshold           int                                      // set with commands
+}
+
+// NewBatchPulseResultSystem creates a new BatchPulseResultSystem.
+func NewBatchPulseResultSystem(world *ecs.World, results <-chan []jobs.Result, logger Logger, stateLogger *StateLogger) *BatchPulseResultSystem {
+	return &BatchPulseResultSystem{
+		world:                    world,
+		logger:                   logger,
+		stateLogger:              stateLogger,
+		stateMapper:              ecs.NewMap1[components.MonitorState](world),
+		metaMapper:               ecs.NewMap1[components.MonitorMeta](world),
+		configMapper:             ecs.NewMap1[components.PulseConfig](world),
+		codeConfigMapper:         ecs.NewMap1[components.CodeConfig](world),
+		interventionConfigMapper: ecs.NewMap1[components.InterventionConfig](world),
+		historyMapper:            ecs.NewMap1[components.ResultHistory](world),
+		sloMapper:                ecs.NewMap1[components.SLO](world),
+		registry:                 components.DefaultConfigRegistry(),
+		ResultChan:               results,
+		drain:                    newResultDrain(),
+	}
+}
+func (s *BatchPulseResultSystem) Initialize(_ *ecs.World) {
+}
+
+// SetFaultInjector enables soak-test fault injection on the result path.
+func (s *BatchPulseResultSystem) SetFaultInjector(f *FaultInjector) {
+	s.faults = f
+}
+
+// SetSharedProbes attaches the shared-probe registry so leader results fan
+// out to their followers. Nil (the default) disables fan-out.
+func (s *BatchPulseResultSystem) SetSharedProbes(r *SharedProbeRegistry) {
+	s.sharedProbes = r
+	s.disabledMapper = ecs.NewMap1[components.Disabled](s.world)
+}
+
+// SetPanicAutoDisable wires the command buffer and code queue used to take a
+// monitor out of rotation after threshold consecutive panicked checks. Nil
+// commands (the default) leaves panic results counting as plain failures. A
+// non-positive threshold uses DefaultPanicDisableThreshold.
+func (s *BatchPulseResultSystem) SetPanicAutoDisable(commands *CommandSystem, codeQueue queue.Queue, threshold int) {
+	s.commands = commands
+	s.codeQueue = codeQueue
+	if threshold <= 0 {
+		threshold = DefaultPanicDisableThreshold
+	}
+	s.panicThreshold = threshold
+	if s.disabledMapper == nil {
+		s.disabledMapper = ecs.NewMap1[components.Disabled](s.world)
+	}
+	s.explicitMapper = ecs.NewMap1[components.ExplicitlyDisabled](s.world)
+}
+
+// SetTracer enables closing dispatch-opened trace spans after result
+// application. Nil (the default) keeps the result path free of tracing work.
+func (s *BatchPulseResultSystem) SetTracer(t trace.Tracer) {
+	s.tracer = t
+	s.jobStorageMapper = ecs.NewMap1[components.JobStorage](s.world)
+}
+
+// finishTrace closes a traced check's span tree: a child span covering the
+// wait between batch receipt and this result's application, then the root.
+// The job's carried context is cleared so a fanned-out or recovered result
+// cannot end a span twice.
+func (s *BatchPulseResultSystem) finishTrace(ent ecs.Entity, result jobs.Result, batchStart time.Time) {
+	storage := s.jobStorageMapper.Get(ent)
+	if storage == nil {
+		return
+	}
+	carrier, ok := storage.PulseJob.(jobs.TraceCarrier)
+	if !ok {
+		return
+	}
+	ctx := carrier.TraceContext()
+	if ctx == nil {
+		return
+	}
+	carrier.SetTraceContext(nil)
+
+	_, applySpan := s.tracer.Start(ctx, "pulse.apply", trace.WithTimestamp(batchStart))
+	applySpan.End()
+	root := trace.SpanFromContext(ctx)
+	if err := result.Error(); err != nil {
+		root.SetStatus(codes.Error, err.Error())
+	}
+	root.End()
+}
+
+// fanOutShared expands a result batch with copies for each group follower
+// that is due by its own interval. A follower's copy is made dispatch-shaped
+// first — pending flag, check times — so the per-result application below
+// treats it exactly like a result the follower probed for itself, keeping
+// its failure accounting independent of the leader's.
+func (s *BatchPulseResultSystem) fanOutShared(results []jobs.Result) []jobs.Result {
+	now := time.Now()
+	for _, result := range results {
+		followers := s.sharedProbes.Followers(result.Entity())
+		if len(followers) == 0 {
+			continue
+		}
+		for _, ent := range followers {
+			if !s.world.Alive(ent) || s.disabledMapper.HasAll(ent) {
+				continue
+			}
+			state := s.stateMapper.Get(ent)
+			config := s.configMapper.Get(ent)
+			if state == nil || config == nil {
+				continue
+			}
+			if state.Flags&components.StatePulsePending != 0 {
+				continue
+			}
+			// The leader probes at the group's smallest interval; a follower
+			// with a longer one only consumes results its own schedule asks
+			// for.
+			due := state.Flags&components.StatePulseFirstCheck != 0 ||
+				now.Sub(state.LastPulseCheckTime) >= config.Interval
+			if !due {
+				continue
+			}
+			oldState := *state
+			state.Flags &^= components.StatePulseFirstCheck
+			state.Flags &^= components.StatePulseNeeded
+			state.Flags |= components.StatePulsePending
+			state.PendingSince = now
+			state.LastPulseCheckTime = now
+			state.NextCheckTime = now.Add(config.Interval)
+			s.stateLogger.LogTransition(ent, oldState, *state)
+
+			copied := result
+			copied.Ent = ent
+			results = append(results, copied)
+		}
+	}
+	return results
+}
+
+// SetResultBudget overrides the per-tick result and time budgets; see
+// resultDrain. Non-positive values keep the defaults.
+func (s *BatchPulseResultSystem) SetResultBudget(maxResults int, maxTime time.Duration) {
+	s.drain.setBudget(maxResults, maxTime)
+}
+
+// ConsumedLastTick reports how many pulse results the last Update applied.
+func (s *BatchPulseResultSystem) ConsumedLastTick() int64 { return s.drain.ConsumedLastTick() }
+
+// ResultBacklog reports how many received pulse results await application.
+func (s *BatchPulseResultSystem) ResultBacklog() int64 { return s.drain.Backlog() }
+
+func (s *BatchPulseResultSystem) Update(_ *ecs.World) {
+	if s.ResultChan == nil {
+		// Channel closed; finish applying any carried-over batches.
+		s.drain.apply(s.ProcessBatch)
+		return
+	}
+
+	if s.faults != nil {
+		// Soak mode: deliver batches whose injected delay has elapsed.
+		for _, res := range s.faults.TakeDelayed() {
+			s.drain.add(res)
+		}
+	}
+loop:
+	for {
+		select {
+		case res, ok := <-s.ResultChan:
+			if !ok {
+				s.ResultChan = nil
+				break loop
+			}
+			if s.faults != nil {
+				var deliver bool
+				if res, deliver = s.faults.InterceptResultBatch(res); !deliver {
+					continue
+				}
+			}
+			s.drain.add(res)
+		default:
+			break loop
+		}
+	}
+
+	// Apply under the per-tick budget; the remainder carries over so a
+	// burst cannot consume the whole tick.
+	s.drain.apply(s.ProcessBatch)
+}
+
+// ProcessBatch processes a batch of pulse results.
+func (s *BatchPulseResultSystem) ProcessBatch(results []jobs.Result) {
+	startTime := time.Now()
+	processedCount := 0
+
+	if s.sharedProbes != nil {
+		results = s.fanOutShared(results)
+	}
+
+	// Thresholds now come from PulseConfig; fall back to defaults if unset
+	const defaultK = 2
+	const defaultM = 3
+	// degradedLatencyK is how many consecutive successful checks the
+	// latency EWMA must exceed degraded_latency before yellow fires.
+	const degradedLatencyK = 3
+
+	for _, result := range results {
+		ent := result.Entity()
+		if !s.world.Alive(ent) {
+			continue
+		}
+
+		// Close a traced check's span tree at application time; the root
+		// span's duration is then the full enqueue-to-applied lifecycle.
+		if s.tracer != nil {
+			s.finishTrace(ent, result, startTime)
+		}
+
+		state := s.stateMapper.Get(ent)
+		meta := s.metaMapper.Get(ent)
+		config := s.configMapper.Get(ent)
+
+		flags := state.Flags
+		if (flags & components.StatePulsePending) == 0 {
+			s.logger.Warnf("Entity[%d] received a PulseResult but was not in a PulsePending state.", ent.ID())
+			continue
+		}
+
+		processedCount++
+		oldState := *state
+		eventTime := time.Now()
+		meta.LastEventTime = eventTime
+
+		// Rolling latency: EWMA (alpha 1/8) plus worst case, fed by the
+		// job-measured check duration when the driver reports one.
+		if d := result.Duration; d > 0 {
+			if meta.LatencyEWMA == 0 {
+				meta.LatencyEWMA = d
+			} else {
+				meta.LatencyEWMA += (d - meta.LatencyEWMA) / 8
+			}
+			if d > meta.LatencyMax {
+				meta.LatencyMax = d
+			}
+		}
+
+		// Failures attributed to the CPRA host itself (socket/fd exhaustion)
+		// do not count against the monitor - and for inverted monitors they
+		// do not count as healthy either: the check never reached the target.
+		if result.Error() != nil && result.Class == jobs.ErrorClassInternalInfrastructure {
+			s.logger.Warnf("Monitor '%s' check failed due to local infrastructure exhaustion; not counted as monitor failure: %v", meta.Name, result.Error())
+			state.Flags &^= components.StatePulsePending
+			s.stateLogger.LogTransition(ent, oldState, *state)
+			continue
+		}
+
+		// A rate-limited probe is the target asking us to slow down, not
+		// evidence of ill health. Honor the Retry-After by pushing the next
+		// check out either way; unless the pulse opts in with
+		// count_rate_limit_as_failure, the check is also exempt from
+		// failure accounting, exactly like an infra-classed one.
+		if limited, _ := result.Payload.Extra("rate_limited").(bool); limited {
+			if secs, ok := result.Payload.Extra("retry_after_seconds").(int); ok && secs > 0 {
+				if delayed := eventTime.Add(time.Duration(secs) * time.Second); delayed.After(state.NextCheckTime) {
+					state.NextCheckTime = delayed
+				}
+			}
+			if !config.CountRateLimitAsFailure {
+				s.logger.Warnf("Monitor '%s' was rate limited by the target; not counted as monitor failure", meta.Name)
+				state.Flags &^= components.StatePulsePending
+				s.stateLogger.LogTransition(ent, oldState, *state)
+				continue
+			}
+		}
+
+		// A panicked check crashed inside CPRA's own driver and fails the
+		// monitor deterministically — the invert flip below must not read
+		// the crash as the target being down. Repeated panics point at a
+		// poisonous config rather than a flaky target, so after enough in
+		// a row the monitor is taken out of rotation through the command
+		// buffer (tag adds must not happen mid-batch).
+		panicked := errors.Is(result.Error(), jobs.ErrJobPanicked)
+		if panicked {
+			meta.PanicStreak++
+			if s.commands != nil && meta.PanicStreak >= s.panicThreshold {
+				s.logger.Errorf("Monitor '%s' pulse check panicked %d times in a row; auto-disabling: %v",
+					meta.Name, meta.PanicStreak, result.Error())
+				meta.LastError = result.Error()
+				meta.PanicStreak = 0
+				state.Flags &^= components.StatePulsePending
+				s.submitPanicDisable(ent)
+				s.stateLogger.LogTransition(ent, oldState, *state)
+				continue
+			}
+		} else {
+			meta.PanicStreak = 0
+		}
+
+		// Inverted monitors watch endpoints that are supposed to be down.
+		// The interpretation flips here, at the result system, so the job
+		// payload still records what actually happened on the wire.
+		checkErr := result.Error()
+		if config.Invert && !panicked {
+			if checkErr == nil {
+				checkErr = jobs.ErrUnexpectedResponse
+			} else {
+				checkErr = nil
+			}
+		}
+
+		if checkErr != nil {
+			// --- FAILURE ---
+			meta.LastError = checkErr
+			// ConsecutiveFailures counts failed checks since the last
+			// success; escalation chains compare it against per-step
+			// after_failures thresholds.
+			state.ConsecutiveFailures++
+			// Attempt accounting: with retries configured one failed check
+			// hides several failed attempts, so track their volume for the
+			// attempt-level threshold. Drivers that do not report attempts
+			// count as one.
+			attempts := result.Payload.Attempts
+			if attempts <= 0 {
+				attempts = 1
+			}
+			state.StreakFailedAttempts += attempts
+			// If we are in verification window, escalate to RED and close verification
+			if flags&components.StateVerifying != 0 {
+				s.logger.Warnf("Monitor '%s' verification failed during post-intervention window: %v", meta.Name, meta.LastError)
+				// Only trigger red if incident not already open (defensive)
+				if (flags & components.StateIncidentOpen) == 0 {
+					s.triggerCode(ent, state, components.ColorRed)
+					state.Flags |= components.StateIncidentOpen
+					s.logger.Infof("Monitor '%s' - RED ALERT: verification failed, incident opened", meta.Name)
+				}
+				state.Flags &^= components.StateVerifying
+				state.VerifyRemaining = 0
+				state.RecoveryStreak = 0
+			} else {
+				state.PulseFailures++
+				s.logger.Warnf("Monitor '%s' pulse failed (%d/%d): %v", meta.Name, state.PulseFailures, config.UnhealthyThreshold, meta.LastError)
+				// First failure: only send yellow if no incident is open
+				if state.PulseFailures == 1 && (flags&components.StateIncidentOpen) == 0 {
+					s.triggerCode(ent, state, components.ColorYellow)
+				}
+				unhealthy := config.UnhealthyThreshold
+				if unhealthy <= 0 {
+					unhealthy = 1
+				}
+				// A hard-down target burns through retry attempts far
+				// faster than a barely-failing one; the attempt threshold
+				// lets it escalate before unhealthy checks accumulate.
+				overThreshold := state.PulseFailures >= unhealthy
+				if !overThreshold && config.UnhealthyAttemptThreshold > 0 &&
+					state.StreakFailedAttempts >= config.UnhealthyAttemptThreshold {
+					s.logger.Warnf("Monitor '%s' reached %d failed attempts (threshold %d) in %d checks.",
+						meta.Name, state.StreakFailedAttempts, config.UnhealthyAttemptThreshold, state.PulseFailures)
+					overThreshold = true
+				}
+				if overThreshold {
+					if s.interventionConfigMapper.HasAll(ent) {
+						// FSM guard: Only trigger intervention if not already pending/needed
+						if (state.Flags&components.StateInterventionNeeded) == 0 && (state.Flags&components.StateInterventionPending) == 0 {
+							s.logger.Warnf("Monitor '%s' reached max failures, triggering intervention.", meta.Name)
+							state.Flags |= components.StateInterventionNeeded
+							state.PulseFailures = 0
+							state.StreakFailedAttempts = 0
+							state.RecoveryStreak = 0
+						} else {
+							s.logger.Debugf("Monitor '%s' - max failures reached but intervention already in progress", meta.Name)
+						}
+					} else {
+						// No intervention configured - trigger RED alert once
+						if (flags & components.StateIncidentOpen) == 0 {
+							s.logger.Warnf("Monitor '%s' reached max failures; no intervention configured, triggering RED alert.", meta.Name)
+							s.triggerCode(ent, state, components.ColorRed)
+							state.Flags |= components.StateIncidentOpen
+							s.logger.Infof("Monitor '%s' - RED ALERT: incident opened (no intervention)", meta.Name)
+						} else {
+							s.logger.Debugf("Monitor '%s' - max failures reached but incident already open, no duplicate red alert", meta.Name)
+						}
+						state.PulseFailures = 0
+						state.StreakFailedAttempts = 0
+						state.RecoveryStreak = 0
+					}
+				}
+			}
+		} else {
+			// --- SUCCESS ---
+			meta.LastError = nil
+			meta.LastSuccessTime = eventTime
+			state.ConsecutiveFailures = 0
+			state.StreakFailedAttempts = 0
+			if flags&components.StateVerifying != 0 {
+				if state.VerifyRemaining <= 0 {
+					// safety: conclude verification immediately
+					state.Flags &^= components.StateVerifying
+					s.triggerCode(ent, state, components.ColorGreen)
+					state.Flags &^= components.StateIncidentOpen
+					state.RecoveryStreak = 0
+					state.EscalationStep = 0
+				} else {
+					state.VerifyRemaining--
+					if state.VerifyRemaining <= 0 {
+						state.Flags &^= components.StateVerifying
+						s.triggerCode(ent, state, components.ColorGreen)
+						state.Flags &^= components.StateIncidentOpen
+						state.RecoveryStreak = 0
+						state.EscalationStep = 0
+					}
+				}
+			} else {
+				// Normal recovery path
+				if state.PulseFailures > 0 || (flags&components.StateIncidentOpen) != 0 {
+					state.RecoveryStreak++
+					k := config.HealthyThreshold
+					if k <= 0 {
+						k = defaultK
+					}
+					if state.RecoveryStreak >= k {
+						s.logger.Infof("Monitor '%s' pulse recovered (K=%d).", meta.Name, k)
+						s.triggerCode(ent, state, components.ColorGreen)
+						state.Flags &^= components.StateIncidentOpen
+						state.RecoveryStreak = 0
+						// A confirmed recovery rewinds the escalation chain.
+						state.EscalationStep = 0
+					}
+				} else {
+					// steady state success, nothing to do
+				}
+			}
+			state.PulseFailures = 0
+			// A success can still be degraded: the configured phase exceeded
+			// its latency threshold. Warn with yellow, but don't fight an
+			// open incident or a verification window.
+			if degraded, _ := result.Payload.Extra("degraded").(bool); degraded &&
+				(state.Flags&(components.StateIncidentOpen|components.StateVerifying)) == 0 {
+				phase, _ := result.Payload.Extra("degraded_phase").(string)
+				s.logger.Warnf("Monitor '%s' check succeeded but %s latency exceeded degraded_threshold", meta.Name, phase)
+				s.triggerCode(ent, state, components.ColorYellow)
+			}
+			// Trend-based degradation: one slow check is the per-result
+			// marker's job above; this fires when the rolling EWMA has sat
+			// above degraded_latency for enough consecutive successes.
+			if config.DegradedLatency > 0 && result.Duration > 0 {
+				if meta.LatencyEWMA > config.DegradedLatency {
+					state.DegradedLatencyStreak++
+					if state.DegradedLatencyStreak >= degradedLatencyK &&
+						(state.Flags&(components.StateIncidentOpen|components.StateVerifying)) == 0 {
+						s.logger.Warnf("Monitor '%s' rolling latency %v exceeded degraded_latency %v for %d consecutive checks",
+							meta.Name, meta.LatencyEWMA.Round(time.Millisecond), config.DegradedLatency, state.DegradedLatencyStreak)
+						s.triggerCode(ent, state, components.ColorYellow)
+						state.DegradedLatencyStreak = 0
+					}
+				} else {
+					state.DegradedLatencyStreak = 0
+				}
+			}
+		}
+
+		// Feed the SLO outcome rings when an objective is declared. Burn
+		// rates are evaluated separately by the SLOSystem; this path only
+		// counts. Internal-infrastructure failures never reach here, so
+		// they never spend error budget.
+		if s.sloMapper.HasAll(ent) {
+			slo := s.sloMapper.Get(ent)
+			slow := slo.LatencyThreshold > 0 && result.Duration > slo.LatencyThreshold
+			slo.Record(eventTime, checkErr == nil, slow)
+		}
+
+		// Record the outcome in the monitor's history ring, if one is
+		// attached. Latency spans dispatch to result processing, so it
+		// includes queue time on top of the check itself.
+		if s.historyMapper.HasAll(ent) {
+			sample := components.ResultSample{
+				Timestamp: eventTime,
+				LatencyMs: float32(eventTime.Sub(state.LastPulseCheckTime).Seconds() * 1000),
+				Success:   checkErr == nil,
+			}
+			if checkErr != nil {
+				sample.Error = checkErr.Error()
+			}
+			s.historyMapper.Get(ent).Append(sample)
+		}
+
+		// Unset the pending flag, regardless of outcome.
+		state.Flags &^= components.StatePulsePending
+		s.stateLogger.LogTransition(ent, oldState, *state)
+	}
+
+	if processedCount > 0 {
+		dur := time.Since(startTime)
+		LogSystemPerformance(s.logger, "BatchPulseResultSystem", processedCount, dur)
+	}
+}
+
+func (s *BatchPulseResultSystem) triggerCode(entity ecs.Entity, state *components.MonitorState, color components.ColorCode) {
+	// Check with HasAll before Get: the nil pointer Ark returns for a missing
+	// component is produced via unsafe arithmetic, and the optimizer is allowed
+	// to elide a == nil check on it.
+	if !s.codeConfigMapper.HasAll(entity) {
+		return
+	}
+	codeConfig := s.codeConfigMapper.Get(entity)
+	if color >= components.MaxColors {
+		return
+	}
+	monitorName := s.metaMapper.Get(entity).Name
+	cfg, ok := s.registry.Lookup(codeConfig.Configs[color])
+	if !ok || cfg.Notify == "" {
+		s.logger.Warnf("Monitor '%s' has no '%s' code config; skipping alert trigger", monitorName, color)
+		return
+	}
+	if !cfg.Dispatch {
+		s.logger.Infof("Monitor '%s' '%s' code dispatch disabled; not triggering", monitorName, color)
+		return
+	}
+
+	// FSM guard: If a code job is already in-flight (Pending), don't overwrite.
+	if (state.Flags & components.StateCodePending) != 0 {
+		s.logger.Debugf("Monitor '%s' already has code in-flight; deferring %s trigger", monitorName, color)
+		return
+	}
+
+	// If CodeNeeded is already set, use priority to decide.
+	if (state.Flags&components.StateCodeNeeded) != 0 && state.PendingColor != components.ColorNone {
+		if !color.HigherPriorityThan(state.PendingColor) {
+			s.logger.Debugf("Monitor '%s' already has %s pending; %s has lower priority, skipping", monitorName, state.PendingColor, color)
+			return
+		}
+		s.logger.Debugf("Monitor '%s' upgrading pending code from %s to %s", monitorName, state.PendingColor, color)
+	}
+
+	state.PendingColor = color
+	state.Flags |= components.StateCodeNeeded
+	s.logger.Infof("Monitor '%s' - triggering %s alert code", monitorName, color)
+}
+
+// submitPanicDisable queues the disable for the next tick's command window,
+// where adding the Disabled tag cannot invalidate component pointers held
+// by this batch. It mirrors MaintenanceSystem.enterMaintenance: drop
+// pending work, then announce the crash with one directly-enqueued red
+// code, since BatchCodeSystem no longer sees the entity once the tag is
+// applied.
+func (s *BatchPulseResultSystem) submitPanicDisable(ent ecs.Entity) {
+	err := s.commands.Submit(func(w *ecs.World) {
+		if !w.Alive(ent) || s.disabledMapper.HasAll(ent) {
+			return
+		}
+		// Marked explicit so a schedule or maintenance edge does not put a
+		// crash-looping monitor back into rotation.
+		s.disabledMapper.Add(ent, &components.Disabled{})
+		s.explicitMapper.Add(ent, &components.ExplicitlyDisabled{})
+		state := s.stateMapper.Get(ent)
+		if state == nil {
+			return
+		}
+		oldState := *state
+		state.SetPulsePending(false)
+		state.SetInterventionPending(false)
+		state.SetCodePending(false)
+		s.dispatchCrashRed(ent, state)
+		s.stateLogger.LogTransition(ent, oldState, *state)
+	})
+	if err != nil {
+		s.logger.Warnf("Failed to submit panic auto-disable command: %v", err)
+	}
+}
+
+// dispatchCrashRed enqueues one red code announcing the auto-disable.
+// Monitors without a dispatchable red config go quiet silently.
+func (s *BatchPulseResultSystem) dispatchCrashRed(ent ecs.Entity, state *components.MonitorState) {
+	if s.codeQueue == nil || !s.codeConfigMapper.HasAll(ent) {
+		return
+	}
+	codeConfig := s.codeConfigMapper.Get(ent)
+	cfg, ok := s.registry.Lookup(codeConfig.Configs[components.ColorRed])
+	if !ok || cfg.Notify == "" || !cfg.Dispatch {
+		return
+	}
+
+	schemaCfg := schema.CodeConfig{
+		Dispatch: cfg.Dispatch,
+		Notify:   cfg.Notify,
+		Config:   cfg.Config,
+	}
+	monitorName := s.metaMapper.Get(ent).Name
+	job, err := jobs.CreateCrashCodeJob(monitorName, schemaCfg, ent, components.ColorRed.String())
+	if err != nil || isNilJob(job) {
+		s.logger.Warnf("Monitor '%s': failed to create crash red code job: %v", monitorName, err)
+		return
+	}
+	job = fanOutCodeJob(s.registry, codeConfig, job, monitorName, ent, components.ColorRed, false, s.logger)
+	jobs.ApplyLabels(job, s.metaMapper.Get(ent).Labels)
+	if err := s.codeQueue.Enqueue(job); err != nil {
+		s.logger.Warnf("Monitor '%s': failed to enqueue crash red code: %v", monitorName, err)
+		return
+	}
+	state.Flags |= components.StateCodePending
+}
+
+// Finalize is a no-op for this system.
+func (s *BatchPulseResultSystem) Finalize(_ *ecs.World) {}
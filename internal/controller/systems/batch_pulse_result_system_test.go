@@ -0,0 +1,608 @@
+package systems
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+func TestBatchPulseResultSystem_InfraClassedFailureExempt(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	results := make(chan []jobs.Result, 1)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulsePending},
+		&components.MonitorMeta{},
+		&components.PulseConfig{UnhealthyThreshold: 1, HealthyThreshold: 1},
+	)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+
+	// A failure attributed to local infrastructure exhaustion must not count
+	// against the monitor: no failure increment, no incident, pending cleared.
+	resultSystem.ProcessBatch([]jobs.Result{{
+		Ent:   ent,
+		Err:   jobs.ErrTCPCheckFailed,
+		Class: jobs.ErrorClassInternalInfrastructure,
+	}})
+
+	state := stateMapper.Get(ent)
+	if state.PulseFailures != 0 {
+		t.Errorf("PulseFailures = %d, want 0", state.PulseFailures)
+	}
+	if state.Flags&components.StateIncidentOpen != 0 {
+		t.Error("expected no incident for infra-classed failure")
+	}
+	if state.Flags&components.StatePulsePending != 0 {
+		t.Error("expected pending flag cleared")
+	}
+
+	// The same error without the infra class counts as a normal failure.
+	state.Flags |= components.StatePulsePending
+	resultSystem.ProcessBatch([]jobs.Result{{Ent: ent, Err: jobs.ErrTCPCheckFailed}})
+
+	state = stateMapper.Get(ent)
+	if state.Flags&components.StateIncidentOpen == 0 {
+		t.Error("expected incident for target-classed failure")
+	}
+	meta := ecs.NewMap[components.MonitorMeta](&world).Get(ent)
+	if meta.LastError != jobs.ErrTCPCheckFailed {
+		t.Errorf("LastError = %v, want ErrTCPCheckFailed", meta.LastError)
+	}
+}
+
+func TestBatchPulseResultSystem_HistoryRing(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	results := make(chan []jobs.Result, 1)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.ResultHistory](&world)
+	history := components.NewResultHistory(3)
+	ent := mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulsePending},
+		&components.MonitorMeta{},
+		&components.PulseConfig{UnhealthyThreshold: 10, HealthyThreshold: 1},
+		&history,
+	)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	historyMapper := ecs.NewMap[components.ResultHistory](&world)
+
+	// A success and a failure both land in the ring in arrival order.
+	resultSystem.ProcessBatch([]jobs.Result{{Ent: ent}})
+	stateMapper.Get(ent).Flags |= components.StatePulsePending
+	resultSystem.ProcessBatch([]jobs.Result{{Ent: ent, Err: jobs.ErrTCPCheckFailed}})
+
+	samples := historyMapper.Get(ent).Snapshot()
+	if len(samples) != 2 {
+		t.Fatalf("history length = %d, want 2", len(samples))
+	}
+	if !samples[0].Success || samples[0].Error != "" {
+		t.Errorf("first sample = %+v, want success with empty error", samples[0])
+	}
+	if samples[1].Success || samples[1].Error != jobs.ErrTCPCheckFailed.Error() {
+		t.Errorf("second sample = %+v, want failure with error string", samples[1])
+	}
+
+	// A result arriving while the monitor is not pulse-pending (the state
+	// DisableMonitor leaves behind) is rejected and must not append.
+	resultSystem.ProcessBatch([]jobs.Result{{Ent: ent}})
+	if got := historyMapper.Get(ent).Count; got != 2 {
+		t.Errorf("history count after non-pending result = %d, want 2", got)
+	}
+
+	// Infra-classed failures are exempt from monitor accounting and from
+	// the history ring alike.
+	stateMapper.Get(ent).Flags |= components.StatePulsePending
+	resultSystem.ProcessBatch([]jobs.Result{{
+		Ent:   ent,
+		Err:   jobs.ErrTCPCheckFailed,
+		Class: jobs.ErrorClassInternalInfrastructure,
+	}})
+	if got := historyMapper.Get(ent).Count; got != 2 {
+		t.Errorf("history count after infra-classed failure = %d, want 2", got)
+	}
+}
+
+// TestBatchPulseResultSystem_DegradedLatencyFiresYellow runs a real HTTP
+// check against a slow-but-healthy server: the checks all succeed, yet the
+// rolling latency EWMA crosses degraded_latency and the yellow code path
+// fires after enough consecutive slow checks.
+func TestBatchPulseResultSystem_DegradedLatencyFiresYellow(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond) // Up, just slow
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	results := make(chan []jobs.Result, 1)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+	reg := components.DefaultConfigRegistry()
+	codeConfig := components.CodeConfig{}
+	codeConfig.Configs[components.ColorYellow] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: true,
+		Config:   &schema.CodeNotificationLog{},
+	})
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.CodeConfig](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulsePending},
+		&components.MonitorMeta{Name: "slow-but-up"},
+		&components.PulseConfig{UnhealthyThreshold: 1, HealthyThreshold: 1, DegradedLatency: 5 * time.Millisecond},
+		&codeConfig,
+	)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+
+	host, isTLS, err := jobs.ExtractHostFromURL(server.URL)
+	if err != nil {
+		t.Fatalf("failed to extract host: %v", err)
+	}
+	job := &jobs.PulseHTTPJob{
+		URL:     server.URL,
+		Method:  "GET",
+		Host:    host,
+		IsTLS:   isTLS,
+		Timeout: 5 * time.Second,
+		Entity:  ent,
+	}
+
+	// The EWMA sits above the threshold from the first check on, so yellow
+	// fires once the streak reaches degradedLatencyK (3).
+	for i := 1; i <= 3; i++ {
+		result := job.Execute(context.Background())
+		if result.Err != nil {
+			t.Fatalf("check %d failed: %v", i, result.Err)
+		}
+		if result.Duration <= 5*time.Millisecond {
+			t.Fatalf("check %d Duration = %v, want > degraded_latency", i, result.Duration)
+		}
+		resultSystem.ProcessBatch([]jobs.Result{result})
+
+		state := stateMapper.Get(ent)
+		if i < 3 {
+			if state.Flags&components.StateCodeNeeded != 0 {
+				t.Fatalf("yellow fired after %d checks, want only after 3", i)
+			}
+			state.Flags |= components.StatePulsePending
+		}
+	}
+
+	state := stateMapper.Get(ent)
+	if state.Flags&components.StateCodeNeeded == 0 {
+		t.Fatal("expected CodeNeeded after sustained slow checks")
+	}
+	if state.PendingColor != components.ColorYellow {
+		t.Errorf("PendingColor = %v, want yellow", state.PendingColor)
+	}
+	meta := ecs.NewMap[components.MonitorMeta](&world).Get(ent)
+	if meta.LatencyEWMA <= 5*time.Millisecond {
+		t.Errorf("LatencyEWMA = %v, want above degraded_latency", meta.LatencyEWMA)
+	}
+	if meta.LatencyMax < meta.LatencyEWMA {
+		t.Errorf("LatencyMax = %v below EWMA %v", meta.LatencyMax, meta.LatencyEWMA)
+	}
+}
+
+// TestBatchPulseResultSystem_Invert covers all four combinations of invert
+// and probe outcome. The flip happens in the result system, so the result
+// itself still carries the wire outcome; only the monitor accounting and
+// the history ring see the inverted interpretation.
+func TestBatchPulseResultSystem_Invert(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name          string
+		invert        bool
+		wireErr       error
+		wantFailure   bool
+		wantLastError error
+	}{
+		{
+			name:        "plain monitor counts a success as healthy",
+			invert:      false,
+			wireErr:     nil,
+			wantFailure: false,
+		},
+		{
+			name:          "plain monitor counts a failure as unhealthy",
+			invert:        false,
+			wireErr:       jobs.ErrTCPCheckFailed,
+			wantFailure:   true,
+			wantLastError: jobs.ErrTCPCheckFailed,
+		},
+		{
+			name:          "inverted monitor counts a success as unhealthy",
+			invert:        true,
+			wireErr:       nil,
+			wantFailure:   true,
+			wantLastError: jobs.ErrUnexpectedResponse,
+		},
+		{
+			name:        "inverted monitor counts a refused connection as healthy",
+			invert:      true,
+			wireErr:     jobs.ErrTCPCheckFailed,
+			wantFailure: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			world := ecs.NewWorld()
+			registerComponents(&world)
+
+			results := make(chan []jobs.Result, 1)
+			resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+			history := components.NewResultHistory(3)
+			mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.ResultHistory](&world)
+			ent := mapper.NewEntity(
+				&components.MonitorState{Flags: components.StatePulsePending},
+				&components.MonitorMeta{},
+				&components.PulseConfig{UnhealthyThreshold: 3, HealthyThreshold: 1, Invert: tc.invert},
+				&history,
+			)
+
+			resultSystem.ProcessBatch([]jobs.Result{{Ent: ent, Err: tc.wireErr}})
+
+			state := ecs.NewMap[components.MonitorState](&world).Get(ent)
+			if got := state.PulseFailures == 1; got != tc.wantFailure {
+				t.Errorf("counted as failure = %v, want %v (PulseFailures=%d)", got, tc.wantFailure, state.PulseFailures)
+			}
+			meta := ecs.NewMap[components.MonitorMeta](&world).Get(ent)
+			if meta.LastError != tc.wantLastError {
+				t.Errorf("LastError = %v, want %v", meta.LastError, tc.wantLastError)
+			}
+			if !tc.wantFailure && meta.LastSuccessTime.IsZero() {
+				t.Error("LastSuccessTime not set for a healthy interpretation")
+			}
+
+			samples := ecs.NewMap[components.ResultHistory](&world).Get(ent).Snapshot()
+			if len(samples) != 1 {
+				t.Fatalf("history length = %d, want 1", len(samples))
+			}
+			if samples[0].Success != !tc.wantFailure {
+				t.Errorf("history Success = %v, want %v", samples[0].Success, !tc.wantFailure)
+			}
+		})
+	}
+}
+
+// TestBatchPulseResultSystem_InvertInfraFailureStaysExempt checks that a
+// failure classed as local infrastructure exhaustion does not count as
+// healthy for an inverted monitor: the check never reached the target, so
+// it says nothing about whether the endpoint is down.
+func TestBatchPulseResultSystem_InvertInfraFailureStaysExempt(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	results := make(chan []jobs.Result, 1)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulsePending},
+		&components.MonitorMeta{},
+		&components.PulseConfig{UnhealthyThreshold: 3, HealthyThreshold: 1, Invert: true},
+	)
+
+	resultSystem.ProcessBatch([]jobs.Result{{
+		Ent:   ent,
+		Err:   jobs.ErrTCPCheckFailed,
+		Class: jobs.ErrorClassInternalInfrastructure,
+	}})
+
+	state := ecs.NewMap[components.MonitorState](&world).Get(ent)
+	if meta := ecs.NewMap[components.MonitorMeta](&world).Get(ent); !meta.LastSuccessTime.IsZero() {
+		t.Error("infra-classed failure counted as healthy on an inverted monitor")
+	}
+	if state.PulseFailures != 0 {
+		t.Errorf("PulseFailures = %d, want 0", state.PulseFailures)
+	}
+	if state.Flags&components.StatePulsePending != 0 {
+		t.Error("expected pending flag cleared")
+	}
+}
+
+// TestBatchPulseResultSystem_AttemptThreshold exercises the attempt-volume
+// escalation path: with retries configured, a hard-down target burns through
+// attempts faster than failed checks accumulate, and the attempt threshold
+// lets it escalate early.
+func TestBatchPulseResultSystem_AttemptThreshold(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name             string
+		attemptThreshold int
+		results          []jobs.Result // applied in order; Err nil means success
+		wantIncident     bool
+		wantStreak       int
+	}{
+		{
+			// Without the attempt threshold, four attempts per check do not
+			// accelerate past the five-check threshold.
+			name:             "disabled threshold keeps check semantics",
+			attemptThreshold: 0,
+			results: []jobs.Result{
+				{Err: jobs.ErrTCPCheckFailed, Payload: jobs.Payload{Attempts: 4}},
+				{Err: jobs.ErrTCPCheckFailed, Payload: jobs.Payload{Attempts: 4}},
+			},
+			wantIncident: false,
+			wantStreak:   8,
+		},
+		{
+			// Two checks of four attempts each cross the attempt threshold
+			// well before five failed checks accumulate.
+			name:             "attempt volume escalates early",
+			attemptThreshold: 8,
+			results: []jobs.Result{
+				{Err: jobs.ErrTCPCheckFailed, Payload: jobs.Payload{Attempts: 4}},
+				{Err: jobs.ErrTCPCheckFailed, Payload: jobs.Payload{Attempts: 4}},
+			},
+			wantIncident: true,
+			wantStreak:   0, // reset by escalation
+		},
+		{
+			// Drivers that do not report attempts count each failed check as
+			// one attempt.
+			name:             "unreported attempts count as one",
+			attemptThreshold: 8,
+			results: []jobs.Result{
+				{Err: jobs.ErrTCPCheckFailed},
+				{Err: jobs.ErrTCPCheckFailed},
+			},
+			wantIncident: false,
+			wantStreak:   2,
+		},
+		{
+			// A success resets the streak, so the attempt volume only counts
+			// consecutive failures.
+			name:             "success resets the streak",
+			attemptThreshold: 8,
+			results: []jobs.Result{
+				{Err: jobs.ErrTCPCheckFailed, Payload: jobs.Payload{Attempts: 4}},
+				{},
+				{Err: jobs.ErrTCPCheckFailed, Payload: jobs.Payload{Attempts: 4}},
+			},
+			wantIncident: false,
+			wantStreak:   4,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			world := ecs.NewWorld()
+			registerComponents(&world)
+
+			results := make(chan []jobs.Result, 1)
+			resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+			mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+			ent := mapper.NewEntity(
+				&components.MonitorState{Flags: components.StatePulsePending},
+				&components.MonitorMeta{},
+				&components.PulseConfig{
+					UnhealthyThreshold:        5,
+					HealthyThreshold:          1,
+					UnhealthyAttemptThreshold: tc.attemptThreshold,
+				},
+			)
+			stateMapper := ecs.NewMap[components.MonitorState](&world)
+
+			for _, result := range tc.results {
+				result.Ent = ent
+				stateMapper.Get(ent).Flags |= components.StatePulsePending
+				resultSystem.ProcessBatch([]jobs.Result{result})
+			}
+
+			state := stateMapper.Get(ent)
+			if got := state.Flags&components.StateIncidentOpen != 0; got != tc.wantIncident {
+				t.Errorf("incident open = %v, want %v", got, tc.wantIncident)
+			}
+			if state.StreakFailedAttempts != tc.wantStreak {
+				t.Errorf("StreakFailedAttempts = %d, want %d", state.StreakFailedAttempts, tc.wantStreak)
+			}
+		})
+	}
+}
+
+func TestBatchPulseResultSystem_RateLimitedExempt(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	results := make(chan []jobs.Result, 1)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{
+			Flags:         components.StatePulsePending,
+			NextCheckTime: time.Now().Add(time.Second),
+		},
+		&components.MonitorMeta{},
+		&components.PulseConfig{UnhealthyThreshold: 1, HealthyThreshold: 1},
+	)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+
+	payload := jobs.GetPulseHTTPPayload()
+	payload.SetExtra("rate_limited", true)
+	payload.SetExtra("retry_after_seconds", 30)
+	resultSystem.ProcessBatch([]jobs.Result{{Ent: ent, Err: jobs.ErrRateLimited, Payload: payload}})
+
+	// The monitor stays healthy: no failure increment, no incident, pending
+	// cleared - but the next check honors the Retry-After.
+	state := stateMapper.Get(ent)
+	if state.PulseFailures != 0 || state.ConsecutiveFailures != 0 {
+		t.Errorf("failures = %d/%d, want 0/0", state.PulseFailures, state.ConsecutiveFailures)
+	}
+	if state.Flags&components.StateIncidentOpen != 0 {
+		t.Error("expected no incident for rate-limited check")
+	}
+	if state.Flags&components.StatePulsePending != 0 {
+		t.Error("expected pending flag cleared")
+	}
+	if wait := time.Until(state.NextCheckTime); wait < 25*time.Second {
+		t.Errorf("NextCheckTime only %v away, want pushed out by ~30s", wait.Round(time.Second))
+	}
+}
+
+func TestBatchPulseResultSystem_RateLimitedCountedWhenOptedIn(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	results := make(chan []jobs.Result, 1)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulsePending},
+		&components.MonitorMeta{},
+		&components.PulseConfig{
+			UnhealthyThreshold:      1,
+			HealthyThreshold:        1,
+			CountRateLimitAsFailure: true,
+		},
+	)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+
+	payload := jobs.GetPulseHTTPPayload()
+	payload.SetExtra("rate_limited", true)
+	payload.SetExtra("retry_after_seconds", 10)
+	resultSystem.ProcessBatch([]jobs.Result{{Ent: ent, Err: jobs.ErrRateLimited, Payload: payload}})
+
+	state := stateMapper.Get(ent)
+	if state.ConsecutiveFailures != 1 {
+		t.Errorf("ConsecutiveFailures = %d, want 1", state.ConsecutiveFailures)
+	}
+	if state.Flags&components.StateIncidentOpen == 0 {
+		t.Error("expected incident when rate limits count as failures")
+	}
+	// Retry-After is still honored even when the failure counts.
+	if wait := time.Until(state.NextCheckTime); wait < 5*time.Second {
+		t.Errorf("NextCheckTime only %v away, want pushed out by ~10s", wait.Round(time.Second))
+	}
+}
+
+// TestBatchPulseResultSystem_PanicAutoDisable drives consecutive panicked
+// results through the system and asserts the streak accounting, the reset on
+// a non-panic result, and the command-buffered disable with its directly
+// enqueued crash red once the threshold is reached.
+func TestBatchPulseResultSystem_PanicAutoDisable(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	reg := components.DefaultConfigRegistry()
+	codeConfig := components.CodeConfig{}
+	codeConfig.Configs[components.ColorRed] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: true,
+		Config:   &schema.CodeNotificationLog{},
+	})
+
+	results := make(chan []jobs.Result, 1)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+	commandSystem := NewCommandSystem(DefaultCommandBuffer)
+	mockQ := newMockQueue(10)
+	resultSystem.SetPanicAutoDisable(commandSystem, mockQ, 3)
+
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.CodeConfig](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulsePending},
+		&components.MonitorMeta{Name: "crashy"},
+		&components.PulseConfig{UnhealthyThreshold: 10, HealthyThreshold: 1},
+		&codeConfig,
+	)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	metaMapper := ecs.NewMap[components.MonitorMeta](&world)
+
+	panicResult := jobs.Result{Ent: ent, Err: fmt.Errorf("%w: %s", jobs.ErrJobPanicked, "nil deref")}
+	deliver := func(res jobs.Result) {
+		stateMapper.Get(ent).Flags |= components.StatePulsePending
+		resultSystem.ProcessBatch([]jobs.Result{res})
+	}
+
+	// A panic below the threshold counts as a plain failure; any non-panic
+	// result resets the streak.
+	deliver(panicResult)
+	deliver(panicResult)
+	if got := metaMapper.Get(ent).PanicStreak; got != 2 {
+		t.Fatalf("PanicStreak after two panics = %d, want 2", got)
+	}
+	deliver(jobs.Result{Ent: ent, Err: jobs.ErrTCPCheckFailed})
+	if got := metaMapper.Get(ent).PanicStreak; got != 0 {
+		t.Fatalf("PanicStreak after non-panic failure = %d, want 0", got)
+	}
+
+	// Three in a row reach the threshold. The disable is deferred to the
+	// command buffer, so nothing changes until CommandSystem runs.
+	deliver(panicResult)
+	deliver(panicResult)
+	deliver(panicResult)
+	disabledMapper := ecs.NewMap1[components.Disabled](&world)
+	if disabledMapper.HasAll(ent) {
+		t.Fatal("entity disabled before the command buffer ran")
+	}
+	commandSystem.Update(&world)
+
+	if !disabledMapper.HasAll(ent) {
+		t.Fatal("expected entity disabled after three consecutive panics")
+	}
+	state := stateMapper.Get(ent)
+	if state.Flags&(components.StatePulsePending|components.StateInterventionPending) != 0 {
+		t.Error("expected pending work dropped on disable")
+	}
+	if state.Flags&components.StateCodePending == 0 {
+		t.Error("expected CodePending set for the crash red's own result")
+	}
+	if len(mockQ.enqueued) != 1 {
+		t.Fatalf("enqueued %d code jobs, want 1 crash red", len(mockQ.enqueued))
+	}
+	logJob, ok := mockQ.enqueued[0].(*jobs.CodeLogJob)
+	if !ok {
+		t.Fatalf("enqueued job is %T, want *jobs.CodeLogJob", mockQ.enqueued[0])
+	}
+	if logJob.Color != "red" || logJob.Status != "CRASHED" {
+		t.Errorf("crash code = color %q status %q, want red/CRASHED", logJob.Color, logJob.Status)
+	}
+
+	// A second threshold crossing must not stack another disable.
+	commandSystem.Update(&world)
+	if len(mockQ.enqueued) != 1 {
+		t.Errorf("enqueued %d code jobs after re-run, want still 1", len(mockQ.enqueued))
+	}
+}
@@ -0,0 +1,312 @@
+package systems
+
+import (
+	"sort"
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/queue"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// DefaultMaxSchedulePerTick limits how many pulse checks can be scheduled per tick.
+// This prevents CPU spikes from scheduling millions of checks simultaneously.
+// At 100 TPS, 10,000/tick = 1M checks/second theoretical max throughput.
+const DefaultMaxSchedulePerTick = 10000
+
+// Default queue saturation thresholds for backpressure. Between the soft and
+// hard thresholds only the most-overdue monitors are scheduled, up to the
+// queue's remaining capacity; at or above the hard threshold the tick is
+// skipped entirely. Scheduling work that cannot be enqueued only churns
+// entities through pending and recovery.
+const (
+	DefaultSoftSaturation = 0.80
+	DefaultHardSaturation = 0.95
+)
+
+// saturationLogInterval rate-limits backpressure logging so a sustained
+// overload does not flood the log at tick frequency.
+const saturationLogInterval = 5 * time.Second
+
+// maxFirstCheckSpread caps the window first checks are jittered across.
+// Without it a million monitors loaded together all become due on the very
+// first tick, and the resulting backlog manufactures timeout yellows while
+// the worker pool is still ramping up. The cap keeps monitors with long
+// intervals from waiting a full interval for their first check.
+const maxFirstCheckSpread = time.Minute
+
+// BatchPulseScheduleSystem schedules pulse checks for entities that are due.
+// It queries for monitors that are not disabled, not already pending a pulse check,
+// and whose interval has passed since the last check.
+// This system is a critical part of the monitoring pipeline, ensuring that checks
+// are scheduled in a timely and efficient manner.
+type BatchPulseScheduleSystem struct {
+	world       *ecs.World
+	logger      Logger
+	stateLogger *StateLogger
+
+	// Filter for entities that are candidates for a pulse check.
+	filter *ecs.Filter2[components.MonitorState, components.PulseConfig]
+
+	// maxSchedulePerTick limits how many monitors can be scheduled per tick
+	// to prevent CPU spikes and spread a load more evenly.
+	maxSchedulePerTick int
+
+	// queue, when set, provides the backpressure signal: its Saturation()
+	// is consulted at the top of every Update. Nil disables backpressure.
+	queue             queue.Queue
+	softSaturation    float64
+	hardSaturation    float64
+	skippedTicks      int64
+	lastSaturationLog time.Time
+
+	now func() time.Time // overridable for fake-clock tests
+}
+
+// NewBatchPulseScheduleSystem creates a new BatchPulseScheduleSystem.
+func NewBatchPulseScheduleSystem(world *ecs.World, logger Logger, stateLogger *StateLogger) *BatchPulseScheduleSystem {
+	return &BatchPulseScheduleSystem{
+		world:       world,
+		logger:      logger,
+		stateLogger: stateLogger,
+		// Heartbeat monitors are push-based and scheduled by the HeartbeatSystem;
+		// high-frequency monitors are scheduled by the HighFrequencyPulseSystem;
+		// probe followers ride their group leader's checks.
+		filter: ecs.NewFilter2[components.MonitorState, components.PulseConfig](world).
+			Without(ecs.C[components.Disabled](), ecs.C[components.Heartbeat](), ecs.C[components.HighFrequency](), ecs.C[components.ProbeFollower]()),
+		maxSchedulePerTick: DefaultMaxSchedulePerTick,
+		softSaturation:     DefaultSoftSaturation,
+		hardSaturation:     DefaultHardSaturation,
+		now:                time.Now,
+	}
+}
+
+// SetClock routes the system's scheduling decisions through c. A nil clock
+// is ignored.
+func (s *BatchPulseScheduleSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+// SetQueue attaches the pulse queue whose saturation gates scheduling.
+// A nil queue disables backpressure.
+func (s *BatchPulseScheduleSystem) SetQueue(q queue.Queue) {
+	s.queue = q
+}
+
+// SetSaturationThresholds overrides the backpressure thresholds. Invalid
+// values (outside 0 < soft < hard <= 1) are ignored.
+func (s *BatchPulseScheduleSystem) SetSaturationThresholds(soft, hard float64) {
+	if soft <= 0 || soft >= hard || hard > 1 {
+		return
+	}
+	s.softSaturation = soft
+	s.hardSaturation = hard
+}
+
+// SkippedTicks reports how many ticks were skipped because the queue was at
+// or above the hard saturation threshold.
+func (s *BatchPulseScheduleSystem) SkippedTicks() int64 {
+	return s.skippedTicks
+}
+
+// SetMaxSchedulePerTick sets the maximum number of monitors that can be scheduled per tick.
+func (s *BatchPulseScheduleSystem) SetMaxSchedulePerTick(max int) {
+	if max > 0 {
+		s.maxSchedulePerTick = max
+	}
+}
+
+func (s *BatchPulseScheduleSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+// Update finds and schedules all monitors that are due for a pulse check.
+// It limits scheduling to maxSchedulePerTick to spread a load across ticks.
+// When a queue is attached, its saturation gates scheduling: at the hard
+// threshold the tick is skipped, and between the soft and hard thresholds
+// only the most-overdue monitors are scheduled up to the remaining capacity.
+func (s *BatchPulseScheduleSystem) Update(_ *ecs.World) {
+	start := time.Now()
+
+	maxSchedule := s.maxSchedulePerTick
+	if maxSchedule <= 0 {
+		maxSchedule = DefaultMaxSchedulePerTick
+	}
+
+	if s.queue != nil {
+		sat := s.queue.Saturation()
+		if sat >= s.hardSaturation {
+			s.skippedTicks++
+			s.logSaturation("Pulse queue saturated; skipping scheduling this tick", sat)
+			return
+		}
+		if sat >= s.softSaturation {
+			stats := s.queue.Stats()
+			free := stats.Capacity - stats.QueueDepth
+			if free <= 0 {
+				s.skippedTicks++
+				s.logSaturation("Pulse queue has no remaining capacity; skipping scheduling this tick", sat)
+				return
+			}
+			if free < maxSchedule {
+				maxSchedule = free
+			}
+			s.logSaturation("Pulse queue near saturation; scheduling only most-overdue monitors", sat)
+			s.scheduleMostOverdue(maxSchedule, start)
+			return
+		}
+	}
+
+	query := s.filter.Query()
+	var scheduledCount int
+
+	now := s.now()
+
+	// Track if we break early (need to close a query manually)
+	brokeEarly := false
+
+	for query.Next() {
+		// Rate limit: stop if we've scheduled enough this tick
+		if scheduledCount >= maxSchedule {
+			brokeEarly = true
+			break
+		}
+
+		entity := query.Entity()
+		state, config := query.Get()
+
+		flags := state.Flags
+
+		// Disabled entities are excluded at the filter level via Without(Disabled)
+		if (flags&components.StatePulseNeeded != 0) || (flags&components.StatePulsePending != 0) {
+			continue
+		}
+
+		var due bool
+		if flags&components.StatePulseFirstCheck != 0 {
+			// First checks are jittered across a spread window keyed on the
+			// entity, measured from creation time (the mapper seeds
+			// NextCheckTime with it), so a bulk load warms up gradually.
+			// States built without a creation time stay immediately due.
+			due = now.Sub(state.NextCheckTime) >= firstCheckJitter(entity, config.Interval)
+		} else {
+			due = now.Sub(state.LastPulseCheckTime) >= config.Interval
+		}
+		if !due {
+			continue
+		}
+
+		oldState := *state
+		state.Flags |= components.StatePulseNeeded
+		state.Flags &^= components.StatePulseFirstCheck
+		s.stateLogger.LogTransition(entity, oldState, *state)
+		scheduledCount++
+	}
+
+	// Only close a query if we broke out early; natural completion auto-closes
+	if brokeEarly {
+		query.Close()
+	}
+
+	if scheduledCount > 0 {
+		dur := time.Since(start)
+		LogSystemPerformance(s.logger, "BatchPulseScheduleSystem", scheduledCount, dur)
+	}
+}
+
+// firstCheckJitter is the deterministic per-entity delay before a monitor's
+// first check becomes due, spread uniformly across the smaller of the
+// monitor's interval and maxFirstCheckSpread. Deterministic hashing keeps
+// the phase stable across ticks without storing anything per entity.
+func firstCheckJitter(ent ecs.Entity, interval time.Duration) time.Duration {
+	window := interval
+	if window > maxFirstCheckSpread {
+		window = maxFirstCheckSpread
+	}
+	if window <= 0 {
+		return 0
+	}
+	// Knuth multiplicative hash of the entity ID.
+	h := uint64(ent.ID()) * 2654435761
+	return time.Duration(h % uint64(window))
+}
+
+// overdueMonitor is a scheduling candidate collected under backpressure.
+type overdueMonitor struct {
+	ent     ecs.Entity
+	state   *components.MonitorState
+	overdue time.Duration
+}
+
+// scheduleMostOverdue scans all due monitors and schedules only the budget
+// most overdue ones. Unlike the normal path it cannot stop early: lateness
+// is only known after seeing every candidate.
+func (s *BatchPulseScheduleSystem) scheduleMostOverdue(budget int, start time.Time) {
+	now := s.now()
+	candidates := make([]overdueMonitor, 0, budget)
+
+	query := s.filter.Query()
+	for query.Next() {
+		entity := query.Entity()
+		state, config := query.Get()
+
+		flags := state.Flags
+		if (flags&components.StatePulseNeeded != 0) || (flags&components.StatePulsePending != 0) {
+			continue
+		}
+
+		sinceLast := now.Sub(state.LastPulseCheckTime)
+		overdue := sinceLast - config.Interval
+		if flags&components.StatePulseFirstCheck != 0 {
+			// A first check has no previous check time to be late against;
+			// treat the full age as lateness so new monitors are not starved.
+			overdue = sinceLast
+		} else if overdue < 0 {
+			continue
+		}
+		candidates = append(candidates, overdueMonitor{ent: entity, state: state, overdue: overdue})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].overdue > candidates[j].overdue
+	})
+	if len(candidates) > budget {
+		candidates = candidates[:budget]
+	}
+
+	for _, c := range candidates {
+		oldState := *c.state
+		c.state.Flags |= components.StatePulseNeeded
+		c.state.Flags &^= components.StatePulseFirstCheck
+		s.stateLogger.LogTransition(c.ent, oldState, *c.state)
+	}
+
+	if len(candidates) > 0 {
+		LogSystemPerformance(s.logger, "BatchPulseScheduleSystem", len(candidates), time.Since(start))
+	}
+}
+
+// logSaturation logs a backpressure decision, rate limited to one line per
+// saturationLogInterval.
+func (s *BatchPulseScheduleSystem) logSaturation(msg string, sat float64) {
+	if time.Since(s.lastSaturationLog) < saturationLogInterval {
+		return
+	}
+	s.lastSaturationLog = time.Now()
+	s.logger.Warnw(msg,
+		"saturation", sat,
+		"soft_threshold", s.softSaturation,
+		"hard_threshold", s.hardSaturation,
+		"skipped_ticks", s.skippedTicks)
+}
+
+// Finalize is a no-op for this system.
+func (s *BatchPulseScheduleSystem) Finalize(_ *ecs.World) {
+	// Nothing to clean up
+}
@@ -0,0 +1,591 @@
+// Package systems provide ECS systems for processing monitor state transitions
+// and job execution coordination.
+//
+// Systems in this package implement the ark System interface and are registered
+// with the controller to process entities in batches. Each system handles a
+// specific aspect of monitor lifecycle management.
+//
+// # System Types
+//
+// Schedule Systems:
+//   - BatchPulseScheduleSystem: Determines when pulse checks are needed based on intervals
+//
+// Dispatch Systems:
+//   - BatchPulseSystem: Enqueues pulse jobs for execution
+//   - BatchInterventionSystem: Enqueues intervention jobs when thresholds exceeded
+//   - BatchCodeSystem: Enqueues code alert jobs
+//
+// Result Systems:
+//   - BatchPulseResultSystem: Processes pulse job results and updates monitor state
+//   - BatchInterventionResultSystem: Processes intervention job results
+//   - BatchCodeResultSystem: Processes code alert job results
+//
+// # Batch Processing
+//
+// All systems use batch processing to maximize throughput:
+//   - Systems query entities in batches using ECS filters
+//   - Jobs are enqueued in batches to reduce queue contention
+//   - Entity state updates are batched for cache efficiency
+//
+// # Performance
+//
+// Systems use object pooling (sync.Pool) to reduce allocations:
+//   - Job slices are pooled for batch enqueue operations
+//   - Entity slices are pooled for batch state updates
+package systems
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/queue"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type scheduledPulse struct {
+	ent      ecs.Entity
+	state    *components.MonitorState
+	interval time.Duration
+	oldState components.MonitorState
+	// traceCtx is the root span context opened for a traced dispatch; nil
+	// for untraced checks. Ended here only if the enqueue is rejected.
+	traceCtx context.Context
+}
+
+// BatchPulseSystem processes entities that need a pulse check.
+//
+// BatchPulseSystem identifies entities with the StatePulseNeeded flag set,
+// enqueues the corresponding pulse job to the pulse queue, and transitions
+// the entity state to StatePulsePending.
+//
+// The system processes entities in batches to maximize throughput and uses
+// object pooling to reduce allocations. It respects queue capacity limits
+// and can be configured with a maximum dispatch rate.
+type BatchPulseSystem struct {
+	queue              queue.Queue
+	logger             Logger
+	stateLogger        *StateLogger
+	world              *ecs.World
+	filter             *ecs.Filter4[components.MonitorState, components.JobStorage, components.PulseConfig, components.Shard]
+	monitorStateMapper *ecs.Map[components.MonitorState]
+	metaMapper         *ecs.Map[components.MonitorMeta]
+	jobPool            *sync.Pool
+	faults             *FaultInjector
+	batchSize          int
+	maxDispatch        int
+	shardSlots         int
+	currentShard       int
+	now                func() time.Time // overridable for fake-clock tests
+	gate               *StandbyGate     // nil unless HA mode is on; closed while standby
+
+	// tracer, when set, opens a root span per dispatch for monitors flagged
+	// trace: true or sampled at traceSampleRate; nil disables all tracing.
+	tracer          trace.Tracer
+	traceSampleRate float64
+
+	// Time-wheel mode (see EnableTimeWheel): instead of scanning one shard
+	// per tick, due monitors are popped from a bucketed wheel and the full
+	// filter scan runs only as a periodic reconciliation pass.
+	wheel             *pulseTimeWheel
+	wheelReconcileAt  time.Time
+	jobStorageMapper  *ecs.Map[components.JobStorage]
+	pulseConfigMapper *ecs.Map[components.PulseConfig]
+	disabledMapper    *ecs.Map1[components.Disabled]
+	followerMapper    *ecs.Map1[components.ProbeFollower]
+}
+
+// Time-wheel mode tuning. Reconciliation is the old full scan at a far lower
+// frequency: it seeds monitors the wheel does not know yet and re-buckets
+// monitors whose next check moved earlier (reload, admin trigger). The retry
+// delay is how soon a popped monitor that could not dispatch (job pending,
+// queue full) is checked again.
+const (
+	wheelReconcileInterval = time.Second
+	wheelRetryDelay        = 2 * defaultWheelTick
+)
+
+// NewBatchPulseSystem creates a new BatchPulseSystem.
+func NewBatchPulseSystem(world *ecs.World, q queue.Queue, batchSize int, logger Logger, stateLogger *StateLogger, shardSlots int) *BatchPulseSystem {
+	if shardSlots <= 0 {
+		shardSlots = components.DefaultShardSlots
+	}
+	return &BatchPulseSystem{
+		world:       world,
+		queue:       q,
+		logger:      logger,
+		stateLogger: stateLogger,
+		batchSize:   batchSize,
+		shardSlots:  shardSlots,
+		// Heartbeat monitors are push-based and handled by the HeartbeatSystem;
+		// high-frequency monitors are handled by the HighFrequencyPulseSystem;
+		// probe followers ride their group leader's checks.
+		filter: ecs.NewFilter4[components.MonitorState, components.JobStorage, components.PulseConfig, components.Shard](world).
+			Without(ecs.C[components.Disabled](), ecs.C[components.Heartbeat](), ecs.C[components.HighFrequency](), ecs.C[components.ProbeFollower]()),
+		monitorStateMapper: ecs.NewMap[components.MonitorState](world),
+		metaMapper:         ecs.NewMap[components.MonitorMeta](world),
+		now:                time.Now,
+		jobPool: &sync.Pool{
+			New: func() interface{} {
+				s := make([]interface{}, 0, batchSize)
+				return &s
+			},
+		},
+	}
+}
+
+func (s *BatchPulseSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+func (s *BatchPulseSystem) SetMaxDispatch(n int) {
+	s.maxDispatch = n
+}
+
+// SetQueue replaces the dispatch queue. Only safe before the scheduler
+// starts: the update loop reads the field without synchronization.
+func (s *BatchPulseSystem) SetQueue(q queue.Queue) {
+	s.queue = q
+}
+
+// SetClock routes the system's scheduling decisions through c. A nil clock
+// is ignored; job execution keeps real time for network deadlines.
+func (s *BatchPulseSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+// SetStandbyGate makes dispatch honor the HA leadership gate. A nil gate
+// (the default) means always dispatch.
+func (s *BatchPulseSystem) SetStandbyGate(g *StandbyGate) {
+	s.gate = g
+}
+
+// EnableTimeWheel switches Update from the per-shard filter scan to the
+// bucketed time-wheel scheduler, so per-tick cost tracks the number of due
+// monitors rather than the total entity count. Call after SetClock and
+// before the first Update.
+func (s *BatchPulseSystem) EnableTimeWheel() {
+	s.wheel = newPulseTimeWheel(defaultWheelBuckets, defaultWheelTick, s.now())
+	s.jobStorageMapper = ecs.NewMap[components.JobStorage](s.world)
+	s.pulseConfigMapper = ecs.NewMap[components.PulseConfig](s.world)
+	s.disabledMapper = ecs.NewMap1[components.Disabled](s.world)
+	s.followerMapper = ecs.NewMap1[components.ProbeFollower](s.world)
+}
+
+// SetFaultInjector enables soak-test fault injection on the enqueue path.
+func (s *BatchPulseSystem) SetFaultInjector(f *FaultInjector) {
+	s.faults = f
+}
+
+// SetTracer enables per-dispatch tracing: monitors flagged trace: true are
+// always traced, others at sampleRate. A nil tracer (the default) keeps the
+// dispatch path free of tracing work.
+func (s *BatchPulseSystem) SetTracer(t trace.Tracer, sampleRate float64) {
+	s.tracer = t
+	s.traceSampleRate = sampleRate
+}
+
+// maybeStartTrace decides whether this dispatch is traced and, if so, opens
+// the root span and hands its context to the job. Untraced dispatches clear
+// any context left from an earlier traced cycle. Only called with a tracer.
+func (s *BatchPulseSystem) maybeStartTrace(ent ecs.Entity, cfg *components.PulseConfig, job interface{}, now time.Time) context.Context {
+	carrier, ok := job.(jobs.TraceCarrier)
+	if !ok {
+		return nil
+	}
+	if !cfg.Trace && (s.traceSampleRate <= 0 || rand.Float64() >= s.traceSampleRate) {
+		carrier.SetTraceContext(nil)
+		return nil
+	}
+	attrs := []attribute.KeyValue{attribute.String("pulse.type", cfg.Type)}
+	if meta := s.metaMapper.Get(ent); meta != nil {
+		attrs = append(attrs, attribute.String("monitor.name", meta.Name))
+	}
+	ctx, _ := s.tracer.Start(context.Background(), "pulse.check",
+		trace.WithTimestamp(now), trace.WithAttributes(attrs...))
+	carrier.SetTraceContext(ctx)
+	return ctx
+}
+
+// Update finds and processes all monitors that need a pulse check.
+func (s *BatchPulseSystem) Update(_ *ecs.World) {
+	if s.gate != nil && s.gate.Standby() {
+		return
+	}
+	if s.wheel != nil {
+		s.updateFromWheel()
+		return
+	}
+	startTime := time.Now()
+	stats := s.queue.Stats()
+	if stats.Capacity > 0 && stats.QueueDepth >= int(float64(stats.Capacity)*0.9) {
+		s.logger.Debugw("Pulse queue saturated", "depth", stats.QueueDepth, "capacity", stats.Capacity)
+	}
+
+	// Process a single shard per tick to avoid O(N) scans.
+	shardToProcess := s.currentShard
+	s.currentShard = (s.currentShard + 1) % s.shardSlots
+
+	var tokens int
+	if stats.Capacity <= 0 {
+		// Sentinel capacity <= 0 signals an unbounded queue (Workings implementation).
+		tokens = s.batchSize
+		if tokens <= 0 {
+			tokens = 1
+		}
+	} else {
+		free := stats.Capacity - stats.QueueDepth
+		if free <= 0 {
+			return
+		}
+		tokens = int(float64(free) * 0.8)
+		if tokens <= 0 {
+			tokens = free
+		}
+	}
+	if s.maxDispatch > 0 && tokens > s.maxDispatch {
+		tokens = s.maxDispatch
+	}
+
+	earlyExit := false
+
+	query := s.filter.Query()
+
+	jobsPtr := s.jobPool.Get().(*[]interface{})
+	jobsToQueue := (*jobsPtr)[:0]
+	scheduled := make([]scheduledPulse, 0, tokens)
+	processedCount := 0
+
+	defer func() {
+		s.jobPool.Put(jobsPtr)
+	}()
+
+	now := s.now()
+	for query.Next() {
+		ent := query.Entity()
+		state, jobStorage, pulseCfg, shard := query.Get()
+
+		if shard == nil || int(shard.ID)%s.shardSlots != shardToProcess {
+			continue
+		}
+
+		// Skip if a pulse job is already pending.
+		if state.Flags&components.StatePulsePending != 0 {
+			continue
+		}
+
+		// Guard against missing jobs.
+		if jobStorage == nil || jobStorage.PulseJob == nil || jobStorage.PulseJob.IsNil() {
+			s.logger.Warnw("Entity has pulse work but no valid PulseJob", "entity_id", ent.ID())
+			continue
+		}
+
+		interval := pulseCfg.Interval
+		if interval <= 0 {
+			interval = time.Second
+		}
+
+		// Determine if pulse is due: first check or next check time reached.
+		due := state.Flags&components.StatePulseFirstCheck != 0
+		if !due {
+			if state.NextCheckTime.IsZero() || !state.NextCheckTime.After(now) {
+				due = true
+			}
+		}
+		if !due {
+			continue
+		}
+
+		var traceCtx context.Context
+		if s.tracer != nil {
+			traceCtx = s.maybeStartTrace(ent, pulseCfg, jobStorage.PulseJob, now)
+		}
+
+		jobsToQueue = append(jobsToQueue, jobStorage.PulseJob)
+		scheduled = append(scheduled, scheduledPulse{
+			ent:      ent,
+			state:    state,
+			interval: interval,
+			oldState: *state,
+			traceCtx: traceCtx,
+		})
+
+		if len(jobsToQueue) >= tokens {
+			s.processBatch(&jobsToQueue, &scheduled)
+			processedCount += len(jobsToQueue)
+			jobsToQueue = jobsToQueue[:0]
+			scheduled = scheduled[:0]
+			earlyExit = true
+			break
+		}
+	}
+
+	// Process any remaining entities
+	if earlyExit {
+		query.Close()
+	}
+
+	if len(jobsToQueue) > 0 {
+		s.processBatch(&jobsToQueue, &scheduled)
+		processedCount += len(jobsToQueue)
+	}
+
+	if processedCount > 0 {
+		dur := time.Since(startTime)
+		LogSystemPerformance(s.logger, "BatchPulseSystem", processedCount, dur)
+	}
+
+}
+
+// updateFromWheel is the time-wheel variant of Update: it pops due entities
+// from the wheel and re-verifies their state through mappers before
+// dispatch, since components may have changed since they were bucketed.
+func (s *BatchPulseSystem) updateFromWheel() {
+	startTime := time.Now()
+	now := s.now()
+
+	if !now.Before(s.wheelReconcileAt) {
+		s.reconcileWheel(now)
+		s.wheelReconcileAt = now.Add(wheelReconcileInterval)
+	}
+
+	dueEnts := s.wheel.Due(now)
+	if len(dueEnts) == 0 {
+		return
+	}
+
+	stats := s.queue.Stats()
+	var tokens int
+	if stats.Capacity <= 0 {
+		tokens = s.batchSize
+		if tokens <= 0 {
+			tokens = 1
+		}
+	} else {
+		free := stats.Capacity - stats.QueueDepth
+		if free <= 0 {
+			// No room to dispatch; keep the due monitors in play.
+			for _, ent := range dueEnts {
+				s.wheel.Schedule(ent, now.Add(wheelRetryDelay))
+			}
+			return
+		}
+		tokens = int(float64(free) * 0.8)
+		if tokens <= 0 {
+			tokens = free
+		}
+	}
+	if s.maxDispatch > 0 && tokens > s.maxDispatch {
+		tokens = s.maxDispatch
+	}
+
+	jobsPtr := s.jobPool.Get().(*[]interface{})
+	jobsToQueue := (*jobsPtr)[:0]
+	scheduled := make([]scheduledPulse, 0, tokens)
+	processedCount := 0
+
+	defer func() {
+		s.jobPool.Put(jobsPtr)
+	}()
+
+	for i, ent := range dueEnts {
+		// Popped entities may have died, been disabled, or become probe
+		// followers since bucketing; reconciliation re-seeds them if they
+		// come back.
+		if !s.world.Alive(ent) || s.disabledMapper.HasAll(ent) || s.followerMapper.HasAll(ent) {
+			continue
+		}
+		state := s.monitorStateMapper.Get(ent)
+		if state == nil {
+			continue
+		}
+		if state.Flags&components.StatePulsePending != 0 {
+			// Result still in flight; check again shortly instead of
+			// waiting out a full interval.
+			s.wheel.Schedule(ent, now.Add(wheelRetryDelay))
+			continue
+		}
+		// The next check may have been pushed out since bucketing
+		// (recovery, manual reschedule); follow the new time.
+		if state.Flags&components.StatePulseFirstCheck == 0 &&
+			!state.NextCheckTime.IsZero() && state.NextCheckTime.After(now) {
+			s.wheel.Schedule(ent, state.NextCheckTime)
+			continue
+		}
+		jobStorage := s.jobStorageMapper.Get(ent)
+		if jobStorage == nil || jobStorage.PulseJob == nil || jobStorage.PulseJob.IsNil() {
+			s.logger.Warnw("Entity has pulse work but no valid PulseJob", "entity_id", ent.ID())
+			continue
+		}
+		interval := time.Second
+		pulseCfg := s.pulseConfigMapper.Get(ent)
+		if pulseCfg != nil && pulseCfg.Interval > 0 {
+			interval = pulseCfg.Interval
+		}
+
+		var traceCtx context.Context
+		if s.tracer != nil && pulseCfg != nil {
+			traceCtx = s.maybeStartTrace(ent, pulseCfg, jobStorage.PulseJob, now)
+		}
+
+		jobsToQueue = append(jobsToQueue, jobStorage.PulseJob)
+		scheduled = append(scheduled, scheduledPulse{
+			ent:      ent,
+			state:    state,
+			interval: interval,
+			oldState: *state,
+			traceCtx: traceCtx,
+		})
+
+		if len(jobsToQueue) >= tokens {
+			s.processBatch(&jobsToQueue, &scheduled)
+			processedCount += len(jobsToQueue)
+			jobsToQueue = jobsToQueue[:0]
+			scheduled = scheduled[:0]
+			// Out of tokens: the rest of the due set retries shortly.
+			for _, rest := range dueEnts[i+1:] {
+				s.wheel.Schedule(rest, now.Add(wheelRetryDelay))
+			}
+			break
+		}
+	}
+
+	if len(jobsToQueue) > 0 {
+		s.processBatch(&jobsToQueue, &scheduled)
+		processedCount += len(jobsToQueue)
+	}
+
+	if processedCount > 0 {
+		dur := time.Since(startTime)
+		LogSystemPerformance(s.logger, "BatchPulseSystem", processedCount, dur)
+	}
+}
+
+// reconcileWheel is the periodic full scan that keeps the wheel honest: it
+// seeds monitors the wheel is not tracking yet (startup, config reload,
+// re-enable) and re-buckets monitors whose next check moved earlier than
+// their wheel slot (admin trigger-now, shortened interval). Checks that
+// moved later are left alone — the dispatch path re-schedules a popped
+// monitor that turns out not to be due, which is cheaper than comparing
+// every entity here.
+func (s *BatchPulseSystem) reconcileWheel(now time.Time) {
+	query := s.filter.Query()
+	for query.Next() {
+		ent := query.Entity()
+		state, _, _, _ := query.Get()
+
+		if state.Flags&components.StatePulsePending != 0 {
+			// In flight; the retry slot set at dispatch tracks this one.
+			if _, ok := s.wheel.DueTime(ent); !ok {
+				s.wheel.Schedule(ent, now.Add(wheelRetryDelay))
+			}
+			continue
+		}
+		due := state.NextCheckTime
+		if state.Flags&components.StatePulseFirstCheck != 0 || due.IsZero() {
+			due = now
+		}
+		if cur, ok := s.wheel.DueTime(ent); !ok || cur.After(due) {
+			s.wheel.Schedule(ent, due)
+		}
+	}
+}
+
+// requeueScheduled pushes every collected entity back into the wheel at the
+// retry slot after an enqueue attempt that transitioned nothing.
+func (s *BatchPulseSystem) requeueScheduled(scheduled []scheduledPulse) {
+	retry := s.now().Add(wheelRetryDelay)
+	for _, item := range scheduled {
+		abandonTrace(item.traceCtx)
+		s.wheel.Schedule(item.ent, retry)
+	}
+}
+
+// abandonTrace closes the root span of a dispatch whose job never made it
+// into the queue; the retried dispatch decides tracing afresh.
+func abandonTrace(ctx context.Context) {
+	if ctx != nil {
+		trace.SpanFromContext(ctx).End()
+	}
+}
+
+// processBatch attempts to enqueue a batch of jobs and updates entity states on success.
+func (s *BatchPulseSystem) processBatch(jobs *[]interface{}, scheduled *[]scheduledPulse) {
+	stats := s.queue.Stats()
+	if stats.Capacity > 0 && stats.QueueDepth >= int(float64(stats.Capacity)*0.9) {
+		s.logger.Debugw("Pulse queue near capacity; skipping enqueue", "depth", stats.QueueDepth, "capacity", stats.Capacity)
+		if s.wheel != nil {
+			s.requeueScheduled(*scheduled)
+		} else {
+			for _, item := range *scheduled {
+				abandonTrace(item.traceCtx)
+			}
+		}
+		return
+	}
+	// Soak mode: an injected enqueue failure behaves like a full queue, so
+	// the entities retry on the next tick without a state transition.
+	if s.faults != nil && s.faults.FailEnqueue() {
+		if s.wheel != nil {
+			s.requeueScheduled(*scheduled)
+		} else {
+			for _, item := range *scheduled {
+				abandonTrace(item.traceCtx)
+			}
+		}
+		return
+	}
+	accepted, err := s.queue.EnqueueBatch(*jobs)
+	if err != nil {
+		s.logger.Warnw("Pulse job batch not fully enqueued, queue may be full", "error", err)
+		// Rejected entities keep their state and retry on the next tick.
+	}
+
+	// Transition only the entities whose job was actually admitted; with drop
+	// policies like DropOldest the accepted set is not necessarily a prefix.
+	now := s.now()
+	for i, item := range *scheduled {
+		if i >= len(accepted) || !accepted[i] {
+			abandonTrace(item.traceCtx)
+			if s.wheel != nil {
+				s.wheel.Schedule(item.ent, now.Add(wheelRetryDelay))
+			}
+			continue
+		}
+		ent := item.ent
+		if !s.world.Alive(ent) {
+			continue
+		}
+		state := item.state
+		if state == nil {
+			continue
+		}
+
+		// Transition directly to Pending and schedule the next check.
+		state.Flags &^= components.StatePulseFirstCheck
+		state.Flags &^= components.StatePulseNeeded
+		state.Flags |= components.StatePulsePending
+		state.PendingSince = now
+		state.LastPulseCheckTime = now
+		s.metaMapper.Get(ent).LastEventTime = now
+		state.NextCheckTime = now.Add(item.interval)
+		if s.wheel != nil {
+			s.wheel.Schedule(ent, state.NextCheckTime)
+		}
+		s.stateLogger.LogTransition(ent, item.oldState, *state)
+	}
+}
+
+// Finalize is a no-op for this system.
+func (s *BatchPulseSystem) Finalize(_ *ecs.World) {}
@@ -0,0 +1,71 @@
+package systems
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// DefaultCommandBuffer is the queue capacity used when none is given.
+const DefaultCommandBuffer = 256
+
+// ErrCommandQueueFull is returned by Submit when the command queue is full.
+var ErrCommandQueueFull = fmt.Errorf("command queue full")
+
+// ErrNilCommand is returned by Submit for a nil function; applying one
+// would panic the tick goroutine, taking every monitor down with it.
+var ErrNilCommand = fmt.Errorf("nil command")
+
+// CommandSystem applies externally submitted world mutations from inside the
+// ECS tick. Goroutines outside the run loop (the admin API, reload watchers)
+// must never touch the world directly: Ark panics if the world is mutated
+// while a query is open, and the tick systems hold queries open constantly.
+// Commands are queued here instead and drained early in each tick, before the
+// dispatch systems read monitor state.
+type CommandSystem struct {
+	commands chan func(*ecs.World)
+}
+
+// NewCommandSystem creates a command system with the given queue capacity.
+// Non-positive capacities fall back to DefaultCommandBuffer.
+func NewCommandSystem(capacity int) *CommandSystem {
+	if capacity <= 0 {
+		capacity = DefaultCommandBuffer
+	}
+	return &CommandSystem{commands: make(chan func(*ecs.World), capacity)}
+}
+
+// Submit queues fn to run inside an upcoming tick. It never blocks; if the
+// queue is full, ErrCommandQueueFull is returned and the caller decides
+// whether to retry or fail its request. Nil commands are rejected rather
+// than queued, since they would only surface as a panic mid-tick.
+// Thread-safe.
+func (s *CommandSystem) Submit(fn func(*ecs.World)) error {
+	if fn == nil {
+		return ErrNilCommand
+	}
+	select {
+	case s.commands <- fn:
+		return nil
+	default:
+		return ErrCommandQueueFull
+	}
+}
+
+// Initialize sets up the system (no-op for this system).
+func (s *CommandSystem) Initialize(w *ecs.World) {}
+
+// Update drains and runs all commands queued since the previous tick.
+func (s *CommandSystem) Update(w *ecs.World) {
+	for {
+		select {
+		case fn := <-s.commands:
+			fn(w)
+		default:
+			return
+		}
+	}
+}
+
+// Finalize performs cleanup (no-op for this system).
+func (s *CommandSystem) Finalize(w *ecs.World) {}
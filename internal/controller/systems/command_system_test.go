@@ -0,0 +1,68 @@
+package systems
+
+import (
+	"errors"
+	"testing"
+
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+func TestCommandSystem_SubmitAndDrain(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	mapper := ecs.NewMap1[components.MonitorState](&world)
+	target := mapper.NewEntity(&components.MonitorState{Flags: components.StatePulsePending})
+	bystander := mapper.NewEntity(&components.MonitorState{
+		Flags: components.StatePulseFirstCheck | components.StateCodePending,
+	})
+
+	system := NewCommandSystem(4)
+	err := system.Submit(func(w *ecs.World) {
+		mapper.Get(target).Flags &^= components.StatePulsePending
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	system.Update(&world)
+
+	if mapper.Get(target).Flags&components.StatePulsePending != 0 {
+		t.Error("expected PulsePending cleared on the target entity")
+	}
+	// The command must not leak onto other entities' state.
+	if flags := mapper.Get(bystander).Flags; flags != components.StatePulseFirstCheck|components.StateCodePending {
+		t.Errorf("bystander flags = %v, want untouched", flags)
+	}
+}
+
+func TestCommandSystem_RejectsNilCommand(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	system := NewCommandSystem(4)
+
+	if err := system.Submit(nil); !errors.Is(err, ErrNilCommand) {
+		t.Errorf("Submit(nil) = %v, want ErrNilCommand", err)
+	}
+	// The rejected command must not have been queued; Update must not panic.
+	system.Update(&world)
+}
+
+func TestCommandSystem_QueueFull(t *testing.T) {
+	t.Parallel()
+
+	system := NewCommandSystem(1)
+	noop := func(*ecs.World) {}
+
+	if err := system.Submit(noop); err != nil {
+		t.Fatalf("first Submit failed: %v", err)
+	}
+	if err := system.Submit(noop); !errors.Is(err, ErrCommandQueueFull) {
+		t.Errorf("second Submit = %v, want ErrCommandQueueFull", err)
+	}
+}
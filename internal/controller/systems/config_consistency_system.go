@@ -0,0 +1,222 @@
+package systems
+
+import (
+	"sync/atomic"
+	"time"
+
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// ConsistencyConfig configures the config-generation consistency sweep.
+type ConsistencyConfig struct {
+	// SweepBudget bounds how many entities are examined per tick.
+	SweepBudget int
+	// GracePeriod is how long a generation mismatch may persist before it is
+	// flagged. A legitimate update writes all components within one tick, so
+	// the grace only needs to cover in-progress work.
+	GracePeriod time.Duration
+}
+
+// DefaultConsistencyConfig returns defaults suitable for large worlds: the
+// sweep visits 1000 entities per tick and tolerates mismatches for 10s.
+func DefaultConsistencyConfig() ConsistencyConfig {
+	return ConsistencyConfig{
+		SweepBudget: 1000,
+		GracePeriod: 10 * time.Second,
+	}
+}
+
+// ConfigHealer re-applies the latest manifest's configuration to one entity.
+// Implementations return true when the entity was healed.
+type ConfigHealer interface {
+	HealEntity(entity ecs.Entity, name string) bool
+}
+
+// ConsistencyStats is a snapshot of the sweep's counters.
+type ConsistencyStats struct {
+	// MixedGeneration is the number of entities currently flagged as having
+	// config components from different manifest generations.
+	MixedGeneration uint64 `json:"mixed_generation"`
+	// Flagged is the total number of mismatches flagged since start.
+	Flagged uint64 `json:"flagged"`
+	// Healed is the total number of entities healed since start.
+	Healed uint64 `json:"healed"`
+}
+
+// ConfigConsistencySystem sweeps entities for config components written from
+// different manifest generations, which happens when an update is interrupted
+// between component swaps. Mismatches outlasting the grace period are logged
+// and counted; if a healer is attached, the latest manifest's config is
+// re-applied to just those entities.
+//
+// The sweep is incremental: at most SweepBudget entities are examined per
+// tick, cycling through the world round-robin.
+type ConfigConsistencySystem struct {
+	world  *ecs.World
+	cfg    ConsistencyConfig
+	logger Logger
+	healer ConfigHealer
+
+	filter             *ecs.Filter2[components.MonitorState, components.PulseConfig]
+	metaMapper         *ecs.Map1[components.MonitorMeta]
+	interventionMapper *ecs.Map1[components.InterventionConfig]
+	codeMapper         *ecs.Map1[components.CodeConfig]
+
+	// cursor is the index of the next entity to examine in iteration order.
+	cursor int
+	// firstSeen tracks when a mismatch was first observed per entity, so the
+	// grace period is measured from detection rather than from the write.
+	firstSeen map[ecs.Entity]time.Time
+	// flaggedSet holds entities currently flagged (mismatch past grace and
+	// not yet healed), so a persistent mismatch is reported once, not every
+	// sweep.
+	flaggedSet map[ecs.Entity]bool
+
+	mixed   atomic.Uint64
+	flagged atomic.Uint64
+	healed  atomic.Uint64
+}
+
+// NewConfigConsistencySystem creates a consistency sweep for the given world.
+func NewConfigConsistencySystem(world *ecs.World, cfg ConsistencyConfig, logger Logger) *ConfigConsistencySystem {
+	if cfg.SweepBudget <= 0 {
+		cfg.SweepBudget = 1000
+	}
+	if cfg.GracePeriod < 0 {
+		cfg.GracePeriod = 0
+	}
+	return &ConfigConsistencySystem{
+		world:              world,
+		cfg:                cfg,
+		logger:             logger,
+		filter:             ecs.NewFilter2[components.MonitorState, components.PulseConfig](world),
+		metaMapper:         ecs.NewMap1[components.MonitorMeta](world),
+		interventionMapper: ecs.NewMap1[components.InterventionConfig](world),
+		codeMapper:         ecs.NewMap1[components.CodeConfig](world),
+		firstSeen:          map[ecs.Entity]time.Time{},
+		flaggedSet:         map[ecs.Entity]bool{},
+	}
+}
+
+// SetHealer attaches the healer used to re-apply the latest manifest's config
+// to flagged entities. Without one, mismatches are only logged and counted.
+func (s *ConfigConsistencySystem) SetHealer(h ConfigHealer) {
+	s.healer = h
+}
+
+// Stats returns a snapshot of the sweep counters.
+func (s *ConfigConsistencySystem) Stats() ConsistencyStats {
+	return ConsistencyStats{
+		MixedGeneration: s.mixed.Load(),
+		Flagged:         s.flagged.Load(),
+		Healed:          s.healed.Load(),
+	}
+}
+
+func (s *ConfigConsistencySystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+// flagCandidate is a mismatch past its grace period, collected during the
+// sweep and processed after the query closes (healing mutates components,
+// which is not allowed while the world is locked for iteration).
+type flagCandidate struct {
+	ent  ecs.Entity
+	name string
+	gen  uint32
+}
+
+func (s *ConfigConsistencySystem) Update(_ *ecs.World) {
+	now := time.Now()
+	examined := 0
+	index := 0
+	var candidates []flagCandidate
+
+	query := s.filter.Query()
+	for query.Next() {
+		if index < s.cursor {
+			index++
+			continue
+		}
+		if examined >= s.cfg.SweepBudget {
+			// Budget exhausted; resume here next tick.
+			query.Close()
+			s.cursor = index
+			s.flagAndHeal(candidates)
+			return
+		}
+		state, pulseCfg := query.Get()
+		if c, flag := s.examine(query.Entity(), state, pulseCfg, now); flag {
+			candidates = append(candidates, c)
+		}
+		examined++
+		index++
+	}
+	// Reached the end of the world; wrap around for the next tick.
+	s.cursor = 0
+	s.flagAndHeal(candidates)
+}
+
+// examine checks one entity's config components for a generation mismatch and
+// reports whether it should be flagged this tick.
+func (s *ConfigConsistencySystem) examine(ent ecs.Entity, state *components.MonitorState, pulseCfg *components.PulseConfig, now time.Time) (flagCandidate, bool) {
+	gen := pulseCfg.Generation
+	mismatch := false
+	// HasAll before Get: the nil pointer Ark returns for a missing component
+	// is produced via unsafe arithmetic; a == nil check may be elided.
+	if s.interventionMapper.HasAll(ent) {
+		if s.interventionMapper.Get(ent).Generation != gen {
+			mismatch = true
+		}
+	}
+	if !mismatch && s.codeMapper.HasAll(ent) {
+		if s.codeMapper.Get(ent).Generation != gen {
+			mismatch = true
+		}
+	}
+
+	if !mismatch {
+		delete(s.firstSeen, ent)
+		delete(s.flaggedSet, ent)
+		s.mixed.Store(uint64(len(s.flaggedSet)))
+		return flagCandidate{}, false
+	}
+
+	seen, tracked := s.firstSeen[ent]
+	if !tracked {
+		s.firstSeen[ent] = now
+		return flagCandidate{}, false
+	}
+	if now.Sub(seen) < s.cfg.GracePeriod || s.flaggedSet[ent] {
+		return flagCandidate{}, false
+	}
+	return flagCandidate{ent: ent, name: s.metaMapper.Get(ent).Name, gen: gen}, true
+}
+
+// flagAndHeal logs and counts flagged mismatches, and re-applies the latest
+// manifest's config through the healer where one is attached. Runs with the
+// world unlocked.
+func (s *ConfigConsistencySystem) flagAndHeal(candidates []flagCandidate) {
+	for _, c := range candidates {
+		// Flag once per mismatch; a persistent mismatch stays in flaggedSet.
+		s.flaggedSet[c.ent] = true
+		s.flagged.Add(1)
+		s.mixed.Store(uint64(len(s.flaggedSet)))
+		s.logger.Warnf("Monitor '%s' has config components from mixed manifest generations (pulse gen=%d); update was likely interrupted", c.name, c.gen)
+
+		if s.healer != nil && s.healer.HealEntity(c.ent, c.name) {
+			s.healed.Add(1)
+			delete(s.firstSeen, c.ent)
+			delete(s.flaggedSet, c.ent)
+			s.mixed.Store(uint64(len(s.flaggedSet)))
+			s.logger.Infof("Monitor '%s' healed: latest manifest config re-applied", c.name)
+		}
+	}
+}
+
+// Finalize is a no-op for this system.
+func (s *ConfigConsistencySystem) Finalize(_ *ecs.World) {}
@@ -0,0 +1,168 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// stubHealer heals entities by aligning the CodeConfig generation with the
+// PulseConfig generation, mimicking a re-applied manifest.
+type stubHealer struct {
+	pulseMapper *ecs.Map1[components.PulseConfig]
+	codeMapper  *ecs.Map1[components.CodeConfig]
+	healed      []string
+}
+
+func (h *stubHealer) HealEntity(ent ecs.Entity, name string) bool {
+	h.codeMapper.Get(ent).Generation = h.pulseMapper.Get(ent).Generation
+	h.healed = append(h.healed, name)
+	return true
+}
+
+// newMixedGenerationWorld creates a world with one consistent entity and one
+// whose update was "interrupted" between component writes: PulseConfig is at
+// generation 2 but CodeConfig stayed at generation 1.
+func newMixedGenerationWorld(t *testing.T) (*ecs.World, ecs.Entity, *ConfigConsistencySystem) {
+	t.Helper()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	system := NewConfigConsistencySystem(&world, ConsistencyConfig{
+		SweepBudget: 100,
+		GracePeriod: 10 * time.Millisecond,
+	}, zap.NewNop().Sugar())
+	system.Initialize(&world)
+
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.CodeConfig](&world)
+	mapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "consistent"},
+		&components.PulseConfig{Generation: 2},
+		&components.CodeConfig{Generation: 2},
+	)
+	mixed := mapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "interrupted"},
+		&components.PulseConfig{Generation: 2},
+		&components.CodeConfig{Generation: 1},
+	)
+	return &world, mixed, system
+}
+
+func TestConfigConsistencySystem_FlagsInterruptedUpdate(t *testing.T) {
+	t.Parallel()
+	world, _, system := newMixedGenerationWorld(t)
+
+	// First sweep only records the mismatch; flagging waits for the grace.
+	system.Update(world)
+	if got := system.Stats().Flagged; got != 0 {
+		t.Fatalf("expected no flags before grace period, got %d", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	system.Update(world)
+
+	stats := system.Stats()
+	if stats.Flagged != 1 {
+		t.Errorf("expected 1 flagged entity, got %d", stats.Flagged)
+	}
+	if stats.MixedGeneration != 1 {
+		t.Errorf("expected 1 mixed-generation entity, got %d", stats.MixedGeneration)
+	}
+	if stats.Healed != 0 {
+		t.Errorf("expected no heals without a healer, got %d", stats.Healed)
+	}
+
+	// A persistent mismatch is flagged once, not every sweep.
+	system.Update(world)
+	if got := system.Stats().Flagged; got != 1 {
+		t.Errorf("expected flag count to stay at 1, got %d", got)
+	}
+}
+
+func TestConfigConsistencySystem_HealsFlaggedEntities(t *testing.T) {
+	t.Parallel()
+	world, _, system := newMixedGenerationWorld(t)
+
+	healer := &stubHealer{
+		pulseMapper: ecs.NewMap1[components.PulseConfig](world),
+		codeMapper:  ecs.NewMap1[components.CodeConfig](world),
+	}
+	system.SetHealer(healer)
+
+	system.Update(world)
+	time.Sleep(20 * time.Millisecond)
+	system.Update(world)
+
+	stats := system.Stats()
+	if stats.Healed != 1 {
+		t.Fatalf("expected 1 healed entity, got %d", stats.Healed)
+	}
+	if stats.MixedGeneration != 0 {
+		t.Errorf("expected no mixed entities after healing, got %d", stats.MixedGeneration)
+	}
+	if len(healer.healed) != 1 || healer.healed[0] != "interrupted" {
+		t.Errorf("expected healer called for 'interrupted', got %v", healer.healed)
+	}
+
+	// After healing, further sweeps see a consistent world.
+	time.Sleep(20 * time.Millisecond)
+	system.Update(world)
+	if got := system.Stats().Flagged; got != 1 {
+		t.Errorf("expected no re-flag after healing, got %d total flags", got)
+	}
+}
+
+func TestConfigConsistencySystem_ResolvedMismatchIsDropped(t *testing.T) {
+	t.Parallel()
+	world, mixed, system := newMixedGenerationWorld(t)
+
+	system.Update(world)
+
+	// Complete the "interrupted" update externally before the grace elapses.
+	codeMapper := ecs.NewMap1[components.CodeConfig](world)
+	codeMapper.Get(mixed).Generation = 2
+
+	time.Sleep(20 * time.Millisecond)
+	system.Update(world)
+
+	stats := system.Stats()
+	if stats.Flagged != 0 {
+		t.Errorf("expected no flags for a resolved mismatch, got %d", stats.Flagged)
+	}
+	if stats.MixedGeneration != 0 {
+		t.Errorf("expected no mixed entities, got %d", stats.MixedGeneration)
+	}
+}
+
+func TestConfigConsistencySystem_SweepIsBounded(t *testing.T) {
+	t.Parallel()
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	system := NewConfigConsistencySystem(&world, ConsistencyConfig{
+		SweepBudget: 3,
+		GracePeriod: time.Hour, // never flag; this test only checks pacing
+	}, zap.NewNop().Sugar())
+	system.Initialize(&world)
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	for i := 0; i < 10; i++ {
+		mapper.NewEntity(&components.MonitorState{}, &components.MonitorMeta{Name: "m"}, &components.PulseConfig{Generation: 1})
+	}
+
+	// Each sweep visits at most SweepBudget entities; four sweeps cover the
+	// ten entities and wrap around without panicking.
+	for i := 0; i < 4; i++ {
+		system.Update(&world)
+	}
+	if system.cursor != 0 {
+		t.Errorf("expected cursor to wrap to 0 after covering the world, got %d", system.cursor)
+	}
+}
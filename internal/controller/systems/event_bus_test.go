@@ -0,0 +1,102 @@
+package systems
+
+import (
+	"testing"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/events"
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// TestEventBus_FailInterveneRecoverCycle drives a full incident through the
+// result systems and asserts the exact ordered event sequence a subscriber
+// sees: intervention fails, red fires, a retry succeeds, cyan announces the
+// verification window, and green closes the cycle as a recovery.
+func TestEventBus_FailInterveneRecoverCycle(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	logger := zap.NewNop().Sugar()
+	stateLogger := newNoopStateLogger()
+	bus := events.NewBus()
+	sub := bus.Subscribe(16)
+
+	interventionResults := make(chan []jobs.Result, 4)
+	interventionSystem := NewBatchInterventionResultSystem(&world, interventionResults, logger, stateLogger)
+	interventionSystem.SetEventBus(bus)
+
+	codeResults := make(chan []jobs.Result, 4)
+	codeSystem := NewBatchCodeResultSystem(&world, codeResults, logger, stateLogger)
+	codeSystem.SetEventBus(bus)
+
+	ent := newChainTestEntity(t, &world)
+	ecs.NewMap[components.CodeStatus](&world).Add(ent, &components.CodeStatus{})
+	state := ecs.NewMap[components.MonitorState](&world).Get(ent)
+
+	intervene := func(err error) {
+		state.Flags |= components.StateInterventionPending
+		interventionResults <- []jobs.Result{{Ent: ent, Err: err, Payload: jobs.Payload{Kind: jobs.KindIntervention}}}
+		interventionSystem.Update(&world)
+	}
+	deliver := func(color string) {
+		state.Flags |= components.StateCodePending
+		codeResults <- []jobs.Result{{Ent: ent, Payload: jobs.Payload{Kind: jobs.KindCode, Color: color}}}
+		codeSystem.Update(&world)
+	}
+
+	intervene(jobs.ErrExecUnexpectedExitCode)
+	deliver("red")
+	intervene(nil)
+	deliver("cyan")
+	deliver("green")
+
+	want := []struct {
+		kind     events.Kind
+		from, to string
+	}{
+		{events.KindInterventionFailed, "intervening", "incident"},
+		{events.KindColorChange, "", "red"},
+		{events.KindInterventionSucceeded, "intervening", "verifying"},
+		{events.KindColorChange, "red", "cyan"},
+		{events.KindRecovered, "cyan", "green"},
+	}
+	got := make([]events.Event, 0, len(want))
+	for {
+		ev, ok := sub.Next()
+		if !ok {
+			break
+		}
+		got = append(got, ev)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("subscriber saw %d events, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		ev := got[i]
+		if ev.Kind != w.kind || ev.From != w.from || ev.To != w.to {
+			t.Errorf("event %d = %v %q→%q, want %v %q→%q", i, ev.Kind, ev.From, ev.To, w.kind, w.from, w.to)
+		}
+		if ev.Monitor != "payments-api" {
+			t.Errorf("event %d monitor = %q, want payments-api", i, ev.Monitor)
+		}
+		if ev.Time.IsZero() {
+			t.Errorf("event %d has zero timestamp", i)
+		}
+	}
+	// The failed intervention carries the error text; the successful one
+	// names the configured action.
+	if got[0].Details != jobs.ErrExecUnexpectedExitCode.Error() {
+		t.Errorf("failure details = %q, want error text", got[0].Details)
+	}
+	if got[2].Details != "exec" {
+		t.Errorf("success details = %q, want exec", got[2].Details)
+	}
+	if dropped := sub.Dropped(); dropped != 0 {
+		t.Errorf("Dropped() = %d, want 0", dropped)
+	}
+}
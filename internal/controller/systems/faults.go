@@ -0,0 +1,215 @@
+package systems
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cpra/internal/jobs"
+)
+
+// FaultInjector provides probabilistic fault injection for soak testing.
+//
+// It is wired into well-defined seams (result routing in
+// BatchPulseResultSystem, enqueue in BatchPulseSystem, pool disruption in the
+// controller's soak loop) and is never active unless explicitly enabled - the
+// systems carry a nil injector in production. Every injected fault is counted
+// and logged, and the RNG is seeded so soak runs are reproducible.
+//
+// Queue replacement is not currently injectable: pools are bound to their
+// queue at construction and Pause/Resume are no-ops, so the closest available
+// disruption is shrinking a pool's worker capacity (see Controller).
+type FaultInjector struct {
+	cfg     FaultConfig
+	logger  Logger
+	seed    int64
+	mu      sync.Mutex
+	rng     *rand.Rand
+	delayed []delayedBatch
+	counts  [faultKindCount]atomic.Uint64
+}
+
+// FaultConfig configures fault injection probabilities and timings.
+// All probabilities are per-opportunity (per batch or per enqueue attempt).
+type FaultConfig struct {
+	Enabled bool  // Master switch; nothing is injected when false
+	Seed    int64 // RNG seed for reproducible runs; 0 derives one from the clock
+
+	DropResultBatch  float64 // Probability of dropping a result batch before processing
+	DelayResultBatch float64 // Probability of delaying a result batch
+	DelayTicks       int     // How many ticks a delayed batch is held
+	FailEnqueue      float64 // Probability of failing a pulse enqueue batch
+	CorruptPayload   float64 // Probability of corrupting one result payload in a batch
+
+	PoolDisruptInterval time.Duration // How often the controller shrinks a worker pool
+	PoolDisruptFor      time.Duration // How long the pool stays shrunk
+}
+
+// DefaultFaultConfig returns a soak-oriented configuration (disabled).
+//
+// DropResultBatch defaults to 0: there is no recovery sweep for stuck Pending
+// flags yet, so a dropped batch strands its entities and the pending-age
+// invariant fires. Enable it deliberately to reproduce that class of bug.
+func DefaultFaultConfig() FaultConfig {
+	return FaultConfig{
+		Enabled:             false,
+		DropResultBatch:     0,
+		DelayResultBatch:    0.02,
+		DelayTicks:          5,
+		FailEnqueue:         0.01,
+		CorruptPayload:      0.005,
+		PoolDisruptInterval: 2 * time.Minute,
+		PoolDisruptFor:      10 * time.Second,
+	}
+}
+
+// FaultKind identifies one injectable fault for counting.
+type FaultKind uint8
+
+const (
+	FaultDropBatch FaultKind = iota
+	FaultDelayBatch
+	FaultFailEnqueue
+	FaultCorruptPayload
+	FaultPoolDisrupt
+	faultKindCount
+)
+
+// String returns the counter label for the fault kind.
+func (k FaultKind) String() string {
+	switch k {
+	case FaultDropBatch:
+		return "drop_batch"
+	case FaultDelayBatch:
+		return "delay_batch"
+	case FaultFailEnqueue:
+		return "fail_enqueue"
+	case FaultCorruptPayload:
+		return "corrupt_payload"
+	case FaultPoolDisrupt:
+		return "pool_disrupt"
+	default:
+		return "unknown"
+	}
+}
+
+// delayedBatch holds a result batch until its tick countdown reaches zero.
+type delayedBatch struct {
+	batch     []jobs.Result
+	ticksLeft int
+}
+
+// NewFaultInjector creates a fault injector. The effective seed is logged so
+// a failing soak run can be replayed.
+func NewFaultInjector(cfg FaultConfig, logger Logger) *FaultInjector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	logger.Warnf("FAULT INJECTION ENABLED (seed=%d drop=%.3f delay=%.3f/%dt fail_enqueue=%.3f corrupt=%.3f) - do not run in production",
+		seed, cfg.DropResultBatch, cfg.DelayResultBatch, cfg.DelayTicks, cfg.FailEnqueue, cfg.CorruptPayload)
+	return &FaultInjector{
+		cfg:    cfg,
+		logger: logger,
+		seed:   seed,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Seed returns the effective RNG seed for this run.
+func (f *FaultInjector) Seed() int64 { return f.seed }
+
+// roll draws one probability decision from the seeded RNG.
+func (f *FaultInjector) roll(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	hit := f.rng.Float64() < p
+	f.mu.Unlock()
+	return hit
+}
+
+// record counts a fault occurrence.
+func (f *FaultInjector) record(kind FaultKind) {
+	f.counts[kind].Add(1)
+}
+
+// Counts returns the number of injected faults per kind.
+func (f *FaultInjector) Counts() map[string]uint64 {
+	out := make(map[string]uint64, faultKindCount)
+	for k := FaultKind(0); k < faultKindCount; k++ {
+		out[k.String()] = f.counts[k].Load()
+	}
+	return out
+}
+
+// InterceptResultBatch applies drop/delay/corrupt faults to a result batch
+// before it is processed. Returns the batch to process and true, or nil and
+// false when the batch was consumed (dropped or held for later delivery via
+// TakeDelayed).
+func (f *FaultInjector) InterceptResultBatch(batch []jobs.Result) ([]jobs.Result, bool) {
+	if f.roll(f.cfg.DropResultBatch) {
+		f.record(FaultDropBatch)
+		f.logger.Warnf("Fault: dropping result batch of %d before routing", len(batch))
+		return nil, false
+	}
+	if f.roll(f.cfg.DelayResultBatch) {
+		ticks := f.cfg.DelayTicks
+		if ticks < 1 {
+			ticks = 1
+		}
+		f.mu.Lock()
+		f.delayed = append(f.delayed, delayedBatch{batch: batch, ticksLeft: ticks})
+		f.mu.Unlock()
+		f.record(FaultDelayBatch)
+		f.logger.Warnf("Fault: delaying result batch of %d by %d ticks", len(batch), ticks)
+		return nil, false
+	}
+	if len(batch) > 0 && f.roll(f.cfg.CorruptPayload) {
+		f.mu.Lock()
+		idx := f.rng.Intn(len(batch))
+		f.mu.Unlock()
+		batch[idx].Payload = jobs.Payload{Extras: map[string]interface{}{"__fault_corrupted": true}}
+		f.record(FaultCorruptPayload)
+		f.logger.Warnf("Fault: corrupted payload of result %d/%d", idx, len(batch))
+	}
+	return batch, true
+}
+
+// TakeDelayed advances delay countdowns by one tick and returns the batches
+// that are now due. Called once per result-system update.
+func (f *FaultInjector) TakeDelayed() [][]jobs.Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var due [][]jobs.Result
+	remaining := f.delayed[:0]
+	for i := range f.delayed {
+		f.delayed[i].ticksLeft--
+		if f.delayed[i].ticksLeft <= 0 {
+			due = append(due, f.delayed[i].batch)
+		} else {
+			remaining = append(remaining, f.delayed[i])
+		}
+	}
+	f.delayed = remaining
+	return due
+}
+
+// FailEnqueue reports whether the current enqueue attempt should be failed.
+// The dispatch system treats it like a full queue, so entities retry on the
+// next tick.
+func (f *FaultInjector) FailEnqueue() bool {
+	if !f.roll(f.cfg.FailEnqueue) {
+		return false
+	}
+	f.record(FaultFailEnqueue)
+	f.logger.Warnf("Fault: failing pulse enqueue batch")
+	return true
+}
+
+// RecordPoolDisrupt counts a pool disruption performed by the controller.
+func (f *FaultInjector) RecordPoolDisrupt() {
+	f.record(FaultPoolDisrupt)
+}
@@ -0,0 +1,95 @@
+package systems
+
+import (
+	"testing"
+
+	"cpra/internal/jobs"
+
+	"go.uber.org/zap"
+)
+
+// newTestInjector returns an injector with the given config, always enabled.
+func newTestInjector(cfg FaultConfig) *FaultInjector {
+	cfg.Enabled = true
+	return NewFaultInjector(cfg, zap.NewNop().Sugar())
+}
+
+func TestFaultInjector_SeededRunsAreReproducible(t *testing.T) {
+	t.Parallel()
+
+	cfg := FaultConfig{Seed: 42, DropResultBatch: 0.5}
+	a := newTestInjector(cfg)
+	b := newTestInjector(cfg)
+
+	for i := 0; i < 100; i++ {
+		_, gotA := a.InterceptResultBatch([]jobs.Result{{}})
+		_, gotB := b.InterceptResultBatch([]jobs.Result{{}})
+		if gotA != gotB {
+			t.Fatalf("decision %d diverged between identically seeded injectors", i)
+		}
+	}
+	if a.Counts()["drop_batch"] != b.Counts()["drop_batch"] {
+		t.Errorf("drop counts diverged: %d vs %d", a.Counts()["drop_batch"], b.Counts()["drop_batch"])
+	}
+	if a.Counts()["drop_batch"] == 0 {
+		t.Error("expected some batches dropped at p=0.5")
+	}
+}
+
+func TestFaultInjector_DelayReleasesAfterTicks(t *testing.T) {
+	t.Parallel()
+
+	f := newTestInjector(FaultConfig{Seed: 1, DelayResultBatch: 1.0, DelayTicks: 3})
+
+	batch := []jobs.Result{{Err: jobs.ErrTCPCheckFailed}}
+	if _, deliver := f.InterceptResultBatch(batch); deliver {
+		t.Fatal("batch should have been held for delayed delivery")
+	}
+
+	for tick := 0; tick < 2; tick++ {
+		if due := f.TakeDelayed(); len(due) != 0 {
+			t.Fatalf("tick %d: batch released early", tick)
+		}
+	}
+	due := f.TakeDelayed()
+	if len(due) != 1 || len(due[0]) != 1 {
+		t.Fatalf("expected delayed batch released on tick 3, got %v", due)
+	}
+	if f.Counts()["delay_batch"] != 1 {
+		t.Errorf("delay_batch count = %d, want 1", f.Counts()["delay_batch"])
+	}
+}
+
+func TestFaultInjector_CorruptPayload(t *testing.T) {
+	t.Parallel()
+
+	f := newTestInjector(FaultConfig{Seed: 1, CorruptPayload: 1.0})
+
+	batch := []jobs.Result{{Payload: jobs.Payload{Kind: jobs.KindPulse, Extras: map[string]interface{}{"latency": 1}}}}
+	out, deliver := f.InterceptResultBatch(batch)
+	if !deliver {
+		t.Fatal("corrupted batch must still be delivered")
+	}
+	if out[0].Payload.Extra("__fault_corrupted") == nil {
+		t.Error("expected payload to be corrupted")
+	}
+}
+
+func TestFaultInjector_DisabledProbabilitiesInjectNothing(t *testing.T) {
+	t.Parallel()
+
+	f := newTestInjector(FaultConfig{Seed: 1})
+	for i := 0; i < 100; i++ {
+		if _, deliver := f.InterceptResultBatch([]jobs.Result{{}}); !deliver {
+			t.Fatal("zero probabilities must never drop a batch")
+		}
+		if f.FailEnqueue() {
+			t.Fatal("zero probabilities must never fail an enqueue")
+		}
+	}
+	for kind, n := range f.Counts() {
+		if n != 0 {
+			t.Errorf("fault %q counted %d times with zero probabilities", kind, n)
+		}
+	}
+}
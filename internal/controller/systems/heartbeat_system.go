@@ -0,0 +1,319 @@
+package systems
+
+import (
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+
+	"github.com/google/uuid"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Heartbeat ingestion errors.
+var (
+	ErrUnknownHeartbeatToken   = errors.New("unknown heartbeat token")
+	ErrUnknownHeartbeatMonitor = errors.New("unknown heartbeat monitor")
+	ErrHeartbeatBadToken       = errors.New("heartbeat token mismatch")
+	ErrHeartbeatRateLimited    = errors.New("heartbeat rate limited")
+)
+
+// defaultHeartbeatMinGap is the minimum spacing between accepted beats per token.
+// Beats arriving faster (e.g. a burst replayed after a network partition) are
+// rejected with ErrHeartbeatRateLimited; the first beat of the burst is enough
+// to mark the monitor alive.
+const defaultHeartbeatMinGap = time.Second
+
+// HeartbeatIngestor is the thread-safe handoff between the HTTP heartbeat
+// endpoint and the ECS tick loop. HTTP handlers call Record, which conflates
+// beats into a pending map (latest timestamp per entity); the HeartbeatSystem
+// drains that map once per tick. The world is never touched from handlers.
+//
+// It also acts as the token registry: the entity mapper registers each
+// heartbeat monitor at load time and receives a generated token in return.
+type HeartbeatIngestor struct {
+	mu           sync.Mutex
+	tokens       map[string]ecs.Entity // token -> entity
+	names        map[string]string     // monitor name -> token
+	lastAccepted map[string]time.Time  // token -> last accepted beat (rate limiting)
+	pending      map[ecs.Entity]time.Time
+	minGap       time.Duration
+}
+
+// NewHeartbeatIngestor creates an empty ingestor with the default rate limit.
+func NewHeartbeatIngestor() *HeartbeatIngestor {
+	return &HeartbeatIngestor{
+		tokens:       make(map[string]ecs.Entity),
+		names:        make(map[string]string),
+		lastAccepted: make(map[string]time.Time),
+		pending:      make(map[ecs.Entity]time.Time),
+		minGap:       defaultHeartbeatMinGap,
+	}
+}
+
+// SetMinGap adjusts the per-token rate limit. Values <= 0 disable rate limiting.
+func (h *HeartbeatIngestor) SetMinGap(gap time.Duration) {
+	h.mu.Lock()
+	h.minGap = gap
+	h.mu.Unlock()
+}
+
+// Register maps the monitor and its token to the entity, generating a
+// random token when the manifest did not configure one. It implements
+// entities.HeartbeatRegistrar.
+func (h *HeartbeatIngestor) Register(monitorName, token string, ent ecs.Entity) string {
+	if token == "" {
+		token = uuid.NewString()
+	}
+	h.mu.Lock()
+	h.tokens[token] = ent
+	h.names[monitorName] = token
+	h.mu.Unlock()
+	return token
+}
+
+// Token returns the token for a monitor name, or "" if the monitor is not a
+// registered heartbeat monitor.
+func (h *HeartbeatIngestor) Token(monitorName string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.names[monitorName]
+}
+
+// Tokens returns a copy of the monitor-name -> token mapping for the
+// effective-config/admin surface.
+func (h *HeartbeatIngestor) Tokens() map[string]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make(map[string]string, len(h.names))
+	for name, token := range h.names {
+		out[name] = token
+	}
+	return out
+}
+
+// Record accepts a heartbeat for the given token at the given time.
+// Safe for concurrent use; called from HTTP handlers.
+func (h *HeartbeatIngestor) Record(token string, at time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ent, ok := h.tokens[token]
+	if !ok {
+		return ErrUnknownHeartbeatToken
+	}
+	return h.accept(token, ent, at)
+}
+
+// RecordNamed accepts a heartbeat addressed by monitor name, authenticated
+// with the monitor's pre-shared token. Safe for concurrent use; called from
+// HTTP handlers.
+func (h *HeartbeatIngestor) RecordNamed(monitorName, token string, at time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	want, ok := h.names[monitorName]
+	if !ok {
+		return ErrUnknownHeartbeatMonitor
+	}
+	// Constant-time compare so response timing never narrows down a token.
+	if subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+		return ErrHeartbeatBadToken
+	}
+	return h.accept(want, h.tokens[want], at)
+}
+
+// accept applies the rate limit and conflates the beat; h.mu must be held.
+func (h *HeartbeatIngestor) accept(token string, ent ecs.Entity, at time.Time) error {
+	if h.minGap > 0 {
+		if last, ok := h.lastAccepted[token]; ok && at.Sub(last) < h.minGap {
+			return ErrHeartbeatRateLimited
+		}
+	}
+	h.lastAccepted[token] = at
+
+	// Conflate: keep only the latest beat per entity until the next tick drains it.
+	if prev, ok := h.pending[ent]; !ok || at.After(prev) {
+		h.pending[ent] = at
+	}
+	return nil
+}
+
+// drain swaps the pending map with the (empty) scratch map and returns the
+// beats accumulated since the previous drain. The caller owns the returned map
+// and passes it back as scratch on the next call, avoiding per-tick allocations.
+func (h *HeartbeatIngestor) drain(scratch map[ecs.Entity]time.Time) map[ecs.Entity]time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := h.pending
+	h.pending = scratch
+	return out
+}
+
+// HeartbeatSystem evaluates push-based monitors once per interval.
+//
+// Each tick it applies the beats drained from the ingestor, then emits a
+// synthetic pulse result for every due monitor: success if a beat arrived
+// within interval+grace, ErrHeartbeatMissed otherwise. Results flow through
+// the regular BatchPulseResultSystem, so failures and recoveries drive the
+// same code/intervention machinery as probe-based monitors.
+type HeartbeatSystem struct {
+	world       *ecs.World
+	ingestor    *HeartbeatIngestor
+	results     chan<- []jobs.Result
+	logger      Logger
+	stateLogger *StateLogger
+
+	filter     *ecs.Filter3[components.MonitorState, components.PulseConfig, components.Heartbeat]
+	metaMapper *ecs.Map1[components.MonitorMeta]
+	scratch    map[ecs.Entity]time.Time
+
+	// now is injectable for deterministic timing tests.
+	now  func() time.Time
+	gate *StandbyGate // nil unless HA mode is on; closed while standby
+}
+
+// NewHeartbeatSystem creates a new HeartbeatSystem emitting into the pulse
+// result channel.
+func NewHeartbeatSystem(world *ecs.World, ingestor *HeartbeatIngestor, results chan<- []jobs.Result, logger Logger, stateLogger *StateLogger) *HeartbeatSystem {
+	return &HeartbeatSystem{
+		world:       world,
+		ingestor:    ingestor,
+		results:     results,
+		logger:      logger,
+		stateLogger: stateLogger,
+		filter: ecs.NewFilter3[components.MonitorState, components.PulseConfig, components.Heartbeat](world).
+			Without(ecs.C[components.Disabled]()),
+		metaMapper: ecs.NewMap1[components.MonitorMeta](world),
+		scratch:    make(map[ecs.Entity]time.Time),
+		now:        time.Now,
+	}
+}
+
+// SetClock routes the deadline decisions through c. A nil clock is ignored.
+func (s *HeartbeatSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+// SetStandbyGate makes dispatch honor the HA leadership gate. A nil gate
+// (the default) means always dispatch.
+func (s *HeartbeatSystem) SetStandbyGate(g *StandbyGate) {
+	s.gate = g
+}
+
+func (s *HeartbeatSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+// Update applies pending beats and emits synthetic results for due monitors.
+func (s *HeartbeatSystem) Update(_ *ecs.World) {
+	if s.gate != nil && s.gate.Standby() {
+		return
+	}
+	now := s.now()
+	beats := s.ingestor.drain(s.scratch)
+
+	var batch []jobs.Result
+	var scheduled []scheduledPulse
+
+	query := s.filter.Query()
+	for query.Next() {
+		ent := query.Entity()
+		state, cfg, hb := query.Get()
+
+		// Record the freshest beat for this monitor.
+		beat, hasBeat := beats[ent]
+		if hasBeat && beat.After(hb.LastSeen) {
+			hb.LastSeen = beat
+		}
+
+		// A synthetic result from a previous tick is still in flight.
+		if state.Flags&components.StatePulsePending != 0 {
+			continue
+		}
+
+		interval := cfg.Interval
+		if interval <= 0 {
+			interval = time.Second
+		}
+
+		// Due on first check, when the evaluation interval elapses, or
+		// immediately when a beat arrives for a currently failing monitor
+		// so recovery is not delayed until the next scheduled evaluation.
+		due := state.Flags&components.StatePulseFirstCheck != 0
+		if !due && (state.NextCheckTime.IsZero() || !state.NextCheckTime.After(now)) {
+			due = true
+		}
+		if !due && hasBeat && (state.PulseFailures > 0 || state.Flags&components.StateIncidentOpen != 0) {
+			due = true
+		}
+		if !due {
+			continue
+		}
+
+		var resErr error
+		if now.After(hb.LastSeen.Add(interval + hb.Grace)) {
+			resErr = jobs.ErrHeartbeatMissed
+		}
+
+		batch = append(batch, jobs.Result{
+			Ent:     ent,
+			Err:     resErr,
+			Payload: jobs.GetPulseHeartbeatPayload(),
+		})
+		scheduled = append(scheduled, scheduledPulse{
+			ent:      ent,
+			state:    state,
+			interval: interval,
+			oldState: *state,
+		})
+	}
+
+	// Return the drained map as scratch for the next tick.
+	for ent := range beats {
+		delete(beats, ent)
+	}
+	s.scratch = beats
+
+	if len(batch) == 0 {
+		return
+	}
+
+	// Hand off to the result channel; the result system drains it later in
+	// the same tick. Non-blocking: if the channel is full we leave states
+	// untouched and re-evaluate on the next tick.
+	select {
+	case s.results <- batch:
+	default:
+		s.logger.Warnw("Heartbeat result channel full; deferring evaluation", "count", len(batch))
+		return
+	}
+
+	// Mark the evaluated monitors pending so the result system accepts the
+	// synthetic results, and schedule the next evaluation.
+	for _, item := range scheduled {
+		if !s.world.Alive(item.ent) || item.state == nil {
+			continue
+		}
+		state := item.state
+		state.Flags &^= components.StatePulseFirstCheck
+		state.Flags &^= components.StatePulseNeeded
+		state.Flags |= components.StatePulsePending
+		state.PendingSince = now
+		state.LastPulseCheckTime = now
+		s.metaMapper.Get(item.ent).LastEventTime = now
+		state.NextCheckTime = now.Add(item.interval)
+		s.stateLogger.LogTransition(item.ent, item.oldState, *state)
+	}
+}
+
+// Finalize is a no-op for this system.
+func (s *HeartbeatSystem) Finalize(_ *ecs.World) {}
@@ -0,0 +1,351 @@
+package systems
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// fakeClock provides a controllable time source for deterministic timing tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(start time.Time) *fakeClock {
+	return &fakeClock{now: start}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// registerComponents registers all monitor component types up front, as the
+// controller's entity manager does. Maps cache pointers into the world's
+// component storage, so types must be registered before systems create maps.
+func registerComponents(world *ecs.World) {
+	ecs.ComponentID[components.MonitorState](world)
+	ecs.ComponentID[components.PulseConfig](world)
+	ecs.ComponentID[components.InterventionConfig](world)
+	ecs.ComponentID[components.CodeConfig](world)
+	ecs.ComponentID[components.CodeStatus](world)
+	ecs.ComponentID[components.JobStorage](world)
+	ecs.ComponentID[components.Shard](world)
+	ecs.ComponentID[components.Heartbeat](world)
+	ecs.ComponentID[components.Disabled](world)
+	ecs.ComponentID[components.ResultHistory](world)
+}
+
+// newHeartbeatWorld creates a world with a single heartbeat monitor and returns
+// the pieces needed to drive the system under test. Systems are created before
+// the entity, mirroring the controller, so all component types are registered
+// up front.
+func newHeartbeatWorld(t *testing.T, interval, grace time.Duration, start time.Time) (*ecs.World, ecs.Entity, *HeartbeatIngestor, *HeartbeatSystem, *BatchPulseResultSystem, chan []jobs.Result) {
+	t.Helper()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	ingestor := NewHeartbeatIngestor()
+	results := make(chan []jobs.Result, 16)
+	system := NewHeartbeatSystem(&world, ingestor, results, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.Heartbeat](&world)
+	ent := mapper.NewEntity(
+		&components.MonitorState{
+			Flags: components.StatePulseFirstCheck,
+		},
+		&components.MonitorMeta{},
+		&components.PulseConfig{
+			Interval:           interval,
+			UnhealthyThreshold: 1,
+			HealthyThreshold:   1,
+		},
+		&components.Heartbeat{
+			LastSeen: start,
+			Grace:    grace,
+		},
+	)
+
+	return &world, ent, ingestor, system, resultSystem, results
+}
+
+// drainOneBatch pops a single result batch or fails the test if none was emitted.
+func drainOneBatch(t *testing.T, results chan []jobs.Result) []jobs.Result {
+	t.Helper()
+	select {
+	case batch := <-results:
+		return batch
+	default:
+		t.Fatal("expected a result batch, got none")
+		return nil
+	}
+}
+
+func TestHeartbeatIngestor_RegisterAndRecord(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	mapper := ecs.NewMap1[components.Heartbeat](&world)
+	ent := mapper.NewEntity(&components.Heartbeat{})
+
+	ingestor := NewHeartbeatIngestor()
+	token := ingestor.Register("web-1", "", ent)
+	if token == "" {
+		t.Fatal("Register returned empty token")
+	}
+	if got := ingestor.Token("web-1"); got != token {
+		t.Errorf("Token(web-1) = %q, want %q", got, token)
+	}
+	if tokens := ingestor.Tokens(); tokens["web-1"] != token {
+		t.Errorf("Tokens()[web-1] = %q, want %q", tokens["web-1"], token)
+	}
+
+	now := time.Now()
+	if err := ingestor.Record("bogus", now); err != ErrUnknownHeartbeatToken {
+		t.Errorf("Record(bogus) = %v, want ErrUnknownHeartbeatToken", err)
+	}
+	if err := ingestor.Record(token, now); err != nil {
+		t.Errorf("Record = %v, want nil", err)
+	}
+	// A burst replayed within the rate-limit window is rejected after the first beat.
+	if err := ingestor.Record(token, now.Add(time.Millisecond)); err != ErrHeartbeatRateLimited {
+		t.Errorf("Record within minGap = %v, want ErrHeartbeatRateLimited", err)
+	}
+	if err := ingestor.Record(token, now.Add(2*time.Second)); err != nil {
+		t.Errorf("Record after minGap = %v, want nil", err)
+	}
+
+	// Beats are conflated: the drained map holds the latest timestamp only.
+	pending := ingestor.drain(make(map[ecs.Entity]time.Time))
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending beat, got %d", len(pending))
+	}
+	if !pending[ent].Equal(now.Add(2 * time.Second)) {
+		t.Errorf("pending beat = %v, want %v", pending[ent], now.Add(2*time.Second))
+	}
+}
+
+func TestHeartbeatSystem_MissDetectionTiming(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_000_000, 0)
+	clock := newFakeClock(start)
+	interval := 10 * time.Second
+	grace := 5 * time.Second
+
+	world, ent, _, system, resultSystem, results := newHeartbeatWorld(t, interval, grace, start)
+	system.now = clock.Now
+	stateMapper := ecs.NewMap[components.MonitorState](world)
+
+	// First evaluation at load time: the beat window has not elapsed yet.
+	system.Update(world)
+	batch := drainOneBatch(t, results)
+	if batch[0].Err != nil {
+		t.Fatalf("first evaluation: unexpected error %v", batch[0].Err)
+	}
+	resultSystem.ProcessBatch(batch)
+
+	// Just inside interval+grace: still healthy.
+	clock.Advance(14 * time.Second)
+	system.Update(world)
+	batch = drainOneBatch(t, results)
+	if batch[0].Err != nil {
+		t.Fatalf("within grace: unexpected error %v", batch[0].Err)
+	}
+	resultSystem.ProcessBatch(batch)
+
+	// Next evaluation is past interval+grace with no beat: the monitor fails.
+	clock.Advance(interval)
+	system.Update(world)
+	batch = drainOneBatch(t, results)
+	if batch[0].Err != jobs.ErrHeartbeatMissed {
+		t.Fatalf("past grace: err = %v, want ErrHeartbeatMissed", batch[0].Err)
+	}
+	resultSystem.ProcessBatch(batch)
+
+	state := stateMapper.Get(ent)
+	if state.Flags&components.StateIncidentOpen == 0 {
+		t.Error("expected incident to be open after missed heartbeat")
+	}
+	if got := ecs.NewMap[components.MonitorMeta](world).Get(ent).LastError; got != jobs.ErrHeartbeatMissed {
+		t.Errorf("LastError = %v, want ErrHeartbeatMissed", got)
+	}
+}
+
+func TestHeartbeatSystem_Recovery(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_000_000, 0)
+	clock := newFakeClock(start)
+	interval := 10 * time.Second
+	grace := 5 * time.Second
+
+	world, ent, ingestor, system, resultSystem, results := newHeartbeatWorld(t, interval, grace, start)
+	system.now = clock.Now
+	token := ingestor.Register("web-1", "", ent)
+	stateMapper := ecs.NewMap[components.MonitorState](world)
+
+	// Drive the monitor into a failed state: no beats for well past interval+grace.
+	system.Update(world)
+	resultSystem.ProcessBatch(drainOneBatch(t, results))
+	clock.Advance(30 * time.Second)
+	system.Update(world)
+	batch := drainOneBatch(t, results)
+	if batch[0].Err != jobs.ErrHeartbeatMissed {
+		t.Fatalf("expected ErrHeartbeatMissed, got %v", batch[0].Err)
+	}
+	resultSystem.ProcessBatch(batch)
+	if stateMapper.Get(ent).Flags&components.StateIncidentOpen == 0 {
+		t.Fatal("expected incident open before recovery")
+	}
+
+	// A beat arrives: the next tick re-evaluates immediately, without waiting
+	// for the scheduled interval, and the monitor recovers.
+	clock.Advance(time.Second)
+	if err := ingestor.Record(token, clock.Now()); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	system.Update(world)
+	batch = drainOneBatch(t, results)
+	if batch[0].Err != nil {
+		t.Fatalf("recovery evaluation: unexpected error %v", batch[0].Err)
+	}
+	resultSystem.ProcessBatch(batch)
+
+	state := stateMapper.Get(ent)
+	if state.Flags&components.StateIncidentOpen != 0 {
+		t.Error("expected incident to be closed after heartbeat recovery")
+	}
+	if got := ecs.NewMap[components.MonitorMeta](world).Get(ent).LastError; got != nil {
+		t.Errorf("LastError = %v, want nil", got)
+	}
+}
+
+func TestHeartbeatSystem_ConcurrentIngestion(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_000_000, 0)
+	clock := newFakeClock(start)
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	ingestor := NewHeartbeatIngestor()
+	ingestor.SetMinGap(0) // exercise the handoff, not the rate limiter
+
+	results := make(chan []jobs.Result, 64)
+	system := NewHeartbeatSystem(&world, ingestor, results, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+	system.now = clock.Now
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.PulseConfig, components.Heartbeat](&world)
+
+	const monitors = 8
+	tokens := make([]string, monitors)
+	ents := make([]ecs.Entity, monitors)
+	for i := 0; i < monitors; i++ {
+		ents[i] = mapper.NewEntity(
+			&components.MonitorState{Flags: components.StatePulseFirstCheck},
+			&components.MonitorMeta{},
+			&components.PulseConfig{Interval: 10 * time.Second, UnhealthyThreshold: 1, HealthyThreshold: 1},
+			&components.Heartbeat{LastSeen: start},
+		)
+		tokens[i] = ingestor.Register("mon", "", ents[i])
+	}
+
+	// Posters hammer the ingestor while the tick loop keeps draining it.
+	const postsPerToken = 50
+	latest := start.Add(postsPerToken * time.Millisecond)
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for _, token := range tokens {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+			for i := 1; i <= postsPerToken; i++ {
+				_ = ingestor.Record(token, start.Add(time.Duration(i)*time.Millisecond))
+			}
+		}(token)
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for ticking := true; ticking; {
+		select {
+		case <-done:
+			ticking = false
+		default:
+		}
+		system.Update(&world)
+		resultSystem.Update(&world)
+	}
+	// One final tick to apply beats recorded after the last drain.
+	system.Update(&world)
+	resultSystem.Update(&world)
+
+	hbMapper := ecs.NewMap[components.Heartbeat](&world)
+	for i, ent := range ents {
+		hb := hbMapper.Get(ent)
+		if !hb.LastSeen.Equal(latest) {
+			t.Errorf("entity %d: LastSeen = %v, want %v", i, hb.LastSeen, latest)
+		}
+	}
+}
+
+// TestHeartbeatIngestor_NamedRecordAuth covers beats addressed by monitor
+// name, authenticated with the manifest's pre-shared token.
+func TestHeartbeatIngestor_NamedRecordAuth(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	mapper := ecs.NewMap1[components.Heartbeat](&world)
+	ent := mapper.NewEntity(&components.Heartbeat{})
+
+	ingestor := NewHeartbeatIngestor()
+	if token := ingestor.Register("batch-etl", "s3cret", ent); token != "s3cret" {
+		t.Fatalf("Register with configured token returned %q, want s3cret", token)
+	}
+
+	now := time.Now()
+	if err := ingestor.RecordNamed("nope", "s3cret", now); err != ErrUnknownHeartbeatMonitor {
+		t.Errorf("RecordNamed(nope) = %v, want ErrUnknownHeartbeatMonitor", err)
+	}
+	if err := ingestor.RecordNamed("batch-etl", "wrong", now); err != ErrHeartbeatBadToken {
+		t.Errorf("RecordNamed with wrong token = %v, want ErrHeartbeatBadToken", err)
+	}
+	if err := ingestor.RecordNamed("batch-etl", "", now); err != ErrHeartbeatBadToken {
+		t.Errorf("RecordNamed with no token = %v, want ErrHeartbeatBadToken", err)
+	}
+	if err := ingestor.RecordNamed("batch-etl", "s3cret", now); err != nil {
+		t.Errorf("RecordNamed = %v, want nil", err)
+	}
+	// Name- and token-addressed beats share the same rate limit.
+	if err := ingestor.Record("s3cret", now.Add(time.Millisecond)); err != ErrHeartbeatRateLimited {
+		t.Errorf("Record within minGap = %v, want ErrHeartbeatRateLimited", err)
+	}
+
+	pending := ingestor.drain(make(map[ecs.Entity]time.Time))
+	if len(pending) != 1 || !pending[ent].Equal(now) {
+		t.Fatalf("pending = %v, want one beat at %v", pending, now)
+	}
+}
@@ -0,0 +1,251 @@
+package systems
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/queue"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// accuracySnapshotInterval is how often the scheduling-accuracy percentiles
+// are recomputed from the per-monitor rings.
+const accuracySnapshotInterval = time.Second
+
+// AccuracyStats is a snapshot of high-frequency scheduling accuracy.
+//
+// Offsets measure dispatch time minus intended check time, so positive
+// values mean late. Percentiles are computed across the per-monitor
+// sample rings roughly once per second.
+type AccuracyStats struct {
+	Monitors int   `json:"monitors"`
+	Samples  int   `json:"samples"`
+	P50Ms    int32 `json:"p50_ms"`
+	P95Ms    int32 `json:"p95_ms"`
+	P99Ms    int32 `json:"p99_ms"`
+	MaxMs    int32 `json:"max_ms"`
+}
+
+type hfScheduled struct {
+	ent      ecs.Entity
+	state    *components.MonitorState
+	hf       *components.HighFrequency
+	interval time.Duration
+	intended time.Time
+	first    bool
+	oldState components.MonitorState
+}
+
+// HighFrequencyPulseSystem dispatches pulse jobs for monitors that opted
+// into sub-second intervals via high_frequency.
+//
+// Unlike BatchPulseSystem, which spreads work across shards and visits
+// each monitor roughly once per sweep, this system scans every
+// high-frequency monitor on every tick. That is only affordable because
+// the loader caps the high-frequency population (see
+// PipelineConfig.HighFrequencyBudget). The next check time advances from
+// the intended time rather than the dispatch time, so scheduling jitter
+// does not accumulate as drift; a monitor that falls more than one
+// interval behind is re-anchored to now instead of bursting to catch up.
+//
+// Each dispatch records the offset between intended and actual dispatch
+// time in the monitor's HighFrequency ring, and the system periodically
+// aggregates the rings into percentile accuracy stats (see Accuracy).
+type HighFrequencyPulseSystem struct {
+	queue       queue.Queue
+	logger      Logger
+	stateLogger *StateLogger
+	world       *ecs.World
+	filter      *ecs.Filter4[components.MonitorState, components.JobStorage, components.PulseConfig, components.HighFrequency]
+	metaMapper  *ecs.Map1[components.MonitorMeta]
+	now         func() time.Time // overridable for fake-clock tests
+	gate        *StandbyGate     // nil unless HA mode is on; closed while standby
+
+	mu           sync.Mutex
+	accuracy     AccuracyStats
+	lastSnapshot time.Time
+	scratch      []int32
+}
+
+// NewHighFrequencyPulseSystem creates a new HighFrequencyPulseSystem
+// dispatching to the given (dedicated) queue.
+func NewHighFrequencyPulseSystem(world *ecs.World, q queue.Queue, logger Logger, stateLogger *StateLogger) *HighFrequencyPulseSystem {
+	return &HighFrequencyPulseSystem{
+		world:       world,
+		queue:       q,
+		logger:      logger,
+		stateLogger: stateLogger,
+		filter: ecs.NewFilter4[components.MonitorState, components.JobStorage, components.PulseConfig, components.HighFrequency](world).
+			Without(ecs.C[components.Disabled]()),
+		metaMapper: ecs.NewMap1[components.MonitorMeta](world),
+		now:        time.Now,
+	}
+}
+
+// SetClock routes the system's scheduling decisions through c. A nil clock
+// is ignored.
+func (s *HighFrequencyPulseSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+// SetStandbyGate makes dispatch honor the HA leadership gate. A nil gate
+// (the default) means always dispatch.
+func (s *HighFrequencyPulseSystem) SetStandbyGate(g *StandbyGate) {
+	s.gate = g
+}
+
+func (s *HighFrequencyPulseSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+// Accuracy returns the most recent scheduling-accuracy snapshot.
+// Safe for concurrent use.
+func (s *HighFrequencyPulseSystem) Accuracy() AccuracyStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accuracy
+}
+
+// Update dispatches all due high-frequency monitors. Runs every tick.
+func (s *HighFrequencyPulseSystem) Update(_ *ecs.World) {
+	if s.gate != nil && s.gate.Standby() {
+		return
+	}
+	now := s.now()
+	snapshotDue := now.Sub(s.lastSnapshot) >= accuracySnapshotInterval
+
+	var jobsToQueue []interface{}
+	var scheduled []hfScheduled
+	monitors := 0
+	samples := s.scratch[:0]
+
+	query := s.filter.Query()
+	for query.Next() {
+		ent := query.Entity()
+		state, jobStorage, pulseCfg, hf := query.Get()
+
+		monitors++
+		if snapshotDue {
+			samples = hf.Samples(samples)
+		}
+
+		// Skip if a pulse job is already pending.
+		if state.Flags&components.StatePulsePending != 0 {
+			continue
+		}
+
+		// Guard against missing jobs.
+		if jobStorage == nil || jobStorage.PulseJob == nil || jobStorage.PulseJob.IsNil() {
+			s.logger.Warnw("High-frequency entity has no valid PulseJob", "entity_id", ent.ID())
+			continue
+		}
+
+		interval := pulseCfg.Interval
+		if interval <= 0 {
+			interval = time.Second
+		}
+
+		first := state.Flags&components.StatePulseFirstCheck != 0
+		if !first && state.NextCheckTime.After(now) {
+			continue
+		}
+
+		jobsToQueue = append(jobsToQueue, jobStorage.PulseJob)
+		scheduled = append(scheduled, hfScheduled{
+			ent:      ent,
+			state:    state,
+			hf:       hf,
+			interval: interval,
+			intended: state.NextCheckTime,
+			first:    first || state.NextCheckTime.IsZero(),
+			oldState: *state,
+		})
+	}
+
+	if len(jobsToQueue) > 0 {
+		s.dispatch(now, jobsToQueue, scheduled)
+	}
+
+	if snapshotDue {
+		s.scratch = samples
+		s.snapshot(now, monitors, samples)
+	}
+}
+
+// dispatch enqueues the due jobs and, on success, transitions their
+// monitors to pending and records scheduling accuracy.
+func (s *HighFrequencyPulseSystem) dispatch(now time.Time, jobs []interface{}, scheduled []hfScheduled) {
+	stats := s.queue.Stats()
+	if stats.Capacity > 0 && stats.QueueDepth+len(jobs) > stats.Capacity {
+		s.logger.Warnw("High-frequency pulse queue full; deferring dispatch", "depth", stats.QueueDepth, "capacity", stats.Capacity)
+		return
+	}
+	accepted, err := s.queue.EnqueueBatch(jobs)
+	if err != nil {
+		s.logger.Warnw("High-frequency pulse batch not fully enqueued", "error", err)
+		// Rejected monitors keep their state and retry on the next tick.
+	}
+
+	for i, item := range scheduled {
+		if i >= len(accepted) || !accepted[i] {
+			continue
+		}
+		if !s.world.Alive(item.ent) {
+			continue
+		}
+		state := item.state
+		state.Flags &^= components.StatePulseFirstCheck
+		state.Flags &^= components.StatePulseNeeded
+		state.Flags |= components.StatePulsePending
+		state.PendingSince = now
+		state.LastPulseCheckTime = now
+		s.metaMapper.Get(item.ent).LastEventTime = now
+
+		if item.first {
+			state.NextCheckTime = now.Add(item.interval)
+		} else {
+			item.hf.Record(now.Sub(item.intended))
+			// Advance from the intended time so jitter does not drift the
+			// schedule; re-anchor if more than one interval behind.
+			next := item.intended.Add(item.interval)
+			if !next.After(now) {
+				next = now.Add(item.interval)
+			}
+			state.NextCheckTime = next
+		}
+		s.stateLogger.LogTransition(item.ent, item.oldState, *state)
+	}
+}
+
+// snapshot recomputes the accuracy percentiles from the collected samples.
+func (s *HighFrequencyPulseSystem) snapshot(now time.Time, monitors int, samples []int32) {
+	stats := AccuracyStats{Monitors: monitors, Samples: len(samples)}
+	if len(samples) > 0 {
+		slices.Sort(samples)
+		stats.P50Ms = percentileMs(samples, 0.50)
+		stats.P95Ms = percentileMs(samples, 0.95)
+		stats.P99Ms = percentileMs(samples, 0.99)
+		stats.MaxMs = samples[len(samples)-1]
+	}
+	s.mu.Lock()
+	s.accuracy = stats
+	s.lastSnapshot = now
+	s.mu.Unlock()
+}
+
+// percentileMs returns the q-th percentile of sorted millisecond samples.
+func percentileMs(sorted []int32, q float64) int32 {
+	idx := int(q * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// Finalize is a no-op for this system.
+func (s *HighFrequencyPulseSystem) Finalize(_ *ecs.World) {}
@@ -0,0 +1,271 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// newHFEntity creates one high-frequency monitor entity in the given world.
+func newHFEntity(world *ecs.World, interval time.Duration) ecs.Entity {
+	mapper := ecs.NewMap5[components.MonitorState, components.MonitorMeta, components.JobStorage, components.PulseConfig, components.HighFrequency](world)
+	return mapper.NewEntity(
+		&components.MonitorState{
+			Flags: components.StatePulseFirstCheck,
+		},
+		&components.MonitorMeta{},
+		&components.JobStorage{
+			PulseJob: newTestPulseJob(0),
+		},
+		&components.PulseConfig{
+			Interval: interval,
+		},
+		&components.HighFrequency{},
+	)
+}
+
+// clearPending simulates the result system completing the in-flight pulse.
+func clearPending(world *ecs.World, ent ecs.Entity) {
+	stateMapper := ecs.NewMap[components.MonitorState](world)
+	state := stateMapper.Get(ent)
+	state.Flags &^= components.StatePulsePending
+}
+
+func TestNewHighFrequencyPulseSystem(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	system := NewHighFrequencyPulseSystem(&world, newMockQueue(100), zap.NewNop().Sugar(), newNoopStateLogger())
+	if system == nil {
+		t.Fatal("NewHighFrequencyPulseSystem returned nil")
+	}
+
+	// Should not panic
+	system.Initialize(&world)
+	system.Finalize(&world)
+}
+
+func TestHighFrequencyPulseSystem_FakeClock_DispatchesEveryInterval(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+	ent := newHFEntity(&world, 250*time.Millisecond)
+
+	mockQ := newMockQueue(100)
+	system := NewHighFrequencyPulseSystem(&world, mockQ, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := base
+	system.now = func() time.Time { return clock }
+
+	// First tick: first check dispatches immediately.
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("Expected 1 enqueued job after first tick, got %d", got)
+	}
+
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	state := stateMapper.Get(ent)
+	if !state.NextCheckTime.Equal(base.Add(250 * time.Millisecond)) {
+		t.Errorf("NextCheckTime = %v, want %v", state.NextCheckTime, base.Add(250*time.Millisecond))
+	}
+
+	// While pending, further ticks do not dispatch.
+	clock = base.Add(300 * time.Millisecond)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("Expected no dispatch while pending, got %d enqueued", got)
+	}
+
+	// Result arrives; the next tick fires 30ms late.
+	clearPending(&world, ent)
+	clock = base.Add(280 * time.Millisecond)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 2 {
+		t.Fatalf("Expected 2 enqueued jobs, got %d", got)
+	}
+
+	// The schedule advances from the intended time, not the dispatch time,
+	// so the 30ms of lateness does not become drift.
+	state = stateMapper.Get(ent)
+	if !state.NextCheckTime.Equal(base.Add(500 * time.Millisecond)) {
+		t.Errorf("NextCheckTime = %v, want %v (drift-free)", state.NextCheckTime, base.Add(500*time.Millisecond))
+	}
+
+	// The lateness was recorded as an accuracy sample.
+	hfMapper := ecs.NewMap[components.HighFrequency](&world)
+	hf := hfMapper.Get(ent)
+	samples := hf.Samples(nil)
+	if len(samples) != 1 || samples[0] != 30 {
+		t.Errorf("Samples = %v, want [30]", samples)
+	}
+}
+
+func TestHighFrequencyPulseSystem_FakeClock_ReanchorsWhenFarBehind(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+	ent := newHFEntity(&world, 250*time.Millisecond)
+
+	mockQ := newMockQueue(100)
+	system := NewHighFrequencyPulseSystem(&world, mockQ, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := base
+	system.now = func() time.Time { return clock }
+
+	system.Update(&world)
+	clearPending(&world, ent)
+
+	// Fall several intervals behind (e.g. a long GC pause). The next check is
+	// re-anchored to now instead of bursting to catch up.
+	clock = base.Add(2 * time.Second)
+	system.Update(&world)
+
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	state := stateMapper.Get(ent)
+	if !state.NextCheckTime.Equal(clock.Add(250 * time.Millisecond)) {
+		t.Errorf("NextCheckTime = %v, want %v (re-anchored)", state.NextCheckTime, clock.Add(250*time.Millisecond))
+	}
+}
+
+func TestHighFrequencyPulseSystem_AccuracySnapshot(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+	ent := newHFEntity(&world, 200*time.Millisecond)
+
+	mockQ := newMockQueue(100)
+	system := NewHighFrequencyPulseSystem(&world, mockQ, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := base
+	system.now = func() time.Time { return clock }
+
+	system.Update(&world)
+
+	// Fire three checks, each a bit later than intended.
+	for i, lateness := range []time.Duration{10, 20, 40} {
+		clearPending(&world, ent)
+		clock = base.Add(time.Duration(i+1)*200*time.Millisecond + lateness*time.Millisecond)
+		system.Update(&world)
+	}
+
+	// Advance past the snapshot interval so percentiles are recomputed.
+	clock = clock.Add(accuracySnapshotInterval)
+	system.Update(&world)
+
+	acc := system.Accuracy()
+	if acc.Monitors != 1 {
+		t.Errorf("Monitors = %d, want 1", acc.Monitors)
+	}
+	if acc.Samples != 3 {
+		t.Errorf("Samples = %d, want 3", acc.Samples)
+	}
+	if acc.P50Ms != 20 {
+		t.Errorf("P50Ms = %d, want 20", acc.P50Ms)
+	}
+	if acc.MaxMs != 40 {
+		t.Errorf("MaxMs = %d, want 40", acc.MaxMs)
+	}
+}
+
+func TestHighFrequencyPulseSystem_IgnoresBulkMonitors(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(100)
+	system := NewHighFrequencyPulseSystem(&world, mockQ, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	// A regular sharded monitor without the HighFrequency component.
+	mapper := ecs.NewMap5[components.MonitorState, components.MonitorMeta, components.JobStorage, components.PulseConfig, components.Shard](&world)
+	mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulseFirstCheck},
+		&components.MonitorMeta{},
+		&components.JobStorage{PulseJob: newTestPulseJob(0)},
+		&components.PulseConfig{Interval: time.Second},
+		&components.Shard{ID: 0},
+	)
+
+	system.Update(&world)
+
+	if got := len(mockQ.getEnqueued()); got != 0 {
+		t.Errorf("Expected 0 enqueued jobs for bulk monitor, got %d", got)
+	}
+}
+
+func TestBatchPulseSystem_SkipsHighFrequencyMonitors(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	mockQ := newMockQueue(100)
+	system := NewBatchPulseSystem(&world, mockQ, 100, zap.NewNop().Sugar(), newNoopStateLogger(), 1)
+	system.Initialize(&world)
+
+	// A high-frequency monitor must not be double-dispatched by the bulk path.
+	mapper := ecs.NewMap6[components.MonitorState, components.MonitorMeta, components.JobStorage, components.PulseConfig, components.Shard, components.HighFrequency](&world)
+	mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulseFirstCheck},
+		&components.MonitorMeta{},
+		&components.JobStorage{PulseJob: newTestPulseJob(0)},
+		&components.PulseConfig{Interval: 250 * time.Millisecond},
+		&components.Shard{ID: 0},
+		&components.HighFrequency{},
+	)
+
+	system.Update(&world)
+
+	if got := len(mockQ.getEnqueued()); got != 0 {
+		t.Errorf("Expected 0 enqueued jobs from bulk system, got %d", got)
+	}
+}
+
+func TestHighFrequencyPulseSystem_RealTime_Smoke(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+	ent := newHFEntity(&world, 100*time.Millisecond)
+
+	mockQ := newMockQueue(100)
+	system := NewHighFrequencyPulseSystem(&world, mockQ, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	// Tick at 10 TPS for half a second with immediate result completion.
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		system.Update(&world)
+		clearPending(&world, ent)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := len(mockQ.getEnqueued()); got < 3 {
+		t.Fatalf("Expected at least 3 dispatches in 500ms at 100ms interval, got %d", got)
+	}
+
+	// Offsets are bounded by tick granularity plus scheduling noise; anything
+	// approaching a full bulk-sweep latency would indicate the fast path broke.
+	hfMapper := ecs.NewMap[components.HighFrequency](&world)
+	for _, offset := range hfMapper.Get(ent).Samples(nil) {
+		if offset > 200 {
+			t.Errorf("Scheduling offset %dms exceeds 200ms bound", offset)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package systems
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/queue"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// InvariantConfig configures the periodic invariant checks run during soaks.
+type InvariantConfig struct {
+	CheckInterval    time.Duration // How often to check (default: 10s)
+	MaxPendingAge    time.Duration // Max time an entity may stay PulsePending (default: 2m)
+	GoroutineSlack   int           // Allowed goroutine growth over baseline (default: 200)
+	GoroutineStrikes int           // Consecutive over-slack samples before violation (default: 3)
+}
+
+// DefaultInvariantConfig returns defaults suitable for a fault-injected soak.
+func DefaultInvariantConfig() InvariantConfig {
+	return InvariantConfig{
+		CheckInterval:    10 * time.Second,
+		MaxPendingAge:    2 * time.Minute,
+		GoroutineSlack:   200,
+		GoroutineStrikes: 3,
+	}
+}
+
+// InvariantSystem periodically verifies soak invariants from inside the tick
+// loop, where world access is safe:
+//
+//   - no entity stays PulsePending longer than MaxPendingAge
+//   - queue and pool counters are consistent across stages
+//   - goroutine count does not grow past the baseline plus slack
+//
+// Violations are recorded and logged; the soak test fails the run when
+// Violations() is non-empty. It runs as the last system so it observes the
+// state the other systems settled on this tick.
+type InvariantSystem struct {
+	world      *ecs.World
+	cfg        InvariantConfig
+	logger     Logger
+	filter     *ecs.Filter1[components.MonitorState]
+	metaMapper *ecs.Map1[components.MonitorMeta]
+
+	queues map[string]queue.Queue
+	pools  map[string]*queue.DynamicWorkerPool
+
+	lastCheck          time.Time
+	baselineGoroutines int
+	goroutineStrikes   int
+
+	mu         sync.Mutex
+	violations []string
+}
+
+// NewInvariantSystem creates an invariant checker for the given world.
+func NewInvariantSystem(world *ecs.World, cfg InvariantConfig, logger Logger) *InvariantSystem {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 10 * time.Second
+	}
+	if cfg.MaxPendingAge <= 0 {
+		cfg.MaxPendingAge = 2 * time.Minute
+	}
+	if cfg.GoroutineSlack <= 0 {
+		cfg.GoroutineSlack = 200
+	}
+	if cfg.GoroutineStrikes <= 0 {
+		cfg.GoroutineStrikes = 3
+	}
+	return &InvariantSystem{
+		world:      world,
+		cfg:        cfg,
+		logger:     logger,
+		filter:     ecs.NewFilter1[components.MonitorState](world),
+		metaMapper: ecs.NewMap1[components.MonitorMeta](world),
+		queues:     map[string]queue.Queue{},
+		pools:      map[string]*queue.DynamicWorkerPool{},
+	}
+}
+
+// AttachQueue registers a queue for counter-consistency checks. Call before Start.
+func (s *InvariantSystem) AttachQueue(name string, q queue.Queue) {
+	s.queues[name] = q
+}
+
+// AttachPool registers a worker pool for counter-consistency checks. Call before Start.
+func (s *InvariantSystem) AttachPool(name string, p *queue.DynamicWorkerPool) {
+	s.pools[name] = p
+}
+
+func (s *InvariantSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+func (s *InvariantSystem) Update(_ *ecs.World) {
+	now := time.Now()
+	if now.Sub(s.lastCheck) < s.cfg.CheckInterval {
+		return
+	}
+	s.lastCheck = now
+
+	s.checkPendingAges(now)
+	s.checkCounters()
+	s.checkGoroutines()
+}
+
+// checkPendingAges flags entities stuck in PulsePending past MaxPendingAge.
+func (s *InvariantSystem) checkPendingAges(now time.Time) {
+	stuck := 0
+	var example string
+	query := s.filter.Query()
+	for query.Next() {
+		state := query.Get()
+		if state.Flags&components.StatePulsePending == 0 {
+			continue
+		}
+		if state.PendingSince.IsZero() {
+			continue
+		}
+		if now.Sub(state.PendingSince) > s.cfg.MaxPendingAge {
+			stuck++
+			if example == "" {
+				example = s.metaMapper.Get(query.Entity()).Name
+			}
+		}
+	}
+	if stuck > 0 {
+		s.violate("%d entities PulsePending longer than %v (e.g. %q)", stuck, s.cfg.MaxPendingAge, example)
+	}
+}
+
+// checkCounters verifies monotonic counter relationships across stages.
+func (s *InvariantSystem) checkCounters() {
+	for name, q := range s.queues {
+		stats := q.Stats()
+		if stats.Dequeued > stats.Enqueued {
+			s.violate("queue %q dequeued (%d) exceeds enqueued (%d)", name, stats.Dequeued, stats.Enqueued)
+		}
+		if stats.Capacity > 0 && stats.QueueDepth > stats.Capacity {
+			s.violate("queue %q depth (%d) exceeds capacity (%d)", name, stats.QueueDepth, stats.Capacity)
+		}
+	}
+	for name, p := range s.pools {
+		stats := p.Stats()
+		if stats.TasksCompleted > stats.TasksSubmitted {
+			s.violate("pool %q completed (%d) exceeds submitted (%d)", name, stats.TasksCompleted, stats.TasksSubmitted)
+		}
+	}
+}
+
+// checkGoroutines flags sustained goroutine growth over the baseline.
+// The baseline is taken at the first check, after startup settles.
+func (s *InvariantSystem) checkGoroutines() {
+	current := runtime.NumGoroutine()
+	if s.baselineGoroutines == 0 {
+		s.baselineGoroutines = current
+		return
+	}
+	if current > s.baselineGoroutines+s.cfg.GoroutineSlack {
+		s.goroutineStrikes++
+		if s.goroutineStrikes >= s.cfg.GoroutineStrikes {
+			s.violate("goroutines grew from %d to %d (slack %d) over %d checks",
+				s.baselineGoroutines, current, s.cfg.GoroutineSlack, s.goroutineStrikes)
+			// Raise the baseline so a single leak is reported once, not every check.
+			s.baselineGoroutines = current
+			s.goroutineStrikes = 0
+		}
+	} else {
+		s.goroutineStrikes = 0
+	}
+}
+
+// violate records and logs one invariant violation.
+func (s *InvariantSystem) violate(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	s.mu.Lock()
+	s.violations = append(s.violations, msg)
+	s.mu.Unlock()
+	s.logger.Errorf("INVARIANT VIOLATION: %s", msg)
+}
+
+// Violations returns a copy of all recorded violations.
+func (s *InvariantSystem) Violations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, len(s.violations))
+	copy(out, s.violations)
+	return out
+}
+
+// Finalize is a no-op for this system.
+func (s *InvariantSystem) Finalize(_ *ecs.World) {}
@@ -0,0 +1,32 @@
+package systems
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Logger is the printf-style logger used by systems.
+// It is an alias for zap.SugaredLogger which provides methods like
+// Infof, Debugf, Warnf, Errorf, and Fatalf for formatted logging.
+type Logger = *zap.SugaredLogger
+
+// LogSystemPerformance emits a structured per-tick performance record for a
+// system at debug level. Structured fields (system, count, duration_ms,
+// rate_per_sec) keep the output machine-parseable next to the rest of the
+// JSON log stream, unlike the printf lines this replaces.
+func LogSystemPerformance(logger Logger, system string, count int, elapsed time.Duration) {
+	if logger == nil || count <= 0 {
+		return
+	}
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(count) / elapsed.Seconds()
+	}
+	logger.Debugw("System performance",
+		"system", system,
+		"count", count,
+		"duration_ms", float64(elapsed.Nanoseconds())/1e6,
+		"rate_per_sec", rate,
+	)
+}
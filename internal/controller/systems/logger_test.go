@@ -0,0 +1,65 @@
+package systems
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newJSONCaptureLogger returns a debug-level sugared logger writing JSON to buf.
+func newJSONCaptureLogger(buf *bytes.Buffer) Logger {
+	encoderCfg := zap.NewProductionEncoderConfig()
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderCfg),
+		zapcore.AddSync(buf),
+		zapcore.DebugLevel,
+	)
+	return zap.New(core).Sugar()
+}
+
+func TestLogSystemPerformance_StructuredJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newJSONCaptureLogger(&buf)
+
+	LogSystemPerformance(logger, "BatchPulseSystem", 250, 50*time.Millisecond)
+	_ = logger.Sync()
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput: %s", err, buf.String())
+	}
+
+	if entry["system"] != "BatchPulseSystem" {
+		t.Errorf("system = %v, want BatchPulseSystem", entry["system"])
+	}
+	if entry["count"] != float64(250) {
+		t.Errorf("count = %v, want 250", entry["count"])
+	}
+	if entry["duration_ms"] != float64(50) {
+		t.Errorf("duration_ms = %v, want 50", entry["duration_ms"])
+	}
+	rate, ok := entry["rate_per_sec"].(float64)
+	if !ok || rate != 5000 {
+		t.Errorf("rate_per_sec = %v, want 5000", entry["rate_per_sec"])
+	}
+	if entry["msg"] != "System performance" {
+		t.Errorf("msg = %v, want %q", entry["msg"], "System performance")
+	}
+}
+
+func TestLogSystemPerformance_SkipsIdleTicks(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newJSONCaptureLogger(&buf)
+
+	LogSystemPerformance(logger, "BatchPulseSystem", 0, time.Millisecond)
+	LogSystemPerformance(nil, "BatchPulseSystem", 10, time.Millisecond)
+	_ = logger.Sync()
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for idle tick or nil logger, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,213 @@
+package systems
+
+import (
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+	"cpra/internal/queue"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// MaintenanceSystem drives scheduled maintenance windows. Each tick it
+// compares the clock against every monitor's compiled windows: entering a
+// window adds the Disabled tag so the dispatch systems skip the monitor
+// and fires a single gray code; leaving the last window removes the tag
+// and schedules an immediate first check. Overlapping windows behave as a
+// union, so only the outermost entry and exit edges fire. A monitor
+// disabled explicitly while inside a window stays disabled when it closes.
+type MaintenanceSystem struct {
+	queue             queue.Queue
+	logger            Logger
+	stateLogger       *StateLogger
+	world             *ecs.World
+	filter            *ecs.Filter2[components.MonitorState, components.Maintenance]
+	stateMapper       *ecs.Map1[components.MonitorState]
+	metaMapper        *ecs.Map1[components.MonitorMeta]
+	maintenanceMapper *ecs.Map1[components.Maintenance]
+	codeConfigMapper  *ecs.Map1[components.CodeConfig]
+	disabledMapper    *ecs.Map1[components.Disabled]
+	explicitMapper    *ecs.Map1[components.ExplicitlyDisabled]
+	scheduleMapper    *ecs.Map1[components.Schedule]
+	registry          *components.ConfigRegistry
+
+	// now is replaceable so tests can cross window boundaries without
+	// sleeping through them.
+	now  func() time.Time
+	gate *StandbyGate // nil unless HA mode is on; closed while standby
+}
+
+// NewMaintenanceSystem creates a new MaintenanceSystem dispatching gray
+// codes to the given code queue.
+func NewMaintenanceSystem(world *ecs.World, q queue.Queue, logger Logger, stateLogger *StateLogger) *MaintenanceSystem {
+	return &MaintenanceSystem{
+		world:             world,
+		queue:             q,
+		logger:            logger,
+		stateLogger:       stateLogger,
+		registry:          components.DefaultConfigRegistry(),
+		filter:            ecs.NewFilter2[components.MonitorState, components.Maintenance](world),
+		stateMapper:       ecs.NewMap1[components.MonitorState](world),
+		metaMapper:        ecs.NewMap1[components.MonitorMeta](world),
+		maintenanceMapper: ecs.NewMap1[components.Maintenance](world),
+		codeConfigMapper:  ecs.NewMap1[components.CodeConfig](world),
+		disabledMapper:    ecs.NewMap1[components.Disabled](world),
+		explicitMapper:    ecs.NewMap1[components.ExplicitlyDisabled](world),
+		scheduleMapper:    ecs.NewMap1[components.Schedule](world),
+		now:               time.Now,
+	}
+}
+
+// SetClock routes the window-edge decisions through c. A nil clock is ignored.
+func (s *MaintenanceSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+// SetStandbyGate makes dispatch honor the HA leadership gate. A nil gate
+// (the default) means always dispatch.
+func (s *MaintenanceSystem) SetStandbyGate(g *StandbyGate) {
+	s.gate = g
+}
+
+func (s *MaintenanceSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+// Update detects window entry and exit edges and applies them.
+func (s *MaintenanceSystem) Update(_ *ecs.World) {
+	if s.gate != nil && s.gate.Standby() {
+		return
+	}
+	now := s.now()
+
+	// Collect edge transitions first: the Disabled tag changes the entity's
+	// archetype, which must not happen while the query is open.
+	var entering, leaving []ecs.Entity
+	query := s.filter.Query()
+	for query.Next() {
+		_, maintenance := query.Get()
+		inWindow := maintenance.InWindow(now)
+		if inWindow == maintenance.Active {
+			continue
+		}
+		if inWindow {
+			entering = append(entering, query.Entity())
+		} else {
+			leaving = append(leaving, query.Entity())
+		}
+	}
+
+	for _, ent := range entering {
+		s.enterMaintenance(ent)
+	}
+	for _, ent := range leaving {
+		s.leaveMaintenance(ent)
+	}
+}
+
+// enterMaintenance suppresses the monitor and announces the window with
+// one gray code.
+func (s *MaintenanceSystem) enterMaintenance(ent ecs.Entity) {
+	if !s.world.Alive(ent) {
+		return
+	}
+	// Mutate the component before the tag add moves the entity to another
+	// archetype and invalidates outstanding component pointers.
+	s.maintenanceMapper.Get(ent).Active = true
+	if !s.disabledMapper.HasAll(ent) {
+		s.disabledMapper.Add(ent, &components.Disabled{})
+	}
+
+	state := s.stateMapper.Get(ent)
+	if state == nil {
+		return
+	}
+	oldState := *state
+
+	// Mirror EntityManager.DisableMonitor: drop pending work so no job
+	// completes into a suppressed monitor. The gray dispatched below sets
+	// CodePending again so its own result is still processed.
+	state.SetPulsePending(false)
+	state.SetInterventionPending(false)
+	state.SetCodePending(false)
+	s.dispatchGray(ent, state)
+
+	s.stateLogger.LogTransition(ent, oldState, *state)
+	s.logger.Infow("Monitor entered maintenance window", "monitor_name", s.metaMapper.Get(ent).Name)
+}
+
+// leaveMaintenance re-enables the monitor once it is outside all windows.
+func (s *MaintenanceSystem) leaveMaintenance(ent ecs.Entity) {
+	if !s.world.Alive(ent) {
+		return
+	}
+	s.maintenanceMapper.Get(ent).Active = false
+	// A monitor still outside its active-hours schedule stays suppressed;
+	// the ActiveHoursSystem releases it when its window opens. An explicit
+	// disable is not the window's to release either: a monitor turned off
+	// via the admin API or a reload mid-window stays off.
+	if s.disabledMapper.HasAll(ent) && !s.outsideActiveHours(ent) && !s.explicitMapper.HasAll(ent) {
+		s.disabledMapper.Remove(ent)
+	}
+
+	state := s.stateMapper.Get(ent)
+	if state == nil {
+		return
+	}
+	oldState := *state
+
+	// Mirror EntityManager.EnableMonitor: check immediately on re-enable.
+	state.SetPulseFirstCheck(true)
+
+	s.stateLogger.LogTransition(ent, oldState, *state)
+	s.logger.Infow("Monitor left maintenance window", "monitor_name", s.metaMapper.Get(ent).Name)
+}
+
+// outsideActiveHours reports whether the monitor has a check schedule that
+// is currently holding it inactive.
+func (s *MaintenanceSystem) outsideActiveHours(ent ecs.Entity) bool {
+	return s.scheduleMapper.HasAll(ent) && !s.scheduleMapper.Get(ent).Active
+}
+
+// dispatchGray enqueues one gray code job announcing the window. Monitors
+// without a dispatchable gray config enter maintenance silently. The job
+// is enqueued directly because BatchCodeSystem no longer sees the entity
+// once the Disabled tag is applied.
+func (s *MaintenanceSystem) dispatchGray(ent ecs.Entity, state *components.MonitorState) {
+	if !s.codeConfigMapper.HasAll(ent) {
+		return
+	}
+	codeConfig := s.codeConfigMapper.Get(ent)
+	cfg, ok := s.registry.Lookup(codeConfig.Configs[components.ColorGray])
+	if !ok || cfg.Notify == "" || !cfg.Dispatch {
+		return
+	}
+
+	schemaCfg := schema.CodeConfig{
+		Dispatch: cfg.Dispatch,
+		Notify:   cfg.Notify,
+		Config:   cfg.Config,
+	}
+	monitorName := s.metaMapper.Get(ent).Name
+	job, err := jobs.CreateCodeJob(monitorName, schemaCfg, ent, components.ColorGray.String())
+	if err != nil || isNilJob(job) {
+		s.logger.Warnw("Failed to create gray code job for maintenance window", "monitor_name", monitorName, "error", err)
+		return
+	}
+	job = fanOutCodeJob(s.registry, codeConfig, job, monitorName, ent, components.ColorGray, false, s.logger)
+	jobs.ApplyLabels(job, s.metaMapper.Get(ent).Labels)
+	if err := s.queue.Enqueue(job); err != nil {
+		s.logger.Warnw("Failed to enqueue maintenance gray code", "monitor_name", monitorName, "error", err)
+		return
+	}
+	state.Flags |= components.StateCodePending
+}
+
+// Finalize is a no-op for this system.
+func (s *MaintenanceSystem) Finalize(_ *ecs.World) {}
@@ -0,0 +1,234 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// newMaintenanceTestEntity creates a monitor with the given maintenance
+// windows and a dispatchable gray code config.
+func newMaintenanceTestEntity(t *testing.T, world *ecs.World, windows []schema.MaintenanceWindow) ecs.Entity {
+	t.Helper()
+
+	compiled := make([]components.MaintenanceWindow, 0, len(windows))
+	for _, w := range windows {
+		cw, err := components.CompileMaintenanceWindow(w)
+		if err != nil {
+			t.Fatalf("CompileMaintenanceWindow failed: %v", err)
+		}
+		compiled = append(compiled, cw)
+	}
+
+	reg := components.DefaultConfigRegistry()
+	codeConfig := components.CodeConfig{}
+	codeConfig.Configs[components.ColorGray] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: true,
+		Config:   &schema.CodeNotificationLog{File: "/var/log/maintenance-test.log"},
+	})
+
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.Maintenance, components.CodeConfig](world)
+	return mapper.NewEntity(
+		&components.MonitorState{},
+		&components.MonitorMeta{Name: "billing-db"},
+		&components.Maintenance{Windows: compiled},
+		&codeConfig,
+	)
+}
+
+// TestMaintenanceSystem_WindowLifecycle crosses a daily window boundary in
+// both directions with an injected clock and checks suppression, the
+// single gray code, and the re-enable edge.
+func TestMaintenanceSystem_WindowLifecycle(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	q := newMockQueue(64)
+	system := NewMaintenanceSystem(&world, q, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	current := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	system.now = func() time.Time { return current }
+
+	ent := newMaintenanceTestEntity(t, &world, []schema.MaintenanceWindow{
+		{Start: "02:00", End: "04:00", Recurrence: "daily"},
+	})
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	disabled := ecs.NewMap1[components.Disabled](&world)
+	maintenance := ecs.NewMap1[components.Maintenance](&world)
+
+	// 01:00: before the window, nothing happens.
+	system.Update(&world)
+	if disabled.HasAll(ent) {
+		t.Fatal("monitor disabled outside the window")
+	}
+	if got := len(q.getEnqueued()); got != 0 {
+		t.Fatalf("enqueued = %d before the window, want 0", got)
+	}
+
+	// 03:00: inside the window, the monitor is suppressed and one gray fires.
+	current = time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC)
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor not disabled inside the window")
+	}
+	if !maintenance.Get(ent).Active {
+		t.Error("maintenance not marked active")
+	}
+	state := stateMapper.Get(ent)
+	if state.Flags&components.StateCodePending == 0 {
+		t.Error("gray code not marked pending")
+	}
+	if got := len(q.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d on entry, want 1", got)
+	}
+
+	// 03:30: still inside, the edge must not fire again.
+	current = time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC)
+	system.Update(&world)
+	if got := len(q.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d while inside, want exactly 1", got)
+	}
+
+	// 05:00: past the window, the monitor re-enables with an immediate check.
+	current = time.Date(2026, 3, 10, 5, 0, 0, 0, time.UTC)
+	system.Update(&world)
+	if disabled.HasAll(ent) {
+		t.Fatal("monitor still disabled after the window")
+	}
+	if maintenance.Get(ent).Active {
+		t.Error("maintenance still marked active")
+	}
+	state = stateMapper.Get(ent)
+	if state.Flags&components.StatePulseFirstCheck == 0 {
+		t.Error("PulseFirstCheck not set on re-enable")
+	}
+	if got := len(q.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d after the window, want 1 (no gray on exit)", got)
+	}
+}
+
+// TestMaintenanceSystem_OverlappingWindowsUnion verifies that overlapping
+// windows behave as a union: one entry edge, and the exit only fires once
+// the monitor is outside every window.
+func TestMaintenanceSystem_OverlappingWindowsUnion(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	q := newMockQueue(64)
+	system := NewMaintenanceSystem(&world, q, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	current := time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC)
+	system.now = func() time.Time { return current }
+
+	ent := newMaintenanceTestEntity(t, &world, []schema.MaintenanceWindow{
+		{Start: "02:00", End: "04:00", Recurrence: "daily"},
+		{Start: "03:00", End: "05:00", Recurrence: "daily"},
+	})
+	disabled := ecs.NewMap1[components.Disabled](&world)
+
+	// Inside both windows: one entry edge, one gray.
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor not disabled inside overlapping windows")
+	}
+	if got := len(q.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d, want 1", got)
+	}
+
+	// 04:30: the first window ended but the second is still open.
+	current = time.Date(2026, 3, 10, 4, 30, 0, 0, time.UTC)
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor re-enabled while still inside the second window")
+	}
+	if got := len(q.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d, want still 1 (no second gray)", got)
+	}
+
+	// 05:30: outside both windows.
+	current = time.Date(2026, 3, 10, 5, 30, 0, 0, time.UTC)
+	system.Update(&world)
+	if disabled.HasAll(ent) {
+		t.Fatal("monitor still disabled outside both windows")
+	}
+}
+
+// TestMaintenanceSystem_ExplicitDisableKeptAcrossWindowClose checks that the
+// exit edge does not lift an explicit disable: a monitor turned off via the
+// admin API (or a reload with enabled: false) while inside a maintenance
+// window must still be disabled after the window closes.
+func TestMaintenanceSystem_ExplicitDisableKeptAcrossWindowClose(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	q := newMockQueue(64)
+	system := NewMaintenanceSystem(&world, q, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	current := time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC)
+	system.now = func() time.Time { return current }
+
+	ent := newMaintenanceTestEntity(t, &world, []schema.MaintenanceWindow{
+		{Start: "02:00", End: "04:00", Recurrence: "daily"},
+	})
+	disabled := ecs.NewMap1[components.Disabled](&world)
+	explicit := ecs.NewMap1[components.ExplicitlyDisabled](&world)
+
+	// 03:00: inside the window, the monitor is suppressed.
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor not disabled inside the window")
+	}
+
+	// The operator disables the monitor mid-window, as DisableMonitor does
+	// when a window already holds the tag: only the marker is added.
+	explicit.Add(ent, &components.ExplicitlyDisabled{})
+
+	// 05:00: the window closed, but the explicit disable is not the
+	// window's to release.
+	current = time.Date(2026, 3, 10, 5, 0, 0, 0, time.UTC)
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("window exit lifted an explicit disable")
+	}
+}
+
+// TestMaintenanceSystem_WeeklyTimezone evaluates a weekly window in its
+// configured zone rather than UTC.
+func TestMaintenanceSystem_WeeklyTimezone(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	q := newMockQueue(64)
+	system := NewMaintenanceSystem(&world, q, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	// 2026-03-08 03:00 UTC is still Saturday 22:00 in New York (EST).
+	current := time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)
+	system.now = func() time.Time { return current }
+
+	ent := newMaintenanceTestEntity(t, &world, []schema.MaintenanceWindow{
+		{Start: "Sat 21:00", End: "Sat 23:00", Recurrence: "weekly", Timezone: "America/New_York"},
+	})
+	disabled := ecs.NewMap1[components.Disabled](&world)
+
+	system.Update(&world)
+	if !disabled.HasAll(ent) {
+		t.Fatal("monitor not disabled; window must be evaluated in America/New_York")
+	}
+
+	// Two hours later it is Sunday 00:00 in New York, outside the window.
+	current = time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC)
+	system.Update(&world)
+	if disabled.HasAll(ent) {
+		t.Fatal("monitor still disabled after the window closed in its zone")
+	}
+}
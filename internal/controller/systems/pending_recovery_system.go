@@ -0,0 +1,236 @@
+package systems
+
+import (
+	"sync/atomic"
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// PendingRecoveryConfig configures the stuck-pending watchdog.
+type PendingRecoveryConfig struct {
+	// ScanEveryTicks is how many ticks pass between scans. Stuck entities
+	// are rare, so the scan does not need to run every tick.
+	ScanEveryTicks int
+	// TimeoutMultiple scales the job timeout into the recovery deadline: a
+	// pending flag older than timeout*TimeoutMultiple is considered lost.
+	// It must leave room for queueing delay and worker retries, or the
+	// watchdog races legitimately slow results.
+	TimeoutMultiple int
+	// BaseTimeout is the timeout used for monitors whose pulse config does
+	// not set one.
+	BaseTimeout time.Duration
+	// CountAsFailure also increments the monitor's failure counters on
+	// recovery, so a worker that keeps crashing on one monitor's job still
+	// escalates instead of silently retrying forever.
+	CountAsFailure bool
+}
+
+// DefaultPendingRecoveryConfig returns watchdog defaults: scan once per
+// second at the default TPS, recover after four times the job timeout, and
+// assume 30s for monitors without an explicit timeout.
+func DefaultPendingRecoveryConfig() PendingRecoveryConfig {
+	return PendingRecoveryConfig{
+		ScanEveryTicks:  100,
+		TimeoutMultiple: 4,
+		BaseTimeout:     30 * time.Second,
+	}
+}
+
+// PendingRecoveryStats is a snapshot of the watchdog's counters.
+type PendingRecoveryStats struct {
+	// Recovered is the total number of stuck pending flags cleared since
+	// start.
+	Recovered uint64 `json:"recovered"`
+	// Deferred is how many recovery batches were postponed because the
+	// command queue was full.
+	Deferred uint64 `json:"deferred"`
+}
+
+// stuckCandidate is one monitor whose pending flag outlasted its recovery
+// deadline, collected during the scan and recovered later through the
+// command buffer.
+type stuckCandidate struct {
+	ent      ecs.Entity
+	deadline time.Duration
+}
+
+// PendingRecoverySystem recovers monitors whose in-flight job result never
+// arrived. A worker that panics after dequeueing, or a result batch dropped
+// on a full channel, leaves its entity in a pending state forever: the
+// dispatch systems skip pending monitors, so the monitor silently stops
+// being checked.
+//
+// Every ScanEveryTicks ticks the system scans for pending flags older than
+// TimeoutMultiple times the monitor's job timeout and submits the recovery
+// through the command buffer, so the mutation lands at the top of a later
+// tick rather than mid-query. Recovery clears the stuck pending flag,
+// restores the Needed flag for intervention and code lanes, and makes pulse
+// monitors due immediately, so the next dispatch sweep retries the job.
+type PendingRecoverySystem struct {
+	world       *ecs.World
+	cfg         PendingRecoveryConfig
+	logger      Logger
+	stateLogger *StateLogger
+	commands    *CommandSystem
+	filter      *ecs.Filter2[components.MonitorState, components.PulseConfig]
+	stateMapper *ecs.Map1[components.MonitorState]
+	metaMapper  *ecs.Map1[components.MonitorMeta]
+	now         func() time.Time // overridable for fake-clock tests
+
+	ticks     int
+	recovered atomic.Uint64
+	deferred  atomic.Uint64
+}
+
+// NewPendingRecoverySystem creates a stuck-pending watchdog submitting its
+// recoveries through the given command system.
+func NewPendingRecoverySystem(world *ecs.World, cfg PendingRecoveryConfig, commands *CommandSystem, logger Logger, stateLogger *StateLogger) *PendingRecoverySystem {
+	if cfg.ScanEveryTicks <= 0 {
+		cfg.ScanEveryTicks = 100
+	}
+	if cfg.TimeoutMultiple <= 0 {
+		cfg.TimeoutMultiple = 4
+	}
+	if cfg.BaseTimeout <= 0 {
+		cfg.BaseTimeout = 30 * time.Second
+	}
+	return &PendingRecoverySystem{
+		world:       world,
+		cfg:         cfg,
+		logger:      logger,
+		stateLogger: stateLogger,
+		commands:    commands,
+		filter: ecs.NewFilter2[components.MonitorState, components.PulseConfig](world).
+			Without(ecs.C[components.Disabled]()),
+		stateMapper: ecs.NewMap1[components.MonitorState](world),
+		metaMapper:  ecs.NewMap1[components.MonitorMeta](world),
+		now:         time.Now,
+	}
+}
+
+// Stats returns a snapshot of the watchdog counters.
+func (s *PendingRecoverySystem) Stats() PendingRecoveryStats {
+	return PendingRecoveryStats{
+		Recovered: s.recovered.Load(),
+		Deferred:  s.deferred.Load(),
+	}
+}
+
+// SetClock routes the stuck-pending timeout decisions through c. A nil clock
+// is ignored.
+func (s *PendingRecoverySystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+func (s *PendingRecoverySystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+const pendingFlags = components.StatePulsePending |
+	components.StateInterventionPending |
+	components.StateCodePending
+
+// Update scans for stuck pending flags every ScanEveryTicks ticks.
+func (s *PendingRecoverySystem) Update(_ *ecs.World) {
+	s.ticks++
+	if s.ticks%s.cfg.ScanEveryTicks != 0 {
+		return
+	}
+
+	now := s.now()
+	var candidates []stuckCandidate
+
+	query := s.filter.Query()
+	for query.Next() {
+		state, pulseCfg := query.Get()
+		if state.Flags&pendingFlags == 0 || state.PendingSince.IsZero() {
+			continue
+		}
+		deadline := s.deadline(pulseCfg)
+		if now.Sub(state.PendingSince) < deadline {
+			continue
+		}
+		candidates = append(candidates, stuckCandidate{ent: query.Entity(), deadline: deadline})
+	}
+
+	if len(candidates) == 0 {
+		return
+	}
+
+	// Recover through the command buffer: the command system drains early
+	// next tick, outside any open query, so the state writes cannot race a
+	// dispatch sweep that is iterating this archetype.
+	if err := s.commands.Submit(func(_ *ecs.World) { s.recover(candidates) }); err != nil {
+		s.deferred.Add(1)
+		s.logger.Warnw("Pending recovery deferred; command queue full", "candidates", len(candidates))
+	}
+}
+
+// deadline returns how long a pending flag may age before recovery.
+func (s *PendingRecoverySystem) deadline(pulseCfg *components.PulseConfig) time.Duration {
+	timeout := pulseCfg.Timeout
+	if timeout <= 0 {
+		timeout = s.cfg.BaseTimeout
+	}
+	return timeout * time.Duration(s.cfg.TimeoutMultiple)
+}
+
+// recover clears the stuck pending flags and reschedules the affected
+// monitors. Runs inside the command system's drain, so the world is safe to
+// mutate. Each candidate is re-checked first: its result may have arrived
+// between the scan and the drain.
+func (s *PendingRecoverySystem) recover(candidates []stuckCandidate) {
+	now := s.now()
+	for _, c := range candidates {
+		if !s.world.Alive(c.ent) || !s.stateMapper.HasAll(c.ent) {
+			continue
+		}
+		state := s.stateMapper.Get(c.ent)
+		stuck := state.Flags & pendingFlags
+		if stuck == 0 || state.PendingSince.IsZero() || now.Sub(state.PendingSince) < c.deadline {
+			continue
+		}
+
+		oldState := *state
+		state.Flags &^= pendingFlags
+		// Pulse retries through the schedule; intervention and code retry
+		// through their Needed flags.
+		if stuck&components.StatePulsePending != 0 {
+			state.NextCheckTime = now
+		}
+		if stuck&components.StateInterventionPending != 0 {
+			state.Flags |= components.StateInterventionNeeded
+		}
+		if stuck&components.StateCodePending != 0 {
+			state.Flags |= components.StateCodeNeeded
+		}
+		if s.cfg.CountAsFailure {
+			state.ConsecutiveFailures++
+			if stuck&components.StatePulsePending != 0 {
+				state.PulseFailures++
+			}
+			if stuck&components.StateInterventionPending != 0 {
+				state.InterventionFailures++
+			}
+		}
+		state.PendingSince = time.Time{}
+
+		s.recovered.Add(1)
+		s.stateLogger.LogTransition(c.ent, oldState, *state)
+		s.logger.Warnw("Recovered stuck pending monitor; job result never arrived",
+			"monitor_name", s.metaMapper.Get(c.ent).Name,
+			"pending_for", now.Sub(oldState.PendingSince),
+			"flags", stuck)
+	}
+}
+
+// Finalize is a no-op for this system.
+func (s *PendingRecoverySystem) Finalize(_ *ecs.World) {}
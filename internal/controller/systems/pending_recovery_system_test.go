@@ -0,0 +1,179 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// newPendingRecoveryFixture builds a watchdog scanning every tick with a
+// controllable clock, wired to a real command system.
+func newPendingRecoveryFixture(world *ecs.World, cfg PendingRecoveryConfig, clock *fakeClock) (*PendingRecoverySystem, *CommandSystem) {
+	commands := NewCommandSystem(DefaultCommandBuffer)
+	system := NewPendingRecoverySystem(world, cfg, commands, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(world)
+	system.now = clock.Now
+	return system, commands
+}
+
+// TestPendingRecoverySystem_RecoversLostPulseResult simulates a pulse job
+// whose result never arrives: the pending flag must be cleared and the
+// monitor made due again once the recovery deadline passes.
+func TestPendingRecoverySystem_RecoversLostPulseResult(t *testing.T) {
+	t.Parallel()
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	clock := newFakeClock(time.Now())
+	system, commands := newPendingRecoveryFixture(&world, PendingRecoveryConfig{
+		ScanEveryTicks:  1,
+		TimeoutMultiple: 2,
+	}, clock)
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	stateMapper := ecs.NewMap1[components.MonitorState](&world)
+
+	now := clock.Now()
+	// The job was dispatched at "now" and its result is lost.
+	ent := mapper.NewEntity(
+		&components.MonitorState{
+			Flags:         components.StatePulsePending,
+			PendingSince:  now,
+			NextCheckTime: now.Add(time.Minute),
+		},
+		&components.MonitorMeta{Name: "lost-result"},
+		&components.PulseConfig{
+			Interval: time.Minute,
+			Timeout:  time.Second,
+		},
+	)
+
+	// Inside the recovery window nothing happens.
+	clock.Advance(time.Second)
+	system.Update(&world)
+	commands.Update(&world)
+	if stateMapper.Get(ent).Flags&components.StatePulsePending == 0 {
+		t.Fatal("Pending flag cleared before the recovery deadline")
+	}
+
+	// Past timeout*multiple the watchdog recovers the monitor.
+	clock.Advance(2 * time.Second)
+	system.Update(&world)
+	commands.Update(&world)
+
+	state := stateMapper.Get(ent)
+	if state.Flags&components.StatePulsePending != 0 {
+		t.Error("Pending flag not cleared after the recovery deadline")
+	}
+	if !state.PendingSince.IsZero() {
+		t.Error("PendingSince not reset on recovery")
+	}
+	if state.NextCheckTime.After(clock.Now()) {
+		t.Errorf("Recovered monitor not due for a re-check: next=%v now=%v", state.NextCheckTime, clock.Now())
+	}
+	if got := system.Stats().Recovered; got != 1 {
+		t.Errorf("Expected 1 recovery, got %d", got)
+	}
+	if state.ConsecutiveFailures != 0 {
+		t.Errorf("Recovery counted as failure without CountAsFailure, failures=%d", state.ConsecutiveFailures)
+	}
+}
+
+// TestPendingRecoverySystem_RestoresNeededFlags checks that intervention and
+// code lanes retry through their Needed flags rather than the schedule.
+func TestPendingRecoverySystem_RestoresNeededFlags(t *testing.T) {
+	t.Parallel()
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	clock := newFakeClock(time.Now())
+	system, commands := newPendingRecoveryFixture(&world, PendingRecoveryConfig{
+		ScanEveryTicks:  1,
+		TimeoutMultiple: 2,
+		CountAsFailure:  true,
+	}, clock)
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	stateMapper := ecs.NewMap1[components.MonitorState](&world)
+
+	ent := mapper.NewEntity(
+		&components.MonitorState{
+			Flags:        components.StateInterventionPending | components.StateCodePending,
+			PendingSince: clock.Now(),
+		},
+		&components.MonitorMeta{Name: "stuck-lanes"},
+		&components.PulseConfig{Timeout: time.Second},
+	)
+
+	clock.Advance(3 * time.Second)
+	system.Update(&world)
+	commands.Update(&world)
+
+	state := stateMapper.Get(ent)
+	if state.Flags&(components.StateInterventionPending|components.StateCodePending) != 0 {
+		t.Error("Pending flags not cleared")
+	}
+	if state.Flags&components.StateInterventionNeeded == 0 {
+		t.Error("InterventionNeeded not restored; the intervention would be lost")
+	}
+	if state.Flags&components.StateCodeNeeded == 0 {
+		t.Error("CodeNeeded not restored; the alert would be lost")
+	}
+	if state.ConsecutiveFailures != 1 || state.InterventionFailures != 1 {
+		t.Errorf("CountAsFailure counters wrong: consecutive=%d intervention=%d",
+			state.ConsecutiveFailures, state.InterventionFailures)
+	}
+	if state.PulseFailures != 0 {
+		t.Errorf("Pulse failure counted for non-pulse recovery: %d", state.PulseFailures)
+	}
+}
+
+// TestPendingRecoverySystem_ResultBeatsRecovery verifies the re-check inside
+// the command drain: a result that lands between the scan and the drain must
+// win over the recovery.
+func TestPendingRecoverySystem_ResultBeatsRecovery(t *testing.T) {
+	t.Parallel()
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	clock := newFakeClock(time.Now())
+	system, commands := newPendingRecoveryFixture(&world, PendingRecoveryConfig{
+		ScanEveryTicks:  1,
+		TimeoutMultiple: 2,
+	}, clock)
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	stateMapper := ecs.NewMap1[components.MonitorState](&world)
+
+	next := clock.Now().Add(time.Minute)
+	ent := mapper.NewEntity(
+		&components.MonitorState{
+			Flags:         components.StatePulsePending,
+			PendingSince:  clock.Now(),
+			NextCheckTime: next,
+		},
+		&components.MonitorMeta{Name: "late-result"},
+		&components.PulseConfig{Timeout: time.Second},
+	)
+
+	clock.Advance(3 * time.Second)
+	system.Update(&world)
+
+	// The late result arrives before the command drain and clears pending.
+	state := stateMapper.Get(ent)
+	state.Flags &^= components.StatePulsePending
+	state.PendingSince = time.Time{}
+
+	commands.Update(&world)
+
+	if got := system.Stats().Recovered; got != 0 {
+		t.Errorf("Recovered a monitor whose result already arrived, recovered=%d", got)
+	}
+	if !stateMapper.Get(ent).NextCheckTime.Equal(next) {
+		t.Error("Recovery rescheduled a monitor whose result already arrived")
+	}
+}
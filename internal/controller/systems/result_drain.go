@@ -0,0 +1,107 @@
+package systems
+
+import (
+	"sync/atomic"
+	"time"
+
+	"cpra/internal/jobs"
+)
+
+// Per-tick result budgets. At the default 100 TPS a tick is 10ms, so the
+// time budget leaves half the tick for the schedule and dispatch systems
+// even when a worker burst lands a huge backlog at once. The result budget
+// is a hard cap for configurations where applying results is unusually
+// expensive relative to wall time.
+const (
+	DefaultResultBudget     = 50000
+	DefaultResultTimeBudget = 5 * time.Millisecond
+)
+
+// resultDrain buffers result batches received from the router and applies
+// them under a per-tick budget, carrying the remainder over to the next
+// tick. Without the budget a single burst of batches would be applied in
+// one Update, starving the other systems of their tick; without the carry
+// the excess would sit in the channel adding end-to-end latency.
+//
+// The published counters are atomics because the metrics endpoint reads
+// them from outside the ECS loop.
+type resultDrain struct {
+	maxResults int
+	maxTime    time.Duration
+
+	pending      [][]jobs.Result
+	pendingCount int
+
+	consumedLastTick atomic.Int64
+	backlogDepth     atomic.Int64
+}
+
+func newResultDrain() resultDrain {
+	return resultDrain{
+		maxResults: DefaultResultBudget,
+		maxTime:    DefaultResultTimeBudget,
+	}
+}
+
+// setBudget overrides the per-tick budgets. Non-positive values keep the
+// current setting for that dimension.
+func (d *resultDrain) setBudget(maxResults int, maxTime time.Duration) {
+	if maxResults > 0 {
+		d.maxResults = maxResults
+	}
+	if maxTime > 0 {
+		d.maxTime = maxTime
+	}
+}
+
+// add appends a received batch to the pending backlog.
+func (d *resultDrain) add(batch []jobs.Result) {
+	if len(batch) == 0 {
+		return
+	}
+	d.pending = append(d.pending, batch)
+	d.pendingCount += len(batch)
+}
+
+// apply feeds pending batches to fn until the tick's budget is spent,
+// splitting a batch when the result cap lands inside it. It refreshes the
+// consumed and backlog counters for the metrics endpoint.
+func (d *resultDrain) apply(fn func([]jobs.Result)) {
+	start := time.Now()
+	budget := d.maxResults
+	consumed := 0
+
+	for len(d.pending) > 0 && budget > 0 {
+		batch := d.pending[0]
+		if len(batch) > budget {
+			fn(batch[:budget])
+			d.pending[0] = batch[budget:]
+			d.pendingCount -= budget
+			consumed += budget
+			break
+		}
+		fn(batch)
+		budget -= len(batch)
+		d.pendingCount -= len(batch)
+		consumed += len(batch)
+		d.pending[0] = nil
+		d.pending = d.pending[1:]
+
+		if time.Since(start) >= d.maxTime {
+			break
+		}
+	}
+	if len(d.pending) == 0 {
+		// Release the drained backing array instead of growing it forever.
+		d.pending = nil
+	}
+
+	d.consumedLastTick.Store(int64(consumed))
+	d.backlogDepth.Store(int64(d.pendingCount))
+}
+
+// ConsumedLastTick reports how many results the last Update applied.
+func (d *resultDrain) ConsumedLastTick() int64 { return d.consumedLastTick.Load() }
+
+// Backlog reports how many received results are still waiting to be applied.
+func (d *resultDrain) Backlog() int64 { return d.backlogDepth.Load() }
@@ -0,0 +1,171 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+func TestResultDrain_BudgetSplitsBatch(t *testing.T) {
+	t.Parallel()
+
+	d := newResultDrain()
+	d.setBudget(30, time.Second)
+	d.add(make([]jobs.Result, 100))
+
+	var applied []int
+	fn := func(batch []jobs.Result) { applied = append(applied, len(batch)) }
+
+	d.apply(fn)
+	if d.ConsumedLastTick() != 30 || d.Backlog() != 70 {
+		t.Fatalf("after first tick: consumed=%d backlog=%d, want 30/70", d.ConsumedLastTick(), d.Backlog())
+	}
+
+	// Three more ticks drain the remainder: 30, 30, 10.
+	d.apply(fn)
+	d.apply(fn)
+	d.apply(fn)
+	want := []int{30, 30, 30, 10}
+	if len(applied) != len(want) {
+		t.Fatalf("apply calls = %v, want %v", applied, want)
+	}
+	for i := range want {
+		if applied[i] != want[i] {
+			t.Fatalf("apply calls = %v, want %v", applied, want)
+		}
+	}
+	if d.Backlog() != 0 || d.ConsumedLastTick() != 10 {
+		t.Errorf("after drain: consumed=%d backlog=%d, want 10/0", d.ConsumedLastTick(), d.Backlog())
+	}
+}
+
+func TestResultDrain_TimeBudgetStopsBetweenBatches(t *testing.T) {
+	t.Parallel()
+
+	d := newResultDrain()
+	d.setBudget(1000, time.Millisecond)
+	d.add(make([]jobs.Result, 5))
+	d.add(make([]jobs.Result, 5))
+
+	calls := 0
+	d.apply(func(batch []jobs.Result) {
+		calls++
+		time.Sleep(2 * time.Millisecond) // each batch overruns the time budget
+	})
+	if calls != 1 {
+		t.Fatalf("expected the time budget to stop after 1 batch, applied %d", calls)
+	}
+	if d.Backlog() != 5 {
+		t.Fatalf("backlog = %d, want 5", d.Backlog())
+	}
+}
+
+// TestBatchPulseResultSystem_DrainBudgetCarryOver pushes 50 result batches
+// into the channel at once and verifies the system applies all of them
+// within the budgeted number of ticks, each exactly once, with the final
+// MonitorState reflecting every result.
+func TestBatchPulseResultSystem_DrainBudgetCarryOver(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	const (
+		batches   = 50
+		batchSize = 20
+		budget    = 100 // results per tick -> 10 ticks for the burst
+	)
+	results := make(chan []jobs.Result, batches)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+	resultSystem.SetResultBudget(budget, time.Second)
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.PulseConfig](&world)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	metaMapper := ecs.NewMap[components.MonitorMeta](&world)
+	ents := make([]ecs.Entity, 0, batches*batchSize)
+	for i := 0; i < batches*batchSize; i++ {
+		ents = append(ents, mapper.NewEntity(
+			&components.MonitorState{Flags: components.StatePulsePending},
+			&components.MonitorMeta{},
+			&components.PulseConfig{UnhealthyThreshold: 1, HealthyThreshold: 1},
+		))
+	}
+	for b := 0; b < batches; b++ {
+		batch := make([]jobs.Result, batchSize)
+		for i := range batch {
+			batch[i] = jobs.Result{Ent: ents[b*batchSize+i]}
+		}
+		results <- batch
+	}
+
+	resultSystem.Update(&world)
+	if got := resultSystem.ConsumedLastTick(); got != budget {
+		t.Fatalf("first tick consumed %d results, want %d", got, budget)
+	}
+	if got := resultSystem.ResultBacklog(); got != int64(batches*batchSize-budget) {
+		t.Fatalf("first tick backlog = %d, want %d", got, batches*batchSize-budget)
+	}
+
+	ticks := 1
+	for ; resultSystem.ResultBacklog() > 0; ticks++ {
+		if ticks > batches*batchSize/budget {
+			t.Fatalf("backlog not drained within the budgeted %d ticks", batches*batchSize/budget)
+		}
+		resultSystem.Update(&world)
+	}
+	if ticks != batches*batchSize/budget {
+		t.Errorf("drained in %d ticks, expected exactly %d", ticks, batches*batchSize/budget)
+	}
+
+	// Every result applied exactly once: pending cleared, one success each.
+	for _, ent := range ents {
+		state := stateMapper.Get(ent)
+		if state.Flags&components.StatePulsePending != 0 {
+			t.Fatal("entity still pending after drain")
+		}
+		if metaMapper.Get(ent).LastSuccessTime.IsZero() {
+			t.Fatal("LastSuccessTime not stamped by the applied result")
+		}
+	}
+}
+
+// TestBatchCodeResultSystem_DrainCarryOver verifies the code result system
+// carries a burst past its budget over to later ticks instead of dropping it.
+func TestBatchCodeResultSystem_DrainCarryOver(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	results := make(chan []jobs.Result, 2)
+	resultSystem := NewBatchCodeResultSystem(&world, results, zap.NewNop().Sugar(), newNoopStateLogger())
+	resultSystem.SetResultBudget(1, time.Second)
+
+	mapper := ecs.NewMap3[components.MonitorState, components.MonitorMeta, components.CodeStatus](&world)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	ents := []ecs.Entity{
+		mapper.NewEntity(&components.MonitorState{Flags: components.StateCodePending}, &components.MonitorMeta{}, &components.CodeStatus{}),
+		mapper.NewEntity(&components.MonitorState{Flags: components.StateCodePending}, &components.MonitorMeta{}, &components.CodeStatus{}),
+	}
+	results <- []jobs.Result{{Ent: ents[0], Payload: jobs.Payload{Color: "red"}}}
+	results <- []jobs.Result{{Ent: ents[1], Payload: jobs.Payload{Color: "red"}}}
+
+	resultSystem.Update(&world)
+	if resultSystem.ResultBacklog() != 1 {
+		t.Fatalf("backlog = %d, want 1", resultSystem.ResultBacklog())
+	}
+	resultSystem.Update(&world)
+	if resultSystem.ResultBacklog() != 0 {
+		t.Fatalf("backlog = %d, want 0", resultSystem.ResultBacklog())
+	}
+	for _, ent := range ents {
+		if stateMapper.Get(ent).Flags&components.StateCodePending != 0 {
+			t.Fatal("entity still code-pending after drain")
+		}
+	}
+}
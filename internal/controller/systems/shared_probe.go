@@ -0,0 +1,158 @@
+package systems
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// probeKey identifies an outbound probe precisely enough that two monitors
+// with equal keys would put identical requests on the wire and judge the
+// response identically. It covers the type, the normalized target, and every
+// config field that changes the request or its interpretation — auth,
+// headers, assertions, retries — plus the timeout. Monitors whose type has
+// no key (push-based heartbeats, docker, or types with payload semantics too
+// entangled to share) return "" and never join a group.
+func probeKey(cfg *components.PulseConfig) string {
+	if cfg == nil || cfg.Config == nil {
+		return ""
+	}
+	switch c := cfg.Config.(type) {
+	case *schema.PulseHTTPConfig:
+		method := strings.ToUpper(c.Method)
+		if method == "" {
+			method = "GET"
+		}
+		var headers strings.Builder
+		for _, h := range c.Headers {
+			fmt.Fprintf(&headers, "%s=%s;", h.Name, h.Value)
+		}
+		auth := ""
+		if c.Auth != nil {
+			auth = fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+				c.Auth.Type, c.Auth.Username, c.Auth.Password, c.Auth.PasswordEnv, c.Auth.Token, c.Auth.TokenEnv)
+		}
+		return fmt.Sprintf("http|%s|%s|%v|%d|%s|%s|%s|%s|%s|%s",
+			method, c.Url, cfg.Timeout, c.Retries, c.ExpectedStatus, c.BodyContains, c.BodyRegex, c.Proxy, headers.String(), auth)
+	case *schema.PulseTCPConfig:
+		return fmt.Sprintf("tcp|%s:%d|%s|%v|%d", strings.ToLower(c.Host), c.Port, c.IPFamily, cfg.Timeout, c.Retries)
+	case *schema.PulseICMPConfig:
+		return fmt.Sprintf("icmp|%s|%s|%d|%t|%v|%d", strings.ToLower(c.Host), c.IPFamily, c.Count, c.Privilege, cfg.Timeout, c.Retries)
+	default:
+		return ""
+	}
+}
+
+// SharedProbeStats summarizes the current grouping for logs and diagnostics.
+type SharedProbeStats struct {
+	Groups    int // probe keys shared by two or more monitors
+	Followers int // monitors whose probe runs on another entity
+}
+
+// SharedProbeRegistry groups monitors whose probes are wire-identical so the
+// check runs once per group. After every (re)load, Rebuild picks the monitor
+// with the smallest interval in each group as the leader — it keeps probing
+// on its own schedule — and tags the rest with components.ProbeFollower,
+// which the pulse schedule and dispatch filters exclude. The pulse result
+// system consults Followers to fan a leader's result out; each follower
+// applies results at its own interval, keeping independent failure
+// accounting.
+//
+// The registry is rebuilt on the load path and read on the tick path, so
+// access is guarded.
+type SharedProbeRegistry struct {
+	mu        sync.RWMutex
+	followers map[ecs.Entity][]ecs.Entity // leader -> followers
+}
+
+// NewSharedProbeRegistry creates an empty registry.
+func NewSharedProbeRegistry() *SharedProbeRegistry {
+	return &SharedProbeRegistry{followers: make(map[ecs.Entity][]ecs.Entity)}
+}
+
+// Followers returns the entities subscribed to leader's probe results, or
+// nil when the entity leads no group.
+func (r *SharedProbeRegistry) Followers(leader ecs.Entity) []ecs.Entity {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.followers[leader]
+}
+
+// Rebuild regroups the whole world by probe key. Existing ProbeFollower
+// tags are cleared first so monitors whose group dissolved (reload removed
+// the leader, dedupe turned off) resume probing themselves.
+func (r *SharedProbeRegistry) Rebuild(world *ecs.World) SharedProbeStats {
+	followerMapper := ecs.NewMap1[components.ProbeFollower](world)
+
+	// Collect first, mutate after: Ark forbids component changes during a query.
+	var stale []ecs.Entity
+	clearQuery := ecs.NewFilter1[components.ProbeFollower](world).Query()
+	for clearQuery.Next() {
+		stale = append(stale, clearQuery.Entity())
+	}
+	for _, ent := range stale {
+		followerMapper.Remove(ent)
+	}
+
+	type member struct {
+		ent      ecs.Entity
+		interval time.Duration
+	}
+	groups := make(map[string][]member)
+	filter := ecs.NewFilter1[components.PulseConfig](world).
+		Without(ecs.C[components.Disabled](), ecs.C[components.Heartbeat](), ecs.C[components.HighFrequency]())
+	query := filter.Query()
+	for query.Next() {
+		cfg := query.Get()
+		if cfg.NoDedupe {
+			continue
+		}
+		key := probeKey(cfg)
+		if key == "" {
+			continue
+		}
+		groups[key] = append(groups[key], member{ent: query.Entity(), interval: cfg.Interval})
+	}
+
+	followers := make(map[ecs.Entity][]ecs.Entity)
+	var stats SharedProbeStats
+	var toTag []ecs.Entity
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		// The smallest interval leads, so the group probes at least as
+		// often as its most demanding member.
+		leader := 0
+		for i, m := range members {
+			if m.interval < members[leader].interval {
+				leader = i
+			}
+		}
+		stats.Groups++
+		group := make([]ecs.Entity, 0, len(members)-1)
+		for i, m := range members {
+			if i == leader {
+				continue
+			}
+			group = append(group, m.ent)
+			toTag = append(toTag, m.ent)
+			stats.Followers++
+		}
+		followers[members[leader].ent] = group
+	}
+	for _, ent := range toTag {
+		followerMapper.Add(ent, &components.ProbeFollower{})
+	}
+
+	r.mu.Lock()
+	r.followers = followers
+	r.mu.Unlock()
+	return stats
+}
@@ -0,0 +1,119 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// newHTTPPulseConfig builds a PulseConfig probing url at the given interval,
+// matching what the entity mapper constructs for an http monitor.
+func newHTTPPulseConfig(url string, interval time.Duration) *components.PulseConfig {
+	return &components.PulseConfig{
+		Type:     "http",
+		Interval: interval,
+		Timeout:  500 * time.Millisecond,
+		Config:   &schema.PulseHTTPConfig{Url: url},
+	}
+}
+
+func TestSharedProbeRegistry_Rebuild(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+	mapper := ecs.NewMap1[components.PulseConfig](&world)
+	followerMapper := ecs.NewMap1[components.ProbeFollower](&world)
+
+	// Three monitors on one URL; the 1s one must lead. A fourth on the same
+	// URL opted out via dedupe: false, and a fifth on a different URL.
+	fast := mapper.NewEntity(newHTTPPulseConfig("http://shared.test/", time.Second))
+	slow := mapper.NewEntity(newHTTPPulseConfig("http://shared.test/", 5*time.Second))
+	slower := mapper.NewEntity(newHTTPPulseConfig("http://shared.test/", 10*time.Second))
+	optOut := newHTTPPulseConfig("http://shared.test/", time.Second)
+	optOut.NoDedupe = true
+	loner := mapper.NewEntity(optOut)
+	other := mapper.NewEntity(newHTTPPulseConfig("http://other.test/", time.Second))
+
+	registry := NewSharedProbeRegistry()
+	stats := registry.Rebuild(&world)
+
+	if stats.Groups != 1 || stats.Followers != 2 {
+		t.Fatalf("stats = %+v, want 1 group with 2 followers", stats)
+	}
+	followers := registry.Followers(fast)
+	if len(followers) != 2 {
+		t.Fatalf("Followers(fast) = %v, want the two slower monitors", followers)
+	}
+	for _, ent := range []ecs.Entity{slow, slower} {
+		if !followerMapper.HasAll(ent) {
+			t.Errorf("entity %v missing ProbeFollower tag", ent)
+		}
+	}
+	for _, ent := range []ecs.Entity{fast, loner, other} {
+		if followerMapper.HasAll(ent) {
+			t.Errorf("entity %v unexpectedly tagged ProbeFollower", ent)
+		}
+		if got := registry.Followers(ent); ent != fast && got != nil {
+			t.Errorf("Followers(%v) = %v, want nil", ent, got)
+		}
+	}
+
+	// Retarget the slow monitor: its old group shrinks to leader+one and the
+	// stale tag must be cleared on rebuild.
+	mapper.Get(slow).Config = &schema.PulseHTTPConfig{Url: "http://moved.test/"}
+	stats = registry.Rebuild(&world)
+	if stats.Groups != 1 || stats.Followers != 1 {
+		t.Fatalf("stats after retarget = %+v, want 1 group with 1 follower", stats)
+	}
+	if followerMapper.HasAll(slow) {
+		t.Error("retargeted monitor still tagged ProbeFollower")
+	}
+	if !followerMapper.HasAll(slower) {
+		t.Error("remaining follower lost its tag")
+	}
+}
+
+func TestProbeKey_UnsharedFields(t *testing.T) {
+	t.Parallel()
+
+	base := newHTTPPulseConfig("http://shared.test/", time.Second)
+	key := probeKey(base)
+	if key == "" {
+		t.Fatal("http config produced empty probe key")
+	}
+
+	// Anything that changes the request or its interpretation must change
+	// the key: same URL is not enough to share a probe.
+	differentMethod := newHTTPPulseConfig("http://shared.test/", time.Second)
+	differentMethod.Config = &schema.PulseHTTPConfig{Url: "http://shared.test/", Method: "HEAD"}
+	differentTimeout := newHTTPPulseConfig("http://shared.test/", time.Second)
+	differentTimeout.Timeout = time.Second
+	differentAuth := newHTTPPulseConfig("http://shared.test/", time.Second)
+	differentAuth.Config = &schema.PulseHTTPConfig{Url: "http://shared.test/", Auth: &schema.HTTPAuth{Type: "bearer", Token: "t"}}
+
+	for name, cfg := range map[string]*components.PulseConfig{
+		"method":  differentMethod,
+		"timeout": differentTimeout,
+		"auth":    differentAuth,
+	} {
+		if probeKey(cfg) == key {
+			t.Errorf("config differing in %s shares a probe key", name)
+		}
+	}
+
+	// Intervals are a scheduling concern, not a wire concern.
+	sameWire := newHTTPPulseConfig("http://shared.test/", time.Minute)
+	if probeKey(sameWire) != key {
+		t.Error("interval change altered the probe key")
+	}
+
+	// Heartbeats are push-based and never keyed.
+	if got := probeKey(&components.PulseConfig{Type: "heartbeat", Config: &schema.PulseHeartbeatConfig{}}); got != "" {
+		t.Errorf("heartbeat probe key = %q, want empty", got)
+	}
+}
@@ -0,0 +1,231 @@
+package systems
+
+import (
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+	"cpra/internal/queue"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Multiwindow burn-rate thresholds, following the standard SRE alerting
+// setup for a 30-day budget: a critical burn of 14.4x spends 2% of the
+// budget per hour, a warning burn of 6x spends 5% in six hours. Each
+// severity pairs a long window (sustained burn) with a short one (still
+// burning now), so a brief spike that already recovered never pages.
+const (
+	sloCriticalBurn        = 14.4
+	sloCriticalShortWindow = 5 * time.Minute
+	sloCriticalLongWindow  = time.Hour
+
+	sloWarnBurn        = 6.0
+	sloWarnShortWindow = 30 * time.Minute
+	sloWarnLongWindow  = 6 * time.Hour
+
+	// defaultSLOEvalInterval is how often burn rates are recomputed; the
+	// minute-bucket granularity makes anything faster pointless.
+	defaultSLOEvalInterval = time.Minute
+
+	// sloMinSamples is the minimum number of checks the long window must
+	// cover before its burn rate is trusted; a monitor's first failed
+	// check is the consecutive-failure logic's business, not a burn.
+	sloMinSamples = 10
+)
+
+// SLOSystem turns error-budget burn into code alerts. Periodically it reads
+// each monitor's SLO outcome rings and computes burn rates over the paired
+// short/long windows: a critical burn fires the red code path, a warning
+// burn the yellow one, each with the burn numbers in the payload. This runs
+// independently of the consecutive-failure logic — a monitor can be "up"
+// right now and still be torching its monthly budget.
+//
+// Alerts are edge-triggered on the severity level, so a sustained burn
+// fires once when it crosses a threshold rather than every evaluation; the
+// level decays silently when the burn subsides. Jobs are enqueued directly
+// (like maintenance grays) because the burn numbers must ride on the job,
+// which the BatchCodeSystem dispatch path cannot carry.
+type SLOSystem struct {
+	queue            queue.Queue
+	logger           Logger
+	stateLogger      *StateLogger
+	world            *ecs.World
+	filter           *ecs.Filter2[components.MonitorState, components.SLO]
+	codeConfigMapper *ecs.Map1[components.CodeConfig]
+	metaMapper       *ecs.Map1[components.MonitorMeta]
+	registry         *components.ConfigRegistry
+
+	evalEvery time.Duration
+	lastEval  time.Time
+
+	// now is replaceable so tests can drive the burn windows without
+	// waiting through them.
+	now  func() time.Time
+	gate *StandbyGate // nil unless HA mode is on; closed while standby
+}
+
+// NewSLOSystem creates a new SLOSystem dispatching burn alerts to the given
+// code queue.
+func NewSLOSystem(world *ecs.World, q queue.Queue, logger Logger, stateLogger *StateLogger) *SLOSystem {
+	return &SLOSystem{
+		world:       world,
+		queue:       q,
+		logger:      logger,
+		stateLogger: stateLogger,
+		registry:    components.DefaultConfigRegistry(),
+		filter: ecs.NewFilter2[components.MonitorState, components.SLO](world).
+			Without(ecs.C[components.Disabled]()),
+		codeConfigMapper: ecs.NewMap1[components.CodeConfig](world),
+		metaMapper:       ecs.NewMap1[components.MonitorMeta](world),
+		evalEvery:        defaultSLOEvalInterval,
+		now:              time.Now,
+	}
+}
+
+// SetClock routes the burn evaluations through c. A nil clock is ignored.
+func (s *SLOSystem) SetClock(c clock.Clock) {
+	if c != nil {
+		s.now = c.Now
+	}
+}
+
+// SetStandbyGate makes dispatch honor the HA leadership gate. A nil gate
+// (the default) means always dispatch.
+func (s *SLOSystem) SetStandbyGate(g *StandbyGate) {
+	s.gate = g
+}
+
+func (s *SLOSystem) Initialize(_ *ecs.World) {
+	if s.filter != nil {
+		s.filter.Register()
+	}
+}
+
+// Update recomputes burn rates once per evaluation interval and dispatches
+// alerts for monitors whose burn severity rose.
+func (s *SLOSystem) Update(_ *ecs.World) {
+	if s.gate != nil && s.gate.Standby() {
+		return
+	}
+	now := s.now()
+	if !s.lastEval.IsZero() && now.Sub(s.lastEval) < s.evalEvery {
+		return
+	}
+	s.lastEval = now
+
+	query := s.filter.Query()
+	for query.Next() {
+		ent := query.Entity()
+		state, slo := query.Get()
+
+		level, burnShort, burnLong := s.evaluate(slo, now)
+		if level == slo.AlertLevel {
+			continue
+		}
+		if level < slo.AlertLevel {
+			// Burn subsided; decay silently so the next crossing can fire.
+			slo.AlertLevel = level
+			s.logger.Infow("Monitor error-budget burn subsided",
+				"monitor_name", s.metaMapper.Get(ent).Name, "level", level)
+			continue
+		}
+
+		// FSM guard: with a code already pending or needed, leave the level
+		// unchanged so the alert is retried on a later evaluation.
+		if state.Flags&(components.StateCodePending|components.StateCodeNeeded) != 0 {
+			continue
+		}
+
+		color := components.ColorYellow
+		if level == 2 {
+			color = components.ColorRed
+		}
+		oldState := *state
+		if s.dispatchBurn(ent, state, slo, color, level, burnShort, burnLong) {
+			slo.AlertLevel = level
+			s.stateLogger.LogTransition(ent, oldState, *state)
+		}
+	}
+}
+
+// evaluate classifies the monitor's current burn: 2 when both critical
+// windows burn critically, 1 when both warning windows burn at warning
+// rate, 0 otherwise. The short and long burns of the matched severity are
+// returned for the alert payload.
+func (s *SLOSystem) evaluate(slo *components.SLO, now time.Time) (level uint8, burnShort, burnLong float64) {
+	critShort, _ := slo.Burn(now, sloCriticalShortWindow)
+	critLong, critSamples := slo.Burn(now, sloCriticalLongWindow)
+	if critSamples >= sloMinSamples && critShort >= sloCriticalBurn && critLong >= sloCriticalBurn {
+		return 2, critShort, critLong
+	}
+	warnShort, _ := slo.Burn(now, sloWarnShortWindow)
+	warnLong, warnSamples := slo.Burn(now, sloWarnLongWindow)
+	if warnSamples >= sloMinSamples && warnShort >= sloWarnBurn && warnLong >= sloWarnBurn {
+		return 1, warnShort, warnLong
+	}
+	return 0, 0, 0
+}
+
+// dispatchBurn enqueues one code job for the burn alert, with the burn
+// numbers annotated onto its result payload. Monitors without a
+// dispatchable config for the color burn silently; that still counts as
+// dispatched so the level does not retry forever.
+func (s *SLOSystem) dispatchBurn(ent ecs.Entity, state *components.MonitorState, slo *components.SLO, color components.ColorCode, level uint8, burnShort, burnLong float64) bool {
+	if !s.codeConfigMapper.HasAll(ent) {
+		return true
+	}
+	codeConfig := s.codeConfigMapper.Get(ent)
+	monitorName := s.metaMapper.Get(ent).Name
+	cfg, ok := s.registry.Lookup(codeConfig.Configs[color])
+	if !ok || cfg.Notify == "" || !cfg.Dispatch {
+		s.logger.Debugw("Monitor has no dispatchable code config for burn alert",
+			"monitor_name", monitorName, "color", color.String())
+		return true
+	}
+
+	schemaCfg := schema.CodeConfig{
+		Dispatch: cfg.Dispatch,
+		Notify:   cfg.Notify,
+		Config:   cfg.Config,
+	}
+	job, err := jobs.CreateCodeJob(monitorName, schemaCfg, ent, color.String())
+	if err != nil || isNilJob(job) {
+		s.logger.Warnw("Failed to create burn alert code job", "monitor_name", monitorName, "error", err)
+		return true
+	}
+	job = fanOutCodeJob(s.registry, codeConfig, job, monitorName, ent, color, false, s.logger)
+	jobs.ApplyLabels(job, s.metaMapper.Get(ent).Labels)
+
+	shortWindow, longWindow := sloWarnShortWindow, sloWarnLongWindow
+	if level == 2 {
+		shortWindow, longWindow = sloCriticalShortWindow, sloCriticalLongWindow
+	}
+	job = &jobs.AnnotatedJob{
+		Job: job,
+		Extras: map[string]interface{}{
+			"slo_target":       slo.Target * 100,
+			"slo_burn_short":   burnShort,
+			"slo_burn_long":    burnLong,
+			"slo_window_short": shortWindow.String(),
+			"slo_window_long":  longWindow.String(),
+			"slo_window":       slo.Window.String(),
+		},
+	}
+
+	if err := s.queue.Enqueue(job); err != nil {
+		s.logger.Warnw("Failed to enqueue burn alert code", "monitor_name", monitorName, "error", err)
+		return false
+	}
+	state.Flags |= components.StateCodePending
+	s.logger.Warnw("Monitor error-budget burn alert",
+		"monitor_name", monitorName, "color", color.String(),
+		"burn_short", burnShort, "burn_long", burnLong,
+		"window_short", shortWindow.String(), "window_long", longWindow.String())
+	return true
+}
+
+// Finalize is a no-op for this system.
+func (s *SLOSystem) Finalize(_ *ecs.World) {}
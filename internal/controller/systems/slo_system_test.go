@@ -0,0 +1,217 @@
+package systems
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// newSLOTestEntity creates a monitor with the given objective and
+// dispatchable yellow and red log configs.
+func newSLOTestEntity(t *testing.T, world *ecs.World, slo *components.SLO) ecs.Entity {
+	t.Helper()
+
+	reg := components.DefaultConfigRegistry()
+	codeConfig := components.CodeConfig{}
+	codeConfig.Configs[components.ColorYellow] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: true,
+		Config:   &schema.CodeNotificationLog{File: "/var/log/slo-yellow.log"},
+	})
+	codeConfig.Configs[components.ColorRed] = reg.GetOrAdd(components.ColorCodeConfig{
+		Notify:   "log",
+		Dispatch: true,
+		Config:   &schema.CodeNotificationLog{File: "/var/log/slo-red.log"},
+	})
+
+	mapper := ecs.NewMap4[components.MonitorState, components.MonitorMeta, components.SLO, components.CodeConfig](world)
+	return mapper.NewEntity(&components.MonitorState{}, &components.MonitorMeta{Name: "api-gw"}, slo, &codeConfig)
+}
+
+// executeBurnJob runs an enqueued burn alert and returns its result payload.
+func executeBurnJob(t *testing.T, enqueued interface{}) jobs.Payload {
+	t.Helper()
+	job, ok := enqueued.(*jobs.AnnotatedJob)
+	if !ok {
+		t.Fatalf("enqueued job is %T, want *jobs.AnnotatedJob", enqueued)
+	}
+	res := job.Execute(context.Background())
+	if res.Err != nil {
+		t.Fatalf("burn alert job failed: %v", res.Err)
+	}
+	return res.Payload
+}
+
+// TestSLOSystem_BurnAlertLifecycle drives a 99% success pattern against a
+// 99.9% objective with an injected clock: the warning burn fires yellow, a
+// full outage escalates to red, and the level decays once the burn ages out.
+func TestSLOSystem_BurnAlertLifecycle(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	q := newMockQueue(64)
+	system := NewSLOSystem(&world, q, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	current := base
+	system.now = func() time.Time { return current }
+
+	slo, err := components.CompileSLO(schema.SLO{Availability: 99.9, Window: "720h"})
+	if err != nil {
+		t.Fatalf("CompileSLO failed: %v", err)
+	}
+	ent := newSLOTestEntity(t, &world, slo)
+	sloMapper := ecs.NewMap1[components.SLO](&world)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	tracker := sloMapper.Get(ent)
+
+	// One failed check per hundred over the last hour: a 1% error rate
+	// burns a 0.1% budget at 10x — warning territory, not critical.
+	for i := 0; i < 600; i++ {
+		tracker.Record(base.Add(-time.Hour+time.Duration(i)*6*time.Second), i%100 != 0, false)
+	}
+
+	system.Update(&world)
+	enqueued := q.getEnqueued()
+	if len(enqueued) != 1 {
+		t.Fatalf("enqueued = %d after warning burn, want 1", len(enqueued))
+	}
+	payload := executeBurnJob(t, enqueued[0])
+	if payload.Color != "yellow" {
+		t.Errorf("alert color = %q, want yellow", payload.Color)
+	}
+	if burn, ok := payload.Extra("slo_burn_long").(float64); !ok || burn < sloWarnBurn {
+		t.Errorf("slo_burn_long = %v, want >= %v", payload.Extra("slo_burn_long"), sloWarnBurn)
+	}
+	if got := payload.Extra("slo_window_long"); got != sloWarnLongWindow.String() {
+		t.Errorf("slo_window_long = %v, want %v", got, sloWarnLongWindow)
+	}
+	if tracker.AlertLevel != 1 {
+		t.Fatalf("AlertLevel = %d after warning, want 1", tracker.AlertLevel)
+	}
+
+	// A sustained warning burn must not re-fire on the next evaluation.
+	current = base.Add(time.Minute)
+	system.Update(&world)
+	if got := len(q.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d on sustained burn, want still 1", got)
+	}
+
+	// The yellow's result came back; a full outage then escalates to red.
+	stateMapper.Get(ent).Flags &^= components.StateCodePending
+	for i := 0; i < 180; i++ {
+		tracker.Record(base.Add(time.Duration(i)*2*time.Second), false, false)
+	}
+	current = base.Add(6 * time.Minute)
+	system.Update(&world)
+	enqueued = q.getEnqueued()
+	if len(enqueued) != 2 {
+		t.Fatalf("enqueued = %d after critical burn, want 2", len(enqueued))
+	}
+	payload = executeBurnJob(t, enqueued[1])
+	if payload.Color != "red" {
+		t.Errorf("escalated alert color = %q, want red", payload.Color)
+	}
+	if burn, ok := payload.Extra("slo_burn_short").(float64); !ok || burn < sloCriticalBurn {
+		t.Errorf("slo_burn_short = %v, want >= %v", payload.Extra("slo_burn_short"), sloCriticalBurn)
+	}
+	if tracker.AlertLevel != 2 {
+		t.Fatalf("AlertLevel = %d after critical, want 2", tracker.AlertLevel)
+	}
+
+	// Seven hours later the outage has aged out of every window: the level
+	// decays silently, with no recovery alert.
+	stateMapper.Get(ent).Flags &^= components.StateCodePending
+	current = base.Add(7 * time.Hour)
+	system.Update(&world)
+	if got := len(q.getEnqueued()); got != 2 {
+		t.Fatalf("enqueued = %d after burn subsided, want still 2", got)
+	}
+	if tracker.AlertLevel != 0 {
+		t.Fatalf("AlertLevel = %d after burn subsided, want 0", tracker.AlertLevel)
+	}
+}
+
+// TestSLOSystem_WithinBudgetNoAlert keeps the error rate under the burn
+// thresholds and expects silence.
+func TestSLOSystem_WithinBudgetNoAlert(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	q := newMockQueue(64)
+	system := NewSLOSystem(&world, q, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	system.now = func() time.Time { return base }
+
+	// 99% objective leaves a 1% budget; a 0.5% error rate burns at 0.5x.
+	slo, err := components.CompileSLO(schema.SLO{Availability: 99, Window: "168h"})
+	if err != nil {
+		t.Fatalf("CompileSLO failed: %v", err)
+	}
+	ent := newSLOTestEntity(t, &world, slo)
+	tracker := ecs.NewMap1[components.SLO](&world).Get(ent)
+	for i := 0; i < 600; i++ {
+		tracker.Record(base.Add(-time.Hour+time.Duration(i)*6*time.Second), i%200 != 0, false)
+	}
+
+	system.Update(&world)
+	if got := len(q.getEnqueued()); got != 0 {
+		t.Fatalf("enqueued = %d within budget, want 0", got)
+	}
+	if tracker.AlertLevel != 0 {
+		t.Fatalf("AlertLevel = %d within budget, want 0", tracker.AlertLevel)
+	}
+}
+
+// TestSLOSystem_DefersWhileCodeInFlight leaves the alert level untouched
+// when a code job is already pending, so the burn alert retries later.
+func TestSLOSystem_DefersWhileCodeInFlight(t *testing.T) {
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	q := newMockQueue(64)
+	system := NewSLOSystem(&world, q, zap.NewNop().Sugar(), newNoopStateLogger())
+	system.Initialize(&world)
+
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	current := base
+	system.now = func() time.Time { return current }
+
+	slo, err := components.CompileSLO(schema.SLO{Availability: 99.9, Window: "720h"})
+	if err != nil {
+		t.Fatalf("CompileSLO failed: %v", err)
+	}
+	ent := newSLOTestEntity(t, &world, slo)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+	tracker := ecs.NewMap1[components.SLO](&world).Get(ent)
+	for i := 0; i < 600; i++ {
+		tracker.Record(base.Add(-time.Hour+time.Duration(i)*6*time.Second), i%100 != 0, false)
+	}
+
+	stateMapper.Get(ent).Flags |= components.StateCodePending
+	system.Update(&world)
+	if got := len(q.getEnqueued()); got != 0 {
+		t.Fatalf("enqueued = %d with code in flight, want 0", got)
+	}
+	if tracker.AlertLevel != 0 {
+		t.Fatalf("AlertLevel = %d while deferred, want 0", tracker.AlertLevel)
+	}
+
+	// Once the in-flight code settles, the next evaluation fires.
+	stateMapper.Get(ent).Flags &^= components.StateCodePending
+	current = base.Add(time.Minute)
+	system.Update(&world)
+	if got := len(q.getEnqueued()); got != 1 {
+		t.Fatalf("enqueued = %d after code settled, want 1", got)
+	}
+}
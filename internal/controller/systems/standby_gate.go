@@ -0,0 +1,28 @@
+package systems
+
+import "sync/atomic"
+
+// StandbyGate holds the dispatch systems back while this instance is the HA
+// standby. The gated systems return from Update without scheduling anything,
+// so a standby never enqueues checks, interventions, or notifications, while
+// the result systems keep draining so nothing is stranded across a handover.
+//
+// The flag is written by the controller's leadership loop and read from the
+// ECS tick goroutine, hence the atomic. A new gate starts in standby; the
+// leadership loop opens it on the first successful campaign.
+type StandbyGate struct {
+	standby atomic.Bool
+}
+
+// NewStandbyGate returns a gate in the standby (closed) position.
+func NewStandbyGate() *StandbyGate {
+	g := &StandbyGate{}
+	g.standby.Store(true)
+	return g
+}
+
+// SetStandby flips the gate; true holds dispatch back.
+func (g *StandbyGate) SetStandby(standby bool) { g.standby.Store(standby) }
+
+// Standby reports whether dispatch is currently held back.
+func (g *StandbyGate) Standby() bool { return g.standby.Load() }
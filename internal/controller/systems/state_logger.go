@@ -0,0 +1,148 @@
+package systems
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// StateLogger provides a dedicated logger for tracking ECS state transitions.
+// It is designed to provide a clear and detailed audit trail of what is happening to each monitor.
+type StateLogger struct {
+	logger    *slog.Logger
+	mu        sync.Mutex
+	debugMode bool
+	counters  StateCounters
+}
+
+// StateCounters maintains population counts for the in-flight state flags.
+// Every system routes its flag changes through LogTransition, so the counts
+// track the fleet without ever scanning it; Controller.Snapshot reads them
+// with plain atomic loads.
+type StateCounters struct {
+	pulsePending        atomic.Int64
+	interventionPending atomic.Int64
+	codePending         atomic.Int64
+}
+
+// Apply adjusts the counters for one flag transition.
+func (c *StateCounters) Apply(oldFlags, newFlags uint32) {
+	applyFlagDelta(&c.pulsePending, oldFlags, newFlags, components.StatePulsePending)
+	applyFlagDelta(&c.interventionPending, oldFlags, newFlags, components.StateInterventionPending)
+	applyFlagDelta(&c.codePending, oldFlags, newFlags, components.StateCodePending)
+}
+
+// Release drops any counted flags for an entity leaving the world, so a
+// monitor removed with a job still in flight does not skew the counts.
+func (c *StateCounters) Release(flags uint32) {
+	c.Apply(flags, 0)
+}
+
+// PulsePending returns the number of monitors with a pulse job in flight.
+func (c *StateCounters) PulsePending() int64 { return c.pulsePending.Load() }
+
+// InterventionPending returns the number of monitors with an intervention in flight.
+func (c *StateCounters) InterventionPending() int64 { return c.interventionPending.Load() }
+
+// CodePending returns the number of monitors with a code alert in flight.
+func (c *StateCounters) CodePending() int64 { return c.codePending.Load() }
+
+func applyFlagDelta(n *atomic.Int64, oldFlags, newFlags, flag uint32) {
+	switch {
+	case oldFlags&flag == 0 && newFlags&flag != 0:
+		n.Add(1)
+	case oldFlags&flag != 0 && newFlags&flag == 0:
+		n.Add(-1)
+	}
+}
+
+// NewStateLogger creates a new StateLogger.
+func NewStateLogger(debugMode bool) *StateLogger {
+	var h slog.Handler
+	if debugMode {
+		h = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		})
+	} else {
+		// In non-debug mode, use a no-op handler to discard all logs.
+		h = slog.NewJSONHandler(&noopWriter{}, &slog.HandlerOptions{})
+	}
+	return &StateLogger{
+		logger:    slog.New(h),
+		debugMode: debugMode,
+	}
+}
+
+// Counters exposes the flag population counts fed by LogTransition.
+func (l *StateLogger) Counters() *StateCounters {
+	return &l.counters
+}
+
+// LogTransition logs a state transition for a monitor.
+func (l *StateLogger) LogTransition(entity ecs.Entity, oldState, newState components.MonitorState) {
+	// The counters update regardless of debug mode; the log line below is
+	// the optional part.
+	l.counters.Apply(oldState.Flags, newState.Flags)
+	if !l.debugMode {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// The entity ID identifies the monitor here; the name lives on the cold
+	// MonitorMeta component, which the hot transition path does not touch.
+	l.logger.Info("state transition",
+		"entity_id", entity.ID(),
+		"old_state", formatState(oldState.Flags),
+		"new_state", formatState(newState.Flags),
+	)
+}
+
+// formatState converts the state flags into a human-readable string.
+func formatState(flags uint32) string {
+	var states []string
+	if flags&components.StatePulseNeeded != 0 {
+		states = append(states, "PulseNeeded")
+	}
+	if flags&components.StatePulsePending != 0 {
+		states = append(states, "PulsePending")
+	}
+	if flags&components.StateInterventionNeeded != 0 {
+		states = append(states, "InterventionNeeded")
+	}
+	if flags&components.StateInterventionPending != 0 {
+		states = append(states, "InterventionPending")
+	}
+	if flags&components.StateCodeNeeded != 0 {
+		states = append(states, "CodeNeeded")
+	}
+	if flags&components.StateCodePending != 0 {
+		states = append(states, "CodePending")
+	}
+	if flags&components.StateIncidentOpen != 0 {
+		states = append(states, "IncidentOpen")
+	}
+	if flags&components.StateVerifying != 0 {
+		states = append(states, "Verifying")
+	}
+	if flags&components.StateDependencySuppressed != 0 {
+		states = append(states, "DependencySuppressed")
+	}
+	if len(states) == 0 {
+		return "Idle"
+	}
+	return fmt.Sprintf("%v", states)
+}
+
+// noopWriter is a writer that does nothing.
+type noopWriter struct{}
+
+func (w *noopWriter) Write(p []byte) (n int, err error) {
+	return len(p), nil
+}
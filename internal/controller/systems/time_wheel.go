@@ -0,0 +1,175 @@
+package systems
+
+import (
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Default wheel geometry: 1024 buckets of 250ms cover a span of 4m16s.
+// Monitors due further out than the span sit in an overflow set and are
+// promoted into a bucket as their due time approaches.
+const (
+	defaultWheelBuckets = 1024
+	defaultWheelTick    = 250 * time.Millisecond
+)
+
+// wheelOverflow marks an entry parked in the overflow set rather than a bucket.
+const wheelOverflow = -1
+
+type wheelEntry struct {
+	bucket int // bucket index, or wheelOverflow
+	due    time.Time
+}
+
+// pulseTimeWheel indexes monitors by their next due time so the dispatch
+// system only visits entities whose time has arrived, instead of scanning
+// every monitor on every tick.
+//
+// Each bucket covers one tick of wall time; an entity due k ticks ahead of
+// the wheel's cursor lands k+1 buckets ahead, so it is collected on the
+// first cursor step at or after its due time — never early, and at most one
+// tick late. Entries due beyond the wheel's span wait in the overflow set,
+// which is swept every quarter span; because overflow entries are by
+// definition at least a full span away, the sweep always promotes them into
+// a bucket well before they come due.
+//
+// Each entity has at most one slot: Schedule on an already-tracked entity
+// moves it, so a monitor can never be collected twice for the same due time.
+// The wheel is not safe for concurrent use; like the systems that own it,
+// it runs on the single scheduler goroutine.
+type pulseTimeWheel struct {
+	tick    time.Duration
+	span    time.Duration
+	buckets []map[ecs.Entity]struct{}
+	// overflow holds entries due beyond the wheel span, keyed to their due
+	// time so the periodic sweep can promote them without a full rebuild.
+	overflow  map[ecs.Entity]time.Time
+	entries   map[ecs.Entity]wheelEntry
+	pos       int       // bucket the cursor last drained
+	cursor    time.Time // wall time the cursor has advanced to
+	nextSweep time.Time
+}
+
+// newPulseTimeWheel creates a wheel of the given geometry anchored at now.
+// Non-positive buckets or tick fall back to the defaults.
+func newPulseTimeWheel(buckets int, tick time.Duration, now time.Time) *pulseTimeWheel {
+	if buckets <= 0 {
+		buckets = defaultWheelBuckets
+	}
+	if tick <= 0 {
+		tick = defaultWheelTick
+	}
+	w := &pulseTimeWheel{
+		tick:     tick,
+		span:     time.Duration(buckets) * tick,
+		buckets:  make([]map[ecs.Entity]struct{}, buckets),
+		overflow: make(map[ecs.Entity]time.Time),
+		entries:  make(map[ecs.Entity]wheelEntry),
+		cursor:   now,
+	}
+	for i := range w.buckets {
+		w.buckets[i] = make(map[ecs.Entity]struct{})
+	}
+	return w
+}
+
+// Schedule inserts ent with the given due time, moving it if it is already
+// tracked. Past due times land in the next bucket the cursor will drain.
+func (w *pulseTimeWheel) Schedule(ent ecs.Entity, due time.Time) {
+	w.Remove(ent)
+
+	delta := due.Sub(w.cursor)
+	if delta < 0 {
+		delta = 0
+	}
+	ticksAhead := int(delta / w.tick)
+	if ticksAhead >= len(w.buckets)-1 {
+		w.overflow[ent] = due
+		w.entries[ent] = wheelEntry{bucket: wheelOverflow, due: due}
+		return
+	}
+	idx := (w.pos + ticksAhead + 1) % len(w.buckets)
+	w.buckets[idx][ent] = struct{}{}
+	w.entries[ent] = wheelEntry{bucket: idx, due: due}
+}
+
+// Remove forgets ent if it is tracked. Safe to call for untracked entities.
+func (w *pulseTimeWheel) Remove(ent ecs.Entity) {
+	e, ok := w.entries[ent]
+	if !ok {
+		return
+	}
+	if e.bucket == wheelOverflow {
+		delete(w.overflow, ent)
+	} else {
+		delete(w.buckets[e.bucket], ent)
+	}
+	delete(w.entries, ent)
+}
+
+// DueTime reports the due time ent is currently scheduled for.
+func (w *pulseTimeWheel) DueTime(ent ecs.Entity) (time.Time, bool) {
+	e, ok := w.entries[ent]
+	return e.due, ok
+}
+
+// Len reports how many entities the wheel is tracking.
+func (w *pulseTimeWheel) Len() int { return len(w.entries) }
+
+// Due advances the cursor to now and returns every entity whose due time has
+// arrived, removing them from the wheel. The caller re-Schedules entities
+// that should fire again.
+func (w *pulseTimeWheel) Due(now time.Time) []ecs.Entity {
+	var due []ecs.Entity
+
+	// Sweep the overflow set periodically: promote entries that are now
+	// within the span, and collect any that matured outright (only possible
+	// after a large clock jump).
+	if !now.Before(w.nextSweep) {
+		// Collect promotions first: when the cursor trails now by almost a
+		// tick, Schedule can land an entry back in the overflow set, and
+		// inserting into the map being ranged over is unspecified.
+		var promote []ecs.Entity
+		for ent, d := range w.overflow {
+			if !d.After(now) {
+				delete(w.overflow, ent)
+				delete(w.entries, ent)
+				due = append(due, ent)
+			} else if d.Sub(now) < w.span-w.tick {
+				promote = append(promote, ent)
+			}
+		}
+		for _, ent := range promote {
+			w.Schedule(ent, w.overflow[ent])
+		}
+		w.nextSweep = now.Add(w.span / 4)
+	}
+
+	steps := int(now.Sub(w.cursor) / w.tick)
+	if steps <= 0 {
+		return due
+	}
+	if steps >= len(w.buckets) {
+		// More than a full revolution elapsed: every bucketed entry is due.
+		for i := range w.buckets {
+			for ent := range w.buckets[i] {
+				due = append(due, ent)
+				delete(w.buckets[i], ent)
+				delete(w.entries, ent)
+			}
+		}
+		w.cursor = now
+		return due
+	}
+	for i := 0; i < steps; i++ {
+		w.pos = (w.pos + 1) % len(w.buckets)
+		for ent := range w.buckets[w.pos] {
+			due = append(due, ent)
+			delete(w.buckets[w.pos], ent)
+			delete(w.entries, ent)
+		}
+	}
+	w.cursor = w.cursor.Add(time.Duration(steps) * w.tick)
+	return due
+}
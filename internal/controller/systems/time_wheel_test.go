@@ -0,0 +1,351 @@
+package systems
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/controller/components"
+
+	"github.com/mlange-42/ark/ecs"
+	"go.uber.org/zap"
+)
+
+// =============================================================================
+// pulseTimeWheel Tests
+// =============================================================================
+
+// TestPulseTimeWheel_NoSkipNoDouble schedules entities across the whole wheel
+// span plus the overflow set, then advances in irregular steps and checks that
+// every entity pops exactly once, never before its due time, and within the
+// wheel's granularity after it.
+func TestPulseTimeWheel_NoSkipNoDouble(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	const (
+		buckets = 16
+		tick    = 10 * time.Millisecond
+		maxStep = 15 * time.Millisecond
+	)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	wheel := newPulseTimeWheel(buckets, tick, base)
+
+	// Dues from the past through several spans ahead, deliberately crossing
+	// bucket boundaries and landing well inside overflow territory.
+	dues := make(map[ecs.Entity]time.Time)
+	for i := 0; i < 200; i++ {
+		ent := world.NewEntity()
+		due := base.Add(time.Duration(i-3) * 7 * time.Millisecond)
+		wheel.Schedule(ent, due)
+		dues[ent] = due
+	}
+	if wheel.Len() != len(dues) {
+		t.Fatalf("expected %d tracked entities, got %d", len(dues), wheel.Len())
+	}
+
+	popped := make(map[ecs.Entity]time.Time)
+	now := base
+	steps := []time.Duration{3, 11, 7, 15, 1, 9, 13, 5}
+	for i := 0; len(popped) < len(dues); i++ {
+		now = now.Add(steps[i%len(steps)] * time.Millisecond)
+		for _, ent := range wheel.Due(now) {
+			if prev, dup := popped[ent]; dup {
+				t.Fatalf("entity %v popped twice: at %v and %v", ent, prev, now)
+			}
+			popped[ent] = now
+		}
+		if now.Sub(base) > 5*time.Second {
+			t.Fatalf("gave up after 5s simulated time with %d of %d popped", len(popped), len(dues))
+		}
+	}
+
+	for ent, due := range dues {
+		at, ok := popped[ent]
+		if !ok {
+			t.Errorf("entity %v (due %v) never popped", ent, due.Sub(base))
+			continue
+		}
+		if due.After(base) && at.Before(due) {
+			t.Errorf("entity %v popped early: due %v, popped %v", ent, due.Sub(base), at.Sub(base))
+		}
+		if late := at.Sub(due); late > 2*tick+maxStep {
+			t.Errorf("entity %v popped %v late", ent, late)
+		}
+	}
+	if wheel.Len() != 0 {
+		t.Errorf("expected empty wheel, still tracking %d", wheel.Len())
+	}
+}
+
+// TestPulseTimeWheel_RescheduleMoves verifies that Schedule on a tracked
+// entity relocates it — both earlier and later, including into and out of
+// the overflow set — without it ever popping from its old slot.
+func TestPulseTimeWheel_RescheduleMoves(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	const tick = 10 * time.Millisecond
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	wheel := newPulseTimeWheel(16, tick, base)
+
+	// Move earlier: an entity due at 100ms pulled in to 20ms.
+	early := world.NewEntity()
+	wheel.Schedule(early, base.Add(100*time.Millisecond))
+	wheel.Schedule(early, base.Add(20*time.Millisecond))
+	if due, ok := wheel.DueTime(early); !ok || !due.Equal(base.Add(20*time.Millisecond)) {
+		t.Fatalf("expected due time 20ms after rebucket, got %v (tracked=%v)", due, ok)
+	}
+	if got := wheel.Due(base.Add(40 * time.Millisecond)); len(got) != 1 || got[0] != early {
+		t.Fatalf("expected early entity to pop at 40ms, got %v", got)
+	}
+	if got := wheel.Due(base.Add(150 * time.Millisecond)); len(got) != 0 {
+		t.Fatalf("entity popped again from its old slot: %v", got)
+	}
+
+	// Move later: an entity due at 200ms pushed out past the span into
+	// overflow, then collected around its new time.
+	late := world.NewEntity()
+	wheel.Schedule(late, base.Add(200*time.Millisecond))
+	wheel.Schedule(late, base.Add(500*time.Millisecond))
+	if got := wheel.Due(base.Add(300 * time.Millisecond)); len(got) != 0 {
+		t.Fatalf("pushed-out entity popped at its old time: %v", got)
+	}
+	if got := wheel.Due(base.Add(600 * time.Millisecond)); len(got) != 1 || got[0] != late {
+		t.Fatalf("expected late entity to pop by 600ms, got %v", got)
+	}
+	if wheel.Len() != 0 {
+		t.Errorf("expected empty wheel, still tracking %d", wheel.Len())
+	}
+}
+
+func TestPulseTimeWheel_Remove(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	wheel := newPulseTimeWheel(16, 10*time.Millisecond, base)
+
+	bucketed := world.NewEntity()
+	overflowed := world.NewEntity()
+	wheel.Schedule(bucketed, base.Add(50*time.Millisecond))
+	wheel.Schedule(overflowed, base.Add(time.Hour))
+	wheel.Remove(bucketed)
+	wheel.Remove(overflowed)
+	wheel.Remove(bucketed) // untracked removal is a no-op
+
+	if wheel.Len() != 0 {
+		t.Fatalf("expected empty wheel after removals, tracking %d", wheel.Len())
+	}
+	if got := wheel.Due(base.Add(2 * time.Hour)); len(got) != 0 {
+		t.Fatalf("removed entities popped: %v", got)
+	}
+}
+
+// =============================================================================
+// BatchPulseSystem time-wheel mode Tests
+// =============================================================================
+
+// newWheelTestSystem builds a wheel-mode BatchPulseSystem on a manual clock
+// with n monitors at the given interval, all awaiting their first check.
+func newWheelTestSystem(world *ecs.World, q *mockQueue, clk *clock.Manual, n int, interval time.Duration) (*BatchPulseSystem, []ecs.Entity) {
+	system := NewBatchPulseSystem(world, q, 100, zap.NewNop().Sugar(), newNoopStateLogger(), 1)
+	system.SetClock(clk)
+	system.EnableTimeWheel()
+	system.Initialize(world)
+
+	mapper := ecs.NewMap5[components.MonitorState, components.MonitorMeta, components.JobStorage, components.PulseConfig, components.Shard](world)
+	ents := make([]ecs.Entity, n)
+	for i := 0; i < n; i++ {
+		ents[i] = mapper.NewEntity(
+			&components.MonitorState{Flags: components.StatePulseFirstCheck},
+			&components.MonitorMeta{Name: fmt.Sprintf("wheel-%d", i)},
+			&components.JobStorage{PulseJob: newTestPulseJob(i)},
+			&components.PulseConfig{Interval: interval},
+			&components.Shard{ID: 0},
+		)
+	}
+	return system, ents
+}
+
+// TestBatchPulseSystem_TimeWheelDispatch drives the wheel-mode system on a
+// manual clock through a full cycle: seed via reconciliation, first-check
+// dispatch, no re-dispatch while the job is pending, and re-dispatch one
+// interval after the result lands.
+func TestBatchPulseSystem_TimeWheelDispatch(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	clk := clock.NewManual(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	mockQ := newMockQueue(100)
+	system, ents := newWheelTestSystem(&world, mockQ, clk, 3, time.Second)
+	stateMapper := ecs.NewMap[components.MonitorState](&world)
+
+	// First Update seeds the wheel; nothing pops until the clock moves.
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 0 {
+		t.Fatalf("expected no dispatch before the clock advances, got %d", got)
+	}
+
+	clk.Advance(defaultWheelTick)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 3 {
+		t.Fatalf("expected 3 first-check dispatches, got %d", got)
+	}
+	dispatchTime := clk.Now()
+	metaMapper := ecs.NewMap[components.MonitorMeta](&world)
+	for _, ent := range ents {
+		state := stateMapper.Get(ent)
+		name := metaMapper.Get(ent).Name
+		if state.Flags&components.StatePulsePending == 0 {
+			t.Errorf("monitor %s not pending after dispatch", name)
+		}
+		if !state.NextCheckTime.Equal(dispatchTime.Add(time.Second)) {
+			t.Errorf("monitor %s NextCheckTime = %v, want %v", name, state.NextCheckTime, dispatchTime.Add(time.Second))
+		}
+	}
+
+	// While results are in flight the due monitors must only cycle through
+	// the retry slot, never dispatch again.
+	clk.Advance(time.Second)
+	system.Update(&world)
+	clk.Advance(wheelRetryDelay)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 3 {
+		t.Fatalf("expected no re-dispatch while pending, got %d total", got)
+	}
+
+	// Results land: pending clears and the next cycle dispatches again.
+	for _, ent := range ents {
+		stateMapper.Get(ent).Flags &^= components.StatePulsePending
+	}
+	clk.Advance(wheelRetryDelay)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 6 {
+		t.Fatalf("expected 6 total dispatches after results landed, got %d", got)
+	}
+}
+
+// TestBatchPulseSystem_TimeWheelQueueFullRetry checks that a monitor whose
+// enqueue was refused stays in the wheel and dispatches once room opens up.
+func TestBatchPulseSystem_TimeWheelQueueFullRetry(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	clk := clock.NewManual(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	mockQ := newMockQueue(10)
+	system, _ := newWheelTestSystem(&world, mockQ, clk, 1, time.Second)
+
+	// Seed, then saturate the queue past the 90% enqueue guard.
+	system.Update(&world)
+	mockQ.setDepth(9)
+	clk.Advance(defaultWheelTick)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 0 {
+		t.Fatalf("expected dispatch refused at 90%% depth, got %d", got)
+	}
+
+	mockQ.setDepth(0)
+	// One extra tick: a due time on a bucket boundary fires on the next step.
+	clk.Advance(wheelRetryDelay + defaultWheelTick)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("expected 1 dispatch after queue drained, got %d", got)
+	}
+}
+
+// TestBatchPulseSystem_TimeWheelDisabled checks that a monitor disabled after
+// bucketing is dropped when popped and re-seeded once re-enabled.
+func TestBatchPulseSystem_TimeWheelDisabled(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	defer world.Reset()
+
+	clk := clock.NewManual(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	mockQ := newMockQueue(100)
+	system, ents := newWheelTestSystem(&world, mockQ, clk, 1, time.Second)
+	disabledMapper := ecs.NewMap1[components.Disabled](&world)
+
+	system.Update(&world)
+	disabledMapper.Add(ents[0], &components.Disabled{})
+	clk.Advance(defaultWheelTick)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 0 {
+		t.Fatalf("disabled monitor dispatched: %d", got)
+	}
+
+	// Re-enable: the next reconciliation pass seeds it again.
+	disabledMapper.Remove(ents[0])
+	clk.Advance(wheelReconcileInterval)
+	system.Update(&world)
+	clk.Advance(defaultWheelTick)
+	system.Update(&world)
+	if got := len(mockQ.getEnqueued()); got != 1 {
+		t.Fatalf("expected 1 dispatch after re-enable, got %d", got)
+	}
+}
+
+// BenchmarkBatchPulseSystem_ScanVsWheel compares steady-state Update cost of
+// the per-shard scan against the time wheel at fleet sizes where the scan's
+// O(N) per tick starts to bite. Monitors are idle (next check an hour out),
+// so the numbers isolate pure scheduling overhead.
+func BenchmarkBatchPulseSystem_ScanVsWheel(b *testing.B) {
+	sizes := []int{10000, 100000, 1000000}
+
+	for _, useWheel := range []bool{false, true} {
+		mode := "scan"
+		if useWheel {
+			mode = "wheel"
+		}
+		for _, size := range sizes {
+			b.Run(fmt.Sprintf("%s_%d", mode, size), func(b *testing.B) {
+				world := ecs.NewWorld()
+				defer world.Reset()
+
+				mockQ := newMockQueue(size * 2)
+				shardSlots := size / 10
+				if shardSlots < 1 {
+					shardSlots = 1
+				}
+				system := NewBatchPulseSystem(&world, mockQ, 1000, zap.NewNop().Sugar(), newNoopStateLogger(), shardSlots)
+				if useWheel {
+					system.EnableTimeWheel()
+				}
+				system.Initialize(&world)
+
+				mapper := ecs.NewMap5[components.MonitorState, components.MonitorMeta, components.JobStorage, components.PulseConfig, components.Shard](&world)
+				nextCheck := time.Now().Add(time.Hour)
+				for i := 0; i < size; i++ {
+					mapper.NewEntity(
+						&components.MonitorState{NextCheckTime: nextCheck},
+						&components.MonitorMeta{},
+						&components.JobStorage{PulseJob: newTestPulseJob(i)},
+						&components.PulseConfig{Interval: time.Hour},
+						&components.Shard{ID: uint8(i % shardSlots)},
+					)
+				}
+				// Let wheel mode pay its seeding scan outside the timer.
+				system.Update(&world)
+
+				b.ResetTimer()
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					system.Update(&world)
+				}
+			})
+		}
+	}
+}
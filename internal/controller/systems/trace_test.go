@@ -0,0 +1,148 @@
+package systems
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+// tracedTestJob is a testPulseJob that can carry a trace context, mirroring
+// the real pulse jobs' TraceCarrier implementation.
+type tracedTestJob struct {
+	testPulseJob
+	traceCtx context.Context
+}
+
+func (j *tracedTestJob) SetTraceContext(ctx context.Context) { j.traceCtx = ctx }
+func (j *tracedTestJob) TraceContext() context.Context       { return j.traceCtx }
+
+// TestPulseTraceLifecycle runs one traced and one untraced monitor through
+// dispatch and result application and asserts the span tree: a pulse.check
+// root with a pulse.apply child for the traced monitor, nothing for the
+// untraced one.
+func TestPulseTraceLifecycle(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	tracer := tp.Tracer("test")
+
+	mockQ := newMockQueue(1000)
+	stateLogger := newNoopStateLogger()
+	system := NewBatchPulseSystem(&world, mockQ, 100, zap.NewNop().Sugar(), stateLogger, 1)
+	system.Initialize(&world)
+	system.SetTracer(tracer, 0)
+
+	results := make(chan []jobs.Result, 1)
+	resultSystem := NewBatchPulseResultSystem(&world, results, zap.NewNop().Sugar(), stateLogger)
+	resultSystem.SetTracer(tracer)
+
+	mapper := ecs.NewMap5[components.MonitorState, components.MonitorMeta, components.JobStorage, components.PulseConfig, components.Shard](&world)
+	newMonitor := func(name string, traced bool) (ecs.Entity, *tracedTestJob) {
+		job := &tracedTestJob{}
+		ent := mapper.NewEntity(
+			&components.MonitorState{Flags: components.StatePulseFirstCheck},
+			&components.MonitorMeta{Name: name},
+			&components.JobStorage{PulseJob: job},
+			&components.PulseConfig{Interval: time.Second, Type: "http", Trace: traced},
+			&components.Shard{ID: 0},
+		)
+		return ent, job
+	}
+	tracedEnt, tracedJob := newMonitor("traced", true)
+	plainEnt, plainJob := newMonitor("plain", false)
+
+	system.Update(&world)
+
+	if len(mockQ.getEnqueued()) != 2 {
+		t.Fatalf("expected both jobs enqueued, got %d", len(mockQ.getEnqueued()))
+	}
+	if tracedJob.TraceContext() == nil {
+		t.Fatal("traced job carries no context after dispatch")
+	}
+	if plainJob.TraceContext() != nil {
+		t.Fatal("untraced job carries a context after dispatch")
+	}
+
+	resultSystem.ProcessBatch([]jobs.Result{{Ent: tracedEnt}, {Ent: plainEnt}})
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2 (apply + check for the traced monitor only)", len(spans))
+	}
+	var rootSpan, applySpan sdktrace.ReadOnlySpan
+	for _, s := range spans {
+		switch s.Name() {
+		case "pulse.check":
+			rootSpan = s
+		case "pulse.apply":
+			applySpan = s
+		default:
+			t.Fatalf("unexpected span %q", s.Name())
+		}
+	}
+	if rootSpan == nil || applySpan == nil {
+		t.Fatal("missing pulse.check or pulse.apply span")
+	}
+	if applySpan.Parent().SpanID() != rootSpan.SpanContext().SpanID() {
+		t.Error("pulse.apply is not a child of pulse.check")
+	}
+	var gotName string
+	for _, attr := range rootSpan.Attributes() {
+		if string(attr.Key) == "monitor.name" {
+			gotName = attr.Value.AsString()
+		}
+	}
+	if gotName != "traced" {
+		t.Errorf("monitor.name = %q, want %q", gotName, "traced")
+	}
+	if tracedJob.TraceContext() != nil {
+		t.Error("trace context not cleared after result application")
+	}
+}
+
+// TestPulseTraceSampleRate checks that the sample rate traces monitors that
+// did not opt in themselves.
+func TestPulseTraceSampleRate(t *testing.T) {
+	t.Parallel()
+
+	world := ecs.NewWorld()
+	registerComponents(&world)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	mockQ := newMockQueue(1000)
+	system := NewBatchPulseSystem(&world, mockQ, 100, zap.NewNop().Sugar(), newNoopStateLogger(), 1)
+	system.Initialize(&world)
+	system.SetTracer(tp.Tracer("test"), 1.0)
+
+	mapper := ecs.NewMap5[components.MonitorState, components.MonitorMeta, components.JobStorage, components.PulseConfig, components.Shard](&world)
+	job := &tracedTestJob{}
+	mapper.NewEntity(
+		&components.MonitorState{Flags: components.StatePulseFirstCheck},
+		&components.MonitorMeta{Name: "sampled"},
+		&components.JobStorage{PulseJob: job},
+		&components.PulseConfig{Interval: time.Second, Type: "tcp"},
+		&components.Shard{ID: 0},
+	)
+
+	system.Update(&world)
+
+	if job.TraceContext() == nil {
+		t.Fatal("sample rate 1.0 left the job untraced")
+	}
+}
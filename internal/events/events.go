@@ -0,0 +1,222 @@
+// Package events provides a bounded in-memory bus for semantic monitor
+// transition events — "payments-api went yellow→red", "intervention
+// succeeded", "monitor recovered" — aimed at embedders who want more than
+// the raw job results the controller's Subscribe API exposes. The result
+// systems publish into the bus from inside the ECS tick, so delivery is
+// strictly at-most-once: each subscriber owns a fixed-size ring that the
+// bus pre-allocates, publishing copies the event value into every ring
+// without allocating, and when a subscriber lags its oldest unread events
+// are overwritten and counted as dropped rather than stalling the tick.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"cpra/internal/interning"
+)
+
+// Kind classifies a monitor transition event.
+type Kind uint8
+
+const (
+	// KindColorChange marks a delivered alert color transition.
+	KindColorChange Kind = iota
+	// KindRecovered marks a transition back to green (healthy).
+	KindRecovered
+	// KindInterventionSucceeded marks a remediation action that completed;
+	// the monitor enters its verification window.
+	KindInterventionSucceeded
+	// KindInterventionFailed marks a remediation action that errored.
+	KindInterventionFailed
+)
+
+// String returns the kind name as used in logs and stats.
+func (k Kind) String() string {
+	switch k {
+	case KindColorChange:
+		return "color_change"
+	case KindRecovered:
+		return "recovered"
+	case KindInterventionSucceeded:
+		return "intervention_succeeded"
+	case KindInterventionFailed:
+		return "intervention_failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is one semantic monitor transition.
+type Event struct {
+	// Time is when the result was applied, not when the job started.
+	Time time.Time `json:"time"`
+	// Monitor is the monitor name; EntityID its ECS entity for debugging
+	// across reloads (entities are recycled, names are stable).
+	Monitor  string `json:"monitor"`
+	EntityID uint32 `json:"entity_id"`
+	Kind     Kind   `json:"kind"`
+	// From and To carry the alert colors for color transitions, or the
+	// monitor phase ("verifying", "incident") for intervention outcomes.
+	// From is empty when no earlier state is known.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Details carries the intervention action or failure text.
+	Details string `json:"details,omitempty"`
+	// Labels is the monitor's manifest metadata, shared with the entity's
+	// MonitorMeta; subscribers must not mutate it.
+	Labels interning.Labels `json:"labels,omitempty"`
+}
+
+// SubscriberStats describes one attached event subscriber.
+type SubscriberStats struct {
+	ID        uint64 `json:"id"`
+	Delivered uint64 `json:"delivered"`
+	Dropped   uint64 `json:"dropped"`
+}
+
+// defaultBuffer is used when Subscribe is called with buffer <= 0.
+const defaultBuffer = 256
+
+// Subscription is one consumer's cursor into the bus. Its methods must not
+// be called concurrently with each other, but are safe against concurrent
+// publishes.
+type Subscription struct {
+	bus *Bus
+	id  uint64
+}
+
+// Bus fans monitor transition events out to subscribers. See the package
+// doc for the delivery contract.
+type Bus struct {
+	mu     sync.Mutex
+	nextID uint64
+	subs   map[uint64]*ring
+	closed bool
+}
+
+// ring is one subscriber's pre-allocated event buffer: start indexes the
+// oldest unread event and size counts the unread ones.
+type ring struct {
+	buf       []Event
+	start     int
+	size      int
+	delivered uint64
+	dropped   uint64
+}
+
+// NewBus creates an empty bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[uint64]*ring)}
+}
+
+// Subscribe attaches a consumer with its own ring of the given capacity.
+// On a closed bus the subscription is inert: Next never yields an event.
+func (b *Bus) Subscribe(buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return &Subscription{bus: b}
+	}
+	b.nextID++
+	b.subs[b.nextID] = &ring{buf: make([]Event, buffer)}
+	return &Subscription{bus: b, id: b.nextID}
+}
+
+// Publish copies the event into every subscriber ring. It never blocks and
+// never allocates; a full ring overwrites its oldest unread event, which is
+// counted against that subscriber as dropped.
+func (b *Bus) Publish(ev Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed || len(b.subs) == 0 {
+		return
+	}
+	for _, r := range b.subs {
+		if r.size == len(r.buf) {
+			// Overwrite the oldest unread event.
+			r.start = (r.start + 1) % len(r.buf)
+			r.size--
+			r.dropped++
+		}
+		r.buf[(r.start+r.size)%len(r.buf)] = ev
+		r.size++
+	}
+}
+
+// Stats returns a snapshot of all attached subscribers.
+func (b *Bus) Stats() []SubscriberStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.subs) == 0 {
+		return nil
+	}
+	out := make([]SubscriberStats, 0, len(b.subs))
+	for id, r := range b.subs {
+		out = append(out, SubscriberStats{ID: id, Delivered: r.delivered, Dropped: r.dropped})
+	}
+	return out
+}
+
+// Close detaches every subscriber, discarding unread events, and rejects
+// further publishes and subscriptions. Called once during controller
+// shutdown, after the result systems have drained.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for id := range b.subs {
+		delete(b.subs, id)
+	}
+}
+
+// Next pops the oldest unread event, reporting false when the ring is empty
+// or the subscription was cancelled.
+func (s *Subscription) Next() (Event, bool) {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	r, ok := s.bus.subs[s.id]
+	if !ok || r.size == 0 {
+		return Event{}, false
+	}
+	ev := r.buf[r.start]
+	r.start = (r.start + 1) % len(r.buf)
+	r.size--
+	r.delivered++
+	return ev, true
+}
+
+// Pending reports how many unread events the subscriber's ring holds.
+func (s *Subscription) Pending() int {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if r, ok := s.bus.subs[s.id]; ok {
+		return r.size
+	}
+	return 0
+}
+
+// Dropped returns how many of this subscriber's events were overwritten
+// before being read.
+func (s *Subscription) Dropped() uint64 {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if r, ok := s.bus.subs[s.id]; ok {
+		return r.dropped
+	}
+	return 0
+}
+
+// Cancel detaches the subscriber, discarding unread events. It is
+// idempotent.
+func (s *Subscription) Cancel() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	delete(s.bus.subs, s.id)
+}
@@ -0,0 +1,128 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestBusDeliversInOrder(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus()
+	sub := bus.Subscribe(8)
+	for i := 0; i < 3; i++ {
+		bus.Publish(Event{Monitor: fmt.Sprintf("m%d", i), Kind: KindColorChange})
+	}
+
+	for i := 0; i < 3; i++ {
+		ev, ok := sub.Next()
+		if !ok {
+			t.Fatalf("Next() empty at %d, want 3 events", i)
+		}
+		if want := fmt.Sprintf("m%d", i); ev.Monitor != want {
+			t.Errorf("event %d monitor = %q, want %q", i, ev.Monitor, want)
+		}
+	}
+	if _, ok := sub.Next(); ok {
+		t.Error("Next() yielded a fourth event, want empty")
+	}
+}
+
+func TestBusOverwritesOldestWhenLagging(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus()
+	sub := bus.Subscribe(2)
+	for i := 0; i < 5; i++ {
+		bus.Publish(Event{Monitor: fmt.Sprintf("m%d", i)})
+	}
+
+	// Ring of 2 after 5 publishes: m0..m2 overwritten, m3 and m4 remain.
+	if got := sub.Pending(); got != 2 {
+		t.Fatalf("Pending() = %d, want 2", got)
+	}
+	if got := sub.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, want 3", got)
+	}
+	for i, want := range []string{"m3", "m4"} {
+		ev, ok := sub.Next()
+		if !ok || ev.Monitor != want {
+			t.Errorf("event %d = (%q, %v), want (%q, true)", i, ev.Monitor, ok, want)
+		}
+	}
+
+	stats := bus.Stats()
+	if len(stats) != 1 {
+		t.Fatalf("Stats() has %d subscribers, want 1", len(stats))
+	}
+	if stats[0].Delivered != 2 || stats[0].Dropped != 3 {
+		t.Errorf("stats = delivered %d dropped %d, want 2 and 3", stats[0].Delivered, stats[0].Dropped)
+	}
+}
+
+func TestBusSubscribersAreIndependent(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus()
+	fast := bus.Subscribe(8)
+	slow := bus.Subscribe(1)
+	bus.Publish(Event{Monitor: "a"})
+	bus.Publish(Event{Monitor: "b"})
+
+	if got := fast.Pending(); got != 2 {
+		t.Errorf("fast Pending() = %d, want 2", got)
+	}
+	if got, dropped := slow.Pending(), slow.Dropped(); got != 1 || dropped != 1 {
+		t.Errorf("slow pending/dropped = %d/%d, want 1/1", got, dropped)
+	}
+	if ev, ok := slow.Next(); !ok || ev.Monitor != "b" {
+		t.Errorf("slow Next() = (%q, %v), want newest event b", ev.Monitor, ok)
+	}
+}
+
+func TestBusCancelAndClose(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus()
+	sub := bus.Subscribe(4)
+	bus.Publish(Event{Monitor: "a"})
+
+	sub.Cancel()
+	sub.Cancel() // idempotent
+	if _, ok := sub.Next(); ok {
+		t.Error("Next() after Cancel yielded an event")
+	}
+
+	other := bus.Subscribe(4)
+	bus.Close()
+	bus.Publish(Event{Monitor: "late"})
+	if _, ok := other.Next(); ok {
+		t.Error("Next() after Close yielded an event")
+	}
+	if post := bus.Subscribe(4); post.Pending() != 0 {
+		t.Error("subscription on a closed bus is not inert")
+	}
+}
+
+func TestBusConcurrentPublish(t *testing.T) {
+	t.Parallel()
+
+	bus := NewBus()
+	sub := bus.Subscribe(1024)
+	var wg sync.WaitGroup
+	for p := 0; p < 8; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				bus.Publish(Event{Monitor: "m"})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := sub.Pending(); got != 800 {
+		t.Errorf("Pending() = %d, want 800", got)
+	}
+}
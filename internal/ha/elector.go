@@ -0,0 +1,92 @@
+// Package ha provides leader election for active/standby pairs of cpra
+// instances. Both instances load and watch the manifest, but only the
+// elected leader schedules checks and runs worker pools, so a redundant
+// deployment never executes interventions or pages twice.
+//
+// Two electors are built in: FileLockElector holds an advisory flock on
+// shared storage, and KVElector takes a lease through any store implementing
+// the small KV interface (etcd, consul, redis adapters live outside this
+// package). MemoryKV is an in-process KV for tests.
+package ha
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultLeaseInterval is how often the controller re-campaigns and the TTL
+// granted on KV leases when the caller does not configure one. Loss of
+// leadership is detected within one interval.
+const DefaultLeaseInterval = 5 * time.Second
+
+// LeaderElector decides which of several cpra instances may schedule work.
+// The controller calls TryAcquire once per lease interval; implementations
+// must guarantee at most one instance holds leadership at a time.
+type LeaderElector interface {
+	// TryAcquire attempts to take leadership, or renews it when this
+	// instance already holds it. It returns whether the instance is the
+	// leader after the attempt. Errors are transient (store unreachable);
+	// the caller treats an error as not holding leadership.
+	TryAcquire(ctx context.Context) (bool, error)
+
+	// Release gives up leadership voluntarily, letting a standby take over
+	// without waiting for the lease to expire. Safe to call when not leader.
+	Release() error
+}
+
+// KV is the minimal lease contract an external store must offer for
+// KV-backed election. Both operations must be atomic in the store.
+type KV interface {
+	// AcquireLease claims key for holder with the given TTL if the key is
+	// unclaimed, expired, or already held by holder (renewal). It returns
+	// whether holder owns the lease afterwards.
+	AcquireLease(key, holder string, ttl time.Duration) (bool, error)
+
+	// ReleaseLease drops the lease if holder owns it. Releasing a lease
+	// held by someone else is a no-op.
+	ReleaseLease(key, holder string) error
+}
+
+// KVElector elects a leader through a lease in an external KV store. Each
+// TryAcquire claims or renews the lease; a leader that stops renewing (crash,
+// partition) loses it after the TTL and a standby takes over.
+type KVElector struct {
+	kv     KV
+	key    string
+	holder string
+	ttl    time.Duration
+}
+
+// NewKVElector creates an elector leasing key in kv under the given holder
+// identity (typically hostname plus PID). A non-positive ttl uses
+// DefaultLeaseInterval; the controller's lease interval must not exceed the
+// TTL or leadership flaps on every renewal.
+func NewKVElector(kv KV, key, holder string, ttl time.Duration) (*KVElector, error) {
+	if kv == nil {
+		return nil, fmt.Errorf("kv store cannot be nil")
+	}
+	if key == "" || holder == "" {
+		return nil, fmt.Errorf("lease key and holder identity are required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultLeaseInterval
+	}
+	return &KVElector{kv: kv, key: key, holder: holder, ttl: ttl}, nil
+}
+
+// TryAcquire claims or renews the lease.
+func (e *KVElector) TryAcquire(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return e.kv.AcquireLease(e.key, e.holder, e.ttl)
+}
+
+// Release drops the lease so a standby can take over immediately.
+func (e *KVElector) Release() error {
+	return e.kv.ReleaseLease(e.key, e.holder)
+}
+
+// Holder returns the identity this elector campaigns under.
+func (e *KVElector) Holder() string { return e.holder }
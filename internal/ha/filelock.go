@@ -0,0 +1,68 @@
+package ha
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// FileLockElector elects a leader through an advisory flock on a file shared
+// by both instances (shared storage, or the same host for a local pair). The
+// kernel releases the lock when the holder exits or crashes, so failover
+// needs no lease bookkeeping; the standby simply wins its next campaign.
+//
+// flock locks follow the open file description: the lock survives as long as
+// the process keeps the file open and is dropped on Release or process exit.
+type FileLockElector struct {
+	path string
+	file *os.File
+}
+
+// NewFileLockElector creates an elector locking the given path. The file is
+// created on first campaign if it does not exist; its content is never read.
+func NewFileLockElector(path string) (*FileLockElector, error) {
+	if path == "" {
+		return nil, fmt.Errorf("lock file path is required")
+	}
+	return &FileLockElector{path: path}, nil
+}
+
+// TryAcquire takes the lock non-blocking. Once held, subsequent calls are
+// renewal no-ops: flock does not expire, so holding the descriptor is
+// holding leadership.
+func (e *FileLockElector) TryAcquire(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if e.file != nil {
+		return true, nil
+	}
+	f, err := os.OpenFile(e.path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return false, fmt.Errorf("open lock file: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+		if err == syscall.EWOULDBLOCK {
+			return false, nil // another instance is leader
+		}
+		return false, fmt.Errorf("flock %s: %w", e.path, err)
+	}
+	e.file = f
+	return true, nil
+}
+
+// Release drops the lock and closes the file.
+func (e *FileLockElector) Release() error {
+	if e.file == nil {
+		return nil
+	}
+	err := syscall.Flock(int(e.file.Fd()), syscall.LOCK_UN)
+	closeErr := e.file.Close()
+	e.file = nil
+	if err != nil {
+		return fmt.Errorf("unlock %s: %w", e.path, err)
+	}
+	return closeErr
+}
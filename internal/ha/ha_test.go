@@ -0,0 +1,186 @@
+package ha
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cpra/internal/clock"
+)
+
+// TestKVElector_ExactlyOneLeaderPerInterval runs two electors against one KV
+// for many lease intervals and asserts every interval sees exactly one
+// leader: never zero, never two, and no churn while the leader keeps
+// renewing.
+func TestKVElector_ExactlyOneLeaderPerInterval(t *testing.T) {
+	t.Parallel()
+
+	manual := clock.NewManual(time.Unix(1_700_000_000, 0))
+	kv := NewMemoryKV()
+	kv.SetClock(manual)
+
+	const ttl = 10 * time.Second
+	const interval = 5 * time.Second // renew at half the TTL, like the controller should
+	a, err := NewKVElector(kv, "cpra-leader", "instance-a", ttl)
+	if err != nil {
+		t.Fatalf("NewKVElector failed: %v", err)
+	}
+	b, err := NewKVElector(kv, "cpra-leader", "instance-b", ttl)
+	if err != nil {
+		t.Fatalf("NewKVElector failed: %v", err)
+	}
+
+	ctx := context.Background()
+	var prevLeader string
+	for i := 0; i < 50; i++ {
+		// Alternate campaign order so neither instance wins by ordering.
+		first, second := a, b
+		if i%2 == 1 {
+			first, second = b, a
+		}
+		firstLed, err := first.TryAcquire(ctx)
+		if err != nil {
+			t.Fatalf("TryAcquire failed: %v", err)
+		}
+		secondLed, err := second.TryAcquire(ctx)
+		if err != nil {
+			t.Fatalf("TryAcquire failed: %v", err)
+		}
+
+		leaders := 0
+		leader := ""
+		if firstLed {
+			leaders++
+			leader = first.Holder()
+		}
+		if secondLed {
+			leaders++
+			leader = second.Holder()
+		}
+		if leaders != 1 {
+			t.Fatalf("interval %d: %d leaders, want exactly 1", i, leaders)
+		}
+		if prevLeader != "" && leader != prevLeader {
+			t.Fatalf("interval %d: leadership churned from %s to %s while renewing", i, prevLeader, leader)
+		}
+		prevLeader = leader
+		manual.Advance(interval)
+	}
+}
+
+// TestKVElector_FailoverOnRevoke kills the leader's lease out from under it,
+// as a crashed instance would, and asserts the standby takes over.
+func TestKVElector_FailoverOnRevoke(t *testing.T) {
+	t.Parallel()
+
+	kv := NewMemoryKV()
+	leader, _ := NewKVElector(kv, "cpra-leader", "instance-a", time.Minute)
+	standby, _ := NewKVElector(kv, "cpra-leader", "instance-b", time.Minute)
+
+	ctx := context.Background()
+	if led, _ := leader.TryAcquire(ctx); !led {
+		t.Fatal("first campaign must win")
+	}
+	if led, _ := standby.TryAcquire(ctx); led {
+		t.Fatal("standby must not win while the lease is held")
+	}
+
+	kv.Revoke("cpra-leader")
+	if led, _ := standby.TryAcquire(ctx); !led {
+		t.Fatal("standby must win after the leader's lease is revoked")
+	}
+	if got := kv.Holder("cpra-leader"); got != "instance-b" {
+		t.Errorf("Holder = %q, want instance-b", got)
+	}
+}
+
+// TestKVElector_ExpiredLeaseFailsOver verifies a leader that stops renewing
+// loses the lease after the TTL.
+func TestKVElector_ExpiredLeaseFailsOver(t *testing.T) {
+	t.Parallel()
+
+	manual := clock.NewManual(time.Unix(1_700_000_000, 0))
+	kv := NewMemoryKV()
+	kv.SetClock(manual)
+
+	leader, _ := NewKVElector(kv, "cpra-leader", "instance-a", 10*time.Second)
+	standby, _ := NewKVElector(kv, "cpra-leader", "instance-b", 10*time.Second)
+
+	ctx := context.Background()
+	if led, _ := leader.TryAcquire(ctx); !led {
+		t.Fatal("first campaign must win")
+	}
+
+	// Inside the TTL the standby stays out.
+	manual.Advance(9 * time.Second)
+	if led, _ := standby.TryAcquire(ctx); led {
+		t.Fatal("standby must not win inside the TTL")
+	}
+
+	// The leader never renews; past the TTL the standby wins.
+	manual.Advance(2 * time.Second)
+	if led, _ := standby.TryAcquire(ctx); !led {
+		t.Fatal("standby must win once the lease expired")
+	}
+}
+
+// TestKVElector_Release verifies voluntary release hands the lease over
+// without waiting out the TTL.
+func TestKVElector_Release(t *testing.T) {
+	t.Parallel()
+
+	kv := NewMemoryKV()
+	leader, _ := NewKVElector(kv, "cpra-leader", "instance-a", time.Minute)
+	standby, _ := NewKVElector(kv, "cpra-leader", "instance-b", time.Minute)
+
+	ctx := context.Background()
+	if led, _ := leader.TryAcquire(ctx); !led {
+		t.Fatal("first campaign must win")
+	}
+	if err := leader.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if led, _ := standby.TryAcquire(ctx); !led {
+		t.Fatal("standby must win after a voluntary release")
+	}
+}
+
+// TestFileLockElector covers mutual exclusion and handover through flock.
+// Two electors in one process contend on separate file descriptors, which
+// flock treats like two processes.
+func TestFileLockElector(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cpra.lock")
+	a, err := NewFileLockElector(path)
+	if err != nil {
+		t.Fatalf("NewFileLockElector failed: %v", err)
+	}
+	b, err := NewFileLockElector(path)
+	if err != nil {
+		t.Fatalf("NewFileLockElector failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if led, err := a.TryAcquire(ctx); err != nil || !led {
+		t.Fatalf("first campaign: leader=%v err=%v, want true/nil", led, err)
+	}
+	// Renewal is a no-op while held.
+	if led, err := a.TryAcquire(ctx); err != nil || !led {
+		t.Fatalf("renewal: leader=%v err=%v, want true/nil", led, err)
+	}
+	if led, err := b.TryAcquire(ctx); err != nil || led {
+		t.Fatalf("contender: leader=%v err=%v, want false/nil", led, err)
+	}
+
+	if err := a.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if led, err := b.TryAcquire(ctx); err != nil || !led {
+		t.Fatalf("after release: leader=%v err=%v, want true/nil", led, err)
+	}
+	if err := b.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package ha
+
+import (
+	"sync"
+	"time"
+
+	"cpra/internal/clock"
+)
+
+// MemoryKV is an in-process KV for tests and single-binary experiments. TTL
+// arithmetic runs on an injected clock so lease expiry can be driven with
+// clock.Manual instead of sleeping.
+type MemoryKV struct {
+	mu     sync.Mutex
+	leases map[string]memoryLease
+	now    func() time.Time
+}
+
+type memoryLease struct {
+	holder  string
+	expires time.Time
+}
+
+// NewMemoryKV creates an empty in-memory KV on the system clock.
+func NewMemoryKV() *MemoryKV {
+	return &MemoryKV{
+		leases: make(map[string]memoryLease),
+		now:    time.Now,
+	}
+}
+
+// SetClock routes lease expiry through c. A nil clock is ignored.
+func (m *MemoryKV) SetClock(c clock.Clock) {
+	if c != nil {
+		m.now = c.Now
+	}
+}
+
+// AcquireLease implements KV.
+func (m *MemoryKV) AcquireLease(key, holder string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	lease, ok := m.leases[key]
+	if ok && lease.holder != holder && now.Before(lease.expires) {
+		return false, nil
+	}
+	m.leases[key] = memoryLease{holder: holder, expires: now.Add(ttl)}
+	return true, nil
+}
+
+// ReleaseLease implements KV.
+func (m *MemoryKV) ReleaseLease(key, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lease, ok := m.leases[key]; ok && lease.holder == holder {
+		delete(m.leases, key)
+	}
+	return nil
+}
+
+// Revoke drops the lease on key regardless of holder, simulating a leader
+// that died without releasing. The next AcquireLease from any instance wins.
+func (m *MemoryKV) Revoke(key string) {
+	m.mu.Lock()
+	delete(m.leases, key)
+	m.mu.Unlock()
+}
+
+// Holder returns who currently holds the lease on key, or "" when it is
+// unclaimed or expired.
+func (m *MemoryKV) Holder(key string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lease, ok := m.leases[key]; ok && m.now().Before(lease.expires) {
+		return lease.holder
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+package interning
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Label is one key/value pair of monitor metadata.
+type Label struct {
+	Key   string
+	Value string
+}
+
+// Labels is an immutable, key-sorted label set. Sets are shared through
+// InternLabels, so many monitors carrying identical labels (team, tier,
+// runbook) hold the same backing slice; callers must never mutate one.
+// A nil Labels is the empty set.
+type Labels []Label
+
+// labelSets maintains the global pool of interned label sets, keyed by the
+// set's canonical encoding. Like interned strings, sets are never removed,
+// so label cardinality must stay bounded (the loader enforces this).
+var labelSets sync.Map // map[string]Labels
+
+// InternLabels returns the shared Labels instance for the given map. The
+// keys and values are themselves interned, and identical maps — regardless
+// of iteration order — return the same slice. Nil or empty maps return nil.
+func InternLabels(m map[string]string) Labels {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Canonical encoding: NUL-separated key/value pairs in key order. NUL
+	// cannot appear in YAML scalars that pass validation, so the encoding
+	// is collision-free.
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(m[k])
+		b.WriteByte(0)
+	}
+	canonical := b.String()
+
+	if v, ok := labelSets.Load(canonical); ok {
+		return v.(Labels)
+	}
+	set := make(Labels, 0, len(keys))
+	for _, k := range keys {
+		set = append(set, Label{Key: Intern(k), Value: Intern(m[k])})
+	}
+	actual, _ := labelSets.LoadOrStore(canonical, set)
+	return actual.(Labels)
+}
+
+// Get returns the value for the key and whether it is present.
+func (l Labels) Get(key string) (string, bool) {
+	i := sort.Search(len(l), func(i int) bool { return l[i].Key >= key })
+	if i < len(l) && l[i].Key == key {
+		return l[i].Value, true
+	}
+	return "", false
+}
+
+// Map returns the labels as a fresh map, safe for the caller to mutate.
+func (l Labels) Map() map[string]string {
+	if len(l) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(l))
+	for _, label := range l {
+		m[label.Key] = label.Value
+	}
+	return m
+}
+
+// String renders the set as "k1=v1,k2=v2" in key order, for log lines and
+// notification messages.
+func (l Labels) String() string {
+	var b strings.Builder
+	for i, label := range l {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(label.Key)
+		b.WriteByte('=')
+		b.WriteString(label.Value)
+	}
+	return b.String()
+}
+
+// MarshalJSON renders the set as a JSON object with deterministic key order.
+func (l Labels) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, label := range l {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(label.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(label.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON object back into the shared interned set,
+// so consumers of serialized events and sink output can round-trip labels.
+func (l *Labels) UnmarshalJSON(data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*l = InternLabels(m)
+	return nil
+}
@@ -0,0 +1,75 @@
+package interning
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInternLabels(t *testing.T) {
+	a := InternLabels(map[string]string{"team": "payments", "tier": "1"})
+	b := InternLabels(map[string]string{"tier": "1", "team": "payments"})
+
+	if len(a) != 2 {
+		t.Fatalf("len = %d, want 2", len(a))
+	}
+	// Equal sets intern to the same backing slice regardless of map order.
+	if &a[0] != &b[0] {
+		t.Error("equal label sets should share one interned instance")
+	}
+	// Keys come back sorted so rendering is deterministic.
+	if a[0].Key != "team" || a[1].Key != "tier" {
+		t.Errorf("keys = %q, %q, want team, tier", a[0].Key, a[1].Key)
+	}
+}
+
+func TestInternLabels_Empty(t *testing.T) {
+	if got := InternLabels(nil); got != nil {
+		t.Errorf("InternLabels(nil) = %v, want nil", got)
+	}
+	if got := InternLabels(map[string]string{}); got != nil {
+		t.Errorf("InternLabels(empty) = %v, want nil", got)
+	}
+}
+
+func TestLabels_Get(t *testing.T) {
+	l := InternLabels(map[string]string{"team": "payments", "tier": "1"})
+
+	if v, ok := l.Get("team"); !ok || v != "payments" {
+		t.Errorf("Get(team) = %q, %v, want payments, true", v, ok)
+	}
+	if _, ok := l.Get("missing"); ok {
+		t.Error("Get(missing) should report absent")
+	}
+	if _, ok := Labels(nil).Get("team"); ok {
+		t.Error("Get on nil Labels should report absent")
+	}
+}
+
+func TestLabels_String(t *testing.T) {
+	l := InternLabels(map[string]string{"tier": "1", "team": "payments"})
+	if got := l.String(); got != "team=payments,tier=1" {
+		t.Errorf("String() = %q, want team=payments,tier=1", got)
+	}
+	if got := Labels(nil).String(); got != "" {
+		t.Errorf("nil String() = %q, want empty", got)
+	}
+}
+
+func TestLabels_MarshalJSON(t *testing.T) {
+	l := InternLabels(map[string]string{"tier": "1", "team": "payments"})
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if got := string(data); got != `{"team":"payments","tier":"1"}` {
+		t.Errorf("Marshal = %s, want sorted object", got)
+	}
+
+	var decoded map[string]string
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("round-trip failed: %v", err)
+	}
+	if decoded["team"] != "payments" || decoded["tier"] != "1" {
+		t.Errorf("round-trip = %v", decoded)
+	}
+}
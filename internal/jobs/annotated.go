@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// AnnotatedJob wraps a job and merges fixed extras into its result payload.
+// Systems that enqueue code jobs directly, outside the per-color dispatch
+// path, use it to attach context the job factory does not know about — the
+// SLO system, for example, stamps the burn-rate numbers onto the alert it
+// fires. Extras set by the inner job win over the annotation on key clashes.
+type AnnotatedJob struct {
+	Job         Job
+	Extras      map[string]interface{}
+	EnqueueTime time.Time
+	StartTime   time.Time
+}
+
+// Execute runs the wrapped job and applies the annotations to its result.
+func (j *AnnotatedJob) Execute(ctx context.Context) Result {
+	res := j.Job.Execute(ctx)
+	for key, value := range j.Extras {
+		if _, exists := res.Payload.Extras[key]; !exists {
+			res.Payload.SetExtra(key, value)
+		}
+	}
+	return res
+}
+
+// Copy returns a copy wrapping a copy of the inner job.
+func (j *AnnotatedJob) Copy() Job {
+	job := *j
+	job.Job = j.Job.Copy()
+	if j.Extras != nil {
+		job.Extras = make(map[string]interface{}, len(j.Extras))
+		for key, value := range j.Extras {
+			job.Extras[key] = value
+		}
+	}
+	return &job
+}
+
+// GetEnqueueTime returns when the job was enqueued.
+func (j *AnnotatedJob) GetEnqueueTime() time.Time { return j.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (j *AnnotatedJob) SetEnqueueTime(t time.Time) { j.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (j *AnnotatedJob) GetStartTime() time.Time { return j.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (j *AnnotatedJob) SetStartTime(t time.Time) { j.StartTime = t }
+
+// IsNil returns true if the wrapper or the wrapped job is nil.
+func (j *AnnotatedJob) IsNil() bool { return j == nil || j.Job == nil || j.Job.IsNil() }
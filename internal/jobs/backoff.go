@@ -0,0 +1,188 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// BackoffStrategy selects how retry delays grow between attempts.
+type BackoffStrategy uint8
+
+const (
+	// BackoffExponential doubles the delay after each failed attempt,
+	// capped at the policy max. This is the default.
+	BackoffExponential BackoffStrategy = iota
+	// BackoffConstant waits the base delay between every attempt.
+	BackoffConstant
+	// BackoffNone retries immediately with no delay.
+	BackoffNone
+)
+
+const (
+	// defaultBackoffBase matches the historical hard-coded retry delay.
+	defaultBackoffBase = 50 * time.Millisecond
+	// defaultBackoffMax matches the historical 1s backoff cap.
+	defaultBackoffMax = time.Second
+)
+
+// ParseBackoffStrategy maps a config string to a strategy. The empty
+// string selects the exponential default.
+func ParseBackoffStrategy(s string) (BackoffStrategy, error) {
+	switch strings.ToLower(s) {
+	case "", "exponential":
+		return BackoffExponential, nil
+	case "constant":
+		return BackoffConstant, nil
+	case "none":
+		return BackoffNone, nil
+	default:
+		return BackoffExponential, fmt.Errorf("unknown backoff strategy %q", s)
+	}
+}
+
+// BackoffPolicy computes per-attempt retry delays.
+//
+// The zero value is equivalent to the historical behavior of
+// RetryWithBackoff: exponential growth from 50ms capped at 1s, no jitter.
+// Existing configs without a retry_backoff block therefore keep their
+// semantics.
+type BackoffPolicy struct {
+	Strategy BackoffStrategy
+	Base     time.Duration
+	Max      time.Duration
+	Jitter   bool
+}
+
+// Delay returns the wait before the next attempt, given how many attempts
+// have already completed (0-based). With Jitter the delay is drawn
+// uniformly from [delay/2, delay] to spread retries of correlated failures.
+func (p BackoffPolicy) Delay(completed int) time.Duration {
+	if p.Strategy == BackoffNone {
+		return 0
+	}
+	base := p.Base
+	if base <= 0 {
+		base = defaultBackoffBase
+	}
+	max := p.Max
+	if max <= 0 {
+		max = defaultBackoffMax
+	}
+
+	delay := base
+	if p.Strategy == BackoffExponential {
+		// Guard the shift: past ~30 doublings any realistic base exceeds max.
+		if completed > 30 {
+			completed = 30
+		}
+		delay = base << uint(completed)
+		if delay <= 0 { // overflow
+			delay = max
+		}
+	}
+	if delay > max {
+		delay = max
+	}
+
+	if p.Jitter && delay > time.Millisecond {
+		half := delay / 2
+		delay = half + rand.N(half+1)
+	}
+	return delay
+}
+
+// RetryStats records what a retry loop actually did, for result payloads.
+type RetryStats struct {
+	// Attempts is how many times fn was invoked (at least 1).
+	Attempts int
+	// RetryLatency is the total time spent sleeping between attempts.
+	RetryLatency time.Duration
+	// LastAttemptErr is the error from the most recent failed attempt.
+	// After an eventual success it records what the earlier attempts
+	// failed with; after exhaustion it matches the returned error.
+	LastAttemptErr error
+}
+
+// RetryWithPolicy executes fn up to `attempts` times, waiting between
+// attempts according to policy. Respects context cancellation between
+// attempts and never sleeps after the final one.
+//
+// budget, when positive, caps the total time spent sleeping: the last
+// delay is truncated to fit and retrying stops once the budget is spent.
+// Jobs pass their Timeout so a flapping target cannot hold a worker for
+// longer than one nominal check.
+func RetryWithPolicy(ctx context.Context, attempts int, policy BackoffPolicy, budget time.Duration, fn func() error) (RetryStats, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var stats RetryStats
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		// Check context before attempting
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		stats.Attempts++
+		if err := fn(); err == nil {
+			stats.LastAttemptErr = lastErr
+			return stats, nil
+		} else {
+			lastErr = err
+			stats.LastAttemptErr = lastErr
+		}
+
+		// Don't wait after last attempt
+		if i == attempts-1 {
+			break
+		}
+
+		delay := policy.Delay(i)
+		if budget > 0 {
+			remaining := budget - stats.RetryLatency
+			if remaining <= 0 {
+				break // retry budget exhausted
+			}
+			if delay > remaining {
+				delay = remaining
+			}
+		}
+		if delay > 0 {
+			select {
+			case <-ctx.Done():
+				return stats, ctx.Err()
+			case <-time.After(delay):
+				stats.RetryLatency += delay
+			}
+		}
+	}
+	return stats, lastErr
+}
+
+// stampAttemptLatency records the successful (final) attempt's own latency.
+// It is only stamped when retries happened, since otherwise it matches
+// Result.Duration and would just allocate Extras for nothing.
+func stampAttemptLatency(payload *Payload, stats RetryStats, lastAttempt time.Duration) {
+	if stats.Attempts <= 1 || lastAttempt <= 0 {
+		return
+	}
+	payload.SetExtra("attempt_latency_ms", lastAttempt.Milliseconds())
+}
+
+// stampRetryStats records retry accounting into the payload. The typed
+// attempt fields are always set (they are allocation-free); the Extras
+// entries are only added for multi-attempt runs so the common case still
+// allocates nothing.
+func stampRetryStats(payload *Payload, stats RetryStats) {
+	payload.Attempts = stats.Attempts
+	payload.LastAttemptError = stats.LastAttemptErr
+	if stats.Attempts <= 1 {
+		return
+	}
+	payload.SetExtra("attempts", stats.Attempts)
+	payload.SetExtra("retry_latency_ms", stats.RetryLatency.Milliseconds())
+}
@@ -0,0 +1,324 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// BackoffPolicy Tests
+// =============================================================================
+
+// TestParseBackoffStrategy tests strategy string parsing.
+func TestParseBackoffStrategy(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		in      string
+		want    BackoffStrategy
+		wantErr bool
+	}{
+		{"", BackoffExponential, false},
+		{"exponential", BackoffExponential, false},
+		{"Exponential", BackoffExponential, false},
+		{"constant", BackoffConstant, false},
+		{"none", BackoffNone, false},
+		{"fibonacci", BackoffExponential, true},
+	}
+	for _, tc := range cases {
+		got, err := ParseBackoffStrategy(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseBackoffStrategy(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("ParseBackoffStrategy(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+// TestBackoffPolicy_Delay_ZeroValueMatchesHistorical tests that the zero
+// policy reproduces the old RetryWithBackoff schedule: 50ms doubling, 1s cap.
+func TestBackoffPolicy_Delay_ZeroValueMatchesHistorical(t *testing.T) {
+	t.Parallel()
+	var policy BackoffPolicy
+
+	want := []time.Duration{
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second, // capped
+		time.Second,
+	}
+	for i, expected := range want {
+		if got := policy.Delay(i); got != expected {
+			t.Errorf("Delay(%d) = %v, want %v", i, got, expected)
+		}
+	}
+}
+
+// TestBackoffPolicy_Delay_ExponentialGrowth tests doubling from a custom base.
+func TestBackoffPolicy_Delay_ExponentialGrowth(t *testing.T) {
+	t.Parallel()
+	policy := BackoffPolicy{
+		Strategy: BackoffExponential,
+		Base:     100 * time.Millisecond,
+		Max:      2 * time.Second,
+	}
+
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		1600 * time.Millisecond,
+		2 * time.Second, // capped
+	}
+	for i, expected := range want {
+		if got := policy.Delay(i); got != expected {
+			t.Errorf("Delay(%d) = %v, want %v", i, got, expected)
+		}
+	}
+
+	// Very large attempt counts must not overflow past the cap.
+	if got := policy.Delay(1000); got != 2*time.Second {
+		t.Errorf("Delay(1000) = %v, want %v", got, 2*time.Second)
+	}
+}
+
+// TestBackoffPolicy_Delay_Constant tests that constant never grows.
+func TestBackoffPolicy_Delay_Constant(t *testing.T) {
+	t.Parallel()
+	policy := BackoffPolicy{Strategy: BackoffConstant, Base: 75 * time.Millisecond}
+
+	for i := 0; i < 5; i++ {
+		if got := policy.Delay(i); got != 75*time.Millisecond {
+			t.Errorf("Delay(%d) = %v, want 75ms", i, got)
+		}
+	}
+}
+
+// TestBackoffPolicy_Delay_None tests that none always yields zero.
+func TestBackoffPolicy_Delay_None(t *testing.T) {
+	t.Parallel()
+	policy := BackoffPolicy{Strategy: BackoffNone, Base: time.Second}
+
+	for i := 0; i < 5; i++ {
+		if got := policy.Delay(i); got != 0 {
+			t.Errorf("Delay(%d) = %v, want 0", i, got)
+		}
+	}
+}
+
+// TestBackoffPolicy_Delay_JitterBounds tests that jittered delays stay within
+// [delay/2, delay] of the unjittered value.
+func TestBackoffPolicy_Delay_JitterBounds(t *testing.T) {
+	t.Parallel()
+	plain := BackoffPolicy{Strategy: BackoffExponential, Base: 100 * time.Millisecond, Max: 2 * time.Second}
+	jittered := plain
+	jittered.Jitter = true
+
+	for completed := 0; completed < 4; completed++ {
+		full := plain.Delay(completed)
+		for i := 0; i < 200; i++ {
+			got := jittered.Delay(completed)
+			if got < full/2 || got > full {
+				t.Fatalf("jittered Delay(%d) = %v, want within [%v, %v]", completed, got, full/2, full)
+			}
+		}
+	}
+}
+
+// =============================================================================
+// RetryWithPolicy Tests
+// =============================================================================
+
+// TestRetryWithPolicy_RecordsAttempts tests that stats reflect what happened.
+func TestRetryWithPolicy_RecordsAttempts(t *testing.T) {
+	t.Parallel()
+	failures := 2
+	policy := BackoffPolicy{Strategy: BackoffConstant, Base: time.Millisecond}
+
+	stats, err := RetryWithPolicy(context.Background(), 5, policy, 0, func() error {
+		if failures > 0 {
+			failures--
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("expected success after retries, got %v", err)
+	}
+	if stats.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", stats.Attempts)
+	}
+	if stats.RetryLatency != 2*time.Millisecond {
+		t.Errorf("RetryLatency = %v, want 2ms", stats.RetryLatency)
+	}
+}
+
+// TestRetryWithPolicy_LastAttemptNeverSleeps tests that no delay follows the
+// final attempt: with N attempts only N-1 sleeps accumulate.
+func TestRetryWithPolicy_LastAttemptNeverSleeps(t *testing.T) {
+	t.Parallel()
+	policy := BackoffPolicy{Strategy: BackoffConstant, Base: 10 * time.Millisecond}
+
+	stats, err := RetryWithPolicy(context.Background(), 3, policy, 0, func() error {
+		return errors.New("always fails")
+	})
+	if err == nil {
+		t.Error("expected error from exhausted retries")
+	}
+	if stats.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", stats.Attempts)
+	}
+	if stats.RetryLatency != 20*time.Millisecond {
+		t.Errorf("RetryLatency = %v, want 20ms (two sleeps, none after the last attempt)", stats.RetryLatency)
+	}
+
+	// A single attempt never sleeps at all, however large the base.
+	start := time.Now()
+	stats, _ = RetryWithPolicy(context.Background(), 1, BackoffPolicy{Base: time.Second}, 0, func() error {
+		return errors.New("fails")
+	})
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("single attempt slept for %v", elapsed)
+	}
+	if stats.RetryLatency != 0 {
+		t.Errorf("RetryLatency = %v, want 0 for a single attempt", stats.RetryLatency)
+	}
+}
+
+// TestRetryWithPolicy_BudgetCapsSleep tests that the cumulative sleep budget
+// truncates the last delay and stops retrying once spent.
+func TestRetryWithPolicy_BudgetCapsSleep(t *testing.T) {
+	t.Parallel()
+	policy := BackoffPolicy{Strategy: BackoffConstant, Base: 100 * time.Millisecond}
+
+	stats, err := RetryWithPolicy(context.Background(), 10, policy, 50*time.Millisecond, func() error {
+		return errors.New("always fails")
+	})
+	if err == nil {
+		t.Error("expected error from exhausted retry budget")
+	}
+	if stats.RetryLatency > 50*time.Millisecond {
+		t.Errorf("RetryLatency = %v, exceeds 50ms budget", stats.RetryLatency)
+	}
+	if stats.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2 (budget spent after first truncated delay)", stats.Attempts)
+	}
+}
+
+// TestRetryWithPolicy_ContextCancellation tests that a cancelled context
+// stops the loop with the context error.
+func TestRetryWithPolicy_ContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stats, err := RetryWithPolicy(ctx, 3, BackoffPolicy{}, 0, func() error {
+		return errors.New("should not run")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if stats.Attempts != 0 {
+		t.Errorf("Attempts = %d, want 0", stats.Attempts)
+	}
+}
+
+// =============================================================================
+// Payload Stamping Tests
+// =============================================================================
+
+// TestStampRetryStats tests that Extras is only allocated when retries
+// actually happened.
+func TestStampRetryStats(t *testing.T) {
+	t.Parallel()
+
+	single := GetPulseHTTPPayload()
+	stampRetryStats(&single, RetryStats{Attempts: 1})
+	if single.Extras != nil {
+		t.Errorf("single-attempt run must not allocate Extras, got %v", single.Extras)
+	}
+
+	stamped := GetPulseHTTPPayload()
+	stampRetryStats(&stamped, RetryStats{Attempts: 3, RetryLatency: 150 * time.Millisecond})
+	if stamped.Kind != KindPulse || stamped.Driver != "http" {
+		t.Errorf("stamped payload lost identity fields: %+v", stamped)
+	}
+	if stamped.Extra("attempts") != 3 {
+		t.Errorf("attempts = %v, want 3", stamped.Extra("attempts"))
+	}
+	if stamped.Extra("retry_latency_ms") != int64(150) {
+		t.Errorf("retry_latency_ms = %v, want 150", stamped.Extra("retry_latency_ms"))
+	}
+}
+
+// TestRetryWithPolicy_LastAttemptErr verifies the stats carry the error the
+// retries were absorbing, on both the eventual-success and exhaustion paths.
+func TestRetryWithPolicy_LastAttemptErr(t *testing.T) {
+	t.Parallel()
+	transient := errors.New("transient")
+	policy := BackoffPolicy{Strategy: BackoffNone}
+
+	failures := 2
+	stats, err := RetryWithPolicy(context.Background(), 5, policy, 0, func() error {
+		if failures > 0 {
+			failures--
+			return transient
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if stats.LastAttemptErr != transient {
+		t.Errorf("LastAttemptErr = %v, want the transient error", stats.LastAttemptErr)
+	}
+
+	stats, err = RetryWithPolicy(context.Background(), 2, policy, 0, func() error {
+		return transient
+	})
+	if err != transient {
+		t.Fatalf("expected exhaustion error, got %v", err)
+	}
+	if stats.LastAttemptErr != transient {
+		t.Errorf("LastAttemptErr = %v, want the exhaustion error", stats.LastAttemptErr)
+	}
+
+	// A first-attempt success records no attempt error.
+	stats, err = RetryWithPolicy(context.Background(), 3, policy, 0, func() error { return nil })
+	if err != nil || stats.LastAttemptErr != nil {
+		t.Errorf("first-attempt success: err=%v LastAttemptErr=%v, want nil/nil", err, stats.LastAttemptErr)
+	}
+}
+
+// TestStampRetryStats_TypedAttemptFields verifies the typed payload fields
+// are always stamped while the Extras entries stay reserved for
+// multi-attempt runs.
+func TestStampRetryStats_TypedAttemptFields(t *testing.T) {
+	t.Parallel()
+	transient := errors.New("transient")
+
+	var single Payload
+	stampRetryStats(&single, RetryStats{Attempts: 1})
+	if single.Attempts != 1 || single.LastAttemptError != nil {
+		t.Errorf("single attempt: Attempts=%d LastAttemptError=%v, want 1/nil", single.Attempts, single.LastAttemptError)
+	}
+	if single.Extras != nil {
+		t.Error("single attempt must not allocate Extras")
+	}
+
+	var retried Payload
+	stampRetryStats(&retried, RetryStats{Attempts: 3, LastAttemptErr: transient})
+	if retried.Attempts != 3 || retried.LastAttemptError != transient {
+		t.Errorf("retried: Attempts=%d LastAttemptError=%v, want 3/transient", retried.Attempts, retried.LastAttemptError)
+	}
+	m := retried.Map()
+	if m["attempts_made"] != 3 || m["last_attempt_error"] != "transient" {
+		t.Errorf("Map() = %v, missing attempt fields", m)
+	}
+}
@@ -0,0 +1,242 @@
+package jobs
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cpra/internal/interning"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// emailDefaultTimeout bounds connecting and sending when no timeout is
+// configured.
+const emailDefaultTimeout = 10 * time.Second
+
+// emailDefaultPort is the SMTP submission port used when the manifest omits
+// smtp_port.
+const emailDefaultPort = 587
+
+// smtpConn is a pooled SMTP connection to one server. A single red code can
+// fan out to thousands of monitors; reusing one connection per server keeps
+// that burst from opening thousands of TCP+TLS sessions. The mutex serializes
+// senders because the SMTP protocol allows only one transaction at a time.
+type smtpConn struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	client *smtp.Client
+}
+
+// smtpConns maps "host:port" to its shared *smtpConn.
+var smtpConns sync.Map
+
+// getSMTPConn returns the shared connection slot for addr, creating it on
+// first use.
+func getSMTPConn(addr string) *smtpConn {
+	if v, ok := smtpConns.Load(addr); ok {
+		return v.(*smtpConn)
+	}
+	v, _ := smtpConns.LoadOrStore(addr, &smtpConn{})
+	return v.(*smtpConn)
+}
+
+// dial establishes a fresh SMTP session bounded by deadline, negotiating
+// STARTTLS and AUTH as configured. Caller must hold p.mu.
+func (p *smtpConn) dial(addr, host string, startTLS bool, auth smtp.Auth, deadline time.Time) error {
+	conn, err := net.DialTimeout("tcp", addr, time.Until(deadline))
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(deadline)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	if startTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+				_ = client.Close()
+				return err
+			}
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(auth); err != nil {
+				_ = client.Close()
+				return err
+			}
+		}
+	}
+	p.conn = conn
+	p.client = client
+	return nil
+}
+
+// close drops the pooled session so the next sender re-dials. Caller must
+// hold p.mu.
+func (p *smtpConn) close() {
+	if p.client != nil {
+		_ = p.client.Close()
+	}
+	p.conn = nil
+	p.client = nil
+}
+
+// CodeEmailJob sends alert notifications via SMTP. The plaintext body comes
+// from the shared code alert template; the subject carries the code color and
+// monitor name. Connections are reused per server through smtpConns.
+type CodeEmailJob struct {
+	EnqueueTime   time.Time
+	StartTime     time.Time
+	Monitor       string
+	Message       string
+	Color         string
+	Host          string
+	From          string
+	SubjectPrefix string
+	Username      string
+	Password      string
+	To            []string
+	Labels        interning.Labels
+	Port          int
+	Timeout       time.Duration
+	StartTLS      bool
+	Entity        ecs.Entity
+}
+
+// buildMessage renders the full RFC 5322 message: headers plus the plaintext
+// alert body.
+func (c *CodeEmailJob) buildMessage() []byte {
+	tpl := codeAlertTemplateFor(c.Color)
+	var b strings.Builder
+	b.WriteString("From: ")
+	b.WriteString(c.From)
+	b.WriteString("\r\nTo: ")
+	b.WriteString(strings.Join(c.To, ", "))
+	b.WriteString("\r\nSubject: ")
+	if c.SubjectPrefix != "" {
+		b.WriteString(c.SubjectPrefix)
+		b.WriteString(" ")
+	}
+	b.WriteString("[")
+	b.WriteString(strings.ToUpper(c.Color))
+	b.WriteString("] ")
+	b.WriteString(c.Monitor)
+	b.WriteString(": ")
+	b.WriteString(tpl.Title)
+	b.WriteString("\r\nDate: ")
+	b.WriteString(time.Now().Format(time.RFC1123Z))
+	b.WriteString("\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(buildCodeNotificationMessage(c.Monitor, c.Labels, tpl), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// deliver runs one MAIL/RCPT/DATA transaction on an established session.
+func (c *CodeEmailJob) deliver(client *smtp.Client) error {
+	if err := client.Mail(c.From); err != nil {
+		return err
+	}
+	for _, rcpt := range c.To {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(c.buildMessage()); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Execute sends the alert via SMTP, reusing the pooled connection for the
+// server where possible.
+func (c *CodeEmailJob) Execute(ctx context.Context) Result {
+	payload := GetCodeEmailPayload()
+	payload.Color = c.Color
+
+	if c.Host == "" || c.From == "" || len(c.To) == 0 {
+		return Result{Ent: c.Entity, Err: ErrEmailConfigMissing, Payload: payload}
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = emailDefaultTimeout
+	}
+	port := c.Port
+	if port <= 0 {
+		port = emailDefaultPort
+	}
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+
+	// Acquire global dial slot to prevent CPU spikes during network outages.
+	if !GetDialLimiter().Acquire(ctx) {
+		return Result{Ent: c.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer GetDialLimiter().Release()
+
+	pc := getSMTPConn(addr)
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	// Try the pooled session first; a stale or dropped connection surfaces
+	// as an error here, in which case we re-dial once.
+	if pc.client != nil {
+		_ = pc.conn.SetDeadline(deadline)
+		if err := c.deliver(pc.client); err == nil {
+			return Result{Ent: c.Entity, Err: nil, Payload: payload}
+		}
+		pc.close()
+	}
+
+	if err := pc.dial(addr, c.Host, c.StartTLS, auth, deadline); err != nil {
+		payload.SetExtra("smtp_error", err.Error())
+		return Result{Ent: c.Entity, Err: ErrEmailSendFailed, Payload: payload, Class: InfraErrorClass(err)}
+	}
+	if err := c.deliver(pc.client); err != nil {
+		pc.close()
+		payload.SetExtra("smtp_error", err.Error())
+		return Result{Ent: c.Entity, Err: ErrEmailSendFailed, Payload: payload, Class: InfraErrorClass(err)}
+	}
+	return Result{Ent: c.Entity, Err: nil, Payload: payload}
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (c *CodeEmailJob) Copy() Job { job := *c; return &job }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (c *CodeEmailJob) GetEnqueueTime() time.Time { return c.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (c *CodeEmailJob) SetEnqueueTime(t time.Time) { c.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (c *CodeEmailJob) GetStartTime() time.Time { return c.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (c *CodeEmailJob) SetStartTime(t time.Time) { c.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (c *CodeEmailJob) IsNil() bool { return c == nil }
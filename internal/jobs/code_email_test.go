@@ -0,0 +1,321 @@
+package jobs
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// smtpTestMessage is one message accepted by the test server.
+type smtpTestMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// smtpTestServer is a minimal in-process SMTP server for exercising
+// CodeEmailJob. It advertises AUTH PLAIN when credentials are configured and
+// accepts multiple transactions per connection so reuse can be observed.
+type smtpTestServer struct {
+	listener net.Listener
+	user     string
+	pass     string
+	conns    atomic.Int32
+
+	mu       sync.Mutex
+	messages []smtpTestMessage
+	auths    []string
+}
+
+// startSMTPTestServer listens on a loopback port and serves until the test
+// ends. Empty user disables AUTH advertisement.
+func startSMTPTestServer(t *testing.T, user, pass string) *smtpTestServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &smtpTestServer{listener: ln, user: user, pass: pass}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.conns.Add(1)
+			go s.handle(conn)
+		}
+	}()
+	t.Cleanup(func() { _ = ln.Close() })
+	return s
+}
+
+// hostPort returns the server's listen host and port.
+func (s *smtpTestServer) hostPort() (string, int) {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// snapshot returns copies of the recorded messages and AUTH payloads.
+func (s *smtpTestServer) snapshot() ([]smtpTestMessage, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpTestMessage(nil), s.messages...), append([]string(nil), s.auths...)
+}
+
+// handle speaks just enough SMTP for one connection's lifetime.
+func (s *smtpTestServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(line string) {
+		_, _ = w.WriteString(line + "\r\n")
+		_ = w.Flush()
+	}
+	reply("220 cpra-test ESMTP")
+
+	var msg smtpTestMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(verb, "EHLO"):
+			_, _ = w.WriteString("250-cpra-test\r\n")
+			if s.user != "" {
+				_, _ = w.WriteString("250-AUTH PLAIN\r\n")
+			}
+			reply("250 OK")
+		case strings.HasPrefix(verb, "HELO"):
+			reply("250 cpra-test")
+		case strings.HasPrefix(verb, "AUTH PLAIN"):
+			payload := strings.TrimSpace(line[len("AUTH PLAIN"):])
+			decoded, err := base64.StdEncoding.DecodeString(payload)
+			if err != nil {
+				reply("501 bad base64")
+				continue
+			}
+			s.mu.Lock()
+			s.auths = append(s.auths, string(decoded))
+			s.mu.Unlock()
+			parts := strings.Split(string(decoded), "\x00")
+			if len(parts) == 3 && parts[1] == s.user && parts[2] == s.pass {
+				reply("235 2.7.0 authenticated")
+			} else {
+				reply("535 5.7.8 authentication failed")
+			}
+		case strings.HasPrefix(verb, "MAIL FROM:"):
+			msg = smtpTestMessage{From: addrBetween(line)}
+			reply("250 OK")
+		case strings.HasPrefix(verb, "RCPT TO:"):
+			msg.To = append(msg.To, addrBetween(line))
+			reply("250 OK")
+		case verb == "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				dl, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if strings.TrimRight(dl, "\r\n") == "." {
+					break
+				}
+				data.WriteString(dl)
+			}
+			msg.Data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 queued")
+		case verb == "RSET" || verb == "NOOP":
+			reply("250 OK")
+		case verb == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 unrecognized")
+		}
+	}
+}
+
+// addrBetween extracts the address from a MAIL FROM:<a> or RCPT TO:<a> line.
+func addrBetween(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.Index(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+// newEmailJob builds a red-alert email job against the given server.
+func newEmailJob(host string, port int) *CodeEmailJob {
+	return &CodeEmailJob{
+		Monitor: "payments-api",
+		Color:   "red",
+		Host:    host,
+		Port:    port,
+		From:    "cpra@example.test",
+		To:      []string{"oncall@example.test", "sre@example.test"},
+		Timeout: 2 * time.Second,
+	}
+}
+
+// TestCodeEmailJob_SendsMessage tests envelope, headers and body of a
+// delivered alert.
+func TestCodeEmailJob_SendsMessage(t *testing.T) {
+	t.Parallel()
+	server := startSMTPTestServer(t, "", "")
+	host, port := server.hostPort()
+
+	job := newEmailJob(host, port)
+	job.SubjectPrefix = "[cpra]"
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+
+	messages, _ := server.snapshot()
+	if len(messages) != 1 {
+		t.Fatalf("server got %d messages, want 1", len(messages))
+	}
+	msg := messages[0]
+	if msg.From != "cpra@example.test" {
+		t.Errorf("envelope from = %q", msg.From)
+	}
+	if len(msg.To) != 2 || msg.To[0] != "oncall@example.test" || msg.To[1] != "sre@example.test" {
+		t.Errorf("envelope to = %v", msg.To)
+	}
+	wantSubject := "Subject: [cpra] [RED] payments-api: CRITICAL ALERT"
+	if !strings.Contains(msg.Data, wantSubject) {
+		t.Errorf("message missing subject %q:\n%s", wantSubject, msg.Data)
+	}
+	if !strings.Contains(msg.Data, "To: oncall@example.test, sre@example.test") {
+		t.Errorf("message missing To header:\n%s", msg.Data)
+	}
+	if !strings.Contains(msg.Data, "Monitor: payments-api") ||
+		!strings.Contains(msg.Data, "Severity: CRITICAL") {
+		t.Errorf("message body missing alert fields:\n%s", msg.Data)
+	}
+}
+
+// TestCodeEmailJob_AuthNegotiation tests PLAIN authentication against a
+// server that advertises AUTH.
+func TestCodeEmailJob_AuthNegotiation(t *testing.T) {
+	t.Parallel()
+	server := startSMTPTestServer(t, "alerts", "hunter2")
+	host, port := server.hostPort()
+
+	job := newEmailJob(host, port)
+	job.Username = "alerts"
+	job.Password = "hunter2"
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+
+	messages, auths := server.snapshot()
+	if len(auths) != 1 {
+		t.Fatalf("server saw %d AUTH attempts, want 1", len(auths))
+	}
+	if auths[0] != "\x00alerts\x00hunter2" {
+		t.Errorf("AUTH payload = %q, want PLAIN identity", auths[0])
+	}
+	if len(messages) != 1 {
+		t.Errorf("server got %d messages, want 1", len(messages))
+	}
+
+	// A fresh server avoids reusing the already-authenticated session above.
+	badServer := startSMTPTestServer(t, "alerts", "hunter2")
+	badHost, badPort := badServer.hostPort()
+	bad := newEmailJob(badHost, badPort)
+	bad.Username = "alerts"
+	bad.Password = "wrong"
+	result := bad.Execute(context.Background())
+	if !errors.Is(result.Err, ErrEmailSendFailed) {
+		t.Errorf("Execute with bad credentials = %v, want ErrEmailSendFailed", result.Err)
+	}
+}
+
+// TestCodeEmailJob_ConnectionReuse tests that sequential sends to the same
+// server share one TCP connection.
+func TestCodeEmailJob_ConnectionReuse(t *testing.T) {
+	t.Parallel()
+	server := startSMTPTestServer(t, "", "")
+	host, port := server.hostPort()
+
+	job := newEmailJob(host, port)
+	for i := 0; i < 3; i++ {
+		if result := job.Execute(context.Background()); result.Err != nil {
+			t.Fatalf("Execute %d failed: %v", i, result.Err)
+		}
+	}
+
+	messages, _ := server.snapshot()
+	if len(messages) != 3 {
+		t.Errorf("server got %d messages, want 3", len(messages))
+	}
+	if got := server.conns.Load(); got != 1 {
+		t.Errorf("server saw %d connections, want 1 (reuse)", got)
+	}
+}
+
+// TestCodeEmailJob_MissingConfig tests the config sentinel.
+func TestCodeEmailJob_MissingConfig(t *testing.T) {
+	t.Parallel()
+	job := &CodeEmailJob{Monitor: "test-monitor", Color: "red"}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrEmailConfigMissing) {
+		t.Errorf("Execute error = %v, want ErrEmailConfigMissing", result.Err)
+	}
+}
+
+// TestCreateCodeJob_EmailThreadsConfig tests the factory wiring, including
+// credentials sourced from the environment.
+func TestCreateCodeJob_EmailThreadsConfig(t *testing.T) {
+	t.Setenv("CPRA_TEST_SMTP_PASSWORD", "from-env")
+	config := schema.CodeConfig{
+		Notify: "email",
+		Config: &schema.CodeNotificationEmail{
+			SMTPHost:      "mail.example.test",
+			SMTPPort:      2525,
+			From:          "cpra@example.test",
+			To:            []string{"oncall@example.test"},
+			SubjectPrefix: "[prod]",
+			Username:      "alerts",
+			PasswordEnv:   "CPRA_TEST_SMTP_PASSWORD",
+			StartTLS:      true,
+		},
+	}
+	job, err := CreateCodeJob("web-app", config, ecs.Entity{}, "yellow")
+	if err != nil {
+		t.Fatalf("CreateCodeJob failed: %v", err)
+	}
+	emailJob, ok := job.(*CodeEmailJob)
+	if !ok {
+		t.Fatalf("expected *CodeEmailJob, got %T", job)
+	}
+	if emailJob.Host != "mail.example.test" || emailJob.Port != 2525 || !emailJob.StartTLS {
+		t.Errorf("server config not threaded: %+v", emailJob)
+	}
+	if emailJob.Username != "alerts" || emailJob.Password != "from-env" {
+		t.Errorf("credentials not threaded: user=%q pass=%q", emailJob.Username, emailJob.Password)
+	}
+	if emailJob.SubjectPrefix != "[prod]" || len(emailJob.To) != 1 {
+		t.Errorf("addressing not threaded: %+v", emailJob)
+	}
+	ReleaseCodeJob(job)
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// CodeFanoutJob delivers one code alert to several notification targets.
+// Notify groups can resolve more than one target for a color; wrapping the
+// per-target jobs keeps a single job — and so a single result — in flight
+// per entity, which the code FSM relies on. The result succeeds only when
+// every target succeeds, so partial delivery failures are retried like any
+// other failed alert.
+type CodeFanoutJob struct {
+	Jobs        []Job // one per target, all for the same monitor and color
+	Entity      ecs.Entity
+	EnqueueTime time.Time
+	StartTime   time.Time
+}
+
+// Execute runs every target job in order and aggregates their outcomes
+// into one result. The payload is the first target's, annotated with the
+// target counts, so the color and kind routing stay intact.
+func (j *CodeFanoutJob) Execute(ctx context.Context) Result {
+	start := time.Now()
+	var (
+		first  Result
+		failed []error
+	)
+	for i, inner := range j.Jobs {
+		res := inner.Execute(ctx)
+		if i == 0 {
+			first = res
+		}
+		if res.Err != nil {
+			failed = append(failed, res.Err)
+		}
+	}
+	result := Result{Ent: j.Entity, Duration: time.Since(start), Payload: first.Payload}
+	result.Payload.SetExtra("targets", len(j.Jobs))
+	if len(failed) > 0 {
+		result.Payload.SetExtra("failed_targets", len(failed))
+		result.Err = fmt.Errorf("%d/%d notification targets failed: %w",
+			len(failed), len(j.Jobs), errors.Join(failed...))
+	}
+	return result
+}
+
+// Copy returns a copy wrapping copies of every target job.
+func (j *CodeFanoutJob) Copy() Job {
+	job := *j
+	job.Jobs = make([]Job, len(j.Jobs))
+	for i, inner := range j.Jobs {
+		job.Jobs[i] = inner.Copy()
+	}
+	return &job
+}
+
+// GetEnqueueTime returns when the job was enqueued.
+func (j *CodeFanoutJob) GetEnqueueTime() time.Time { return j.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (j *CodeFanoutJob) SetEnqueueTime(t time.Time) { j.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (j *CodeFanoutJob) GetStartTime() time.Time { return j.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (j *CodeFanoutJob) SetStartTime(t time.Time) { j.StartTime = t }
+
+// IsNil returns true if the wrapper carries no target jobs.
+func (j *CodeFanoutJob) IsNil() bool { return j == nil || len(j.Jobs) == 0 }
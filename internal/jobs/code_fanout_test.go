@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// stubCodeJob is a minimal Job for fan-out tests: it records that it ran
+// and returns a fixed error.
+type stubCodeJob struct {
+	err         error
+	executed    bool
+	enqueueTime time.Time
+	startTime   time.Time
+}
+
+func (s *stubCodeJob) Execute(_ context.Context) Result {
+	s.executed = true
+	return Result{Err: s.err, Payload: Payload{Kind: KindCode, Color: "red"}}
+}
+func (s *stubCodeJob) Copy() Job                  { cp := *s; return &cp }
+func (s *stubCodeJob) GetEnqueueTime() time.Time  { return s.enqueueTime }
+func (s *stubCodeJob) SetEnqueueTime(t time.Time) { s.enqueueTime = t }
+func (s *stubCodeJob) GetStartTime() time.Time    { return s.startTime }
+func (s *stubCodeJob) SetStartTime(t time.Time)   { s.startTime = t }
+func (s *stubCodeJob) GetEntity() ecs.Entity      { return ecs.Entity{} }
+func (s *stubCodeJob) IsNil() bool                { return s == nil }
+
+// TestCodeFanoutJob_AllTargetsRun verifies every target executes even when
+// an earlier one fails, and that partial failure surfaces as one error.
+func TestCodeFanoutJob_AllTargetsRun(t *testing.T) {
+	t.Parallel()
+	failing := &stubCodeJob{err: errors.New("webhook timed out")}
+	ok := &stubCodeJob{}
+	fanout := &CodeFanoutJob{Jobs: []Job{failing, ok}, Entity: ecs.Entity{}}
+
+	res := fanout.Execute(context.Background())
+	if !failing.executed || !ok.executed {
+		t.Fatalf("executed = %v/%v, want both targets to run", failing.executed, ok.executed)
+	}
+	if res.Err == nil || !strings.Contains(res.Err.Error(), "1/2 notification targets failed") {
+		t.Errorf("Err = %v, want aggregated target failure", res.Err)
+	}
+	if got := res.Payload.Extra("failed_targets"); got != 1 {
+		t.Errorf("payload failed_targets = %v, want 1", got)
+	}
+	if res.Payload.Color != "red" {
+		t.Errorf("payload color = %q, want the first target's color", res.Payload.Color)
+	}
+}
+
+// TestCodeFanoutJob_AllSucceed verifies a clean fan-out reports success.
+func TestCodeFanoutJob_AllSucceed(t *testing.T) {
+	t.Parallel()
+	fanout := &CodeFanoutJob{Jobs: []Job{&stubCodeJob{}, &stubCodeJob{}}}
+	res := fanout.Execute(context.Background())
+	if res.Err != nil {
+		t.Fatalf("Execute failed: %v", res.Err)
+	}
+	if got := res.Payload.Extra("targets"); got != 2 {
+		t.Errorf("payload targets = %v, want 2", got)
+	}
+}
@@ -0,0 +1,241 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"cpra/internal/interning"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// codeAlertTemplate holds the template for code alert messages.
+type codeAlertTemplate struct {
+	Title     string
+	Status    string
+	Severity  string
+	Summary   string
+	Action    string
+	NextSteps string
+}
+
+// codeAlertTemplateFor returns the appropriate template for the given color.
+func codeAlertTemplateFor(color string) codeAlertTemplate {
+	switch strings.ToLower(color) {
+	case "red":
+		return codeAlertTemplate{
+			Title:     "CRITICAL ALERT",
+			Status:    "FAILED",
+			Severity:  "critical",
+			Summary:   "Service outage detected after repeated health check failures and interventions",
+			Action:    "Escalate immediately and engage on-call responders",
+			NextSteps: "Perform manual recovery and review related service telemetry",
+		}
+	case "yellow":
+		return codeAlertTemplate{
+			Title:     "DEGRADED ALERT",
+			Status:    "DEGRADED",
+			Severity:  "warning",
+			Summary:   "Service health checks are failing consecutively beyond safe thresholds",
+			Action:    "Investigate partial outage or performance regression",
+			NextSteps: "Validate dependencies, review recent changes, and monitor closely",
+		}
+	case "green":
+		return codeAlertTemplate{
+			Title:     "RECOVERY NOTICE",
+			Status:    "RECOVERED",
+			Severity:  "info",
+			Summary:   "Service returned to a healthy state after previous failures",
+			Action:    "No immediate action required",
+			NextSteps: "Continue monitoring stability and capture incident follow-up notes",
+		}
+	case "cyan":
+		return codeAlertTemplate{
+			Title:     "INTERVENTION SUCCESS",
+			Status:    "RESTORED",
+			Severity:  "info",
+			Summary:   "Automated intervention completed and service health checks are passing",
+			Action:    "Confirm downstream systems are stable",
+			NextSteps: "Document intervention details and verify customer impact is resolved",
+		}
+	case "gray":
+		return codeAlertTemplate{
+			Title:     "MAINTENANCE MODE",
+			Status:    "MAINTENANCE",
+			Severity:  "info",
+			Summary:   "Monitor is intentionally suppressed during planned maintenance",
+			Action:    "No action required during maintenance window",
+			NextSteps: "Re-enable monitoring once maintenance activities conclude",
+		}
+	default:
+		return codeAlertTemplate{
+			Title:     "STATUS UPDATE",
+			Status:    "UNKNOWN",
+			Severity:  "unknown",
+			Summary:   "Monitor generated an unspecified status update",
+			Action:    "Review monitor configuration and recent events",
+			NextSteps: "Validate service state and adjust alert routing if required",
+		}
+	}
+}
+
+// invertedCodeAlertTemplateFor returns the template for inverted monitors.
+// There a "failure" means the endpoint responded when it should not have,
+// so the outage wording of the standard templates would mislead responders.
+// Colors outside the pulse-driven set (gray, cyan) keep their standard text.
+func invertedCodeAlertTemplateFor(color string) codeAlertTemplate {
+	tpl := codeAlertTemplateFor(color)
+	switch strings.ToLower(color) {
+	case "red":
+		tpl.Summary = "Unexpected response from decommissioned endpoint that should be down"
+		tpl.Action = "Verify the endpoint was fully decommissioned and take it back offline"
+		tpl.NextSteps = "Check for restarted processes, DNS reuse, or rolled-back infrastructure changes"
+	case "yellow":
+		tpl.Summary = "Endpoint expected to be down is responding to health checks"
+		tpl.Action = "Investigate why the decommissioned endpoint became reachable"
+		tpl.NextSteps = "Confirm shutdown procedures completed and watch for further responses"
+	case "green":
+		tpl.Summary = "Endpoint is unreachable again, as expected"
+		tpl.Action = "No immediate action required"
+		tpl.NextSteps = "Continue monitoring that the endpoint stays down"
+	}
+	return tpl
+}
+
+// crashCodeAlertTemplateFor returns the template for the red code dispatched
+// when a monitor is auto-disabled after repeated driver panics. The standard
+// red wording describes a target outage, which is not what happened: the
+// check itself kept crashing, so the target's health is unknown.
+func crashCodeAlertTemplateFor(color string) codeAlertTemplate {
+	tpl := codeAlertTemplateFor(color)
+	if strings.ToLower(color) == "red" {
+		tpl.Status = "CRASHED"
+		tpl.Summary = "Pulse check crashed repeatedly; monitor auto-disabled to protect workers"
+		tpl.Action = "Review the monitor's configuration and the recorded panic message"
+		tpl.NextSteps = "Fix or remove the crashing check, then re-enable the monitor"
+	}
+	return tpl
+}
+
+// buildCodeNotificationMessage builds a human-readable alert message.
+func buildCodeNotificationMessage(monitor string, labels interning.Labels, tpl codeAlertTemplate) string {
+	var b strings.Builder
+	// Pre-size approximately to reduce reallocations
+	b.Grow(len(tpl.Title) + len("\nMonitor: ") + len(monitor) +
+		len("\nStatus: ") + len(tpl.Status) + len("\nSeverity: ") + len(tpl.Severity) +
+		len("\nSummary: ") + len(tpl.Summary) + len("\nRecommended Action: ") + len(tpl.Action) +
+		len("\nNext Steps: ") + len(tpl.NextSteps) + 8)
+	b.WriteString(tpl.Title)
+	b.WriteString("\nMonitor: ")
+	b.WriteString(monitor)
+	b.WriteString("\nStatus: ")
+	b.WriteString(tpl.Status)
+	b.WriteString("\nSeverity: ")
+	b.WriteString(strings.ToUpper(tpl.Severity))
+	b.WriteString("\nSummary: ")
+	b.WriteString(tpl.Summary)
+	b.WriteString("\nRecommended Action: ")
+	b.WriteString(tpl.Action)
+	b.WriteString("\nNext Steps: ")
+	b.WriteString(tpl.NextSteps)
+	if len(labels) > 0 {
+		b.WriteString("\nLabels: ")
+		b.WriteString(labels.String())
+	}
+	return b.String()
+}
+
+// CodeLogJob writes alert notifications to a log file in JSON format.
+// It uses an async LogManager to avoid blocking on file I/O.
+type CodeLogJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Status      string
+	Monitor     string
+	Color       string
+	Severity    string
+	Summary     string
+	Action      string
+	NextSteps   string
+	File        string
+	Labels      interning.Labels
+	Entity      ecs.Entity
+}
+
+// Execute writes the alert to the log file.
+func (c *CodeLogJob) Execute(_ context.Context) Result {
+	payload := GetCodeLogPayload()
+	payload.Color = c.Color
+	payload.SetExtra("severity", c.Severity)
+	payload.SetExtra("status", c.Status)
+
+	// Build message on-demand
+	tpl := codeAlertTemplate{
+		Title:     codeAlertTemplateFor(c.Color).Title,
+		Status:    c.Status,
+		Severity:  c.Severity,
+		Summary:   c.Summary,
+		Action:    c.Action,
+		NextSteps: c.NextSteps,
+	}
+	message := buildCodeNotificationMessage(c.Monitor, c.Labels, tpl)
+
+	now := time.Now().UTC()
+	entry := struct {
+		Timestamp string           `json:"timestamp"`
+		Type      string           `json:"type"`
+		Monitor   string           `json:"monitor"`
+		Color     string           `json:"color"`
+		Status    string           `json:"status"`
+		Severity  string           `json:"severity"`
+		Summary   string           `json:"summary"`
+		Action    string           `json:"action"`
+		NextSteps string           `json:"next_steps,omitempty"`
+		Labels    interning.Labels `json:"labels,omitempty"`
+		Message   string           `json:"message,omitempty"`
+	}{
+		Timestamp: now.Format(time.RFC3339Nano),
+		Type:      "code",
+		Monitor:   c.Monitor,
+		Color:     c.Color,
+		Status:    c.Status,
+		Severity:  c.Severity,
+		Summary:   c.Summary,
+		Action:    c.Action,
+		NextSteps: c.NextSteps,
+		Labels:    c.Labels,
+		Message:   message,
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return Result{Ent: c.Entity, Err: ErrLogMarshalFailed, Payload: payload}
+	}
+
+	line = append(line, '\n')
+
+	// Use asynchronous log manager
+	GetLogManager().WriteLog(c.File, line)
+
+	return Result{Ent: c.Entity, Err: nil, Payload: payload}
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (c *CodeLogJob) Copy() Job { job := *c; return &job }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (c *CodeLogJob) GetEnqueueTime() time.Time { return c.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (c *CodeLogJob) SetEnqueueTime(t time.Time) { c.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (c *CodeLogJob) GetStartTime() time.Time { return c.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (c *CodeLogJob) SetStartTime(t time.Time) { c.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (c *CodeLogJob) IsNil() bool { return c == nil }
@@ -0,0 +1,260 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cpra/internal/interning"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// PagerDutyEventsURL is the default Events API v2 endpoint. Jobs carry their
+// own URL so tests (and regional endpoints) can override it.
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// pagerDutyDefaultTimeout bounds the event POST when no timeout is configured.
+const pagerDutyDefaultTimeout = 10 * time.Second
+
+// pagerDutyMaxAttempts is the total number of delivery attempts; 429 and 5xx
+// responses are retried, other failures are final.
+const pagerDutyMaxAttempts = 3
+
+// pagerDutyRetryAfterCap bounds how long a Retry-After header can delay a
+// retry, keeping the job within its timeout budget.
+const pagerDutyRetryAfterCap = 5 * time.Second
+
+// pagerDutyActionFor maps code colors to event actions: recovery colors
+// resolve the incident opened by an earlier trigger with the same dedup key.
+func pagerDutyActionFor(color string) string {
+	switch color {
+	case "green", "cyan":
+		return "resolve"
+	default:
+		return "trigger"
+	}
+}
+
+// pagerDutySeverityFor maps template severities onto the values the Events
+// API accepts (critical, error, warning, info).
+func pagerDutySeverityFor(severity string) string {
+	switch severity {
+	case "critical", "error", "warning", "info":
+		return severity
+	default:
+		return "warning"
+	}
+}
+
+// pagerDutyDedupKey derives a stable dedup key from the monitor name, so
+// repeated triggers update one incident and a later resolve closes it.
+func pagerDutyDedupKey(monitor string) string {
+	return "cpra-" + monitor
+}
+
+// pagerDutyEvent is the Events API v2 request body.
+type pagerDutyEvent struct {
+	RoutingKey  string                `json:"routing_key"`
+	EventAction string                `json:"event_action"`
+	DedupKey    string                `json:"dedup_key"`
+	Payload     pagerDutyEventPayload `json:"payload"`
+}
+
+type pagerDutyEventPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// pagerDutyResponse is the subset of the API response we read back.
+type pagerDutyResponse struct {
+	Status   string `json:"status"`
+	DedupKey string `json:"dedup_key"`
+}
+
+// CodePagerDutyJob sends alert notifications through the PagerDuty Events
+// API v2. Red/yellow alerts trigger an incident keyed by the monitor name;
+// green/cyan alerts resolve it. 429 and 5xx responses are retried with
+// backoff, honoring Retry-After.
+type CodePagerDutyJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Monitor     string
+	Message     string
+	Color       string
+	RoutingKey  string
+	URL         string
+	Status      string
+	Severity    string
+	Summary     string
+	Action      string
+	Labels      interning.Labels
+	Timeout     time.Duration
+	Entity      ecs.Entity
+}
+
+// Execute posts the event to the PagerDuty Events API.
+func (c *CodePagerDutyJob) Execute(ctx context.Context) Result {
+	action := pagerDutyActionFor(c.Color)
+	dedupKey := pagerDutyDedupKey(c.Monitor)
+	payload := GetCodePagerDutyPayload()
+	payload.Color = c.Color
+	payload.SetExtra("event_action", action)
+	payload.SetExtra("dedup_key", dedupKey)
+
+	if c.RoutingKey == "" {
+		return Result{Ent: c.Entity, Err: ErrPagerDutyRoutingKeyMissing, Payload: payload}
+	}
+
+	customDetails := map[string]string{
+		"monitor":            c.Monitor,
+		"color":              c.Color,
+		"recommended_action": c.Action,
+	}
+	// Labels ride along as custom details so responders see team/tier/runbook
+	// in the incident without opening the manifest.
+	for _, l := range c.Labels {
+		if _, taken := customDetails[l.Key]; !taken {
+			customDetails[l.Key] = l.Value
+		}
+	}
+
+	event := pagerDutyEvent{
+		RoutingKey:  c.RoutingKey,
+		EventAction: action,
+		DedupKey:    dedupKey,
+		Payload: pagerDutyEventPayload{
+			Summary:       c.Status + ": " + c.Monitor + " - " + c.Summary,
+			Source:        c.Monitor,
+			Severity:      pagerDutySeverityFor(c.Severity),
+			CustomDetails: customDetails,
+		},
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return Result{Ent: c.Entity, Err: err, Payload: payload}
+	}
+
+	url := c.URL
+	if url == "" {
+		url = PagerDutyEventsURL
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = pagerDutyDefaultTimeout
+	}
+	client, err := GetPooledHTTPClient(timeout, false, "")
+	if err != nil {
+		return Result{Ent: c.Entity, Err: err, Payload: payload}
+	}
+
+	// Acquire global dial slot to prevent CPU spikes during network outages.
+	if !AcquireHTTPDialSlot(ctx) {
+		return Result{Ent: c.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer ReleaseHTTPDialSlot()
+
+	var lastStatus int
+	var lastErr error
+	delay := 50 * time.Millisecond
+	for attempt := 0; attempt < pagerDutyMaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return Result{Ent: c.Entity, Err: ctx.Err(), Payload: payload}
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		status, respDedup, err := c.post(ctx, client, url, body)
+		if err != nil {
+			if err == context.Canceled || err == context.DeadlineExceeded {
+				return Result{Ent: c.Entity, Err: err, Payload: payload}
+			}
+			lastErr = err
+			continue
+		}
+		lastStatus = status
+		if status >= 200 && status < 300 {
+			payload.SetExtra("http_status", status)
+			if respDedup != "" {
+				payload.SetExtra("dedup_key", respDedup)
+			}
+			return Result{Ent: c.Entity, Err: nil, Payload: payload}
+		}
+		lastErr = ErrPagerDutyEventFailed
+		// Only rate limiting and server errors are worth retrying.
+		if status != http.StatusTooManyRequests && status < 500 {
+			break
+		}
+	}
+
+	if lastStatus > 0 {
+		payload.SetExtra("http_status", lastStatus)
+	}
+	return Result{Ent: c.Entity, Err: lastErr, Payload: payload, Class: InfraErrorClass(lastErr)}
+}
+
+// post performs one event POST and returns the HTTP status and the dedup key
+// echoed by the API. A positive Retry-After on a retryable response stretches
+// the next backoff delay.
+func (c *CodePagerDutyJob) post(ctx context.Context, client *http.Client, url string, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", ErrFailedToCreateHTTPRequest
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, "", ctx.Err()
+		}
+		return 0, "", err
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close()
+
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	if ra := resp.Header.Get("Retry-After"); ra != "" && retryable {
+		if secs, err := strconv.Atoi(ra); err == nil && secs > 0 {
+			wait := time.Duration(secs) * time.Second
+			if wait > pagerDutyRetryAfterCap {
+				wait = pagerDutyRetryAfterCap
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+		}
+	}
+
+	var parsed pagerDutyResponse
+	_ = json.Unmarshal(respBody, &parsed)
+	return resp.StatusCode, parsed.DedupKey, nil
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (c *CodePagerDutyJob) Copy() Job { job := *c; return &job }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (c *CodePagerDutyJob) GetEnqueueTime() time.Time { return c.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (c *CodePagerDutyJob) SetEnqueueTime(t time.Time) { c.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (c *CodePagerDutyJob) GetStartTime() time.Time { return c.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (c *CodePagerDutyJob) SetStartTime(t time.Time) { c.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (c *CodePagerDutyJob) IsNil() bool { return c == nil }
@@ -0,0 +1,197 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// fakePagerDuty records received Events API requests and answers like the
+// real endpoint, echoing the dedup key back.
+type fakePagerDuty struct {
+	mu     sync.Mutex
+	events []pagerDutyEvent
+	server *httptest.Server
+}
+
+func newFakePagerDuty(t *testing.T) *fakePagerDuty {
+	t.Helper()
+	f := &fakePagerDuty{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev pagerDutyEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		f.events = append(f.events, ev)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(pagerDutyResponse{Status: "success", DedupKey: ev.DedupKey})
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakePagerDuty) recorded() []pagerDutyEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]pagerDutyEvent(nil), f.events...)
+}
+
+// newPagerDutyJob builds a job for the given color against the fake server.
+func newPagerDutyJob(url, color string) *CodePagerDutyJob {
+	tpl := codeAlertTemplateFor(color)
+	return &CodePagerDutyJob{
+		Monitor:    "payments-api",
+		Color:      color,
+		RoutingKey: "test-routing-key",
+		URL:        url,
+		Status:     tpl.Status,
+		Severity:   tpl.Severity,
+		Summary:    tpl.Summary,
+		Action:     tpl.Action,
+		Timeout:    2 * time.Second,
+	}
+}
+
+// TestCodePagerDutyJob_TriggerResolveSequence tests that a red alert
+// triggers an incident and a later green alert resolves it under the same
+// dedup key.
+func TestCodePagerDutyJob_TriggerResolveSequence(t *testing.T) {
+	t.Parallel()
+	fake := newFakePagerDuty(t)
+
+	red := newPagerDutyJob(fake.server.URL, "red").Execute(context.Background())
+	if red.Err != nil {
+		t.Fatalf("red Execute failed: %v", red.Err)
+	}
+	green := newPagerDutyJob(fake.server.URL, "green").Execute(context.Background())
+	if green.Err != nil {
+		t.Fatalf("green Execute failed: %v", green.Err)
+	}
+
+	events := fake.recorded()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0].EventAction != "trigger" {
+		t.Errorf("red event action = %q, want trigger", events[0].EventAction)
+	}
+	if events[1].EventAction != "resolve" {
+		t.Errorf("green event action = %q, want resolve", events[1].EventAction)
+	}
+	if events[0].DedupKey == "" || events[0].DedupKey != events[1].DedupKey {
+		t.Errorf("dedup keys differ: trigger=%q resolve=%q", events[0].DedupKey, events[1].DedupKey)
+	}
+	if events[0].Payload.Severity != "critical" {
+		t.Errorf("red severity = %q, want critical", events[0].Payload.Severity)
+	}
+	if events[0].Payload.Source != "payments-api" {
+		t.Errorf("event source = %q, want monitor name", events[0].Payload.Source)
+	}
+	if red.Payload.Extra("dedup_key") != events[0].DedupKey {
+		t.Errorf("result dedup_key = %v, want %q", red.Payload.Extra("dedup_key"), events[0].DedupKey)
+	}
+}
+
+// TestCodePagerDutyJob_RetriesOn429 tests that rate-limited attempts retry
+// until accepted.
+func TestCodePagerDutyJob_RetriesOn429(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	result := newPagerDutyJob(server.URL, "red").Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed after retries: %v", result.Err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("endpoint called %d times, want 3", got)
+	}
+}
+
+// TestCodePagerDutyJob_BadRequestIsFinal tests that a 400 fails without
+// retry and carries the status in the payload.
+func TestCodePagerDutyJob_BadRequestIsFinal(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	result := newPagerDutyJob(server.URL, "red").Execute(context.Background())
+	if !errors.Is(result.Err, ErrPagerDutyEventFailed) {
+		t.Fatalf("Execute error = %v, want ErrPagerDutyEventFailed", result.Err)
+	}
+	if result.Payload.Extra("http_status") != 400 {
+		t.Errorf("payload http_status = %v, want 400", result.Payload.Extra("http_status"))
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("endpoint called %d times, want 1 (no retry on 4xx)", got)
+	}
+}
+
+// TestCodePagerDutyJob_MissingRoutingKey tests the guard for a missing key.
+func TestCodePagerDutyJob_MissingRoutingKey(t *testing.T) {
+	t.Parallel()
+	job := newPagerDutyJob("http://127.0.0.1:1", "red")
+	job.RoutingKey = ""
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrPagerDutyRoutingKeyMissing) {
+		t.Errorf("Execute error = %v, want ErrPagerDutyRoutingKeyMissing", result.Err)
+	}
+}
+
+// TestCreateCodeJob_PagerDutyThreadsConfig tests that the factory copies the
+// routing key (including from the environment) and endpoint into the job.
+func TestCreateCodeJob_PagerDutyThreadsConfig(t *testing.T) {
+	config := schema.CodeConfig{
+		Notify: "pagerduty",
+		Config: &schema.CodeNotificationPagerDuty{URL: "http://example.test/enqueue", RoutingKey: "rk-direct"},
+	}
+	job, err := CreateCodeJob("web-app", config, ecs.Entity{}, "red")
+	if err != nil {
+		t.Fatalf("CreateCodeJob failed: %v", err)
+	}
+	pdJob, ok := job.(*CodePagerDutyJob)
+	if !ok {
+		t.Fatalf("expected *CodePagerDutyJob, got %T", job)
+	}
+	if pdJob.RoutingKey != "rk-direct" || pdJob.URL != "http://example.test/enqueue" {
+		t.Errorf("config not threaded: routing_key=%q url=%q", pdJob.RoutingKey, pdJob.URL)
+	}
+	ReleaseCodeJob(job)
+
+	t.Setenv("CPRA_TEST_PD_ROUTING_KEY", "rk-from-env")
+	config.Config = &schema.CodeNotificationPagerDuty{RoutingKeyEnv: "CPRA_TEST_PD_ROUTING_KEY"}
+	job, err = CreateCodeJob("web-app", config, ecs.Entity{}, "red")
+	if err != nil {
+		t.Fatalf("CreateCodeJob failed: %v", err)
+	}
+	pdJob = job.(*CodePagerDutyJob)
+	if pdJob.RoutingKey != "rk-from-env" {
+		t.Errorf("RoutingKey = %q, want value from environment", pdJob.RoutingKey)
+	}
+	ReleaseCodeJob(job)
+}
@@ -0,0 +1,238 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cpra/internal/interning"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// slackDefaultTimeout bounds the webhook POST when no timeout is configured.
+const slackDefaultTimeout = 10 * time.Second
+
+// slackMaxAttempts is the total number of delivery attempts; 429 and 5xx
+// responses are retried, other failures are final.
+const slackMaxAttempts = 3
+
+// slackRetryAfterCap bounds how long a Retry-After header can delay a retry,
+// so a misbehaving endpoint cannot park a code worker.
+const slackRetryAfterCap = 5 * time.Second
+
+// slackColorFor maps code colors to Slack attachment hex colors.
+func slackColorFor(color string) string {
+	switch color {
+	case "red":
+		return "#d32f2f"
+	case "orange":
+		return "#ef6c00"
+	case "yellow":
+		return "#f9a825"
+	case "green":
+		return "#2e7d32"
+	case "cyan":
+		return "#00acc1"
+	case "blue":
+		return "#1565c0"
+	case "purple":
+		return "#6a1b9a"
+	case "gray":
+		return "#9e9e9e"
+	default:
+		return "#757575"
+	}
+}
+
+// slackText, slackBlock, slackAttachment and slackMessage model the subset
+// of Slack's Block Kit used for code alerts.
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+// CodeSlackJob sends alert notifications to a Slack incoming webhook using
+// a Block Kit payload built from the code alert template. Delivery retries
+// on 429 and 5xx responses, honoring Retry-After.
+type CodeSlackJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Monitor     string
+	Message     string
+	Color       string
+	WebHook     string
+	Status      string
+	Severity    string
+	Summary     string
+	Action      string
+	NextSteps   string
+	Labels      interning.Labels
+	Timeout     time.Duration
+	Entity      ecs.Entity
+}
+
+// buildMessage renders the Block Kit payload for this alert.
+func (c *CodeSlackJob) buildMessage() slackMessage {
+	title := codeAlertTemplateFor(c.Color).Title
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: title}},
+		{Type: "section", Fields: []slackText{
+			{Type: "mrkdwn", Text: "*Monitor:*\n" + c.Monitor},
+			{Type: "mrkdwn", Text: "*Status:*\n" + c.Status},
+			{Type: "mrkdwn", Text: "*Severity:*\n" + c.Severity},
+		}},
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: c.Summary}},
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "*Recommended Action:* " + c.Action}},
+	}
+	if c.NextSteps != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: "*Next Steps:* " + c.NextSteps},
+		})
+	}
+	return slackMessage{
+		Text:        title + ": " + c.Monitor + " is " + c.Status,
+		Attachments: []slackAttachment{{Color: slackColorFor(c.Color), Blocks: blocks}},
+	}
+}
+
+// Execute posts the alert to the Slack webhook.
+func (c *CodeSlackJob) Execute(ctx context.Context) Result {
+	payload := GetCodeSlackPayload()
+	payload.Color = c.Color
+
+	if c.WebHook == "" {
+		return Result{Ent: c.Entity, Err: ErrSlackWebhookMissing, Payload: payload}
+	}
+	body, err := json.Marshal(c.buildMessage())
+	if err != nil {
+		return Result{Ent: c.Entity, Err: err, Payload: payload}
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = slackDefaultTimeout
+	}
+	client, err := GetPooledHTTPClient(timeout, false, "")
+	if err != nil {
+		return Result{Ent: c.Entity, Err: err, Payload: payload}
+	}
+
+	// Acquire global dial slot to prevent CPU spikes during network outages.
+	if !AcquireHTTPDialSlot(ctx) {
+		return Result{Ent: c.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer ReleaseHTTPDialSlot()
+
+	var lastStatus int
+	var lastErr error
+	delay := 50 * time.Millisecond
+	for attempt := 0; attempt < slackMaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return Result{Ent: c.Entity, Err: ctx.Err(), Payload: payload}
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		status, err := c.post(ctx, client, body)
+		if err != nil {
+			if err == context.Canceled || err == context.DeadlineExceeded {
+				return Result{Ent: c.Entity, Err: err, Payload: payload}
+			}
+			lastErr = err
+			continue
+		}
+		lastStatus = status
+		if status >= 200 && status < 300 {
+			payload.SetExtra("http_status", status)
+			return Result{Ent: c.Entity, Err: nil, Payload: payload}
+		}
+		lastErr = ErrSlackNotifyFailed
+		// Only rate limiting and server errors are worth retrying.
+		if status != http.StatusTooManyRequests && status < 500 {
+			break
+		}
+	}
+
+	if lastStatus > 0 {
+		payload.SetExtra("http_status", lastStatus)
+	}
+	return Result{Ent: c.Entity, Err: lastErr, Payload: payload, Class: InfraErrorClass(lastErr)}
+}
+
+// post performs one webhook attempt and returns the HTTP status. A positive
+// Retry-After on a retryable response stretches the next backoff delay.
+func (c *CodeSlackJob) post(ctx context.Context, client *http.Client, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.WebHook, bytes.NewReader(body))
+	if err != nil {
+		return 0, ErrFailedToCreateHTTPRequest
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	if ra := resp.Header.Get("Retry-After"); ra != "" && retryable {
+		if secs, err := strconv.Atoi(ra); err == nil && secs > 0 {
+			wait := time.Duration(secs) * time.Second
+			if wait > slackRetryAfterCap {
+				wait = slackRetryAfterCap
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (c *CodeSlackJob) Copy() Job { job := *c; return &job }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (c *CodeSlackJob) GetEnqueueTime() time.Time { return c.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (c *CodeSlackJob) SetEnqueueTime(t time.Time) { c.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (c *CodeSlackJob) GetStartTime() time.Time { return c.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (c *CodeSlackJob) SetStartTime(t time.Time) { c.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (c *CodeSlackJob) IsNil() bool { return c == nil }
@@ -0,0 +1,193 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// newSlackJob builds a red-alert Slack job pointed at the given webhook URL.
+func newSlackJob(webhook string) *CodeSlackJob {
+	tpl := codeAlertTemplateFor("red")
+	return &CodeSlackJob{
+		Monitor:   "payments-api",
+		Color:     "red",
+		WebHook:   webhook,
+		Status:    tpl.Status,
+		Severity:  tpl.Severity,
+		Summary:   tpl.Summary,
+		Action:    tpl.Action,
+		NextSteps: tpl.NextSteps,
+		Timeout:   2 * time.Second,
+	}
+}
+
+// TestCodeSlackJob_Execute_PayloadShape tests the Block Kit payload posted
+// to the webhook.
+func TestCodeSlackJob_Execute_PayloadShape(t *testing.T) {
+	t.Parallel()
+	var body []byte
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("webhook called with method %s, want POST", r.Method)
+		}
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := newSlackJob(server.URL)
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	if result.Payload.Driver != "slack" || result.Payload.Extra("http_status") != 200 {
+		t.Errorf("unexpected result payload: %v", result.Payload)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	var msg slackMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatalf("webhook body is not valid JSON: %v", err)
+	}
+	if !strings.Contains(msg.Text, "payments-api") || !strings.Contains(msg.Text, "CRITICAL ALERT") {
+		t.Errorf("fallback text = %q, want monitor name and title", msg.Text)
+	}
+	if len(msg.Attachments) != 1 {
+		t.Fatalf("got %d attachments, want 1", len(msg.Attachments))
+	}
+	att := msg.Attachments[0]
+	if att.Color != slackColorFor("red") {
+		t.Errorf("attachment color = %q, want %q", att.Color, slackColorFor("red"))
+	}
+	if len(att.Blocks) == 0 || att.Blocks[0].Type != "header" || att.Blocks[0].Text.Text != "CRITICAL ALERT" {
+		t.Errorf("first block should be the CRITICAL ALERT header, got %+v", att.Blocks)
+	}
+	raw := string(body)
+	for _, want := range []string{"*Monitor:*", "*Status:*", "*Severity:*", "*Recommended Action:*", "*Next Steps:*"} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("payload missing %q section", want)
+		}
+	}
+}
+
+// TestCodeSlackJob_Execute_RetriesOn429 tests that rate-limited attempts are
+// retried (respecting Retry-After) until the webhook accepts the alert.
+func TestCodeSlackJob_Execute_RetriesOn429(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := newSlackJob(server.URL).Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed after retries: %v", result.Err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("webhook called %d times, want 3", got)
+	}
+}
+
+// TestCodeSlackJob_Execute_ServerErrorExhaustsRetries tests that persistent
+// 5xx responses surface as an error with the status in the payload.
+func TestCodeSlackJob_Execute_ServerErrorExhaustsRetries(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	result := newSlackJob(server.URL).Execute(context.Background())
+	if !errors.Is(result.Err, ErrSlackNotifyFailed) {
+		t.Fatalf("Execute error = %v, want ErrSlackNotifyFailed", result.Err)
+	}
+	if result.Payload.Extra("http_status") != 500 {
+		t.Errorf("payload http_status = %v, want 500", result.Payload.Extra("http_status"))
+	}
+	if got := calls.Load(); got != slackMaxAttempts {
+		t.Errorf("webhook called %d times, want %d", got, slackMaxAttempts)
+	}
+}
+
+// TestCodeSlackJob_Execute_ClientErrorIsFinal tests that non-retryable 4xx
+// responses fail without retry.
+func TestCodeSlackJob_Execute_ClientErrorIsFinal(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	result := newSlackJob(server.URL).Execute(context.Background())
+	if !errors.Is(result.Err, ErrSlackNotifyFailed) {
+		t.Fatalf("Execute error = %v, want ErrSlackNotifyFailed", result.Err)
+	}
+	if result.Payload.Extra("http_status") != 400 {
+		t.Errorf("payload http_status = %v, want 400", result.Payload.Extra("http_status"))
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("webhook called %d times, want 1 (no retry on 4xx)", got)
+	}
+}
+
+// TestCodeSlackJob_Execute_MissingWebhook tests the guard for an empty URL.
+func TestCodeSlackJob_Execute_MissingWebhook(t *testing.T) {
+	t.Parallel()
+	job := newSlackJob("")
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrSlackWebhookMissing) {
+		t.Errorf("Execute error = %v, want ErrSlackWebhookMissing", result.Err)
+	}
+}
+
+// TestCreateCodeJob_SlackThreadsWebhook tests that the factory copies the
+// webhook URL and template fields into the job.
+func TestCreateCodeJob_SlackThreadsWebhook(t *testing.T) {
+	t.Parallel()
+	config := schema.CodeConfig{
+		Notify: "slack",
+		Config: &schema.CodeNotificationSlack{WebHook: "https://hooks.slack.com/services/T0/B0/x"},
+	}
+	job, err := CreateCodeJob("web-app", config, ecs.Entity{}, "yellow")
+	if err != nil {
+		t.Fatalf("CreateCodeJob failed: %v", err)
+	}
+	slackJob, ok := job.(*CodeSlackJob)
+	if !ok {
+		t.Fatalf("expected *CodeSlackJob, got %T", job)
+	}
+	if slackJob.WebHook != "https://hooks.slack.com/services/T0/B0/x" {
+		t.Errorf("WebHook = %q, webhook URL was dropped", slackJob.WebHook)
+	}
+	tpl := codeAlertTemplateFor("yellow")
+	if slackJob.Status != tpl.Status || slackJob.Severity != tpl.Severity {
+		t.Errorf("template not threaded: status=%q severity=%q", slackJob.Status, slackJob.Severity)
+	}
+	ReleaseCodeJob(job)
+}
@@ -0,0 +1,209 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"cpra/internal/interning"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// webhookDefaultTimeout bounds the notification request when no timeout is
+// configured.
+const webhookDefaultTimeout = 10 * time.Second
+
+// webhookMaxAttempts is the total number of delivery attempts; 429 and 5xx
+// responses are retried, other failures are final.
+const webhookMaxAttempts = 3
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body,
+// computed with the configured secret, prefixed "sha256=".
+const WebhookSignatureHeader = "X-CPRA-Signature"
+
+// webhookAlertData is the data rendered into the payload template and the
+// default JSON body.
+type webhookAlertData struct {
+	Monitor   string           `json:"monitor"`
+	Color     string           `json:"color"`
+	Status    string           `json:"status"`
+	Severity  string           `json:"severity"`
+	Summary   string           `json:"summary"`
+	Action    string           `json:"action"`
+	NextSteps string           `json:"next_steps,omitempty"`
+	Labels    interning.Labels `json:"labels,omitempty"`
+}
+
+// CodeWebhookJob sends alert notifications to a generic webhook endpoint.
+// The body is either the rendered payload template or a default JSON
+// document, optionally signed with HMAC-SHA256. 429 and 5xx responses are
+// retried with backoff within the job timeout.
+type CodeWebhookJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Monitor     string
+	Message     string
+	Color       string
+	URL         string
+	Method      string
+	Headers     map[string]string
+	Secret      string
+	// Template is parsed at job creation so bad templates fail the load,
+	// not the first alert. Nil sends the default JSON payload.
+	Template  *template.Template
+	Status    string
+	Severity  string
+	Summary   string
+	Action    string
+	NextSteps string
+	Labels    interning.Labels
+	Timeout   time.Duration
+	Entity    ecs.Entity
+}
+
+// buildBody renders the request body from the template or the default JSON.
+func (c *CodeWebhookJob) buildBody() ([]byte, error) {
+	data := webhookAlertData{
+		Monitor:   c.Monitor,
+		Color:     c.Color,
+		Status:    c.Status,
+		Severity:  c.Severity,
+		Summary:   c.Summary,
+		Action:    c.Action,
+		NextSteps: c.NextSteps,
+		Labels:    c.Labels,
+	}
+	if c.Template != nil {
+		var buf bytes.Buffer
+		if err := c.Template.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return json.Marshal(data)
+}
+
+// Execute sends the alert to the webhook endpoint.
+func (c *CodeWebhookJob) Execute(ctx context.Context) Result {
+	payload := GetCodeWebhookPayload()
+	payload.Color = c.Color
+
+	if c.URL == "" {
+		return Result{Ent: c.Entity, Err: ErrWebhookURLMissing, Payload: payload}
+	}
+	body, err := c.buildBody()
+	if err != nil {
+		return Result{Ent: c.Entity, Err: err, Payload: payload}
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = webhookDefaultTimeout
+	}
+	client, err := GetPooledHTTPClient(timeout, false, "")
+	if err != nil {
+		return Result{Ent: c.Entity, Err: err, Payload: payload}
+	}
+
+	// Acquire global dial slot to prevent CPU spikes during network outages.
+	if !AcquireHTTPDialSlot(ctx) {
+		return Result{Ent: c.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer ReleaseHTTPDialSlot()
+
+	var lastStatus int
+	var lastErr error
+	delay := 50 * time.Millisecond
+	for attempt := 0; attempt < webhookMaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return Result{Ent: c.Entity, Err: ctx.Err(), Payload: payload}
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		status, err := c.send(ctx, client, body)
+		if err != nil {
+			if err == context.Canceled || err == context.DeadlineExceeded {
+				return Result{Ent: c.Entity, Err: err, Payload: payload}
+			}
+			lastErr = err
+			continue
+		}
+		lastStatus = status
+		if status >= 200 && status < 300 {
+			payload.SetExtra("http_status", status)
+			return Result{Ent: c.Entity, Err: nil, Payload: payload}
+		}
+		lastErr = ErrWebhookNotifyFailed
+		// Only rate limiting and server errors are worth retrying.
+		if status != http.StatusTooManyRequests && status < 500 {
+			break
+		}
+	}
+
+	if lastStatus > 0 {
+		payload.SetExtra("http_status", lastStatus)
+	}
+	return Result{Ent: c.Entity, Err: lastErr, Payload: payload, Class: InfraErrorClass(lastErr)}
+}
+
+// send performs one delivery attempt and returns the HTTP status.
+func (c *CodeWebhookJob) send(ctx context.Context, client *http.Client, body []byte) (int, error) {
+	method := c.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, ErrFailedToCreateHTTPRequest
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+	if c.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.Secret))
+		mac.Write(body)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (c *CodeWebhookJob) Copy() Job { job := *c; return &job }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (c *CodeWebhookJob) GetEnqueueTime() time.Time { return c.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (c *CodeWebhookJob) SetEnqueueTime(t time.Time) { c.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (c *CodeWebhookJob) GetStartTime() time.Time { return c.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (c *CodeWebhookJob) SetStartTime(t time.Time) { c.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (c *CodeWebhookJob) IsNil() bool { return c == nil }
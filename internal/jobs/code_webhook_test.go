@@ -0,0 +1,195 @@
+package jobs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// newWebhookJob builds a red-alert webhook job against the given URL.
+func newWebhookJob(url string) *CodeWebhookJob {
+	tpl := codeAlertTemplateFor("red")
+	return &CodeWebhookJob{
+		Monitor:   "payments-api",
+		Color:     "red",
+		URL:       url,
+		Status:    tpl.Status,
+		Severity:  tpl.Severity,
+		Summary:   tpl.Summary,
+		Action:    tpl.Action,
+		NextSteps: tpl.NextSteps,
+		Timeout:   2 * time.Second,
+	}
+}
+
+// TestCodeWebhookJob_DefaultPayloadAndSignature tests the default JSON body
+// and the HMAC-SHA256 signature header.
+func TestCodeWebhookJob_DefaultPayloadAndSignature(t *testing.T) {
+	t.Parallel()
+	const secret = "s3cret"
+	var body []byte
+	var signature, method, customHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		signature = r.Header.Get(WebhookSignatureHeader)
+		customHeader = r.Header.Get("X-Team")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := newWebhookJob(server.URL)
+	job.Secret = secret
+	job.Headers = map[string]string{"X-Team": "sre"}
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	if method != http.MethodPost {
+		t.Errorf("method = %s, want POST default", method)
+	}
+	if customHeader != "sre" {
+		t.Errorf("custom header not sent, got %q", customHeader)
+	}
+
+	var data webhookAlertData
+	if err := json.Unmarshal(body, &data); err != nil {
+		t.Fatalf("default payload is not valid JSON: %v", err)
+	}
+	if data.Monitor != "payments-api" || data.Color != "red" || data.Severity != "critical" {
+		t.Errorf("default payload = %+v, want monitor/color/severity filled", data)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if signature != want {
+		t.Errorf("signature = %q, want %q", signature, want)
+	}
+}
+
+// TestCodeWebhookJob_TemplateRendering tests that a payload template
+// replaces the default body.
+func TestCodeWebhookJob_TemplateRendering(t *testing.T) {
+	t.Parallel()
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := newWebhookJob(server.URL)
+	job.Template = texttemplate.Must(texttemplate.New("webhook").Parse(
+		`{"alert":"{{.Monitor}} is {{.Status}}","level":"{{.Severity}}"}`))
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	want := `{"alert":"payments-api is FAILED","level":"critical"}`
+	if string(body) != want {
+		t.Errorf("rendered body = %s, want %s", body, want)
+	}
+}
+
+// TestCodeWebhookJob_NonSuccessStatus tests retry on 5xx and final failure
+// on 4xx.
+func TestCodeWebhookJob_NonSuccessStatus(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	result := newWebhookJob(server.URL).Execute(context.Background())
+	if !errors.Is(result.Err, ErrWebhookNotifyFailed) {
+		t.Fatalf("Execute error = %v, want ErrWebhookNotifyFailed", result.Err)
+	}
+	if result.Payload.Extra("http_status") != 502 {
+		t.Errorf("payload http_status = %v, want 502", result.Payload.Extra("http_status"))
+	}
+	if got := calls.Load(); got != webhookMaxAttempts {
+		t.Errorf("endpoint called %d times, want %d", got, webhookMaxAttempts)
+	}
+
+	var notFoundCalls atomic.Int32
+	notFound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		notFoundCalls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer notFound.Close()
+
+	result = newWebhookJob(notFound.URL).Execute(context.Background())
+	if !errors.Is(result.Err, ErrWebhookNotifyFailed) {
+		t.Fatalf("Execute error = %v, want ErrWebhookNotifyFailed", result.Err)
+	}
+	if got := notFoundCalls.Load(); got != 1 {
+		t.Errorf("endpoint called %d times, want 1 (no retry on 4xx)", got)
+	}
+}
+
+// TestCreateCodeJob_WebhookTemplateParseError tests that a broken template
+// fails job creation with the monitor name in the error.
+func TestCreateCodeJob_WebhookTemplateParseError(t *testing.T) {
+	t.Parallel()
+	config := schema.CodeConfig{
+		Notify: "webhook",
+		Config: &schema.CodeNotificationWebhook{
+			URL:             "http://example.test/hook",
+			PayloadTemplate: `{{.Monitor`,
+		},
+	}
+	_, err := CreateCodeJob("broken-tmpl", config, ecs.Entity{}, "red")
+	if err == nil {
+		t.Fatal("expected template parse error, got nil")
+	}
+	if !strings.Contains(err.Error(), "broken-tmpl") {
+		t.Errorf("error %q should name the monitor", err)
+	}
+}
+
+// TestCreateCodeJob_WebhookThreadsConfig tests the factory wiring for the
+// webhook config.
+func TestCreateCodeJob_WebhookThreadsConfig(t *testing.T) {
+	t.Parallel()
+	config := schema.CodeConfig{
+		Notify: "webhook",
+		Config: &schema.CodeNotificationWebhook{
+			URL:             "http://example.test/hook",
+			Method:          "PUT",
+			Secret:          "s",
+			PayloadTemplate: `{{.Monitor}}`,
+		},
+	}
+	job, err := CreateCodeJob("web-app", config, ecs.Entity{}, "yellow")
+	if err != nil {
+		t.Fatalf("CreateCodeJob failed: %v", err)
+	}
+	whJob, ok := job.(*CodeWebhookJob)
+	if !ok {
+		t.Fatalf("expected *CodeWebhookJob, got %T", job)
+	}
+	if whJob.URL != "http://example.test/hook" || whJob.Method != "PUT" || whJob.Secret != "s" {
+		t.Errorf("config not threaded: %+v", whJob)
+	}
+	if whJob.Template == nil {
+		t.Error("payload template not parsed into the job")
+	}
+	ReleaseCodeJob(job)
+}
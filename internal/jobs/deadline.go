@@ -0,0 +1,93 @@
+package jobs
+
+import "time"
+
+// DeadlineJob is implemented by jobs that can report a total execution
+// budget, letting the worker pool wrap Execute in a hard deadline so a
+// misbehaving check cannot hold a worker far past its nominal worst case.
+// Jobs without the method run under the pool context alone.
+type DeadlineJob interface {
+	ExecutionBudget() time.Duration
+}
+
+// executionBudget converts a per-attempt timeout and retry count into a
+// total budget: up to timeout per attempt, plus up to one more timeout of
+// backoff sleeping (RetryWithPolicy caps total sleep at the budget). A
+// zero timeout disables the deadline rather than guessing one.
+func executionBudget(timeout time.Duration, retries int) time.Duration {
+	if timeout <= 0 {
+		return 0
+	}
+	if retries < 0 {
+		retries = 0
+	}
+	return timeout*time.Duration(retries+1) + timeout
+}
+
+// Pulse checks retry with per-attempt timeouts; the budget covers every
+// attempt plus backoff sleeps.
+
+func (p *PulseHTTPJob) ExecutionBudget() time.Duration { return executionBudget(p.Timeout, p.Retries) }
+func (p *PulseTCPJob) ExecutionBudget() time.Duration  { return executionBudget(p.Timeout, p.Retries) }
+func (p *PulseICMPJob) ExecutionBudget() time.Duration { return executionBudget(p.Timeout, p.Retries) }
+func (p *PulseDNSJob) ExecutionBudget() time.Duration  { return executionBudget(p.Timeout, p.Retries) }
+func (p *PulseGRPCJob) ExecutionBudget() time.Duration { return executionBudget(p.Timeout, p.Retries) }
+func (p *PulseNTPJob) ExecutionBudget() time.Duration  { return executionBudget(p.Timeout, p.Retries) }
+
+// Docker interventions. These methods live here, outside the nodocker build
+// tag, so both the real and stub variants of the types pick them up.
+
+func (i *InterventionDockerJob) ExecutionBudget() time.Duration {
+	return executionBudget(i.Timeout, i.Retries)
+}
+
+func (j *InterventionDockerStopJob) ExecutionBudget() time.Duration {
+	return executionBudget(j.Timeout, j.Retries)
+}
+
+func (j *InterventionDockerStartJob) ExecutionBudget() time.Duration {
+	return executionBudget(j.Timeout, j.Retries)
+}
+
+// Kill, pause, and unpause have no configured timeout (they are immediate
+// daemon calls), so they run under the pool context alone.
+
+func (j *InterventionDockerScaleJob) ExecutionBudget() time.Duration {
+	return executionBudget(j.Timeout, j.Retries)
+}
+
+func (j *InterventionExecJob) ExecutionBudget() time.Duration {
+	return executionBudget(j.Timeout, j.Retries)
+}
+
+func (j *InterventionWebhookJob) ExecutionBudget() time.Duration {
+	return executionBudget(j.Timeout, j.Retries)
+}
+
+// Code notifications run a single attempt against a default-capped timeout;
+// the budget mirrors each job's own fallback.
+
+func (c *CodeWebhookJob) ExecutionBudget() time.Duration {
+	return executionBudget(effectiveTimeout(c.Timeout, webhookDefaultTimeout), 0)
+}
+
+func (c *CodeSlackJob) ExecutionBudget() time.Duration {
+	return executionBudget(effectiveTimeout(c.Timeout, slackDefaultTimeout), 0)
+}
+
+func (c *CodeEmailJob) ExecutionBudget() time.Duration {
+	return executionBudget(effectiveTimeout(c.Timeout, emailDefaultTimeout), 0)
+}
+
+func (c *CodePagerDutyJob) ExecutionBudget() time.Duration {
+	return executionBudget(effectiveTimeout(c.Timeout, pagerDutyDefaultTimeout), 0)
+}
+
+// effectiveTimeout applies a job's default when no timeout is configured,
+// matching the fallback inside its Execute.
+func effectiveTimeout(timeout, fallback time.Duration) time.Duration {
+	if timeout <= 0 {
+		return fallback
+	}
+	return timeout
+}
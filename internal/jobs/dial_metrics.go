@@ -0,0 +1,350 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http/httptrace"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// DialErrorClass classifies dial failures by root cause, so local resource
+// exhaustion (ephemeral ports, file descriptors) can be told apart from
+// genuine target-side failures.
+type DialErrorClass uint8
+
+// Dial error classes, ordered roughly by severity for the local host.
+const (
+	DialErrOther DialErrorClass = iota
+	DialErrAddrExhaustion
+	DialErrFDExhaustion
+	DialErrRefused
+	DialErrTimeout
+
+	dialErrClassCount
+)
+
+// String returns the class name for logs and metric labels.
+func (c DialErrorClass) String() string {
+	switch c {
+	case DialErrAddrExhaustion:
+		return "addr_exhaustion"
+	case DialErrFDExhaustion:
+		return "fd_exhaustion"
+	case DialErrRefused:
+		return "refused"
+	case DialErrTimeout:
+		return "timeout"
+	default:
+		return "other"
+	}
+}
+
+// IsExhaustion reports whether the class indicates local resource exhaustion
+// on the CPRA host rather than a problem with the target.
+func (c DialErrorClass) IsExhaustion() bool {
+	return c == DialErrAddrExhaustion || c == DialErrFDExhaustion
+}
+
+// ClassifyDialError maps a dial error to its class by unwrapping to the errno.
+func ClassifyDialError(err error) DialErrorClass {
+	if err == nil {
+		return DialErrOther
+	}
+	switch {
+	case errors.Is(err, syscall.EADDRNOTAVAIL):
+		return DialErrAddrExhaustion
+	case errors.Is(err, syscall.EMFILE), errors.Is(err, syscall.ENFILE):
+		return DialErrFDExhaustion
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return DialErrRefused
+	case errors.Is(err, context.DeadlineExceeded):
+		return DialErrTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return DialErrTimeout
+	}
+	return DialErrOther
+}
+
+// DialWatchdogConfig configures the socket exhaustion watchdog.
+type DialWatchdogConfig struct {
+	// Window is the rolling window over which exhaustion errors are counted.
+	// Default: 10 seconds.
+	Window time.Duration
+
+	// Threshold is the number of exhaustion-class dial errors within Window
+	// that triggers the watchdog. Default: 20.
+	Threshold int
+}
+
+// DefaultDialWatchdogConfig returns sensible defaults.
+func DefaultDialWatchdogConfig() DialWatchdogConfig {
+	return DialWatchdogConfig{
+		Window:    10 * time.Second,
+		Threshold: 20,
+	}
+}
+
+// DialWatchdogEvent describes a watchdog state change.
+type DialWatchdogEvent struct {
+	// Active is true when exhaustion was detected, false on recovery.
+	Active bool
+	// Class is the error class that tripped the watchdog (when Active).
+	Class DialErrorClass
+	// ErrorsInWindow is the number of exhaustion errors in the window.
+	ErrorsInWindow int
+}
+
+// fdSampleInterval limits how often /proc and rlimit are consulted when
+// stats are requested.
+const fdSampleInterval = 10 * time.Second
+
+// DialMetrics collects socket-level observability for the shared HTTP/TCP
+// dialing layer: connection reuse counters, an open connection estimate,
+// dial errors by errno class, and file descriptor usage vs rlimit.
+//
+// It also runs the exhaustion watchdog: when exhaustion-class dial errors
+// (EADDRNOTAVAIL, EMFILE/ENFILE) exceed a threshold rate, results produced
+// from those errors can be marked ErrorClassInternalInfrastructure so they
+// are not counted as monitor failures.
+type DialMetrics struct {
+	newConns    atomic.Int64
+	reusedConns atomic.Int64
+	closedConns atomic.Int64
+	errorCounts [dialErrClassCount]atomic.Int64
+
+	fdCount     atomic.Int64
+	fdLimit     atomic.Int64
+	fdSampledAt atomic.Int64 // Unix nanos of the last FD sample
+
+	watchdogMu       sync.Mutex
+	exhaustionTimes  []time.Time
+	watchdogConfig   DialWatchdogConfig
+	exhaustionActive atomic.Bool
+	onWatchdog       func(DialWatchdogEvent)
+}
+
+var (
+	globalDialMetrics     *DialMetrics
+	globalDialMetricsOnce sync.Once
+)
+
+// GetDialMetrics returns the global dial metrics collector, creating it if needed.
+func GetDialMetrics() *DialMetrics {
+	globalDialMetricsOnce.Do(func() {
+		globalDialMetrics = NewDialMetrics(DefaultDialWatchdogConfig())
+	})
+	return globalDialMetrics
+}
+
+// NewDialMetrics creates a new collector with the given watchdog configuration.
+func NewDialMetrics(cfg DialWatchdogConfig) *DialMetrics {
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Second
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 20
+	}
+	return &DialMetrics{watchdogConfig: cfg}
+}
+
+// SetWatchdogCallback registers a callback invoked on watchdog state changes.
+// Must be set before dials start (typically at startup).
+func (m *DialMetrics) SetWatchdogCallback(fn func(DialWatchdogEvent)) {
+	m.watchdogMu.Lock()
+	m.onWatchdog = fn
+	m.watchdogMu.Unlock()
+}
+
+// ObserveDial records the outcome of a dial attempt. On success the returned
+// connection is wrapped so the open-connection estimate decrements on Close.
+func (m *DialMetrics) ObserveDial(conn net.Conn, err error) net.Conn {
+	if err != nil {
+		m.recordDialError(err)
+		return conn
+	}
+	m.newConns.Add(1)
+	m.recordDialSuccess()
+	return &countedConn{Conn: conn, metrics: m}
+}
+
+// ClientTrace returns an httptrace.ClientTrace that records connection reuse
+// for net/http based requests (GotConn reports whether the connection came
+// from the idle pool).
+func (m *DialMetrics) ClientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				m.reusedConns.Add(1)
+			}
+		},
+	}
+}
+
+// RecordReusedConn counts a request served over an existing connection, for
+// dialing layers without httptrace support (fasthttp).
+func (m *DialMetrics) RecordReusedConn() {
+	m.reusedConns.Add(1)
+}
+
+// ExhaustionActive reports whether the watchdog currently considers the host
+// to be in socket/fd exhaustion.
+func (m *DialMetrics) ExhaustionActive() bool {
+	return m.exhaustionActive.Load()
+}
+
+// recordDialError classifies and counts a dial error, and feeds the watchdog.
+func (m *DialMetrics) recordDialError(err error) {
+	class := ClassifyDialError(err)
+	m.errorCounts[class].Add(1)
+	if !class.IsExhaustion() {
+		return
+	}
+
+	now := time.Now()
+	m.watchdogMu.Lock()
+	m.exhaustionTimes = append(m.exhaustionTimes, now)
+	m.pruneWindowLocked(now)
+	count := len(m.exhaustionTimes)
+	trip := count >= m.watchdogConfig.Threshold && !m.exhaustionActive.Load()
+	if trip {
+		m.exhaustionActive.Store(true)
+	}
+	fn := m.onWatchdog
+	m.watchdogMu.Unlock()
+
+	if trip && fn != nil {
+		fn(DialWatchdogEvent{Active: true, Class: class, ErrorsInWindow: count})
+	}
+}
+
+// recordDialSuccess clears the watchdog once the error window has drained.
+func (m *DialMetrics) recordDialSuccess() {
+	if !m.exhaustionActive.Load() {
+		return
+	}
+	now := time.Now()
+	m.watchdogMu.Lock()
+	m.pruneWindowLocked(now)
+	recovered := len(m.exhaustionTimes) == 0 && m.exhaustionActive.Load()
+	if recovered {
+		m.exhaustionActive.Store(false)
+	}
+	fn := m.onWatchdog
+	m.watchdogMu.Unlock()
+
+	if recovered && fn != nil {
+		fn(DialWatchdogEvent{Active: false})
+	}
+}
+
+// pruneWindowLocked drops exhaustion samples outside the window.
+// Must be called with watchdogMu held.
+func (m *DialMetrics) pruneWindowLocked(now time.Time) {
+	cutoff := now.Add(-m.watchdogConfig.Window)
+	idx := 0
+	for idx < len(m.exhaustionTimes) && !m.exhaustionTimes[idx].After(cutoff) {
+		idx++
+	}
+	if idx > 0 {
+		m.exhaustionTimes = m.exhaustionTimes[idx:]
+	}
+}
+
+// sampleFDUsage refreshes the fd count/limit gauges if the last sample is stale.
+func (m *DialMetrics) sampleFDUsage() {
+	now := time.Now().UnixNano()
+	last := m.fdSampledAt.Load()
+	if now-last < int64(fdSampleInterval) || !m.fdSampledAt.CompareAndSwap(last, now) {
+		return
+	}
+
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err == nil {
+		m.fdLimit.Store(int64(limit.Cur))
+	}
+	if entries, err := os.ReadDir("/proc/self/fd"); err == nil {
+		m.fdCount.Store(int64(len(entries)))
+	}
+}
+
+// Stats returns a snapshot of the dial metrics for the diagnostics dump.
+func (m *DialMetrics) Stats() DialMetricsStats {
+	m.sampleFDUsage()
+	stats := DialMetricsStats{
+		NewConns:         m.newConns.Load(),
+		ReusedConns:      m.reusedConns.Load(),
+		OpenConns:        m.newConns.Load() - m.closedConns.Load(),
+		FDCount:          m.fdCount.Load(),
+		FDLimit:          m.fdLimit.Load(),
+		ExhaustionActive: m.exhaustionActive.Load(),
+		ErrorsByClass:    make(map[string]int64, int(dialErrClassCount)),
+	}
+	for c := DialErrorClass(0); c < dialErrClassCount; c++ {
+		if n := m.errorCounts[c].Load(); n > 0 {
+			stats.ErrorsByClass[c.String()] = n
+		}
+	}
+	return stats
+}
+
+// DialMetricsStats is a point-in-time snapshot of dial-layer metrics.
+type DialMetricsStats struct {
+	NewConns         int64
+	ReusedConns      int64
+	OpenConns        int64
+	FDCount          int64
+	FDLimit          int64
+	ExhaustionActive bool
+	ErrorsByClass    map[string]int64
+}
+
+// countedConn decrements the open connection estimate when closed.
+type countedConn struct {
+	net.Conn
+	metrics *DialMetrics
+	closed  atomic.Bool
+}
+
+func (c *countedConn) Close() error {
+	if c.closed.CompareAndSwap(false, true) {
+		c.metrics.closedConns.Add(1)
+	}
+	return c.Conn.Close()
+}
+
+// infraExemptionEnabled controls whether exhaustion-classed failures are
+// exempted from monitor failure counting. Enabled by default.
+var infraExemptionDisabled atomic.Bool
+
+// SetInfraFailureExemption toggles whether dial failures caused by local
+// resource exhaustion are marked ErrorClassInternalInfrastructure so result
+// systems skip failure counting for them.
+func SetInfraFailureExemption(enabled bool) {
+	infraExemptionDisabled.Store(!enabled)
+}
+
+// InfraErrorClass classifies a job error for result reporting. It returns
+// ErrorClassInternalInfrastructure only when the exhaustion watchdog is
+// active, the error is an exhaustion-class dial failure, and the exemption
+// has not been disabled.
+func (m *DialMetrics) InfraErrorClass(err error) ErrorClass {
+	if err == nil || infraExemptionDisabled.Load() || !m.ExhaustionActive() {
+		return ErrorClassNone
+	}
+	if ClassifyDialError(err).IsExhaustion() {
+		return ErrorClassInternalInfrastructure
+	}
+	return ErrorClassNone
+}
+
+// InfraErrorClass classifies an error against the global dial metrics.
+func InfraErrorClass(err error) ErrorClass {
+	return GetDialMetrics().InfraErrorClass(err)
+}
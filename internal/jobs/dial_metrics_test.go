@@ -0,0 +1,151 @@
+package jobs
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeDialErr wraps an errno the way the net package does, so classification
+// exercises the same unwrap chain as real dial failures.
+func fakeDialErr(errno syscall.Errno) error {
+	return &net.OpError{
+		Op:  "dial",
+		Net: "tcp",
+		Err: os.NewSyscallError("connect", errno),
+	}
+}
+
+// fakeTimeoutErr implements net.Error with Timeout() == true.
+type fakeTimeoutErr struct{}
+
+func (fakeTimeoutErr) Error() string   { return "dial timeout" }
+func (fakeTimeoutErr) Timeout() bool   { return true }
+func (fakeTimeoutErr) Temporary() bool { return true }
+
+// fakeConn is a no-op net.Conn for counter tests.
+type fakeConn struct{ net.Conn }
+
+func (fakeConn) Close() error { return nil }
+
+func TestClassifyDialError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want DialErrorClass
+	}{
+		{"eaddrnotavail", fakeDialErr(syscall.EADDRNOTAVAIL), DialErrAddrExhaustion},
+		{"emfile", fakeDialErr(syscall.EMFILE), DialErrFDExhaustion},
+		{"enfile", fakeDialErr(syscall.ENFILE), DialErrFDExhaustion},
+		{"econnrefused", fakeDialErr(syscall.ECONNREFUSED), DialErrRefused},
+		{"timeout", fakeTimeoutErr{}, DialErrTimeout},
+		{"other", fakeDialErr(syscall.ECONNRESET), DialErrOther},
+		{"nil", nil, DialErrOther},
+	}
+	for _, tc := range cases {
+		if got := ClassifyDialError(tc.err); got != tc.want {
+			t.Errorf("%s: ClassifyDialError = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	if !DialErrAddrExhaustion.IsExhaustion() || !DialErrFDExhaustion.IsExhaustion() {
+		t.Error("exhaustion classes must report IsExhaustion")
+	}
+	if DialErrRefused.IsExhaustion() || DialErrTimeout.IsExhaustion() {
+		t.Error("refused/timeout must not report IsExhaustion")
+	}
+}
+
+func TestDialMetrics_ObserveDialCounters(t *testing.T) {
+	t.Parallel()
+
+	m := NewDialMetrics(DefaultDialWatchdogConfig())
+
+	conn := m.ObserveDial(fakeConn{}, nil)
+	m.RecordReusedConn()
+	_ = m.ObserveDial(nil, fakeDialErr(syscall.ECONNREFUSED))
+
+	stats := m.Stats()
+	if stats.NewConns != 1 || stats.ReusedConns != 1 {
+		t.Errorf("conns = new:%d reused:%d, want 1/1", stats.NewConns, stats.ReusedConns)
+	}
+	if stats.OpenConns != 1 {
+		t.Errorf("open estimate = %d, want 1", stats.OpenConns)
+	}
+	if stats.ErrorsByClass["refused"] != 1 {
+		t.Errorf("refused errors = %d, want 1", stats.ErrorsByClass["refused"])
+	}
+
+	_ = conn.Close()
+	_ = conn.Close() // double close must not double-decrement
+	if got := m.Stats().OpenConns; got != 0 {
+		t.Errorf("open estimate after close = %d, want 0", got)
+	}
+
+	if stats.FDLimit <= 0 || stats.FDCount <= 0 {
+		t.Errorf("fd sample = %d/%d, want positive values", stats.FDCount, stats.FDLimit)
+	}
+}
+
+func TestDialMetrics_WatchdogTripAndRecover(t *testing.T) {
+	t.Parallel()
+
+	m := NewDialMetrics(DialWatchdogConfig{Window: 50 * time.Millisecond, Threshold: 3})
+
+	var events []DialWatchdogEvent
+	m.SetWatchdogCallback(func(ev DialWatchdogEvent) { events = append(events, ev) })
+
+	for i := 0; i < 3; i++ {
+		_ = m.ObserveDial(nil, fakeDialErr(syscall.EADDRNOTAVAIL))
+	}
+	if !m.ExhaustionActive() {
+		t.Fatal("watchdog should be active after threshold errors")
+	}
+	if len(events) != 1 || !events[0].Active || events[0].Class != DialErrAddrExhaustion {
+		t.Fatalf("unexpected watchdog events: %+v", events)
+	}
+
+	// Refused errors do not feed the watchdog window.
+	_ = m.ObserveDial(nil, fakeDialErr(syscall.ECONNREFUSED))
+
+	// After the window drains, a successful dial clears the watchdog.
+	time.Sleep(60 * time.Millisecond)
+	_ = m.ObserveDial(fakeConn{}, nil)
+	if m.ExhaustionActive() {
+		t.Error("watchdog should clear after the error window drains")
+	}
+	if len(events) != 2 || events[1].Active {
+		t.Fatalf("expected recovery event, got %+v", events)
+	}
+}
+
+func TestDialMetrics_InfraErrorClass(t *testing.T) {
+	m := NewDialMetrics(DialWatchdogConfig{Window: time.Minute, Threshold: 2})
+	err := fakeDialErr(syscall.EADDRNOTAVAIL)
+
+	// Below threshold: failures count against the target.
+	_ = m.ObserveDial(nil, err)
+	if got := m.InfraErrorClass(err); got != ErrorClassNone {
+		t.Errorf("below threshold: class = %v, want ErrorClassNone", got)
+	}
+
+	// Watchdog active: exhaustion errors are exempted, others are not.
+	_ = m.ObserveDial(nil, err)
+	if got := m.InfraErrorClass(err); got != ErrorClassInternalInfrastructure {
+		t.Errorf("active: class = %v, want ErrorClassInternalInfrastructure", got)
+	}
+	if got := m.InfraErrorClass(fakeDialErr(syscall.ECONNREFUSED)); got != ErrorClassNone {
+		t.Errorf("refused: class = %v, want ErrorClassNone", got)
+	}
+
+	// The exemption is configurable.
+	SetInfraFailureExemption(false)
+	defer SetInfraFailureExemption(true)
+	if got := m.InfraErrorClass(err); got != ErrorClassNone {
+		t.Errorf("exemption disabled: class = %v, want ErrorClassNone", got)
+	}
+}
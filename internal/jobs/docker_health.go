@@ -0,0 +1,167 @@
+//go:build !nodocker
+
+// Docker daemon health tracking for the shared client pool.
+//
+// When a Docker daemon is down, every intervention job targeting it would
+// otherwise burn its full timeout and retries, backing up the intervention
+// queue with thousands of identical failures. This file tracks daemon health
+// per endpoint with a cheap periodic ping. While an endpoint is unhealthy,
+// intervention jobs fail fast with ErrDockerDaemonUnavailable, a single
+// warning is logged per state transition, and an optional hook reports the
+// transition for metrics/watchdog integration. When the daemon recovers,
+// interventions resume normally.
+
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/moby/moby/client"
+)
+
+// Ping cadence for daemon health probes. Variables (not constants) so tests
+// can shorten them to simulate downtime and recovery quickly.
+var (
+	dockerPingInterval = 10 * time.Second
+	dockerPingTimeout  = 2 * time.Second
+)
+
+// DockerDaemonStateHook is invoked on every daemon health transition.
+// healthy=false means the endpoint became unavailable; healthy=true means it
+// recovered. Hooks must be fast and must not block (called from the probe
+// goroutine).
+type DockerDaemonStateHook func(host string, healthy bool)
+
+var dockerDaemonStateHook atomic.Value // DockerDaemonStateHook
+
+// SetDockerDaemonStateHook registers a hook for daemon health transitions.
+// Typically wired to a metric counter or the watchdog at startup.
+func SetDockerDaemonStateHook(hook DockerDaemonStateHook) {
+	dockerDaemonStateHook.Store(hook)
+}
+
+// dockerDaemonMonitor tracks the health of a single Docker endpoint.
+type dockerDaemonMonitor struct {
+	host      string
+	client    *client.Client
+	unhealthy atomic.Bool
+	stop      chan struct{}
+}
+
+// dockerDaemonMonitors stores one monitor per endpoint, keyed by host
+// (empty string = default environment client).
+var dockerDaemonMonitors sync.Map // map[string]*dockerDaemonMonitor
+
+// ensureDockerDaemonMonitor starts a health monitor for the given endpoint
+// if one is not already running. Called from GetDockerClient after a client
+// is successfully created.
+func ensureDockerDaemonMonitor(host string, cli *client.Client) *dockerDaemonMonitor {
+	if m, ok := dockerDaemonMonitors.Load(host); ok {
+		return m.(*dockerDaemonMonitor)
+	}
+	m := &dockerDaemonMonitor{host: host, client: cli, stop: make(chan struct{})}
+	if actual, loaded := dockerDaemonMonitors.LoadOrStore(host, m); loaded {
+		return actual.(*dockerDaemonMonitor)
+	}
+	go m.run()
+	return m
+}
+
+// run probes the daemon immediately, then on every tick until stopped.
+// The immediate probe catches daemons that are already down at startup so
+// the first intervention wave does not burn timeouts.
+func (m *dockerDaemonMonitor) run() {
+	m.probe()
+	ticker := time.NewTicker(dockerPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.probe()
+		}
+	}
+}
+
+// probe pings the daemon and updates health state, logging and notifying the
+// hook exactly once per transition.
+func (m *dockerDaemonMonitor) probe() {
+	ctx, cancel := context.WithTimeout(context.Background(), dockerPingTimeout)
+	_, err := m.client.Ping(ctx)
+	cancel()
+
+	if err != nil {
+		if m.unhealthy.CompareAndSwap(false, true) {
+			log.Printf("docker daemon unavailable at %s: %v (failing interventions fast until it recovers)",
+				dockerHostLabel(m.host), err)
+			notifyDockerDaemonState(m.host, false)
+		}
+		return
+	}
+	if m.unhealthy.CompareAndSwap(true, false) {
+		log.Printf("docker daemon recovered at %s; interventions resuming", dockerHostLabel(m.host))
+		notifyDockerDaemonState(m.host, true)
+	}
+}
+
+// stopMonitor halts the probe goroutine and removes the monitor from the
+// pool. Used by tests and by CloseDockerClients at shutdown.
+func (m *dockerDaemonMonitor) stopMonitor() {
+	select {
+	case <-m.stop:
+		// already stopped
+	default:
+		close(m.stop)
+	}
+	dockerDaemonMonitors.Delete(m.host)
+}
+
+// notifyDockerDaemonState invokes the registered hook, if any.
+func notifyDockerDaemonState(host string, healthy bool) {
+	if hook, ok := dockerDaemonStateHook.Load().(DockerDaemonStateHook); ok && hook != nil {
+		hook(host, healthy)
+	}
+}
+
+// dockerHostLabel returns a human-readable label for an endpoint.
+// The empty host means the default environment client.
+func dockerHostLabel(host string) string {
+	if host == "" {
+		return "default endpoint"
+	}
+	return host
+}
+
+// CheckDockerDaemon returns ErrDockerDaemonUnavailable if the daemon behind
+// the given host is currently known to be down, or nil otherwise. Intervention
+// jobs call this after obtaining a client so they fail fast instead of burning
+// their full timeout and retries against a dead socket.
+func CheckDockerDaemon(host string) error {
+	if m, ok := dockerDaemonMonitors.Load(host); ok && m.(*dockerDaemonMonitor).unhealthy.Load() {
+		return ErrDockerDaemonUnavailable
+	}
+	return nil
+}
+
+// UnhealthyDockerEndpoints returns the endpoints currently tracked as
+// unavailable. The default environment client is reported as "default".
+// Used by the watchdog to surface daemon outages.
+func UnhealthyDockerEndpoints() []string {
+	var hosts []string
+	dockerDaemonMonitors.Range(func(key, value interface{}) bool {
+		if value.(*dockerDaemonMonitor).unhealthy.Load() {
+			host := key.(string)
+			if host == "" {
+				host = "default"
+			}
+			hosts = append(hosts, host)
+		}
+		return true
+	})
+	return hosts
+}
@@ -0,0 +1,226 @@
+//go:build !nodocker
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeDockerDaemon serves the minimal Docker API surface needed by the
+// health probes and container restart calls.
+type fakeDockerDaemon struct {
+	handler  http.Handler
+	server   *http.Server
+	addr     string
+	restarts atomic.Int64
+}
+
+func newFakeDockerDaemon(t *testing.T) *fakeDockerDaemon {
+	t.Helper()
+	d := &fakeDockerDaemon{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			w.Header().Set("Api-Version", "1.43")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/restart"):
+			d.restarts.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	d.handler = mux
+	d.start(t, "127.0.0.1:0")
+	return d
+}
+
+// start begins listening on the given address. Reused for recovery: passing
+// the previously recorded address brings the daemon back on the same port.
+func (d *fakeDockerDaemon) start(t *testing.T, addr string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to listen on %s: %v", addr, err)
+	}
+	d.addr = ln.Addr().String()
+	d.server = &http.Server{Handler: d.handler}
+	go func() { _ = d.server.Serve(ln) }()
+}
+
+// stop closes the listener and all active connections so clients observe
+// the daemon as down immediately (plain listener close would leave pooled
+// keep-alive connections working).
+func (d *fakeDockerDaemon) stop() {
+	_ = d.server.Close()
+}
+
+// syncBuffer is a goroutine-safe log capture target.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor polls cond until it returns true or the timeout elapses.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", msg)
+}
+
+// TestDockerDaemonHealth_FailFastAndRecovery simulates daemon downtime and
+// asserts fast-fail behavior, a single log line per state transition, and
+// interventions resuming after recovery. Not parallel: it adjusts the global
+// ping cadence and captures the global log output.
+func TestDockerDaemonHealth_FailFastAndRecovery(t *testing.T) {
+	oldInterval, oldTimeout := dockerPingInterval, dockerPingTimeout
+	dockerPingInterval, dockerPingTimeout = 20*time.Millisecond, 200*time.Millisecond
+	defer func() { dockerPingInterval, dockerPingTimeout = oldInterval, oldTimeout }()
+
+	var logs syncBuffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	transitions := make(chan bool, 16)
+	SetDockerDaemonStateHook(func(host string, healthy bool) {
+		transitions <- healthy
+	})
+	defer SetDockerDaemonStateHook(nil)
+
+	daemon := newFakeDockerDaemon(t)
+	defer daemon.stop()
+	host := "tcp://" + daemon.addr
+
+	if _, err := GetDockerClient(host); err != nil {
+		t.Fatalf("GetDockerClient failed: %v", err)
+	}
+	defer func() {
+		if m, ok := dockerDaemonMonitors.Load(host); ok {
+			m.(*dockerDaemonMonitor).stopMonitor()
+		}
+	}()
+
+	job := &InterventionDockerJob{
+		Container:  "test-container",
+		DockerHost: host,
+		Timeout:    5 * time.Second,
+		Retries:    2,
+	}
+
+	// Daemon up: intervention succeeds.
+	if res := job.Execute(context.Background()); res.Err != nil {
+		t.Fatalf("expected restart to succeed with daemon up, got %v", res.Err)
+	}
+	if daemon.restarts.Load() != 1 {
+		t.Fatalf("expected 1 restart call, got %d", daemon.restarts.Load())
+	}
+
+	// Take the daemon down and wait for the probe to notice.
+	daemon.stop()
+	select {
+	case healthy := <-transitions:
+		if healthy {
+			t.Fatal("expected unhealthy transition, got healthy")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for unhealthy transition")
+	}
+
+	// Jobs fail fast with the distinct error class, not after full timeout.
+	start := time.Now()
+	res := job.Execute(context.Background())
+	if res.Err != ErrDockerDaemonUnavailable {
+		t.Fatalf("expected ErrDockerDaemonUnavailable, got %v", res.Err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected fast failure, took %v", elapsed)
+	}
+
+	// Several more probe cycles must not add more warning lines.
+	time.Sleep(5 * dockerPingInterval)
+	if n := strings.Count(logs.String(), "docker daemon unavailable"); n != 1 {
+		t.Fatalf("expected exactly 1 unavailable log line, got %d:\n%s", n, logs.String())
+	}
+
+	// Bring the daemon back on the same address and wait for recovery.
+	daemon.start(t, daemon.addr)
+	select {
+	case healthy := <-transitions:
+		if !healthy {
+			t.Fatal("expected healthy transition, got unhealthy")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for recovery transition")
+	}
+
+	// Interventions resume normally.
+	waitFor(t, 2*time.Second, func() bool {
+		return job.Execute(context.Background()).Err == nil
+	}, "intervention to succeed after recovery")
+
+	if n := strings.Count(logs.String(), "docker daemon recovered"); n != 1 {
+		t.Fatalf("expected exactly 1 recovery log line, got %d:\n%s", n, logs.String())
+	}
+}
+
+// TestCheckDockerDaemon_UntrackedHost verifies hosts without a monitor are
+// treated as available.
+func TestCheckDockerDaemon_UntrackedHost(t *testing.T) {
+	t.Parallel()
+	if err := CheckDockerDaemon("tcp://never-seen:2375"); err != nil {
+		t.Fatalf("expected nil for untracked host, got %v", err)
+	}
+}
+
+// TestUnhealthyDockerEndpoints_ReportsDownHosts verifies the watchdog-facing
+// endpoint listing.
+func TestUnhealthyDockerEndpoints_ReportsDownHosts(t *testing.T) {
+	t.Parallel()
+	m := &dockerDaemonMonitor{host: "tcp://down-host:2375", stop: make(chan struct{})}
+	m.unhealthy.Store(true)
+	dockerDaemonMonitors.Store(m.host, m)
+	defer dockerDaemonMonitors.Delete(m.host)
+
+	found := false
+	for _, host := range UnhealthyDockerEndpoints() {
+		if host == m.host {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %s in unhealthy endpoints, got %v", m.host, UnhealthyDockerEndpoints())
+	}
+	if err := CheckDockerDaemon(m.host); err != ErrDockerDaemonUnavailable {
+		t.Fatalf("expected ErrDockerDaemonUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import "github.com/mlange-42/ark/ecs"
+
+// GetEntity implementations for every job type, satisfying the Job
+// interface's entity accessor. They live together in one file rather than
+// next to each type because the docker jobs are defined twice (real and
+// nodocker stub) and a shared untagged file keeps the accessors from being
+// duplicated per build variant.
+
+func (p *PulseHTTPJob) GetEntity() ecs.Entity { return p.Entity }
+func (p *PulseTCPJob) GetEntity() ecs.Entity  { return p.Entity }
+func (p *PulseUDPJob) GetEntity() ecs.Entity  { return p.Entity }
+func (p *PulseICMPJob) GetEntity() ecs.Entity { return p.Entity }
+func (p *PulseDNSJob) GetEntity() ecs.Entity  { return p.Entity }
+func (p *PulseNTPJob) GetEntity() ecs.Entity  { return p.Entity }
+func (p *PulseGRPCJob) GetEntity() ecs.Entity { return p.Entity }
+
+func (p *PulseDockerJob) GetEntity() ecs.Entity               { return p.Entity }
+func (i *InterventionDockerJob) GetEntity() ecs.Entity        { return i.Entity }
+func (j *InterventionDockerStopJob) GetEntity() ecs.Entity    { return j.Entity }
+func (j *InterventionDockerStartJob) GetEntity() ecs.Entity   { return j.Entity }
+func (j *InterventionDockerKillJob) GetEntity() ecs.Entity    { return j.Entity }
+func (j *InterventionDockerPauseJob) GetEntity() ecs.Entity   { return j.Entity }
+func (j *InterventionDockerUnpauseJob) GetEntity() ecs.Entity { return j.Entity }
+func (j *InterventionDockerScaleJob) GetEntity() ecs.Entity   { return j.Entity }
+
+func (j *InterventionExecJob) GetEntity() ecs.Entity    { return j.Entity }
+func (j *InterventionWebhookJob) GetEntity() ecs.Entity { return j.Entity }
+func (j *DryRunJob) GetEntity() ecs.Entity              { return j.Entity }
+
+func (c *CodeLogJob) GetEntity() ecs.Entity       { return c.Entity }
+func (c *CodeSlackJob) GetEntity() ecs.Entity     { return c.Entity }
+func (c *CodePagerDutyJob) GetEntity() ecs.Entity { return c.Entity }
+func (c *CodeEmailJob) GetEntity() ecs.Entity     { return c.Entity }
+func (c *CodeWebhookJob) GetEntity() ecs.Entity   { return c.Entity }
+func (j *CodeFanoutJob) GetEntity() ecs.Entity    { return j.Entity }
+
+// The wrappers delegate to the job they carry.
+
+func (j *AnnotatedJob) GetEntity() ecs.Entity {
+	if j.Job == nil {
+		return ecs.Entity{}
+	}
+	return j.Job.GetEntity()
+}
+
+func (j *InterventionStepJob) GetEntity() ecs.Entity {
+	if j.Job == nil {
+		return ecs.Entity{}
+	}
+	return j.Job.GetEntity()
+}
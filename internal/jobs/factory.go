@@ -0,0 +1,569 @@
+package jobs
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/mlange-42/ark/ecs"
+
+	"cpra/internal/interning"
+	"cpra/internal/loader/schema"
+)
+
+// CreatePulseJob creates a new pulse job based on the provided schema.
+// It returns the appropriate job type (HTTP, TCP, or ICMP) based on the config.
+//
+// Jobs are obtained from sync.Pool for memory efficiency and must be
+// returned after use via their respective put functions.
+func CreatePulseJob(pulseSchema schema.Pulse, jobID ecs.Entity) (Job, error) {
+	timeout := pulseSchema.Timeout
+	switch cfg := pulseSchema.Config.(type) {
+	case *schema.PulseHTTPConfig:
+		// Extract host for fasthttp client pool
+		host, isTLS, err := ExtractHostFromURL(cfg.Url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		headers, err := ParseHTTPHeaders(cfg.Headers)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Auth != nil {
+			header, err := authHeader(cfg.Auth)
+			if err != nil {
+				return nil, err
+			}
+			headers = append(headers, header)
+		}
+		expectedStatus, err := schema.ParseStatusRanges(cfg.ExpectedStatus)
+		if err != nil {
+			return nil, err
+		}
+		var bodyRegex *regexp.Regexp
+		if cfg.BodyRegex != "" {
+			if bodyRegex, err = regexp.Compile(cfg.BodyRegex); err != nil {
+				return nil, fmt.Errorf("invalid body_regex %q: %w", cfg.BodyRegex, err)
+			}
+		}
+		job := getPulseHTTPJob()
+		job.Entity = jobID
+		job.URL = interning.Intern(cfg.Url)
+		job.Method = interning.Intern(cfg.Method)
+		job.Timeout = timeout
+		job.Retries = cfg.Retries
+		job.Host = interning.Intern(host)
+		job.IsTLS = isTLS
+		job.Proxy = resolveHTTPProxy(cfg.Proxy, host)
+		job.Headers = headers
+		job.ExpectedStatus = expectedStatus
+		job.BodyContains = cfg.BodyContains
+		job.BodyRegex = bodyRegex
+		job.DetailedTiming = pulseSchema.DetailedTiming
+		job.DegradedThreshold = pulseSchema.DegradedThreshold
+		job.DegradedPhase = pulseSchema.DegradedPhase
+		job.Backoff = backoffFromSchema(pulseSchema.RetryBackoff)
+		job.Group = concurrencyGroup(pulseSchema)
+		job.JobType = InternedPulse
+		job.Driver = InternedHTTP
+		return job, nil
+
+	case *schema.PulseTCPConfig:
+		job := getPulseTCPJob()
+		job.Entity = jobID
+		job.Host = interning.Intern(cfg.Host)
+		job.Port = cfg.Port
+		job.Network = networkForFamily("tcp", cfg.IPFamily)
+		job.Timeout = timeout
+		job.Retries = cfg.Retries
+		job.DetailedTiming = pulseSchema.DetailedTiming
+		job.DegradedThreshold = pulseSchema.DegradedThreshold
+		job.DegradedPhase = pulseSchema.DegradedPhase
+		job.Backoff = backoffFromSchema(pulseSchema.RetryBackoff)
+		job.Group = concurrencyGroup(pulseSchema)
+		job.JobType = InternedPulse
+		job.Driver = InternedTCP
+		return job, nil
+
+	case *schema.PulseUDPConfig:
+		probe, err := schema.ParseUDPBytes(cfg.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+		expect, err := schema.ParseUDPBytes(cfg.Expect)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect: %w", err)
+		}
+		job := getPulseUDPJob()
+		job.Entity = jobID
+		job.Host = interning.Intern(cfg.Host)
+		job.Port = cfg.Port
+		job.Probe = probe
+		job.Expect = expect
+		job.ExpectPrefix = strings.HasPrefix(cfg.Expect, "hex:")
+		job.ReadTimeout = cfg.ReadTimeout
+		job.RequireResponse = cfg.RequireResponse
+		job.Timeout = timeout
+		job.Retries = cfg.Retries
+		job.Backoff = backoffFromSchema(pulseSchema.RetryBackoff)
+		job.Group = concurrencyGroup(pulseSchema)
+		job.JobType = InternedPulse
+		job.Driver = InternedUDP
+		return job, nil
+
+	case *schema.PulseNTPConfig:
+		job := getPulseNTPJob()
+		job.Entity = jobID
+		job.Servers = internStrings(cfg.Servers)
+		job.MaxOffset = cfg.MaxOffset
+		job.Samples = cfg.Samples
+		job.Timeout = timeout
+		job.Retries = cfg.Retries
+		job.Group = concurrencyGroup(pulseSchema)
+		job.JobType = InternedPulse
+		job.Driver = InternedNTP
+		return job, nil
+
+	case *schema.PulseDNSConfig:
+		job := getPulseDNSJob()
+		job.Entity = jobID
+		job.Host = interning.Intern(cfg.Host)
+		job.RecordType = interning.Intern(strings.ToUpper(cfg.RecordType))
+		job.Expected = append([]string(nil), cfg.Expected...)
+		job.Resolver = interning.Intern(cfg.Resolver)
+		job.Timeout = timeout
+		job.Retries = cfg.Retries
+		job.Group = concurrencyGroup(pulseSchema)
+		job.JobType = InternedPulse
+		job.Driver = InternedDNS
+		return job, nil
+
+	case *schema.PulseGRPCConfig:
+		job := getPulseGRPCJob()
+		job.Entity = jobID
+		job.Target = interning.Intern(cfg.Target)
+		job.Service = cfg.Service
+		job.TLS = cfg.TLS
+		job.Timeout = timeout
+		job.Retries = cfg.Retries
+		job.Group = concurrencyGroup(pulseSchema)
+		job.JobType = InternedPulse
+		job.Driver = InternedGRPC
+		return job, nil
+
+	case *schema.PulseDockerConfig:
+		job := getPulseDockerJob()
+		job.Entity = jobID
+		job.Container = interning.Intern(cfg.Container)
+		job.DockerHost = interning.Intern(cfg.DockerHost)
+		job.RequireHealthy = strings.EqualFold(cfg.RequireStatus, "healthy")
+		job.Timeout = timeout
+		job.Retries = cfg.Retries
+		job.Group = concurrencyGroup(pulseSchema)
+		job.JobType = InternedPulse
+		job.Driver = InternedDocker
+		return job, nil
+
+	case *schema.PulseHeartbeatConfig:
+		// Heartbeat monitors are push-based: the target reports in, so there
+		// is no job to execute. The HeartbeatSystem evaluates them directly.
+		return nil, nil
+
+	case *schema.PulseICMPConfig:
+		job := getPulseICMPJob()
+		job.Entity = jobID
+		job.Host = interning.Intern(cfg.Host)
+		job.Network = networkForFamily("ip", cfg.IPFamily)
+		job.Timeout = timeout
+		job.Count = cfg.Count
+		job.Retries = cfg.Retries
+		job.IgnorePrivilege = cfg.Privilege
+		job.Backoff = backoffFromSchema(pulseSchema.RetryBackoff)
+		job.Group = concurrencyGroup(pulseSchema)
+		job.JobType = InternedPulse
+		job.Driver = InternedICMP
+		return job, nil
+
+	default:
+		return nil, ErrUnknownPulseConfig
+	}
+}
+
+// concurrencyGroup picks the pulse job's concurrency group: the first of
+// the monitor's pulse groups, or the default group when none are set.
+// networkForFamily maps a validated ip_family setting onto a dial/resolve
+// network for the given base ("tcp" or "ip"). "auto" and empty keep the
+// base, letting the OS pick the family.
+func networkForFamily(base, family string) string {
+	switch strings.ToLower(family) {
+	case "ipv4":
+		return base + "4"
+	case "ipv6":
+		return base + "6"
+	default:
+		return base
+	}
+}
+
+func concurrencyGroup(pulseSchema schema.Pulse) string {
+	if len(pulseSchema.Groups) > 0 && pulseSchema.Groups[0] != "" {
+		return interning.Intern(pulseSchema.Groups[0])
+	}
+	return DefaultConcurrencyGroup
+}
+
+// internStrings returns a fresh slice of interned copies of ss, so jobs
+// sharing a server list (e.g. a fleet pointed at the same NTP pool) share
+// one instance of each string without aliasing the schema's slice.
+func internStrings(ss []string) []string {
+	if len(ss) == 0 {
+		return nil
+	}
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = interning.Intern(s)
+	}
+	return out
+}
+
+// backoffFromSchema translates an optional retry_backoff block into a
+// BackoffPolicy. A nil block yields the zero policy, which preserves the
+// historical 50ms-base exponential behavior.
+func backoffFromSchema(rb *schema.RetryBackoff) BackoffPolicy {
+	if rb == nil {
+		return BackoffPolicy{}
+	}
+	// The strategy string is validated at load time; fall back to the
+	// exponential default on anything unexpected.
+	strategy, _ := ParseBackoffStrategy(rb.Strategy)
+	return BackoffPolicy{
+		Strategy: strategy,
+		Base:     rb.Base,
+		Max:      rb.Max,
+		Jitter:   rb.Jitter,
+	}
+}
+
+// CreateInterventionJob creates a new intervention job based on the provided schema.
+// Supports Docker interventions: restart, stop, start, kill, pause, unpause, scale.
+//
+// When the schema's DryRun flag is set the real job is built (so bad targets
+// still fail the load) but wrapped in a DryRunJob that reports the would-be
+// action instead of performing it.
+//
+// Jobs are obtained from sync.Pool for memory efficiency.
+func CreateInterventionJob(interventionSchema schema.Intervention, jobID ecs.Entity) (Job, error) {
+	job, err := buildInterventionJob(interventionSchema, jobID)
+	if err != nil || !interventionSchema.DryRun {
+		return job, err
+	}
+	return &DryRunJob{
+		Job:    job,
+		Action: interventionSchema.Action,
+		Target: describeInterventionTarget(interventionSchema),
+		Entity: jobID,
+	}, nil
+}
+
+// describeInterventionTarget renders the would-be target of a dry-run
+// intervention for logs and result payloads.
+func describeInterventionTarget(interventionSchema schema.Intervention) string {
+	switch t := interventionSchema.Target.(type) {
+	case *schema.InterventionTargetDocker:
+		action := t.Type
+		if action == "" {
+			action = "restart"
+		}
+		if action == "scale" {
+			return fmt.Sprintf("docker scale %s=%d", t.Service, t.Replicas)
+		}
+		return fmt.Sprintf("docker %s %s", action, t.Container)
+	case *schema.InterventionTargetExec:
+		return strings.Join(append([]string{t.Command}, t.Args...), " ")
+	case *schema.InterventionTargetWebhook:
+		method := t.Method
+		if method == "" {
+			method = "POST"
+		}
+		return method + " " + t.URL
+	default:
+		return interventionSchema.Action
+	}
+}
+
+func buildInterventionJob(interventionSchema schema.Intervention, jobID ecs.Entity) (Job, error) {
+	retries := interventionSchema.Retries
+	switch interventionSchema.Action {
+	case "docker":
+		target, ok := interventionSchema.Target.(*schema.InterventionTargetDocker)
+		if !ok || target == nil {
+			return nil, ErrDockerMissingTarget
+		}
+
+		// Route by target.Type (default to "restart" for backwards compatibility)
+		switch target.Type {
+		case "restart", "":
+			job := getInterventionDockerJob()
+			job.Entity = jobID
+			job.Container = interning.Intern(target.Container)
+			job.DockerHost = interning.Intern(target.DockerHost)
+			job.Retries = retries
+			job.Timeout = target.Timeout
+			job.JobType = InternedIntervention
+			job.Driver = InternedDocker
+			return job, nil
+
+		case "stop":
+			job := getInterventionDockerStopJob()
+			job.Entity = jobID
+			job.Container = interning.Intern(target.Container)
+			job.DockerHost = interning.Intern(target.DockerHost)
+			job.Retries = retries
+			job.Timeout = target.Timeout
+			return job, nil
+
+		case "start":
+			job := getInterventionDockerStartJob()
+			job.Entity = jobID
+			job.Container = interning.Intern(target.Container)
+			job.DockerHost = interning.Intern(target.DockerHost)
+			job.Retries = retries
+			job.Timeout = target.Timeout
+			return job, nil
+
+		case "kill":
+			job := getInterventionDockerKillJob()
+			job.Entity = jobID
+			job.Container = interning.Intern(target.Container)
+			job.DockerHost = interning.Intern(target.DockerHost)
+			job.Signal = target.Signal
+			job.Retries = retries
+			return job, nil
+
+		case "pause":
+			job := getInterventionDockerPauseJob()
+			job.Entity = jobID
+			job.Container = interning.Intern(target.Container)
+			job.DockerHost = interning.Intern(target.DockerHost)
+			job.Retries = retries
+			return job, nil
+
+		case "unpause":
+			job := getInterventionDockerUnpauseJob()
+			job.Entity = jobID
+			job.Container = interning.Intern(target.Container)
+			job.DockerHost = interning.Intern(target.DockerHost)
+			job.Retries = retries
+			return job, nil
+
+		case "scale":
+			job := getInterventionDockerScaleJob()
+			job.Entity = jobID
+			job.Service = target.Service
+			job.DockerHost = interning.Intern(target.DockerHost)
+			job.Replicas = target.Replicas
+			job.Retries = retries
+			job.Timeout = target.Timeout
+			return job, nil
+
+		default:
+			return nil, ErrUnknownDockerAction
+		}
+
+	case "exec":
+		target, ok := interventionSchema.Target.(*schema.InterventionTargetExec)
+		if !ok || target == nil {
+			return nil, ErrExecMissingTarget
+		}
+		job := getInterventionExecJob()
+		job.Entity = jobID
+		job.Command = target.Command
+		job.Args = target.Args
+		job.WorkingDir = target.WorkingDir
+		job.Env = target.Env
+		job.ExpectedExitCodes = target.ExpectedExitCodes
+		job.Timeout = target.Timeout
+		job.Retries = retries
+		return job, nil
+
+	case "webhook":
+		target, ok := interventionSchema.Target.(*schema.InterventionTargetWebhook)
+		if !ok || target == nil {
+			return nil, ErrWebhookMissingTarget
+		}
+		job := getInterventionWebhookJob()
+		job.Entity = jobID
+		job.URL = interning.Intern(target.URL)
+		job.Method = target.Method
+		job.Headers = target.Headers
+		job.ExpectedStatus = target.ExpectedStatus
+		job.Timeout = target.Timeout
+		job.Retries = retries
+		if target.SecretEnv != "" {
+			job.Secret = os.Getenv(target.SecretEnv)
+		}
+		if target.BodyTemplate != "" {
+			// Parse at creation so bad templates fail the load, not the
+			// first intervention.
+			tmpl, err := texttemplate.New("intervention").Parse(target.BodyTemplate)
+			if err != nil {
+				ReleaseInterventionJob(job)
+				return nil, fmt.Errorf("invalid webhook body_template: %w", err)
+			}
+			job.Template = tmpl
+		}
+		return job, nil
+
+	default:
+		return nil, ErrUnknownInterventionAction
+	}
+}
+
+// CreateInterventionStepJob creates the job for one escalation-chain step,
+// wrapped so its result payload carries the step index.
+func CreateInterventionStepJob(step schema.InterventionStep, index int, jobID ecs.Entity) (Job, error) {
+	inner, err := CreateInterventionJob(schema.Intervention{
+		Action:  step.Action,
+		Target:  step.Target,
+		Retries: step.Retries,
+		DryRun:  step.DryRun,
+	}, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return &InterventionStepJob{Job: inner, Step: index}, nil
+}
+
+// CreateCodeJob creates a new code alert job based on the provided configuration.
+// Supported notification types: log, pagerduty, slack, email, webhook.
+//
+// Jobs are obtained from sync.Pool for memory efficiency.
+func CreateCodeJob(monitor string, config schema.CodeConfig, jobID ecs.Entity, color string) (Job, error) {
+	return createCodeJob(monitor, config, jobID, color, codeAlertTemplateFor(color))
+}
+
+// CreateInvertedCodeJob is CreateCodeJob with the inverted-monitor alert
+// templates: the monitor watches an endpoint expected to be down, so the
+// failure colors describe an unexpected response instead of an outage.
+func CreateInvertedCodeJob(monitor string, config schema.CodeConfig, jobID ecs.Entity, color string) (Job, error) {
+	return createCodeJob(monitor, config, jobID, color, invertedCodeAlertTemplateFor(color))
+}
+
+// CreateCrashCodeJob is CreateCodeJob with the crash alert template: the
+// monitor was auto-disabled because its pulse check kept panicking, so the
+// red wording describes the crash rather than a target outage.
+func CreateCrashCodeJob(monitor string, config schema.CodeConfig, jobID ecs.Entity, color string) (Job, error) {
+	return createCodeJob(monitor, config, jobID, color, crashCodeAlertTemplateFor(color))
+}
+
+func createCodeJob(monitor string, config schema.CodeConfig, jobID ecs.Entity, color string, template codeAlertTemplate) (Job, error) {
+	colorValue := color
+	monitorValue := monitor
+
+	switch config.Notify {
+	case "log":
+		logJob := getCodeLogJob()
+		logJob.Entity = jobID
+		logJob.Monitor = monitorValue
+		logJob.Color = colorValue
+		logJob.Status = template.Status
+		logJob.Severity = template.Severity
+		logJob.Summary = template.Summary
+		logJob.Action = template.Action
+		logJob.NextSteps = template.NextSteps
+		logJob.File = ""
+		if logCfg, ok := config.Config.(*schema.CodeNotificationLog); ok && logCfg != nil {
+			logJob.File = logCfg.File
+		}
+		return logJob, nil
+
+	case "pagerduty":
+		job := getCodePagerDutyJob()
+		job.Entity = jobID
+		job.Monitor = monitorValue
+		job.Color = colorValue
+		job.Status = template.Status
+		job.Severity = template.Severity
+		job.Summary = template.Summary
+		job.Action = template.Action
+		if pdCfg, ok := config.Config.(*schema.CodeNotificationPagerDuty); ok && pdCfg != nil {
+			job.URL = interning.Intern(pdCfg.URL)
+			job.RoutingKey = pdCfg.RoutingKey
+			if job.RoutingKey == "" && pdCfg.RoutingKeyEnv != "" {
+				job.RoutingKey = os.Getenv(pdCfg.RoutingKeyEnv)
+			}
+		}
+		return job, nil
+
+	case "slack":
+		job := getCodeSlackJob()
+		job.Entity = jobID
+		job.Monitor = monitorValue
+		job.Color = colorValue
+		job.Status = template.Status
+		job.Severity = template.Severity
+		job.Summary = template.Summary
+		job.Action = template.Action
+		job.NextSteps = template.NextSteps
+		if slackCfg, ok := config.Config.(*schema.CodeNotificationSlack); ok && slackCfg != nil {
+			job.WebHook = slackCfg.WebHook
+		}
+		return job, nil
+
+	case "email":
+		job := getCodeEmailJob()
+		job.Entity = jobID
+		job.Monitor = monitorValue
+		job.Color = colorValue
+		if emailCfg, ok := config.Config.(*schema.CodeNotificationEmail); ok && emailCfg != nil {
+			job.Host = interning.Intern(emailCfg.SMTPHost)
+			job.Port = emailCfg.SMTPPort
+			job.From = emailCfg.From
+			job.To = emailCfg.To
+			job.SubjectPrefix = emailCfg.SubjectPrefix
+			job.Username = emailCfg.Username
+			job.Password = emailCfg.Password
+			job.StartTLS = emailCfg.StartTLS
+			if job.Username == "" && emailCfg.UsernameEnv != "" {
+				job.Username = os.Getenv(emailCfg.UsernameEnv)
+			}
+			if job.Password == "" && emailCfg.PasswordEnv != "" {
+				job.Password = os.Getenv(emailCfg.PasswordEnv)
+			}
+		}
+		return job, nil
+
+	case "webhook":
+		job := getCodeWebhookJob()
+		job.Entity = jobID
+		job.Monitor = monitorValue
+		job.Color = colorValue
+		job.Status = template.Status
+		job.Severity = template.Severity
+		job.Summary = template.Summary
+		job.Action = template.Action
+		job.NextSteps = template.NextSteps
+		if whCfg, ok := config.Config.(*schema.CodeNotificationWebhook); ok && whCfg != nil {
+			job.URL = interning.Intern(whCfg.URL)
+			job.Method = whCfg.Method
+			job.Headers = whCfg.Headers
+			job.Secret = whCfg.Secret
+			if whCfg.PayloadTemplate != "" {
+				// Parse at creation so bad templates fail the load with the
+				// monitor name, not the first alert.
+				tmpl, err := texttemplate.New("webhook").Parse(whCfg.PayloadTemplate)
+				if err != nil {
+					ReleaseCodeJob(job)
+					return nil, fmt.Errorf("monitor %q: invalid webhook payload_template: %w", monitorValue, err)
+				}
+				job.Template = tmpl
+			}
+		}
+		return job, nil
+
+	default:
+		return nil, ErrUnknownCodeNotification
+	}
+}
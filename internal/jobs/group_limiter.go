@@ -0,0 +1,184 @@
+// Per-group concurrency limiting for outbound checks.
+//
+// The dynamic worker pool can scale far beyond what a single downstream
+// service tolerates: tens of thousands of monitors pointed at one API
+// gateway all fire at once. The group limiter caps in-flight checks per
+// concurrency group (the first entry of a monitor's pulse groups), so one
+// hot target cannot be flooded while unrelated groups keep draining.
+package jobs
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultConcurrencyGroup is the group used for jobs whose monitor has no
+// pulse groups configured, and the limits key that caps groups without an
+// explicit limit of their own.
+const DefaultConcurrencyGroup = "default"
+
+// GroupedJob is implemented by jobs subject to per-group concurrency
+// limits. The worker pool dispatcher acquires a group slot before invoking
+// the job and releases it when execution finishes.
+type GroupedJob interface {
+	Job
+	ConcurrencyGroup() string
+}
+
+// GroupConcurrencyStats is a snapshot of one group's limiter state.
+type GroupConcurrencyStats struct {
+	Group     string
+	InFlight  int
+	Limit     int
+	Throttled uint64
+}
+
+// groupSlot tracks one group's in-flight checks and throttle count.
+type groupSlot struct {
+	sem       chan struct{}
+	throttled atomic.Uint64
+}
+
+// GroupLimiter caps in-flight checks per concurrency group. Groups without
+// a configured limit (directly or via the "default" key) are unlimited and
+// tracked only lazily, so the limiter costs nothing when unconfigured.
+type GroupLimiter struct {
+	mu      sync.RWMutex
+	limits  map[string]int
+	slots   map[string]*groupSlot
+	enabled atomic.Bool
+}
+
+var globalGroupLimiter = &GroupLimiter{slots: make(map[string]*groupSlot)}
+
+// GetGroupLimiter returns the global group limiter.
+func GetGroupLimiter() *GroupLimiter {
+	return globalGroupLimiter
+}
+
+// SetGroupLimits configures per-group maximum in-flight checks on the
+// global limiter. The "default" key caps groups without an explicit entry;
+// zero or missing means unlimited. Call at startup before pools run: any
+// in-flight slots are discarded by the reconfiguration.
+func SetGroupLimits(limits map[string]int) {
+	globalGroupLimiter.SetLimits(limits)
+}
+
+// SetLimits replaces the limiter's configuration. See SetGroupLimits.
+func (g *GroupLimiter) SetLimits(limits map[string]int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limits = make(map[string]int, len(limits))
+	for group, limit := range limits {
+		if limit > 0 {
+			g.limits[group] = limit
+		}
+	}
+	g.slots = make(map[string]*groupSlot)
+	g.enabled.Store(len(g.limits) > 0)
+}
+
+// limitFor resolves the effective limit for a group: its own entry, the
+// "default" entry, or 0 for unlimited.
+func (g *GroupLimiter) limitFor(group string) int {
+	if limit, ok := g.limits[group]; ok {
+		return limit
+	}
+	return g.limits[DefaultConcurrencyGroup]
+}
+
+// slotFor returns the slot tracking the group, creating it on first use.
+// Returns nil for unlimited groups.
+func (g *GroupLimiter) slotFor(group string) *groupSlot {
+	g.mu.RLock()
+	slot, ok := g.slots[group]
+	g.mu.RUnlock()
+	if ok {
+		return slot
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if slot, ok = g.slots[group]; ok {
+		return slot
+	}
+	limit := g.limitFor(group)
+	if limit <= 0 {
+		return nil
+	}
+	slot = &groupSlot{sem: make(chan struct{}, limit)}
+	g.slots[group] = slot
+	return slot
+}
+
+// TryAcquire attempts to take an in-flight slot for the group without
+// blocking. Returns true when the group is unlimited or below its limit;
+// a false return is counted as a throttle and the job should be re-enqueued.
+func (g *GroupLimiter) TryAcquire(group string) bool {
+	if !g.enabled.Load() {
+		return true
+	}
+	if group == "" {
+		group = DefaultConcurrencyGroup
+	}
+	slot := g.slotFor(group)
+	if slot == nil {
+		return true
+	}
+	select {
+	case slot.sem <- struct{}{}:
+		return true
+	default:
+		slot.throttled.Add(1)
+		return false
+	}
+}
+
+// Release returns the group's in-flight slot. Must be called exactly once
+// after TryAcquire returned true.
+func (g *GroupLimiter) Release(group string) {
+	if !g.enabled.Load() {
+		return
+	}
+	if group == "" {
+		group = DefaultConcurrencyGroup
+	}
+	g.mu.RLock()
+	slot := g.slots[group]
+	g.mu.RUnlock()
+	if slot == nil {
+		return
+	}
+	select {
+	case <-slot.sem:
+	default:
+		// Semaphore already empty (limits were reconfigured mid-flight)
+	}
+}
+
+// Stats returns a snapshot of every limited group seen so far, sorted by
+// group name for stable logging.
+func (g *GroupLimiter) Stats() []GroupConcurrencyStats {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if len(g.slots) == 0 {
+		return nil
+	}
+	stats := make([]GroupConcurrencyStats, 0, len(g.slots))
+	for group, slot := range g.slots {
+		stats = append(stats, GroupConcurrencyStats{
+			Group:     group,
+			InFlight:  len(slot.sem),
+			Limit:     cap(slot.sem),
+			Throttled: slot.throttled.Load(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Group < stats[j].Group })
+	return stats
+}
+
+// GroupLimiterStats returns the global limiter's per-group snapshot.
+func GroupLimiterStats() []GroupConcurrencyStats {
+	return globalGroupLimiter.Stats()
+}
@@ -0,0 +1,257 @@
+package jobs
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func newTestGroupLimiter(limits map[string]int) *GroupLimiter {
+	g := &GroupLimiter{slots: make(map[string]*groupSlot)}
+	g.SetLimits(limits)
+	return g
+}
+
+// TestGroupLimiter_Unconfigured tests that an unconfigured limiter never throttles
+func TestGroupLimiter_Unconfigured(t *testing.T) {
+	t.Parallel()
+	g := newTestGroupLimiter(nil)
+
+	for i := 0; i < 100; i++ {
+		if !g.TryAcquire("payments-api") {
+			t.Fatal("expected TryAcquire on unconfigured limiter to succeed")
+		}
+	}
+	// Release on an unconfigured limiter must be a no-op, not a panic.
+	g.Release("payments-api")
+
+	if stats := g.Stats(); stats != nil {
+		t.Errorf("Stats on unconfigured limiter = %v, want nil", stats)
+	}
+}
+
+// TestGroupLimiter_PerGroupLimits tests two groups with different limits
+func TestGroupLimiter_PerGroupLimits(t *testing.T) {
+	t.Parallel()
+	g := newTestGroupLimiter(map[string]int{
+		"payments-api": 2,
+		"search":       3,
+	})
+
+	// Exhaust payments-api
+	if !g.TryAcquire("payments-api") || !g.TryAcquire("payments-api") {
+		t.Fatal("expected first two payments-api acquires to succeed")
+	}
+	if g.TryAcquire("payments-api") {
+		t.Fatal("expected third payments-api acquire to fail")
+	}
+
+	// search has its own budget
+	for i := 0; i < 3; i++ {
+		if !g.TryAcquire("search") {
+			t.Fatalf("expected search acquire #%d to succeed", i+1)
+		}
+	}
+	if g.TryAcquire("search") {
+		t.Fatal("expected fourth search acquire to fail")
+	}
+
+	// Releasing one payments-api slot frees exactly one acquire
+	g.Release("payments-api")
+	if !g.TryAcquire("payments-api") {
+		t.Fatal("expected payments-api acquire after Release to succeed")
+	}
+}
+
+// TestGroupLimiter_DefaultKeyFallback tests the "default" key capping unlisted groups
+func TestGroupLimiter_DefaultKeyFallback(t *testing.T) {
+	t.Parallel()
+	g := newTestGroupLimiter(map[string]int{
+		"payments-api": 5,
+		"default":      1,
+	})
+
+	// Unlisted group inherits the default limit of 1
+	if !g.TryAcquire("unlisted") {
+		t.Fatal("expected first unlisted acquire to succeed")
+	}
+	if g.TryAcquire("unlisted") {
+		t.Fatal("expected second unlisted acquire to be capped by default limit")
+	}
+
+	// The empty group maps onto "default" and shares its budget
+	if !g.TryAcquire("") {
+		t.Fatal("expected empty-group acquire to succeed independently of unlisted")
+	}
+	g.Release("")
+
+	// Explicit entries are not affected by the default key
+	if !g.TryAcquire("payments-api") || !g.TryAcquire("payments-api") {
+		t.Fatal("expected payments-api to use its own limit, not default")
+	}
+}
+
+// TestGroupLimiter_ZeroLimitIsUnlimited tests that zero entries are dropped
+func TestGroupLimiter_ZeroLimitIsUnlimited(t *testing.T) {
+	t.Parallel()
+	g := newTestGroupLimiter(map[string]int{
+		"payments-api": 0,
+		"search":       2,
+	})
+
+	for i := 0; i < 50; i++ {
+		if !g.TryAcquire("payments-api") {
+			t.Fatal("expected zero-limit group to be unlimited")
+		}
+	}
+}
+
+// TestGroupLimiter_ThrottledCount tests throttle accounting in Stats
+func TestGroupLimiter_ThrottledCount(t *testing.T) {
+	t.Parallel()
+	g := newTestGroupLimiter(map[string]int{"search": 1})
+
+	if !g.TryAcquire("search") {
+		t.Fatal("expected first acquire to succeed")
+	}
+	for i := 0; i < 3; i++ {
+		if g.TryAcquire("search") {
+			t.Fatal("expected saturated acquire to fail")
+		}
+	}
+
+	stats := g.Stats()
+	if len(stats) != 1 {
+		t.Fatalf("Stats length = %d, want 1", len(stats))
+	}
+	if stats[0].Group != "search" {
+		t.Errorf("Group = %q, want %q", stats[0].Group, "search")
+	}
+	if stats[0].InFlight != 1 {
+		t.Errorf("InFlight = %d, want 1", stats[0].InFlight)
+	}
+	if stats[0].Limit != 1 {
+		t.Errorf("Limit = %d, want 1", stats[0].Limit)
+	}
+	if stats[0].Throttled != 3 {
+		t.Errorf("Throttled = %d, want 3", stats[0].Throttled)
+	}
+
+	g.Release("search")
+	if got := g.Stats()[0].InFlight; got != 0 {
+		t.Errorf("InFlight after Release = %d, want 0", got)
+	}
+}
+
+// TestGroupLimiter_StatsSorted tests that Stats is sorted by group name
+func TestGroupLimiter_StatsSorted(t *testing.T) {
+	t.Parallel()
+	g := newTestGroupLimiter(map[string]int{"zebra": 1, "alpha": 1, "mango": 1})
+
+	// Touch the groups out of order so lazy slot creation cannot hide sorting
+	for _, group := range []string{"zebra", "mango", "alpha"} {
+		g.TryAcquire(group)
+	}
+
+	stats := g.Stats()
+	if len(stats) != 3 {
+		t.Fatalf("Stats length = %d, want 3", len(stats))
+	}
+	for i, want := range []string{"alpha", "mango", "zebra"} {
+		if stats[i].Group != want {
+			t.Errorf("Stats[%d].Group = %q, want %q", i, stats[i].Group, want)
+		}
+	}
+}
+
+// TestGroupLimiter_Reconfigure tests that SetLimits resets slots safely
+func TestGroupLimiter_Reconfigure(t *testing.T) {
+	t.Parallel()
+	g := newTestGroupLimiter(map[string]int{"search": 1})
+
+	if !g.TryAcquire("search") {
+		t.Fatal("expected acquire to succeed")
+	}
+
+	// Reconfigure while a slot is held; the old slot is discarded
+	g.SetLimits(map[string]int{"search": 2})
+
+	// Release for the pre-reconfiguration acquire must not panic or
+	// underflow the new semaphore.
+	g.Release("search")
+	if !g.TryAcquire("search") || !g.TryAcquire("search") {
+		t.Fatal("expected two acquires under the new limit to succeed")
+	}
+	if g.TryAcquire("search") {
+		t.Fatal("expected third acquire to fail under new limit of 2")
+	}
+
+	// Clearing the configuration disables the limiter entirely
+	g.SetLimits(nil)
+	for i := 0; i < 10; i++ {
+		if !g.TryAcquire("search") {
+			t.Fatal("expected acquire after clearing limits to succeed")
+		}
+	}
+}
+
+// TestGroupLimiter_ConcurrencyLimit tests that the limit holds under contention
+func TestGroupLimiter_ConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+	const limit = 4
+	g := newTestGroupLimiter(map[string]int{"search": limit})
+
+	var current, maxObserved atomic.Int32
+	var wg sync.WaitGroup
+
+	const numGoroutines = 32
+	wg.Add(numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if !g.TryAcquire("search") {
+					continue
+				}
+				c := current.Add(1)
+				for {
+					old := maxObserved.Load()
+					if c <= old || maxObserved.CompareAndSwap(old, c) {
+						break
+					}
+				}
+				current.Add(-1)
+				g.Release("search")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if observed := maxObserved.Load(); observed > limit {
+		t.Errorf("Max observed concurrency = %d, exceeds limit %d", observed, limit)
+	}
+	if got := g.Stats()[0].InFlight; got != 0 {
+		t.Errorf("InFlight after test = %d, want 0", got)
+	}
+}
+
+// TestSetGroupLimits_Global tests the global limiter wiring used by the controller
+func TestSetGroupLimits_Global(t *testing.T) {
+	defer SetGroupLimits(nil)
+
+	SetGroupLimits(map[string]int{"payments-api": 1})
+
+	limiter := GetGroupLimiter()
+	if !limiter.TryAcquire("payments-api") {
+		t.Fatal("expected global acquire to succeed")
+	}
+	if limiter.TryAcquire("payments-api") {
+		t.Fatal("expected saturated global acquire to fail")
+	}
+	limiter.Release("payments-api")
+
+	stats := GroupLimiterStats()
+	if len(stats) != 1 || stats[0].Group != "payments-api" || stats[0].Throttled != 1 {
+		t.Errorf("GroupLimiterStats = %+v, want one payments-api entry with Throttled=1", stats)
+	}
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"bytes"
+	"errors"
+)
+
+// maxBodyCheckBytes caps how much of a response body the body assertions
+// inspect. Bounding the check keeps a misbehaving endpoint that streams
+// megabytes from bloating memory at 1M-monitor scale; anything past the cap
+// is simply not matched against.
+const maxBodyCheckBytes = 64 * 1024
+
+// checkBody evaluates the configured body assertions against the (capped)
+// response body. Returns nil when no assertions are configured.
+func (p *PulseHTTPJob) checkBody(body []byte) error {
+	if p.BodyContains == "" && p.BodyRegex == nil {
+		return nil
+	}
+	if len(body) > maxBodyCheckBytes {
+		body = body[:maxBodyCheckBytes]
+	}
+	if p.BodyContains != "" && !bytes.Contains(body, []byte(p.BodyContains)) {
+		return ErrBodyAssertion
+	}
+	if p.BodyRegex != nil && !p.BodyRegex.Match(body) {
+		return ErrBodyAssertion
+	}
+	return nil
+}
+
+// assertionReason maps an assertion failure to the payload value naming it,
+// or "" for errors that are not assertion failures.
+func assertionReason(err error) string {
+	switch {
+	case errors.Is(err, ErrStatusAssertion):
+		return "status"
+	case errors.Is(err, ErrBodyAssertion):
+		return "body"
+	default:
+		return ""
+	}
+}
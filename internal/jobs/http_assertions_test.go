@@ -0,0 +1,164 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+
+	"cpra/internal/loader/schema"
+)
+
+// newAssertionTestJob builds an HTTP job pointed at a test server.
+func newAssertionTestJob(t *testing.T, serverURL string) *PulseHTTPJob {
+	t.Helper()
+	host, isTLS, err := ExtractHostFromURL(serverURL)
+	if err != nil {
+		t.Fatalf("failed to extract host: %v", err)
+	}
+	return &PulseHTTPJob{
+		URL:     serverURL,
+		Method:  "GET",
+		Host:    host,
+		IsTLS:   isTLS,
+		Timeout: 5 * time.Second,
+	}
+}
+
+// TestPulseHTTPJob_Execute_ExpectedStatus tests that a non-2xx code listed in
+// expected_status passes, and an unlisted one fails naming the assertion.
+func TestPulseHTTPJob_Execute_ExpectedStatus(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	job := newAssertionTestJob(t, server.URL)
+	job.ExpectedStatus, _ = schema.ParseStatusRanges("200-204,401")
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Errorf("401 listed in expected_status must pass, got %v", result.Err)
+	}
+
+	job = newAssertionTestJob(t, server.URL)
+	job.ExpectedStatus, _ = schema.ParseStatusRanges("200-204")
+	result := job.Execute(context.Background())
+	if result.Err == nil {
+		t.Fatal("401 outside expected_status must fail")
+	}
+	if got := result.Payload.Extra("failed_assertion"); got != "status" {
+		t.Errorf("failed_assertion = %v, want %q", got, "status")
+	}
+}
+
+// TestPulseHTTPJob_Execute_BodyAssertions tests substring and regex matching
+// against the response body.
+func TestPulseHTTPJob_Execute_BodyAssertions(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"degraded"}`))
+	}))
+	defer server.Close()
+
+	job := newAssertionTestJob(t, server.URL)
+	job.BodyRegex = regexp.MustCompile(`"status":"(ok|degraded)"`)
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Errorf("Matching body_regex must pass, got %v", result.Err)
+	}
+
+	job = newAssertionTestJob(t, server.URL)
+	job.BodyContains = `"status":"ok"`
+	result := job.Execute(context.Background())
+	if result.Err == nil {
+		t.Fatal("Missing substring must fail the check")
+	}
+	if got := result.Payload.Extra("failed_assertion"); got != "body" {
+		t.Errorf("failed_assertion = %v, want %q", got, "body")
+	}
+}
+
+// TestPulseHTTPJob_Execute_BodyCap tests that assertions only see the first
+// maxBodyCheckBytes of the response.
+func TestPulseHTTPJob_Execute_BodyCap(t *testing.T) {
+	t.Parallel()
+	padding := strings.Repeat("x", maxBodyCheckBytes)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(padding + "needle"))
+	}))
+	defer server.Close()
+
+	job := newAssertionTestJob(t, server.URL)
+	job.BodyContains = "needle"
+	if result := job.Execute(context.Background()); result.Err == nil {
+		t.Error("Substring past the cap must not match")
+	}
+
+	job = newAssertionTestJob(t, server.URL)
+	job.BodyContains = "xxx"
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Errorf("Substring within the cap must match, got %v", result.Err)
+	}
+}
+
+// TestPulseHTTPJob_ExecuteDetailed_BodyAssertion tests assertions on the
+// traced path, where the body is read through a limited reader.
+func TestPulseHTTPJob_ExecuteDetailed_BodyAssertion(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"degraded"}`))
+	}))
+	defer server.Close()
+
+	job := newAssertionTestJob(t, server.URL)
+	job.DetailedTiming = true
+	job.BodyContains = `"status":"ok"`
+	result := job.Execute(context.Background())
+	if result.Err == nil {
+		t.Fatal("Missing substring must fail the detailed check")
+	}
+	if got := result.Payload.Extra("failed_assertion"); got != "body" {
+		t.Errorf("failed_assertion = %v, want %q", got, "body")
+	}
+}
+
+// TestCreatePulseJob_AssertionConfig tests schema wiring and that malformed
+// specs fail at load time.
+func TestCreatePulseJob_AssertionConfig(t *testing.T) {
+	t.Parallel()
+	entity := ecs.Entity{}
+
+	pulse := schema.Pulse{
+		Type:    "http",
+		Timeout: 5 * time.Second,
+		Config: &schema.PulseHTTPConfig{
+			Url:            "http://example.com/health",
+			ExpectedStatus: "200-204,301",
+			BodyContains:   "ok",
+			BodyRegex:      `"status":"ok"`,
+		},
+	}
+	job, err := CreatePulseJob(pulse, entity)
+	if err != nil {
+		t.Fatalf("CreatePulseJob failed: %v", err)
+	}
+	httpJob := job.(*PulseHTTPJob)
+	defer ReleasePulseJob(httpJob)
+	if len(httpJob.ExpectedStatus) != 2 || httpJob.BodyRegex == nil || httpJob.BodyContains != "ok" {
+		t.Errorf("Assertion config not wired: %+v", httpJob)
+	}
+
+	pulse.Config = &schema.PulseHTTPConfig{Url: "http://example.com", BodyRegex: `(unclosed`}
+	if _, err := CreatePulseJob(pulse, entity); err == nil {
+		t.Error("Expected compile error for invalid body_regex")
+	}
+
+	pulse.Config = &schema.PulseHTTPConfig{Url: "http://example.com", ExpectedStatus: "7xx"}
+	if _, err := CreatePulseJob(pulse, entity); err == nil {
+		t.Error("Expected parse error for invalid expected_status")
+	}
+}
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"cpra/internal/loader/schema"
+)
+
+// HTTPHeader is one pre-parsed request header, applied on every attempt.
+// Headers are parsed once at job creation so Execute stays allocation-free.
+type HTTPHeader struct {
+	Name  string
+	Value string
+}
+
+// ParseHTTPHeaders converts the canonical schema headers into job headers.
+// The string-vs-map shape work happens in the schema unmarshalers; this copy
+// still rejects empty names so configs built in code (bypassing unmarshal)
+// fail at job creation rather than sending a nameless header.
+func ParseHTTPHeaders(raw schema.HeaderList) ([]HTTPHeader, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	headers := make([]HTTPHeader, 0, len(raw))
+	for _, entry := range raw {
+		if strings.TrimSpace(entry.Name) == "" {
+			return nil, fmt.Errorf("malformed header: empty name for value %q", entry.Value)
+		}
+		headers = append(headers, HTTPHeader{Name: entry.Name, Value: entry.Value})
+	}
+	return headers, nil
+}
+
+// authHeader resolves an auth block into its Authorization header.
+// Env-sourced credentials are read here, at job creation, so a missing
+// variable fails the load instead of every check.
+func authHeader(auth *schema.HTTPAuth) (HTTPHeader, error) {
+	switch strings.ToLower(auth.Type) {
+	case "basic":
+		password := auth.Password
+		if auth.PasswordEnv != "" {
+			password = os.Getenv(auth.PasswordEnv)
+			if password == "" {
+				return HTTPHeader{}, fmt.Errorf("auth password_env %q is not set", auth.PasswordEnv)
+			}
+		}
+		encoded := base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + password))
+		return HTTPHeader{Name: "Authorization", Value: "Basic " + encoded}, nil
+
+	case "bearer":
+		token := auth.Token
+		if auth.TokenEnv != "" {
+			token = os.Getenv(auth.TokenEnv)
+			if token == "" {
+				return HTTPHeader{}, fmt.Errorf("auth token_env %q is not set", auth.TokenEnv)
+			}
+		}
+		return HTTPHeader{Name: "Authorization", Value: "Bearer " + token}, nil
+
+	default:
+		// The validator rejects unknown schemes at load time.
+		return HTTPHeader{}, fmt.Errorf("unknown auth type %q", auth.Type)
+	}
+}
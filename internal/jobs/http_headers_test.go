@@ -0,0 +1,288 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+	"gopkg.in/yaml.v3"
+
+	"cpra/internal/loader/schema"
+)
+
+// =============================================================================
+// Header Parsing Tests
+// =============================================================================
+
+// TestParseHTTPHeaders tests schema-to-job conversion and empty-name
+// rejection; the string/map shape parsing is tested with the schema package.
+func TestParseHTTPHeaders(t *testing.T) {
+	t.Parallel()
+
+	headers, err := ParseHTTPHeaders(schema.HeaderList{
+		{Name: "Accept", Value: "application/json"},
+		{Name: "X-Empty", Value: ""},
+		{Name: "Host", Value: "internal.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("ParseHTTPHeaders failed: %v", err)
+	}
+	want := []HTTPHeader{
+		{Name: "Accept", Value: "application/json"},
+		{Name: "X-Empty", Value: ""},
+		{Name: "Host", Value: "internal.example.com"},
+	}
+	if len(headers) != len(want) {
+		t.Fatalf("Expected %d headers, got %d", len(want), len(headers))
+	}
+	for i, h := range want {
+		if headers[i] != h {
+			t.Errorf("headers[%d] = %+v, want %+v", i, headers[i], h)
+		}
+	}
+
+	// Empty names only occur in configs built in code, never from unmarshal.
+	for _, name := range []string{"", "   "} {
+		if _, err := ParseHTTPHeaders(schema.HeaderList{{Name: name, Value: "v"}}); err == nil {
+			t.Errorf("Expected error for empty header name %q", name)
+		}
+	}
+
+	if headers, err := ParseHTTPHeaders(nil); err != nil || headers != nil {
+		t.Errorf("ParseHTTPHeaders(nil) = %v, %v, want nil, nil", headers, err)
+	}
+}
+
+// =============================================================================
+// Auth Resolution Tests
+// =============================================================================
+
+// TestAuthHeader_Basic tests inline basic auth encoding.
+func TestAuthHeader_Basic(t *testing.T) {
+	t.Parallel()
+	header, err := authHeader(&schema.HTTPAuth{Type: "basic", Username: "admin", Password: "secret"})
+	if err != nil {
+		t.Fatalf("authHeader failed: %v", err)
+	}
+	// base64("admin:secret")
+	if header.Name != "Authorization" || header.Value != "Basic YWRtaW46c2VjcmV0" {
+		t.Errorf("header = %+v, want basic Authorization", header)
+	}
+}
+
+// TestAuthHeader_BearerFromEnv tests env-sourced bearer tokens.
+func TestAuthHeader_BearerFromEnv(t *testing.T) {
+	t.Setenv("CPRA_TEST_TOKEN", "tok-123")
+
+	header, err := authHeader(&schema.HTTPAuth{Type: "bearer", TokenEnv: "CPRA_TEST_TOKEN"})
+	if err != nil {
+		t.Fatalf("authHeader failed: %v", err)
+	}
+	if header.Value != "Bearer tok-123" {
+		t.Errorf("Value = %q, want %q", header.Value, "Bearer tok-123")
+	}
+}
+
+// TestAuthHeader_MissingEnv tests that an unset env var fails job creation.
+func TestAuthHeader_MissingEnv(t *testing.T) {
+	t.Parallel()
+	_, err := authHeader(&schema.HTTPAuth{Type: "bearer", TokenEnv: "CPRA_TEST_UNSET_TOKEN"})
+	if err == nil || !strings.Contains(err.Error(), "CPRA_TEST_UNSET_TOKEN") {
+		t.Errorf("Expected missing-env error naming the variable, got %v", err)
+	}
+
+	_, err = authHeader(&schema.HTTPAuth{Type: "basic", Username: "admin", PasswordEnv: "CPRA_TEST_UNSET_PASSWORD"})
+	if err == nil || !strings.Contains(err.Error(), "CPRA_TEST_UNSET_PASSWORD") {
+		t.Errorf("Expected missing-env error naming the variable, got %v", err)
+	}
+}
+
+// =============================================================================
+// Execute Tests
+// =============================================================================
+
+// TestPulseHTTPJob_Execute_SendsHeaders tests that custom headers and the
+// resolved Authorization header arrive on the fasthttp fast path.
+func TestPulseHTTPJob_Execute_SendsHeaders(t *testing.T) {
+	t.Parallel()
+	var gotAccept, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	host, isTLS, _ := ExtractHostFromURL(server.URL)
+	job := &PulseHTTPJob{
+		URL:     server.URL,
+		Method:  "GET",
+		Host:    host,
+		IsTLS:   isTLS,
+		Timeout: 5 * time.Second,
+		Headers: []HTTPHeader{
+			{Name: "Accept", Value: "application/json"},
+			{Name: "Authorization", Value: "Bearer tok-123"},
+		},
+	}
+
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	if gotAccept != "application/json" {
+		t.Errorf("Accept = %q, want %q", gotAccept, "application/json")
+	}
+	if gotAuth != "Bearer tok-123" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer tok-123")
+	}
+}
+
+// TestPulseHTTPJob_Execute_HeaderFormsEquivalent tests that the list and
+// map manifest forms of the same headers produce identical requests on the
+// wire, and that a value containing colons survives the map form intact.
+func TestPulseHTTPJob_Execute_HeaderFormsEquivalent(t *testing.T) {
+	t.Parallel()
+	var captured []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = append(captured, r.Header.Clone())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	listForm := `
+type: http
+interval: 30s
+timeout: 5s
+config:
+  url: ` + server.URL + `
+  method: GET
+  headers:
+    - "Authorization: Bearer a:b:c"
+    - "X-Window: 12:30-13:30"
+`
+	mapForm := `
+type: http
+interval: 30s
+timeout: 5s
+config:
+  url: ` + server.URL + `
+  method: GET
+  headers:
+    X-Window: 12:30-13:30
+    Authorization: Bearer a:b:c
+`
+	for _, manifest := range []string{listForm, mapForm} {
+		var pulse schema.Pulse
+		if err := yaml.Unmarshal([]byte(manifest), &pulse); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		job, err := CreatePulseJob(pulse, ecs.Entity{})
+		if err != nil {
+			t.Fatalf("CreatePulseJob failed: %v", err)
+		}
+		if result := job.Execute(context.Background()); result.Err != nil {
+			t.Fatalf("Execute failed: %v", result.Err)
+		}
+		ReleasePulseJob(job.(*PulseHTTPJob))
+	}
+
+	if len(captured) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(captured))
+	}
+	if !reflect.DeepEqual(captured[0], captured[1]) {
+		t.Errorf("List and map forms sent different headers:\nlist: %v\nmap:  %v", captured[0], captured[1])
+	}
+	if got := captured[0].Get("Authorization"); got != "Bearer a:b:c" {
+		t.Errorf("Authorization = %q, want colon-containing value intact", got)
+	}
+	if got := captured[0].Get("X-Window"); got != "12:30-13:30" {
+		t.Errorf("X-Window = %q, want %q", got, "12:30-13:30")
+	}
+}
+
+// TestPulseHTTPJob_ExecuteDetailed_SendsHeaders tests headers on the traced
+// net/http path, including the Host override.
+func TestPulseHTTPJob_ExecuteDetailed_SendsHeaders(t *testing.T) {
+	t.Parallel()
+	var gotHost, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := &PulseHTTPJob{
+		URL:            server.URL,
+		Method:         "GET",
+		Timeout:        5 * time.Second,
+		DetailedTiming: true,
+		Headers: []HTTPHeader{
+			{Name: "Host", Value: "internal.example.com"},
+			{Name: "Authorization", Value: "Basic YWRtaW46c2VjcmV0"},
+		},
+	}
+
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	if gotHost != "internal.example.com" {
+		t.Errorf("Host = %q, want %q", gotHost, "internal.example.com")
+	}
+	if gotAuth != "Basic YWRtaW46c2VjcmV0" {
+		t.Errorf("Authorization = %q, want basic credentials", gotAuth)
+	}
+}
+
+// TestCreatePulseJob_HeadersAndAuth tests the schema-to-job wiring.
+func TestCreatePulseJob_HeadersAndAuth(t *testing.T) {
+	t.Setenv("CPRA_TEST_FACTORY_TOKEN", "factory-tok")
+
+	pulse := schema.Pulse{
+		Type:    "http",
+		Timeout: 5 * time.Second,
+		Config: &schema.PulseHTTPConfig{
+			Url:     "http://example.com/health",
+			Method:  "GET",
+			Headers: schema.HeaderList{{Name: "Accept", Value: "text/plain"}},
+			Auth:    &schema.HTTPAuth{Type: "bearer", TokenEnv: "CPRA_TEST_FACTORY_TOKEN"},
+		},
+	}
+
+	entity := ecs.Entity{}
+	job, err := CreatePulseJob(pulse, entity)
+	if err != nil {
+		t.Fatalf("CreatePulseJob failed: %v", err)
+	}
+	httpJob, ok := job.(*PulseHTTPJob)
+	if !ok {
+		t.Fatalf("Expected *PulseHTTPJob, got %T", job)
+	}
+	defer ReleasePulseJob(httpJob)
+
+	if len(httpJob.Headers) != 2 {
+		t.Fatalf("Expected 2 headers, got %d", len(httpJob.Headers))
+	}
+	if httpJob.Headers[0] != (HTTPHeader{Name: "Accept", Value: "text/plain"}) {
+		t.Errorf("Headers[0] = %+v", httpJob.Headers[0])
+	}
+	if httpJob.Headers[1] != (HTTPHeader{Name: "Authorization", Value: "Bearer factory-tok"}) {
+		t.Errorf("Headers[1] = %+v", httpJob.Headers[1])
+	}
+
+	// A nameless header fails creation rather than being dropped.
+	pulse.Config = &schema.PulseHTTPConfig{
+		Url:     "http://example.com/health",
+		Headers: schema.HeaderList{{Name: "", Value: "not-a-header"}},
+	}
+	if _, err := CreatePulseJob(pulse, entity); err == nil {
+		t.Error("Expected error for nameless header")
+	}
+}
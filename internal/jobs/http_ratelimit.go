@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rate-limited responses are the target asking the prober to back off, not
+// evidence of ill health. Both HTTP execute paths convert them into a
+// distinct outcome — ErrRateLimited plus rate_limited/retry_after_seconds
+// payload extras — instead of burning retries against the limiter. The
+// result system decides what that outcome means for the monitor's state.
+
+// rateLimitError carries the parsed Retry-After through the retry loop so
+// the execute paths can stop retrying without treating it as a failure.
+type rateLimitError struct {
+	after time.Duration
+}
+
+func (e *rateLimitError) Error() string { return ErrRateLimited.Error() }
+
+// rateLimitResponse classifies a non-matching status: 429 is always rate
+// limiting (with or without Retry-After), 503 only when the server sent a
+// parseable Retry-After — a plain 503 stays an ordinary failed check.
+func rateLimitResponse(status int, retryAfter string) *rateLimitError {
+	switch status {
+	case http.StatusTooManyRequests:
+		return &rateLimitError{after: parseRetryAfter(retryAfter)}
+	case http.StatusServiceUnavailable:
+		if after := parseRetryAfter(retryAfter); after > 0 {
+			return &rateLimitError{after: after}
+		}
+	}
+	return nil
+}
+
+// parseRetryAfter parses a Retry-After header value, either delta-seconds
+// or an HTTP-date. Absent or malformed values yield zero.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// stampRateLimit records the rate-limited outcome in the payload. Seconds
+// are rounded up so a sub-second Retry-After still delays the next check.
+func stampRateLimit(payload *Payload, rle *rateLimitError) {
+	payload.SetExtra("rate_limited", true)
+	if rle.after > 0 {
+		payload.SetExtra("retry_after_seconds", int((rle.after+time.Second-1)/time.Second))
+	}
+}
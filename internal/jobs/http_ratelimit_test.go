@@ -0,0 +1,143 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cpra/internal/loader/schema"
+)
+
+func rateLimitedServer(t *testing.T, status int, retryAfter string) (*httptest.Server, *int) {
+	t.Helper()
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestPulseHTTPJob_RateLimited429(t *testing.T) {
+	t.Parallel()
+
+	server, hits := rateLimitedServer(t, http.StatusTooManyRequests, "7")
+
+	host, isTLS, _ := ExtractHostFromURL(server.URL)
+	job := &PulseHTTPJob{
+		URL:     server.URL,
+		Method:  "GET",
+		Host:    host,
+		IsTLS:   isTLS,
+		Timeout: 5 * time.Second,
+		Retries: 2,
+	}
+
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrRateLimited) {
+		t.Fatalf("Err = %v, want ErrRateLimited", result.Err)
+	}
+	if limited, _ := result.Payload.Extra("rate_limited").(bool); !limited {
+		t.Error("payload missing rate_limited=true")
+	}
+	if secs, _ := result.Payload.Extra("retry_after_seconds").(int); secs != 7 {
+		t.Errorf("retry_after_seconds = %v, want 7", result.Payload.Extra("retry_after_seconds"))
+	}
+	// Retrying against a limiter digs the hole deeper; one attempt only.
+	if *hits != 1 {
+		t.Errorf("server hit %d times, want 1", *hits)
+	}
+}
+
+func TestPulseHTTPJob_RateLimited503(t *testing.T) {
+	t.Parallel()
+
+	// 503 with Retry-After is rate limiting; a plain 503 stays a failure.
+	server, _ := rateLimitedServer(t, http.StatusServiceUnavailable, "3")
+	host, isTLS, _ := ExtractHostFromURL(server.URL)
+	job := &PulseHTTPJob{URL: server.URL, Method: "GET", Host: host, IsTLS: isTLS, Timeout: 5 * time.Second}
+	if result := job.Execute(context.Background()); !errors.Is(result.Err, ErrRateLimited) {
+		t.Errorf("503 with Retry-After: Err = %v, want ErrRateLimited", result.Err)
+	}
+
+	plain, _ := rateLimitedServer(t, http.StatusServiceUnavailable, "")
+	host, isTLS, _ = ExtractHostFromURL(plain.URL)
+	job = &PulseHTTPJob{URL: plain.URL, Method: "GET", Host: host, IsTLS: isTLS, Timeout: 5 * time.Second}
+	if result := job.Execute(context.Background()); !errors.Is(result.Err, ErrHTTPCheckFailed) {
+		t.Errorf("plain 503: Err = %v, want ErrHTTPCheckFailed", result.Err)
+	}
+}
+
+func TestPulseHTTPJob_Expected429IsSuccess(t *testing.T) {
+	t.Parallel()
+
+	server, _ := rateLimitedServer(t, http.StatusTooManyRequests, "7")
+	host, isTLS, _ := ExtractHostFromURL(server.URL)
+	job := &PulseHTTPJob{
+		URL:            server.URL,
+		Method:         "GET",
+		Host:           host,
+		IsTLS:          isTLS,
+		Timeout:        5 * time.Second,
+		ExpectedStatus: schema.StatusRanges{{429, 429}},
+	}
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Errorf("explicitly expected 429: Err = %v, want nil", result.Err)
+	}
+}
+
+func TestPulseHTTPJob_RateLimitedDetailed(t *testing.T) {
+	t.Parallel()
+
+	server, hits := rateLimitedServer(t, http.StatusTooManyRequests, "2")
+	job := &PulseHTTPJob{
+		URL:            server.URL,
+		Method:         "GET",
+		Timeout:        5 * time.Second,
+		Retries:        2,
+		DetailedTiming: true,
+	}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrRateLimited) {
+		t.Fatalf("Err = %v, want ErrRateLimited", result.Err)
+	}
+	if secs, _ := result.Payload.Extra("retry_after_seconds").(int); secs != 2 {
+		t.Errorf("retry_after_seconds = %v, want 2", result.Payload.Extra("retry_after_seconds"))
+	}
+	if *hits != 1 {
+		t.Errorf("server hit %d times, want 1", *hits)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		min  time.Duration
+		max  time.Duration
+	}{
+		{"empty", "", 0, 0},
+		{"seconds", "10", 10 * time.Second, 10 * time.Second},
+		{"negative", "-5", 0, 0},
+		{"garbage", "soon", 0, 0},
+		{"http date", time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat), 25 * time.Second, 30 * time.Second},
+		{"past date", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseRetryAfter(tc.in)
+			if got < tc.min || got > tc.max {
+				t.Errorf("parseRetryAfter(%q) = %v, want in [%v, %v]", tc.in, got, tc.min, tc.max)
+			}
+		})
+	}
+}
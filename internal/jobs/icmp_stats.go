@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Per-host ICMP diagnostics. Every PulseICMPJob execution records its outcome
+// here, keyed by the configured host, so operators can tell a host that has
+// never answered from one that recently degraded without grepping logs.
+//
+// The registry would otherwise grow without bound under host churn (reloads,
+// generated fleets), so a lazily started janitor evicts entries idle past a
+// configurable TTL. The controller stops the janitor through
+// StopICMPStatsJanitor at shutdown.
+
+// ICMPHostStats is a snapshot of one host's ICMP check history.
+type ICMPHostStats struct {
+	Host       string        `json:"host"`
+	Executions uint64        `json:"executions"`
+	Failures   uint64        `json:"failures"`
+	LastRTT    time.Duration `json:"last_rtt"`
+	LastUsed   time.Time     `json:"last_used"`
+}
+
+// DefaultICMPStatsIdleTTL is how long a host's stats survive without a new
+// execution before the janitor evicts them.
+const DefaultICMPStatsIdleTTL = 15 * time.Minute
+
+// icmpJanitorInterval is how often the janitor sweeps for idle entries.
+const icmpJanitorInterval = time.Minute
+
+// icmpStatsRegistry holds per-host entries and the janitor lifecycle. All
+// fields are guarded by mu; record volume is bounded by the ICMP worker
+// count, so a single mutex is not a contention point.
+type icmpStatsRegistry struct {
+	mu      sync.Mutex
+	hosts   map[string]*ICMPHostStats
+	ttl     time.Duration
+	started bool
+	stop    chan struct{}
+}
+
+var globalICMPStats = &icmpStatsRegistry{
+	hosts: make(map[string]*ICMPHostStats),
+	ttl:   DefaultICMPStatsIdleTTL,
+}
+
+// SetICMPStatsIdleTTL overrides how long idle host entries are retained.
+// Non-positive values are ignored.
+func SetICMPStatsIdleTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	globalICMPStats.mu.Lock()
+	globalICMPStats.ttl = ttl
+	globalICMPStats.mu.Unlock()
+}
+
+// GetICMPHostStats returns a snapshot of every tracked host, sorted by host
+// name for stable diagnostic output.
+func GetICMPHostStats() []ICMPHostStats {
+	globalICMPStats.mu.Lock()
+	snapshot := make([]ICMPHostStats, 0, len(globalICMPStats.hosts))
+	for _, entry := range globalICMPStats.hosts {
+		snapshot = append(snapshot, *entry)
+	}
+	globalICMPStats.mu.Unlock()
+
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Host < snapshot[j].Host })
+	return snapshot
+}
+
+// StopICMPStatsJanitor stops the eviction goroutine. Safe to call multiple
+// times and before any ICMP check ran; a later record restarts the janitor.
+func StopICMPStatsJanitor() {
+	globalICMPStats.mu.Lock()
+	defer globalICMPStats.mu.Unlock()
+	if globalICMPStats.started {
+		close(globalICMPStats.stop)
+		globalICMPStats.started = false
+	}
+}
+
+// recordICMPExecution folds one check outcome into the host's entry and
+// lazily starts the janitor on first use.
+func recordICMPExecution(host string, rtt time.Duration, failed bool) {
+	globalICMPStats.record(host, rtt, failed, time.Now())
+
+	globalICMPStats.mu.Lock()
+	if !globalICMPStats.started {
+		globalICMPStats.started = true
+		globalICMPStats.stop = make(chan struct{})
+		go globalICMPStats.runJanitor(globalICMPStats.stop)
+	}
+	globalICMPStats.mu.Unlock()
+}
+
+// record updates the host's entry at the given time. Split from
+// recordICMPExecution so tests can drive the clock.
+func (r *icmpStatsRegistry) record(host string, rtt time.Duration, failed bool, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.hosts[host]
+	if !ok {
+		entry = &ICMPHostStats{Host: host}
+		r.hosts[host] = entry
+	}
+	entry.Executions++
+	if failed {
+		entry.Failures++
+	}
+	if rtt > 0 {
+		entry.LastRTT = rtt
+	}
+	entry.LastUsed = now
+}
+
+// evictIdle drops entries whose last execution is older than the TTL and
+// returns how many were removed.
+func (r *icmpStatsRegistry) evictIdle(now time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var evicted int
+	for host, entry := range r.hosts {
+		if now.Sub(entry.LastUsed) > r.ttl {
+			delete(r.hosts, host)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// runJanitor sweeps idle entries until stop closes. The stop channel is
+// passed in rather than read from the struct so a stop/restart cycle cannot
+// leave two janitors running.
+func (r *icmpStatsRegistry) runJanitor(stop chan struct{}) {
+	ticker := time.NewTicker(icmpJanitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.evictIdle(time.Now())
+		}
+	}
+}
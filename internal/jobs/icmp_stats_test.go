@@ -0,0 +1,103 @@
+package jobs
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestICMPStatsEviction(t *testing.T) {
+	t.Parallel()
+
+	registry := &icmpStatsRegistry{
+		hosts: make(map[string]*ICMPHostStats),
+		ttl:   DefaultICMPStatsIdleTTL,
+	}
+
+	// A churned-through fleet: 1000 hosts checked once, long ago.
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		registry.record(fmt.Sprintf("gone-%04d.example", i), time.Millisecond, false, start)
+	}
+	// One host still being checked after the others went idle.
+	registry.record("alive.example", 2*time.Millisecond, false, start.Add(DefaultICMPStatsIdleTTL))
+
+	evicted := registry.evictIdle(start.Add(DefaultICMPStatsIdleTTL + time.Minute))
+	if evicted != 1000 {
+		t.Errorf("evicted %d entries, want 1000", evicted)
+	}
+	if len(registry.hosts) != 1 {
+		t.Fatalf("registry holds %d entries after eviction, want 1", len(registry.hosts))
+	}
+	if _, ok := registry.hosts["alive.example"]; !ok {
+		t.Error("recently used host was evicted")
+	}
+}
+
+func TestICMPStatsConcurrentRecord(t *testing.T) {
+	t.Parallel()
+
+	registry := &icmpStatsRegistry{
+		hosts: make(map[string]*ICMPHostStats),
+		ttl:   DefaultICMPStatsIdleTTL,
+	}
+
+	// Concurrent checks of the same host must fold into one entry.
+	const workers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			registry.record("shared.example", time.Millisecond, i%2 == 0, time.Now())
+		}(i)
+	}
+	wg.Wait()
+
+	if len(registry.hosts) != 1 {
+		t.Fatalf("registry holds %d entries, want 1", len(registry.hosts))
+	}
+	entry := registry.hosts["shared.example"]
+	if entry.Executions != workers {
+		t.Errorf("Executions = %d, want %d", entry.Executions, workers)
+	}
+	if entry.Failures != workers/2 {
+		t.Errorf("Failures = %d, want %d", entry.Failures, workers/2)
+	}
+	if entry.LastRTT != time.Millisecond {
+		t.Errorf("LastRTT = %v, want 1ms", entry.LastRTT)
+	}
+}
+
+func TestICMPStatsJanitorStop(t *testing.T) {
+	// Uses the global registry; not parallel so other tests cannot race the
+	// janitor lifecycle.
+	recordICMPExecution("janitor.example", time.Millisecond, false)
+	globalICMPStats.mu.Lock()
+	started := globalICMPStats.started
+	globalICMPStats.mu.Unlock()
+	if !started {
+		t.Fatal("janitor not started by first record")
+	}
+
+	StopICMPStatsJanitor()
+	StopICMPStatsJanitor() // idempotent
+
+	globalICMPStats.mu.Lock()
+	started = globalICMPStats.started
+	globalICMPStats.mu.Unlock()
+	if started {
+		t.Error("janitor still marked started after stop")
+	}
+
+	// A later record restarts it.
+	recordICMPExecution("janitor.example", time.Millisecond, false)
+	globalICMPStats.mu.Lock()
+	started = globalICMPStats.started
+	globalICMPStats.mu.Unlock()
+	if !started {
+		t.Error("janitor not restarted by record after stop")
+	}
+	StopICMPStatsJanitor()
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// DryRunJob wraps an intervention job and reports what it would have done
+// without doing it. Execute never touches the wrapped driver — no docker
+// client, no exec, no webhook call — so dry-run monitors can be trusted
+// against production targets. The result succeeds and its payload carries
+// the would-be action and target so the result system and audit trail can
+// mark it distinctly from a real remediation.
+type DryRunJob struct {
+	Job         Job    // the real intervention; never executed
+	Action      string // would-be action ("docker", "exec", "webhook")
+	Target      string // human-readable would-be target
+	Entity      ecs.Entity
+	EnqueueTime time.Time
+	StartTime   time.Time
+}
+
+// Execute reports the skipped action without running it.
+func (j *DryRunJob) Execute(_ context.Context) Result {
+	payload := Payload{Kind: KindIntervention, Driver: j.Action}
+	payload.SetExtra("dry_run", true)
+	payload.SetExtra("would_be_action", j.Action)
+	payload.SetExtra("would_be_target", j.Target)
+	return Result{Ent: j.Entity, Payload: payload}
+}
+
+// Copy returns a copy wrapping a copy of the inner job.
+func (j *DryRunJob) Copy() Job {
+	job := *j
+	job.Job = j.Job.Copy()
+	return &job
+}
+
+// GetEnqueueTime returns when the job was enqueued.
+func (j *DryRunJob) GetEnqueueTime() time.Time { return j.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (j *DryRunJob) SetEnqueueTime(t time.Time) { j.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (j *DryRunJob) GetStartTime() time.Time { return j.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (j *DryRunJob) SetStartTime(t time.Time) { j.StartTime = t }
+
+// IsNil returns true if the wrapper or the wrapped job is nil.
+func (j *DryRunJob) IsNil() bool { return j == nil || j.Job == nil || j.Job.IsNil() }
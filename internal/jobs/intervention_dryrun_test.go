@@ -0,0 +1,121 @@
+//go:build !nodocker
+
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// TestDryRunIntervention_NeverCallsDocker points a dry-run docker intervention
+// at a fake daemon and asserts the daemon is never contacted, while the same
+// intervention without dry_run restarts the container (proving the fake would
+// have counted the call).
+func TestDryRunIntervention_NeverCallsDocker(t *testing.T) {
+	daemon := newFakeDockerDaemon(t)
+	defer daemon.stop()
+	host := "tcp://" + daemon.addr
+	defer func() {
+		if m, ok := dockerDaemonMonitors.Load(host); ok {
+			m.(*dockerDaemonMonitor).stopMonitor()
+		}
+	}()
+
+	config := schema.Intervention{
+		Action:  "docker",
+		DryRun:  true,
+		Retries: 2,
+		Target: &schema.InterventionTargetDocker{
+			Type:       "restart",
+			Container:  "payments-api",
+			DockerHost: host,
+			Timeout:    5 * time.Second,
+		},
+	}
+
+	job, err := CreateInterventionJob(config, ecs.Entity{})
+	if err != nil {
+		t.Fatalf("CreateInterventionJob failed: %v", err)
+	}
+	dryJob, ok := job.(*DryRunJob)
+	if !ok {
+		t.Fatalf("job is %T, want *DryRunJob", job)
+	}
+	if _, ok := dryJob.Job.(*InterventionDockerJob); !ok {
+		t.Fatalf("wrapped job is %T, want *InterventionDockerJob", dryJob.Job)
+	}
+
+	res := job.Execute(context.Background())
+	if res.Err != nil {
+		t.Fatalf("dry run failed: %v", res.Err)
+	}
+	if got := res.Payload.Extra("dry_run"); got != true {
+		t.Errorf("payload dry_run = %v, want true", got)
+	}
+	if got := res.Payload.Extra("would_be_action"); got != "docker" {
+		t.Errorf("payload would_be_action = %v, want docker", got)
+	}
+	if got := res.Payload.Extra("would_be_target"); got != "docker restart payments-api" {
+		t.Errorf("payload would_be_target = %v, want %q", got, "docker restart payments-api")
+	}
+	if n := daemon.restarts.Load(); n != 0 {
+		t.Fatalf("dry run performed %d restarts, want 0", n)
+	}
+
+	// Control: the same intervention without dry_run really restarts.
+	config.DryRun = false
+	realJob, err := CreateInterventionJob(config, ecs.Entity{})
+	if err != nil {
+		t.Fatalf("CreateInterventionJob failed: %v", err)
+	}
+	if res := realJob.Execute(context.Background()); res.Err != nil {
+		t.Fatalf("real intervention failed: %v", res.Err)
+	}
+	if n := daemon.restarts.Load(); n != 1 {
+		t.Fatalf("real intervention performed %d restarts, want 1", n)
+	}
+}
+
+// TestCreateInterventionStepJob_DryRun verifies dry-run chain steps keep the
+// step index stamping and describe exec targets by their command line.
+func TestCreateInterventionStepJob_DryRun(t *testing.T) {
+	t.Parallel()
+	step := schema.InterventionStep{
+		Action:        "exec",
+		Target:        &schema.InterventionTargetExec{Command: "systemctl", Args: []string{"restart", "web.service"}},
+		AfterFailures: 2,
+		DryRun:        true,
+	}
+	job, err := CreateInterventionStepJob(step, 1, ecs.Entity{})
+	if err != nil {
+		t.Fatalf("CreateInterventionStepJob failed: %v", err)
+	}
+	res := job.Execute(context.Background())
+	if res.Err != nil {
+		t.Fatalf("Execute failed: %v", res.Err)
+	}
+	if got := res.Payload.Extra("step"); got != 1 {
+		t.Errorf("payload step = %v, want 1", got)
+	}
+	if got := res.Payload.Extra("dry_run"); got != true {
+		t.Errorf("payload dry_run = %v, want true", got)
+	}
+	if got := res.Payload.Extra("would_be_target"); got != "systemctl restart web.service" {
+		t.Errorf("payload would_be_target = %v, want the command line", got)
+	}
+}
+
+// TestCreateInterventionJob_DryRunBadTargetStillFails verifies dry-run does
+// not weaken load-time validation: the real job is still built first.
+func TestCreateInterventionJob_DryRunBadTargetStillFails(t *testing.T) {
+	t.Parallel()
+	config := schema.Intervention{Action: "docker", DryRun: true}
+	if _, err := CreateInterventionJob(config, ecs.Entity{}); err == nil {
+		t.Fatal("expected error for dry-run intervention with no target")
+	}
+}
@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// execDefaultTimeout bounds one command attempt when no timeout is configured.
+const execDefaultTimeout = 30 * time.Second
+
+// execOutputCap is how many trailing bytes of stdout and stderr are kept in
+// the result payload.
+const execOutputCap = 4096
+
+// tailBuffer is an io.Writer that keeps only the last limit bytes written,
+// so a chatty command cannot balloon the result payload.
+type tailBuffer struct {
+	limit int
+	buf   []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if n >= t.limit {
+		t.buf = append(t.buf[:0], p[n-t.limit:]...)
+		return n, nil
+	}
+	if over := len(t.buf) + n - t.limit; over > 0 {
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+	}
+	t.buf = append(t.buf, p...)
+	return n, nil
+}
+
+func (t *tailBuffer) String() string { return string(t.buf) }
+
+// InterventionExecJob runs a local command (e.g. systemctl restart) as an
+// automated intervention. The command is executed directly via exec, never
+// through a shell, so configured arguments cannot be injected.
+//
+// Safety features:
+//   - Per-attempt timeout via exec.CommandContext (the process is killed)
+//   - Bounded stdout/stderr capture (last execOutputCap bytes each)
+//   - Exit codes outside ExpectedExitCodes count as failure
+type InterventionExecJob struct {
+	EnqueueTime       time.Time
+	StartTime         time.Time
+	Command           string
+	WorkingDir        string
+	Args              []string
+	Env               map[string]string
+	ExpectedExitCodes []int
+	Timeout           time.Duration
+	Retries           int
+	Entity            ecs.Entity
+}
+
+// exitCodeAllowed reports whether code counts as success.
+func (j *InterventionExecJob) exitCodeAllowed(code int) bool {
+	if len(j.ExpectedExitCodes) == 0 {
+		return code == 0
+	}
+	for _, allowed := range j.ExpectedExitCodes {
+		if code == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Execute runs the command with retries.
+func (j *InterventionExecJob) Execute(ctx context.Context) Result {
+	payload := Payload{Kind: KindIntervention, Driver: "exec"}
+
+	if j.Command == "" {
+		return Result{Ent: j.Entity, Err: ErrExecMissingCommand, Payload: payload}
+	}
+
+	timeout := j.Timeout
+	if timeout <= 0 {
+		timeout = execDefaultTimeout
+	}
+
+	var exitCode int
+	var stdout, stderr string
+	err := RetryWithBackoff(ctx, j.Retries+1, 50*time.Millisecond, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		var attemptErr error
+		exitCode, stdout, stderr, attemptErr = j.runOnce(attemptCtx)
+		return attemptErr
+	})
+
+	payload.SetExtra("exit_code", exitCode)
+	payload.SetExtra("stdout", stdout)
+	payload.SetExtra("stderr", stderr)
+
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: j.Entity, Err: err, Payload: payload}
+		}
+		return Result{Ent: j.Entity, Err: ErrExecActionFailed, Payload: payload}
+	}
+	return Result{Ent: j.Entity, Err: nil, Payload: payload}
+}
+
+// runOnce performs one command attempt and returns the exit code and the
+// captured output tails. A disallowed exit code is returned as an error so
+// the retry loop treats it as a failed attempt.
+func (j *InterventionExecJob) runOnce(ctx context.Context) (int, string, string, error) {
+	cmd := exec.CommandContext(ctx, j.Command, j.Args...)
+	cmd.Dir = j.WorkingDir
+	if len(j.Env) > 0 {
+		env := os.Environ()
+		for k, v := range j.Env {
+			env = append(env, k+"="+v)
+		}
+		cmd.Env = env
+	}
+	stdout := &tailBuffer{limit: execOutputCap}
+	stderr := &tailBuffer{limit: execOutputCap}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return -1, stdout.String(), stderr.String(), ErrExecTimeout
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code := exitErr.ExitCode()
+			if j.exitCodeAllowed(code) {
+				return code, stdout.String(), stderr.String(), nil
+			}
+			return code, stdout.String(), stderr.String(), ErrExecUnexpectedExitCode
+		}
+		return -1, stdout.String(), stderr.String(), err
+	}
+	if !j.exitCodeAllowed(0) {
+		return 0, stdout.String(), stderr.String(), ErrExecUnexpectedExitCode
+	}
+	return 0, stdout.String(), stderr.String(), nil
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (j *InterventionExecJob) Copy() Job { job := *j; return &job }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (j *InterventionExecJob) GetEnqueueTime() time.Time { return j.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (j *InterventionExecJob) SetEnqueueTime(t time.Time) { j.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (j *InterventionExecJob) GetStartTime() time.Time { return j.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (j *InterventionExecJob) SetStartTime(t time.Time) { j.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (j *InterventionExecJob) IsNil() bool { return j == nil }
@@ -0,0 +1,151 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// TestInterventionExecJob_Success tests a successful command run and the
+// captured output in the payload.
+func TestInterventionExecJob_Success(t *testing.T) {
+	t.Parallel()
+	job := &InterventionExecJob{
+		Command: "echo",
+		Args:    []string{"service", "restarted"},
+		Timeout: 5 * time.Second,
+	}
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	if result.Payload.Extra("exit_code") != 0 {
+		t.Errorf("exit_code = %v, want 0", result.Payload.Extra("exit_code"))
+	}
+	if got, _ := result.Payload.Extra("stdout").(string); !strings.Contains(got, "service restarted") {
+		t.Errorf("stdout = %q, want command output", got)
+	}
+}
+
+// TestInterventionExecJob_NonZeroExit tests that a disallowed exit code
+// fails and an allowed one succeeds.
+func TestInterventionExecJob_NonZeroExit(t *testing.T) {
+	t.Parallel()
+	job := &InterventionExecJob{
+		Command: "sh",
+		Args:    []string{"-c", "echo broken >&2; exit 3"},
+		Timeout: 5 * time.Second,
+	}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrExecActionFailed) {
+		t.Fatalf("Execute error = %v, want ErrExecActionFailed", result.Err)
+	}
+	if result.Payload.Extra("exit_code") != 3 {
+		t.Errorf("exit_code = %v, want 3", result.Payload.Extra("exit_code"))
+	}
+	if got, _ := result.Payload.Extra("stderr").(string); !strings.Contains(got, "broken") {
+		t.Errorf("stderr = %q, want command output", got)
+	}
+
+	// The same exit code is a success when listed in ExpectedExitCodes.
+	job = &InterventionExecJob{
+		Command:           "sh",
+		Args:              []string{"-c", "exit 3"},
+		ExpectedExitCodes: []int{0, 3},
+		Timeout:           5 * time.Second,
+	}
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Errorf("Execute with allowed exit code failed: %v", result.Err)
+	}
+}
+
+// TestInterventionExecJob_TimeoutKillsCommand tests that a hung command is
+// killed at the attempt timeout.
+func TestInterventionExecJob_TimeoutKillsCommand(t *testing.T) {
+	t.Parallel()
+	job := &InterventionExecJob{
+		Command: "sleep",
+		Args:    []string{"30"},
+		Timeout: 150 * time.Millisecond,
+	}
+	start := time.Now()
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrExecActionFailed) {
+		t.Fatalf("Execute error = %v, want ErrExecActionFailed", result.Err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Execute took %v, command was not killed at the timeout", elapsed)
+	}
+}
+
+// TestInterventionExecJob_MissingCommand tests the config sentinel.
+func TestInterventionExecJob_MissingCommand(t *testing.T) {
+	t.Parallel()
+	job := &InterventionExecJob{}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrExecMissingCommand) {
+		t.Errorf("Execute error = %v, want ErrExecMissingCommand", result.Err)
+	}
+}
+
+// TestCreateInterventionJob_Exec tests the factory wiring for exec targets.
+func TestCreateInterventionJob_Exec(t *testing.T) {
+	t.Parallel()
+	intervention := schema.Intervention{
+		Action:  "exec",
+		Retries: 2,
+		Target: &schema.InterventionTargetExec{
+			Command:           "systemctl",
+			Args:              []string{"restart", "web.service"},
+			WorkingDir:        "/tmp",
+			Env:               map[string]string{"SYSTEMD_COLORS": "0"},
+			Timeout:           10 * time.Second,
+			ExpectedExitCodes: []int{0},
+		},
+	}
+	job, err := CreateInterventionJob(intervention, ecs.Entity{})
+	if err != nil {
+		t.Fatalf("CreateInterventionJob failed: %v", err)
+	}
+	execJob, ok := job.(*InterventionExecJob)
+	if !ok {
+		t.Fatalf("expected *InterventionExecJob, got %T", job)
+	}
+	if execJob.Command != "systemctl" || len(execJob.Args) != 2 || execJob.Retries != 2 {
+		t.Errorf("config not threaded: %+v", execJob)
+	}
+	if execJob.WorkingDir != "/tmp" || execJob.Env["SYSTEMD_COLORS"] != "0" {
+		t.Errorf("environment not threaded: %+v", execJob)
+	}
+	ReleaseInterventionJob(job)
+
+	// A docker target under action exec is rejected.
+	intervention.Target = &schema.InterventionTargetDocker{Container: "web"}
+	if _, err := CreateInterventionJob(intervention, ecs.Entity{}); !errors.Is(err, ErrExecMissingTarget) {
+		t.Errorf("CreateInterventionJob error = %v, want ErrExecMissingTarget", err)
+	}
+}
+
+// TestTailBuffer tests that only the trailing bytes are kept.
+func TestTailBuffer(t *testing.T) {
+	t.Parallel()
+	buf := &tailBuffer{limit: 8}
+	for _, chunk := range []string{"aaaa", "bbbb", "cccc"} {
+		if _, err := buf.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if got := buf.String(); got != "bbbbcccc" {
+		t.Errorf("String() = %q, want %q", got, "bbbbcccc")
+	}
+	buf.Write([]byte("0123456789abcdef"))
+	if got := buf.String(); got != "89abcdef" {
+		t.Errorf("String() after oversized write = %q, want %q", got, "89abcdef")
+	}
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// InterventionStepJob wraps one step of an intervention escalation chain.
+// Execute runs the wrapped job and stamps the step index into the result
+// payload so the result system can track which escalation level ran.
+type InterventionStepJob struct {
+	Job  Job
+	Step int
+}
+
+// Execute runs the wrapped job and adds the step index to the payload.
+func (j *InterventionStepJob) Execute(ctx context.Context) Result {
+	result := j.Job.Execute(ctx)
+	result.Payload.SetExtra("step", j.Step)
+	return result
+}
+
+// Copy returns a copy wrapping a copy of the inner job.
+func (j *InterventionStepJob) Copy() Job {
+	return &InterventionStepJob{Job: j.Job.Copy(), Step: j.Step}
+}
+
+// GetEnqueueTime returns when the job was enqueued.
+func (j *InterventionStepJob) GetEnqueueTime() time.Time { return j.Job.GetEnqueueTime() }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (j *InterventionStepJob) SetEnqueueTime(t time.Time) { j.Job.SetEnqueueTime(t) }
+
+// GetStartTime returns when the job started executing.
+func (j *InterventionStepJob) GetStartTime() time.Time { return j.Job.GetStartTime() }
+
+// SetStartTime sets when the job started executing.
+func (j *InterventionStepJob) SetStartTime(t time.Time) { j.Job.SetStartTime(t) }
+
+// IsNil returns true if the wrapper or the wrapped job is nil.
+func (j *InterventionStepJob) IsNil() bool { return j == nil || j.Job == nil || j.Job.IsNil() }
@@ -0,0 +1,198 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// interventionWebhookExcerptCap is how many leading bytes of the response
+// body are kept in the result payload.
+const interventionWebhookExcerptCap = 1024
+
+// webhookInterventionData is the data rendered into the body template and
+// the default JSON body. Monitor and FailureCount are stamped by
+// BatchInterventionSystem at dispatch time.
+type webhookInterventionData struct {
+	Monitor      string `json:"monitor"`
+	FailureCount int    `json:"failure_count"`
+}
+
+// InterventionWebhookJob drives remediation through an external HTTP
+// endpoint, such as a runbook automation service. The body is either the
+// rendered template or a default JSON document, optionally signed with
+// HMAC-SHA256. Responses outside ExpectedStatus count as failure, and the
+// status plus a truncated body excerpt land in the result payload so
+// BatchInterventionResultSystem can log what the endpoint said.
+type InterventionWebhookJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Monitor     string
+	URL         string
+	Method      string
+	Headers     map[string]string
+	Secret      string
+	// Template is parsed at job creation so bad templates fail the load,
+	// not the first intervention. Nil sends the default JSON payload.
+	Template       *template.Template
+	ExpectedStatus []int
+	FailureCount   int
+	Timeout        time.Duration
+	Retries        int
+	Entity         ecs.Entity
+}
+
+// statusAllowed reports whether status counts as success.
+func (j *InterventionWebhookJob) statusAllowed(status int) bool {
+	if len(j.ExpectedStatus) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, allowed := range j.ExpectedStatus {
+		if status == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// buildBody renders the request body from the template or the default JSON.
+func (j *InterventionWebhookJob) buildBody() ([]byte, error) {
+	data := webhookInterventionData{
+		Monitor:      j.Monitor,
+		FailureCount: j.FailureCount,
+	}
+	if j.Template != nil {
+		var buf bytes.Buffer
+		if err := j.Template.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return json.Marshal(data)
+}
+
+// Execute calls the remediation endpoint with retries.
+func (j *InterventionWebhookJob) Execute(ctx context.Context) Result {
+	payload := Payload{Kind: KindIntervention, Driver: "webhook"}
+
+	if j.URL == "" {
+		return Result{Ent: j.Entity, Err: ErrWebhookMissingURL, Payload: payload}
+	}
+	body, err := j.buildBody()
+	if err != nil {
+		return Result{Ent: j.Entity, Err: err, Payload: payload}
+	}
+
+	timeout := j.Timeout
+	if timeout <= 0 {
+		timeout = webhookDefaultTimeout
+	}
+	client, err := GetPooledHTTPClient(timeout, false, "")
+	if err != nil {
+		return Result{Ent: j.Entity, Err: err, Payload: payload}
+	}
+
+	// Acquire global dial slot to prevent CPU spikes during network outages.
+	if !AcquireHTTPDialSlot(ctx) {
+		return Result{Ent: j.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer ReleaseHTTPDialSlot()
+
+	var lastStatus int
+	var lastExcerpt string
+	err = RetryWithBackoff(ctx, j.Retries+1, 50*time.Millisecond, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		status, excerpt, attemptErr := j.send(attemptCtx, client, body)
+		if status > 0 {
+			lastStatus = status
+			lastExcerpt = excerpt
+		}
+		if attemptErr != nil {
+			// An attempt timeout is retryable; only the parent context's
+			// deadline or cancellation aborts the job.
+			if attemptErr == context.DeadlineExceeded && ctx.Err() == nil {
+				return ErrWebhookTimeout
+			}
+			return attemptErr
+		}
+		if !j.statusAllowed(status) {
+			return ErrWebhookUnexpectedStatus
+		}
+		return nil
+	})
+
+	if lastStatus > 0 {
+		payload.SetExtra("http_status", lastStatus)
+		payload.SetExtra("response_excerpt", lastExcerpt)
+	}
+
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: j.Entity, Err: err, Payload: payload}
+		}
+		return Result{Ent: j.Entity, Err: ErrWebhookActionFailed, Payload: payload, Class: InfraErrorClass(err)}
+	}
+	return Result{Ent: j.Entity, Err: nil, Payload: payload}
+}
+
+// send performs one delivery attempt and returns the HTTP status and a
+// truncated response body excerpt.
+func (j *InterventionWebhookJob) send(ctx context.Context, client *http.Client, body []byte) (int, string, error) {
+	method := j.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, j.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", ErrFailedToCreateHTTPRequest
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range j.Headers {
+		req.Header.Set(name, value)
+	}
+	if j.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(j.Secret))
+		mac.Write(body)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, "", ctx.Err()
+		}
+		return 0, "", err
+	}
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, interventionWebhookExcerptCap))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return resp.StatusCode, string(excerpt), nil
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (j *InterventionWebhookJob) Copy() Job { job := *j; return &job }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (j *InterventionWebhookJob) GetEnqueueTime() time.Time { return j.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (j *InterventionWebhookJob) SetEnqueueTime(t time.Time) { j.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (j *InterventionWebhookJob) GetStartTime() time.Time { return j.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (j *InterventionWebhookJob) SetStartTime(t time.Time) { j.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (j *InterventionWebhookJob) IsNil() bool { return j == nil }
@@ -0,0 +1,200 @@
+package jobs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// newWebhookInterventionServer starts a server capturing request bodies and
+// signature headers, responding with the given status.
+func newWebhookInterventionServer(t *testing.T, status int, respBody string) (*httptest.Server, *[]string, *[]string) {
+	t.Helper()
+	var bodies []string
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		signatures = append(signatures, r.Header.Get(WebhookSignatureHeader))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(respBody))
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies, &signatures
+}
+
+// TestInterventionWebhookJob_RendersFailureCount tests that the body template
+// receives the monitor name and the failure count set at dispatch time.
+func TestInterventionWebhookJob_RendersFailureCount(t *testing.T) {
+	server, bodies, _ := newWebhookInterventionServer(t, http.StatusOK, `{"runbook":"started"}`)
+
+	tmpl := texttemplate.Must(texttemplate.New("intervention").Parse(
+		`{"monitor":"{{.Monitor}}","failures":{{.FailureCount}}}`))
+	job := &InterventionWebhookJob{
+		Monitor:      "payments-api",
+		URL:          server.URL,
+		Template:     tmpl,
+		FailureCount: 4,
+		Timeout:      5 * time.Second,
+	}
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	if len(*bodies) != 1 || (*bodies)[0] != `{"monitor":"payments-api","failures":4}` {
+		t.Errorf("body = %v, want rendered template with failure count", *bodies)
+	}
+
+	// A later dispatch with a higher count renders the current value.
+	job.FailureCount = 7
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	if got := (*bodies)[1]; !strings.Contains(got, `"failures":7`) {
+		t.Errorf("body = %q, want failure count at time of dispatch", got)
+	}
+}
+
+// TestInterventionWebhookJob_DefaultBody tests the JSON body sent without a
+// template.
+func TestInterventionWebhookJob_DefaultBody(t *testing.T) {
+	server, bodies, _ := newWebhookInterventionServer(t, http.StatusOK, "")
+
+	job := &InterventionWebhookJob{
+		Monitor:      "payments-api",
+		URL:          server.URL,
+		FailureCount: 3,
+		Timeout:      5 * time.Second,
+	}
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	got := (*bodies)[0]
+	if !strings.Contains(got, `"monitor":"payments-api"`) || !strings.Contains(got, `"failure_count":3`) {
+		t.Errorf("body = %q, want default JSON with monitor and failure count", got)
+	}
+}
+
+// TestInterventionWebhookJob_SignsBody tests the HMAC signature header.
+func TestInterventionWebhookJob_SignsBody(t *testing.T) {
+	server, bodies, signatures := newWebhookInterventionServer(t, http.StatusOK, "")
+
+	job := &InterventionWebhookJob{
+		Monitor: "payments-api",
+		URL:     server.URL,
+		Secret:  "hunter2",
+		Timeout: 5 * time.Second,
+	}
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	mac := hmac.New(sha256.New, []byte("hunter2"))
+	mac.Write([]byte((*bodies)[0]))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if (*signatures)[0] != want {
+		t.Errorf("signature = %q, want %q", (*signatures)[0], want)
+	}
+}
+
+// TestInterventionWebhookJob_UnexpectedStatus tests that a response outside
+// ExpectedStatus fails and the payload carries the status and excerpt.
+func TestInterventionWebhookJob_UnexpectedStatus(t *testing.T) {
+	server, _, _ := newWebhookInterventionServer(t, http.StatusBadGateway, "runbook engine down")
+
+	job := &InterventionWebhookJob{
+		Monitor: "payments-api",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+	}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrWebhookActionFailed) {
+		t.Fatalf("Execute error = %v, want ErrWebhookActionFailed", result.Err)
+	}
+	if result.Payload.Extra("http_status") != http.StatusBadGateway {
+		t.Errorf("http_status = %v, want 502", result.Payload.Extra("http_status"))
+	}
+	if got, _ := result.Payload.Extra("response_excerpt").(string); !strings.Contains(got, "runbook engine down") {
+		t.Errorf("response_excerpt = %q, want endpoint response", got)
+	}
+
+	// The same status succeeds when listed in ExpectedStatus.
+	job.ExpectedStatus = []int{http.StatusBadGateway}
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Errorf("Execute with allowed status failed: %v", result.Err)
+	}
+}
+
+// TestInterventionWebhookJob_MissingURL tests the config sentinel.
+func TestInterventionWebhookJob_MissingURL(t *testing.T) {
+	t.Parallel()
+	job := &InterventionWebhookJob{}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrWebhookMissingURL) {
+		t.Errorf("Execute error = %v, want ErrWebhookMissingURL", result.Err)
+	}
+}
+
+// TestCreateInterventionJob_Webhook tests the factory wiring for webhook
+// targets, including the environment secret and template parsing.
+func TestCreateInterventionJob_Webhook(t *testing.T) {
+	t.Setenv("CPRA_TEST_RUNBOOK_SECRET", "s3cret")
+	intervention := schema.Intervention{
+		Action:  "webhook",
+		Retries: 2,
+		Target: &schema.InterventionTargetWebhook{
+			URL:            "https://runbook.internal/remediate",
+			Method:         "PUT",
+			Headers:        map[string]string{"X-Team": "sre"},
+			BodyTemplate:   `{"monitor":"{{.Monitor}}"}`,
+			ExpectedStatus: []int{202},
+			SecretEnv:      "CPRA_TEST_RUNBOOK_SECRET",
+			Timeout:        10 * time.Second,
+		},
+	}
+	job, err := CreateInterventionJob(intervention, ecs.Entity{})
+	if err != nil {
+		t.Fatalf("CreateInterventionJob failed: %v", err)
+	}
+	whJob, ok := job.(*InterventionWebhookJob)
+	if !ok {
+		t.Fatalf("expected *InterventionWebhookJob, got %T", job)
+	}
+	if whJob.URL != "https://runbook.internal/remediate" || whJob.Method != "PUT" || whJob.Retries != 2 {
+		t.Errorf("config not threaded: %+v", whJob)
+	}
+	if whJob.Secret != "s3cret" {
+		t.Errorf("Secret = %q, want value from environment", whJob.Secret)
+	}
+	if whJob.Template == nil {
+		t.Error("Template = nil, want parsed body template")
+	}
+	ReleaseInterventionJob(job)
+
+	// A bad template fails at creation, not on the first intervention.
+	intervention.Target = &schema.InterventionTargetWebhook{
+		URL:          "https://runbook.internal/remediate",
+		BodyTemplate: "{{.Broken",
+	}
+	if _, err := CreateInterventionJob(intervention, ecs.Entity{}); err == nil {
+		t.Error("expected error for invalid body_template")
+	}
+
+	// A docker target under action webhook is rejected.
+	intervention.Target = &schema.InterventionTargetDocker{Container: "web"}
+	if _, err := CreateInterventionJob(intervention, ecs.Entity{}); !errors.Is(err, ErrWebhookMissingTarget) {
+		t.Errorf("CreateInterventionJob error = %v, want ErrWebhookMissingTarget", err)
+	}
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import "cpra/internal/interning"
+
+// LabeledJob is implemented by notification jobs that carry the monitor's
+// labels into their output. The code job factories do not know about labels
+// — they live on the entity's metadata — so dispatch sites attach them
+// after construction with ApplyLabels.
+type LabeledJob interface {
+	SetLabels(interning.Labels)
+}
+
+// ApplyLabels attaches the monitor's labels to the job when it supports
+// them; jobs without label support are left untouched. Wrapper jobs
+// propagate to the jobs they carry. The label set is shared and must not
+// be mutated.
+func ApplyLabels(job Job, labels interning.Labels) {
+	if len(labels) == 0 || job == nil || job.IsNil() {
+		return
+	}
+	if lj, ok := job.(LabeledJob); ok {
+		lj.SetLabels(labels)
+	}
+}
+
+// SetLabels implementations for the notification job types. Kept together
+// like the entity accessors in entity.go.
+
+func (c *CodeLogJob) SetLabels(l interning.Labels)       { c.Labels = l }
+func (c *CodeSlackJob) SetLabels(l interning.Labels)     { c.Labels = l }
+func (c *CodePagerDutyJob) SetLabels(l interning.Labels) { c.Labels = l }
+func (c *CodeEmailJob) SetLabels(l interning.Labels)     { c.Labels = l }
+func (c *CodeWebhookJob) SetLabels(l interning.Labels)   { c.Labels = l }
+
+// The wrappers propagate to the jobs they carry.
+
+func (j *CodeFanoutJob) SetLabels(l interning.Labels) {
+	for _, inner := range j.Jobs {
+		ApplyLabels(inner, l)
+	}
+}
+
+func (j *AnnotatedJob) SetLabels(l interning.Labels) {
+	ApplyLabels(j.Job, l)
+}
@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/interning"
+)
+
+func TestApplyLabels(t *testing.T) {
+	t.Parallel()
+	labels := interning.InternLabels(map[string]string{"team": "payments"})
+
+	t.Run("SetsOnLabeledJob", func(t *testing.T) {
+		job := &CodeLogJob{Monitor: "m"}
+		ApplyLabels(job, labels)
+		if v, ok := job.Labels.Get("team"); !ok || v != "payments" {
+			t.Errorf("Labels = %v, want team=payments", job.Labels)
+		}
+	})
+
+	t.Run("IgnoresUnlabeledJob", func(t *testing.T) {
+		// Pulse jobs have no label support; ApplyLabels must not panic.
+		ApplyLabels(&PulseHTTPJob{}, labels)
+	})
+
+	t.Run("IgnoresNilAndEmpty", func(t *testing.T) {
+		ApplyLabels(nil, labels)
+		ApplyLabels((*CodeLogJob)(nil), labels)
+		job := &CodeLogJob{}
+		ApplyLabels(job, nil)
+		if job.Labels != nil {
+			t.Error("empty label set should leave job untouched")
+		}
+	})
+
+	t.Run("FanoutPropagates", func(t *testing.T) {
+		logJob := &CodeLogJob{Monitor: "m"}
+		webhookJob := &CodeWebhookJob{Monitor: "m"}
+		fanout := &CodeFanoutJob{Jobs: []Job{logJob, webhookJob}}
+		ApplyLabels(fanout, labels)
+		if _, ok := logJob.Labels.Get("team"); !ok {
+			t.Error("fanout did not propagate labels to log job")
+		}
+		if _, ok := webhookJob.Labels.Get("team"); !ok {
+			t.Error("fanout did not propagate labels to webhook job")
+		}
+	})
+
+	t.Run("AnnotatedDelegates", func(t *testing.T) {
+		logJob := &CodeLogJob{Monitor: "m"}
+		wrapped := &AnnotatedJob{Job: logJob}
+		ApplyLabels(wrapped, labels)
+		if _, ok := logJob.Labels.Get("team"); !ok {
+			t.Error("annotated wrapper did not delegate labels")
+		}
+	})
+}
+
+// TestCodeLogJob_Execute_IncludesLabels verifies the written log entry and
+// its rendered message both carry the monitor's labels.
+func TestCodeLogJob_Execute_IncludesLabels(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "alerts.log")
+	job := &CodeLogJob{
+		Monitor:  "labeled-monitor",
+		Color:    "red",
+		Status:   "FAILED",
+		Severity: "critical",
+		Summary:  "Test alert",
+		File:     file,
+		Labels:   interning.InternLabels(map[string]string{"team": "payments", "tier": "1"}),
+	}
+
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+
+	// The log manager writes asynchronously; poll for the line.
+	var data []byte
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		data, _ = os.ReadFile(file)
+		if len(data) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(data) == 0 {
+		t.Fatal("log entry was not written")
+	}
+
+	var entry struct {
+		Monitor string            `json:"monitor"`
+		Labels  map[string]string `json:"labels"`
+		Message string            `json:"message"`
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("log entry is not valid JSON: %v", err)
+	}
+	if entry.Labels["team"] != "payments" || entry.Labels["tier"] != "1" {
+		t.Errorf("entry labels = %v, want team=payments tier=1", entry.Labels)
+	}
+	if !strings.Contains(entry.Message, "Labels: team=payments,tier=1") {
+		t.Errorf("message missing labels line: %q", entry.Message)
+	}
+}
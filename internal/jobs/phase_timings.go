@@ -0,0 +1,79 @@
+package jobs
+
+import "time"
+
+// PhaseTimings is the per-phase latency breakdown of one check, recorded when
+// a monitor sets detailed_timing. Durations are stored as whole milliseconds
+// in uint16 (clamped at ~65s) so a per-monitor history ring can keep many of
+// them cheaply.
+//
+// Connection reuse is represented honestly: a reused connection reports zero
+// dns/connect/tls with Reused set, rather than pretending the handshake was
+// free.
+type PhaseTimings struct {
+	DNSMs      uint16
+	ConnectMs  uint16
+	TLSMs      uint16
+	TTFBMs     uint16
+	TransferMs uint16
+	Reused     bool
+}
+
+// Phase returns the duration of the named phase in milliseconds.
+// Valid names: dns, connect, tls, ttfb, transfer.
+func (t PhaseTimings) Phase(name string) (uint16, bool) {
+	switch name {
+	case "dns":
+		return t.DNSMs, true
+	case "connect":
+		return t.ConnectMs, true
+	case "tls":
+		return t.TLSMs, true
+	case "ttfb":
+		return t.TTFBMs, true
+	case "transfer":
+		return t.TransferMs, true
+	default:
+		return 0, false
+	}
+}
+
+// durationToMs converts a duration to whole milliseconds, clamped to uint16.
+func durationToMs(d time.Duration) uint16 {
+	if d <= 0 {
+		return 0
+	}
+	ms := d.Milliseconds()
+	if ms > 65535 {
+		return 65535
+	}
+	return uint16(ms)
+}
+
+// markDegraded stamps degraded markers on a successful check whose selected
+// phase exceeded the threshold. An empty phase (or "total") compares the
+// whole-check latency; named phases require recorded timings.
+func markDegraded(payload *Payload, phase string, threshold, total time.Duration, timings *PhaseTimings) {
+	if threshold <= 0 {
+		return
+	}
+	measured := total
+	name := phase
+	switch phase {
+	case "", "total":
+		name = "total"
+	default:
+		if timings == nil {
+			return
+		}
+		ms, ok := timings.Phase(phase)
+		if !ok {
+			return
+		}
+		measured = time.Duration(ms) * time.Millisecond
+	}
+	if measured > threshold {
+		payload.SetExtra("degraded", true)
+		payload.SetExtra("degraded_phase", name)
+	}
+}
@@ -0,0 +1,564 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Top-level factory functions for sync.Pool - eliminates closure allocations at init
+func newPulseHTTPJob() any                 { return &PulseHTTPJob{} }
+func newPulseTCPJob() any                  { return &PulseTCPJob{} }
+func newPulseUDPJob() any                  { return &PulseUDPJob{} }
+func newPulseICMPJob() any                 { return &PulseICMPJob{} }
+func newPulseNTPJob() any                  { return &PulseNTPJob{} }
+func newPulseDNSJob() any                  { return &PulseDNSJob{} }
+func newPulseGRPCJob() any                 { return &PulseGRPCJob{} }
+func newPulseDockerJob() any               { return &PulseDockerJob{} }
+func newInterventionDockerJob() any        { return &InterventionDockerJob{} }
+func newInterventionDockerStopJob() any    { return &InterventionDockerStopJob{} }
+func newInterventionDockerStartJob() any   { return &InterventionDockerStartJob{} }
+func newInterventionDockerKillJob() any    { return &InterventionDockerKillJob{} }
+func newInterventionDockerPauseJob() any   { return &InterventionDockerPauseJob{} }
+func newInterventionDockerUnpauseJob() any { return &InterventionDockerUnpauseJob{} }
+func newInterventionDockerScaleJob() any   { return &InterventionDockerScaleJob{} }
+func newInterventionExecJob() any          { return &InterventionExecJob{} }
+func newInterventionWebhookJob() any       { return &InterventionWebhookJob{} }
+func newCodeLogJob() any                   { return &CodeLogJob{} }
+func newCodePagerDutyJob() any             { return &CodePagerDutyJob{} }
+func newCodeSlackJob() any                 { return &CodeSlackJob{} }
+func newCodeEmailJob() any                 { return &CodeEmailJob{} }
+func newCodeWebhookJob() any               { return &CodeWebhookJob{} }
+
+var (
+	pulseHTTPJobPool   = sync.Pool{New: newPulseHTTPJob}
+	pulseTCPJobPool    = sync.Pool{New: newPulseTCPJob}
+	pulseUDPJobPool    = sync.Pool{New: newPulseUDPJob}
+	pulseICMPJobPool   = sync.Pool{New: newPulseICMPJob}
+	pulseNTPJobPool    = sync.Pool{New: newPulseNTPJob}
+	pulseDNSJobPool    = sync.Pool{New: newPulseDNSJob}
+	pulseGRPCJobPool   = sync.Pool{New: newPulseGRPCJob}
+	pulseDockerJobPool = sync.Pool{New: newPulseDockerJob}
+
+	interventionDockerJobPool        = sync.Pool{New: newInterventionDockerJob}
+	interventionDockerStopJobPool    = sync.Pool{New: newInterventionDockerStopJob}
+	interventionDockerStartJobPool   = sync.Pool{New: newInterventionDockerStartJob}
+	interventionDockerKillJobPool    = sync.Pool{New: newInterventionDockerKillJob}
+	interventionDockerPauseJobPool   = sync.Pool{New: newInterventionDockerPauseJob}
+	interventionDockerUnpauseJobPool = sync.Pool{New: newInterventionDockerUnpauseJob}
+	interventionDockerScaleJobPool   = sync.Pool{New: newInterventionDockerScaleJob}
+	interventionExecJobPool          = sync.Pool{New: newInterventionExecJob}
+	interventionWebhookJobPool       = sync.Pool{New: newInterventionWebhookJob}
+
+	codeLogJobPool       = sync.Pool{New: newCodeLogJob}
+	codePagerDutyJobPool = sync.Pool{New: newCodePagerDutyJob}
+	codeSlackJobPool     = sync.Pool{New: newCodeSlackJob}
+	codeEmailJobPool     = sync.Pool{New: newCodeEmailJob}
+	codeWebhookJobPool   = sync.Pool{New: newCodeWebhookJob}
+)
+
+func getPulseHTTPJob() *PulseHTTPJob { return pulseHTTPJobPool.Get().(*PulseHTTPJob) }
+func getPulseTCPJob() *PulseTCPJob   { return pulseTCPJobPool.Get().(*PulseTCPJob) }
+func getPulseUDPJob() *PulseUDPJob   { return pulseUDPJobPool.Get().(*PulseUDPJob) }
+func getPulseICMPJob() *PulseICMPJob { return pulseICMPJobPool.Get().(*PulseICMPJob) }
+func getPulseNTPJob() *PulseNTPJob   { return pulseNTPJobPool.Get().(*PulseNTPJob) }
+func getPulseDNSJob() *PulseDNSJob   { return pulseDNSJobPool.Get().(*PulseDNSJob) }
+func getPulseGRPCJob() *PulseGRPCJob { return pulseGRPCJobPool.Get().(*PulseGRPCJob) }
+func getPulseDockerJob() *PulseDockerJob {
+	return pulseDockerJobPool.Get().(*PulseDockerJob)
+}
+
+func getInterventionDockerJob() *InterventionDockerJob {
+	return interventionDockerJobPool.Get().(*InterventionDockerJob)
+}
+func getInterventionDockerStopJob() *InterventionDockerStopJob {
+	return interventionDockerStopJobPool.Get().(*InterventionDockerStopJob)
+}
+func getInterventionDockerStartJob() *InterventionDockerStartJob {
+	return interventionDockerStartJobPool.Get().(*InterventionDockerStartJob)
+}
+func getInterventionDockerKillJob() *InterventionDockerKillJob {
+	return interventionDockerKillJobPool.Get().(*InterventionDockerKillJob)
+}
+func getInterventionDockerPauseJob() *InterventionDockerPauseJob {
+	return interventionDockerPauseJobPool.Get().(*InterventionDockerPauseJob)
+}
+func getInterventionDockerUnpauseJob() *InterventionDockerUnpauseJob {
+	return interventionDockerUnpauseJobPool.Get().(*InterventionDockerUnpauseJob)
+}
+func getInterventionDockerScaleJob() *InterventionDockerScaleJob {
+	return interventionDockerScaleJobPool.Get().(*InterventionDockerScaleJob)
+}
+func getInterventionExecJob() *InterventionExecJob {
+	return interventionExecJobPool.Get().(*InterventionExecJob)
+}
+func getInterventionWebhookJob() *InterventionWebhookJob {
+	return interventionWebhookJobPool.Get().(*InterventionWebhookJob)
+}
+
+func getCodeLogJob() *CodeLogJob             { return codeLogJobPool.Get().(*CodeLogJob) }
+func getCodePagerDutyJob() *CodePagerDutyJob { return codePagerDutyJobPool.Get().(*CodePagerDutyJob) }
+func getCodeSlackJob() *CodeSlackJob         { return codeSlackJobPool.Get().(*CodeSlackJob) }
+func getCodeEmailJob() *CodeEmailJob         { return codeEmailJobPool.Get().(*CodeEmailJob) }
+func getCodeWebhookJob() *CodeWebhookJob     { return codeWebhookJobPool.Get().(*CodeWebhookJob) }
+
+// ReleasePulseJob returns a pulse job back to its pool.
+func ReleasePulseJob(job Job) {
+	switch j := job.(type) {
+	case *PulseHTTPJob:
+		resetPulseHTTPJob(j)
+		pulseHTTPJobPool.Put(j)
+	case *PulseTCPJob:
+		resetPulseTCPJob(j)
+		pulseTCPJobPool.Put(j)
+	case *PulseUDPJob:
+		resetPulseUDPJob(j)
+		pulseUDPJobPool.Put(j)
+	case *PulseICMPJob:
+		resetPulseICMPJob(j)
+		pulseICMPJobPool.Put(j)
+	case *PulseNTPJob:
+		resetPulseNTPJob(j)
+		pulseNTPJobPool.Put(j)
+	case *PulseDNSJob:
+		resetPulseDNSJob(j)
+		pulseDNSJobPool.Put(j)
+	case *PulseGRPCJob:
+		resetPulseGRPCJob(j)
+		pulseGRPCJobPool.Put(j)
+	case *PulseDockerJob:
+		resetPulseDockerJob(j)
+		pulseDockerJobPool.Put(j)
+	}
+}
+
+// ReleaseInterventionJob returns an intervention job back to its pool.
+func ReleaseInterventionJob(job Job) {
+	switch j := job.(type) {
+	case *InterventionDockerJob:
+		resetInterventionDockerJob(j)
+		interventionDockerJobPool.Put(j)
+	case *InterventionDockerStopJob:
+		resetInterventionDockerStopJob(j)
+		interventionDockerStopJobPool.Put(j)
+	case *InterventionDockerStartJob:
+		resetInterventionDockerStartJob(j)
+		interventionDockerStartJobPool.Put(j)
+	case *InterventionDockerKillJob:
+		resetInterventionDockerKillJob(j)
+		interventionDockerKillJobPool.Put(j)
+	case *InterventionDockerPauseJob:
+		resetInterventionDockerPauseJob(j)
+		interventionDockerPauseJobPool.Put(j)
+	case *InterventionDockerUnpauseJob:
+		resetInterventionDockerUnpauseJob(j)
+		interventionDockerUnpauseJobPool.Put(j)
+	case *InterventionDockerScaleJob:
+		resetInterventionDockerScaleJob(j)
+		interventionDockerScaleJobPool.Put(j)
+	case *InterventionExecJob:
+		resetInterventionExecJob(j)
+		interventionExecJobPool.Put(j)
+	case *InterventionWebhookJob:
+		resetInterventionWebhookJob(j)
+		interventionWebhookJobPool.Put(j)
+	case *InterventionStepJob:
+		// The wrapper itself is not pooled; release the wrapped job.
+		ReleaseInterventionJob(j.Job)
+	}
+}
+
+// ReleaseCodeJob returns a code job back to its pool.
+func ReleaseCodeJob(job Job) {
+	switch j := job.(type) {
+	case *CodeLogJob:
+		resetCodeLogJob(j)
+		codeLogJobPool.Put(j)
+	case *CodePagerDutyJob:
+		resetCodePagerDutyJob(j)
+		codePagerDutyJobPool.Put(j)
+	case *CodeSlackJob:
+		resetCodeSlackJob(j)
+		codeSlackJobPool.Put(j)
+	case *CodeEmailJob:
+		resetCodeEmailJob(j)
+		codeEmailJobPool.Put(j)
+	case *CodeWebhookJob:
+		resetCodeWebhookJob(j)
+		codeWebhookJobPool.Put(j)
+	}
+}
+
+func resetPulseHTTPJob(job *PulseHTTPJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.URL = ""
+	job.Method = ""
+	job.Host = ""
+	job.IsTLS = false
+	job.Headers = nil
+	job.ExpectedStatus = nil
+	job.BodyContains = ""
+	job.BodyRegex = nil
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	job.DetailedTiming = false
+	job.DegradedThreshold = 0
+	job.DegradedPhase = ""
+	job.Backoff = BackoffPolicy{}
+	job.Group = ""
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetPulseTCPJob(job *PulseTCPJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Host = ""
+	job.Port = 0
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	job.DetailedTiming = false
+	job.DegradedThreshold = 0
+	job.DegradedPhase = ""
+	job.Backoff = BackoffPolicy{}
+	job.Group = ""
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetPulseUDPJob(job *PulseUDPJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Host = ""
+	job.Port = 0
+	job.Probe = nil
+	job.Expect = nil
+	job.ExpectPrefix = false
+	job.ReadTimeout = 0
+	job.RequireResponse = false
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	job.Backoff = BackoffPolicy{}
+	job.Group = ""
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetPulseICMPJob(job *PulseICMPJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Host = ""
+	job.Timeout = 0
+	job.Count = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	job.IgnorePrivilege = false
+	job.Backoff = BackoffPolicy{}
+	job.Group = ""
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetPulseNTPJob(job *PulseNTPJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Servers = nil
+	job.MaxOffset = 0
+	job.Samples = 0
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	job.Group = ""
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetPulseDNSJob(job *PulseDNSJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Host = ""
+	job.RecordType = ""
+	job.Resolver = ""
+	job.Expected = nil
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	job.lookup = nil
+	job.Group = ""
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetPulseGRPCJob(job *PulseGRPCJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Target = ""
+	job.Service = ""
+	job.TLS = false
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	job.Group = ""
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetPulseDockerJob(job *PulseDockerJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Container = ""
+	job.DockerHost = ""
+	job.RequireHealthy = false
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	job.Group = ""
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetInterventionDockerJob(job *InterventionDockerJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Container = ""
+	job.DockerHost = ""
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+	// JobType and Driver are set on creation, don't clear
+}
+
+func resetInterventionDockerStopJob(job *InterventionDockerStopJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Container = ""
+	job.DockerHost = ""
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetInterventionDockerStartJob(job *InterventionDockerStartJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Container = ""
+	job.DockerHost = ""
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetInterventionDockerKillJob(job *InterventionDockerKillJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Container = ""
+	job.DockerHost = ""
+	job.Signal = ""
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetInterventionDockerPauseJob(job *InterventionDockerPauseJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Container = ""
+	job.DockerHost = ""
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetInterventionDockerUnpauseJob(job *InterventionDockerUnpauseJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Container = ""
+	job.DockerHost = ""
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetInterventionDockerScaleJob(job *InterventionDockerScaleJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Service = ""
+	job.DockerHost = ""
+	job.Replicas = 0
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetInterventionExecJob(job *InterventionExecJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Command = ""
+	job.WorkingDir = ""
+	job.Args = nil
+	job.Env = nil
+	job.ExpectedExitCodes = nil
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetInterventionWebhookJob(job *InterventionWebhookJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Monitor = ""
+	job.URL = ""
+	job.Method = ""
+	job.Headers = nil
+	job.Secret = ""
+	job.Template = nil
+	job.ExpectedStatus = nil
+	job.FailureCount = 0
+	job.Timeout = 0
+	job.Retries = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetCodeLogJob(job *CodeLogJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Status = ""
+	job.Monitor = ""
+	job.Color = ""
+	job.Severity = ""
+	job.Summary = ""
+	job.Action = ""
+	job.NextSteps = ""
+	job.File = ""
+	job.Entity = ecs.Entity{}
+}
+
+func resetCodePagerDutyJob(job *CodePagerDutyJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Monitor = ""
+	job.Message = ""
+	job.Color = ""
+	job.RoutingKey = ""
+	job.URL = ""
+	job.Status = ""
+	job.Severity = ""
+	job.Summary = ""
+	job.Action = ""
+	job.Timeout = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetCodeSlackJob(job *CodeSlackJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Monitor = ""
+	job.Message = ""
+	job.Color = ""
+	job.WebHook = ""
+	job.Status = ""
+	job.Severity = ""
+	job.Summary = ""
+	job.Action = ""
+	job.NextSteps = ""
+	job.Timeout = 0
+	job.Entity = ecs.Entity{}
+}
+
+func resetCodeEmailJob(job *CodeEmailJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Monitor = ""
+	job.Message = ""
+	job.Color = ""
+	job.Host = ""
+	job.From = ""
+	job.SubjectPrefix = ""
+	job.Username = ""
+	job.Password = ""
+	job.To = nil
+	job.Port = 0
+	job.Timeout = 0
+	job.StartTLS = false
+	job.Entity = ecs.Entity{}
+}
+
+func resetCodeWebhookJob(job *CodeWebhookJob) {
+	if job == nil {
+		return
+	}
+	job.EnqueueTime = time.Time{}
+	job.StartTime = time.Time{}
+	job.Monitor = ""
+	job.Message = ""
+	job.Color = ""
+	job.URL = ""
+	job.Method = ""
+	job.Headers = nil
+	job.Secret = ""
+	job.Template = nil
+	job.Status = ""
+	job.Severity = ""
+	job.Summary = ""
+	job.Action = ""
+	job.NextSteps = ""
+	job.Timeout = 0
+	job.Entity = ecs.Entity{}
+}
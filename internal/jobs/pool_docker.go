@@ -0,0 +1,90 @@
+//go:build !nodocker
+
+package jobs
+
+import (
+	"sync"
+
+	"github.com/moby/moby/client"
+)
+
+// Default Docker client with sync.Once for thread-safe lazy initialization.
+// This is the most common case (empty host = use environment).
+var (
+	defaultDockerClient     *client.Client
+	defaultDockerClientOnce sync.Once
+	defaultDockerClientErr  error
+)
+
+// dockerClientEntry holds a client and its initialization state for custom hosts.
+type dockerClientEntry struct {
+	once   sync.Once
+	client *client.Client
+	err    error
+}
+
+// dockerClientPool stores shared Docker clients keyed by host.
+// Each entry uses sync.Once to ensure exactly one initialization per host.
+var dockerClientPool sync.Map // map[string]*dockerClientEntry
+
+// GetDockerClient returns a shared *client.Client for the given host.
+// If host is empty, it uses the default environment configuration.
+// Uses sync.Once to ensure each client is created exactly once (no duplicate creations).
+func GetDockerClient(host string) (*client.Client, error) {
+	// Fast path: default client (most common case)
+	if host == "" {
+		defaultDockerClientOnce.Do(func() {
+			defaultDockerClient, defaultDockerClientErr = client.NewClientWithOpts(
+				client.FromEnv,
+				client.WithAPIVersionNegotiation(),
+			)
+		})
+		if defaultDockerClientErr == nil {
+			ensureDockerDaemonMonitor(host, defaultDockerClient)
+		}
+		return defaultDockerClient, defaultDockerClientErr
+	}
+
+	// Custom host: use per-host sync.Once via sync.Map
+	entryI, _ := dockerClientPool.LoadOrStore(host, &dockerClientEntry{})
+	entry := entryI.(*dockerClientEntry)
+
+	entry.once.Do(func() {
+		entry.client, entry.err = client.NewClientWithOpts(
+			client.WithHost(host),
+			client.WithAPIVersionNegotiation(),
+		)
+	})
+
+	if entry.err == nil {
+		ensureDockerDaemonMonitor(host, entry.client)
+	}
+	return entry.client, entry.err
+}
+
+// CloseDockerClients stops the daemon health monitors and closes every
+// shared client, releasing their pooled connections. Called at controller
+// shutdown after the intervention pool has drained, so no job still holds a
+// client; it must not run concurrently with GetDockerClient. The pool is
+// reset, so a later GetDockerClient (e.g. from a new controller instance)
+// rebuilds clients from scratch.
+func CloseDockerClients() {
+	dockerDaemonMonitors.Range(func(_, value interface{}) bool {
+		value.(*dockerDaemonMonitor).stopMonitor()
+		return true
+	})
+
+	if defaultDockerClient != nil {
+		_ = defaultDockerClient.Close()
+	}
+	defaultDockerClient, defaultDockerClientErr = nil, nil
+	defaultDockerClientOnce = sync.Once{}
+
+	dockerClientPool.Range(func(key, value interface{}) bool {
+		if entry := value.(*dockerClientEntry); entry.client != nil {
+			_ = entry.client.Close()
+		}
+		dockerClientPool.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,33 @@
+//go:build nodocker
+
+package jobs
+
+import "errors"
+
+// ErrDockerDisabled is returned when Docker support is disabled via build tags.
+var ErrDockerDisabled = errors.New("docker support disabled (build with -tags nodocker)")
+
+// GetDockerClient returns an error when Docker is disabled.
+func GetDockerClient(host string) (interface{}, error) {
+	return nil, ErrDockerDisabled
+}
+
+// CheckDockerDaemon is a no-op when Docker is disabled.
+func CheckDockerDaemon(host string) error {
+	return nil
+}
+
+// CloseDockerClients is a no-op when Docker is disabled.
+func CloseDockerClients() {}
+
+// UnhealthyDockerEndpoints returns nil when Docker is disabled.
+func UnhealthyDockerEndpoints() []string {
+	return nil
+}
+
+// SetDockerDaemonStateHook is a no-op when Docker is disabled.
+func SetDockerDaemonStateHook(hook DockerDaemonStateHook) {}
+
+// DockerDaemonStateHook is invoked on daemon health transitions (unused when
+// Docker is disabled).
+type DockerDaemonStateHook func(host string, healthy bool)
@@ -0,0 +1,95 @@
+//go:build !nodocker
+
+package jobs
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDockerClientPool_ReuseAndClose verifies that sequential executions
+// share one negotiated client instead of rebuilding it per call, and that
+// CloseDockerClients tears the pool down so a later caller gets a fresh
+// client. Not parallel: CloseDockerClients wipes the package-level pool.
+func TestDockerClientPool_ReuseAndClose(t *testing.T) {
+	var pings, restarts atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			pings.Add(1)
+			w.Header().Set("Api-Version", "1.43")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/restart"):
+			restarts.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := &http.Server{Handler: mux}
+	go func() { _ = server.Serve(ln) }()
+	defer func() { _ = server.Close() }()
+
+	host := "tcp://" + ln.Addr().String()
+	defer CloseDockerClients()
+
+	c1, err := GetDockerClient(host)
+	if err != nil {
+		t.Fatalf("GetDockerClient failed: %v", err)
+	}
+	if c2, _ := GetDockerClient(host); c2 != c1 {
+		t.Fatal("expected GetDockerClient to return the same shared client")
+	}
+
+	job := &InterventionDockerJob{
+		Container:  "test-container",
+		DockerHost: host,
+		Timeout:    5 * time.Second,
+	}
+
+	// The first API call triggers the one-time version negotiation; warm up
+	// with one execution, then record the ping count before measuring.
+	if res := job.Execute(context.Background()); res.Err != nil {
+		t.Fatalf("warm-up execution failed: %v", res.Err)
+	}
+	pingsBefore := pings.Load()
+
+	const executions = 5
+	for i := 0; i < executions; i++ {
+		if res := job.Execute(context.Background()); res.Err != nil {
+			t.Fatalf("execution %d failed: %v", i, res.Err)
+		}
+	}
+	if restarts.Load() != executions+1 {
+		t.Fatalf("expected %d restart calls, got %d", executions+1, restarts.Load())
+	}
+	// A shared client negotiates once; per-execution clients would ping on
+	// every run. The monitor's 10s tick cannot fire within this window.
+	if pings.Load() != pingsBefore {
+		t.Fatalf("expected no additional negotiation pings across %d executions, got %d",
+			executions, pings.Load()-pingsBefore)
+	}
+
+	CloseDockerClients()
+	if _, ok := dockerDaemonMonitors.Load(host); ok {
+		t.Fatal("expected daemon monitor to be stopped after CloseDockerClients")
+	}
+	c3, err := GetDockerClient(host)
+	if err != nil {
+		t.Fatalf("GetDockerClient after close failed: %v", err)
+	}
+	if c3 == c1 {
+		t.Fatal("expected a fresh client after CloseDockerClients")
+	}
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"crypto/tls"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// =============================================================================
+// gRPC Connection Pool
+// =============================================================================
+
+// GRPCConnPool manages one shared *grpc.ClientConn per target, mirroring the
+// per-host fasthttp client pool. gRPC connections multiplex RPCs over a
+// single HTTP/2 stream, so one long-lived conn per target serves any number
+// of monitors instead of one connection each.
+type GRPCConnPool struct {
+	conns sync.Map // map[string]*grpc.ClientConn
+}
+
+// grpcConns is the global gRPC connection pool.
+var grpcConns = &GRPCConnPool{}
+
+// Get returns the shared client connection for a target, creating it lazily.
+// grpc.NewClient does not dial until the first RPC, so creation is cheap and
+// a dead target costs nothing until it is actually checked.
+func (p *GRPCConnPool) Get(target string, useTLS bool) (*grpc.ClientConn, error) {
+	key := target
+	if useTLS {
+		key = "tls:" + target
+	}
+
+	if v, ok := p.conns.Load(key); ok {
+		return v.(*grpc.ClientConn), nil
+	}
+
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	actual, loaded := p.conns.LoadOrStore(key, conn)
+	if loaded {
+		// Another goroutine won the race; discard ours.
+		_ = conn.Close()
+	}
+	return actual.(*grpc.ClientConn), nil
+}
@@ -0,0 +1,370 @@
+package jobs
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpproxy"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// =============================================================================
+// Fasthttp Client Pool (Primary - High Performance)
+// =============================================================================
+
+// FastHTTPClientPool manages fasthttp.HostClient instances per host.
+// Using HostClient instead of Client allows connection reuse per host.
+type FastHTTPClientPool struct {
+	clients sync.Map // map[string]*fasthttp.HostClient
+}
+
+// fasthttpClients is the global fasthttp client pool.
+var fasthttpClients = &FastHTTPClientPool{}
+
+// customFasthttpDialer is a shared dialer with SO_REUSEADDR for faster socket recycling.
+var customFasthttpDialer = func(addr string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:       10 * time.Second,
+		KeepAlive:     -1,    // Disable keep-alive for health checks
+		DualStack:     false, // IPv4 only
+		FallbackDelay: -1,    // Disable Happy Eyeballs
+		Control: func(network, address string, c syscall.RawConn) error {
+			return c.Control(func(fd uintptr) {
+				_ = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+			})
+		},
+	}
+	conn, err := dialer.Dial("tcp", addr)
+	return GetDialMetrics().ObserveDial(conn, err), err
+}
+
+// proxyFasthttpDialer returns a dial function that tunnels through the given
+// proxy URL (http://, https://, or socks5://). The loader validates proxy
+// URLs at load time, so a dialer construction failure falls back to a direct
+// dial rather than panicking mid-check.
+func proxyFasthttpDialer(proxy string) fasthttp.DialFunc {
+	d := fasthttpproxy.Dialer{
+		Config:         httpproxy.Config{HTTPProxy: proxy, HTTPSProxy: proxy},
+		Timeout:        10 * time.Second,
+		ConnectTimeout: 10 * time.Second,
+	}
+	inner, err := d.GetDialFunc(false)
+	if err != nil {
+		return customFasthttpDialer
+	}
+	return func(addr string) (net.Conn, error) {
+		conn, dialErr := inner(addr)
+		return GetDialMetrics().ObserveDial(conn, dialErr), dialErr
+	}
+}
+
+// Get returns a fasthttp.HostClient for the given host and proxy (empty for
+// a direct dial). Clients are cached and reused for connection pooling; the
+// proxy is part of the cache key so proxied and direct connections to the
+// same host never mix.
+func (p *FastHTTPClientPool) Get(host string, isTLS bool, proxy string) *fasthttp.HostClient {
+	key := host
+	if isTLS {
+		key = "tls:" + host
+	}
+	if proxy != "" {
+		key = proxy + "|" + key
+	}
+
+	if v, ok := p.clients.Load(key); ok {
+		return v.(*fasthttp.HostClient)
+	}
+
+	dial := customFasthttpDialer
+	if proxy != "" {
+		dial = proxyFasthttpDialer(proxy)
+	}
+
+	client := &fasthttp.HostClient{
+		Addr: host,
+
+		// Connection pooling - optimized for health checks at scale
+		MaxConns:            512,              // Max concurrent connections per host
+		MaxIdleConnDuration: 30 * time.Second, // Release idle connections faster
+
+		// Timeouts - will be overridden per-request with DoTimeout
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+
+		// TLS configuration
+		IsTLS: isTLS,
+
+		// Custom dialer with SO_REUSEADDR for faster socket recycling,
+		// or a tunneling dialer when a proxy is configured
+		Dial: dial,
+
+		// Disable features not needed for health checks
+		DisableHeaderNamesNormalizing: true,
+		DisablePathNormalizing:        true,
+
+		// Connection settings
+		MaxConnWaitTimeout: 5 * time.Second,
+	}
+
+	actual, _ := p.clients.LoadOrStore(key, client)
+	return actual.(*fasthttp.HostClient)
+}
+
+// AcquireHTTPDialSlot acquires a global dial slot for HTTP requests.
+// This prevents CPU spikes during network outages when all targets fail.
+// Returns true if slot acquired, false if timeout/cancelled.
+// Caller MUST call ReleaseHTTPDialSlot() after the request completes.
+func AcquireHTTPDialSlot(ctx context.Context) bool {
+	return GetDialLimiter().Acquire(ctx)
+}
+
+// ReleaseHTTPDialSlot releases a dial slot back to the global pool.
+func ReleaseHTTPDialSlot() {
+	GetDialLimiter().Release()
+}
+
+// GetFastHTTPClient returns a fasthttp.HostClient for the given URL.
+// This is the primary method for HTTP health checks.
+func GetFastHTTPClient(rawURL string) (*fasthttp.HostClient, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	isTLS := u.Scheme == "https"
+
+	// Add default port if missing
+	if u.Port() == "" {
+		if isTLS {
+			host = host + ":443"
+		} else {
+			host = host + ":80"
+		}
+	}
+
+	return fasthttpClients.Get(host, isTLS, ""), nil
+}
+
+// ExtractHostFromURL extracts host:port from a URL for fasthttp.
+func ExtractHostFromURL(rawURL string) (host string, isTLS bool, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false, err
+	}
+
+	host = u.Host
+	isTLS = u.Scheme == "https"
+
+	// Add default port if missing
+	if u.Port() == "" {
+		if isTLS {
+			host = host + ":443"
+		} else {
+			host = host + ":80"
+		}
+	}
+
+	return host, isTLS, nil
+}
+
+// =============================================================================
+// Shared net/http Client Pool (Legacy checks, detailed timing helpers)
+// =============================================================================
+
+// HTTPTransportConfig configures connection pooling for the shared net/http
+// transports. The defaults balance connection reuse (CPU) against memory:
+// each idle connection consumes ~10-50KB (buffers, TLS state, etc.).
+type HTTPTransportConfig struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept per host.
+	// Default: 4
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept before close.
+	// Default: 30 seconds
+	IdleConnTimeout time.Duration
+}
+
+// DefaultHTTPTransportConfig returns the pooling defaults used when no
+// override is configured.
+func DefaultHTTPTransportConfig() HTTPTransportConfig {
+	return HTTPTransportConfig{
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     30 * time.Second,
+	}
+}
+
+var (
+	httpTransportConfig     = DefaultHTTPTransportConfig()
+	httpTransportConfigOnce sync.Once
+)
+
+// SetHTTPTransportConfig overrides the transport pooling configuration.
+// Must be called before any clients are created (typically at startup);
+// transports already handed out keep their original settings.
+func SetHTTPTransportConfig(cfg HTTPTransportConfig) {
+	httpTransportConfigOnce.Do(func() {
+		if cfg.MaxIdleConnsPerHost > 0 {
+			httpTransportConfig.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+		}
+		if cfg.IdleConnTimeout > 0 {
+			httpTransportConfig.IdleConnTimeout = cfg.IdleConnTimeout
+		}
+	})
+}
+
+// HTTPProxyConfig is the controller-wide proxy default for HTTP pulse
+// checks. Monitors may override the URL per check; NoProxy hosts are dialed
+// directly regardless of which proxy would otherwise apply.
+type HTTPProxyConfig struct {
+	// Proxy is the default proxy URL (http://, https://, or socks5://).
+	// Empty means direct dials unless a monitor sets its own proxy.
+	Proxy string
+
+	// NoProxy lists hosts that bypass proxying, NO_PROXY style: an entry
+	// matches the target host exactly or as a parent domain ("corp.example"
+	// matches "db.corp.example"). Ports are ignored.
+	NoProxy []string
+}
+
+var (
+	httpProxyConfig     HTTPProxyConfig
+	httpProxyConfigOnce sync.Once
+)
+
+// SetHTTPProxyConfig installs the controller-wide proxy defaults. Must be
+// called before jobs are created (typically at startup); jobs already built
+// keep the proxy resolved at creation.
+func SetHTTPProxyConfig(cfg HTTPProxyConfig) {
+	httpProxyConfigOnce.Do(func() { httpProxyConfig = cfg })
+}
+
+// resolveHTTPProxy picks the proxy for a check: the monitor's own setting
+// wins over the controller default, and NoProxy hosts get a direct dial
+// either way. host is "host:port" as produced by ExtractHostFromURL.
+func resolveHTTPProxy(explicit, host string) string {
+	proxy := explicit
+	if proxy == "" {
+		proxy = httpProxyConfig.Proxy
+	}
+	if proxy == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	for _, entry := range httpProxyConfig.NoProxy {
+		entry = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(entry)), ".")
+		if entry == "" {
+			continue
+		}
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return ""
+		}
+	}
+	return proxy
+}
+
+// httpClientKey identifies a shared client: jobs with the same timeout, TLS
+// verification mode, and proxy share one *http.Client (and its transport),
+// so N monitors against one host reuse connections instead of dialing N times.
+type httpClientKey struct {
+	timeout  time.Duration
+	insecure bool
+	proxy    string
+}
+
+// httpClientPool stores shared HTTP clients keyed by (timeout, insecure, proxy).
+var httpClientPool sync.Map // map[httpClientKey]*http.Client
+
+// httpTransportPool stores shared transports keyed by (insecure, proxy).
+// Clients that differ only in timeout share a transport, maximizing reuse.
+var httpTransportPool sync.Map // map[httpClientKey]*http.Transport
+
+// newHTTPTransport builds a transport with the configured pooling limits.
+func newHTTPTransport(insecure bool, proxyURL *url.URL) *http.Transport {
+	transport := &http.Transport{
+		// Connection pooling - balanced for CPU vs memory tradeoff
+		MaxIdleConns:        2048, // Total idle connections (was 100000 - too high)
+		MaxIdleConnsPerHost: httpTransportConfig.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     0, // No limit on concurrent connections per host
+		IdleConnTimeout:     httpTransportConfig.IdleConnTimeout,
+		DisableKeepAlives:   false, // Enable keep-alive for reuse
+
+		// Connection establishment optimization
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second, // Connection timeout
+			KeepAlive: 30 * time.Second, // TCP keep-alive probe interval
+			DualStack: false,            // Disable dual-stack to reduce connection attempts
+		}).DialContext,
+
+		// Response handling
+		ResponseHeaderTimeout: 0, // Use client timeout
+		ExpectContinueTimeout: 0, // Disable Expect: 100-continue
+
+		// TLS optimization
+		TLSHandshakeTimeout: 10 * time.Second,
+		ForceAttemptHTTP2:   false, // HTTP/1.1 is faster for health checks
+	}
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return transport
+}
+
+// getHTTPTransport returns the shared transport for a TLS mode and proxy.
+func getHTTPTransport(insecure bool, proxy string, proxyURL *url.URL) *http.Transport {
+	key := httpClientKey{insecure: insecure, proxy: proxy}
+	if v, ok := httpTransportPool.Load(key); ok {
+		return v.(*http.Transport)
+	}
+	actual, _ := httpTransportPool.LoadOrStore(key, newHTTPTransport(insecure, proxyURL))
+	return actual.(*http.Transport)
+}
+
+// GetPooledHTTPClient returns a shared *http.Client for the given timeout,
+// TLS verification mode, and proxy URL (empty for none). Callers must hold
+// the returned pointer and never copy the client by value: the transport is
+// shared, and per-job copies would defeat connection reuse.
+func GetPooledHTTPClient(timeout time.Duration, insecure bool, proxy string) (*http.Client, error) {
+	key := httpClientKey{timeout: timeout, insecure: insecure, proxy: proxy}
+	if v, ok := httpClientPool.Load(key); ok {
+		return v.(*http.Client), nil
+	}
+
+	var proxyURL *url.URL
+	if proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, err
+		}
+		proxyURL = u
+	}
+
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: getHTTPTransport(insecure, proxy, proxyURL),
+	}
+	actual, _ := httpClientPool.LoadOrStore(key, client)
+	return actual.(*http.Client), nil
+}
+
+// GetHTTPClient returns a shared *http.Client for the given timeout.
+// Clients share a Transport with sensible connection pooling defaults.
+// DEPRECATED: Use GetFastHTTPClient for better performance.
+func GetHTTPClient(timeout time.Duration) *http.Client {
+	client, _ := GetPooledHTTPClient(timeout, false, "")
+	return client
+}
@@ -0,0 +1,255 @@
+package jobs
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startConnCountingServer runs an HTTP server that counts accepted
+// connections, so tests can observe dials rather than guess at reuse.
+func startConnCountingServer(t testing.TB) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var conns atomic.Int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, &conns
+}
+
+// TestGetPooledHTTPClient_SharedByKey tests that clients are shared per
+// (timeout, insecure, proxy) key and distinct across keys.
+func TestGetPooledHTTPClient_SharedByKey(t *testing.T) {
+	t.Parallel()
+	first, err := GetPooledHTTPClient(3*time.Second, false, "")
+	if err != nil {
+		t.Fatalf("GetPooledHTTPClient failed: %v", err)
+	}
+	second, err := GetPooledHTTPClient(3*time.Second, false, "")
+	if err != nil {
+		t.Fatalf("GetPooledHTTPClient failed: %v", err)
+	}
+	if first != second {
+		t.Error("expected the same client for identical keys")
+	}
+
+	insecure, err := GetPooledHTTPClient(3*time.Second, true, "")
+	if err != nil {
+		t.Fatalf("GetPooledHTTPClient failed: %v", err)
+	}
+	if insecure == first {
+		t.Error("expected a distinct client for the insecure variant")
+	}
+	if insecure.Transport == first.Transport {
+		t.Error("expected a distinct transport for the insecure variant")
+	}
+
+	// Clients differing only in timeout share one transport.
+	slower, err := GetPooledHTTPClient(9*time.Second, false, "")
+	if err != nil {
+		t.Fatalf("GetPooledHTTPClient failed: %v", err)
+	}
+	if slower == first {
+		t.Error("expected distinct clients for distinct timeouts")
+	}
+	if slower.Transport != first.Transport {
+		t.Error("expected clients differing only in timeout to share a transport")
+	}
+
+	// The legacy accessor is a thin wrapper over the keyed pool.
+	if GetHTTPClient(3*time.Second) != first {
+		t.Error("expected GetHTTPClient to return the pooled client")
+	}
+}
+
+// TestGetPooledHTTPClient_InvalidProxy tests proxy URL validation.
+func TestGetPooledHTTPClient_InvalidProxy(t *testing.T) {
+	t.Parallel()
+	if _, err := GetPooledHTTPClient(time.Second, false, ":"); err == nil {
+		t.Error("expected an error for an unparseable proxy URL")
+	}
+}
+
+// TestPooledHTTPClient_ConnectionCountStaysBounded tests that concurrent
+// checks through one pooled client do not open one connection per request.
+func TestPooledHTTPClient_ConnectionCountStaysBounded(t *testing.T) {
+	t.Parallel()
+	server, conns := startConnCountingServer(t)
+
+	client, err := GetPooledHTTPClient(5*time.Second, false, "")
+	if err != nil {
+		t.Fatalf("GetPooledHTTPClient failed: %v", err)
+	}
+
+	// Keep concurrency at the per-host idle limit so steady state reuses
+	// the idle pool instead of churning extra connections.
+	const requests = 200
+	const workers = 4
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	// The transport needs at most `workers` concurrent connections; anywhere
+	// near one per request means pooling is broken. Allow slack for
+	// connections closed and reopened around the idle limit.
+	if got := conns.Load(); got > workers*4 {
+		t.Errorf("server accepted %d connections for %d requests, want bounded by worker count", got, requests)
+	}
+}
+
+// BenchmarkPooledHTTPClient_SequentialChecks measures sequential checks
+// against one host through the pooled client. Connection reuse should keep
+// the reported conns metric at one regardless of b.N.
+func BenchmarkPooledHTTPClient_SequentialChecks(b *testing.B) {
+	server, conns := startConnCountingServer(b)
+
+	client, err := GetPooledHTTPClient(5*time.Second, false, "")
+	if err != nil {
+		b.Fatalf("GetPooledHTTPClient failed: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			b.Fatalf("request failed: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(conns.Load()), "conns")
+}
+
+// startConnectProxy runs a minimal HTTP CONNECT proxy and returns its URL and
+// a counter of tunnels established, so tests can assert a check really
+// traversed the proxy instead of dialing the target directly.
+func startConnectProxy(t testing.TB) (string, *atomic.Int64) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var tunnels atomic.Int64
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil || req.Method != http.MethodConnect {
+					return
+				}
+				upstream, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+					return
+				}
+				defer upstream.Close()
+				if _, err := io.WriteString(conn, "HTTP/1.1 200 OK\r\n\r\n"); err != nil {
+					return
+				}
+				tunnels.Add(1)
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}(conn)
+		}
+	}()
+	return "http://" + ln.Addr().String(), &tunnels
+}
+
+// TestPulseHTTPJob_ThroughHTTPProxy tests that a proxied check tunnels
+// through the configured proxy via CONNECT and still reaches the target.
+func TestPulseHTTPJob_ThroughHTTPProxy(t *testing.T) {
+	t.Parallel()
+	server, conns := startConnCountingServer(t)
+	proxyURL, tunnels := startConnectProxy(t)
+
+	host, isTLS, err := ExtractHostFromURL(server.URL)
+	if err != nil {
+		t.Fatalf("failed to extract host: %v", err)
+	}
+	job := &PulseHTTPJob{
+		URL:     server.URL,
+		Method:  "GET",
+		Host:    host,
+		IsTLS:   isTLS,
+		Proxy:   proxyURL,
+		Timeout: 5 * time.Second,
+	}
+
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("proxied check failed: %v", result.Err)
+	}
+	if tunnels.Load() == 0 {
+		t.Error("expected the check to tunnel through the proxy")
+	}
+	if conns.Load() == 0 {
+		t.Error("expected the tunnel to reach the backend")
+	}
+}
+
+// TestResolveHTTPProxy tests proxy resolution precedence and NoProxy bypass.
+func TestResolveHTTPProxy(t *testing.T) {
+	prev := httpProxyConfig
+	defer func() { httpProxyConfig = prev }()
+	httpProxyConfig = HTTPProxyConfig{
+		Proxy:   "http://default-proxy:3128",
+		NoProxy: []string{"internal.example", "10.0.0.1"},
+	}
+
+	cases := []struct {
+		name     string
+		explicit string
+		host     string
+		want     string
+	}{
+		{"default applies", "", "api.example.com:443", "http://default-proxy:3128"},
+		{"override wins", "socks5://edge:1080", "api.example.com:443", "socks5://edge:1080"},
+		{"no-proxy exact match", "", "internal.example:80", ""},
+		{"no-proxy beats override", "socks5://edge:1080", "db.internal.example:5432", ""},
+		{"no-proxy ip", "", "10.0.0.1:9100", ""},
+		{"sibling host still proxied", "", "notinternal.example:80", "http://default-proxy:3128"},
+	}
+	for _, tc := range cases {
+		if got := resolveHTTPProxy(tc.explicit, tc.host); got != tc.want {
+			t.Errorf("%s: resolveHTTPProxy(%q, %q) = %q, want %q",
+				tc.name, tc.explicit, tc.host, got, tc.want)
+		}
+	}
+}
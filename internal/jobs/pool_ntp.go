@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+// NTPConnPool pools connected UDP sockets keyed by NTP server address.
+// A connected socket filters out datagrams from other peers, and reusing it
+// avoids re-resolving the server name on every sample.
+type NTPConnPool struct {
+	pools sync.Map // map[string]*sync.Pool of net.Conn
+}
+
+// ntpConns is the global NTP connection pool.
+var ntpConns = &NTPConnPool{}
+
+// Get returns a pooled connection for the given server address, dialing a
+// new one if none is available.
+func (p *NTPConnPool) Get(ctx context.Context, address string) (net.Conn, error) {
+	if v, ok := p.pools.Load(address); ok {
+		if c := v.(*sync.Pool).Get(); c != nil {
+			return c.(net.Conn), nil
+		}
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "udp", address)
+}
+
+// Put returns a healthy connection to the pool for its server address.
+// Connections that errored should be closed instead of returned.
+func (p *NTPConnPool) Put(address string, conn net.Conn) {
+	v, _ := p.pools.LoadOrStore(address, &sync.Pool{})
+	v.(*sync.Pool).Put(conn)
+}
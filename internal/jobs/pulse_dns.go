@@ -0,0 +1,236 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// dnsDefaultPort is appended to resolver addresses without an explicit port.
+const dnsDefaultPort = "53"
+
+// PulseDNSJob performs DNS resolution health checks. It resolves Host for
+// the configured record type and, when Expected is set, requires every
+// expected value to be present in the answer (order-insensitive).
+//
+// Safety features:
+//   - Uses global dial limiter to prevent CPU spikes during outages
+//   - Checks context cancellation between retries
+//   - Applies the job Timeout per attempt via context
+//   - Fresh payload per execution (resolved records escape in the Result)
+type PulseDNSJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Host        string
+	RecordType  string
+	JobType     string
+	Driver      string
+	// Resolver optionally overrides the system resolver, as "host" or
+	// "host:port" (port 53 by default).
+	Resolver string
+	Expected []string
+	Timeout  time.Duration
+	Retries  int
+	Entity   ecs.Entity
+	// Group is the concurrency group for per-group in-flight limits.
+	Group string
+
+	// lookup can be swapped for a stub resolver in tests; nil selects the
+	// real net.Resolver path.
+	lookup func(ctx context.Context, recordType, host string) ([]string, error)
+}
+
+// Execute performs the DNS resolution check with retries.
+func (p *PulseDNSJob) Execute(ctx context.Context) Result {
+	recordType := strings.ToUpper(p.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+
+	payload := Payload{Kind: KindPulse, Driver: InternedDNS}
+	payload.SetExtra("record_type", recordType)
+
+	// Use global dial limiter to prevent CPU spikes during mass failures.
+	if !GetDialLimiter().Acquire(ctx) {
+		return Result{Ent: p.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer GetDialLimiter().Release()
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	var records []string
+	start := time.Now()
+	err := RetryWithBackoff(ctx, p.Retries+1, 50*time.Millisecond, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var lookupErr error
+		records, lookupErr = p.doLookup(attemptCtx, recordType, p.Host)
+		if lookupErr != nil {
+			return classifyDNSError(lookupErr)
+		}
+		return checkExpectedRecords(records, p.Expected)
+	})
+
+	if len(records) > 0 {
+		payload.SetExtra("records", records)
+	}
+
+	elapsed := time.Since(start)
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		// NXDOMAIN, timeout, and mismatch surface as distinct sentinels so
+		// downstream consumers can tell configuration drift from outages.
+		return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed, Class: InfraErrorClass(err)}
+	}
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// doLookup resolves through the test stub when set, else a real resolver.
+func (p *PulseDNSJob) doLookup(ctx context.Context, recordType, host string) ([]string, error) {
+	if p.lookup != nil {
+		return p.lookup(ctx, recordType, host)
+	}
+	return resolveRecords(ctx, p.newResolver(), recordType, host)
+}
+
+// newResolver returns the system resolver, or a Go resolver dialing the
+// configured override address.
+func (p *PulseDNSJob) newResolver() *net.Resolver {
+	if p.Resolver == "" {
+		return net.DefaultResolver
+	}
+	address := p.Resolver
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, dnsDefaultPort)
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// resolveRecords performs the typed lookup and renders the answer as strings:
+// addresses for A/AAAA, lowercased names without the trailing dot for
+// CNAME/MX, and raw strings for TXT.
+func resolveRecords(ctx context.Context, r *net.Resolver, recordType, host string) ([]string, error) {
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]string, len(ips))
+		for i, ip := range ips {
+			records[i] = ip.String()
+		}
+		return records, nil
+
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		return []string{normalizeDNSName(cname)}, nil
+
+	case "TXT":
+		return r.LookupTXT(ctx, host)
+
+	case "MX":
+		mxs, err := r.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]string, len(mxs))
+		for i, mx := range mxs {
+			records[i] = normalizeDNSName(mx.Host)
+		}
+		return records, nil
+
+	default:
+		// The validator rejects unknown record types at load time.
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+}
+
+// classifyDNSError maps resolver errors to the job's sentinel errors so
+// NXDOMAIN and timeouts stay distinguishable after retries.
+func classifyDNSError(err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return ErrDNSNotFound
+		}
+		if dnsErr.IsTimeout {
+			return ErrDNSTimeout
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrDNSTimeout
+	}
+	return err
+}
+
+// checkExpectedRecords requires every expected value to appear in the
+// answer. Comparison ignores order, name case, and trailing dots.
+func checkExpectedRecords(records, expected []string) error {
+	for _, want := range expected {
+		if !recordPresent(records, want) {
+			return ErrDNSMismatch
+		}
+	}
+	return nil
+}
+
+func recordPresent(records []string, want string) bool {
+	for _, record := range records {
+		if record == want || normalizeDNSName(record) == normalizeDNSName(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDNSName lowercases a name and strips the trailing dot resolvers
+// append to fully-qualified answers.
+func normalizeDNSName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (p *PulseDNSJob) Copy() Job { job := *p; return &job }
+
+// ConcurrencyGroup returns the job's group for the group limiter.
+func (p *PulseDNSJob) ConcurrencyGroup() string { return p.Group }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (p *PulseDNSJob) GetEnqueueTime() time.Time { return p.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (p *PulseDNSJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (p *PulseDNSJob) GetStartTime() time.Time { return p.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (p *PulseDNSJob) SetStartTime(t time.Time) { p.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (p *PulseDNSJob) IsNil() bool { return p == nil }
@@ -0,0 +1,188 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// stubLookup returns a lookup func that serves canned records or an error.
+func stubLookup(records []string, err error) func(context.Context, string, string) ([]string, error) {
+	return func(context.Context, string, string) ([]string, error) {
+		return records, err
+	}
+}
+
+// TestPulseDNSJob_Execute_Success tests a resolving record with matching
+// expectations and the records landing in the payload.
+func TestPulseDNSJob_Execute_Success(t *testing.T) {
+	t.Parallel()
+	job := &PulseDNSJob{
+		Host:       "example.com",
+		RecordType: "A",
+		Expected:   []string{"192.0.2.10"},
+		Timeout:    time.Second,
+		lookup:     stubLookup([]string{"192.0.2.20", "192.0.2.10"}, nil),
+	}
+
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("expected success, got %v", result.Err)
+	}
+	records, ok := result.Payload.Extra("records").([]string)
+	if !ok || len(records) != 2 {
+		t.Errorf("payload records = %v, want both resolved values", result.Payload.Extra("records"))
+	}
+	if result.Payload.Extra("record_type") != "A" {
+		t.Errorf("record_type = %v, want A", result.Payload.Extra("record_type"))
+	}
+}
+
+// TestPulseDNSJob_Execute_OrderInsensitiveMatch tests that expectations match
+// regardless of answer order, case, and trailing dots.
+func TestPulseDNSJob_Execute_OrderInsensitiveMatch(t *testing.T) {
+	t.Parallel()
+	job := &PulseDNSJob{
+		Host:       "example.com",
+		RecordType: "MX",
+		Expected:   []string{"Mail2.Example.com", "mail1.example.com"},
+		Timeout:    time.Second,
+		lookup:     stubLookup([]string{"mail1.example.com", "mail2.example.com."}, nil),
+	}
+
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Errorf("expected order-insensitive match, got %v", result.Err)
+	}
+}
+
+// TestPulseDNSJob_Execute_DistinguishableErrors tests that NXDOMAIN, timeout,
+// and mismatch each surface their own sentinel.
+func TestPulseDNSJob_Execute_DistinguishableErrors(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		records []string
+		err     error
+		want    error
+	}{
+		{
+			name: "nxdomain",
+			err:  &net.DNSError{Err: "no such host", Name: "missing.example.com", IsNotFound: true},
+			want: ErrDNSNotFound,
+		},
+		{
+			name: "timeout",
+			err:  &net.DNSError{Err: "i/o timeout", Name: "slow.example.com", IsTimeout: true},
+			want: ErrDNSTimeout,
+		},
+		{
+			name:    "mismatch",
+			records: []string{"192.0.2.99"},
+			want:    ErrDNSMismatch,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &PulseDNSJob{
+				Host:     "example.com",
+				Expected: []string{"192.0.2.10"},
+				Timeout:  time.Second,
+				lookup:   stubLookup(tc.records, tc.err),
+			}
+			result := job.Execute(context.Background())
+			if !errors.Is(result.Err, tc.want) {
+				t.Errorf("Err = %v, want %v", result.Err, tc.want)
+			}
+		})
+	}
+}
+
+// TestPulseDNSJob_Execute_RetriesTransientFailures tests that a failure
+// followed by a clean answer succeeds within the retry budget.
+func TestPulseDNSJob_Execute_RetriesTransientFailures(t *testing.T) {
+	t.Parallel()
+	failures := 1
+	job := &PulseDNSJob{
+		Host:    "example.com",
+		Timeout: time.Second,
+		Retries: 2,
+		lookup: func(context.Context, string, string) ([]string, error) {
+			if failures > 0 {
+				failures--
+				return nil, &net.DNSError{Err: "i/o timeout", IsTimeout: true, IsTemporary: true}
+			}
+			return []string{"192.0.2.10"}, nil
+		},
+	}
+
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Errorf("expected success after retry, got %v", result.Err)
+	}
+}
+
+// TestPulseDNSJob_DefaultsToARecords tests the record type default.
+func TestPulseDNSJob_DefaultsToARecords(t *testing.T) {
+	t.Parallel()
+	var gotType string
+	job := &PulseDNSJob{
+		Host:    "example.com",
+		Timeout: time.Second,
+		lookup: func(_ context.Context, recordType, _ string) ([]string, error) {
+			gotType = recordType
+			return []string{"192.0.2.10"}, nil
+		},
+	}
+
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("expected success, got %v", result.Err)
+	}
+	if gotType != "A" {
+		t.Errorf("record type = %q, want default A", gotType)
+	}
+}
+
+// TestCreatePulseJob_DNS tests the schema-to-job wiring for dns pulses.
+func TestCreatePulseJob_DNS(t *testing.T) {
+	t.Parallel()
+	pulse := schema.Pulse{
+		Type:    "dns",
+		Timeout: 2 * time.Second,
+		Config: &schema.PulseDNSConfig{
+			Host:       "example.com",
+			RecordType: "cname",
+			Expected:   schema.StringList{"edge.example.net"},
+			Resolver:   "192.0.2.53",
+			Retries:    1,
+		},
+	}
+
+	job, err := CreatePulseJob(pulse, ecs.Entity{})
+	if err != nil {
+		t.Fatalf("CreatePulseJob failed: %v", err)
+	}
+	dnsJob, ok := job.(*PulseDNSJob)
+	if !ok {
+		t.Fatalf("Expected *PulseDNSJob, got %T", job)
+	}
+	defer ReleasePulseJob(dnsJob)
+
+	if dnsJob.RecordType != "CNAME" {
+		t.Errorf("RecordType = %q, want CNAME", dnsJob.RecordType)
+	}
+	if dnsJob.Resolver != "192.0.2.53" || dnsJob.Retries != 1 || dnsJob.Timeout != 2*time.Second {
+		t.Errorf("Config not wired: %+v", dnsJob)
+	}
+	if len(dnsJob.Expected) != 1 || dnsJob.Expected[0] != "edge.example.net" {
+		t.Errorf("Expected = %v", dnsJob.Expected)
+	}
+	if dnsJob.JobType != InternedPulse || dnsJob.Driver != InternedDNS {
+		t.Errorf("JobType/Driver = %q/%q", dnsJob.JobType, dnsJob.Driver)
+	}
+}
@@ -0,0 +1,131 @@
+//go:build !nodocker
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// PulseDockerJob checks the health of a Docker container by inspecting it.
+// A missing container, any non-running state (exited, restarting, paused,
+// dead), or - when RequireHealthy is set - an unhealthy healthcheck maps to
+// failure with the observed state in the payload.
+//
+// Safety features:
+//   - Uses the pooled Docker client (not created per-call)
+//   - Fails fast when the daemon monitor reports the endpoint down
+//   - Applies the job Timeout per attempt via context
+//   - Fresh payload per execution (the state strings escape in the Result)
+type PulseDockerJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Container   string
+	DockerHost  string
+	JobType     string
+	Driver      string
+	// RequireHealthy additionally requires a passing healthcheck
+	// (require_status: healthy), not just a running state.
+	RequireHealthy bool
+	Timeout        time.Duration
+	Retries        int
+	Entity         ecs.Entity
+	// Group is the concurrency group for per-group in-flight limits.
+	Group string
+}
+
+// Execute inspects the Docker container with retries.
+func (p *PulseDockerJob) Execute(ctx context.Context) Result {
+	payload := Payload{Kind: KindPulse, Driver: InternedDocker}
+
+	cli, err := GetDockerClient(p.DockerHost)
+	if err != nil {
+		return Result{Ent: p.Entity, Err: fmt.Errorf("%w: %w", ErrFailedToCreateDockerClient, err), Payload: payload}
+	}
+	if err := CheckDockerDaemon(p.DockerHost); err != nil {
+		return Result{Ent: p.Entity, Err: err, Payload: payload}
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	var state, health string
+	start := time.Now()
+	err = RetryWithBackoff(ctx, p.Retries+1, 50*time.Millisecond, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		resp, inspectErr := cli.ContainerInspect(attemptCtx, p.Container)
+		if inspectErr != nil {
+			if cerrdefs.IsNotFound(inspectErr) {
+				state = "not_found"
+				return ErrDockerContainerNotFound
+			}
+			return inspectErr
+		}
+		if resp.ContainerJSONBase == nil || resp.State == nil {
+			state = "unknown"
+			return ErrDockerContainerState
+		}
+		state = string(resp.State.Status)
+		if resp.State.Health != nil {
+			health = string(resp.State.Health.Status)
+		}
+		// Retrying a non-running state gives restarting containers a chance
+		// to come back within the check's retry budget.
+		if !resp.State.Running {
+			return ErrDockerContainerState
+		}
+		if p.RequireHealthy && health != "healthy" {
+			return ErrDockerContainerState
+		}
+		return nil
+	})
+
+	elapsed := time.Since(start)
+	if state != "" {
+		payload.SetExtra("state", state)
+	}
+	if health != "" {
+		payload.SetExtra("health", health)
+	}
+
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		if errors.Is(err, ErrDockerContainerNotFound) || errors.Is(err, ErrDockerContainerState) {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		return Result{Ent: p.Entity, Err: ErrDockerCheckFailed, Payload: payload, Duration: elapsed, Class: InfraErrorClass(err)}
+	}
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (p *PulseDockerJob) Copy() Job { job := *p; return &job }
+
+// ConcurrencyGroup returns the job's group for the group limiter.
+func (p *PulseDockerJob) ConcurrencyGroup() string { return p.Group }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (p *PulseDockerJob) GetEnqueueTime() time.Time { return p.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (p *PulseDockerJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (p *PulseDockerJob) GetStartTime() time.Time { return p.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (p *PulseDockerJob) SetStartTime(t time.Time) { p.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (p *PulseDockerJob) IsNil() bool { return p == nil }
@@ -0,0 +1,36 @@
+//go:build nodocker
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// PulseDockerJob is a stub when Docker is disabled.
+type PulseDockerJob struct {
+	EnqueueTime    time.Time
+	StartTime      time.Time
+	Container      string
+	DockerHost     string
+	JobType        string
+	Driver         string
+	RequireHealthy bool
+	Timeout        time.Duration
+	Retries        int
+	Entity         ecs.Entity
+	Group          string
+}
+
+func (p *PulseDockerJob) Execute(ctx context.Context) Result {
+	return Result{Ent: p.Entity, Err: ErrDockerDisabled, Payload: Payload{Kind: KindPulse, Driver: InternedDocker}}
+}
+func (p *PulseDockerJob) Copy() Job                  { job := *p; return &job }
+func (p *PulseDockerJob) ConcurrencyGroup() string   { return p.Group }
+func (p *PulseDockerJob) GetEnqueueTime() time.Time  { return p.EnqueueTime }
+func (p *PulseDockerJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+func (p *PulseDockerJob) GetStartTime() time.Time    { return p.StartTime }
+func (p *PulseDockerJob) SetStartTime(t time.Time)   { p.StartTime = t }
+func (p *PulseDockerJob) IsNil() bool                { return p == nil }
@@ -0,0 +1,133 @@
+//go:build !nodocker
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startInspectDaemon serves the minimal Docker API surface needed by the
+// container health pulse: the version-negotiation ping plus inspect responses
+// for the given containers (name -> inspect JSON). Unknown containers get the
+// daemon's 404 shape. Returns the docker host URL for GetDockerClient.
+func startInspectDaemon(t *testing.T, containers map[string]string) string {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			w.Header().Set("Api-Version", "1.43")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/json"):
+			parts := strings.Split(r.URL.Path, "/")
+			name := parts[len(parts)-2]
+			body, ok := containers[name]
+			if !ok {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"No such container: ` + name + `"}`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := &http.Server{Handler: mux}
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	host := "tcp://" + ln.Addr().String()
+	t.Cleanup(func() {
+		if m, ok := dockerDaemonMonitors.Load(host); ok {
+			m.(*dockerDaemonMonitor).stopMonitor()
+		}
+	})
+	return host
+}
+
+func TestPulseDockerJob_Execute_Running(t *testing.T) {
+	t.Parallel()
+	host := startInspectDaemon(t, map[string]string{
+		"web": `{"Id":"abc","Name":"/web","State":{"Status":"running","Running":true}}`,
+	})
+
+	job := &PulseDockerJob{Container: "web", DockerHost: host, Timeout: 2 * time.Second}
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("expected nil error, got %v", result.Err)
+	}
+	if state := result.Payload.Extra("state"); state != "running" {
+		t.Errorf("state = %v, want running", state)
+	}
+}
+
+func TestPulseDockerJob_Execute_Exited(t *testing.T) {
+	t.Parallel()
+	host := startInspectDaemon(t, map[string]string{
+		"worker": `{"Id":"def","Name":"/worker","State":{"Status":"exited","Running":false,"ExitCode":137}}`,
+	})
+
+	job := &PulseDockerJob{Container: "worker", DockerHost: host, Timeout: 2 * time.Second}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrDockerContainerState) {
+		t.Fatalf("expected ErrDockerContainerState, got %v", result.Err)
+	}
+	if state := result.Payload.Extra("state"); state != "exited" {
+		t.Errorf("state = %v, want exited", state)
+	}
+}
+
+func TestPulseDockerJob_Execute_RequireHealthy(t *testing.T) {
+	t.Parallel()
+	host := startInspectDaemon(t, map[string]string{
+		"db":    `{"Id":"ghi","Name":"/db","State":{"Status":"running","Running":true,"Health":{"Status":"healthy"}}}`,
+		"cache": `{"Id":"jkl","Name":"/cache","State":{"Status":"running","Running":true,"Health":{"Status":"unhealthy"}}}`,
+	})
+
+	job := &PulseDockerJob{Container: "db", DockerHost: host, RequireHealthy: true, Timeout: 2 * time.Second}
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("expected healthy container to pass, got %v", result.Err)
+	}
+
+	// A running but unhealthy container fails the stricter check, with both
+	// the state and health strings recorded for the payload.
+	job = &PulseDockerJob{Container: "cache", DockerHost: host, RequireHealthy: true, Timeout: 2 * time.Second}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrDockerContainerState) {
+		t.Fatalf("expected ErrDockerContainerState, got %v", result.Err)
+	}
+	if health := result.Payload.Extra("health"); health != "unhealthy" {
+		t.Errorf("health = %v, want unhealthy", health)
+	}
+	if state := result.Payload.Extra("state"); state != "running" {
+		t.Errorf("state = %v, want running", state)
+	}
+}
+
+func TestPulseDockerJob_Execute_NotFound(t *testing.T) {
+	t.Parallel()
+	host := startInspectDaemon(t, map[string]string{})
+
+	job := &PulseDockerJob{Container: "ghost", DockerHost: host, Timeout: 2 * time.Second}
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrDockerContainerNotFound) {
+		t.Fatalf("expected ErrDockerContainerNotFound, got %v", result.Err)
+	}
+	if state := result.Payload.Extra("state"); state != "not_found" {
+		t.Errorf("state = %v, want not_found", state)
+	}
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// PulseGRPCJob performs gRPC health checks via the standard
+// grpc.health.v1.Health/Check RPC. SERVING maps to success; any other
+// response status, dial error, or deadline maps to failure with the status
+// string in the payload.
+//
+// Safety features:
+//   - Uses global dial limiter to prevent CPU spikes during outages
+//   - Shares one multiplexed connection per target via grpcConns
+//   - Applies the job Timeout per attempt via context
+//   - Fresh payload per execution (the status string escapes in the Result)
+type PulseGRPCJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Target      string
+	Service     string
+	JobType     string
+	Driver      string
+	TLS         bool
+	Timeout     time.Duration
+	Retries     int
+	Entity      ecs.Entity
+	// Group is the concurrency group for per-group in-flight limits.
+	Group string
+}
+
+// Execute performs the gRPC health check with retries.
+func (p *PulseGRPCJob) Execute(ctx context.Context) Result {
+	payload := Payload{Kind: KindPulse, Driver: InternedGRPC}
+
+	// Use global dial limiter to prevent CPU spikes during mass failures.
+	if !GetDialLimiter().Acquire(ctx) {
+		return Result{Ent: p.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer GetDialLimiter().Release()
+
+	conn, err := grpcConns.Get(p.Target, p.TLS)
+	if err != nil {
+		payload.SetExtra("status", "INVALID_TARGET")
+		return Result{Ent: p.Entity, Err: ErrGRPCCheckFailed, Payload: payload, Class: InfraErrorClass(err)}
+	}
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	var lastStatus string
+	start := time.Now()
+	err = RetryWithBackoff(ctx, p.Retries+1, 50*time.Millisecond, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		resp, rpcErr := client.Check(attemptCtx, &grpc_health_v1.HealthCheckRequest{Service: p.Service})
+		if rpcErr != nil {
+			// Record the gRPC status code (Unavailable, DeadlineExceeded,
+			// NotFound for unregistered services, ...) for the payload.
+			lastStatus = status.Code(rpcErr).String()
+			return rpcErr
+		}
+		lastStatus = resp.Status.String()
+		if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			return ErrGRPCNotServing
+		}
+		return nil
+	})
+
+	elapsed := time.Since(start)
+	payload.SetExtra("status", lastStatus)
+
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		return Result{Ent: p.Entity, Err: ErrGRPCCheckFailed, Payload: payload, Duration: elapsed, Class: InfraErrorClass(err)}
+	}
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (p *PulseGRPCJob) Copy() Job { job := *p; return &job }
+
+// ConcurrencyGroup returns the job's group for the group limiter.
+func (p *PulseGRPCJob) ConcurrencyGroup() string { return p.Group }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (p *PulseGRPCJob) GetEnqueueTime() time.Time { return p.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (p *PulseGRPCJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (p *PulseGRPCJob) GetStartTime() time.Time { return p.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (p *PulseGRPCJob) SetStartTime(t time.Time) { p.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (p *PulseGRPCJob) IsNil() bool { return p == nil }
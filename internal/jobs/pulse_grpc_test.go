@@ -0,0 +1,180 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startHealthServer runs an in-process gRPC server with the standard health
+// service on a loopback port and returns its target address.
+func startHealthServer(t *testing.T) (string, *health.Server) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	healthSrv := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(srv, healthSrv)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), healthSrv
+}
+
+// TestPulseGRPCJob_Execute_Serving tests a SERVING response mapping to success.
+func TestPulseGRPCJob_Execute_Serving(t *testing.T) {
+	t.Parallel()
+	target, _ := startHealthServer(t)
+	job := &PulseGRPCJob{
+		Target:  target,
+		Timeout: 2 * time.Second,
+	}
+
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("expected success, got %v (status %v)", result.Err, result.Payload.Extra("status"))
+	}
+	if result.Payload.Extra("status") != "SERVING" {
+		t.Errorf("payload status = %v, want SERVING", result.Payload.Extra("status"))
+	}
+	if result.Payload.Driver != "grpc" {
+		t.Errorf("payload driver = %v, want grpc", result.Payload.Driver)
+	}
+}
+
+// TestPulseGRPCJob_Execute_NotServing tests NOT_SERVING mapping to failure
+// with the status string preserved in the payload.
+func TestPulseGRPCJob_Execute_NotServing(t *testing.T) {
+	t.Parallel()
+	target, healthSrv := startHealthServer(t)
+	healthSrv.SetServingStatus("billing.Service", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	job := &PulseGRPCJob{
+		Target:  target,
+		Service: "billing.Service",
+		Timeout: 2 * time.Second,
+	}
+
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrGRPCCheckFailed) {
+		t.Fatalf("Err = %v, want ErrGRPCCheckFailed", result.Err)
+	}
+	if result.Payload.Extra("status") != "NOT_SERVING" {
+		t.Errorf("payload status = %v, want NOT_SERVING", result.Payload.Extra("status"))
+	}
+}
+
+// TestPulseGRPCJob_Execute_UnknownService tests that an unregistered service
+// fails with the gRPC code in the payload.
+func TestPulseGRPCJob_Execute_UnknownService(t *testing.T) {
+	t.Parallel()
+	target, _ := startHealthServer(t)
+	job := &PulseGRPCJob{
+		Target:  target,
+		Service: "no.Such.Service",
+		Timeout: 2 * time.Second,
+	}
+
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrGRPCCheckFailed) {
+		t.Fatalf("Err = %v, want ErrGRPCCheckFailed", result.Err)
+	}
+	if result.Payload.Extra("status") != "NotFound" {
+		t.Errorf("payload status = %v, want NotFound", result.Payload.Extra("status"))
+	}
+}
+
+// TestPulseGRPCJob_Execute_DialFailure tests an unreachable target failing
+// within the job timeout rather than hanging.
+func TestPulseGRPCJob_Execute_DialFailure(t *testing.T) {
+	t.Parallel()
+	// Reserve a port, then close it so nothing is listening.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	target := lis.Addr().String()
+	lis.Close()
+
+	job := &PulseGRPCJob{
+		Target:  target,
+		Timeout: 500 * time.Millisecond,
+	}
+
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrGRPCCheckFailed) {
+		t.Fatalf("Err = %v, want ErrGRPCCheckFailed", result.Err)
+	}
+	if result.Payload.Extra("status") == nil || result.Payload.Extra("status") == "" {
+		t.Errorf("payload status = %v, want a gRPC code string", result.Payload.Extra("status"))
+	}
+}
+
+// TestGRPCConnPool_SharesConnections tests that repeated lookups of the same
+// target reuse one connection and TLS targets get a separate one.
+func TestGRPCConnPool_SharesConnections(t *testing.T) {
+	t.Parallel()
+	pool := &GRPCConnPool{}
+	first, err := pool.Get("conn-pool.example.com:443", false)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	second, err := pool.Get("conn-pool.example.com:443", false)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if first != second {
+		t.Error("expected the same connection for repeated lookups of one target")
+	}
+	tlsConn, err := pool.Get("conn-pool.example.com:443", true)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if tlsConn == first {
+		t.Error("expected a distinct connection for the TLS variant of a target")
+	}
+}
+
+// TestCreatePulseJob_GRPC tests the schema-to-job wiring for grpc pulses.
+func TestCreatePulseJob_GRPC(t *testing.T) {
+	t.Parallel()
+	pulse := schema.Pulse{
+		Type:    "grpc",
+		Timeout: 2 * time.Second,
+		Config: &schema.PulseGRPCConfig{
+			Target:  "api.example.com:50051",
+			Service: "billing.Service",
+			TLS:     true,
+			Retries: 1,
+		},
+	}
+
+	job, err := CreatePulseJob(pulse, ecs.Entity{})
+	if err != nil {
+		t.Fatalf("CreatePulseJob failed: %v", err)
+	}
+	grpcJob, ok := job.(*PulseGRPCJob)
+	if !ok {
+		t.Fatalf("Expected *PulseGRPCJob, got %T", job)
+	}
+	defer ReleasePulseJob(grpcJob)
+
+	if grpcJob.Target != "api.example.com:50051" || grpcJob.Service != "billing.Service" {
+		t.Errorf("Target/Service = %q/%q", grpcJob.Target, grpcJob.Service)
+	}
+	if !grpcJob.TLS || grpcJob.Retries != 1 || grpcJob.Timeout != 2*time.Second {
+		t.Errorf("Config not wired: %+v", grpcJob)
+	}
+	if grpcJob.JobType != InternedPulse || grpcJob.Driver != InternedGRPC {
+		t.Errorf("JobType/Driver = %q/%q", grpcJob.JobType, grpcJob.Driver)
+	}
+}
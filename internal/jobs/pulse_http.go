@@ -0,0 +1,172 @@
+package jobs
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+	"github.com/valyala/fasthttp"
+
+	"cpra/internal/loader/schema"
+)
+
+// PulseHTTPJob performs HTTP health checks using fasthttp for high performance.
+// It supports configurable methods, timeouts, and retry logic.
+//
+// Safety features:
+//   - Uses global dial limiter to prevent CPU spikes during outages
+//   - Checks context cancellation before each retry
+//   - Uses pre-allocated payloads to reduce allocations
+//   - Uses fasthttp request/response pools
+type PulseHTTPJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	URL         string
+	Method      string
+	JobType     string
+	Driver      string
+	Timeout     time.Duration
+	Retries     int
+	Entity      ecs.Entity
+	// Host and IsTLS are pre-computed for fasthttp client selection
+	Host  string
+	IsTLS bool
+	// Proxy routes the check through an HTTP, HTTPS, or SOCKS5 proxy.
+	// Resolved at job creation from the monitor config and the controller
+	// default, with NoProxy bypass already applied; empty dials directly.
+	Proxy string
+	// Headers are applied to every attempt; auth resolves into an
+	// Authorization entry at job creation (see factory.go).
+	Headers []HTTPHeader
+	// ExpectedStatus overrides the 2xx health check; nil keeps the default.
+	ExpectedStatus schema.StatusRanges
+	// BodyContains and BodyRegex assert on the response body, capped at
+	// maxBodyCheckBytes. Both compiled/validated at job creation.
+	BodyContains string
+	BodyRegex    *regexp.Regexp
+	// DetailedTiming switches to the traced net/http path (see
+	// pulse_http_detailed.go) to record per-phase latencies.
+	DetailedTiming    bool
+	DegradedThreshold time.Duration
+	DegradedPhase     string
+	// Backoff controls retry delays; the zero value keeps the historical
+	// 50ms-base exponential behavior.
+	Backoff BackoffPolicy
+	// Group is the concurrency group for per-group in-flight limits.
+	Group string
+	// TraceCtx, when non-nil, carries the dispatch-opened trace span for
+	// this check; queue wait and attempts record as children (see tracing.go).
+	TraceCtx context.Context
+}
+
+// SetTraceContext stores the dispatch-opened trace context (TraceCarrier).
+func (p *PulseHTTPJob) SetTraceContext(ctx context.Context) { p.TraceCtx = ctx }
+
+// TraceContext returns the current dispatch's trace context, nil if untraced.
+func (p *PulseHTTPJob) TraceContext() context.Context { return p.TraceCtx }
+
+// Execute performs the HTTP health check with retries.
+func (p *PulseHTTPJob) Execute(ctx context.Context) Result {
+	if p.DetailedTiming {
+		return p.executeDetailed(ctx)
+	}
+
+	payload := GetPulseHTTPPayload()
+
+	// Acquire global dial slot to prevent CPU spikes during network outages.
+	if !AcquireHTTPDialSlot(ctx) {
+		return Result{Ent: p.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer ReleaseHTTPDialSlot()
+
+	// Get the fasthttp client for this host (cached per host and proxy)
+	client := fasthttpClients.Get(p.Host, p.IsTLS, p.Proxy)
+
+	// Acquire request/response from pool
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	// Set up the request once (reused across retries)
+	req.SetRequestURI(p.URL)
+	req.Header.SetMethod(p.Method)
+	for _, header := range p.Headers {
+		// Add keeps duplicate names as separate lines (RFC-equivalent to a
+		// comma join); fasthttp special-cases Host (and friends) inside it.
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	start := time.Now()
+	if p.TraceCtx != nil {
+		traceQueueWait(p.TraceCtx, p.EnqueueTime, start)
+	}
+	var attemptLatency time.Duration
+	var attempt int
+	var rateLimited *rateLimitError
+	stats, err := RetryWithPolicy(ctx, p.Retries+1, p.Backoff, p.Timeout, func() (retErr error) {
+		attempt++
+		span := startAttempt(p.TraceCtx, attempt)
+		defer func() { endAttempt(span, retErr) }()
+		attemptStart := time.Now()
+		defer func() { attemptLatency = time.Since(attemptStart) }()
+		resp.Reset()
+		if httpErr := client.DoTimeout(req, resp, p.Timeout); httpErr != nil {
+			return httpErr
+		}
+		if !p.ExpectedStatus.Match(resp.StatusCode()) {
+			// Rate limiting ends the loop immediately: retrying against a
+			// limiter only digs the hole deeper.
+			if rle := rateLimitResponse(resp.StatusCode(), string(resp.Header.Peek("Retry-After"))); rle != nil {
+				rateLimited = rle
+				return nil
+			}
+			return ErrStatusAssertion // Unexpected status triggers retry
+		}
+		return p.checkBody(resp.Body())
+	})
+	elapsed := time.Since(start)
+	stampRetryStats(&payload, stats)
+	stampAttemptLatency(&payload, stats, attemptLatency)
+
+	if err == nil && rateLimited != nil {
+		stampRateLimit(&payload, rateLimited)
+		return Result{Ent: p.Entity, Err: ErrRateLimited, Payload: payload, Duration: elapsed}
+	}
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		if reason := assertionReason(err); reason != "" {
+			payload.SetExtra("failed_assertion", reason)
+		}
+		return Result{Ent: p.Entity, Err: ErrHTTPCheckFailed, Payload: payload, Duration: elapsed, Class: InfraErrorClass(err)}
+	}
+	// Total-latency degradation works without tracing.
+	if p.DegradedThreshold > 0 && elapsed > p.DegradedThreshold {
+		markDegraded(&payload, "total", p.DegradedThreshold, elapsed, nil)
+	}
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (p *PulseHTTPJob) Copy() Job { job := *p; return &job }
+
+// ConcurrencyGroup returns the job's group for the group limiter.
+func (p *PulseHTTPJob) ConcurrencyGroup() string { return p.Group }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (p *PulseHTTPJob) GetEnqueueTime() time.Time { return p.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (p *PulseHTTPJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (p *PulseHTTPJob) GetStartTime() time.Time { return p.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (p *PulseHTTPJob) SetStartTime(t time.Time) { p.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (p *PulseHTTPJob) IsNil() bool { return p == nil }
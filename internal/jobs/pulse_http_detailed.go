@@ -0,0 +1,202 @@
+package jobs
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Detailed-timing HTTP checks run through net/http instead of fasthttp:
+// fasthttp has no httptrace support, and the per-request trace overhead is
+// exactly what detailed_timing opts into. The fasthttp fast path stays
+// untouched for the 1M-monitor common case.
+
+// detailedClients holds the shared clients for detailed-timing checks,
+// keyed by proxy URL (empty for direct dials).
+var detailedClients sync.Map // map[string]*http.Client
+
+// getDetailedHTTPClient returns the shared client for detailed-timing checks.
+// One transport is shared per proxy so connection reuse across checks is
+// real, reported honestly via the Reused flag, and proxied and direct
+// connections never mix.
+func getDetailedHTTPClient(proxy string) *http.Client {
+	if v, ok := detailedClients.Load(proxy); ok {
+		return v.(*http.Client)
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			return GetDialMetrics().ObserveDial(conn, err), err
+		},
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	if proxy != "" {
+		// The loader validates proxy URLs at load time; a parse failure
+		// here leaves the transport dialing directly.
+		if proxyURL, err := url.Parse(proxy); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	actual, _ := detailedClients.LoadOrStore(proxy, &http.Client{Transport: transport})
+	return actual.(*http.Client)
+}
+
+// executeDetailed performs the HTTP check with per-phase latency tracing.
+// The breakdown of the last attempt lands in the payload under "phases".
+func (p *PulseHTTPJob) executeDetailed(ctx context.Context) Result {
+	payload := GetPulseHTTPPayload()
+
+	if !AcquireHTTPDialSlot(ctx) {
+		return Result{Ent: p.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer ReleaseHTTPDialSlot()
+
+	client := getDetailedHTTPClient(p.Proxy)
+
+	var timings PhaseTimings
+	var total time.Duration
+	var attempts int
+	start := time.Now()
+	if p.TraceCtx != nil {
+		traceQueueWait(p.TraceCtx, p.EnqueueTime, start)
+	}
+	var rateLimited *rateLimitError
+	stats, err := RetryWithPolicy(ctx, p.Retries+1, p.Backoff, p.Timeout, func() (retErr error) {
+		attempts++
+		span := startAttempt(p.TraceCtx, attempts)
+		defer func() { endAttempt(span, retErr) }()
+		t, attempt, traceErr := p.doTracedRequest(ctx, client)
+		timings = t // keep the last attempt's breakdown
+		total = attempt
+		// Rate limiting ends the loop immediately: retrying against a
+		// limiter only digs the hole deeper.
+		if rle, ok := traceErr.(*rateLimitError); ok {
+			rateLimited = rle
+			return nil
+		}
+		return traceErr
+	})
+	elapsed := time.Since(start)
+
+	payload.SetExtra("phases", timings)
+	payload.SetExtra("reused", timings.Reused)
+	stampRetryStats(&payload, stats)
+	stampAttemptLatency(&payload, stats, total)
+
+	if err == nil && rateLimited != nil {
+		stampRateLimit(&payload, rateLimited)
+		return Result{Ent: p.Entity, Err: ErrRateLimited, Payload: payload, Duration: elapsed}
+	}
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		if reason := assertionReason(err); reason != "" {
+			payload.SetExtra("failed_assertion", reason)
+		}
+		return Result{Ent: p.Entity, Err: ErrHTTPCheckFailed, Payload: payload, Duration: elapsed, Class: InfraErrorClass(err)}
+	}
+
+	markDegraded(&payload, p.DegradedPhase, p.DegradedThreshold, total, &timings)
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// doTracedRequest performs one traced request and returns its phase breakdown
+// and wall time.
+func (p *PulseHTTPJob) doTracedRequest(ctx context.Context, client *http.Client) (PhaseTimings, time.Duration, error) {
+	var (
+		timings      PhaseTimings
+		dnsStart     time.Time
+		connectStart time.Time
+		tlsStart     time.Time
+		wroteRequest time.Time
+		firstByte    time.Time
+	)
+
+	reqCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			timings.DNSMs = durationToMs(time.Since(dnsStart))
+		},
+		ConnectStart: func(string, string) { connectStart = time.Now() },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				timings.ConnectMs = durationToMs(time.Since(connectStart))
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				timings.TLSMs = durationToMs(time.Since(tlsStart))
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				timings.Reused = true
+				GetDialMetrics().RecordReusedConn()
+			}
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) { wroteRequest = time.Now() },
+		GotFirstResponseByte: func() {
+			firstByte = time.Now()
+			if !wroteRequest.IsZero() {
+				timings.TTFBMs = durationToMs(firstByte.Sub(wroteRequest))
+			}
+		},
+	}
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(reqCtx, trace), p.Method, p.URL, nil)
+	if err != nil {
+		return timings, time.Since(start), err
+	}
+	for _, header := range p.Headers {
+		// net/http sends the Host header from the request field, not the map.
+		if strings.EqualFold(header.Name, "Host") {
+			req.Host = header.Value
+			continue
+		}
+		// Add keeps duplicate names as separate lines, equivalent per RFC
+		// to joining their values with commas.
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return timings, time.Since(start), err
+	}
+	var body []byte
+	if p.BodyContains != "" || p.BodyRegex != nil {
+		// Read only what the assertions may inspect, then drain the rest so
+		// the connection is still reusable.
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxBodyCheckBytes))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if !firstByte.IsZero() {
+		timings.TransferMs = durationToMs(time.Since(firstByte))
+	}
+
+	if !p.ExpectedStatus.Match(resp.StatusCode) {
+		if rle := rateLimitResponse(resp.StatusCode, resp.Header.Get("Retry-After")); rle != nil {
+			return timings, time.Since(start), rle
+		}
+		return timings, time.Since(start), ErrStatusAssertion
+	}
+	return timings, time.Since(start), p.checkBody(body)
+}
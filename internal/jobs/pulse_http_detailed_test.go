@@ -0,0 +1,226 @@
+package jobs
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Detailed Timing (httptrace) Tests
+// =============================================================================
+
+func detailedHTTPJob(url string, timeout time.Duration) *PulseHTTPJob {
+	host, isTLS, _ := ExtractHostFromURL(url)
+	return &PulseHTTPJob{
+		URL:            url,
+		Method:         "GET",
+		Host:           host,
+		IsTLS:          isTLS,
+		Timeout:        timeout,
+		DetailedTiming: true,
+	}
+}
+
+// TestPulseHTTPJob_Detailed_PhasesInPayload verifies the traced path records a
+// phase breakdown on success.
+func TestPulseHTTPJob_Detailed_PhasesInPayload(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := detailedHTTPJob(server.URL, 5*time.Second)
+	result := job.Execute(context.Background())
+
+	if result.Err != nil {
+		t.Fatalf("expected success, got %v", result.Err)
+	}
+	timings, ok := result.Payload.Extra("phases").(PhaseTimings)
+	if !ok {
+		t.Fatalf("expected PhaseTimings in payload, got %T", result.Payload.Extra("phases"))
+	}
+	// Loopback checks quantize to 0ms; just verify the breakdown is present
+	// and internally consistent.
+	if timings.Reused {
+		t.Error("first request on a connection should not report reuse")
+	}
+	if _, ok := result.Payload.Extra("reused").(bool); !ok {
+		t.Error("expected reused flag in payload")
+	}
+}
+
+// TestPulseHTTPJob_Detailed_TTFB verifies a server that stalls before writing
+// headers shows up in the ttfb phase.
+func TestPulseHTTPJob_Detailed_TTFB(t *testing.T) {
+	t.Parallel()
+	const delay = 60 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := detailedHTTPJob(server.URL, 5*time.Second)
+	result := job.Execute(context.Background())
+
+	if result.Err != nil {
+		t.Fatalf("expected success, got %v", result.Err)
+	}
+	timings := result.Payload.Extra("phases").(PhaseTimings)
+	if timings.TTFBMs < uint16(delay.Milliseconds()/2) {
+		t.Errorf("expected ttfb to reflect %v server stall, got %dms", delay, timings.TTFBMs)
+	}
+}
+
+// TestPulseHTTPJob_Detailed_ConnectionReuse verifies a second check against
+// the same host reports reuse instead of fabricating handshake timings.
+func TestPulseHTTPJob_Detailed_ConnectionReuse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := detailedHTTPJob(server.URL, 5*time.Second)
+	first := job.Execute(context.Background())
+	if first.Err != nil {
+		t.Fatalf("first check failed: %v", first.Err)
+	}
+	second := job.Execute(context.Background())
+	if second.Err != nil {
+		t.Fatalf("second check failed: %v", second.Err)
+	}
+
+	timings := second.Payload.Extra("phases").(PhaseTimings)
+	if !timings.Reused {
+		t.Fatal("expected second check to reuse the idle connection")
+	}
+	if timings.DNSMs != 0 || timings.ConnectMs != 0 || timings.TLSMs != 0 {
+		t.Errorf("reused connection should report zero handshake phases, got dns=%d connect=%d tls=%d",
+			timings.DNSMs, timings.ConnectMs, timings.TLSMs)
+	}
+}
+
+// TestPulseHTTPJob_Detailed_DegradedPhase verifies a slow ttfb trips the
+// degraded marker when that phase is configured.
+func TestPulseHTTPJob_Detailed_DegradedPhase(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(60 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := detailedHTTPJob(server.URL, 5*time.Second)
+	job.DegradedThreshold = time.Millisecond
+	job.DegradedPhase = "ttfb"
+	result := job.Execute(context.Background())
+
+	if result.Err != nil {
+		t.Fatalf("expected success, got %v", result.Err)
+	}
+	if degraded, _ := result.Payload.Extra("degraded").(bool); !degraded {
+		t.Error("expected degraded marker for slow ttfb")
+	}
+	if phase, _ := result.Payload.Extra("degraded_phase").(string); phase != "ttfb" {
+		t.Errorf("expected degraded_phase=ttfb, got %q", phase)
+	}
+}
+
+// TestPulseHTTPJob_Detailed_NotDegraded verifies a fast check stays unmarked.
+func TestPulseHTTPJob_Detailed_NotDegraded(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := detailedHTTPJob(server.URL, 5*time.Second)
+	job.DegradedThreshold = 30 * time.Second
+	job.DegradedPhase = "total"
+	result := job.Execute(context.Background())
+
+	if result.Err != nil {
+		t.Fatalf("expected success, got %v", result.Err)
+	}
+	if result.Payload.Extra("degraded") != nil {
+		t.Error("fast check should not carry a degraded marker")
+	}
+}
+
+// TestPulseHTTPJob_FastPath_TotalDegraded verifies the fasthttp path still
+// supports total-latency degradation without tracing.
+func TestPulseHTTPJob_FastPath_TotalDegraded(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	host, isTLS, _ := ExtractHostFromURL(server.URL)
+	job := &PulseHTTPJob{
+		URL:               server.URL,
+		Method:            "GET",
+		Host:              host,
+		IsTLS:             isTLS,
+		Timeout:           5 * time.Second,
+		DegradedThreshold: time.Millisecond,
+	}
+	result := job.Execute(context.Background())
+
+	if result.Err != nil {
+		t.Fatalf("expected success, got %v", result.Err)
+	}
+	if degraded, _ := result.Payload.Extra("degraded").(bool); !degraded {
+		t.Error("expected degraded marker on slow total latency")
+	}
+	if result.Payload.Extra("phases") != nil {
+		t.Error("fast path should not record phase timings")
+	}
+}
+
+// TestPulseTCPJob_Detailed_ConnectPhase verifies TCP detailed timing records
+// the connect phase and supports total-latency degradation.
+func TestPulseTCPJob_Detailed_ConnectPhase(t *testing.T) {
+	t.Parallel()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	job := &PulseTCPJob{
+		Host:              addr.IP.String(),
+		Port:              addr.Port,
+		Timeout:           5 * time.Second,
+		DetailedTiming:    true,
+		DegradedThreshold: time.Nanosecond,
+		DegradedPhase:     "total", // loopback connects quantize to 0ms
+	}
+	result := job.Execute(context.Background())
+
+	if result.Err != nil {
+		t.Fatalf("expected success, got %v", result.Err)
+	}
+	if _, ok := result.Payload.Extra("phases").(PhaseTimings); !ok {
+		t.Fatalf("expected PhaseTimings in payload, got %T", result.Payload.Extra("phases"))
+	}
+	if degraded, _ := result.Payload.Extra("degraded").(bool); !degraded {
+		t.Error("expected degraded marker with nanosecond total threshold")
+	}
+}
@@ -0,0 +1,200 @@
+package jobs
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+	ping "github.com/prometheus-community/pro-bing"
+)
+
+// PulseICMPJob performs ICMP ping health checks.
+// It uses the pro-bing library for cross-platform ICMP support.
+//
+// Safety features:
+//   - Uses global dial limiter to prevent CPU spikes during outages
+//   - Creates fresh pinger each attempt (pro-bing is not reuse-safe)
+//   - Handles privilege escalation fallback on Linux
+type PulseICMPJob struct {
+	EnqueueTime     time.Time
+	StartTime       time.Time
+	Host            string
+	JobType         string
+	Driver          string
+	Timeout         time.Duration
+	Count           int
+	Retries         int
+	Entity          ecs.Entity
+	IgnorePrivilege bool
+	// Network pins resolution and pinging to one address family: "ip",
+	// "ip4", or "ip6", resolved from the monitor's ip_family at job
+	// creation. Empty resolves "ip" (whatever the OS returns first).
+	Network string
+	// Backoff controls retry delays; the zero value keeps the historical
+	// 50ms-base exponential behavior.
+	Backoff BackoffPolicy
+	// Group is the concurrency group for per-group in-flight limits.
+	Group string
+	// TraceCtx, when non-nil, carries the dispatch-opened trace span for
+	// this check; queue wait and attempts record as children (see tracing.go).
+	TraceCtx context.Context
+}
+
+// SetTraceContext stores the dispatch-opened trace context (TraceCarrier).
+func (p *PulseICMPJob) SetTraceContext(ctx context.Context) { p.TraceCtx = ctx }
+
+// TraceContext returns the current dispatch's trace context, nil if untraced.
+func (p *PulseICMPJob) TraceContext() context.Context { return p.TraceCtx }
+
+// Execute performs the ICMP ping check with retries.
+func (p *PulseICMPJob) Execute(ctx context.Context) Result {
+	payload := Payload{Kind: KindPulse, Driver: InternedICMP}
+
+	// Use global dial limiter to prevent CPU spikes during mass failures.
+	if !GetDialLimiter().Acquire(ctx) {
+		return Result{Ent: p.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer GetDialLimiter().Release()
+
+	count := p.Count
+	if count <= 0 {
+		count = 1
+	}
+	payload.SetExtra("count", count)
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = time.Duration(count)*time.Second + 500*time.Millisecond
+	}
+
+	var privilegeIgnored bool
+	var attemptLatency time.Duration
+	var resolvedAddr string
+	var lastRTT time.Duration
+
+	start := time.Now()
+	if p.TraceCtx != nil {
+		traceQueueWait(p.TraceCtx, p.EnqueueTime, start)
+	}
+	var attempt int
+	stats, err := RetryWithPolicy(ctx, p.Retries+1, p.Backoff, p.Timeout, func() (retErr error) {
+		attempt++
+		span := startAttempt(p.TraceCtx, attempt)
+		defer func() { endAttempt(span, retErr) }()
+		attemptStart := time.Now()
+		defer func() { attemptLatency = time.Since(attemptStart) }()
+		// Create a fresh pinger each attempt - pro-bing Pinger is not safe
+		// for reuse. The network must be set before resolution so ip_family
+		// pins which address of a dual-stacked host gets pinged.
+		pr := ping.New(p.Host)
+		if p.Network != "" {
+			pr.SetNetwork(p.Network)
+		}
+		if pingerErr := pr.Resolve(); pingerErr != nil {
+			return pingerErr
+		}
+		if addr := pr.IPAddr(); addr != nil {
+			resolvedAddr = addr.String()
+		}
+
+		// Default privilege: Linux unprivileged, others privileged
+		if runtime.GOOS == "linux" {
+			pr.SetPrivileged(false)
+		} else {
+			pr.SetPrivileged(true)
+		}
+
+		pr.Count = count
+		pr.Timeout = timeout
+
+		// RunWithContext lets pool shutdown or the job deadline abort an
+		// in-flight ping instead of waiting out the full pinger timeout.
+		runErr := pr.RunWithContext(ctx)
+		if runErr == nil {
+			if stats := pr.Statistics(); stats != nil && stats.PacketsRecv > 0 {
+				lastRTT = stats.AvgRtt
+				return nil // Success
+			}
+			return ErrICMPCheckFailed // No packets received
+		}
+
+		// Privilege fallback for Linux
+		if !pr.Privileged() && isPrivilegeError(runErr) {
+			pr.SetPrivileged(true)
+			privilegedErr := pr.RunWithContext(ctx)
+			if privilegedErr == nil {
+				if stats := pr.Statistics(); stats != nil && stats.PacketsRecv > 0 {
+					lastRTT = stats.AvgRtt
+					return nil // Success with elevated privilege
+				}
+				return ErrICMPCheckFailed // No packets received
+			}
+			if p.IgnorePrivilege && isPrivilegeError(privilegedErr) {
+				privilegeIgnored = true
+				return nil // Ignore privilege error
+			}
+			return privilegedErr
+		}
+
+		if p.IgnorePrivilege && isPrivilegeError(runErr) {
+			privilegeIgnored = true
+			return nil // Ignore privilege error
+		}
+		return runErr
+	})
+
+	elapsed := time.Since(start)
+	recordICMPExecution(p.Host, lastRTT, err != nil)
+	if privilegeIgnored {
+		payload.SetExtra("privilege_ignored", true)
+	}
+	stampRetryStats(&payload, stats)
+	stampAttemptLatency(&payload, stats, attemptLatency)
+
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		// Record which address was pinged, so dual-stack monitors pinned to
+		// one family can tell v4 and v6 failures apart.
+		if resolvedAddr != "" {
+			payload.SetExtra("resolved_addr", resolvedAddr)
+		}
+		return Result{Ent: p.Entity, Err: ErrICMPCheckFailed, Payload: payload, Duration: elapsed}
+	}
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// isPrivilegeError checks common privilege-related error strings from pinger.
+func isPrivilegeError(err error) bool {
+	if err == nil {
+		return false
+	}
+	lower := strings.ToLower(err.Error())
+	return strings.Contains(lower, "operation not permitted") ||
+		strings.Contains(lower, "permission denied") ||
+		strings.Contains(lower, "privilege")
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (p *PulseICMPJob) Copy() Job { job := *p; return &job }
+
+// ConcurrencyGroup returns the job's group for the group limiter.
+func (p *PulseICMPJob) ConcurrencyGroup() string { return p.Group }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (p *PulseICMPJob) GetEnqueueTime() time.Time { return p.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (p *PulseICMPJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (p *PulseICMPJob) GetStartTime() time.Time { return p.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (p *PulseICMPJob) SetStartTime(t time.Time) { p.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (p *PulseICMPJob) IsNil() bool { return p == nil }
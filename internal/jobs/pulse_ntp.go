@@ -0,0 +1,292 @@
+package jobs
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// SNTP protocol constants (RFC 4330). The client sends a 48-byte mode-3
+// request and reads the server's receive/transmit timestamps from the reply.
+const (
+	ntpPacketSize = 48
+	// ntpEpochOffset is the difference between the NTP epoch (1900) and the
+	// Unix epoch (1970) in seconds.
+	ntpEpochOffset = 2208988800
+	// ntpDefaultPort is appended to server addresses without an explicit port.
+	ntpDefaultPort = "123"
+)
+
+// errNTPKissOfDeath marks a Kiss-of-Death reply (stratum 0). The offending
+// server is rotated out of the candidate list for the rest of the check.
+var errNTPKissOfDeath = errors.New("ntp kiss-of-death")
+
+// PulseNTPJob performs SNTP clock-drift checks against one or more servers.
+// It samples each server in rotation, computes the median offset, and fails
+// when the offset exceeds MaxOffset or no server responds.
+//
+// Safety features:
+//   - Uses global dial limiter to prevent CPU spikes during outages
+//   - Respects the pulse timeout as a budget across all samples
+//   - Rotates out servers that answer with a Kiss-of-Death packet
+//   - Fresh payload per execution (cannot pool - escapes to Result)
+//
+// Note: NTP payloads are NOT pooled because the payload map carries
+// per-server offset/delay metadata and escapes in the Result struct.
+type PulseNTPJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	JobType     string
+	Driver      string
+	Servers     []string
+	MaxOffset   time.Duration
+	Samples     int
+	Timeout     time.Duration
+	Retries     int
+	Entity      ecs.Entity
+	// Group is the concurrency group for per-group in-flight limits.
+	Group string
+}
+
+// Execute performs the SNTP offset check across all configured servers.
+func (p *PulseNTPJob) Execute(ctx context.Context) Result {
+	payload := Payload{Kind: KindPulse, Driver: InternedNTP}
+
+	// Use global dial limiter to prevent CPU spikes during mass failures.
+	if !GetDialLimiter().Acquire(ctx) {
+		return Result{Ent: p.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer GetDialLimiter().Release()
+
+	samples := p.Samples
+	if samples <= 0 {
+		samples = 1
+	}
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	// The pulse timeout is a budget across all samples, not per query.
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	servers := make([]string, len(p.Servers))
+	copy(servers, p.Servers)
+
+	serverStats := make(map[string]interface{}, len(servers))
+	payload.SetExtra("servers", serverStats)
+
+	var offsets []time.Duration
+	for i := 0; i < samples && len(servers) > 0; i++ {
+		if ctx.Err() != nil || !time.Now().Before(deadline) {
+			break
+		}
+		server := servers[i%len(servers)]
+
+		var offset, delay time.Duration
+		err := RetryWithBackoff(ctx, p.Retries+1, 50*time.Millisecond, func() error {
+			var queryErr error
+			offset, delay, queryErr = queryNTP(ctx, server, deadline)
+			return queryErr
+		})
+
+		if err != nil {
+			serverStats[server] = map[string]interface{}{"error": err.Error()}
+			if errors.Is(err, errNTPKissOfDeath) {
+				// Rotate the server out for the remainder of this check.
+				servers = removeServer(servers, server)
+			}
+			continue
+		}
+
+		offsets = append(offsets, offset)
+		serverStats[server] = map[string]interface{}{
+			"offset": offset.String(),
+			"delay":  delay.String(),
+		}
+	}
+
+	elapsed := time.Since(start)
+	if len(offsets) == 0 {
+		if ctx.Err() == context.Canceled || ctx.Err() == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: ctx.Err(), Payload: payload, Duration: elapsed}
+		}
+		return Result{Ent: p.Entity, Err: ErrNTPNoResponse, Payload: payload, Duration: elapsed}
+	}
+
+	median := medianDuration(offsets)
+	payload.SetExtra("offset", median.String())
+
+	if absDuration(median) > p.MaxOffset {
+		return Result{Ent: p.Entity, Err: ErrNTPOffsetExceeded, Payload: payload, Duration: elapsed}
+	}
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// queryNTP performs a single SNTP exchange with the given server and returns
+// the clock offset and round-trip delay.
+func queryNTP(ctx context.Context, server string, deadline time.Time) (time.Duration, time.Duration, error) {
+	address := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		address = net.JoinHostPort(server, ntpDefaultPort)
+	}
+
+	conn, err := ntpConns.Get(ctx, address)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Do not return the conn to the pool on error paths: a stale socket may
+	// hold an unread datagram that would corrupt the next exchange.
+	reusable := false
+	defer func() {
+		if reusable {
+			ntpConns.Put(address, conn)
+		} else {
+			_ = conn.Close()
+		}
+	}()
+
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return 0, 0, err
+	}
+
+	var req [ntpPacketSize]byte
+	req[0] = 0x23 // LI=0, VN=4, Mode=3 (client)
+	t0 := time.Now()
+	putNTPTime(req[40:48], t0)
+
+	if _, err := conn.Write(req[:]); err != nil {
+		return 0, 0, err
+	}
+
+	var resp [ntpPacketSize]byte
+	if _, err := readFullPacket(conn, resp[:]); err != nil {
+		return 0, 0, err
+	}
+	t3 := time.Now()
+
+	// The server must echo our transmit timestamp as the originate timestamp;
+	// anything else is a stray or spoofed datagram.
+	if !bytesEqual(resp[24:32], req[40:48]) {
+		return 0, 0, fmt.Errorf("ntp response originate timestamp mismatch from %s", server)
+	}
+
+	// Stratum 0 is a Kiss-of-Death packet; the reference ID carries the code.
+	if resp[1] == 0 {
+		code := string(resp[12:16])
+		return 0, 0, fmt.Errorf("%w from %s: %q", errNTPKissOfDeath, server, code)
+	}
+
+	t1 := getNTPTime(resp[32:40]) // server receive
+	t2 := getNTPTime(resp[40:48]) // server transmit
+
+	offset := (t1.Sub(t0) + t2.Sub(t3)) / 2
+	delay := t3.Sub(t0) - t2.Sub(t1)
+
+	reusable = true
+	return offset, delay, nil
+}
+
+// putNTPTime encodes a time as a 64-bit NTP timestamp (seconds since 1900
+// plus a 32-bit fraction).
+func putNTPTime(b []byte, t time.Time) {
+	secs := uint64(t.Unix()) + ntpEpochOffset
+	frac := uint64(t.Nanosecond()) << 32 / 1e9
+	binary.BigEndian.PutUint32(b[0:4], uint32(secs))
+	binary.BigEndian.PutUint32(b[4:8], uint32(frac))
+}
+
+// getNTPTime decodes a 64-bit NTP timestamp into a time.Time.
+func getNTPTime(b []byte) time.Time {
+	secs := binary.BigEndian.Uint32(b[0:4])
+	frac := binary.BigEndian.Uint32(b[4:8])
+	nanos := uint64(frac) * 1e9 >> 32
+	return time.Unix(int64(secs)-ntpEpochOffset, int64(nanos))
+}
+
+// readFullPacket reads a single datagram of at least len(b) bytes.
+func readFullPacket(conn net.Conn, b []byte) (int, error) {
+	n, err := conn.Read(b)
+	if err != nil {
+		return n, err
+	}
+	if n < len(b) {
+		return n, fmt.Errorf("short ntp packet: %d bytes", n)
+	}
+	return n, nil
+}
+
+func bytesEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// removeServer returns servers with the given entry filtered out.
+func removeServer(servers []string, server string) []string {
+	out := servers[:0]
+	for _, s := range servers {
+		if s != server {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// medianDuration returns the median of the given durations.
+// The slice is partially reordered in place.
+func medianDuration(d []time.Duration) time.Duration {
+	// Insertion sort: sample counts are tiny
+	for i := 1; i < len(d); i++ {
+		for j := i; j > 0 && d[j] < d[j-1]; j-- {
+			d[j], d[j-1] = d[j-1], d[j]
+		}
+	}
+	mid := len(d) / 2
+	if len(d)%2 == 1 {
+		return d[mid]
+	}
+	return (d[mid-1] + d[mid]) / 2
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (p *PulseNTPJob) Copy() Job { job := *p; return &job }
+
+// ConcurrencyGroup returns the job's group for the group limiter.
+func (p *PulseNTPJob) ConcurrencyGroup() string { return p.Group }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (p *PulseNTPJob) GetEnqueueTime() time.Time { return p.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (p *PulseNTPJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (p *PulseNTPJob) GetStartTime() time.Time { return p.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (p *PulseNTPJob) SetStartTime(t time.Time) { p.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (p *PulseNTPJob) IsNil() bool { return p == nil }
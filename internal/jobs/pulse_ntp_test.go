@@ -0,0 +1,200 @@
+package jobs
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// fakeNTPServer is a minimal SNTP responder for tests. It echoes the client's
+// transmit timestamp as originate and reports server time shifted by offset.
+// With kissOfDeath set, it answers with a stratum-0 packet instead.
+type fakeNTPServer struct {
+	conn        net.PacketConn
+	offset      time.Duration
+	kissOfDeath bool
+}
+
+func newFakeNTPServer(t *testing.T, offset time.Duration, kissOfDeath bool) *fakeNTPServer {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeNTPServer{conn: conn, offset: offset, kissOfDeath: kissOfDeath}
+	go s.serve()
+	t.Cleanup(func() { _ = conn.Close() })
+	return s
+}
+
+func (s *fakeNTPServer) addr() string { return s.conn.LocalAddr().String() }
+
+func (s *fakeNTPServer) serve() {
+	buf := make([]byte, ntpPacketSize)
+	for {
+		n, client, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if n < ntpPacketSize {
+			continue
+		}
+
+		var resp [ntpPacketSize]byte
+		resp[0] = 0x24 // LI=0, VN=4, Mode=4 (server)
+		copy(resp[24:32], buf[40:48])
+
+		if s.kissOfDeath {
+			resp[1] = 0 // stratum 0 marks Kiss-of-Death
+			copy(resp[12:16], "RATE")
+		} else {
+			resp[1] = 2
+			now := time.Now().Add(s.offset)
+			putNTPTime(resp[32:40], now)
+			putNTPTime(resp[40:48], now)
+		}
+
+		_, _ = s.conn.WriteTo(resp[:], client)
+	}
+}
+
+func newNTPJob(servers []string, maxOffset time.Duration) *PulseNTPJob {
+	return &PulseNTPJob{
+		Entity:    ecs.Entity{},
+		Servers:   servers,
+		MaxOffset: maxOffset,
+		Samples:   3,
+		Timeout:   2 * time.Second,
+		JobType:   InternedPulse,
+		Driver:    InternedNTP,
+	}
+}
+
+func TestPulseNTPJob_Execute_Success(t *testing.T) {
+	t.Parallel()
+	server := newFakeNTPServer(t, 0, false)
+
+	job := newNTPJob([]string{server.addr()}, 500*time.Millisecond)
+	result := job.Execute(context.Background())
+
+	if result.Err != nil {
+		t.Errorf("expected success, got %v", result.Err)
+	}
+	if result.Payload.Extra("offset") == nil {
+		t.Error("expected median offset in payload")
+	}
+	servers, ok := result.Payload.Extra("servers").(map[string]interface{})
+	if !ok || servers[server.addr()] == nil {
+		t.Error("expected per-server stats in payload")
+	}
+}
+
+func TestPulseNTPJob_Execute_OffsetExceeded(t *testing.T) {
+	t.Parallel()
+	server := newFakeNTPServer(t, 5*time.Second, false)
+
+	job := newNTPJob([]string{server.addr()}, 500*time.Millisecond)
+	result := job.Execute(context.Background())
+
+	if result.Err != ErrNTPOffsetExceeded {
+		t.Errorf("expected ErrNTPOffsetExceeded, got %v", result.Err)
+	}
+}
+
+func TestPulseNTPJob_Execute_KissOfDeathRotation(t *testing.T) {
+	t.Parallel()
+	kod := newFakeNTPServer(t, 0, true)
+	good := newFakeNTPServer(t, 0, false)
+
+	job := newNTPJob([]string{kod.addr(), good.addr()}, 500*time.Millisecond)
+	result := job.Execute(context.Background())
+
+	// The KoD server is rotated out; the healthy server still satisfies the check.
+	if result.Err != nil {
+		t.Errorf("expected success after KoD rotation, got %v", result.Err)
+	}
+	servers, _ := result.Payload.Extra("servers").(map[string]interface{})
+	stats, _ := servers[kod.addr()].(map[string]interface{})
+	if stats == nil || stats["error"] == nil {
+		t.Error("expected KoD server to be reported with an error")
+	}
+}
+
+func TestPulseNTPJob_Execute_AllUnreachable(t *testing.T) {
+	t.Parallel()
+
+	// An address that immediately refuses: closed UDP port on localhost.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := conn.LocalAddr().String()
+	_ = conn.Close()
+
+	job := newNTPJob([]string{addr}, 500*time.Millisecond)
+	job.Timeout = 500 * time.Millisecond
+	result := job.Execute(context.Background())
+
+	if result.Err != ErrNTPNoResponse {
+		t.Errorf("expected ErrNTPNoResponse, got %v", result.Err)
+	}
+}
+
+func TestQueryNTP_MeasuresConfiguredOffset(t *testing.T) {
+	t.Parallel()
+	server := newFakeNTPServer(t, 2*time.Second, false)
+
+	offset, delay, err := queryNTP(context.Background(), server.addr(), time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("queryNTP failed: %v", err)
+	}
+	if offset < 1900*time.Millisecond || offset > 2100*time.Millisecond {
+		t.Errorf("offset = %v, want ~2s", offset)
+	}
+	if delay < 0 || delay > time.Second {
+		t.Errorf("delay = %v, want small positive", delay)
+	}
+}
+
+func TestQueryNTP_KissOfDeath(t *testing.T) {
+	t.Parallel()
+	server := newFakeNTPServer(t, 0, true)
+
+	_, _, err := queryNTP(context.Background(), server.addr(), time.Now().Add(time.Second))
+	if !errors.Is(err, errNTPKissOfDeath) {
+		t.Errorf("expected kiss-of-death error, got %v", err)
+	}
+}
+
+func TestMedianDuration(t *testing.T) {
+	t.Parallel()
+
+	odd := []time.Duration{3 * time.Second, time.Second, 2 * time.Second}
+	if got := medianDuration(odd); got != 2*time.Second {
+		t.Errorf("median(odd) = %v, want 2s", got)
+	}
+	even := []time.Duration{4 * time.Second, time.Second, 3 * time.Second, 2 * time.Second}
+	if got := medianDuration(even); got != 2500*time.Millisecond {
+		t.Errorf("median(even) = %v, want 2.5s", got)
+	}
+}
+
+func TestNTPTimeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var b [8]byte
+	now := time.Now()
+	putNTPTime(b[:], now)
+	got := getNTPTime(b[:])
+	if diff := got.Sub(now); diff < -time.Microsecond || diff > time.Microsecond {
+		t.Errorf("round trip drift %v, want < 1µs", diff)
+	}
+	if secs := binary.BigEndian.Uint32(b[0:4]); int64(secs) != now.Unix()+ntpEpochOffset {
+		t.Errorf("encoded seconds = %d, want %d", secs, now.Unix()+ntpEpochOffset)
+	}
+}
@@ -0,0 +1,134 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// PulseTCPJob performs TCP connection health checks.
+// It attempts to establish a TCP connection to verify port availability.
+//
+// Safety features:
+//   - Uses global dial limiter via acquireTCPSlot()
+//   - Checks context cancellation between retries
+//   - Uses pre-allocated payloads to reduce allocations
+//   - Uses optimized TCP dialer with SO_REUSEADDR
+type PulseTCPJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Host        string
+	JobType     string
+	Driver      string
+	Port        int
+	Timeout     time.Duration
+	Retries     int
+	Entity      ecs.Entity
+	// Network pins the dial to one address family: "tcp", "tcp4", or
+	// "tcp6", resolved from the monitor's ip_family at job creation.
+	// Empty dials "tcp".
+	Network string
+	// DetailedTiming records the dial (connect) duration into the payload.
+	// TCP has no dns/tls/ttfb split; only the connect phase is meaningful.
+	DetailedTiming    bool
+	DegradedThreshold time.Duration
+	DegradedPhase     string
+	// Backoff controls retry delays; the zero value keeps the historical
+	// 50ms-base exponential behavior.
+	Backoff BackoffPolicy
+	// Group is the concurrency group for per-group in-flight limits.
+	Group string
+	// TraceCtx, when non-nil, carries the dispatch-opened trace span for
+	// this check; queue wait and attempts record as children (see tracing.go).
+	TraceCtx context.Context
+}
+
+// SetTraceContext stores the dispatch-opened trace context (TraceCarrier).
+func (p *PulseTCPJob) SetTraceContext(ctx context.Context) { p.TraceCtx = ctx }
+
+// TraceContext returns the current dispatch's trace context, nil if untraced.
+func (p *PulseTCPJob) TraceContext() context.Context { return p.TraceCtx }
+
+// Execute performs the TCP connection check with retries.
+func (p *PulseTCPJob) Execute(ctx context.Context) Result {
+	payload := GetPulseTCPPayload()
+
+	// Acquire TCP connection slot to limit concurrent dials
+	if !acquireTCPSlot(ctx, p.Timeout) {
+		return Result{Ent: p.Entity, Err: ErrSemaphoreTimeout, Payload: payload}
+	}
+	defer releaseTCPSlot()
+
+	address := net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+
+	var timings PhaseTimings
+	var attemptLatency time.Duration
+	var attempt int
+	start := time.Now()
+	if p.TraceCtx != nil {
+		traceQueueWait(p.TraceCtx, p.EnqueueTime, start)
+	}
+	stats, err := RetryWithPolicy(ctx, p.Retries+1, p.Backoff, p.Timeout, func() (retErr error) {
+		attempt++
+		span := startAttempt(p.TraceCtx, attempt)
+		defer func() { endAttempt(span, retErr) }()
+		dialStart := time.Now()
+		defer func() { attemptLatency = time.Since(dialStart) }()
+		conn, dialErr := DialTCP(ctx, p.Network, address, p.Timeout)
+		if dialErr != nil {
+			return dialErr
+		}
+		timings.ConnectMs = durationToMs(time.Since(dialStart))
+		_ = conn.Close()
+		return nil
+	})
+
+	elapsed := time.Since(start)
+	if p.DetailedTiming {
+		payload.SetExtra("phases", timings)
+	}
+	stampRetryStats(&payload, stats)
+	stampAttemptLatency(&payload, stats, attemptLatency)
+
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		// Record which address the dialer actually tried, so dual-stack
+		// monitors pinned to one family can tell v4 and v6 failures apart.
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Addr != nil {
+			payload.SetExtra("resolved_addr", opErr.Addr.String())
+		}
+		return Result{Ent: p.Entity, Err: ErrTCPCheckFailed, Payload: payload, Duration: elapsed, Class: InfraErrorClass(err)}
+	}
+	if p.DegradedThreshold > 0 {
+		markDegraded(&payload, p.DegradedPhase, p.DegradedThreshold, elapsed, &timings)
+	}
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (p *PulseTCPJob) Copy() Job { job := *p; return &job }
+
+// ConcurrencyGroup returns the job's group for the group limiter.
+func (p *PulseTCPJob) ConcurrencyGroup() string { return p.Group }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (p *PulseTCPJob) GetEnqueueTime() time.Time { return p.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (p *PulseTCPJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (p *PulseTCPJob) GetStartTime() time.Time { return p.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (p *PulseTCPJob) SetStartTime(t time.Time) { p.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (p *PulseTCPJob) IsNil() bool { return p == nil }
@@ -0,0 +1,178 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// udpMaxReplySize caps the reply buffer; health probes exchange small
+// datagrams, and anything larger is truncated for the assertion.
+const udpMaxReplySize = 2048
+
+// PulseUDPJob performs UDP send/expect probes against services like DNS or
+// statsd where "healthy" means a probe datagram draws a reply. The socket is
+// connected, so an ICMP port-unreachable surfaces as a read error and always
+// fails; plain silence is configurable via RequireResponse because UDP gives
+// no other liveness signal.
+//
+// Safety features:
+//   - Uses global dial limiter to prevent CPU spikes during outages
+//   - Checks context cancellation between retries
+//   - Applies the job Timeout per attempt via context
+//   - Fresh payload per execution (byte counts escape in the Result)
+type PulseUDPJob struct {
+	EnqueueTime time.Time
+	StartTime   time.Time
+	Host        string
+	JobType     string
+	Driver      string
+	Port        int
+	// Probe is the datagram sent; Expect optionally asserts on the reply.
+	// Both are decoded from their config specs at job creation (see
+	// factory.go).
+	Probe  []byte
+	Expect []byte
+	// ExpectPrefix selects prefix matching (hex specs) over substring
+	// matching (literal specs).
+	ExpectPrefix bool
+	// ReadTimeout bounds the wait for a reply; zero uses Timeout.
+	ReadTimeout time.Duration
+	// RequireResponse fails the check when no reply arrives before the
+	// read deadline. When false, a successful write is healthy enough.
+	RequireResponse bool
+	Timeout         time.Duration
+	Retries         int
+	Entity          ecs.Entity
+	// Backoff controls retry delays; the zero value keeps the historical
+	// 50ms-base exponential behavior.
+	Backoff BackoffPolicy
+	// Group is the concurrency group for per-group in-flight limits.
+	Group string
+}
+
+// Execute performs the UDP probe with retries.
+func (p *PulseUDPJob) Execute(ctx context.Context) Result {
+	payload := Payload{Kind: KindPulse, Driver: InternedUDP}
+
+	// Use global dial limiter to prevent CPU spikes during mass failures.
+	if !GetDialLimiter().Acquire(ctx) {
+		return Result{Ent: p.Entity, Err: ErrDialLimiterTimeout, Payload: payload}
+	}
+	defer GetDialLimiter().Release()
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	readTimeout := p.ReadTimeout
+	if readTimeout <= 0 {
+		readTimeout = timeout
+	}
+
+	address := net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+
+	var sent, received int
+	start := time.Now()
+	err := RetryWithBackoff(ctx, p.Retries+1, 50*time.Millisecond, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var d net.Dialer
+		conn, dialErr := d.DialContext(attemptCtx, "udp", address)
+		if dialErr != nil {
+			return dialErr
+		}
+		defer conn.Close()
+
+		n, writeErr := conn.Write(p.Probe)
+		if writeErr != nil {
+			return writeErr
+		}
+		sent = n
+
+		if deadlineErr := conn.SetReadDeadline(time.Now().Add(readTimeout)); deadlineErr != nil {
+			return deadlineErr
+		}
+		buf := make([]byte, udpMaxReplySize)
+		rn, readErr := conn.Read(buf)
+		if readErr != nil {
+			// The connected socket turns ICMP port-unreachable into a read
+			// error: always a failure. A plain deadline only fails when the
+			// monitor requires a response.
+			var netErr net.Error
+			if errors.As(readErr, &netErr) && netErr.Timeout() {
+				if p.RequireResponse {
+					return ErrUDPNoResponse
+				}
+				return nil
+			}
+			return readErr
+		}
+		received = rn
+		return p.checkExpect(buf[:rn])
+	})
+
+	elapsed := time.Since(start)
+	payload.SetExtra("bytes_sent", sent)
+	if received > 0 {
+		payload.SetExtra("bytes_received", received)
+	}
+
+	if err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		// Silence and mismatches surface as distinct sentinels so downstream
+		// consumers can tell a dead service from a misbehaving one.
+		if errors.Is(err, ErrUDPNoResponse) || errors.Is(err, ErrUDPMismatch) {
+			return Result{Ent: p.Entity, Err: err, Payload: payload, Duration: elapsed}
+		}
+		return Result{Ent: p.Entity, Err: ErrUDPCheckFailed, Payload: payload, Duration: elapsed, Class: InfraErrorClass(err)}
+	}
+	return Result{Ent: p.Entity, Err: nil, Payload: payload, Duration: elapsed}
+}
+
+// checkExpect asserts on the reply: hex specs must prefix the datagram,
+// literal specs may appear anywhere in it.
+func (p *PulseUDPJob) checkExpect(reply []byte) error {
+	if len(p.Expect) == 0 {
+		return nil
+	}
+	if p.ExpectPrefix {
+		if !bytes.HasPrefix(reply, p.Expect) {
+			return ErrUDPMismatch
+		}
+		return nil
+	}
+	if !bytes.Contains(reply, p.Expect) {
+		return ErrUDPMismatch
+	}
+	return nil
+}
+
+// Copy returns a shallow copy of the job for safe pool reuse.
+func (p *PulseUDPJob) Copy() Job { job := *p; return &job }
+
+// ConcurrencyGroup returns the job's group for the group limiter.
+func (p *PulseUDPJob) ConcurrencyGroup() string { return p.Group }
+
+// GetEnqueueTime returns when the job was enqueued.
+func (p *PulseUDPJob) GetEnqueueTime() time.Time { return p.EnqueueTime }
+
+// SetEnqueueTime sets when the job was enqueued.
+func (p *PulseUDPJob) SetEnqueueTime(t time.Time) { p.EnqueueTime = t }
+
+// GetStartTime returns when the job started executing.
+func (p *PulseUDPJob) GetStartTime() time.Time { return p.StartTime }
+
+// SetStartTime sets when the job started executing.
+func (p *PulseUDPJob) SetStartTime(t time.Time) { p.StartTime = t }
+
+// IsNil returns true if the job pointer is nil.
+func (p *PulseUDPJob) IsNil() bool { return p == nil }
@@ -0,0 +1,124 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// startUDPResponder runs a UDP listener that answers every probe with the
+// given reply and returns its port. A nil reply makes it swallow probes
+// silently.
+func startUDPResponder(t *testing.T, reply []byte) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, udpMaxReplySize)
+		for {
+			_, client, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply != nil {
+				_, _ = conn.WriteTo(reply, client)
+			}
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func udpJobFor(port int) *PulseUDPJob {
+	return &PulseUDPJob{
+		Host:    "127.0.0.1",
+		Port:    port,
+		Probe:   []byte("ping"),
+		Timeout: 2 * time.Second,
+	}
+}
+
+func TestPulseUDPJob_Execute_ExpectMatch(t *testing.T) {
+	t.Parallel()
+	port := startUDPResponder(t, []byte("pong from server"))
+
+	job := udpJobFor(port)
+	job.Expect = []byte("pong")
+
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("expected nil error, got %v", result.Err)
+	}
+	if sent, ok := result.Payload.Extra("bytes_sent").(int); !ok || sent != len("ping") {
+		t.Errorf("bytes_sent = %v, want %d", result.Payload.Extra("bytes_sent"), len("ping"))
+	}
+	if recv, ok := result.Payload.Extra("bytes_received").(int); !ok || recv != len("pong from server") {
+		t.Errorf("bytes_received = %v, want %d", result.Payload.Extra("bytes_received"), len("pong from server"))
+	}
+}
+
+func TestPulseUDPJob_Execute_ExpectMismatch(t *testing.T) {
+	t.Parallel()
+	port := startUDPResponder(t, []byte("ERROR"))
+
+	job := udpJobFor(port)
+	job.Expect = []byte("pong")
+
+	result := job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrUDPMismatch) {
+		t.Errorf("expected ErrUDPMismatch, got %v", result.Err)
+	}
+}
+
+func TestPulseUDPJob_Execute_HexExpectPrefix(t *testing.T) {
+	t.Parallel()
+	port := startUDPResponder(t, []byte{0xde, 0xad, 0xbe, 0xef, 0x01})
+
+	job := udpJobFor(port)
+	job.Expect = []byte{0xde, 0xad}
+	job.ExpectPrefix = true
+
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("expected nil error, got %v", result.Err)
+	}
+
+	// Prefix matching means the bytes must open the reply, not merely
+	// appear in it.
+	job.Expect = []byte{0xbe, 0xef}
+	if result := job.Execute(context.Background()); !errors.Is(result.Err, ErrUDPMismatch) {
+		t.Errorf("expected ErrUDPMismatch for non-prefix bytes, got %v", result.Err)
+	}
+}
+
+func TestPulseUDPJob_Execute_NoResponse(t *testing.T) {
+	t.Parallel()
+	port := startUDPResponder(t, nil) // swallow probes
+
+	// Silence is healthy when the monitor does not require a response: the
+	// write succeeded and no port-unreachable came back.
+	job := udpJobFor(port)
+	job.ReadTimeout = 50 * time.Millisecond
+
+	result := job.Execute(context.Background())
+	if result.Err != nil {
+		t.Fatalf("expected nil error without require_response, got %v", result.Err)
+	}
+	if result.Payload.Extra("bytes_received") != nil {
+		t.Error("expected no bytes_received for a silent target")
+	}
+
+	// The same silence fails when a response is required.
+	job = udpJobFor(port)
+	job.ReadTimeout = 50 * time.Millisecond
+	job.RequireResponse = true
+
+	result = job.Execute(context.Background())
+	if !errors.Is(result.Err, ErrUDPNoResponse) {
+		t.Errorf("expected ErrUDPNoResponse, got %v", result.Err)
+	}
+}
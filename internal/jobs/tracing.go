@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace plumbing for pulse jobs. The dispatch system opens a root span for a
+// traced check and hands its context to the job through TraceCarrier; the
+// job records queue wait and per-attempt child spans, and the result system
+// closes the root after applying the result. A nil trace context — the
+// default — short-circuits every helper, so untraced checks pay only nil
+// checks.
+
+// TraceCarrier is implemented by pulse jobs that can carry a per-dispatch
+// trace context. Dispatch sets it (to nil for untraced checks) before the
+// job is enqueued; only one check per entity is in flight, so the field is
+// never written while a worker reads it.
+type TraceCarrier interface {
+	SetTraceContext(ctx context.Context)
+	TraceContext() context.Context
+}
+
+// jobTracer returns the tracer that opened the span carried in ctx.
+func jobTracer(ctx context.Context) trace.Tracer {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer("cpra/jobs")
+}
+
+// traceQueueWait records the time between enqueue and worker pickup as a
+// child span, stamped retroactively from the job's enqueue time.
+func traceQueueWait(ctx context.Context, enqueued, pickedUp time.Time) {
+	if ctx == nil || enqueued.IsZero() || pickedUp.Before(enqueued) {
+		return
+	}
+	_, span := jobTracer(ctx).Start(ctx, "pulse.queue_wait", trace.WithTimestamp(enqueued))
+	span.End(trace.WithTimestamp(pickedUp))
+}
+
+// startAttempt opens a span for one execute attempt; nil when untraced.
+func startAttempt(ctx context.Context, attempt int) trace.Span {
+	if ctx == nil {
+		return nil
+	}
+	_, span := jobTracer(ctx).Start(ctx, "pulse.attempt",
+		trace.WithAttributes(attribute.Int("attempt", attempt)))
+	return span
+}
+
+// endAttempt closes an attempt span, recording the attempt's error.
+func endAttempt(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestPulseHTTPJobTraceSpans verifies that a job carrying a trace context
+// records the queue-wait and per-attempt child spans under the dispatch root,
+// and that a job without one stays silent.
+func TestPulseHTTPJobTraceSpans(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	ctx, root := tp.Tracer("test").Start(context.Background(), "pulse.check")
+
+	host, isTLS, _ := ExtractHostFromURL(server.URL)
+	job := &PulseHTTPJob{
+		URL:     server.URL,
+		Method:  "GET",
+		Host:    host,
+		IsTLS:   isTLS,
+		Timeout: 5 * time.Second,
+	}
+	job.SetEnqueueTime(time.Now().Add(-50 * time.Millisecond))
+	job.SetTraceContext(ctx)
+
+	if result := job.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("Execute failed: %v", result.Err)
+	}
+	root.End()
+
+	spans := recorder.Ended()
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range spans {
+		byName[s.Name()] = s
+	}
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3 (queue_wait, attempt, check)", len(spans))
+	}
+	rootID := root.SpanContext().SpanID()
+	for _, name := range []string{"pulse.queue_wait", "pulse.attempt"} {
+		span, ok := byName[name]
+		if !ok {
+			t.Fatalf("missing span %q", name)
+		}
+		if span.Parent().SpanID() != rootID {
+			t.Errorf("%s parent = %s, want root %s", name, span.Parent().SpanID(), rootID)
+		}
+	}
+	if _, ok := byName["pulse.check"]; !ok {
+		t.Error("missing root span pulse.check")
+	}
+
+	// The queue-wait span is retro-stamped to the enqueue time.
+	qw := byName["pulse.queue_wait"]
+	if wait := qw.EndTime().Sub(qw.StartTime()); wait < 40*time.Millisecond {
+		t.Errorf("queue_wait duration = %v, want >= 40ms", wait)
+	}
+
+	// A job with no carried context must not emit spans.
+	before := len(recorder.Ended())
+	plain := &PulseHTTPJob{URL: server.URL, Method: "GET", Host: host, IsTLS: isTLS, Timeout: 5 * time.Second}
+	if result := plain.Execute(context.Background()); result.Err != nil {
+		t.Fatalf("untraced Execute failed: %v", result.Err)
+	}
+	if after := len(recorder.Ended()); after != before {
+		t.Errorf("untraced job emitted %d spans", after-before)
+	}
+}
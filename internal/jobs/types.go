@@ -0,0 +1,309 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+
+	"cpra/internal/interning"
+)
+
+// Job defines the interface for any executable task in the system.
+//
+// All job implementations must be safe for concurrent execution. Jobs are
+// typically created via factory functions, enqueued, dequeued by worker pools,
+// executed, and results are processed by ECS systems.
+//
+// Jobs should implement Copy() to allow safe reuse of job pools. The IsNil()
+// method allows checking for nil jobs without type assertions.
+//
+// Ownership: the instance stored in JobStorage is a shared prototype that
+// the scheduling systems re-enqueue every cycle. Queues stamp bookkeeping
+// times (SetEnqueueTime) on a private Copy, never on the instance they were
+// handed, so a prototype is safe to enqueue while an earlier copy of it is
+// still executing.
+type Job interface {
+	Execute(ctx context.Context) Result
+	Copy() Job
+	GetEnqueueTime() time.Time
+	SetEnqueueTime(time.Time)
+	GetStartTime() time.Time
+	SetStartTime(time.Time)
+	// GetEntity returns the entity the job reports its Result against, so
+	// infrastructure (e.g. the worker pool's panic recovery) can fail the
+	// right monitor even when Execute never returned.
+	GetEntity() ecs.Entity
+	IsNil() bool
+}
+
+// ErrorClass attributes a job failure to its origin. Failures classed as
+// internal infrastructure (e.g. local socket exhaustion) should not count
+// against the monitored target.
+type ErrorClass uint8
+
+const (
+	// ErrorClassNone means the failure, if any, is attributed to the target.
+	ErrorClassNone ErrorClass = iota
+	// ErrorClassInternalInfrastructure marks failures caused by the CPRA
+	// host itself (ephemeral port or file descriptor exhaustion).
+	ErrorClassInternalInfrastructure
+)
+
+// JobKind classifies a result for routing. It replaces the old
+// payload["type"] string so RouteResults switches on an integer instead
+// of a map lookup plus interface comparison.
+type JobKind uint8
+
+const (
+	// KindUnknown is the zero value; the router logs and drops it.
+	KindUnknown JobKind = iota
+	// KindPulse routes to the pulse result channel.
+	KindPulse
+	// KindIntervention routes to the intervention result channel.
+	KindIntervention
+	// KindCode routes to the code result channel.
+	KindCode
+)
+
+// String returns the legacy payload["type"] spelling of the kind.
+func (k JobKind) String() string {
+	switch k {
+	case KindPulse:
+		return "pulse"
+	case KindIntervention:
+		return "intervention"
+	case KindCode:
+		return "code"
+	default:
+		return "unknown"
+	}
+}
+
+// Payload carries per-result metadata as plain fields instead of the old
+// map[string]interface{}. The common success path (Kind + Driver, maybe
+// Color) allocates nothing; Extras is only created when a driver records
+// free-form data such as retry stats, phase timings, or privilege markers.
+type Payload struct {
+	Kind   JobKind
+	Driver string
+	// Color is the alert color for code notifications ("red"/"green").
+	Color string
+	// Attempts is how many times the driver ran the check, retries
+	// included; zero means the driver does not track attempts.
+	Attempts int
+	// LastAttemptError is the error from the most recent failed attempt.
+	// On an eventually-successful check it shows what the retries were
+	// absorbing; on a failed check it matches the Result error.
+	LastAttemptError error
+	// Extras holds driver-specific values keyed by their legacy map
+	// names. Nil on the common path; use SetExtra to populate it.
+	Extras map[string]interface{}
+}
+
+// Extra returns a free-form payload value, or nil when unset.
+func (p Payload) Extra(key string) interface{} {
+	if p.Extras == nil {
+		return nil
+	}
+	return p.Extras[key]
+}
+
+// SetExtra stores a free-form value, allocating Extras on first use.
+func (p *Payload) SetExtra(key string, value interface{}) {
+	if p.Extras == nil {
+		p.Extras = make(map[string]interface{}, 4)
+	}
+	p.Extras[key] = value
+}
+
+// Map renders the payload in its legacy map form, for subscribers and
+// sinks that still expect map[string]interface{}. It allocates; the
+// router and batch result systems read the struct fields directly.
+func (p Payload) Map() map[string]interface{} {
+	m := make(map[string]interface{}, 3+len(p.Extras))
+	if p.Kind != KindUnknown {
+		m["type"] = p.Kind.String()
+	}
+	if p.Driver != "" {
+		m["driver"] = p.Driver
+	}
+	if p.Color != "" {
+		m["color"] = p.Color
+	}
+	if p.Attempts > 0 {
+		m["attempts_made"] = p.Attempts
+	}
+	if p.LastAttemptError != nil {
+		m["last_attempt_error"] = p.LastAttemptError.Error()
+	}
+	for k, v := range p.Extras {
+		m[k] = v
+	}
+	return m
+}
+
+// Result is a generic structure for returning the outcome of a job.
+// It includes the entity it belongs to, any error that occurred, and a typed payload.
+type Result struct {
+	Err     error
+	Payload Payload
+	// Duration is the wall time from the first attempt to the final
+	// outcome, including retry backoff. Pulse jobs populate it so result
+	// systems can track latency, not just liveness; zero means untracked.
+	Duration time.Duration
+	Ent      ecs.Entity
+	Class    ErrorClass
+}
+
+// Entity returns the entity associated with the result.
+func (r *Result) Entity() ecs.Entity {
+	return r.Ent
+}
+
+// Error returns the error associated with the result, if any.
+func (r *Result) Error() error {
+	return r.Err
+}
+
+// Predefined errors for job creation and execution.
+// Using predeclared errors avoids allocations in hot paths.
+var (
+	// Factory errors
+	ErrUnknownPulseConfig        = errors.New("unknown pulse config type")
+	ErrDockerMissingTarget       = errors.New("docker intervention missing target configuration")
+	ErrUnknownInterventionAction = errors.New("unknown intervention action")
+	ErrUnknownCodeNotification   = errors.New("unknown code notification type")
+
+	// Execution errors - pulse jobs
+	ErrHTTPNon2xxStatus = errors.New("received non-2xx status code")
+	ErrHTTPCheckFailed  = errors.New("http check failed after retries")
+	ErrStatusAssertion  = errors.New("response status did not match expected_status")
+	// ErrRateLimited marks a check the target answered with 429 (or 503
+	// with Retry-After): a distinct outcome, neither success nor failure.
+	ErrRateLimited       = errors.New("endpoint rate limited the check")
+	ErrBodyAssertion     = errors.New("response body did not match body assertion")
+	ErrTCPCheckFailed    = errors.New("tcp check failed after retries")
+	ErrUDPNoResponse     = errors.New("no udp response received")
+	ErrUDPMismatch       = errors.New("udp response did not match expected bytes")
+	ErrUDPCheckFailed    = errors.New("udp check failed after retries")
+	ErrICMPCheckFailed   = errors.New("icmp check failed after retries")
+	ErrNoPackets         = errors.New("no packets received")
+	ErrHeartbeatMissed   = errors.New("heartbeat not received within interval and grace period")
+	ErrNTPNoResponse     = errors.New("no NTP server responded")
+	ErrNTPOffsetExceeded = errors.New("clock offset exceeds maximum")
+	ErrDNSNotFound       = errors.New("dns name does not exist")
+	ErrDNSTimeout        = errors.New("dns lookup timed out")
+	ErrDNSMismatch       = errors.New("dns records did not match expected values")
+	ErrGRPCNotServing    = errors.New("grpc service not serving")
+	ErrGRPCCheckFailed   = errors.New("grpc health check failed after retries")
+	// ErrUnexpectedResponse is the synthesized failure for inverted
+	// monitors: the endpoint was expected to be down but a probe succeeded.
+	ErrUnexpectedResponse = errors.New("unexpected response from endpoint expected to be down")
+
+	// ErrJobPanicked is the synthesized failure the worker pool reports
+	// when a job's Execute panicked instead of returning a Result.
+	ErrJobPanicked = errors.New("job panicked during execution")
+
+	ErrDockerContainerNotFound = errors.New("docker container not found")
+	ErrDockerContainerState    = errors.New("docker container not in required state")
+	ErrDockerCheckFailed       = errors.New("docker check failed after retries")
+
+	// Execution errors - intervention jobs
+	ErrFailedToCreateDockerClient = errors.New("failed to create docker client")
+	ErrDockerDaemonUnavailable    = errors.New("docker daemon unavailable")
+	ErrDockerActionFailed         = errors.New("docker intervention failed after retries")
+	ErrDockerStopFailed           = errors.New("docker stop failed after retries")
+	ErrDockerStartFailed          = errors.New("docker start failed after retries")
+	ErrDockerKillFailed           = errors.New("docker kill failed after retries")
+	ErrDockerPauseFailed          = errors.New("docker pause failed after retries")
+	ErrDockerUnpauseFailed        = errors.New("docker unpause failed after retries")
+	ErrDockerScaleFailed          = errors.New("docker scale failed after retries")
+	ErrNotReplicatedService       = errors.New("service is not in replicated mode")
+	ErrUnknownDockerAction        = errors.New("unknown docker action type")
+
+	ErrExecMissingTarget      = errors.New("exec intervention missing target configuration")
+	ErrExecMissingCommand     = errors.New("exec intervention requires command")
+	ErrExecActionFailed       = errors.New("exec intervention failed after retries")
+	ErrExecTimeout            = errors.New("exec intervention timed out")
+	ErrExecUnexpectedExitCode = errors.New("exec intervention exited with unexpected code")
+
+	ErrWebhookMissingTarget    = errors.New("webhook intervention missing target configuration")
+	ErrWebhookMissingURL       = errors.New("webhook intervention requires url")
+	ErrWebhookActionFailed     = errors.New("webhook intervention failed after retries")
+	ErrWebhookTimeout          = errors.New("webhook intervention timed out")
+	ErrWebhookUnexpectedStatus = errors.New("webhook intervention returned unexpected status")
+
+	// Execution errors - code jobs
+	ErrLogMarshalFailed    = errors.New("failed to marshal log entry")
+	ErrSlackWebhookMissing = errors.New("slack notification requires webhook URL")
+	ErrSlackNotifyFailed   = errors.New("slack notification failed")
+
+	ErrPagerDutyRoutingKeyMissing = errors.New("pagerduty notification requires routing key")
+	ErrPagerDutyEventFailed       = errors.New("pagerduty event delivery failed")
+
+	ErrWebhookURLMissing   = errors.New("webhook notification requires URL")
+	ErrWebhookNotifyFailed = errors.New("webhook notification failed")
+
+	ErrEmailConfigMissing = errors.New("email notification requires smtp_host, from and to")
+	ErrEmailSendFailed    = errors.New("email notification failed")
+
+	// Resource limit errors
+	ErrSemaphoreTimeout   = errors.New("ICMP semaphore acquire timeout")
+	ErrDialLimiterTimeout = errors.New("dial limiter timeout (system under load)")
+
+	// Deprecated - kept for backwards compatibility
+	ErrFailedToCreateHTTPRequest = errors.New("failed to create http request")
+)
+
+// Pre-interned strings for job types and drivers to reduce allocations.
+// These are used in job creation to avoid string allocations.
+var (
+	InternedPulse        = interning.Intern("pulse")
+	InternedIntervention = interning.Intern("intervention")
+	InternedCode         = interning.Intern("code")
+	InternedHTTP         = interning.Intern("http")
+	InternedTCP          = interning.Intern("tcp")
+	InternedUDP          = interning.Intern("udp")
+	InternedICMP         = interning.Intern("icmp")
+	InternedNTP          = interning.Intern("ntp")
+	InternedDNS          = interning.Intern("dns")
+	InternedGRPC         = interning.Intern("grpc")
+	InternedHeartbeat    = interning.Intern("heartbeat")
+	InternedDocker       = interning.Intern("docker")
+)
+
+// Payload getters - return the base payload value for each job type.
+// Payload is a value type, so each call hands the job its own copy; jobs
+// mutate it freely without the aliasing hazards the old shared maps had,
+// and the common path still allocates nothing.
+
+// GetPulseHTTPPayload returns the base pulse HTTP payload.
+func GetPulseHTTPPayload() Payload { return Payload{Kind: KindPulse, Driver: InternedHTTP} }
+
+// GetPulseTCPPayload returns the base pulse TCP payload.
+func GetPulseTCPPayload() Payload { return Payload{Kind: KindPulse, Driver: InternedTCP} }
+
+// GetPulseHeartbeatPayload returns the base pulse heartbeat payload.
+func GetPulseHeartbeatPayload() Payload { return Payload{Kind: KindPulse, Driver: InternedHeartbeat} }
+
+// GetInterventionDockerPayload returns the base intervention Docker payload.
+func GetInterventionDockerPayload() Payload {
+	return Payload{Kind: KindIntervention, Driver: InternedDocker}
+}
+
+// GetCodeLogPayload returns the base code log payload.
+func GetCodeLogPayload() Payload { return Payload{Kind: KindCode, Driver: "log"} }
+
+// GetCodePagerDutyPayload returns the base code PagerDuty payload.
+func GetCodePagerDutyPayload() Payload { return Payload{Kind: KindCode, Driver: "pagerduty"} }
+
+// GetCodeSlackPayload returns the base code Slack payload.
+func GetCodeSlackPayload() Payload { return Payload{Kind: KindCode, Driver: "slack"} }
+
+// GetCodeEmailPayload returns the base code email payload.
+func GetCodeEmailPayload() Payload { return Payload{Kind: KindCode, Driver: "email"} }
+
+// GetCodeWebhookPayload returns the base code webhook payload.
+func GetCodeWebhookPayload() Payload { return Payload{Kind: KindCode, Driver: "webhook"} }
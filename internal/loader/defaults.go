@@ -0,0 +1,143 @@
+package loader
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// defaultableKeys are the monitor fields a top-level `defaults` block can
+// provide. Restricting the merge to these keeps anchor-template defaults
+// blocks of arbitrary shape (referenced via `<<: *defaults`) working
+// unchanged alongside the implicit merge.
+var defaultableKeys = map[string]struct{}{
+	"pulse_check":  {},
+	"intervention": {},
+	"codes":        {},
+}
+
+// mergeMonitorDefaults deep-merges the recognized keys of the top-level
+// defaults block into a monitor mapping node. It runs before validation and
+// decoding, so a monitor may rely on a defaulted interval to pass
+// required-field checks. Monitor-level values always win, and an explicitly
+// empty mapping (`codes: {}`) blocks inheritance for that subtree. Default
+// nodes are deep-copied into the monitor so concurrent workers never share
+// mutable nodes. Reports whether anything was merged.
+func mergeMonitorDefaults(monitor, defaults *yaml.Node) bool {
+	monitor = resolvedNode(monitor)
+	defaults = resolvedNode(defaults)
+	if monitor == nil || defaults == nil ||
+		monitor.Kind != yaml.MappingNode || defaults.Kind != yaml.MappingNode {
+		return false
+	}
+	merged := false
+	for i := 0; i+1 < len(defaults.Content); i += 2 {
+		if _, ok := defaultableKeys[defaults.Content[i].Value]; !ok {
+			continue
+		}
+		if mergeDefaultInto(monitor, defaults.Content[i], defaults.Content[i+1]) {
+			merged = true
+		}
+	}
+	return merged
+}
+
+// mergeDefaultInto merges one default key/value pair into a mapping node.
+// Existing monitor values win; mappings present on both sides are merged
+// recursively unless the monitor's mapping is explicitly empty (a clear) or
+// reached through an alias (mutating the anchor would leak into siblings).
+func mergeDefaultInto(target *yaml.Node, key, val *yaml.Node) bool {
+	for i := 0; i+1 < len(target.Content); i += 2 {
+		if target.Content[i].Value != key.Value {
+			continue
+		}
+		if target.Content[i+1].Kind == yaml.AliasNode {
+			return false
+		}
+		existing := target.Content[i+1]
+		defVal := resolvedNode(val)
+		if existing.Kind != yaml.MappingNode || defVal == nil || defVal.Kind != yaml.MappingNode {
+			return false
+		}
+		if len(existing.Content) == 0 {
+			// An explicitly empty mapping clears the inherited section.
+			return false
+		}
+		merged := false
+		for j := 0; j+1 < len(defVal.Content); j += 2 {
+			if mergeDefaultInto(existing, defVal.Content[j], defVal.Content[j+1]) {
+				merged = true
+			}
+		}
+		return merged
+	}
+	// Values pulled in through a `<<` merge key count as present: an
+	// explicit default key appended here would override them when decoding.
+	if mergeKeyProvides(target, key.Value) {
+		return false
+	}
+	target.Content = append(target.Content, copyNode(key), copyNode(val))
+	return true
+}
+
+// mergeKeyProvides reports whether a `<<` merge key in the mapping supplies
+// the given field.
+func mergeKeyProvides(target *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(target.Content); i += 2 {
+		if target.Content[i].Value != "<<" {
+			continue
+		}
+		sources := []*yaml.Node{target.Content[i+1]}
+		if target.Content[i+1].Kind == yaml.SequenceNode {
+			sources = target.Content[i+1].Content
+		}
+		for _, src := range sources {
+			src = resolvedNode(src)
+			if src == nil || src.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(src.Content); j += 2 {
+				if src.Content[j].Value == key {
+					return true
+				}
+			}
+			if mergeKeyProvides(src, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolvedNode unwraps document and alias nodes.
+func resolvedNode(node *yaml.Node) *yaml.Node {
+	for node != nil {
+		switch {
+		case node.Kind == yaml.DocumentNode && len(node.Content) > 0:
+			node = node.Content[0]
+		case node.Kind == yaml.AliasNode:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+	return nil
+}
+
+// copyNode deep-copies a node tree, dereferencing aliases so the copy is
+// self-contained and safe to mutate independently of the source.
+func copyNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.AliasNode {
+		return copyNode(node.Alias)
+	}
+	cp := *node
+	cp.Anchor = ""
+	if len(node.Content) > 0 {
+		cp.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			cp.Content[i] = copyNode(child)
+		}
+	}
+	return &cp
+}
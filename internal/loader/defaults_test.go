@@ -0,0 +1,155 @@
+package loader
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/entities"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// defaultsManifest is shared by the inherit/override tests: the defaults
+// block supplies interval, timeout, and a codes section; inheritor relies
+// on them, overrider sets its own interval, and clearer drops the codes.
+const defaultsManifest = `defaults:
+  pulse_check:
+    interval: 30s
+    timeout: 10s
+  codes:
+    red:
+      notify: log
+      config:
+        file: /var/log/alerts.log
+monitors:
+  - name: inheritor
+    pulse_check:
+      type: http
+      config:
+        url: http://example.com/health
+  - name: overrider
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+  - name: clearer
+    codes: {}
+    pulse_check:
+      type: http
+      config:
+        url: http://example.com/health
+`
+
+func TestManifestDefaults_InheritOverrideClear(t *testing.T) {
+	monitors, err := LoadManifestFile(writeManifest(t, defaultsManifest))
+	if err != nil {
+		t.Fatalf("LoadManifestFile failed: %v", err)
+	}
+	if len(monitors) != 3 {
+		t.Fatalf("Expected 3 monitors, got %d", len(monitors))
+	}
+
+	inheritor, overrider, clearer := monitors[0], monitors[1], monitors[2]
+	if inheritor.Pulse.Interval != 30*time.Second || inheritor.Pulse.Timeout != 10*time.Second {
+		t.Errorf("inheritor should get defaulted interval/timeout, got %v/%v",
+			inheritor.Pulse.Interval, inheritor.Pulse.Timeout)
+	}
+	if _, ok := inheritor.Codes["red"]; !ok {
+		t.Errorf("inheritor should get the defaulted codes block, got %v", inheritor.Codes)
+	}
+	if overrider.Pulse.Interval != 5*time.Second {
+		t.Errorf("monitor-level interval should win over default, got %v", overrider.Pulse.Interval)
+	}
+	if len(clearer.Codes) != 0 {
+		t.Errorf("codes: {} should clear inherited codes, got %v", clearer.Codes)
+	}
+}
+
+// TestManifestDefaults_StreamingPipeline runs the same manifest through the
+// streaming pipeline, which threads the defaults block to the workers
+// instead of resolving it through anchors.
+func TestManifestDefaults_StreamingPipeline(t *testing.T) {
+	tmpFile := writeManifest(t, defaultsManifest)
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	pipeline := NewPipeline(&world, em, DefaultPipelineConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stats, err := pipeline.Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.EntitiesCreated != 3 {
+		t.Errorf("Expected 3 entities, got %d (skipped %d)", stats.EntitiesCreated, stats.SkippedMonitors)
+	}
+}
+
+// TestManifestDefaults_RequiredByValidation verifies the same monitor fails
+// validation without the defaults block, i.e. the merge happens before
+// required-field checks.
+func TestManifestDefaults_RequiredByValidation(t *testing.T) {
+	_, err := LoadManifestFile(writeManifest(t, `monitors:
+  - name: inheritor
+    pulse_check:
+      type: http
+      config:
+        url: http://example.com/health
+`))
+	if err == nil {
+		t.Fatal("Expected validation error without defaults, got nil")
+	}
+	if !strings.Contains(err.Error(), "interval must be positive") {
+		t.Errorf("Expected missing-interval error, got %v", err)
+	}
+}
+
+// TestManifestDefaults_ErrorNamesDefaults verifies validation errors note
+// when the failing monitor had defaults merged in, since the offending
+// value may not appear at the monitor's own lines.
+func TestManifestDefaults_ErrorNamesDefaults(t *testing.T) {
+	_, err := LoadManifestFile(writeManifest(t, `defaults:
+  pulse_check:
+    timeout: 60s
+monitors:
+  - name: tight
+    pulse_check:
+      type: http
+      interval: 5s
+      config:
+        url: http://example.com/health
+`))
+	if err == nil {
+		t.Fatal("Expected timeout-exceeds-interval error, got nil")
+	}
+	if !strings.Contains(err.Error(), "manifest defaults applied") {
+		t.Errorf("Error should note that defaults were applied: %v", err)
+	}
+}
+
+// TestManifestDefaults_AnchorTemplatesUntouched verifies a defaults block
+// shaped as an anchor template (not monitor sections) is left to explicit
+// references, as before.
+func TestManifestDefaults_AnchorTemplatesUntouched(t *testing.T) {
+	monitors, err := LoadManifestFile(writeManifest(t, `defaults: &http_pulse
+  type: http
+  interval: 5s
+  timeout: 3s
+  config:
+    url: http://example.com/health
+monitors:
+  - name: api-1
+    pulse_check: *http_pulse
+`))
+	if err != nil {
+		t.Fatalf("LoadManifestFile failed: %v", err)
+	}
+	if len(monitors) != 1 || monitors[0].Pulse.Interval != 5*time.Second {
+		t.Errorf("Anchor-style defaults should still work via references: %+v", monitors)
+	}
+}
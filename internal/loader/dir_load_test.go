@@ -0,0 +1,103 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/entities"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// monitorYAML renders a minimal valid manifest with the given monitor names.
+func monitorYAML(names ...string) string {
+	var b strings.Builder
+	b.WriteString("monitors:\n")
+	for _, name := range names {
+		b.WriteString("  - name: " + name + "\n")
+		b.WriteString("    enabled: true\n")
+		b.WriteString("    pulse_check:\n")
+		b.WriteString("      type: http\n")
+		b.WriteString("      interval: 5s\n")
+		b.WriteString("      timeout: 3s\n")
+		b.WriteString("      config:\n")
+		b.WriteString("        url: http://example.com/health\n")
+	}
+	return b.String()
+}
+
+// loadDir writes the given files into a temp directory and loads it.
+func loadDir(t *testing.T, files map[string]string) (*PipelineStats, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	pipeline := NewPipeline(&world, em, DefaultPipelineConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return pipeline.Load(ctx, dir)
+}
+
+// TestDirectoryLoadHappyPath verifies that a directory of manifest fragments
+// loads as one manifest, aggregating stats across files. Non-YAML files are
+// ignored.
+func TestDirectoryLoadHappyPath(t *testing.T) {
+	stats, err := loadDir(t, map[string]string{
+		"team-a.yaml": monitorYAML("a-1", "a-2"),
+		"team-b.yml":  monitorYAML("b-1", "b-2", "b-3"),
+		"team-c.yaml": monitorYAML("c-1"),
+		"README.md":   "not a manifest\n",
+	})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.EntitiesCreated != 6 {
+		t.Errorf("Expected 6 entities across 3 files, got %d", stats.EntitiesCreated)
+	}
+	if stats.DuplicateMonitors != 0 {
+		t.Errorf("Expected 0 duplicates, got %d", stats.DuplicateMonitors)
+	}
+}
+
+// TestDirectoryLoadCrossFileDuplicate verifies that the same monitor name in
+// two files aborts the load with an error naming both sources.
+func TestDirectoryLoadCrossFileDuplicate(t *testing.T) {
+	_, err := loadDir(t, map[string]string{
+		"team-a.yaml": monitorYAML("web-1", "shared"),
+		"team-b.yaml": monitorYAML("shared", "web-2"),
+	})
+	if err == nil {
+		t.Fatal("Expected cross-file duplicate error, got nil")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `duplicate monitor name "shared"`) {
+		t.Errorf("Error should name the duplicate monitor: %v", err)
+	}
+	if !strings.Contains(msg, "team-a.yaml") || !strings.Contains(msg, "team-b.yaml") {
+		t.Errorf("Error should name both files: %v", err)
+	}
+	if !strings.Contains(msg, "line") {
+		t.Errorf("Error should include line numbers: %v", err)
+	}
+}
+
+// TestDirectoryLoadEmptyDirectory verifies a directory with no manifests is
+// rejected rather than silently loading nothing.
+func TestDirectoryLoadEmptyDirectory(t *testing.T) {
+	_, err := loadDir(t, map[string]string{"README.md": "nothing here\n"})
+	if err == nil || !strings.Contains(err.Error(), "no .yaml or .yml files") {
+		t.Errorf("Expected empty-directory error, got %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/controller/entities"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// monitorYAMLWithInterval renders one monitor entry with a chosen interval so
+// override tests can tell which definition won.
+func monitorYAMLWithInterval(name, interval string) string {
+	var b strings.Builder
+	b.WriteString("  - name: " + name + "\n")
+	b.WriteString("    enabled: true\n")
+	b.WriteString("    pulse_check:\n")
+	b.WriteString("      type: http\n")
+	b.WriteString("      interval: " + interval + "\n")
+	b.WriteString("      timeout: 3s\n")
+	b.WriteString("      config:\n")
+	b.WriteString("        url: http://example.com/health\n")
+	return b.String()
+}
+
+// loadDirWorld is loadDir with a caller-supplied config, returning the world
+// and entity manager so tests can inspect what was created.
+func loadDirWorld(t *testing.T, files map[string]string, config PipelineConfig) (*ecs.World, *entities.EntityManager, *PipelineStats, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	pipeline := NewPipeline(&world, em, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stats, err := pipeline.Load(ctx, dir)
+	return &world, em, stats, err
+}
+
+// TestSameFileDuplicateKeepsFirstAndWarns verifies the default policy: the
+// first definition wins, the repeat is counted, and the warning points at the
+// first occurrence so it can be found in a large generated manifest.
+func TestSameFileDuplicateKeepsFirstAndWarns(t *testing.T) {
+	manifest := "monitors:\n" +
+		monitorYAMLWithInterval("dup", "5s") +
+		monitorYAMLWithInterval("dup", "30s")
+
+	world, em, stats, err := loadDirWorld(t, map[string]string{"a.yaml": manifest}, DefaultPipelineConfig())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.DuplicateMonitors != 1 {
+		t.Errorf("DuplicateMonitors = %d, want 1", stats.DuplicateMonitors)
+	}
+	if stats.EntitiesCreated != 1 {
+		t.Errorf("EntitiesCreated = %d, want 1", stats.EntitiesCreated)
+	}
+
+	ent, ok := em.LookupMonitor("dup")
+	if !ok {
+		t.Fatal("monitor 'dup' not found")
+	}
+	if got := ecs.NewMap[components.PulseConfig](world).Get(ent).Interval; got != 5*time.Second {
+		t.Errorf("Interval = %v, want 5s (first definition wins)", got)
+	}
+
+	var found bool
+	for _, w := range stats.Warnings {
+		if w.Monitor == "dup" && strings.Contains(w.Message, "duplicate monitor name") &&
+			strings.Contains(w.Message, "first defined at") && strings.Contains(w.Message, "line") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected duplicate warning naming the first occurrence, got %+v", stats.Warnings)
+	}
+}
+
+// TestDuplicateOverrideLastWins verifies the override policy: the later
+// definition replaces the earlier one even across a flushed batch, leaving a
+// single entity with the later config and a warning instead of an error.
+func TestDuplicateOverrideLastWins(t *testing.T) {
+	manifest := "monitors:\n" +
+		monitorYAMLWithInterval("dup", "5s") +
+		monitorYAMLWithInterval("other", "5s") +
+		monitorYAMLWithInterval("dup", "30s")
+
+	config := DefaultPipelineConfig()
+	config.DuplicatePolicy = DuplicatePolicyOverride
+	// Force one monitor per batch so the override crosses a flushed batch
+	// and exercises the in-place entity replacement path.
+	config.BatchSize = 1
+
+	world, em, stats, err := loadDirWorld(t, map[string]string{"a.yaml": manifest}, config)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.DuplicateMonitors != 1 {
+		t.Errorf("DuplicateMonitors = %d, want 1", stats.DuplicateMonitors)
+	}
+	if stats.EntitiesCreated != 2 {
+		t.Errorf("EntitiesCreated = %d, want 2", stats.EntitiesCreated)
+	}
+
+	ent, ok := em.LookupMonitor("dup")
+	if !ok {
+		t.Fatal("monitor 'dup' not found")
+	}
+	if got := ecs.NewMap[components.PulseConfig](world).Get(ent).Interval; got != 30*time.Second {
+		t.Errorf("Interval = %v, want 30s (last definition wins)", got)
+	}
+
+	var found bool
+	for _, w := range stats.Warnings {
+		if w.Monitor == "dup" && strings.Contains(w.Message, "overrides earlier definition") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected override warning, got %+v", stats.Warnings)
+	}
+}
+
+// TestDuplicateOverrideCrossFile verifies that override mode turns the
+// cross-file duplicate error into a replacement with a warning naming the
+// first source file.
+func TestDuplicateOverrideCrossFile(t *testing.T) {
+	config := DefaultPipelineConfig()
+	config.DuplicatePolicy = DuplicatePolicyOverride
+
+	world, em, stats, err := loadDirWorld(t, map[string]string{
+		"base.yaml":    "monitors:\n" + monitorYAMLWithInterval("shared", "5s"),
+		"overlay.yaml": "monitors:\n" + monitorYAMLWithInterval("shared", "30s"),
+	}, config)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.EntitiesCreated != 1 {
+		t.Errorf("EntitiesCreated = %d, want 1", stats.EntitiesCreated)
+	}
+
+	ent, ok := em.LookupMonitor("shared")
+	if !ok {
+		t.Fatal("monitor 'shared' not found")
+	}
+	// Directory fragments load in sorted order, so overlay.yaml wins.
+	if got := ecs.NewMap[components.PulseConfig](world).Get(ent).Interval; got != 30*time.Second {
+		t.Errorf("Interval = %v, want 30s (overlay wins)", got)
+	}
+
+	var found bool
+	for _, w := range stats.Warnings {
+		if w.Monitor == "shared" && strings.Contains(w.Message, "base.yaml") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected warning naming base.yaml, got %+v", stats.Warnings)
+	}
+}
+
+// TestUnknownDuplicatePolicy verifies a typo'd policy fails fast.
+func TestUnknownDuplicatePolicy(t *testing.T) {
+	config := DefaultPipelineConfig()
+	config.DuplicatePolicy = "last-writer"
+
+	_, _, _, err := loadDirWorld(t, map[string]string{
+		"a.yaml": "monitors:\n" + monitorYAMLWithInterval("m", "5s"),
+	}, config)
+	if err == nil || !strings.Contains(err.Error(), "unknown duplicate policy") {
+		t.Errorf("expected unknown-policy error, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package loader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// generateEntry is one template in the top-level `generate` section. Either
+// Count or Values drives the expansion; Template is a monitor entry with
+// {{index}} / {{value}} placeholders in its scalar values.
+type generateEntry struct {
+	Count    int       `yaml:"count"`
+	Values   []string  `yaml:"values"`
+	Template yaml.Node `yaml:"template"`
+}
+
+// expandGenerate expands the `generate` section into concrete monitor nodes,
+// one per instance, so the rest of the pipeline (validation, duplicate
+// detection, entity creation) sees plain monitors. {{index}} expands to the
+// instance number (0-based) and {{value}} to the substitution value; for
+// count-driven entries the two are the same. maxGenerated caps the total
+// expansion across all entries (PipelineConfig.MaxGeneratedMonitors).
+func expandGenerate(generate *yaml.Node, maxGenerated int) ([]*yaml.Node, error) {
+	generate = resolvedNode(generate)
+	if generate == nil || generate.Kind == 0 {
+		return nil, nil
+	}
+	if generate.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("'generate' field must be a YAML sequence")
+	}
+
+	var nodes []*yaml.Node
+	for _, entryNode := range generate.Content {
+		var entry generateEntry
+		if err := entryNode.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("generate entry at line %d: %w", entryNode.Line, err)
+		}
+		if tpl := resolvedNode(&entry.Template); tpl == nil || tpl.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("generate entry at line %d: 'template' must be a monitor mapping", entryNode.Line)
+		}
+		if entry.Count > 0 && len(entry.Values) > 0 {
+			return nil, fmt.Errorf("generate entry at line %d: 'count' and 'values' are mutually exclusive", entryNode.Line)
+		}
+		n := entry.Count
+		if len(entry.Values) > 0 {
+			n = len(entry.Values)
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("generate entry at line %d: needs 'count' or 'values'", entryNode.Line)
+		}
+		if len(nodes)+n > maxGenerated {
+			return nil, fmt.Errorf("generate section expands to more than %d monitors; raise MaxGeneratedMonitors if intended", maxGenerated)
+		}
+
+		for i := 0; i < n; i++ {
+			index := strconv.Itoa(i)
+			value := index
+			if len(entry.Values) > 0 {
+				value = entry.Values[i]
+			}
+			instance := copyNode(resolvedNode(&entry.Template))
+			substitutePlaceholders(instance, index, value)
+			nodes = append(nodes, instance)
+		}
+	}
+	return nodes, nil
+}
+
+// substitutePlaceholders replaces {{index}} and {{value}} in every scalar
+// of the node tree.
+func substitutePlaceholders(node *yaml.Node, index, value string) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.ScalarNode {
+		if strings.Contains(node.Value, "{{") {
+			node.Value = strings.ReplaceAll(node.Value, "{{index}}", index)
+			node.Value = strings.ReplaceAll(node.Value, "{{value}}", value)
+		}
+		return
+	}
+	for _, child := range node.Content {
+		substitutePlaceholders(child, index, value)
+	}
+}
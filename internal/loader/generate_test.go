@@ -0,0 +1,134 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/controller/entities"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// loadGenerated loads the manifest with the given config and returns stats
+// plus lookup handles, failing the test on error.
+func loadGenerated(t *testing.T, yamlContent string, config PipelineConfig) (*PipelineStats, *ecs.World, *entities.EntityManager, error) {
+	t.Helper()
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	pipeline := NewPipeline(&world, em, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	stats, err := pipeline.Load(ctx, writeManifest(t, yamlContent))
+	return stats, &world, em, err
+}
+
+// TestGenerateCountExpansion expands 1000 monitors from one template and
+// verifies unique names and per-instance URL substitution.
+func TestGenerateCountExpansion(t *testing.T) {
+	manifest := `generate:
+  - count: 1000
+    template:
+      name: shard-{{index}}
+      pulse_check:
+        type: http
+        interval: 5s
+        timeout: 3s
+        config:
+          url: http://shard-{{index}}.internal/health
+monitors:
+  - name: literal-1
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+`
+	stats, world, em, err := loadGenerated(t, manifest, DefaultPipelineConfig())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.EntitiesCreated != 1001 {
+		t.Errorf("Expected 1001 entities (1000 generated + 1 literal), got %d", stats.EntitiesCreated)
+	}
+	if stats.GeneratedMonitors != 1000 {
+		t.Errorf("Expected 1000 generated monitors in stats, got %d", stats.GeneratedMonitors)
+	}
+	if stats.DuplicateMonitors != 0 {
+		t.Errorf("Generated names should be unique, got %d duplicates", stats.DuplicateMonitors)
+	}
+
+	// Spot-check per-instance substitution in the name index and the URL.
+	pulseMap := ecs.NewMap1[components.PulseConfig](world)
+	for _, i := range []int{0, 42, 999} {
+		name := fmt.Sprintf("shard-%d", i)
+		ent, ok := em.LookupMonitor(name)
+		if !ok {
+			t.Fatalf("Missing generated monitor %s", name)
+		}
+		config := pulseMap.Get(ent).Config.(*schema.PulseHTTPConfig)
+		want := fmt.Sprintf("http://shard-%d.internal/health", i)
+		if config.Url != want {
+			t.Errorf("URL substitution for %s: got %q, want %q", name, config.Url, want)
+		}
+	}
+}
+
+// TestGenerateValuesExpansion uses an explicit substitution list.
+func TestGenerateValuesExpansion(t *testing.T) {
+	manifest := `generate:
+  - values: [eu, us, ap]
+    template:
+      name: edge-{{value}}
+      pulse_check:
+        type: http
+        interval: 5s
+        timeout: 3s
+        config:
+          url: http://edge-{{value}}.example.com/health
+monitors: []
+`
+	stats, _, em, err := loadGenerated(t, manifest, DefaultPipelineConfig())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.EntitiesCreated != 3 || stats.GeneratedMonitors != 3 {
+		t.Errorf("Expected 3 generated entities, got created=%d generated=%d",
+			stats.EntitiesCreated, stats.GeneratedMonitors)
+	}
+	for _, region := range []string{"eu", "us", "ap"} {
+		if _, ok := em.LookupMonitor("edge-" + region); !ok {
+			t.Errorf("Missing generated monitor edge-%s", region)
+		}
+	}
+}
+
+// TestGenerateCountCap verifies the configurable expansion cap aborts the
+// load instead of allocating an absurd fleet.
+func TestGenerateCountCap(t *testing.T) {
+	manifest := `generate:
+  - count: 101
+    template:
+      name: shard-{{index}}
+      pulse_check:
+        type: http
+        interval: 5s
+        timeout: 3s
+        config:
+          url: http://shard-{{index}}.internal/health
+monitors: []
+`
+	config := DefaultPipelineConfig()
+	config.MaxGeneratedMonitors = 100
+	_, _, _, err := loadGenerated(t, manifest, config)
+	if err == nil || !strings.Contains(err.Error(), "MaxGeneratedMonitors") {
+		t.Errorf("Expected cap error, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnresolvedEnvVar is returned when a manifest references an environment
+// variable that is not set and has no `${VAR:-default}` fallback. It aborts
+// the load: a missing secret must never silently drop or misconfigure a
+// monitor.
+var ErrUnresolvedEnvVar = errors.New("unresolved environment variable")
+
+// rawScalarTag marks a scalar that must be passed through without
+// interpolation, e.g. a literal block whose `${...}` syntax belongs to
+// another tool. The tag is consumed here and the scalar decodes as a plain
+// string.
+const rawScalarTag = "!raw"
+
+// envVarPattern matches ${VAR} and ${VAR:-default} references. Group 1 is
+// the variable name; group 2 (including the leading ":-") is the default.
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+// interpolateEnv expands ${VAR} and ${VAR:-default} references in scalar
+// string values throughout the node tree, so manifests no longer need an
+// envsubst pre-pass that breaks line numbers in parser errors. lookup is
+// os.LookupEnv in production. lineOffset shifts reported line numbers for
+// trees parsed from a fragment of a larger file (streaming mode).
+func interpolateEnv(node *yaml.Node, lookup func(string) (string, bool), lineOffset int) error {
+	return interpolateNode(node, lookup, "", lineOffset)
+}
+
+func interpolateNode(node *yaml.Node, lookup func(string) (string, bool), field string, lineOffset int) error {
+	if node == nil {
+		return nil
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == rawScalarTag {
+			node.Tag = "!!str"
+			return nil
+		}
+		if node.Tag != "!!str" || !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		var firstErr error
+		node.Value = envVarPattern.ReplaceAllStringFunc(node.Value, func(ref string) string {
+			groups := envVarPattern.FindStringSubmatch(ref)
+			name := groups[1]
+			if val, ok := lookup(name); ok {
+				return val
+			}
+			if groups[2] != "" {
+				return strings.TrimPrefix(groups[2], ":-")
+			}
+			if firstErr == nil {
+				firstErr = fmt.Errorf("field %q (line %d): %w ${%s}",
+					field, node.Line+lineOffset, ErrUnresolvedEnvVar, name)
+			}
+			return ref
+		})
+		return firstErr
+	case yaml.AliasNode:
+		// Interpolate through to the anchored node. Repeat visits from
+		// several aliases are harmless: expanded text has no ${...} left.
+		return interpolateNode(node.Alias, lookup, field, lineOffset)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := interpolateNode(node.Content[i+1], lookup, node.Content[i].Value, lineOffset); err != nil {
+				return err
+			}
+		}
+		return nil
+	default: // document and sequence nodes
+		for _, child := range node.Content {
+			if err := interpolateNode(child, lookup, field, lineOffset); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// monitorNameFromNode extracts the monitor's name from its mapping node
+// without a full decode, for error messages raised before decoding.
+func monitorNameFromNode(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "name" && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,211 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/entities"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// writeManifest writes the YAML to a temp file and returns the path.
+func writeManifest(t *testing.T, yamlContent string) string {
+	t.Helper()
+
+	tmpFile := filepath.Join(t.TempDir(), "monitors.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	return tmpFile
+}
+
+func TestEnvInterpolation_DefaultsAndOverrides(t *testing.T) {
+	t.Setenv("CPRA_TEST_TOKEN", "secret-123")
+	os.Unsetenv("CPRA_TEST_HOST")
+
+	monitors, err := LoadManifestFile(writeManifest(t, `monitors:
+  - name: api
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://${CPRA_TEST_HOST:-fallback.example.com}/health
+        headers:
+          - "Authorization: Bearer ${CPRA_TEST_TOKEN:-none}"
+`))
+	if err != nil {
+		t.Fatalf("LoadManifestFile failed: %v", err)
+	}
+	if len(monitors) != 1 {
+		t.Fatalf("Expected 1 monitor, got %d", len(monitors))
+	}
+	config, ok := monitors[0].Pulse.Config.(*schema.PulseHTTPConfig)
+	if !ok {
+		t.Fatalf("Expected HTTP config, got %T", monitors[0].Pulse.Config)
+	}
+	if config.Url != "http://fallback.example.com/health" {
+		t.Errorf("Default not applied for unset variable: url = %q", config.Url)
+	}
+	if len(config.Headers) != 1 || config.Headers[0] != (schema.Header{Name: "Authorization", Value: "Bearer secret-123"}) {
+		t.Errorf("Set variable should win over default: headers = %v", config.Headers)
+	}
+}
+
+func TestEnvInterpolation_MissingVarFailsLoad(t *testing.T) {
+	os.Unsetenv("CPRA_TEST_NO_SUCH_VAR")
+
+	_, err := LoadManifestFile(writeManifest(t, `monitors:
+  - name: api
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://${CPRA_TEST_NO_SUCH_VAR}/health
+`))
+	if err == nil {
+		t.Fatal("Expected unresolved variable error, got nil")
+	}
+	msg := err.Error()
+	for _, want := range []string{`monitor "api"`, `field "url"`, "line 9", "${CPRA_TEST_NO_SUCH_VAR}"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Error should contain %q: %v", want, err)
+		}
+	}
+}
+
+// TestEnvInterpolation_StreamingPipelineAborts verifies the concurrent
+// pipeline fails the whole load on an unresolved variable instead of
+// skipping the monitor like an ordinary bad entry.
+func TestEnvInterpolation_StreamingPipelineAborts(t *testing.T) {
+	os.Unsetenv("CPRA_TEST_NO_SUCH_VAR")
+
+	tmpFile := writeManifest(t, `monitors:
+  - name: good
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+  - name: broken
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://${CPRA_TEST_NO_SUCH_VAR}/health
+`)
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	pipeline := NewPipeline(&world, em, DefaultPipelineConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := pipeline.Load(ctx, tmpFile)
+	if err == nil {
+		t.Fatal("Expected load to abort on unresolved variable, got nil")
+	}
+	if !strings.Contains(err.Error(), `monitor "broken"`) || !strings.Contains(err.Error(), "${CPRA_TEST_NO_SUCH_VAR}") {
+		t.Errorf("Error should name the monitor and variable: %v", err)
+	}
+}
+
+func TestEnvInterpolation_OptOut(t *testing.T) {
+	tmpFile := writeManifest(t, `monitors:
+  - name: api
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+        headers:
+          - "X-Template: ${LEFT_ALONE}"
+`)
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	config := DefaultPipelineConfig()
+	config.DisableEnvInterpolation = true
+	pipeline := NewPipeline(&world, em, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stats, err := pipeline.Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.EntitiesCreated != 1 {
+		t.Errorf("Expected 1 entity, got %d", stats.EntitiesCreated)
+	}
+}
+
+// TestEnvInterpolation_RawTagSkipped verifies scalars tagged !raw pass
+// through untouched even when they contain ${...} syntax.
+func TestEnvInterpolation_RawTagSkipped(t *testing.T) {
+	monitors, err := LoadManifestFile(writeManifest(t, `monitors:
+  - name: api
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+        headers:
+          - !raw "X-Template: ${NOT_A_VAR}"
+`))
+	if err != nil {
+		t.Fatalf("LoadManifestFile failed: %v", err)
+	}
+	config := monitors[0].Pulse.Config.(*schema.PulseHTTPConfig)
+	if len(config.Headers) != 1 || config.Headers[0] != (schema.Header{Name: "X-Template", Value: "${NOT_A_VAR}"}) {
+		t.Errorf("!raw scalar should not be interpolated: headers = %v", config.Headers)
+	}
+}
+
+// TestEnvInterpolation_LineNumbersPreserved verifies that validation errors
+// after interpolation still report the real manifest line, which is the
+// point of doing this in the parser instead of an envsubst pre-pass.
+func TestEnvInterpolation_LineNumbersPreserved(t *testing.T) {
+	t.Setenv("CPRA_TEST_HOST", "example.com")
+
+	_, err := LoadManifestFile(writeManifest(t, `monitors:
+  - name: api
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://${CPRA_TEST_HOST}/health
+  - name: bad-interval
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 0s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+`))
+	if err == nil {
+		t.Fatal("Expected validation error for bad-interval, got nil")
+	}
+	if !strings.Contains(err.Error(), "line 10") {
+		t.Errorf("Validation error should keep the real line number: %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/controller/entities"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// loadManifestInto loads the file into a fresh world and returns the stats,
+// the world, and the entity manager for component inspection.
+func loadManifestInto(t *testing.T, filename string) (*PipelineStats, *ecs.World, *entities.EntityManager) {
+	t.Helper()
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	pipeline := NewPipeline(&world, em, DefaultPipelineConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	stats, err := pipeline.Load(ctx, filename)
+	if err != nil {
+		t.Fatalf("Load failed for %s: %v", filename, err)
+	}
+	return stats, &world, em
+}
+
+func TestJSONManifestLoad(t *testing.T) {
+	manifest := `{"monitors": [
+		{"name": "api-1", "pulse_check": {"type": "http", "interval": "5s", "timeout": "3s", "config": {"url": "http://example.com/health"}}},
+		{"name": "db-1", "enabled": false, "pulse_check": {"type": "tcp", "interval": "10s", "timeout": "5s", "config": {"host": "localhost", "port": 5432}}}
+	]}`
+	tmpFile := filepath.Join(t.TempDir(), "monitors.json")
+	if err := os.WriteFile(tmpFile, []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, _, em := loadManifestInto(t, tmpFile)
+	if stats.EntitiesCreated != 2 {
+		t.Errorf("Expected 2 entities, got %d", stats.EntitiesCreated)
+	}
+	// 'enabled' must default to true when omitted, as in YAML.
+	if _, ok := em.LookupMonitor("api-1"); !ok {
+		t.Error("api-1 should be registered")
+	}
+}
+
+func TestNDJSONDuplicateNames(t *testing.T) {
+	manifest := `{"name": "web-1", "pulse_check": {"type": "http", "interval": "5s", "timeout": "3s", "config": {"url": "http://example.com/a"}}}
+{"name": "web-1", "pulse_check": {"type": "http", "interval": "5s", "timeout": "3s", "config": {"url": "http://example.com/b"}}}
+`
+	tmpFile := filepath.Join(t.TempDir(), "monitors.ndjson")
+	if err := os.WriteFile(tmpFile, []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, _, _ := loadManifestInto(t, tmpFile)
+	if stats.EntitiesCreated != 1 {
+		t.Errorf("Expected 1 entity after dedupe, got %d", stats.EntitiesCreated)
+	}
+	if stats.DuplicateMonitors != 1 {
+		t.Errorf("Expected 1 duplicate, got %d", stats.DuplicateMonitors)
+	}
+}
+
+func TestNDJSONMalformedLineSkipped(t *testing.T) {
+	manifest := `{"name": "good-1", "pulse_check": {"type": "http", "interval": "5s", "timeout": "3s", "config": {"url": "http://example.com/health"}}}
+{not valid json
+{"name": "good-2", "pulse_check": {"type": "http", "interval": "5s", "timeout": "3s", "config": {"url": "http://example.com/health"}}}
+`
+	tmpFile := filepath.Join(t.TempDir(), "monitors.ndjson")
+	if err := os.WriteFile(tmpFile, []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, _, _ := loadManifestInto(t, tmpFile)
+	if stats.EntitiesCreated != 2 {
+		t.Errorf("Expected 2 entities, got %d", stats.EntitiesCreated)
+	}
+	if stats.SkippedMonitors != 1 {
+		t.Errorf("Expected the malformed line to be skipped, got %d skipped", stats.SkippedMonitors)
+	}
+}
+
+// TestNDJSONLargeMatchesYAML loads 100k monitors from an NDJSON export and
+// from the equivalent YAML manifest, and checks both entity counts and a
+// sampled monitor's pulse component agree between the two parsers.
+func TestNDJSONLargeMatchesYAML(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping large file test in short mode")
+	}
+
+	const count = 100000
+	var ndjson, yaml bytes.Buffer
+	yaml.WriteString("monitors:\n")
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&ndjson, `{"name": "monitor-%06d", "pulse_check": {"type": "http", "interval": "5s", "timeout": "3s", "config": {"url": "http://example.com/health"}}}`+"\n", i)
+		fmt.Fprintf(&yaml, "  - name: monitor-%06d\n", i)
+		yaml.WriteString("    pulse_check:\n")
+		yaml.WriteString("      type: http\n")
+		yaml.WriteString("      interval: 5s\n")
+		yaml.WriteString("      timeout: 3s\n")
+		yaml.WriteString("      config:\n")
+		yaml.WriteString("        url: http://example.com/health\n")
+	}
+
+	tmpDir := t.TempDir()
+	ndjsonFile := filepath.Join(tmpDir, "monitors.ndjson")
+	yamlFile := filepath.Join(tmpDir, "monitors.yaml")
+	if err := os.WriteFile(ndjsonFile, ndjson.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write NDJSON file: %v", err)
+	}
+	if err := os.WriteFile(yamlFile, yaml.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+
+	jsonStats, jsonWorld, jsonEM := loadManifestInto(t, ndjsonFile)
+	yamlStats, yamlWorld, yamlEM := loadManifestInto(t, yamlFile)
+
+	if jsonStats.EntitiesCreated != count {
+		t.Errorf("NDJSON: expected %d entities, got %d", count, jsonStats.EntitiesCreated)
+	}
+	if jsonStats.EntitiesCreated != yamlStats.EntitiesCreated {
+		t.Errorf("Entity counts differ: NDJSON=%d YAML=%d",
+			jsonStats.EntitiesCreated, yamlStats.EntitiesCreated)
+	}
+
+	// Sample one monitor and compare its pulse component across parsers.
+	const sample = "monitor-054321"
+	jsonEnt, ok := jsonEM.LookupMonitor(sample)
+	if !ok {
+		t.Fatalf("NDJSON world is missing %s", sample)
+	}
+	yamlEnt, ok := yamlEM.LookupMonitor(sample)
+	if !ok {
+		t.Fatalf("YAML world is missing %s", sample)
+	}
+	jsonPulse := ecs.NewMap1[components.PulseConfig](jsonWorld).Get(jsonEnt)
+	yamlPulse := ecs.NewMap1[components.PulseConfig](yamlWorld).Get(yamlEnt)
+	if jsonPulse.Type != yamlPulse.Type ||
+		jsonPulse.Interval != yamlPulse.Interval ||
+		jsonPulse.Timeout != yamlPulse.Timeout {
+		t.Errorf("Pulse components differ: NDJSON=%+v YAML=%+v", jsonPulse, yamlPulse)
+	}
+	jsonURL := jsonPulse.Config.(*schema.PulseHTTPConfig).Url
+	yamlURL := yamlPulse.Config.(*schema.PulseHTTPConfig).Url
+	if jsonURL != yamlURL {
+		t.Errorf("URLs differ: NDJSON=%q YAML=%q", jsonURL, yamlURL)
+	}
+}
@@ -0,0 +1,204 @@
+package loader
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cpra/internal/loader/schema"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Finding severities. Errors make `cpra validate` exit nonzero; warnings
+// are advisory.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Finding is one lint result for a manifest: what is wrong, how bad it is,
+// and where. Line is the monitor's line in the manifest (0 when the finding
+// is not tied to a single monitor, e.g. a top-level structure problem).
+type Finding struct {
+	Severity string `json:"severity"`
+	Monitor  string `json:"monitor,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// validDockerActionTypes mirrors the docker target.Type switch in the job
+// factory. The factory only rejects an unknown type when an intervention
+// actually fires, so the linter has to check it up front.
+var validDockerActionTypes = map[string]bool{
+	"":        true, // defaults to restart
+	"restart": true,
+	"stop":    true,
+	"start":   true,
+	"kill":    true,
+	"pause":   true,
+	"unpause": true,
+	"scale":   true,
+}
+
+// LintManifest parses a manifest and reports every problem it can find,
+// including checks that normally only surface at entity or job creation
+// time (unknown docker action types, duplicate names). Unlike
+// LoadManifestFile it is not fail-closed: parse and validation errors
+// become findings and the scan continues to the next monitor. The returned
+// error covers only problems reading the file itself; the slice is never
+// nil so callers can marshal it directly.
+func LintManifest(filename string) ([]Finding, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(strings.ToLower(filename), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	decoder := yaml.NewDecoder(bufio.NewReader(r))
+
+	findings := []Finding{}
+	var topLevel struct {
+		Defaults yaml.Node     `yaml:"defaults"`
+		Routes   schema.Routes `yaml:"routes"`
+		Monitors yaml.Node     `yaml:"monitors"`
+	}
+	if err := decoder.Decode(&topLevel); err != nil {
+		if err == io.EOF {
+			return findings, nil // Empty file is not an error
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("failed to decode top-level: %v", err),
+		})
+		return findings, nil
+	}
+	if topLevel.Monitors.Kind != yaml.SequenceNode {
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Message:  "'monitors' field must be a YAML sequence",
+		})
+		return findings, nil
+	}
+
+	var defaultsNode *yaml.Node
+	if d := resolvedNode(&topLevel.Defaults); d != nil && d.Kind == yaml.MappingNode {
+		defaultsNode = d
+	}
+
+	validator := NewValidator()
+	firstSeen := make(map[string]int) // name -> line of first definition
+	for _, node := range topLevel.Monitors.Content {
+		if defaultsNode != nil {
+			mergeMonitorDefaults(node, defaultsNode)
+		}
+		if err := validateMonitorFields(node); err != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Line:     node.Line,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		if err := interpolateEnv(node, os.LookupEnv, 0); err != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Monitor:  monitorNameFromNode(node),
+				Line:     node.Line,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		var monitor schema.Monitor
+		if err := node.Decode(&monitor); err != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Line:     node.Line,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		// Skip empty or malformed entries, as the pipeline does
+		if monitor.Name == "" && monitor.Pulse.Type == "" {
+			continue
+		}
+
+		if line, dup := firstSeen[monitor.Name]; dup {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Monitor:  monitor.Name,
+				Line:     node.Line,
+				Message:  fmt.Sprintf("duplicate monitor name (first defined at line %d)", line),
+			})
+		} else if monitor.Name != "" {
+			firstSeen[monitor.Name] = node.Line
+		}
+
+		if err := monitor.ResolveNotifyGroups(topLevel.Routes); err != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Monitor:  monitor.Name,
+				Line:     node.Line,
+				Message:  err.Error(),
+			})
+		}
+
+		for _, err := range validator.ValidateAll(&monitor) {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Monitor:  monitor.Name,
+				Line:     node.Line,
+				Message:  err.Error(),
+			})
+		}
+
+		findings = append(findings, semanticFindings(&monitor, node, SeverityWarning)...)
+		findings = append(findings, lintMonitor(&monitor, node.Line)...)
+	}
+	return findings, nil
+}
+
+// lintMonitor holds the checks that go beyond the validator rules and the
+// semantic table: conditions the runtime only catches when a job is created.
+func lintMonitor(monitor *schema.Monitor, line int) []Finding {
+	var findings []Finding
+
+	steps := monitor.Intervention.Steps
+	if len(steps) == 0 && monitor.Intervention.Action != "" {
+		steps = []schema.InterventionStep{{
+			Action: monitor.Intervention.Action,
+			Target: monitor.Intervention.Target,
+		}}
+	}
+	for i, step := range steps {
+		if step.Action != "docker" {
+			continue
+		}
+		target, ok := step.Target.(*schema.InterventionTargetDocker)
+		if !ok || target == nil {
+			continue // decode already rejected mismatched targets
+		}
+		if !validDockerActionTypes[target.Type] {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Monitor:  monitor.Name,
+				Line:     line,
+				Message:  fmt.Sprintf("intervention step %d: unknown docker action type %q", i, target.Type),
+			})
+		}
+	}
+	return findings
+}
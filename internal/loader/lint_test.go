@@ -0,0 +1,167 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lintYAML writes the manifest to a temp file and lints it.
+func lintYAML(t *testing.T, yamlContent string) []Finding {
+	t.Helper()
+
+	tmpFile := filepath.Join(t.TempDir(), "monitors.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	findings, err := LintManifest(tmpFile)
+	if err != nil {
+		t.Fatalf("LintManifest failed: %v", err)
+	}
+	return findings
+}
+
+// requireFinding asserts that exactly one finding matches the severity,
+// line, and message substring.
+func requireFinding(t *testing.T, findings []Finding, severity string, line int, substr string) {
+	t.Helper()
+
+	matches := 0
+	for _, f := range findings {
+		if f.Severity == severity && f.Line == line && strings.Contains(f.Message, substr) {
+			matches++
+		}
+	}
+	if matches != 1 {
+		t.Errorf("expected exactly one %s finding at line %d containing %q, got %d\nall findings: %+v",
+			severity, line, substr, matches, findings)
+	}
+}
+
+func TestLintManifest_CleanManifest(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  - name: web-1
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+`)
+	if len(findings) != 0 {
+		t.Errorf("expected no findings for a clean manifest, got %+v", findings)
+	}
+}
+
+func TestLintManifest_CollectsAllFindings(t *testing.T) {
+	// One broken manifest exercising every lint category; the scan must
+	// not stop at the first problem the way LoadManifestFile does.
+	findings := lintYAML(t, `monitors:
+  - name: bad-type
+    enabled: true
+    pulse_check:
+      type: smtp
+      interval: 5s
+      timeout: 3s
+  - name: zero-interval
+    enabled: true
+    pulse_check:
+      type: tcp
+      interval: 0s
+      timeout: 3s
+      config:
+        host: example.com
+        port: 80
+  - name: tight-timeout
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 5s
+      config:
+        url: http://example.com/health
+  - name: bad-docker
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+    intervention:
+      action: docker
+      target:
+        type: reboot
+        container: web
+  - name: zero-interval
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+`)
+
+	requireFinding(t, findings, SeverityError, 2, "unknown pulse type")
+	requireFinding(t, findings, SeverityError, 8, "interval must be positive")
+	requireFinding(t, findings, SeverityWarning, 22, "timeout equals interval")
+	requireFinding(t, findings, SeverityError, 25, `unknown docker action type "reboot"`)
+	requireFinding(t, findings, SeverityError, 38, "duplicate monitor name (first defined at line 8)")
+}
+
+func TestLintManifest_MultipleErrorsOnOneMonitor(t *testing.T) {
+	// The validator normally stops at the first rule failure; the linter
+	// reports all of them.
+	findings := lintYAML(t, `monitors:
+  - name: broken
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 0s
+      timeout: 3s
+`)
+	requireFinding(t, findings, SeverityError, 2, "requires URL")
+	requireFinding(t, findings, SeverityError, 2, "interval must be positive")
+}
+
+func TestLintManifest_InvalidLabels(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  - name: labeled
+    enabled: true
+    labels:
+      "bad key!": payments
+      team: ok
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+`)
+	requireFinding(t, findings, SeverityError, 2, "invalid label key")
+}
+
+func TestLintManifest_UnknownFieldIsError(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  - name: web-1
+    enabled: true
+    retry_count: 3
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+`)
+	requireFinding(t, findings, SeverityError, 2, "retry_count")
+}
+
+func TestLintManifest_NotASequence(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  name: web-1
+`)
+	requireFinding(t, findings, SeverityError, 0, "must be a YAML sequence")
+}
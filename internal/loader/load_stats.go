@@ -0,0 +1,100 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	"cpra/internal/loader/schema"
+)
+
+// highRetriesThreshold is the retries count above which a monitor draws a
+// load warning. Each retry holds a worker for up to the full timeout, so
+// double-digit retry counts usually mean the interval should grow instead.
+const highRetriesThreshold = 5
+
+// loadTally accumulates the per-type breakdown and non-fatal warnings for
+// PipelineStats. It is owned by the sequential batch collector, so no
+// locking is needed while monitors stream through; the pipeline publishes
+// it under its mutex when the collector exits.
+type loadTally struct {
+	pulseTypes        map[string]int64
+	codeColors        map[string]int64
+	withInterventions int64
+	warnings          []LoadWarning
+	warningsDropped   int64
+	maxWarnings       int
+}
+
+func newLoadTally(maxWarnings int) *loadTally {
+	if maxWarnings <= 0 {
+		maxWarnings = 100
+	}
+	return &loadTally{
+		pulseTypes:  make(map[string]int64),
+		codeColors:  make(map[string]int64),
+		maxWarnings: maxWarnings,
+	}
+}
+
+// record tallies one validated monitor and gathers its warnings.
+func (t *loadTally) record(vm ValidatedMonitor) {
+	monitor := &vm.Monitor
+	t.pulseTypes[strings.ToLower(monitor.Pulse.Type)]++
+	for color := range monitor.Codes {
+		t.codeColors[color]++
+	}
+	if monitor.Intervention.Action != "" || len(monitor.Intervention.Steps) > 0 {
+		t.withInterventions++
+	}
+
+	if !monitor.Enabled {
+		t.warn(vm, "monitor is disabled and will not be scheduled")
+	}
+	// Cross-field contradictions from the shared semantic rule table; under
+	// StrictValidation these monitors never reach the collector, so the
+	// warnings only appear on permissive loads.
+	for _, issue := range checkSemantics(monitor) {
+		t.warn(vm, issue.message)
+	}
+	if retries := configRetries(monitor.Pulse.Config); retries > highRetriesThreshold {
+		t.warn(vm, fmt.Sprintf("retries=%d is unusually high; a failing check holds a worker for every attempt", retries))
+	}
+}
+
+// warn appends one warning, counting instead of retaining past the cap.
+func (t *loadTally) warn(vm ValidatedMonitor, message string) {
+	if len(t.warnings) >= t.maxWarnings {
+		t.warningsDropped++
+		return
+	}
+	t.warnings = append(t.warnings, LoadWarning{
+		Monitor: vm.Monitor.Name,
+		File:    vm.File,
+		Line:    vm.Line,
+		Message: message,
+	})
+}
+
+// configRetries extracts the retries knob from a per-type pulse config.
+// Heartbeat is push-based and has no retries.
+func configRetries(config schema.PulseConfig) int {
+	switch cfg := config.(type) {
+	case *schema.PulseHTTPConfig:
+		return cfg.Retries
+	case *schema.PulseTCPConfig:
+		return cfg.Retries
+	case *schema.PulseICMPConfig:
+		return cfg.Retries
+	case *schema.PulseUDPConfig:
+		return cfg.Retries
+	case *schema.PulseNTPConfig:
+		return cfg.Retries
+	case *schema.PulseDNSConfig:
+		return cfg.Retries
+	case *schema.PulseGRPCConfig:
+		return cfg.Retries
+	case *schema.PulseDockerConfig:
+		return cfg.Retries
+	}
+	return 0
+}
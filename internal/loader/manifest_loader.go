@@ -0,0 +1,97 @@
+package loader
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cpra/internal/loader/schema"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadManifestFile parses and validates a manifest file in one pass, without
+// creating entities. Unlike the streaming pipeline it is fail-closed: the
+// first parse or validation error aborts the whole load, naming the monitor
+// and line. Reload paths use it so a bad edit never half-applies.
+func LoadManifestFile(filename string) ([]schema.Monitor, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(strings.ToLower(filename), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	decoder := yaml.NewDecoder(bufio.NewReader(r))
+
+	// A `defaults` block may hold anchored templates (ignored here) or a
+	// mapping of monitor defaults that is merged into every entry, matching
+	// the pipeline's top-level handling.
+	var topLevel struct {
+		Defaults yaml.Node     `yaml:"defaults"`
+		Routes   schema.Routes `yaml:"routes"`
+		Monitors yaml.Node     `yaml:"monitors"`
+	}
+	if err := decoder.Decode(&topLevel); err != nil {
+		if err == io.EOF {
+			return nil, nil // Empty file is not an error
+		}
+		return nil, fmt.Errorf("failed to decode top-level: %w", err)
+	}
+	if topLevel.Monitors.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("'monitors' field must be a YAML sequence")
+	}
+	var defaultsNode *yaml.Node
+	if d := resolvedNode(&topLevel.Defaults); d != nil && d.Kind == yaml.MappingNode {
+		defaultsNode = d
+	}
+
+	validator := NewValidator()
+	monitors := make([]schema.Monitor, 0, len(topLevel.Monitors.Content))
+	for _, node := range topLevel.Monitors.Content {
+		usedDefaults := defaultsNode != nil && mergeMonitorDefaults(node, defaultsNode)
+		// The node walk reports unknown fields and malformed entries with
+		// the monitor name and line number.
+		if err := validateMonitorFields(node); err != nil {
+			return nil, err
+		}
+		if err := interpolateEnv(node, os.LookupEnv, 0); err != nil {
+			if name := monitorNameFromNode(node); name != "" {
+				return nil, fmt.Errorf("monitor %q: %w", name, err)
+			}
+			return nil, fmt.Errorf("monitor at line %d: %w", node.Line, err)
+		}
+		var monitor schema.Monitor
+		if err := node.Decode(&monitor); err != nil {
+			return nil, fmt.Errorf("monitor at line %d: %w", node.Line, err)
+		}
+		// Skip empty or malformed entries, as the pipeline does
+		if monitor.Name == "" && monitor.Pulse.Type == "" {
+			continue
+		}
+		if err := monitor.ResolveNotifyGroups(topLevel.Routes); err != nil {
+			return nil, fmt.Errorf("monitor %q (line %d): %w", monitor.Name, node.Line, err)
+		}
+		if err := validator.Validate(&monitor); err != nil {
+			if usedDefaults {
+				return nil, fmt.Errorf("monitor %q (line %d, with manifest defaults applied): %w",
+					monitor.Name, node.Line, err)
+			}
+			return nil, fmt.Errorf("monitor %q (line %d): %w", monitor.Name, node.Line, err)
+		}
+		monitors = append(monitors, monitor)
+	}
+	return monitors, nil
+}
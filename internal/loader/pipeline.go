@@ -0,0 +1,1161 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cpra/internal/controller/entities"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+)
+
+// stringBuilderPool reduces allocations by reusing strings.Builder instances.
+// Each builder is pre-allocated with capacity for a typical monitor (~1KB).
+var stringBuilderPool = sync.Pool{
+	New: func() interface{} {
+		b := &strings.Builder{}
+		b.Grow(1024) // Pre-allocate for typical monitor size
+		return b
+	},
+}
+
+func getStringBuilder() *strings.Builder {
+	return stringBuilderPool.Get().(*strings.Builder)
+}
+
+func putStringBuilder(b *strings.Builder) {
+	b.Reset()
+	stringBuilderPool.Put(b)
+}
+
+// Pipeline orchestrates concurrent loading of monitor configurations.
+type Pipeline struct {
+	startTime     time.Time
+	world         *ecs.World
+	entityManager *entities.EntityManager
+	validator     *MonitorValidator
+	rawChan       chan RawMonitor
+	validatedChan chan ValidatedMonitor
+	batchChan     chan MonitorBatch
+	config        PipelineConfig
+	validated     int64
+	skipped       int64
+	duplicates    int64
+	generated     int64
+	batched       int64
+	created       int64
+	pulseRate     float64
+	rawParsed     int64
+	tally         *loadTally // published by batchCollector on exit; guarded by mu
+	mu            sync.RWMutex
+}
+
+// NewPipeline creates a new concurrent loading pipeline.
+func NewPipeline(world *ecs.World, entityManager *entities.EntityManager, config PipelineConfig) *Pipeline {
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU() * 2
+	}
+	if config.Workers > 1000 {
+		config.Workers = 1000
+	}
+	if config.MaxGeneratedMonitors <= 0 {
+		config.MaxGeneratedMonitors = 1_000_000
+	}
+
+	return &Pipeline{
+		config:        config,
+		world:         world,
+		entityManager: entityManager,
+		validator:     NewValidator(),
+		rawChan:       make(chan RawMonitor, config.RawChannelSize),
+		validatedChan: make(chan ValidatedMonitor, config.ValidatedChannelSize),
+		batchChan:     make(chan MonitorBatch, config.BatchChannelSize),
+	}
+}
+
+// Load runs the complete pipeline to load monitors from a file.
+// Uses errgroup for clean error propagation - first error cancels all stages.
+// All goroutines are tracked to prevent leaks (per "Concurrency in Go" p. 90).
+func (p *Pipeline) Load(ctx context.Context, filename string) (*PipelineStats, error) {
+	switch p.config.DuplicatePolicy {
+	case "", DuplicatePolicyError, DuplicatePolicyOverride:
+	default:
+		return nil, fmt.Errorf("unknown duplicate policy %q (valid: %q, %q)",
+			p.config.DuplicatePolicy, DuplicatePolicyError, DuplicatePolicyOverride)
+	}
+	p.startTime = time.Now()
+
+	// errgroup.WithContext: first error cancels ctx, Wait returns first error
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Stage 1: Sequential file reading (I/O bound)
+	g.Go(func() error {
+		defer close(p.rawChan)
+		return p.readYAMLNodes(ctx, filename)
+	})
+
+	// Stage 2: Fan-out to workers (CPU bound parse + validate)
+	// Workers are tracked via errgroup to prevent leaks on context cancellation.
+	var workerWg sync.WaitGroup
+	for i := 0; i < p.config.Workers; i++ {
+		workerWg.Add(1)
+		g.Go(func() error {
+			defer workerWg.Done()
+			return p.worker(ctx)
+		})
+	}
+
+	// Close validated channel when all workers are done.
+	// This is tracked via errgroup to ensure clean shutdown.
+	g.Go(func() error {
+		workerWg.Wait()
+		close(p.validatedChan)
+		return nil
+	})
+
+	// Stage 3: Fan-in and batch collection
+	g.Go(func() error {
+		defer close(p.batchChan)
+		return p.batchCollector(ctx)
+	})
+
+	// Stage 4: Sequential entity creation (Ark constraint)
+	g.Go(func() error {
+		return p.createEntities(ctx)
+	})
+
+	// Wait for all stages - returns first error. Stats are returned even on
+	// failure so a cancelled load can report how far it got; entity creation
+	// stops at a batch boundary, so the world holds only complete batches.
+	if err := g.Wait(); err != nil {
+		return p.buildStats(), err
+	}
+
+	return p.buildStats(), nil
+}
+
+// readYAMLNodes reads the manifest path and sends raw nodes to the channel.
+// The path may be a single file or a directory; a directory is read as its
+// *.yaml/*.yml files in sorted order, so per-team manifest fragments load
+// deterministically without being concatenated first. Any file failing to
+// read aborts the whole load.
+func (p *Pipeline) readYAMLNodes(ctx context.Context, path string) error {
+	files, err := ManifestFiles(path)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := p.readManifestFile(ctx, f); err != nil {
+			if len(files) > 1 || f != path {
+				return fmt.Errorf("%s: %w", f, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// ManifestFiles resolves a manifest path to the list of files to load. A
+// regular file is returned as-is; a directory yields its *.yaml/*.yml
+// entries in sorted (deterministic) order. An empty directory is an error
+// so a typo'd path does not silently load nothing.
+func ManifestFiles(path string) ([]string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat manifest path: %w", err)
+	}
+	if !stat.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest directory: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := strings.ToLower(e.Name())
+		if strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") {
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .yaml or .yml files in directory %s", path)
+	}
+	// os.ReadDir sorts by filename, so the order is already deterministic.
+	return files, nil
+}
+
+// Manifest formats accepted by PipelineConfig.Format and inferred from
+// file extensions by manifestFormat.
+const (
+	formatYAML   = "yaml"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+// manifestFormat picks the parser for a file: an explicit configured format
+// wins, otherwise the extension decides (.json is a JSON document,
+// .ndjson/.jsonl is one monitor object per line, anything else is YAML).
+// A .gz suffix is stripped before the extension check.
+func manifestFormat(filename, configured string) string {
+	if configured != "" {
+		return configured
+	}
+	name := strings.TrimSuffix(strings.ToLower(filename), ".gz")
+	switch {
+	case strings.HasSuffix(name, ".json"):
+		return formatJSON
+	case strings.HasSuffix(name, ".ndjson"), strings.HasSuffix(name, ".jsonl"):
+		return formatNDJSON
+	}
+	return formatYAML
+}
+
+// readManifestFile reads one manifest file and sends raw monitors to the
+// channel, dispatching on format. YAML uses streaming mode if configured,
+// otherwise loads the full yaml.Node tree.
+func (p *Pipeline) readManifestFile(ctx context.Context, filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	// Get file size for progress reporting
+	var totalSize int64
+	if stat, err := file.Stat(); err == nil {
+		totalSize = stat.Size()
+	}
+
+	var r io.Reader = file
+	isGzip := strings.HasSuffix(strings.ToLower(filename), ".gz")
+	if isGzip {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+		totalSize = 0 // Can't know decompressed size
+	}
+
+	switch manifestFormat(filename, p.config.Format) {
+	case formatJSON:
+		return p.readJSONManifest(ctx, r)
+	case formatNDJSON:
+		return p.readNDJSON(ctx, r, filename, totalSize)
+	}
+
+	// Use streaming mode for large files to avoid OOM
+	if p.config.StreamingMode {
+		return p.readYAMLStreaming(ctx, r, filename, totalSize)
+	}
+
+	// Traditional mode: load full yaml.Node tree
+	return p.readYAMLTraditional(ctx, r, filename, totalSize)
+}
+
+// readJSONManifest streams monitors out of a single JSON document matching
+// schema.Manifest. The decoder walks tokens to the "monitors" array so each
+// element is dispatched as its own raw message instead of materialising the
+// whole slice.
+func (p *Pipeline) readJSONManifest(ctx context.Context, r io.Reader) error {
+	bufSize := p.config.BufferSize
+	if bufSize <= 0 {
+		bufSize = 64 * 1024
+	}
+	dec := json.NewDecoder(bufio.NewReaderSize(r, bufSize))
+
+	tok, err := dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil // Empty file is not an error
+		}
+		return fmt.Errorf("failed to decode JSON manifest: %w", err)
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("JSON manifest must be an object with a 'monitors' array")
+	}
+
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode JSON manifest: %w", err)
+		}
+		key, _ := keyTok.(string)
+		if key != "monitors" {
+			// Skip unrelated top-level values (e.g. a defaults block).
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("failed to decode JSON manifest: %w", err)
+			}
+			continue
+		}
+
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return fmt.Errorf("'monitors' field must be a JSON array")
+		}
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return fmt.Errorf("failed to decode monitor: %w", err)
+			}
+			select {
+			case p.rawChan <- RawMonitor{JSONBytes: raw}:
+				atomic.AddInt64(&p.rawParsed, 1)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("failed to decode JSON manifest: %w", err)
+		}
+	}
+	return nil
+}
+
+// readNDJSON reads one monitor object per line, the flat-memory path for
+// database exports: like YAML streaming mode, memory stays bounded at any
+// row count. Line numbers are carried for error reporting.
+func (p *Pipeline) readNDJSON(ctx context.Context, r io.Reader, filename string, totalSize int64) error {
+	cr := &countingReader{reader: r, totalSize: totalSize}
+
+	bufSize := p.config.BufferSize
+	if bufSize <= 0 {
+		bufSize = 4 * 1024 * 1024
+	}
+	scanner := bufio.NewScanner(cr)
+	scanner.Buffer(make([]byte, bufSize), bufSize)
+
+	var (
+		lineNum       int
+		lastProgress  time.Time
+		progressEvery = p.config.ProgressInterval
+	)
+	if progressEvery <= 0 {
+		progressEvery = 250 * time.Millisecond
+	}
+
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		// Check for context cancellation and report progress periodically
+		if lineNum%10000 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			if p.config.ProgressCallback != nil && time.Since(lastProgress) >= progressEvery {
+				lastProgress = time.Now()
+				p.config.ProgressCallback(LoadProgress{
+					BytesRead:      cr.bytesRead,
+					TotalBytes:     totalSize,
+					MonitorsParsed: atomic.LoadInt64(&p.rawParsed),
+					Elapsed:        time.Since(p.startTime),
+					Stage:          "reading",
+				})
+			}
+		}
+
+		// Copy the line: the scanner reuses its buffer.
+		raw := RawMonitor{JSONBytes: append([]byte(nil), line...), File: filename, Line: lineNum}
+		select {
+		case p.rawChan <- raw:
+			atomic.AddInt64(&p.rawParsed, 1)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scanner error: %w", err)
+	}
+	return nil
+}
+
+// readYAMLTraditional loads the full yaml.Node tree into memory.
+// Fast but uses ~500MB+ for 1M monitors - may OOM.
+func (p *Pipeline) readYAMLTraditional(ctx context.Context, r io.Reader, filename string, totalSize int64) error {
+	bufSize := p.config.BufferSize
+	if bufSize <= 0 {
+		bufSize = 64 * 1024
+	}
+	bufr := bufio.NewReaderSize(r, bufSize)
+
+	decoder := yaml.NewDecoder(bufr)
+	decoder.KnownFields(p.config.StrictUnknownFields)
+
+	// Decode top-level structure. A `defaults` block is accepted (and
+	// otherwise ignored) so configs can hold anchored templates
+	// (defaults: &defaults ...) without tripping strict mode.
+	var topLevel struct {
+		Defaults yaml.Node     `yaml:"defaults"`
+		Generate yaml.Node     `yaml:"generate"`
+		Routes   schema.Routes `yaml:"routes"`
+		Monitors yaml.Node     `yaml:"monitors"`
+	}
+	if err := decoder.Decode(&topLevel); err != nil {
+		if err == io.EOF {
+			return nil // Empty file is not an error
+		}
+		return fmt.Errorf("failed to decode top-level: %w", err)
+	}
+
+	hasGenerate := resolvedNode(&topLevel.Generate) != nil && topLevel.Generate.Kind != 0
+	if topLevel.Monitors.Kind != yaml.SequenceNode && !hasGenerate {
+		return fmt.Errorf("'monitors' field must be a YAML sequence")
+	}
+
+	// A mapping-shaped defaults block is merged into each monitor by the
+	// workers; any other shape is an anchor template and only referenced
+	// explicitly.
+	var defaultsNode *yaml.Node
+	if d := resolvedNode(&topLevel.Defaults); d != nil && d.Kind == yaml.MappingNode {
+		defaultsNode = d
+	}
+
+	// Send each monitor node to the workers
+	for _, node := range topLevel.Monitors.Content {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			raw := RawMonitor{Node: node, Defaults: defaultsNode, Routes: topLevel.Routes, File: filename, Line: node.Line}
+			select {
+			case p.rawChan <- raw:
+				atomic.AddInt64(&p.rawParsed, 1)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	if hasGenerate {
+		return p.sendGenerated(ctx, &topLevel.Generate, defaultsNode, topLevel.Routes, filename)
+	}
+	return nil
+}
+
+// sendGenerated expands the `generate` section and feeds the resulting
+// monitor nodes to the workers, so generated monitors share the parse,
+// validation, and duplicate-detection path of literal ones.
+func (p *Pipeline) sendGenerated(ctx context.Context, generate, defaults *yaml.Node, routes schema.Routes, filename string) error {
+	nodes, err := expandGenerate(generate, p.config.MaxGeneratedMonitors)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		raw := RawMonitor{Node: node, Defaults: defaults, Routes: routes, File: filename, Line: node.Line}
+		select {
+		case p.rawChan <- raw:
+			atomic.AddInt64(&p.rawParsed, 1)
+			atomic.AddInt64(&p.generated, 1)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// readYAMLStreaming parses YAML line-by-line to minimize memory usage.
+// Accumulates lines for each monitor (between `-` markers) and sends raw bytes.
+// Uses ~10MB for 1M monitors instead of 500MB+.
+func (p *Pipeline) readYAMLStreaming(ctx context.Context, r io.Reader, filename string, totalSize int64) error {
+	// Create counting reader for progress
+	cr := &countingReader{reader: r, totalSize: totalSize}
+
+	// Use large buffer for better I/O performance
+	bufSize := p.config.BufferSize
+	if bufSize <= 0 {
+		bufSize = 4 * 1024 * 1024 // 4MB default for streaming
+	}
+	scanner := bufio.NewScanner(cr)
+	scanner.Buffer(make([]byte, bufSize), bufSize)
+
+	var (
+		currentMonitor = getStringBuilder() // Use pooled builder
+		inMonitors     bool                 // True after seeing "monitors:" line
+		inMonitor      bool                 // True when accumulating a monitor
+		lineNum        int
+		monitorLine    int
+		lastProgress   time.Time
+		progressEvery  = p.config.ProgressInterval
+		gcCounter      int             // Counter for periodic GC hints
+		header         strings.Builder // Everything before "monitors:", for the defaults block
+		defaultsNode   *yaml.Node
+		routes         schema.Routes
+	)
+	defer putStringBuilder(currentMonitor)
+
+	if progressEvery <= 0 {
+		progressEvery = 250 * time.Millisecond
+	}
+
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		// Check for context cancellation and report progress periodically
+		if lineNum%10000 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			// Report progress
+			if p.config.ProgressCallback != nil && time.Since(lastProgress) >= progressEvery {
+				lastProgress = time.Now()
+				p.config.ProgressCallback(LoadProgress{
+					BytesRead:      cr.bytesRead,
+					TotalBytes:     totalSize,
+					MonitorsParsed: atomic.LoadInt64(&p.rawParsed),
+					Elapsed:        time.Since(p.startTime),
+					Stage:          "reading",
+				})
+			}
+		}
+
+		trimmed := strings.TrimSpace(line)
+
+		// Look for "monitors:" to start parsing
+		if !inMonitors {
+			if trimmed == "monitors:" || strings.HasPrefix(trimmed, "monitors:") {
+				inMonitors = true
+				var generateNode *yaml.Node
+				defaultsNode, generateNode, routes = parseStreamingHeader(header.String())
+				if generateNode != nil {
+					if err := p.sendGenerated(ctx, generateNode, defaultsNode, routes, filename); err != nil {
+						return err
+					}
+				}
+			} else {
+				header.WriteString(line)
+				header.WriteByte('\n')
+			}
+			continue
+		}
+
+		// Detect start of a new monitor (line starting with "- " at proper indent)
+		// A monitor entry starts with "  - " (2 space indent + dash)
+		if len(line) >= 2 && line[0] == ' ' && line[1] == ' ' {
+			restTrimmed := strings.TrimLeft(line[2:], " ")
+			if strings.HasPrefix(restTrimmed, "- ") || restTrimmed == "-" {
+				// Flush previous monitor
+				if inMonitor && currentMonitor.Len() > 0 {
+					raw := RawMonitor{
+						RawBytes: []byte(currentMonitor.String()),
+						Defaults: defaultsNode,
+						Routes:   routes,
+						File:     filename,
+						Line:     monitorLine,
+					}
+					select {
+					case p.rawChan <- raw:
+						atomic.AddInt64(&p.rawParsed, 1)
+						gcCounter++
+						// Hint GC every 50k monitors to reclaim memory
+						if gcCounter%50000 == 0 {
+							runtime.GC()
+						}
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+					currentMonitor.Reset()
+				}
+				inMonitor = true
+				monitorLine = lineNum
+			}
+		}
+
+		// Accumulate lines for current monitor
+		if inMonitor {
+			currentMonitor.WriteString(line)
+			currentMonitor.WriteByte('\n')
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scanner error: %w", err)
+	}
+
+	// A manifest may consist only of header sections (e.g. a generate block
+	// with no literal monitors); parse it when "monitors:" never appeared.
+	if !inMonitors {
+		defaults, generate, headerRoutes := parseStreamingHeader(header.String())
+		if generate != nil {
+			return p.sendGenerated(ctx, generate, defaults, headerRoutes, filename)
+		}
+	}
+
+	// Flush last monitor
+	if inMonitor && currentMonitor.Len() > 0 {
+		raw := RawMonitor{
+			RawBytes: []byte(currentMonitor.String()),
+			Defaults: defaultsNode,
+			Routes:   routes,
+			File:     filename,
+			Line:     monitorLine,
+		}
+		select {
+		case p.rawChan <- raw:
+			atomic.AddInt64(&p.rawParsed, 1)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Final progress report
+	if p.config.ProgressCallback != nil {
+		p.config.ProgressCallback(LoadProgress{
+			BytesRead:      cr.bytesRead,
+			TotalBytes:     totalSize,
+			MonitorsParsed: atomic.LoadInt64(&p.rawParsed),
+			Elapsed:        time.Since(p.startTime),
+			Stage:          "reading",
+		})
+	}
+
+	return nil
+}
+
+// parseStreamingHeader parses the manifest header (everything before the
+// monitors: line) and returns its defaults block when mapping-shaped, plus
+// the generate and routes sections. Streaming mode parses each monitor
+// fragment independently, so these blocks are threaded to the workers
+// rather than resolved through anchors.
+func parseStreamingHeader(header string) (defaults, generate *yaml.Node, routes schema.Routes) {
+	if strings.TrimSpace(header) == "" {
+		return nil, nil, nil
+	}
+	var top struct {
+		Defaults yaml.Node     `yaml:"defaults"`
+		Generate yaml.Node     `yaml:"generate"`
+		Routes   schema.Routes `yaml:"routes"`
+	}
+	if err := yaml.Unmarshal([]byte(header), &top); err != nil {
+		return nil, nil, nil
+	}
+	if d := resolvedNode(&top.Defaults); d != nil && d.Kind == yaml.MappingNode {
+		defaults = d
+	}
+	if g := resolvedNode(&top.Generate); g != nil && top.Generate.Kind != 0 {
+		generate = &top.Generate
+	}
+	return defaults, generate, top.Routes
+}
+
+// countingReader wraps an io.Reader to track bytes read.
+type countingReader struct {
+	reader    io.Reader
+	bytesRead int64
+	totalSize int64
+}
+
+func (c *countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.reader.Read(p)
+	c.bytesRead += int64(n)
+	return n, err
+}
+
+// worker processes raw YAML nodes or bytes, parses them, and validates them.
+// A non-nil error (currently only unresolved environment variables) aborts
+// the whole load via the errgroup; ordinary bad entries are skipped.
+func (p *Pipeline) worker(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case raw, ok := <-p.rawChan:
+			if !ok {
+				return nil
+			}
+
+			var monitor schema.Monitor
+			var err error
+			var usedDefaults bool
+
+			// Handle streaming mode (raw bytes) vs traditional mode (yaml.Node)
+			if raw.JSONBytes != nil {
+				// JSON/NDJSON manifests: each message is one monitor object.
+				err = json.Unmarshal(raw.JSONBytes, &monitor)
+			} else if raw.RawBytes != nil {
+				// Streaming mode: parse from raw YAML bytes
+				// The bytes represent a single monitor entry like:
+				//   - name: foo
+				//     pulse: ...
+				// We need to parse just the map contents (without the leading "- ")
+				usedDefaults, err = p.parseMonitorFromBytes(raw.RawBytes, raw.Line, raw.Defaults, &monitor)
+			} else if raw.Node != nil {
+				// Traditional mode: decode from yaml.Node.
+				// Strict field checking happens on the node itself: Monitor's
+				// custom unmarshaler hides its keys from the decoder's
+				// KnownFields, and the node walk resolves anchors and merge
+				// keys the same way the decoder does.
+				if raw.Defaults != nil {
+					usedDefaults = mergeMonitorDefaults(raw.Node, raw.Defaults)
+				}
+				if p.config.StrictUnknownFields {
+					err = validateMonitorFields(raw.Node)
+				}
+				if err == nil && !p.config.DisableEnvInterpolation {
+					err = interpolateEnv(raw.Node, os.LookupEnv, 0)
+				}
+				if err == nil {
+					err = raw.Node.Decode(&monitor)
+				}
+			} else {
+				atomic.AddInt64(&p.skipped, 1)
+				continue
+			}
+
+			if err != nil {
+				// Unresolved environment variables are a config error, not a
+				// bad entry to skip: fail the load so a missing secret never
+				// silently drops monitors.
+				if errors.Is(err, ErrUnresolvedEnvVar) {
+					if name := monitorNameFromNode(raw.Node); name != "" {
+						return fmt.Errorf("monitor %q: %w", name, err)
+					}
+					return err
+				}
+				atomic.AddInt64(&p.skipped, 1)
+				if p.config.LogValidationErrors && p.config.Logger != nil {
+					p.config.Logger.Warnf("Parse failed for monitor (line %d): %v", raw.Line, err)
+				}
+				continue
+			}
+
+			// Skip empty or malformed entries
+			if monitor.Name == "" && monitor.Pulse.Type == "" {
+				atomic.AddInt64(&p.skipped, 1)
+				continue
+			}
+
+			// Resolve notify groups before validation so the merged codes
+			// are what gets validated. An unknown group is a config error,
+			// not a bad entry to skip: fail the load, like a missing env
+			// var, so a typo never silently drops a route's alerts.
+			if err := monitor.ResolveNotifyGroups(raw.Routes); err != nil {
+				return fmt.Errorf("monitor %q (line %d): %w", monitor.Name, raw.Line, err)
+			}
+
+			// Validate
+			if err := p.validator.Validate(&monitor); err != nil {
+				atomic.AddInt64(&p.skipped, 1)
+				// Log validation errors when enabled for debugging bad configs
+				if p.config.LogValidationErrors && p.config.Logger != nil {
+					note := ""
+					if usedDefaults {
+						note = " (manifest defaults applied)"
+					}
+					p.config.Logger.Warnf("Validation failed for monitor %q (line %d)%s: %v",
+						monitor.Name, raw.Line, note, err)
+				}
+				if p.config.FailFast {
+					return nil
+				}
+				continue
+			}
+
+			// Semantic pass: cross-field contradictions are warnings by
+			// default (recorded by the batch collector's tally) and reject
+			// the monitor under StrictValidation.
+			if p.config.StrictValidation {
+				if issues := checkSemantics(&monitor); len(issues) > 0 {
+					atomic.AddInt64(&p.skipped, 1)
+					if p.config.LogValidationErrors && p.config.Logger != nil {
+						p.config.Logger.Warnf("Semantic validation failed for monitor %q (line %d): %s",
+							monitor.Name, raw.Line, issues[0].message)
+					}
+					if p.config.FailFast {
+						return nil
+					}
+					continue
+				}
+			}
+
+			atomic.AddInt64(&p.validated, 1)
+
+			// Send to batch collector
+			select {
+			case p.validatedChan <- ValidatedMonitor{Monitor: monitor, File: raw.File, Line: raw.Line}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// parseMonitorFromBytes parses a single monitor from raw YAML bytes.
+// The input is expected to be a list item like:
+//
+//   - name: foo
+//     pulse:
+//     type: http
+//     ...
+//
+// We convert it to a proper YAML document for parsing. startLine is the
+// monitor's line in the source file, used to offset line numbers in
+// interpolation errors; defaults, when non-nil, is merged into the monitor
+// before decoding. Reports whether defaults contributed any fields.
+func (p *Pipeline) parseMonitorFromBytes(rawBytes []byte, startLine int, defaults *yaml.Node, monitor *schema.Monitor) (bool, error) {
+	// The raw bytes contain a list item starting with "  - "
+	// We need to convert it to a standalone YAML document
+	// by stripping the leading "  - " and reducing indentation
+
+	lines := strings.Split(string(rawBytes), "\n")
+	if len(lines) == 0 {
+		return false, fmt.Errorf("empty monitor bytes")
+	}
+
+	var normalized strings.Builder
+	normalized.Grow(len(rawBytes))
+
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		if i == 0 {
+			// First line: strip the leading spaces and "- " or just "-"
+			trimmed := strings.TrimLeft(line, " ")
+			if strings.HasPrefix(trimmed, "- ") {
+				// "- name: foo" -> "name: foo"
+				normalized.WriteString(trimmed[2:])
+				normalized.WriteByte('\n')
+			} else if trimmed == "-" {
+				// Just "-", next lines have the content
+				continue
+			} else {
+				normalized.WriteString(trimmed)
+				normalized.WriteByte('\n')
+			}
+		} else {
+			// Subsequent lines: remove 4 spaces of indentation (list item indent)
+			if len(line) >= 4 && line[:4] == "    " {
+				normalized.WriteString(line[4:])
+			} else if len(line) >= 2 && line[:2] == "  " {
+				// Sometimes only 2 space indent after the "- "
+				normalized.WriteString(line[2:])
+			} else {
+				normalized.WriteString(strings.TrimLeft(line, " "))
+			}
+			normalized.WriteByte('\n')
+		}
+	}
+
+	if p.config.DisableEnvInterpolation && defaults == nil {
+		return false, yaml.Unmarshal([]byte(normalized.String()), monitor)
+	}
+
+	// Parse to a node first so the defaults merge and interpolation see
+	// scalar boundaries and !raw tags, then decode the expanded tree.
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(normalized.String()), &doc); err != nil {
+		return false, err
+	}
+	usedDefaults := defaults != nil && mergeMonitorDefaults(&doc, defaults)
+	if !p.config.DisableEnvInterpolation {
+		if err := interpolateEnv(&doc, os.LookupEnv, startLine-1); err != nil {
+			if name := monitorNameFromNode(&doc); name != "" {
+				return usedDefaults, fmt.Errorf("monitor %q: %w", name, err)
+			}
+			return usedDefaults, err
+		}
+	}
+	return usedDefaults, doc.Decode(monitor)
+}
+
+// batchCollector collects validated monitors and sends batches for entity creation.
+// Uses bounded deduplication to prevent OOM with large monitor counts.
+func (p *Pipeline) batchCollector(ctx context.Context) error {
+	batch := make([]schema.Monitor, 0, p.config.BatchSize)
+	// seen maps monitor name to where it was first defined, so cross-file
+	// collisions can name both sources.
+	type sourcePos struct {
+		file string
+		line int
+	}
+	seen := make(map[string]sourcePos)
+	batchID := 0
+	highFrequency := 0
+
+	// Override mode replaces earlier definitions in the pending batch when
+	// they have not been flushed yet; flushed ones are replaced in place by
+	// the creation stage (see createEntities).
+	override := p.config.DuplicatePolicy == DuplicatePolicyOverride
+	var pendingIdx map[string]int
+	if override {
+		pendingIdx = make(map[string]int)
+	}
+
+	// The collector is the one sequential stage that sees every monitor, so
+	// the breakdown and warnings are tallied here without locking and
+	// published when it exits — including on error, so aborted loads still
+	// report what streamed through.
+	tally := newLoadTally(p.config.MaxLoadWarnings)
+	defer func() {
+		p.mu.Lock()
+		p.tally = tally
+		p.mu.Unlock()
+	}()
+
+	// Bounded deduplication: track insertion order for FIFO eviction
+	maxDedup := p.config.MaxDeduplicationEntries
+	var seenOrder []string
+	if maxDedup > 0 {
+		seenOrder = make([]string, 0, maxDedup)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case vm, ok := <-p.validatedChan:
+			if !ok {
+				// Channel closed, send remaining batch
+				if len(batch) > 0 {
+					select {
+					case p.batchChan <- MonitorBatch{Monitors: batch, BatchID: batchID}:
+						atomic.AddInt64(&p.batched, int64(len(batch)))
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+				return nil
+			}
+
+			// Deduplicate by name. Under the default policy a duplicate
+			// within one file is skipped and counted — first definition
+			// wins — while across files it is a config error: two teams
+			// claiming the same name aborts the load. Override mode lets
+			// the later definition win either way, trading the error for a
+			// warning in the stats.
+			if first, exists := seen[vm.Monitor.Name]; exists {
+				atFirst := fmt.Sprintf("line %d", first.line)
+				if first.file != "" {
+					atFirst = fmt.Sprintf("%s (line %d)", first.file, first.line)
+				}
+				if override {
+					atomic.AddInt64(&p.duplicates, 1)
+					tally.warn(vm, fmt.Sprintf("duplicate monitor name overrides earlier definition at %s (override-last-wins)", atFirst))
+					seen[vm.Monitor.Name] = sourcePos{file: vm.File, line: vm.Line}
+					if idx, ok := pendingIdx[vm.Monitor.Name]; ok {
+						batch[idx] = vm.Monitor
+					} else {
+						// The earlier definition already went out in a
+						// flushed batch; queue the replacement and let the
+						// creation stage swap it into the existing entity.
+						batch = append(batch, vm.Monitor)
+						pendingIdx[vm.Monitor.Name] = len(batch) - 1
+					}
+					continue
+				}
+				if first.file != vm.File {
+					return fmt.Errorf("duplicate monitor name %q: defined in %s (line %d) and %s (line %d)",
+						vm.Monitor.Name, first.file, first.line, vm.File, vm.Line)
+				}
+				atomic.AddInt64(&p.duplicates, 1)
+				tally.warn(vm, fmt.Sprintf("duplicate monitor name (first defined at %s); keeping the first definition", atFirst))
+				continue
+			}
+
+			// Bounded deduplication: evict oldest entries when at capacity
+			if maxDedup > 0 && len(seen) >= maxDedup {
+				// Remove oldest 10% to amortize eviction cost
+				evictCount := maxDedup / 10
+				if evictCount < 1 {
+					evictCount = 1
+				}
+				for i := 0; i < evictCount && len(seenOrder) > 0; i++ {
+					delete(seen, seenOrder[0])
+					seenOrder = seenOrder[1:]
+				}
+			}
+
+			seen[vm.Monitor.Name] = sourcePos{file: vm.File, line: vm.Line}
+			if maxDedup > 0 {
+				seenOrder = append(seenOrder, vm.Monitor.Name)
+			}
+
+			tally.record(vm)
+
+			// Enforce the high-frequency budget across the whole load: the
+			// fast path sweeps every such monitor every tick, so an unbounded
+			// count would defeat the point of the cap.
+			if vm.Monitor.Pulse.HighFrequency {
+				highFrequency++
+				if p.config.HighFrequencyBudget > 0 && highFrequency > p.config.HighFrequencyBudget {
+					return fmt.Errorf("high-frequency monitor budget exceeded: more than %d monitors set high_frequency (at monitor %q)",
+						p.config.HighFrequencyBudget, vm.Monitor.Name)
+				}
+			}
+
+			batch = append(batch, vm.Monitor)
+			if override {
+				pendingIdx[vm.Monitor.Name] = len(batch) - 1
+			}
+
+			if len(batch) >= p.config.BatchSize {
+				// Send batch copy to avoid race. The send is guarded by ctx
+				// so cancellation never deadlocks against an entity creator
+				// that has already stopped draining.
+				batchCopy := make([]schema.Monitor, len(batch))
+				copy(batchCopy, batch)
+				select {
+				case p.batchChan <- MonitorBatch{Monitors: batchCopy, BatchID: batchID}:
+					atomic.AddInt64(&p.batched, int64(len(batch)))
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				batchID++
+				batch = batch[:0]
+				if override {
+					clear(pendingIdx)
+				}
+			}
+		}
+	}
+}
+
+// createEntities creates ECS entities from batches. The context is checked
+// before every batch, so cancellation aborts within one batch worth of work
+// and the world never holds a partially created batch.
+func (p *Pipeline) createEntities(ctx context.Context) error {
+	var (
+		totalPulseRate float64
+		lastProgress   time.Time
+		progressEvery  = p.config.ProgressInterval
+	)
+	if progressEvery <= 0 {
+		progressEvery = 250 * time.Millisecond
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case batch, ok := <-p.batchChan:
+			if !ok {
+				// All batches are in the world; depends_on names may point
+				// across batch boundaries, so resolution waits until here.
+				if err := p.entityManager.ResolveDependencies(p.world); err != nil {
+					return err
+				}
+				p.mu.Lock()
+				p.pulseRate = totalPulseRate
+				p.mu.Unlock()
+				return nil
+			}
+
+			// Calculate pulse rate
+			for _, monitor := range batch.Monitors {
+				if monitor.Enabled && monitor.Pulse.Interval > 0 {
+					sec := monitor.Pulse.Interval.Seconds()
+					if sec > 0 {
+						totalPulseRate += 1.0 / sec
+					}
+				}
+			}
+
+			// Override mode: a later duplicate may target an entity an
+			// earlier batch already created; swap its config in place so
+			// only one entity per name ever exists.
+			toCreate := batch.Monitors
+			if p.config.DuplicatePolicy == DuplicatePolicyOverride {
+				toCreate = batch.Monitors[:0]
+				for i := range batch.Monitors {
+					monitor := &batch.Monitors[i]
+					if ent, ok := p.entityManager.LookupMonitor(monitor.Name); ok {
+						if err := p.entityManager.ApplyMonitorConfig(p.world, ent, monitor); err != nil {
+							return fmt.Errorf("failed to override monitor %q in batch %d: %w", monitor.Name, batch.BatchID, err)
+						}
+						continue
+					}
+					toCreate = append(toCreate, *monitor)
+				}
+			}
+
+			// Use batch API for efficient entity creation
+			if err := p.entityManager.CreateEntitiesFromMonitors(p.world, toCreate); err != nil {
+				return fmt.Errorf("failed to create batch %d: %w", batch.BatchID, err)
+			}
+
+			atomic.AddInt64(&p.created, int64(len(toCreate)))
+
+			// Report the creation stage at batch boundaries so long loads
+			// show feedback after reading finishes.
+			if p.config.ProgressCallback != nil && time.Since(lastProgress) >= progressEvery {
+				lastProgress = time.Now()
+				p.config.ProgressCallback(LoadProgress{
+					MonitorsParsed: atomic.LoadInt64(&p.created),
+					Elapsed:        time.Since(p.startTime),
+					Stage:          "creating",
+				})
+			}
+		}
+	}
+}
+
+// buildStats builds the final statistics.
+func (p *Pipeline) buildStats() *PipelineStats {
+	elapsed := time.Since(p.startTime)
+	created := atomic.LoadInt64(&p.created)
+
+	var parseRate, creationRate float64
+	if elapsed.Seconds() > 0 {
+		parseRate = float64(atomic.LoadInt64(&p.validated)) / elapsed.Seconds()
+		creationRate = float64(created) / elapsed.Seconds()
+	}
+
+	p.mu.RLock()
+	pulseRate := p.pulseRate
+	tally := p.tally
+	p.mu.RUnlock()
+	if tally == nil {
+		// The batch collector never ran (e.g. reading failed immediately).
+		tally = newLoadTally(p.config.MaxLoadWarnings)
+	}
+
+	return &PipelineStats{
+		TotalMonitors:     atomic.LoadInt64(&p.rawParsed),
+		EntitiesCreated:   created,
+		SkippedMonitors:   atomic.LoadInt64(&p.skipped),
+		DuplicateMonitors: atomic.LoadInt64(&p.duplicates),
+		GeneratedMonitors: atomic.LoadInt64(&p.generated),
+		LoadingTime:       elapsed,
+		ParseRate:         parseRate,
+		CreationRate:      creationRate,
+		PulseRate:         pulseRate,
+		PulseTypes:        tally.pulseTypes,
+		CodeColors:        tally.codeColors,
+		WithInterventions: tally.withInterventions,
+		Warnings:          tally.warnings,
+		WarningsDropped:   tally.warningsDropped,
+	}
+}
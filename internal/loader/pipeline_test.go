@@ -0,0 +1,562 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/components"
+	"cpra/internal/controller/entities"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// TestStreamingParserBasic tests the streaming YAML parser with a small file.
+func TestStreamingParserBasic(t *testing.T) {
+	// Create a test YAML file
+	yaml := `monitors:
+  - name: test-monitor-1
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+  - name: test-monitor-2
+    enabled: true
+    pulse_check:
+      type: tcp
+      interval: 10s
+      timeout: 5s
+      config:
+        host: localhost
+        port: 22
+  - name: test-monitor-3
+    enabled: false
+    pulse_check:
+      type: icmp
+      interval: 30s
+      timeout: 2s
+      config:
+        host: 8.8.8.8
+`
+
+	// Write to temp file
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test_monitors.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yaml), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	// Create minimal pipeline with streaming mode
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+
+	config := DefaultPipelineConfig()
+	config.StreamingMode = true // Explicit streaming
+	config.Workers = 4
+
+	pipeline := NewPipeline(&world, em, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := pipeline.Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Verify stats
+	if stats.TotalMonitors != 3 {
+		t.Errorf("Expected 3 monitors, got %d", stats.TotalMonitors)
+	}
+	if stats.EntitiesCreated != 3 {
+		t.Errorf("Expected 3 entities created, got %d", stats.EntitiesCreated)
+	}
+}
+
+// TestStreamingParserLarge tests the streaming parser with a larger synthetic file.
+func TestStreamingParserLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping large file test in short mode")
+	}
+
+	// Generate 10,000 monitors
+	var buf bytes.Buffer
+	buf.WriteString("monitors:\n")
+	for i := 0; i < 10000; i++ {
+		buf.WriteString(fmt.Sprintf("  - name: monitor-%04d\n", i))
+		buf.WriteString("    enabled: true\n")
+		buf.WriteString("    pulse_check:\n")
+		buf.WriteString("      type: http\n")
+		buf.WriteString("      interval: 5s\n")
+		buf.WriteString("      timeout: 3s\n")
+		buf.WriteString("      config:\n")
+		buf.WriteString("        url: http://example.com/health\n")
+	}
+
+	// Write to temp file
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test_monitors_large.yaml")
+	if err := os.WriteFile(tmpFile, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	// Create pipeline with streaming mode
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+
+	config := DefaultPipelineConfig()
+	config.StreamingMode = true
+	config.Workers = 100
+
+	pipeline := NewPipeline(&world, em, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	stats, err := pipeline.Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Verify stats
+	if stats.TotalMonitors != 10000 {
+		t.Errorf("Expected 10000 monitors, got %d", stats.TotalMonitors)
+	}
+	if stats.EntitiesCreated != 10000 {
+		t.Errorf("Expected 10000 entities created, got %d", stats.EntitiesCreated)
+	}
+
+	t.Logf("Loaded %d monitors in %v (%.0f/sec)", stats.EntitiesCreated, stats.LoadingTime, stats.CreationRate)
+}
+
+// TestTraditionalVsStreaming compares traditional and streaming modes.
+func TestTraditionalVsStreaming(t *testing.T) {
+	yaml := `monitors:
+  - name: test-1
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+  - name: test-2
+    enabled: true
+    pulse_check:
+      type: tcp
+      interval: 10s
+      timeout: 5s
+      config:
+        host: localhost
+        port: 22
+`
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yaml), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	// Test traditional mode
+	t.Run("Traditional", func(t *testing.T) {
+		world := ecs.NewWorld()
+		em := entities.NewEntityManager(&world)
+
+		config := DefaultPipelineConfig()
+		config.StreamingMode = false
+		config.Workers = 4
+
+		pipeline := NewPipeline(&world, em, config)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stats, err := pipeline.Load(ctx, tmpFile)
+		if err != nil {
+			t.Fatalf("Traditional load failed: %v", err)
+		}
+		if stats.EntitiesCreated != 2 {
+			t.Errorf("Expected 2 entities, got %d", stats.EntitiesCreated)
+		}
+	})
+
+	// Test streaming mode
+	t.Run("Streaming", func(t *testing.T) {
+		world := ecs.NewWorld()
+		em := entities.NewEntityManager(&world)
+
+		config := DefaultPipelineConfig()
+		config.StreamingMode = true
+		config.Workers = 4
+
+		pipeline := NewPipeline(&world, em, config)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stats, err := pipeline.Load(ctx, tmpFile)
+		if err != nil {
+			t.Fatalf("Streaming load failed: %v", err)
+		}
+		if stats.EntitiesCreated != 2 {
+			t.Errorf("Expected 2 entities, got %d", stats.EntitiesCreated)
+		}
+	})
+}
+
+// TestProgressCallback tests that progress callback is called.
+func TestProgressCallback(t *testing.T) {
+	yaml := `monitors:
+  - name: test-1
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+`
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yaml), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+
+	var callbackCount int64
+	config := DefaultPipelineConfig()
+	config.StreamingMode = true
+	config.ProgressInterval = 1 * time.Millisecond // Frequent updates for testing
+	config.ProgressCallback = func(progress LoadProgress) {
+		atomic.AddInt64(&callbackCount, 1)
+	}
+
+	pipeline := NewPipeline(&world, em, config)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pipeline.Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Should have at least one callback (final progress)
+	if atomic.LoadInt64(&callbackCount) < 1 {
+		t.Error("Expected progress callback to be called at least once")
+	}
+}
+
+// TestLoadCancellationMidLoad cancels the context once entity creation has
+// started and verifies the load aborts promptly, returns context.Canceled
+// with partial stats, and that the world holds only complete batches.
+func TestLoadCancellationMidLoad(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("monitors:\n")
+	for i := 0; i < 20000; i++ {
+		sb.WriteString(fmt.Sprintf(`  - name: monitor-%06d
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/%d
+`, i, i))
+	}
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "cancel.yaml")
+	if err := os.WriteFile(tmpFile, []byte(sb.String()), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := DefaultPipelineConfig()
+	config.BatchSize = 100
+	config.ProgressInterval = 1 * time.Nanosecond // Report every batch
+	config.ProgressCallback = func(progress LoadProgress) {
+		if progress.Stage == "creating" {
+			cancel() // Cancel as soon as the first batch lands
+		}
+	}
+
+	pipeline := NewPipeline(&world, em, config)
+	start := time.Now()
+	stats, err := pipeline.Load(ctx, tmpFile)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("Cancellation took %v, expected prompt abort", elapsed)
+	}
+	if stats == nil {
+		t.Fatal("Expected partial stats on cancellation, got nil")
+	}
+	if stats.EntitiesCreated == 0 || stats.EntitiesCreated >= 20000 {
+		t.Errorf("Expected a partial entity count, got %d", stats.EntitiesCreated)
+	}
+	if stats.EntitiesCreated%100 != 0 {
+		t.Errorf("Entity count %d is not a multiple of the batch size; a batch was partially created", stats.EntitiesCreated)
+	}
+
+	// The world must agree with the reported count.
+	filter := ecs.NewFilter1[components.MonitorState](&world)
+	query := filter.Query()
+	worldCount := int64(query.Count())
+	query.Close()
+	if worldCount != stats.EntitiesCreated {
+		t.Errorf("World holds %d entities, stats report %d", worldCount, stats.EntitiesCreated)
+	}
+}
+
+// TestLoadBreakdownAndWarnings loads a mixed fixture and checks the
+// per-type breakdown, code color counts, intervention count, and that
+// non-fatal warnings carry monitor names and line numbers.
+func TestLoadBreakdownAndWarnings(t *testing.T) {
+	yaml := `monitors:
+  - name: web-1
+    enabled: true
+    codes:
+      red:
+        notify: pagerduty
+        config:
+          routing_key: key-1
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+  - name: web-2
+    enabled: true
+    codes:
+      red:
+        notify: pagerduty
+        config:
+          routing_key: key-2
+      yellow:
+        notify: slack
+        config:
+          hook: http://hooks.example.com/x
+    intervention:
+      action: docker
+      target:
+        type: restart
+        name: web-2
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health2
+  - name: db-1
+    enabled: true
+    pulse_check:
+      type: tcp
+      interval: 10s
+      timeout: 10s
+      config:
+        host: localhost
+        port: 5432
+  - name: old-service
+    enabled: false
+    pulse_check:
+      type: icmp
+      interval: 30s
+      timeout: 2s
+      config:
+        host: 10.0.0.9
+`
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "mixed.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yaml), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	config := DefaultPipelineConfig()
+	config.Workers = 4
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := NewPipeline(&world, em, config).Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if stats.PulseTypes["http"] != 2 || stats.PulseTypes["tcp"] != 1 || stats.PulseTypes["icmp"] != 1 {
+		t.Errorf("PulseTypes = %v, want http:2 tcp:1 icmp:1", stats.PulseTypes)
+	}
+	if stats.CodeColors["red"] != 2 || stats.CodeColors["yellow"] != 1 {
+		t.Errorf("CodeColors = %v, want red:2 yellow:1", stats.CodeColors)
+	}
+	if stats.WithInterventions != 1 {
+		t.Errorf("WithInterventions = %d, want 1", stats.WithInterventions)
+	}
+	if stats.WarningsDropped != 0 {
+		t.Errorf("WarningsDropped = %d, want 0", stats.WarningsDropped)
+	}
+
+	// db-1 has timeout == interval and old-service is disabled: two warnings.
+	if len(stats.Warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %d: %v", len(stats.Warnings), stats.Warnings)
+	}
+	var found bool
+	for _, w := range stats.Warnings {
+		if w.Monitor != "db-1" {
+			continue
+		}
+		found = true
+		if !strings.Contains(w.Message, "timeout equals interval") {
+			t.Errorf("db-1 warning = %q, want timeout-equals-interval", w.Message)
+		}
+		if w.Line == 0 || w.File != tmpFile {
+			t.Errorf("db-1 warning missing source position: file=%q line=%d", w.File, w.Line)
+		}
+	}
+	if !found {
+		t.Errorf("No warning for db-1 in %v", stats.Warnings)
+	}
+}
+
+// TestLoadWarningsCap verifies warnings past MaxLoadWarnings are counted
+// rather than retained.
+func TestLoadWarningsCap(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("monitors:\n")
+	for i := 0; i < 10; i++ {
+		sb.WriteString(fmt.Sprintf(`  - name: disabled-%02d
+    enabled: false
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/%d
+`, i, i))
+	}
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "disabled.yaml")
+	if err := os.WriteFile(tmpFile, []byte(sb.String()), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	config := DefaultPipelineConfig()
+	config.Workers = 4
+	config.MaxLoadWarnings = 3
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := NewPipeline(&world, em, config).Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(stats.Warnings) != 3 {
+		t.Errorf("Expected 3 retained warnings, got %d", len(stats.Warnings))
+	}
+	if stats.WarningsDropped != 7 {
+		t.Errorf("WarningsDropped = %d, want 7", stats.WarningsDropped)
+	}
+}
+
+// TestProgressReporter tests the progress reporter formatting.
+func TestProgressReporter(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := NewProgressReporter(&buf, "monitors", 1000)
+
+	// Simulate progress updates
+	reporter.Update(100)
+	reporter.Update(500)
+	reporter.Update(1000)
+	reporter.Complete()
+
+	output := buf.String()
+	if len(output) == 0 {
+		t.Error("Expected progress output")
+	}
+
+	// Should contain "monitors" label
+	if !bytes.Contains(buf.Bytes(), []byte("monitors")) {
+		t.Error("Expected output to contain 'monitors'")
+	}
+}
+
+// TestHighFrequencyBudget verifies the load-time cap on high_frequency monitors.
+func TestHighFrequencyBudget(t *testing.T) {
+	yaml := `monitors:
+  - name: hf-monitor-1
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 250ms
+      timeout: 100ms
+      high_frequency: true
+      config:
+        url: http://example.com/health
+  - name: hf-monitor-2
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 500ms
+      timeout: 100ms
+      high_frequency: true
+      config:
+        url: http://example.com/health
+`
+
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "hf_monitors.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yaml), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Within budget: both monitors load.
+	world := ecs.NewWorld()
+	config := DefaultPipelineConfig()
+	config.Workers = 4
+	config.HighFrequencyBudget = 2
+
+	stats, err := NewPipeline(&world, entities.NewEntityManager(&world), config).Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.EntitiesCreated != 2 {
+		t.Errorf("Expected 2 entities created, got %d", stats.EntitiesCreated)
+	}
+
+	// Over budget: the load fails rather than silently degrading accuracy.
+	world2 := ecs.NewWorld()
+	config.HighFrequencyBudget = 1
+
+	_, err = NewPipeline(&world2, entities.NewEntityManager(&world2), config).Load(ctx, tmpFile)
+	if err == nil {
+		t.Fatal("Expected load to fail when high-frequency budget is exceeded")
+	}
+	if !strings.Contains(err.Error(), "high-frequency monitor budget exceeded") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package loader
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/entities"
+	"cpra/internal/loader/schema"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// routesManifest is shared by the routing tests: two groups, a monitor
+// inheriting from both, and a monitor whose local red overrides the route.
+const routesManifest = `routes:
+  payments:
+    red:
+      notify: log
+      config:
+        file: /var/log/payments-red.log
+    green:
+      notify: log
+      config:
+        file: /var/log/payments-green.log
+  oncall-eu:
+    red:
+      notify: log
+      config:
+        file: /var/log/oncall-red.log
+monitors:
+  - name: checkout
+    notify_groups: [payments, oncall-eu]
+    pulse_check:
+      type: http
+      interval: 30s
+      timeout: 10s
+      config:
+        url: http://example.com/health
+  - name: refunds
+    notify_groups: [payments]
+    codes:
+      red:
+        notify: log
+        config:
+          file: /var/log/local-red.log
+    pulse_check:
+      type: http
+      interval: 30s
+      timeout: 10s
+      config:
+        url: http://example.com/health
+`
+
+func TestNotifyGroups_UnionAndLocalWins(t *testing.T) {
+	monitors, err := LoadManifestFile(writeManifest(t, routesManifest))
+	if err != nil {
+		t.Fatalf("LoadManifestFile failed: %v", err)
+	}
+	if len(monitors) != 2 {
+		t.Fatalf("Expected 2 monitors, got %d", len(monitors))
+	}
+
+	checkout, refunds := monitors[0], monitors[1]
+
+	// checkout inherits red from both groups: the first group's target is
+	// the primary, the second fans out via Extra.
+	red, ok := checkout.Codes["red"]
+	if !ok {
+		t.Fatalf("checkout should inherit red from its groups, got %v", checkout.Codes)
+	}
+	if file := red.Config.(*schema.CodeNotificationLog).File; file != "/var/log/payments-red.log" {
+		t.Errorf("primary red target should come from the first group, got %q", file)
+	}
+	if len(red.Extra) != 1 {
+		t.Fatalf("red should fan out to the second group's target, got %d extras", len(red.Extra))
+	}
+	if file := red.Extra[0].Config.(*schema.CodeNotificationLog).File; file != "/var/log/oncall-red.log" {
+		t.Errorf("red fan-out target should come from the second group, got %q", file)
+	}
+	if _, ok := checkout.Codes["green"]; !ok {
+		t.Errorf("checkout should inherit green from payments, got %v", checkout.Codes)
+	}
+
+	// refunds declares its own red, which wins over the route's.
+	red, ok = refunds.Codes["red"]
+	if !ok {
+		t.Fatalf("refunds should keep its local red, got %v", refunds.Codes)
+	}
+	if file := red.Config.(*schema.CodeNotificationLog).File; file != "/var/log/local-red.log" {
+		t.Errorf("monitor-local red should win over the route, got %q", file)
+	}
+	if len(red.Extra) != 0 {
+		t.Errorf("monitor-local red should suppress route targets entirely, got %d extras", len(red.Extra))
+	}
+	if _, ok := refunds.Codes["green"]; !ok {
+		t.Errorf("refunds should still inherit green from payments, got %v", refunds.Codes)
+	}
+}
+
+// TestNotifyGroups_StreamingPipeline runs the same manifest through the
+// streaming pipeline, which threads the routes block to the workers from
+// the header.
+func TestNotifyGroups_StreamingPipeline(t *testing.T) {
+	tmpFile := writeManifest(t, routesManifest)
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+	pipeline := NewPipeline(&world, em, DefaultPipelineConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stats, err := pipeline.Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if stats.EntitiesCreated != 2 {
+		t.Errorf("Expected 2 entities, got %d (skipped %d)", stats.EntitiesCreated, stats.SkippedMonitors)
+	}
+}
+
+// TestNotifyGroups_UnknownGroupFailsLoad verifies a misspelled group aborts
+// the load naming the monitor and line, rather than silently dropping the
+// route's alerts.
+func TestNotifyGroups_UnknownGroupFailsLoad(t *testing.T) {
+	_, err := LoadManifestFile(writeManifest(t, `routes:
+  payments:
+    red:
+      notify: log
+      config:
+        file: /var/log/payments-red.log
+monitors:
+  - name: checkout
+    notify_groups: [payment]
+    pulse_check:
+      type: http
+      interval: 30s
+      timeout: 10s
+      config:
+        url: http://example.com/health
+`))
+	if err == nil {
+		t.Fatal("Expected unknown group error, got nil")
+	}
+	if !strings.Contains(err.Error(), `unknown notify group "payment"`) {
+		t.Errorf("Error should name the unknown group: %v", err)
+	}
+	if !strings.Contains(err.Error(), `monitor "checkout" (line 8)`) {
+		t.Errorf("Error should name the monitor and line: %v", err)
+	}
+}
+
+// TestNotifyGroups_LintFinding verifies the linter reports an unknown group
+// as a finding instead of aborting the scan.
+func TestNotifyGroups_LintFinding(t *testing.T) {
+	findings, err := LintManifest(writeManifest(t, `routes:
+  payments:
+    red:
+      notify: log
+      config:
+        file: /var/log/payments-red.log
+monitors:
+  - name: checkout
+    notify_groups: [oncall]
+    pulse_check:
+      type: http
+      interval: 30s
+      timeout: 10s
+      config:
+        url: http://example.com/health
+`))
+	if err != nil {
+		t.Fatalf("LintManifest failed: %v", err)
+	}
+	found := false
+	for _, f := range findings {
+		if f.Severity == SeverityError && f.Monitor == "checkout" &&
+			strings.Contains(f.Message, `unknown notify group "oncall"`) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected an unknown-group finding, got %+v", findings)
+	}
+}
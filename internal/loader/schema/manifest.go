@@ -0,0 +1,2112 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//// UTILITY TYPES
+
+type DurationSeconds int
+
+func (d *DurationSeconds) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw string
+	if err := unmarshal(&raw); err != nil {
+		return fmt.Errorf("invalid duration %q", raw)
+	}
+	p, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", raw, err)
+	}
+	*d = DurationSeconds(int(p.Seconds()))
+	return nil
+}
+
+type StringList []string
+
+func (s *StringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*s = []string{single}
+		return nil
+	}
+	var multi []string
+	if err := unmarshal(&multi); err == nil {
+		*s = multi
+		return nil
+	}
+	return fmt.Errorf("value must be a string or list of strings")
+}
+
+// Header is one canonical HTTP request header name/value pair.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// HeaderList holds request headers in canonical form. Manifests may write
+// headers either as "Name: value" strings (order preserved, duplicates kept
+// and joined per RFC semantics at send time) or as a name → value mapping
+// (sorted by name; the map form never splits on colons, so values containing
+// colons need no quoting).
+type HeaderList []Header
+
+// parseHeaderString splits one "Name: value" entry on its first colon.
+func parseHeaderString(entry string) (Header, error) {
+	name, value, ok := strings.Cut(entry, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return Header{}, fmt.Errorf("malformed header %q: expected \"Name: value\"", entry)
+	}
+	return Header{Name: name, Value: strings.TrimSpace(value)}, nil
+}
+
+var errHeadersShape = fmt.Errorf("headers must be a list of \"Name: value\" strings or a name → value map")
+
+func (h *HeaderList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.MappingNode:
+		headers := make(HeaderList, 0, len(value.Content)/2)
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			name, val := value.Content[i], value.Content[i+1]
+			if val.Kind != yaml.ScalarNode {
+				return errHeadersShape
+			}
+			if strings.TrimSpace(name.Value) == "" {
+				return fmt.Errorf("malformed header: empty name for value %q", val.Value)
+			}
+			headers = append(headers, Header{Name: name.Value, Value: val.Value})
+		}
+		sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+		*h = headers
+		return nil
+
+	case yaml.ScalarNode:
+		// A bare string is a single-entry list, mirroring StringList.
+		header, err := parseHeaderString(value.Value)
+		if err != nil {
+			return err
+		}
+		*h = HeaderList{header}
+		return nil
+
+	case yaml.SequenceNode:
+		var entries []string
+		if err := value.Decode(&entries); err != nil {
+			return errHeadersShape
+		}
+		headers := make(HeaderList, 0, len(entries))
+		for _, entry := range entries {
+			header, err := parseHeaderString(entry)
+			if err != nil {
+				return err
+			}
+			headers = append(headers, header)
+		}
+		*h = headers
+		return nil
+
+	default:
+		return errHeadersShape
+	}
+}
+
+// UnmarshalJSON accepts the same two shapes as the YAML form.
+func (h *HeaderList) UnmarshalJSON(data []byte) error {
+	var asMap map[string]string
+	if err := json.Unmarshal(data, &asMap); err == nil {
+		headers := make(HeaderList, 0, len(asMap))
+		for name, value := range asMap {
+			if strings.TrimSpace(name) == "" {
+				return fmt.Errorf("malformed header: empty name for value %q", value)
+			}
+			headers = append(headers, Header{Name: name, Value: value})
+		}
+		sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+		*h = headers
+		return nil
+	}
+	var entries []string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return errHeadersShape
+	}
+	headers := make(HeaderList, 0, len(entries))
+	for _, entry := range entries {
+		header, err := parseHeaderString(entry)
+		if err != nil {
+			return err
+		}
+		headers = append(headers, header)
+	}
+	*h = headers
+	return nil
+}
+
+//// PULSE TYPES
+
+type PulseConfig interface {
+	isPulseConfigs()
+	Copy() PulseConfig
+}
+
+// HTTPAuth configures request authentication for HTTP pulses. Credentials
+// may be given inline or sourced from environment variables (the *_env
+// fields) so manifests can stay secret-free.
+type HTTPAuth struct {
+	// Type is the auth scheme: "basic" or "bearer".
+	Type        string `yaml:"type" json:"type"`
+	Username    string `yaml:"username" json:"username"`
+	Password    string `yaml:"password" json:"password"`
+	PasswordEnv string `yaml:"password_env" json:"password_env"`
+	Token       string `yaml:"token" json:"token"`
+	TokenEnv    string `yaml:"token_env" json:"token_env"`
+}
+
+type PulseHTTPConfig struct {
+	Url     string     `yaml:"url" json:"url"`
+	Method  string     `yaml:"method" json:"method"`
+	Headers HeaderList `yaml:"headers" json:"headers"`
+	Auth    *HTTPAuth  `yaml:"auth" json:"auth"`
+	Retries int        `yaml:"retries" json:"retries"`
+	// ExpectedStatus lists the healthy status codes as single codes and
+	// inclusive ranges, e.g. "200-204,301". Empty keeps the 2xx default.
+	ExpectedStatus string `yaml:"expected_status" json:"expected_status"`
+	// BodyContains fails the check when the response body (capped; see the
+	// jobs package) does not contain this substring.
+	BodyContains string `yaml:"body_contains" json:"body_contains"`
+	// BodyRegex fails the check when the capped response body does not
+	// match this regular expression. Compile errors fail the load.
+	BodyRegex string `yaml:"body_regex" json:"body_regex"`
+	// Proxy optionally routes the check through an HTTP, HTTPS, or SOCKS5
+	// proxy ("http://", "https://", or "socks5://" URL). Overrides the
+	// controller-wide default; hosts on the controller's no-proxy list are
+	// always dialed directly. Invalid URLs fail the load.
+	Proxy string `yaml:"proxy" json:"proxy"`
+}
+
+// ParseStatusRanges parses an expected_status spec into inclusive code
+// ranges. It lives in the schema package so the loader's validator and the
+// job factory share one definition of the syntax.
+func ParseStatusRanges(spec string) (StatusRanges, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var ranges StatusRanges
+	for _, token := range strings.Split(spec, ",") {
+		token = strings.TrimSpace(token)
+		lo, hi, isRange := strings.Cut(token, "-")
+		low, err := parseStatusCode(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected_status %q: %w", token, err)
+		}
+		high := low
+		if isRange {
+			if high, err = parseStatusCode(hi); err != nil {
+				return nil, fmt.Errorf("invalid expected_status %q: %w", token, err)
+			}
+		}
+		if high < low {
+			return nil, fmt.Errorf("invalid expected_status %q: range is reversed", token)
+		}
+		ranges = append(ranges, [2]int{low, high})
+	}
+	return ranges, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("not a status code: %q", s)
+	}
+	if code < 100 || code > 599 {
+		return 0, fmt.Errorf("status code %d out of range", code)
+	}
+	return code, nil
+}
+
+// StatusRanges is a parsed expected_status spec. The empty set matches the
+// conventional 2xx success range.
+type StatusRanges [][2]int
+
+// Match reports whether code is considered healthy.
+func (r StatusRanges) Match(code int) bool {
+	if len(r) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, span := range r {
+		if code >= span[0] && code <= span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *PulseHTTPConfig) Copy() PulseConfig {
+	// This was already correct, but for consistency, we'll return a pointer
+	// to a new struct.
+
+	newConfig := new(PulseHTTPConfig)
+	*newConfig = *c
+	newConfig.Headers = append(HeaderList(nil), c.Headers...)
+	if c.Auth != nil {
+		auth := *c.Auth
+		newConfig.Auth = &auth
+	}
+	return newConfig
+}
+
+func (*PulseHTTPConfig) isPulseConfigs() {}
+
+type PulseTCPConfig struct {
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+	Retries int    `yaml:"retries"`
+	// IPFamily pins the dial to one address family: "auto" (default),
+	// "ipv4", or "ipv6". One monitor per family covers both endpoints of
+	// a dual-stacked host.
+	IPFamily string `yaml:"ip_family"`
+}
+
+func (c *PulseTCPConfig) Copy() PulseConfig {
+	newConfig := new(PulseTCPConfig)
+	*newConfig = *c
+	return newConfig
+}
+
+func (*PulseTCPConfig) isPulseConfigs() {}
+
+type PulseICMPConfig struct {
+	Host      string `yaml:"host"`
+	Privilege bool   `yaml:"ignore_privilege"`
+	Count     int    `yaml:"count"`
+	Retries   int    `yaml:"retries"`
+	// IPFamily pins resolution to one address family: "auto" (default),
+	// "ipv4", or "ipv6". One monitor per family covers both endpoints of
+	// a dual-stacked host.
+	IPFamily string `yaml:"ip_family"`
+}
+
+func (c *PulseICMPConfig) Copy() PulseConfig {
+	newConfig := new(PulseICMPConfig)
+	*newConfig = *c
+	return newConfig
+}
+
+func (*PulseICMPConfig) isPulseConfigs() {}
+
+// PulseUDPConfig configures a UDP send/expect probe. The job sends Payload
+// to host:port and waits up to ReadTimeout for a reply datagram. UDP is
+// connectionless, so RequireResponse decides whether silence fails the check
+// or a successful write is healthy enough.
+type PulseUDPConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	// Payload is the probe datagram: a literal string, or raw bytes with a
+	// "hex:" prefix (e.g. "hex:deadbeef"). Empty sends an empty datagram.
+	Payload string `yaml:"payload"`
+	// Expect optionally asserts on the reply: a literal substring, or a
+	// "hex:"-prefixed byte sequence the reply must start with.
+	Expect string `yaml:"expect"`
+	// ReadTimeout bounds the wait for a reply; zero falls back to the
+	// pulse timeout.
+	ReadTimeout time.Duration `yaml:"read_timeout"`
+	// RequireResponse fails the check when no reply arrives. ICMP
+	// port-unreachable fails regardless.
+	RequireResponse bool `yaml:"require_response"`
+	Retries         int  `yaml:"retries"`
+}
+
+func (c *PulseUDPConfig) Copy() PulseConfig {
+	newConfig := new(PulseUDPConfig)
+	*newConfig = *c
+	return newConfig
+}
+
+func (*PulseUDPConfig) isPulseConfigs() {}
+
+// UnmarshalJSON parses the read timeout from a duration string (e.g. "2s").
+func (c *PulseUDPConfig) UnmarshalJSON(data []byte) error {
+	var temp struct {
+		Host            string `json:"host"`
+		Port            int    `json:"port"`
+		Payload         string `json:"payload"`
+		Expect          string `json:"expect"`
+		ReadTimeout     string `json:"read_timeout"`
+		RequireResponse bool   `json:"require_response"`
+		Retries         int    `json:"retries"`
+	}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+	c.Host = temp.Host
+	c.Port = temp.Port
+	c.Payload = temp.Payload
+	c.Expect = temp.Expect
+	c.RequireResponse = temp.RequireResponse
+	c.Retries = temp.Retries
+	if temp.ReadTimeout == "" {
+		c.ReadTimeout = 0
+		return nil
+	}
+	readTimeout, err := time.ParseDuration(temp.ReadTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid read_timeout duration %q: %w", temp.ReadTimeout, err)
+	}
+	c.ReadTimeout = readTimeout
+	return nil
+}
+
+// ParseUDPBytes parses a UDP payload/expect spec: a "hex:"-prefixed hex
+// string decodes to raw bytes, anything else is taken literally. It lives in
+// the schema package so the loader's validator and the job factory share one
+// definition of the syntax.
+func ParseUDPBytes(spec string) ([]byte, error) {
+	if hexPart, ok := strings.CutPrefix(spec, "hex:"); ok {
+		b, err := hex.DecodeString(hexPart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex bytes %q: %w", spec, err)
+		}
+		return b, nil
+	}
+	return []byte(spec), nil
+}
+
+// PulseNTPConfig configures an NTP clock-drift check. The job queries each
+// server via SNTP, computes the median offset across samples, and fails when
+// the offset exceeds MaxOffset or no server responds.
+type PulseNTPConfig struct {
+	Servers   StringList    `yaml:"servers" json:"servers"`
+	MaxOffset time.Duration `yaml:"max_offset" json:"max_offset"`
+	Samples   int           `yaml:"samples" json:"samples"`
+	Retries   int           `yaml:"retries" json:"retries"`
+}
+
+func (c *PulseNTPConfig) Copy() PulseConfig {
+	newConfig := new(PulseNTPConfig)
+	*newConfig = *c
+	newConfig.Servers = append(StringList(nil), c.Servers...)
+	return newConfig
+}
+
+func (*PulseNTPConfig) isPulseConfigs() {}
+
+// PulseDNSConfig configures a DNS resolution check. The job resolves Host
+// for the given record type and, when Expected is set, requires every
+// expected value to be present in the answer (order-insensitive).
+type PulseDNSConfig struct {
+	Host string `yaml:"host" json:"host"`
+	// RecordType is one of A (default), AAAA, CNAME, TXT, or MX.
+	RecordType string     `yaml:"record_type" json:"record_type"`
+	Expected   StringList `yaml:"expected" json:"expected"`
+	// Resolver optionally overrides the system resolver, as "host" or
+	// "host:port" (port 53 by default).
+	Resolver string `yaml:"resolver" json:"resolver"`
+	Retries  int    `yaml:"retries" json:"retries"`
+}
+
+func (c *PulseDNSConfig) Copy() PulseConfig {
+	newConfig := new(PulseDNSConfig)
+	*newConfig = *c
+	newConfig.Expected = append(StringList(nil), c.Expected...)
+	return newConfig
+}
+
+func (*PulseDNSConfig) isPulseConfigs() {}
+
+// PulseGRPCConfig configures a gRPC health check via the standard
+// grpc.health.v1.Health/Check RPC. Service selects which registered service
+// to query; empty asks for the server's overall status.
+type PulseGRPCConfig struct {
+	Target  string `yaml:"target" json:"target"`
+	Service string `yaml:"service" json:"service"`
+	TLS     bool   `yaml:"tls" json:"tls"`
+	Retries int    `yaml:"retries" json:"retries"`
+}
+
+func (c *PulseGRPCConfig) Copy() PulseConfig {
+	newConfig := new(PulseGRPCConfig)
+	*newConfig = *c
+	return newConfig
+}
+
+func (*PulseGRPCConfig) isPulseConfigs() {}
+
+// PulseDockerConfig configures a Docker container health check. The job
+// inspects Container on the daemon and fails when the container is missing,
+// exited, restarting, or (with require_status: healthy) reports an unhealthy
+// healthcheck.
+type PulseDockerConfig struct {
+	Container string `yaml:"container" json:"container"`
+	// RequireStatus is "running" (the default) or "healthy". "healthy"
+	// additionally requires the container's healthcheck to pass, so it only
+	// makes sense for images that define one.
+	RequireStatus string `yaml:"require_status" json:"require_status"`
+	// DockerHost optionally overrides the daemon endpoint for this monitor;
+	// empty uses the environment default (DOCKER_HOST or the local socket).
+	DockerHost string `yaml:"docker_host" json:"docker_host"`
+	Retries    int    `yaml:"retries" json:"retries"`
+}
+
+func (c *PulseDockerConfig) Copy() PulseConfig {
+	newConfig := new(PulseDockerConfig)
+	*newConfig = *c
+	return newConfig
+}
+
+func (*PulseDockerConfig) isPulseConfigs() {}
+
+// UnmarshalJSON parses the max offset from a duration string (e.g. "500ms").
+func (c *PulseNTPConfig) UnmarshalJSON(data []byte) error {
+	var temp struct {
+		Servers   StringList `json:"servers"`
+		MaxOffset string     `json:"max_offset"`
+		Samples   int        `json:"samples"`
+		Retries   int        `json:"retries"`
+	}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+	c.Servers = temp.Servers
+	c.Samples = temp.Samples
+	c.Retries = temp.Retries
+	if temp.MaxOffset == "" {
+		c.MaxOffset = 0
+		return nil
+	}
+	maxOffset, err := time.ParseDuration(temp.MaxOffset)
+	if err != nil {
+		return fmt.Errorf("invalid max_offset duration %q: %w", temp.MaxOffset, err)
+	}
+	c.MaxOffset = maxOffset
+	return nil
+}
+
+// PulseHeartbeatConfig configures a push-based (heartbeat) monitor. Instead of
+// probing the target, CPRA expects the target to POST to the heartbeat endpoint
+// at least once per interval; grace adds slack before a missed beat is a failure.
+type PulseHeartbeatConfig struct {
+	Grace time.Duration `yaml:"grace" json:"grace"`
+	// Token is the pre-shared secret the sender presents when posting beats
+	// by monitor name. Empty generates a random token at load time, which
+	// is then the only way to address the monitor.
+	Token string `yaml:"token" json:"token"`
+}
+
+func (c *PulseHeartbeatConfig) Copy() PulseConfig {
+	newConfig := new(PulseHeartbeatConfig)
+	*newConfig = *c
+	return newConfig
+}
+
+func (*PulseHeartbeatConfig) isPulseConfigs() {}
+
+// UnmarshalJSON parses the grace period from a duration string (e.g. "30s").
+func (c *PulseHeartbeatConfig) UnmarshalJSON(data []byte) error {
+	var temp struct {
+		Grace string `json:"grace"`
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+	c.Token = temp.Token
+	if temp.Grace == "" {
+		c.Grace = 0
+		return nil
+	}
+	grace, err := time.ParseDuration(temp.Grace)
+	if err != nil {
+		return fmt.Errorf("invalid grace duration %q: %w", temp.Grace, err)
+	}
+	c.Grace = grace
+	return nil
+}
+
+// RetryBackoff configures the delay between pulse retry attempts. When
+// omitted the jobs fall back to exponential backoff from 50ms capped at
+// 1s, matching the historical behavior.
+type RetryBackoff struct {
+	// Strategy is exponential (default), constant, or none.
+	Strategy string        `yaml:"strategy" json:"strategy"`
+	Base     time.Duration `yaml:"base" json:"base"`
+	Max      time.Duration `yaml:"max" json:"max"`
+	// Jitter draws each delay uniformly from [delay/2, delay] to spread
+	// retries of correlated failures.
+	Jitter bool `yaml:"jitter" json:"jitter"`
+}
+
+// Schedule restricts when a monitor's pulse checks run: only inside the
+// active hours on the listed days, evaluated in the given timezone. Outside
+// the window the monitor is suppressed and its failure streaks reset, so
+// the first check after re-entering the window starts fresh.
+type Schedule struct {
+	// ActiveHours is the daily window as "HH:MM-HH:MM", e.g. "08:00-20:00".
+	// An end at or before the start wraps past midnight.
+	ActiveHours string `yaml:"active_hours" json:"active_hours"`
+	// Days lists the active weekdays as a range ("mon-fri") or a
+	// comma-separated list ("mon,wed,fri"). Empty means every day.
+	Days string `yaml:"days,omitempty" json:"days,omitempty"`
+	// Timezone is the IANA zone name ("Europe/Berlin") the window is
+	// evaluated in. Empty means UTC.
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// ActiveWindow parses ActiveHours into offsets from local midnight.
+func (s *Schedule) ActiveWindow() (start, end time.Duration, err error) {
+	parts := strings.Split(s.ActiveHours, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid schedule active_hours %q (want \"HH:MM-HH:MM\")", s.ActiveHours)
+	}
+	start, err = parseScheduleTime(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid schedule active_hours %q: %w", s.ActiveHours, err)
+	}
+	end, err = parseScheduleTime(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid schedule active_hours %q: %w", s.ActiveHours, err)
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("schedule active_hours %q start and end must differ", s.ActiveHours)
+	}
+	return start, end, nil
+}
+
+// DayMask parses Days into a weekday bitmask with bit 0 = Sunday. An empty
+// Days means every day; ranges wrap past the end of the week ("fri-mon").
+func (s *Schedule) DayMask() (uint8, error) {
+	const allDays = uint8(1<<7 - 1)
+	trimmed := strings.TrimSpace(s.Days)
+	if trimmed == "" {
+		return allDays, nil
+	}
+	var mask uint8
+	for _, token := range strings.Split(trimmed, ",") {
+		token = strings.TrimSpace(token)
+		if from, to, isRange := strings.Cut(token, "-"); isRange {
+			first, err := parseScheduleWeekday(strings.TrimSpace(from))
+			if err != nil {
+				return 0, err
+			}
+			last, err := parseScheduleWeekday(strings.TrimSpace(to))
+			if err != nil {
+				return 0, err
+			}
+			for d := first; ; d = (d + 1) % 7 {
+				mask |= 1 << uint(d)
+				if d == last {
+					break
+				}
+			}
+			continue
+		}
+		d, err := parseScheduleWeekday(token)
+		if err != nil {
+			return 0, err
+		}
+		mask |= 1 << uint(d)
+	}
+	return mask, nil
+}
+
+// validate checks the schedule block at load time. The parsed forms are
+// produced again when the block is compiled into its component.
+func (s *Schedule) validate() error {
+	if _, _, err := s.ActiveWindow(); err != nil {
+		return err
+	}
+	if _, err := s.DayMask(); err != nil {
+		return err
+	}
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("invalid schedule timezone %q: %w", s.Timezone, err)
+		}
+	}
+	return nil
+}
+
+// parseScheduleTime converts a "15:04" time of day into an offset from
+// midnight.
+func parseScheduleTime(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseScheduleWeekday matches a day name in either its "Mon" or "Monday"
+// form, case-insensitively.
+func parseScheduleWeekday(s string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) || strings.EqualFold(s, d.String()[:3]) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown schedule weekday %q", s)
+}
+
+type Pulse struct {
+	Config             PulseConfig   `json:"config"`
+	Type               string        `yaml:"type" json:"type"`
+	Groups             StringList    `yaml:"groups" json:"groups"`
+	Interval           time.Duration `yaml:"interval" json:"interval"`
+	Timeout            time.Duration `yaml:"timeout" json:"timeout"`
+	MaxFailures        int           `yaml:"max_failures" json:"max_failures"`
+	UnhealthyThreshold int           `yaml:"unhealthy_threshold" json:"unhealthy_threshold"`
+	// UnhealthyAttemptThreshold, when positive, counts individual failed
+	// attempts (including retries) across the current bad streak and trips
+	// the unhealthy escalation once the volume reaches it, even before
+	// unhealthy_threshold failed checks accumulate. With retries configured
+	// this distinguishes a hard-down target (many attempts per check) from
+	// a barely-failing one.
+	UnhealthyAttemptThreshold int `yaml:"unhealthy_attempt_threshold" json:"unhealthy_attempt_threshold"`
+	HealthyThreshold          int `yaml:"healthy_threshold" json:"healthy_threshold"`
+	// DetailedTiming enables per-phase latency measurement (dns, connect,
+	// tls, ttfb, transfer). Off by default: phase tracing has per-request
+	// overhead that matters at 1M-monitor scale.
+	DetailedTiming bool `yaml:"detailed_timing" json:"detailed_timing"`
+	// DegradedThreshold marks an otherwise-successful check as degraded when
+	// the DegradedPhase duration (or total latency if unset) exceeds it.
+	DegradedThreshold time.Duration `yaml:"degraded_threshold" json:"degraded_threshold"`
+	DegradedPhase     string        `yaml:"degraded_phase" json:"degraded_phase"`
+	// DegradedLatency triggers the yellow code path when the monitor's
+	// rolling (EWMA) check latency stays above it for consecutive
+	// successful checks. Unlike DegradedThreshold it looks at the trend,
+	// not a single slow check.
+	DegradedLatency time.Duration `yaml:"degraded_latency" json:"degraded_latency"`
+	// HighFrequency opts the monitor into sub-second intervals via the
+	// dedicated fast path. Capped in count by the loader's budget; intervals
+	// below one second are rejected without it.
+	HighFrequency bool `yaml:"high_frequency" json:"high_frequency"`
+	// RetryBackoff overrides the delay between retry attempts; nil keeps
+	// the default policy.
+	RetryBackoff *RetryBackoff `yaml:"retry_backoff" json:"retry_backoff"`
+	// Schedule restricts checks to the monitor's active hours; nil runs
+	// around the clock.
+	Schedule *Schedule `yaml:"schedule" json:"schedule,omitempty"`
+	// Invert flips the result interpretation for expected-failure checks:
+	// a successful probe counts as a failure toward the thresholds, while
+	// connection refused or timeout counts as healthy. Used to watch
+	// decommissioned endpoints that must stay down. Interventions stay off
+	// unless an intervention block is configured explicitly.
+	Invert bool `yaml:"invert" json:"invert"`
+	// Dedupe, when explicitly false, opts this monitor out of shared-probe
+	// deduplication (monitors probing the identical target share one
+	// outbound check). Nil means participate; the feature itself is off
+	// unless the controller enables it.
+	Dedupe *bool `yaml:"dedupe" json:"dedupe"`
+	// Trace opts this monitor's checks into OpenTelemetry spans when the
+	// controller has a trace exporter configured; without one the flag is
+	// inert.
+	Trace bool `yaml:"trace" json:"trace"`
+	// CountRateLimitAsFailure counts HTTP 429 (and 503-with-Retry-After)
+	// responses as ordinary failures. By default a rate-limited check is a
+	// distinct outcome that neither fails nor recovers the monitor.
+	CountRateLimitAsFailure bool `yaml:"count_rate_limit_as_failure" json:"count_rate_limit_as_failure"`
+}
+
+type rawPulse struct {
+	Type                      string        `yaml:"type"`
+	Groups                    StringList    `yaml:"groups"`
+	Interval                  time.Duration `yaml:"interval"`
+	Timeout                   time.Duration `yaml:"timeout"`
+	Retries                   int           `yaml:"retries"`
+	MaxFailures               int           `yaml:"max_failures"`
+	UnhealthyThreshold        int           `yaml:"unhealthy_threshold"`
+	UnhealthyAttemptThreshold int           `yaml:"unhealthy_attempt_threshold"`
+	HealthyThreshold          int           `yaml:"healthy_threshold"`
+	DetailedTiming            bool          `yaml:"detailed_timing"`
+	DegradedThreshold         time.Duration `yaml:"degraded_threshold"`
+	DegradedPhase             string        `yaml:"degraded_phase"`
+	DegradedLatency           time.Duration `yaml:"degraded_latency"`
+	HighFrequency             bool          `yaml:"high_frequency"`
+	RetryBackoff              *RetryBackoff `yaml:"retry_backoff"`
+	Schedule                  *Schedule     `yaml:"schedule"`
+	Invert                    bool          `yaml:"invert"`
+	Dedupe                    *bool         `yaml:"dedupe"`
+	Trace                     bool          `yaml:"trace"`
+	CountRateLimitAsFailure   bool          `yaml:"count_rate_limit_as_failure"`
+}
+
+func (p *Pulse) UnmarshalYAML(value *yaml.Node) error {
+	var temp struct {
+		Config   yaml.Node `yaml:"config"`
+		rawPulse `yaml:",inline"`
+	}
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+	*p = Pulse{
+		Type:                      temp.Type,
+		Interval:                  temp.Interval,
+		Timeout:                   temp.Timeout,
+		MaxFailures:               temp.MaxFailures,
+		UnhealthyThreshold:        temp.UnhealthyThreshold,
+		UnhealthyAttemptThreshold: temp.UnhealthyAttemptThreshold,
+		HealthyThreshold:          temp.HealthyThreshold,
+		Groups:                    temp.Groups,
+		DetailedTiming:            temp.DetailedTiming,
+		DegradedThreshold:         temp.DegradedThreshold,
+		DegradedPhase:             temp.DegradedPhase,
+		DegradedLatency:           temp.DegradedLatency,
+		HighFrequency:             temp.HighFrequency,
+		RetryBackoff:              temp.RetryBackoff,
+		Schedule:                  temp.Schedule,
+		Invert:                    temp.Invert,
+		Dedupe:                    temp.Dedupe,
+		Trace:                     temp.Trace,
+		CountRateLimitAsFailure:   temp.CountRateLimitAsFailure,
+	}
+	if p.Schedule != nil {
+		if err := p.Schedule.validate(); err != nil {
+			return err
+		}
+	}
+	// Backward compatibility: if UnhealthyThreshold not set, use MaxFailures
+	if p.UnhealthyThreshold == 0 && p.MaxFailures > 0 {
+		p.UnhealthyThreshold = p.MaxFailures
+	}
+	switch temp.Type {
+	case "http":
+		var c = &PulseHTTPConfig{} // FIX: Allocate on the heap
+		if err := temp.Config.Decode(c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "tcp":
+		var c = &PulseTCPConfig{} // FIX: Allocate on the heap
+		if err := temp.Config.Decode(c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "icmp":
+		var c = &PulseICMPConfig{} // FIX: Allocate on the heap
+		if err := temp.Config.Decode(c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "udp":
+		var c = &PulseUDPConfig{}
+		if err := temp.Config.Decode(c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "ntp":
+		var c = &PulseNTPConfig{}
+		if err := temp.Config.Decode(c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "dns":
+		var c = &PulseDNSConfig{}
+		if err := temp.Config.Decode(c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "grpc":
+		var c = &PulseGRPCConfig{}
+		if err := temp.Config.Decode(c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "docker":
+		var c = &PulseDockerConfig{}
+		if err := temp.Config.Decode(c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "heartbeat":
+		var c = &PulseHeartbeatConfig{}
+		// The config block is optional for heartbeats (grace defaults to 0)
+		if temp.Config.Kind != 0 {
+			if err := temp.Config.Decode(c); err != nil {
+				return err
+			}
+		}
+		p.Config = c
+	default:
+		return fmt.Errorf("unknown pulse type: %q", temp.Type)
+	}
+	return nil
+}
+
+// UnmarshalJSON handles JSON unmarshaling for Pulse (needed for JSON parser)
+func (p *Pulse) UnmarshalJSON(data []byte) error {
+	var temp struct {
+		Type                      string          `json:"type"`
+		Interval                  string          `json:"interval"`
+		Timeout                   string          `json:"timeout"`
+		Config                    json.RawMessage `json:"config"`
+		MaxFailures               int             `json:"max_failures"`
+		UnhealthyThreshold        int             `json:"unhealthy_threshold"`
+		UnhealthyAttemptThreshold int             `json:"unhealthy_attempt_threshold"`
+		HealthyThreshold          int             `json:"healthy_threshold"`
+		DetailedTiming            bool            `json:"detailed_timing"`
+		DegradedThreshold         string          `json:"degraded_threshold"`
+		DegradedPhase             string          `json:"degraded_phase"`
+		DegradedLatency           string          `json:"degraded_latency"`
+		HighFrequency             bool            `json:"high_frequency"`
+		RetryBackoff              *struct {
+			Strategy string `json:"strategy"`
+			Base     string `json:"base"`
+			Max      string `json:"max"`
+			Jitter   bool   `json:"jitter"`
+		} `json:"retry_backoff"`
+		Schedule                *Schedule `json:"schedule"`
+		Invert                  bool      `json:"invert"`
+		Dedupe                  *bool     `json:"dedupe"`
+		Trace                   bool      `json:"trace"`
+		CountRateLimitAsFailure bool      `json:"count_rate_limit_as_failure"`
+	}
+
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	// Parse duration strings
+	interval, err := time.ParseDuration(temp.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid interval duration %q: %w", temp.Interval, err)
+	}
+
+	// Timeout is optional for push-based types (heartbeat); the validator
+	// enforces it where required.
+	var timeout time.Duration
+	if temp.Timeout != "" {
+		timeout, err = time.ParseDuration(temp.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout duration %q: %w", temp.Timeout, err)
+		}
+	}
+
+	var degraded time.Duration
+	if temp.DegradedThreshold != "" {
+		degraded, err = time.ParseDuration(temp.DegradedThreshold)
+		if err != nil {
+			return fmt.Errorf("invalid degraded_threshold duration %q: %w", temp.DegradedThreshold, err)
+		}
+	}
+
+	var degradedLatency time.Duration
+	if temp.DegradedLatency != "" {
+		degradedLatency, err = time.ParseDuration(temp.DegradedLatency)
+		if err != nil {
+			return fmt.Errorf("invalid degraded_latency duration %q: %w", temp.DegradedLatency, err)
+		}
+	}
+
+	var retryBackoff *RetryBackoff
+	if temp.RetryBackoff != nil {
+		retryBackoff = &RetryBackoff{Strategy: temp.RetryBackoff.Strategy, Jitter: temp.RetryBackoff.Jitter}
+		if temp.RetryBackoff.Base != "" {
+			retryBackoff.Base, err = time.ParseDuration(temp.RetryBackoff.Base)
+			if err != nil {
+				return fmt.Errorf("invalid retry_backoff base duration %q: %w", temp.RetryBackoff.Base, err)
+			}
+		}
+		if temp.RetryBackoff.Max != "" {
+			retryBackoff.Max, err = time.ParseDuration(temp.RetryBackoff.Max)
+			if err != nil {
+				return fmt.Errorf("invalid retry_backoff max duration %q: %w", temp.RetryBackoff.Max, err)
+			}
+		}
+	}
+
+	*p = Pulse{
+		Type:                      temp.Type,
+		Interval:                  interval,
+		Timeout:                   timeout,
+		MaxFailures:               temp.MaxFailures,
+		UnhealthyThreshold:        temp.UnhealthyThreshold,
+		UnhealthyAttemptThreshold: temp.UnhealthyAttemptThreshold,
+		HealthyThreshold:          temp.HealthyThreshold,
+		DetailedTiming:            temp.DetailedTiming,
+		DegradedThreshold:         degraded,
+		DegradedPhase:             temp.DegradedPhase,
+		DegradedLatency:           degradedLatency,
+		HighFrequency:             temp.HighFrequency,
+		RetryBackoff:              retryBackoff,
+		Schedule:                  temp.Schedule,
+		Invert:                    temp.Invert,
+		Dedupe:                    temp.Dedupe,
+		Trace:                     temp.Trace,
+		CountRateLimitAsFailure:   temp.CountRateLimitAsFailure,
+	}
+	if p.Schedule != nil {
+		if err := p.Schedule.validate(); err != nil {
+			return err
+		}
+	}
+	if p.UnhealthyThreshold == 0 && p.MaxFailures > 0 {
+		p.UnhealthyThreshold = p.MaxFailures
+	}
+
+	switch temp.Type {
+	case "http":
+		var c = &PulseHTTPConfig{}
+		if err := json.Unmarshal(temp.Config, c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "tcp":
+		var c = &PulseTCPConfig{}
+		if err := json.Unmarshal(temp.Config, c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "icmp":
+		var c = &PulseICMPConfig{}
+		if err := json.Unmarshal(temp.Config, c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "udp":
+		var c = &PulseUDPConfig{}
+		if err := json.Unmarshal(temp.Config, c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "ntp":
+		var c = &PulseNTPConfig{}
+		if err := json.Unmarshal(temp.Config, c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "dns":
+		var c = &PulseDNSConfig{}
+		if err := json.Unmarshal(temp.Config, c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "grpc":
+		var c = &PulseGRPCConfig{}
+		if err := json.Unmarshal(temp.Config, c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "docker":
+		var c = &PulseDockerConfig{}
+		if err := json.Unmarshal(temp.Config, c); err != nil {
+			return err
+		}
+		p.Config = c
+	case "heartbeat":
+		var c = &PulseHeartbeatConfig{}
+		// The config block is optional for heartbeats (grace defaults to 0)
+		if len(temp.Config) > 0 {
+			if err := json.Unmarshal(temp.Config, c); err != nil {
+				return err
+			}
+		}
+		p.Config = c
+	default:
+		return fmt.Errorf("unknown pulse type: %q", temp.Type)
+	}
+	return nil
+}
+
+//// INTERVENTION TYPES
+
+type Intervention struct {
+	Target      InterventionTarget `yaml:"target"`
+	Action      string             `yaml:"action"`
+	Retries     int                `yaml:"retries"`
+	MaxFailures int                `yaml:"max_failures"`
+	// Cooldown is the minimum time between two interventions on the same
+	// monitor; dispatches inside the window are suppressed and escalate to
+	// the red code instead. Zero disables the cooldown.
+	Cooldown time.Duration `yaml:"cooldown"`
+	// MaxPerHour caps how many interventions may fire per hour; dispatches
+	// over the cap are suppressed like cooldown hits. Zero means unlimited.
+	MaxPerHour int `yaml:"max_per_hour"`
+	// DryRun evaluates and logs the intervention without performing the
+	// action: the job reports what it would have done instead of doing it.
+	// For escalation chains the flag applies to the chain as a whole.
+	DryRun bool `yaml:"dry_run"`
+	// Steps holds the escalation chain when the manifest uses the list form.
+	// Step 0 is mirrored into Action/Target/Retries so legacy consumers of
+	// the single-map form keep working.
+	Steps []InterventionStep `yaml:"-" json:"-"`
+}
+
+// InterventionStep is one level of an intervention escalation chain.
+type InterventionStep struct {
+	Target  InterventionTarget
+	Action  string
+	Retries int
+	// AfterFailures is how many consecutive failures must accumulate after
+	// the previous step ran before this step is tried. Step 0 runs at the
+	// first intervention trigger.
+	AfterFailures int
+	// DryRun is stamped from the chain-level dry_run flag so per-step job
+	// creation sees it.
+	DryRun bool
+}
+
+type rawIntervention struct {
+	Action      string        `yaml:"action"`
+	Retries     int           `yaml:"retries"`
+	MaxFailures int           `yaml:"max_failures"`
+	Cooldown    time.Duration `yaml:"cooldown"`
+	MaxPerHour  int           `yaml:"max_per_hour"`
+	DryRun      bool          `yaml:"dry_run"`
+}
+
+// decodeInterventionTargetYAML decodes a target node according to the action.
+func decodeInterventionTargetYAML(action string, node *yaml.Node) (InterventionTarget, error) {
+	switch action {
+	case "docker":
+		var t = &InterventionTargetDocker{} // FIX: Allocate on the heap
+		if err := node.Decode(t); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case "exec":
+		var t = &InterventionTargetExec{}
+		if err := node.Decode(t); err != nil {
+			return nil, err
+		}
+		if err := t.validate(); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case "webhook":
+		var t = &InterventionTargetWebhook{}
+		if err := node.Decode(t); err != nil {
+			return nil, err
+		}
+		if err := t.validate(); err != nil {
+			return nil, err
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("unknown intervention type: %q", action)
+	}
+}
+
+// decodeInterventionTargetJSON decodes a raw target according to the action.
+func decodeInterventionTargetJSON(action string, raw json.RawMessage) (InterventionTarget, error) {
+	switch action {
+	case "docker":
+		var t = &InterventionTargetDocker{}
+		if err := json.Unmarshal(raw, t); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case "exec":
+		var t = &InterventionTargetExec{}
+		if err := json.Unmarshal(raw, t); err != nil {
+			return nil, err
+		}
+		if err := t.validate(); err != nil {
+			return nil, err
+		}
+		return t, nil
+	case "webhook":
+		var t = &InterventionTargetWebhook{}
+		if err := json.Unmarshal(raw, t); err != nil {
+			return nil, err
+		}
+		if err := t.validate(); err != nil {
+			return nil, err
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("unknown intervention type: %q", action)
+	}
+}
+
+// validateInterventionSteps rejects chains that could never escalate in
+// order, so bad manifests fail at load time.
+func validateInterventionSteps(steps []InterventionStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("intervention chain requires at least one step")
+	}
+	if steps[0].AfterFailures != 0 {
+		return fmt.Errorf("first intervention step must have after_failures 0")
+	}
+	for n := 1; n < len(steps); n++ {
+		if steps[n].AfterFailures <= steps[n-1].AfterFailures {
+			return fmt.Errorf("intervention step %d: after_failures must increase along the chain", n)
+		}
+	}
+	return nil
+}
+
+// fromSteps fills an Intervention from a decoded chain, mirroring step 0
+// into the legacy single-action fields.
+func (i *Intervention) fromSteps(steps []InterventionStep) error {
+	if err := validateInterventionSteps(steps); err != nil {
+		return err
+	}
+	*i = Intervention{
+		Action:  steps[0].Action,
+		Target:  steps[0].Target,
+		Retries: steps[0].Retries,
+		Steps:   steps,
+	}
+	return nil
+}
+
+// setDryRun stamps the chain-level dry_run flag onto the intervention and
+// every step.
+func (i *Intervention) setDryRun(v bool) {
+	i.DryRun = v
+	for n := range i.Steps {
+		i.Steps[n].DryRun = v
+	}
+}
+
+// StepList returns the escalation chain. A legacy single-map intervention
+// yields one step with a zero threshold.
+func (i *Intervention) StepList() []InterventionStep {
+	if len(i.Steps) > 0 {
+		return i.Steps
+	}
+	if i.Action == "" {
+		return nil
+	}
+	return []InterventionStep{{
+		Action:  i.Action,
+		Target:  i.Target,
+		Retries: i.Retries,
+		DryRun:  i.DryRun,
+	}}
+}
+
+func (i *Intervention) UnmarshalYAML(value *yaml.Node) error {
+	// The list form declares an escalation chain of steps.
+	if value.Kind == yaml.SequenceNode {
+		var rawSteps []struct {
+			rawIntervention `yaml:",inline"`
+			AfterFailures   int       `yaml:"after_failures"`
+			Target          yaml.Node `yaml:"target"`
+		}
+		if err := value.Decode(&rawSteps); err != nil {
+			return err
+		}
+		steps := make([]InterventionStep, 0, len(rawSteps))
+		for _, raw := range rawSteps {
+			target, err := decodeInterventionTargetYAML(raw.Action, &raw.Target)
+			if err != nil {
+				return err
+			}
+			steps = append(steps, InterventionStep{
+				Target:        target,
+				Action:        raw.Action,
+				Retries:       raw.Retries,
+				AfterFailures: raw.AfterFailures,
+			})
+		}
+		if err := i.fromSteps(steps); err != nil {
+			return err
+		}
+		// Throttle settings apply to the chain as a whole and are read
+		// from its first step.
+		i.MaxFailures = rawSteps[0].MaxFailures
+		i.Cooldown = rawSteps[0].Cooldown
+		i.MaxPerHour = rawSteps[0].MaxPerHour
+		i.setDryRun(rawSteps[0].DryRun)
+		return nil
+	}
+
+	var temp struct {
+		rawIntervention `yaml:",inline"`
+		Target          yaml.Node `yaml:"target"`
+	}
+	if err := value.Decode(&temp); err != nil {
+		return err
+	}
+	target, err := decodeInterventionTargetYAML(temp.Action, &temp.Target)
+	if err != nil {
+		return err
+	}
+	*i = Intervention{
+		Action:      temp.Action,
+		Retries:     temp.Retries,
+		MaxFailures: temp.MaxFailures,
+		Target:      target,
+		Cooldown:    temp.Cooldown,
+		MaxPerHour:  temp.MaxPerHour,
+		DryRun:      temp.DryRun,
+	}
+	return nil
+}
+
+// UnmarshalJSON handles JSON unmarshaling for Intervention (needed for JSON parser)
+func (i *Intervention) UnmarshalJSON(data []byte) error {
+	// The list form declares an escalation chain of steps.
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var rawSteps []struct {
+			Action        string          `json:"action"`
+			Target        json.RawMessage `json:"target"`
+			Retries       int             `json:"retries"`
+			AfterFailures int             `json:"after_failures"`
+			MaxFailures   int             `json:"max_failures"`
+			Cooldown      string          `json:"cooldown"`
+			MaxPerHour    int             `json:"max_per_hour"`
+			DryRun        bool            `json:"dry_run"`
+		}
+		if err := json.Unmarshal(data, &rawSteps); err != nil {
+			return err
+		}
+		steps := make([]InterventionStep, 0, len(rawSteps))
+		for _, raw := range rawSteps {
+			target, err := decodeInterventionTargetJSON(raw.Action, raw.Target)
+			if err != nil {
+				return err
+			}
+			steps = append(steps, InterventionStep{
+				Target:        target,
+				Action:        raw.Action,
+				Retries:       raw.Retries,
+				AfterFailures: raw.AfterFailures,
+			})
+		}
+		if err := i.fromSteps(steps); err != nil {
+			return err
+		}
+		// Throttle settings apply to the chain as a whole and are read
+		// from its first step.
+		cooldown, err := parseInterventionCooldown(rawSteps[0].Cooldown)
+		if err != nil {
+			return err
+		}
+		i.MaxFailures = rawSteps[0].MaxFailures
+		i.Cooldown = cooldown
+		i.MaxPerHour = rawSteps[0].MaxPerHour
+		i.setDryRun(rawSteps[0].DryRun)
+		return nil
+	}
+
+	var temp struct {
+		Action      string          `json:"action"`
+		Target      json.RawMessage `json:"target"`
+		Retries     int             `json:"retries"`
+		MaxFailures int             `json:"max_failures"`
+		Cooldown    string          `json:"cooldown"`
+		MaxPerHour  int             `json:"max_per_hour"`
+		DryRun      bool            `json:"dry_run"`
+	}
+
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	target, err := decodeInterventionTargetJSON(temp.Action, temp.Target)
+	if err != nil {
+		return err
+	}
+	cooldown, err := parseInterventionCooldown(temp.Cooldown)
+	if err != nil {
+		return err
+	}
+	*i = Intervention{
+		Action:      temp.Action,
+		Retries:     temp.Retries,
+		MaxFailures: temp.MaxFailures,
+		Target:      target,
+		Cooldown:    cooldown,
+		MaxPerHour:  temp.MaxPerHour,
+		DryRun:      temp.DryRun,
+	}
+	return nil
+}
+
+// parseInterventionCooldown parses the cooldown from a duration string
+// (e.g. "10m"); empty means no cooldown.
+func parseInterventionCooldown(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	cooldown, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cooldown duration %q: %w", s, err)
+	}
+	return cooldown, nil
+}
+
+type InterventionTarget interface {
+	GetTargetType() string
+	Copy() InterventionTarget
+}
+
+type InterventionTargetDocker struct {
+	Type       string        `yaml:"type" json:"type"`                               // restart, stop, start, kill, pause, unpause, scale
+	Container  string        `yaml:"container,omitempty" json:"container,omitempty"` // For container ops
+	Service    string        `yaml:"service,omitempty" json:"service,omitempty"`     // For swarm scale
+	Replicas   uint64        `yaml:"replicas,omitempty" json:"replicas,omitempty"`   // For swarm scale
+	Signal     string        `yaml:"signal,omitempty" json:"signal,omitempty"`       // For kill (default: SIGKILL)
+	DockerHost string        `yaml:"docker_host,omitempty" json:"docker_host,omitempty"`
+	Timeout    time.Duration `yaml:"timeout" json:"timeout"`
+}
+
+func (i *InterventionTargetDocker) Copy() InterventionTarget {
+	return &InterventionTargetDocker{
+		Type:       strings.Clone(i.Type),
+		Container:  strings.Clone(i.Container),
+		Service:    strings.Clone(i.Service),
+		Replicas:   i.Replicas,
+		Signal:     strings.Clone(i.Signal),
+		DockerHost: strings.Clone(i.DockerHost),
+	}
+}
+
+func (i *InterventionTargetDocker) GetTargetType() string {
+	return i.Type
+}
+
+// InterventionTargetExec runs a local command (e.g. systemctl restart) as an
+// automated intervention. The command is executed directly, never through a
+// shell, so arguments cannot be injected.
+type InterventionTargetExec struct {
+	Command    string            `yaml:"command" json:"command"`
+	Args       []string          `yaml:"args" json:"args"`
+	WorkingDir string            `yaml:"working_dir" json:"working_dir"`
+	Env        map[string]string `yaml:"env" json:"env"`
+	Timeout    time.Duration     `yaml:"timeout" json:"timeout"`
+	// ExpectedExitCodes lists exit codes treated as success; empty means [0].
+	ExpectedExitCodes []int `yaml:"expected_exit_codes" json:"expected_exit_codes"`
+}
+
+func (i *InterventionTargetExec) Copy() InterventionTarget {
+	var env map[string]string
+	if i.Env != nil {
+		env = make(map[string]string, len(i.Env))
+		for k, v := range i.Env {
+			env[strings.Clone(k)] = strings.Clone(v)
+		}
+	}
+	args := make([]string, len(i.Args))
+	for n, a := range i.Args {
+		args[n] = strings.Clone(a)
+	}
+	return &InterventionTargetExec{
+		Command:           strings.Clone(i.Command),
+		Args:              args,
+		WorkingDir:        strings.Clone(i.WorkingDir),
+		Env:               env,
+		Timeout:           i.Timeout,
+		ExpectedExitCodes: append([]int(nil), i.ExpectedExitCodes...),
+	}
+}
+
+func (i *InterventionTargetExec) GetTargetType() string {
+	return "exec"
+}
+
+// validate rejects exec targets that can never run, so bad manifests fail at
+// load time rather than on the first intervention.
+func (i *InterventionTargetExec) validate() error {
+	if i.Command == "" {
+		return fmt.Errorf("exec intervention requires command")
+	}
+	return nil
+}
+
+// InterventionTargetWebhook drives remediation through an external HTTP
+// endpoint (e.g. a runbook automation service). The body template receives
+// the monitor name and failure count at dispatch time.
+type InterventionTargetWebhook struct {
+	URL string `yaml:"url" json:"url"`
+	// Method defaults to POST when empty.
+	Method  string            `yaml:"method" json:"method"`
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	// BodyTemplate is a text/template rendered with {{.Monitor}} and
+	// {{.FailureCount}}. Empty sends a default JSON body.
+	BodyTemplate string `yaml:"body_template" json:"body_template"`
+	// ExpectedStatus lists response codes treated as success; empty means 2xx.
+	ExpectedStatus []int `yaml:"expected_status" json:"expected_status"`
+	// SecretEnv names an environment variable holding the HMAC signing secret.
+	SecretEnv string        `yaml:"secret_env" json:"secret_env"`
+	Timeout   time.Duration `yaml:"timeout" json:"timeout"`
+}
+
+func (i *InterventionTargetWebhook) Copy() InterventionTarget {
+	var headers map[string]string
+	if i.Headers != nil {
+		headers = make(map[string]string, len(i.Headers))
+		for k, v := range i.Headers {
+			headers[strings.Clone(k)] = strings.Clone(v)
+		}
+	}
+	return &InterventionTargetWebhook{
+		URL:            strings.Clone(i.URL),
+		Method:         strings.Clone(i.Method),
+		Headers:        headers,
+		BodyTemplate:   strings.Clone(i.BodyTemplate),
+		ExpectedStatus: append([]int(nil), i.ExpectedStatus...),
+		SecretEnv:      strings.Clone(i.SecretEnv),
+		Timeout:        i.Timeout,
+	}
+}
+
+func (i *InterventionTargetWebhook) GetTargetType() string {
+	return "webhook"
+}
+
+// validate rejects webhook targets without an endpoint, so bad manifests fail
+// at load time rather than on the first intervention.
+func (i *InterventionTargetWebhook) validate() error {
+	if i.URL == "" {
+		return fmt.Errorf("webhook intervention requires url")
+	}
+	return nil
+}
+
+type CodeNotification interface {
+	IsCodeNotification()
+	Copy() CodeNotification
+}
+
+type CodeNotificationLog struct {
+	File string `yaml:"file" json:"file"`
+}
+
+func (c *CodeNotificationLog) Copy() CodeNotification {
+	return &CodeNotificationLog{
+		File: strings.Clone(c.File),
+	}
+}
+
+func (c *CodeNotificationLog) IsCodeNotification() {
+}
+
+type CodeNotificationPagerDuty struct {
+	// URL overrides the Events API v2 endpoint; empty uses the default.
+	URL string `yaml:"url" json:"url"`
+	// RoutingKey is the Events API v2 integration routing key.
+	RoutingKey string `yaml:"routing_key" json:"routing_key"`
+	// RoutingKeyEnv names an environment variable to read the routing key
+	// from, so manifests need not embed the secret.
+	RoutingKeyEnv string `yaml:"routing_key_env" json:"routing_key_env"`
+}
+
+func (c *CodeNotificationPagerDuty) Copy() CodeNotification {
+	return &CodeNotificationPagerDuty{
+		URL:           strings.Clone(c.URL),
+		RoutingKey:    strings.Clone(c.RoutingKey),
+		RoutingKeyEnv: strings.Clone(c.RoutingKeyEnv),
+	}
+}
+
+func (c *CodeNotificationPagerDuty) IsCodeNotification() {
+}
+
+type CodeNotificationSlack struct {
+	WebHook string `yaml:"hook" json:"hook"`
+}
+
+func (c *CodeNotificationSlack) Copy() CodeNotification {
+	return &CodeNotificationSlack{
+		WebHook: strings.Clone(c.WebHook),
+	}
+}
+
+func (c *CodeNotificationSlack) IsCodeNotification() {
+}
+
+type CodeNotificationEmail struct {
+	SMTPHost string `yaml:"smtp_host" json:"smtp_host"`
+	// SMTPPort defaults to 587 when zero.
+	SMTPPort int      `yaml:"smtp_port" json:"smtp_port"`
+	From     string   `yaml:"from" json:"from"`
+	To       []string `yaml:"to" json:"to"`
+	// SubjectPrefix is prepended verbatim to the generated subject line.
+	SubjectPrefix string `yaml:"subject_prefix" json:"subject_prefix"`
+	Username      string `yaml:"username" json:"username"`
+	Password      string `yaml:"password" json:"password"`
+	// UsernameEnv and PasswordEnv name environment variables to read the
+	// credentials from, so manifests need not embed secrets.
+	UsernameEnv string `yaml:"username_env" json:"username_env"`
+	PasswordEnv string `yaml:"password_env" json:"password_env"`
+	// StartTLS upgrades the connection via STARTTLS before authenticating.
+	StartTLS bool `yaml:"starttls" json:"starttls"`
+}
+
+func (c *CodeNotificationEmail) Copy() CodeNotification {
+	var to []string
+	if c.To != nil {
+		to = make([]string, len(c.To))
+		for i, addr := range c.To {
+			to[i] = strings.Clone(addr)
+		}
+	}
+	return &CodeNotificationEmail{
+		SMTPHost:      strings.Clone(c.SMTPHost),
+		SMTPPort:      c.SMTPPort,
+		From:          strings.Clone(c.From),
+		To:            to,
+		SubjectPrefix: strings.Clone(c.SubjectPrefix),
+		Username:      strings.Clone(c.Username),
+		Password:      strings.Clone(c.Password),
+		UsernameEnv:   strings.Clone(c.UsernameEnv),
+		PasswordEnv:   strings.Clone(c.PasswordEnv),
+		StartTLS:      c.StartTLS,
+	}
+}
+
+func (c *CodeNotificationEmail) IsCodeNotification() {
+}
+
+// validate rejects email configs that can never deliver, so bad manifests
+// fail at load time rather than on the first alert.
+func (c *CodeNotificationEmail) validate() error {
+	if c.SMTPHost == "" {
+		return fmt.Errorf("email notification requires smtp_host")
+	}
+	if c.From == "" {
+		return fmt.Errorf("email notification requires from")
+	}
+	if len(c.To) == 0 {
+		return fmt.Errorf("email notification requires at least one to address")
+	}
+	return nil
+}
+
+type CodeNotificationWebhook struct {
+	URL string `yaml:"url" json:"url"`
+	// Method defaults to POST when empty.
+	Method  string            `yaml:"method" json:"method"`
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	// Secret, when set, signs the request body with HMAC-SHA256; the
+	// signature is sent in the X-CPRA-Signature header.
+	Secret string `yaml:"secret" json:"secret"`
+	// PayloadTemplate is an optional Go text/template rendered with the
+	// alert fields (.Monitor, .Color, .Status, .Severity, .Summary,
+	// .Action, .NextSteps). Empty sends a default JSON payload.
+	PayloadTemplate string `yaml:"payload_template" json:"payload_template"`
+}
+
+func (c *CodeNotificationWebhook) Copy() CodeNotification {
+	var headers map[string]string
+	if c.Headers != nil {
+		headers = make(map[string]string, len(c.Headers))
+		for k, v := range c.Headers {
+			headers[strings.Clone(k)] = strings.Clone(v)
+		}
+	}
+	return &CodeNotificationWebhook{
+		URL:             strings.Clone(c.URL),
+		Method:          strings.Clone(c.Method),
+		Headers:         headers,
+		Secret:          strings.Clone(c.Secret),
+		PayloadTemplate: strings.Clone(c.PayloadTemplate),
+	}
+}
+
+func (c *CodeNotificationWebhook) IsCodeNotification() {
+}
+
+type CodeConfig struct {
+	Config CodeNotification `yaml:"config" json:"config"`
+	Notify string           `yaml:"notify" json:"notify"`
+	// Cooldown suppresses repeat alerts of the same color fired within the
+	// window. Zero disables suppression.
+	Cooldown time.Duration `yaml:"cooldown" json:"cooldown"`
+	Dispatch bool          `yaml:"dispatch" json:"dispatch"`
+	// Extra holds additional targets for the same color, filled in when
+	// more than one notify group routes this color to a monitor. Never
+	// written in manifests; see Monitor.ResolveNotifyGroups.
+	Extra []CodeConfig `yaml:"-" json:"-"`
+}
+
+type Codes map[string]CodeConfig
+
+type rawCodes struct {
+	Dispatch *bool  `yaml:"dispatch" json:"dispatch"` // Pointer to detect omitted field
+	Notify   string `yaml:"notify" json:"notify"`
+	Cooldown string `yaml:"cooldown" json:"cooldown"`
+}
+
+func (c *Codes) UnmarshalYAML(value *yaml.Node) error {
+	var codes map[string]yaml.Node
+	if err := value.Decode(&codes); err != nil {
+		return err
+	}
+	colors := make(map[string]CodeConfig)
+	for color, config := range codes {
+		var temp struct {
+			rawCodes `yaml:",inline"`
+			Config   yaml.Node `yaml:"config"`
+		}
+		if err := config.Decode(&temp); err != nil {
+			return err
+		}
+		// Default dispatch to true if omitted
+		dispatch := true
+		if temp.Dispatch != nil {
+			dispatch = *temp.Dispatch
+		}
+
+		var cooldown time.Duration
+		if temp.Cooldown != "" {
+			var err error
+			cooldown, err = time.ParseDuration(temp.Cooldown)
+			if err != nil {
+				return fmt.Errorf("invalid cooldown for code %q: %w", color, err)
+			}
+		}
+
+		switch temp.Notify {
+		case "log":
+			var t = &CodeNotificationLog{} // FIX: Allocate on the heap
+			if err := temp.Config.Decode(t); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   temp.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		case "slack":
+			var t = &CodeNotificationSlack{} // FIX: Allocate on the heap
+			if err := temp.Config.Decode(t); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   temp.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		case "pagerduty":
+			var t = &CodeNotificationPagerDuty{} // FIX: Allocate on the heap
+			if err := temp.Config.Decode(t); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   temp.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		case "email":
+			var t = &CodeNotificationEmail{} // FIX: Allocate on the heap
+			if err := temp.Config.Decode(t); err != nil {
+				return err
+			}
+			if err := t.validate(); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   temp.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		case "webhook":
+			var t = &CodeNotificationWebhook{} // FIX: Allocate on the heap
+			if err := temp.Config.Decode(t); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   temp.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		default:
+			return fmt.Errorf("unknown notification type: %q", temp.Notify)
+		}
+	}
+	*c = colors
+	return nil
+}
+
+// UnmarshalJSON handles JSON unmarshaling for Codes (needed for JSON parser)
+func (c *Codes) UnmarshalJSON(data []byte) error {
+	var codes map[string]struct {
+		Dispatch *bool           `json:"dispatch"` // Pointer to detect omitted field
+		Notify   string          `json:"notify"`
+		Cooldown string          `json:"cooldown"`
+		Config   json.RawMessage `json:"config"`
+	}
+
+	if err := json.Unmarshal(data, &codes); err != nil {
+		return err
+	}
+
+	colors := make(map[string]CodeConfig)
+	for color, config := range codes {
+		// Default dispatch to true if omitted
+		dispatch := true
+		if config.Dispatch != nil {
+			dispatch = *config.Dispatch
+		}
+
+		var cooldown time.Duration
+		if config.Cooldown != "" {
+			var err error
+			cooldown, err = time.ParseDuration(config.Cooldown)
+			if err != nil {
+				return fmt.Errorf("invalid cooldown for code %q: %w", color, err)
+			}
+		}
+
+		switch config.Notify {
+		case "log":
+			var t = &CodeNotificationLog{}
+			if err := json.Unmarshal(config.Config, t); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   config.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		case "slack":
+			var t = &CodeNotificationSlack{}
+			if err := json.Unmarshal(config.Config, t); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   config.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		case "pagerduty":
+			var t = &CodeNotificationPagerDuty{}
+			if err := json.Unmarshal(config.Config, t); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   config.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		case "email":
+			var t = &CodeNotificationEmail{}
+			if err := json.Unmarshal(config.Config, t); err != nil {
+				return err
+			}
+			if err := t.validate(); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   config.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		case "webhook":
+			var t = &CodeNotificationWebhook{}
+			if err := json.Unmarshal(config.Config, t); err != nil {
+				return err
+			}
+			colors[color] = CodeConfig{
+				Dispatch: dispatch,
+				Notify:   config.Notify,
+				Cooldown: cooldown,
+				Config:   t,
+			}
+		default:
+			return fmt.Errorf("unknown notification type: %q", config.Notify)
+		}
+	}
+	*c = colors
+	return nil
+}
+
+// MaintenanceWindow describes one scheduled maintenance window. While a
+// window is active the monitor is suppressed (no pulses, no interventions,
+// no alerts) and a single gray code announces the window on entry.
+type MaintenanceWindow struct {
+	// Start and End are RFC3339 timestamps for a one-off window. Recurring
+	// windows use a time of day ("22:00") for daily recurrence, or a weekday
+	// plus time of day ("Sat 22:00") for weekly recurrence. End may lie
+	// before Start for recurring windows, wrapping past midnight or the end
+	// of the week.
+	Start string `yaml:"start" json:"start"`
+	End   string `yaml:"end" json:"end"`
+	// Recurrence is empty for a one-off window, "daily" or "weekly".
+	Recurrence string `yaml:"recurrence,omitempty" json:"recurrence,omitempty"`
+	// Timezone is the IANA zone name ("Europe/Berlin") the window is
+	// evaluated in. Empty means UTC.
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// validate checks the fields a window must always carry. The start/end
+// formats are fully parsed when the window is compiled into its component
+// at load time.
+func (w *MaintenanceWindow) validate() error {
+	if w.Start == "" || w.End == "" {
+		return fmt.Errorf("maintenance window requires start and end")
+	}
+	switch w.Recurrence {
+	case "", "daily", "weekly":
+	default:
+		return fmt.Errorf("invalid maintenance recurrence %q (want daily or weekly)", w.Recurrence)
+	}
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("invalid maintenance timezone %q: %w", w.Timezone, err)
+		}
+	}
+	return nil
+}
+
+// SLO declares a service-level objective evaluated over a rolling window.
+// Check outcomes are counted in coarse buckets and a periodic system turns
+// the error-budget burn rate into yellow (warning burn) or red (critical
+// burn) codes, independently of the consecutive-failure logic.
+type SLO struct {
+	// Availability is the target percentage of successful checks over the
+	// window, e.g. 99.9.
+	Availability float64 `yaml:"availability" json:"availability"`
+	// Window is the rolling compliance window as a duration string, e.g.
+	// "720h" for 30 days. Outcomes are tracked in per-hour buckets, so
+	// windows longer than 30 days are capped at load time.
+	Window string `yaml:"window" json:"window"`
+	// Latency optionally adds a latency objective on top of availability.
+	Latency *SLOLatency `yaml:"latency,omitempty" json:"latency,omitempty"`
+}
+
+// SLOLatency is the optional latency half of an SLO: a target percentage of
+// checks that must complete under a threshold.
+type SLOLatency struct {
+	// Threshold is the duration above which a successful check counts as
+	// slow, e.g. "500ms".
+	Threshold string `yaml:"threshold" json:"threshold"`
+	// Target is the percentage of checks that must finish under Threshold,
+	// e.g. 99.0.
+	Target float64 `yaml:"target" json:"target"`
+}
+
+// validate checks the objective's ranges. The duration strings are fully
+// parsed again when the block is compiled into its component at load time.
+func (s *SLO) validate() error {
+	if s.Availability <= 0 || s.Availability >= 100 {
+		return fmt.Errorf("slo availability must be between 0 and 100 exclusive, got %v", s.Availability)
+	}
+	window, err := time.ParseDuration(s.Window)
+	if err != nil {
+		return fmt.Errorf("invalid slo window %q: %w", s.Window, err)
+	}
+	if window < time.Hour {
+		return fmt.Errorf("slo window must be at least 1h, got %q", s.Window)
+	}
+	if s.Latency != nil {
+		threshold, err := time.ParseDuration(s.Latency.Threshold)
+		if err != nil {
+			return fmt.Errorf("invalid slo latency threshold %q: %w", s.Latency.Threshold, err)
+		}
+		if threshold <= 0 {
+			return fmt.Errorf("slo latency threshold must be positive, got %q", s.Latency.Threshold)
+		}
+		if s.Latency.Target <= 0 || s.Latency.Target >= 100 {
+			return fmt.Errorf("slo latency target must be between 0 and 100 exclusive, got %v", s.Latency.Target)
+		}
+	}
+	return nil
+}
+
+type Monitor struct {
+	Codes        Codes               `yaml:"codes" json:"codes"`
+	Name         string              `yaml:"name" json:"name"`
+	Intervention Intervention        `yaml:"intervention,omitempty" json:"intervention,omitempty"`
+	Maintenance  []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	// DependsOn names the monitors this monitor sits behind. While any of
+	// them is failed, code alerts for this monitor are suppressed. Names
+	// must refer to monitors in the same manifest; unknown names and
+	// dependency cycles are rejected at load time.
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	// NotifyGroups names the route groups whose notification targets this
+	// monitor inherits; see Routes. Resolved into Codes at load time.
+	NotifyGroups StringList `yaml:"notify_groups,omitempty" json:"notify_groups,omitempty"`
+	// SLO declares an optional error-budget objective for this monitor.
+	SLO *SLO `yaml:"slo,omitempty" json:"slo,omitempty"`
+	// Labels attach arbitrary metadata (team, tier, runbook URL) to the
+	// monitor. They are carried into notifications, snapshots and events so
+	// downstream routing can key off them, and are immutable per load —
+	// changing them requires a reload. Keys are restricted to a safe
+	// charset and the set is bounded; see the loader's label rule.
+	Labels  map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Pulse   Pulse             `yaml:"pulse_check" json:"pulse_check"`
+	Enabled bool              `yaml:"enabled" json:"enabled"`
+}
+
+// ResolveNotifyGroups merges the code configs of every group named in
+// notify_groups into the monitor's own codes. Colors the monitor declares
+// locally always win over route targets; a color contributed by more than
+// one group fans out to every target. Unknown group names are an error so
+// a typo never silently drops a route's alerts.
+func (m *Monitor) ResolveNotifyGroups(routes Routes) error {
+	if len(m.NotifyGroups) == 0 {
+		return nil
+	}
+	local := make(map[string]struct{}, len(m.Codes))
+	for color := range m.Codes {
+		local[color] = struct{}{}
+	}
+	for _, group := range m.NotifyGroups {
+		codes, ok := routes[group]
+		if !ok {
+			return fmt.Errorf("unknown notify group %q", group)
+		}
+		// Deterministic order so fan-out targets line up across reloads.
+		colors := make([]string, 0, len(codes))
+		for color := range codes {
+			colors = append(colors, color)
+		}
+		sort.Strings(colors)
+		for _, color := range colors {
+			if _, ok := local[color]; ok {
+				continue // monitor-local config wins for this color
+			}
+			cfg := codes[color]
+			if cfg.Config != nil {
+				// Routes are shared between every monitor in the group;
+				// copy so per-entity state never aliases.
+				cfg.Config = cfg.Config.Copy()
+			}
+			if existing, ok := m.Codes[color]; ok {
+				existing.Extra = append(existing.Extra, cfg)
+				m.Codes[color] = existing
+			} else {
+				if m.Codes == nil {
+					m.Codes = Codes{}
+				}
+				m.Codes[color] = cfg
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalYAML sets default values for the Monitor struct, specifically for the Enabled field.
+func (m *Monitor) UnmarshalYAML(value *yaml.Node) error {
+	// Create a temporary struct with a pointer to a bool for 'Enabled'
+	type TmpMonitor struct {
+		Enabled      *bool               `yaml:"enabled"`
+		Codes        Codes               `yaml:"codes"`
+		Name         string              `yaml:"name"`
+		Labels       map[string]string   `yaml:"labels,omitempty"`
+		Intervention Intervention        `yaml:"intervention,omitempty"`
+		Maintenance  []MaintenanceWindow `yaml:"maintenance,omitempty"`
+		DependsOn    []string            `yaml:"depends_on,omitempty"`
+		NotifyGroups StringList          `yaml:"notify_groups,omitempty"`
+		SLO          *SLO                `yaml:"slo,omitempty"`
+		Pulse        Pulse               `yaml:"pulse_check"`
+	}
+
+	var tmp TmpMonitor
+	if err := value.Decode(&tmp); err != nil {
+		return err
+	}
+
+	for i := range tmp.Maintenance {
+		if err := tmp.Maintenance[i].validate(); err != nil {
+			return err
+		}
+	}
+	if tmp.SLO != nil {
+		if err := tmp.SLO.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Assign fields to the actual monitor struct
+	m.Name = tmp.Name
+	m.Labels = tmp.Labels
+	m.Pulse = tmp.Pulse
+	m.Intervention = tmp.Intervention
+	m.Maintenance = tmp.Maintenance
+	m.DependsOn = tmp.DependsOn
+	m.NotifyGroups = tmp.NotifyGroups
+	m.SLO = tmp.SLO
+	m.Codes = tmp.Codes
+
+	// Set 'Enabled' to true if it's not specified in the YAML
+	if tmp.Enabled == nil {
+		m.Enabled = true
+	} else {
+		m.Enabled = *tmp.Enabled
+	}
+
+	return nil
+}
+
+// UnmarshalJSON mirrors UnmarshalYAML for JSON manifests: maintenance
+// windows are validated and 'enabled' defaults to true when omitted.
+func (m *Monitor) UnmarshalJSON(data []byte) error {
+	type TmpMonitor struct {
+		Enabled      *bool               `json:"enabled"`
+		Codes        Codes               `json:"codes"`
+		Name         string              `json:"name"`
+		Labels       map[string]string   `json:"labels,omitempty"`
+		Intervention Intervention        `json:"intervention,omitempty"`
+		Maintenance  []MaintenanceWindow `json:"maintenance,omitempty"`
+		DependsOn    []string            `json:"depends_on,omitempty"`
+		NotifyGroups StringList          `json:"notify_groups,omitempty"`
+		SLO          *SLO                `json:"slo,omitempty"`
+		Pulse        Pulse               `json:"pulse_check"`
+	}
+
+	var tmp TmpMonitor
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+
+	for i := range tmp.Maintenance {
+		if err := tmp.Maintenance[i].validate(); err != nil {
+			return err
+		}
+	}
+	if tmp.SLO != nil {
+		if err := tmp.SLO.validate(); err != nil {
+			return err
+		}
+	}
+
+	m.Name = tmp.Name
+	m.Labels = tmp.Labels
+	m.Pulse = tmp.Pulse
+	m.Intervention = tmp.Intervention
+	m.Maintenance = tmp.Maintenance
+	m.DependsOn = tmp.DependsOn
+	m.NotifyGroups = tmp.NotifyGroups
+	m.SLO = tmp.SLO
+	m.Codes = tmp.Codes
+
+	if tmp.Enabled == nil {
+		m.Enabled = true
+	} else {
+		m.Enabled = *tmp.Enabled
+	}
+
+	return nil
+}
+
+type Manifest struct {
+	// Defaults is the optional top-level defaults block. A mapping-shaped
+	// block's pulse_check, intervention, and codes sections are deep-merged
+	// into every monitor before validation, with monitor-level values
+	// winning and an explicitly empty mapping (codes: {}) clearing the
+	// inherited section. Any other shape (e.g. an anchored template) is
+	// ignored by the merge and only used through explicit references.
+	Defaults yaml.Node `yaml:"defaults,omitempty" json:"-"`
+	// Routes is the optional top-level routes block; see Routes.
+	Routes   Routes    `yaml:"routes,omitempty" json:"routes,omitempty"`
+	Monitors []Monitor `yaml:"monitors" json:"monitors"`
+}
+
+// Routes maps a notify group name to the code configs its members inherit.
+// Each group value has the same shape as a monitor's codes section. A
+// monitor opts in with notify_groups; the group's colors are merged into
+// the monitor's codes before validation, so shared webhook or slack config
+// lives in one place instead of being duplicated on every monitor.
+type Routes map[string]Codes
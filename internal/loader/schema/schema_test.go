@@ -0,0 +1,1928 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// Pulse YAML Unmarshaling Tests
+// =============================================================================
+
+// TestPulse_UnmarshalYAML_HTTP tests HTTP pulse config unmarshaling
+func TestPulse_UnmarshalYAML_HTTP(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+type: http
+interval: 30s
+timeout: 5s
+max_failures: 3
+unhealthy_threshold: 5
+healthy_threshold: 2
+config:
+  url: http://example.com/health
+  method: GET
+  retries: 3
+`
+	var pulse Pulse
+	if err := yaml.Unmarshal([]byte(yamlData), &pulse); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if pulse.Type != "http" {
+		t.Errorf("Type = %q, want %q", pulse.Type, "http")
+	}
+	if pulse.Interval != 30*time.Second {
+		t.Errorf("Interval = %v, want 30s", pulse.Interval)
+	}
+	if pulse.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", pulse.Timeout)
+	}
+	if pulse.MaxFailures != 3 {
+		t.Errorf("MaxFailures = %d, want 3", pulse.MaxFailures)
+	}
+	if pulse.UnhealthyThreshold != 5 {
+		t.Errorf("UnhealthyThreshold = %d, want 5", pulse.UnhealthyThreshold)
+	}
+	if pulse.HealthyThreshold != 2 {
+		t.Errorf("HealthyThreshold = %d, want 2", pulse.HealthyThreshold)
+	}
+
+	httpConfig, ok := pulse.Config.(*PulseHTTPConfig)
+	if !ok {
+		t.Fatalf("Config is not *PulseHTTPConfig, got %T", pulse.Config)
+	}
+	if httpConfig.Url != "http://example.com/health" {
+		t.Errorf("URL = %q, want %q", httpConfig.Url, "http://example.com/health")
+	}
+	if httpConfig.Method != "GET" {
+		t.Errorf("Method = %q, want %q", httpConfig.Method, "GET")
+	}
+	if httpConfig.Retries != 3 {
+		t.Errorf("Retries = %d, want 3", httpConfig.Retries)
+	}
+}
+
+// TestPulse_UnmarshalYAML_TCP tests TCP pulse config unmarshaling
+func TestPulse_UnmarshalYAML_TCP(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+type: tcp
+interval: 10s
+timeout: 2s
+config:
+  host: db.example.com
+  port: 5432
+  retries: 2
+`
+	var pulse Pulse
+	if err := yaml.Unmarshal([]byte(yamlData), &pulse); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if pulse.Type != "tcp" {
+		t.Errorf("Type = %q, want %q", pulse.Type, "tcp")
+	}
+
+	tcpConfig, ok := pulse.Config.(*PulseTCPConfig)
+	if !ok {
+		t.Fatalf("Config is not *PulseTCPConfig, got %T", pulse.Config)
+	}
+	if tcpConfig.Host != "db.example.com" {
+		t.Errorf("Host = %q, want %q", tcpConfig.Host, "db.example.com")
+	}
+	if tcpConfig.Port != 5432 {
+		t.Errorf("Port = %d, want 5432", tcpConfig.Port)
+	}
+	if tcpConfig.Retries != 2 {
+		t.Errorf("Retries = %d, want 2", tcpConfig.Retries)
+	}
+}
+
+// TestPulse_UnmarshalYAML_UDP tests UDP pulse config unmarshaling
+func TestPulse_UnmarshalYAML_UDP(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+type: udp
+interval: 30s
+timeout: 2s
+config:
+  host: statsd.local
+  port: 8125
+  payload: "hex:deadbeef"
+  expect: "pong"
+  read_timeout: 500ms
+  require_response: true
+  retries: 1
+`
+	var pulse Pulse
+	if err := yaml.Unmarshal([]byte(yamlData), &pulse); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if pulse.Type != "udp" {
+		t.Errorf("Type = %q, want %q", pulse.Type, "udp")
+	}
+
+	udpConfig, ok := pulse.Config.(*PulseUDPConfig)
+	if !ok {
+		t.Fatalf("Config is not *PulseUDPConfig, got %T", pulse.Config)
+	}
+	if udpConfig.Host != "statsd.local" {
+		t.Errorf("Host = %q, want %q", udpConfig.Host, "statsd.local")
+	}
+	if udpConfig.Port != 8125 {
+		t.Errorf("Port = %d, want 8125", udpConfig.Port)
+	}
+	if udpConfig.ReadTimeout != 500*time.Millisecond {
+		t.Errorf("ReadTimeout = %v, want 500ms", udpConfig.ReadTimeout)
+	}
+	if !udpConfig.RequireResponse {
+		t.Error("RequireResponse should be true")
+	}
+
+	probe, err := ParseUDPBytes(udpConfig.Payload)
+	if err != nil {
+		t.Fatalf("ParseUDPBytes failed: %v", err)
+	}
+	if want := []byte{0xde, 0xad, 0xbe, 0xef}; !bytes.Equal(probe, want) {
+		t.Errorf("probe = %x, want %x", probe, want)
+	}
+	if _, err := ParseUDPBytes("hex:zz"); err == nil {
+		t.Error("expected an error for malformed hex bytes")
+	}
+}
+
+// TestPulse_UnmarshalYAML_ICMP tests ICMP pulse config unmarshaling
+func TestPulse_UnmarshalYAML_ICMP(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+type: icmp
+interval: 60s
+timeout: 3s
+config:
+  host: gateway.local
+  count: 3
+  ignore_privilege: true
+  retries: 1
+`
+	var pulse Pulse
+	if err := yaml.Unmarshal([]byte(yamlData), &pulse); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if pulse.Type != "icmp" {
+		t.Errorf("Type = %q, want %q", pulse.Type, "icmp")
+	}
+
+	icmpConfig, ok := pulse.Config.(*PulseICMPConfig)
+	if !ok {
+		t.Fatalf("Config is not *PulseICMPConfig, got %T", pulse.Config)
+	}
+	if icmpConfig.Host != "gateway.local" {
+		t.Errorf("Host = %q, want %q", icmpConfig.Host, "gateway.local")
+	}
+	if icmpConfig.Count != 3 {
+		t.Errorf("Count = %d, want 3", icmpConfig.Count)
+	}
+	if !icmpConfig.Privilege {
+		t.Error("Privilege should be true")
+	}
+}
+
+// TestPulse_UnmarshalYAML_UnknownType tests error on unknown pulse type
+func TestPulse_UnmarshalYAML_UnknownType(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+type: unknown
+interval: 30s
+timeout: 5s
+config: {}
+`
+	var pulse Pulse
+	err := yaml.Unmarshal([]byte(yamlData), &pulse)
+	if err == nil {
+		t.Fatal("Expected error for unknown pulse type, got nil")
+	}
+}
+
+// TestPulse_UnmarshalYAML_BackwardsCompatibility tests max_failures fallback
+func TestPulse_UnmarshalYAML_BackwardsCompatibility(t *testing.T) {
+	t.Parallel()
+	// When unhealthy_threshold is not set, it should fallback to max_failures
+	yamlData := `
+type: http
+interval: 30s
+timeout: 5s
+max_failures: 3
+config:
+  url: http://example.com
+  method: GET
+`
+	var pulse Pulse
+	if err := yaml.Unmarshal([]byte(yamlData), &pulse); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if pulse.UnhealthyThreshold != 3 {
+		t.Errorf("UnhealthyThreshold = %d, want 3 (from max_failures fallback)", pulse.UnhealthyThreshold)
+	}
+}
+
+// =============================================================================
+// Intervention YAML Unmarshaling Tests
+// =============================================================================
+
+// TestIntervention_UnmarshalYAML_DockerRestart tests Docker restart intervention
+func TestIntervention_UnmarshalYAML_DockerRestart(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+retries: 3
+target:
+  type: restart
+  container: my-container
+  docker_host: unix:///var/run/docker.sock
+  timeout: 30s
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if intervention.Action != "docker" {
+		t.Errorf("Action = %q, want %q", intervention.Action, "docker")
+	}
+	if intervention.Retries != 3 {
+		t.Errorf("Retries = %d, want 3", intervention.Retries)
+	}
+
+	target, ok := intervention.Target.(*InterventionTargetDocker)
+	if !ok {
+		t.Fatalf("Target is not *InterventionTargetDocker, got %T", intervention.Target)
+	}
+	if target.Type != "restart" {
+		t.Errorf("Type = %q, want %q", target.Type, "restart")
+	}
+	if target.Container != "my-container" {
+		t.Errorf("Container = %q, want %q", target.Container, "my-container")
+	}
+	if target.DockerHost != "unix:///var/run/docker.sock" {
+		t.Errorf("DockerHost = %q, want %q", target.DockerHost, "unix:///var/run/docker.sock")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_Throttle tests cooldown and max_per_hour parsing
+func TestIntervention_UnmarshalYAML_Throttle(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+cooldown: 10m
+max_per_hour: 3
+target:
+  type: restart
+  container: my-container
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if intervention.Cooldown != 10*time.Minute {
+		t.Errorf("Cooldown = %v, want 10m", intervention.Cooldown)
+	}
+	if intervention.MaxPerHour != 3 {
+		t.Errorf("MaxPerHour = %d, want 3", intervention.MaxPerHour)
+	}
+}
+
+// TestIntervention_UnmarshalYAML_Exec tests exec intervention parsing
+func TestIntervention_UnmarshalYAML_Exec(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: exec
+retries: 1
+target:
+  command: systemctl
+  args: [restart, web.service]
+  working_dir: /srv/web
+  env:
+    SYSTEMD_COLORS: "0"
+  timeout: 10s
+  expected_exit_codes: [0, 1]
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if intervention.Action != "exec" {
+		t.Errorf("Action = %q, want %q", intervention.Action, "exec")
+	}
+	target, ok := intervention.Target.(*InterventionTargetExec)
+	if !ok {
+		t.Fatalf("Target is not *InterventionTargetExec, got %T", intervention.Target)
+	}
+	if target.Command != "systemctl" {
+		t.Errorf("Command = %q, want %q", target.Command, "systemctl")
+	}
+	if len(target.Args) != 2 || target.Args[1] != "web.service" {
+		t.Errorf("Args = %v, want [restart web.service]", target.Args)
+	}
+	if target.WorkingDir != "/srv/web" {
+		t.Errorf("WorkingDir = %q, want %q", target.WorkingDir, "/srv/web")
+	}
+	if target.Env["SYSTEMD_COLORS"] != "0" {
+		t.Errorf("Env = %v, want SYSTEMD_COLORS=0", target.Env)
+	}
+	if len(target.ExpectedExitCodes) != 2 {
+		t.Errorf("ExpectedExitCodes = %v, want [0 1]", target.ExpectedExitCodes)
+	}
+}
+
+// TestIntervention_UnmarshalYAML_Chain tests escalation chain parsing
+func TestIntervention_UnmarshalYAML_Chain(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+- action: docker
+  retries: 2
+  target:
+    type: restart
+    container: web
+- action: exec
+  retries: 1
+  after_failures: 2
+  target:
+    command: systemctl
+    args: [restart, web.service]
+- action: webhook
+  after_failures: 4
+  target:
+    url: https://runbook.internal/remediate
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(intervention.Steps) != 3 {
+		t.Fatalf("Steps = %d, want 3", len(intervention.Steps))
+	}
+	// Step 0 is mirrored into the legacy single-action fields.
+	if intervention.Action != "docker" || intervention.Retries != 2 {
+		t.Errorf("Action/Retries = %q/%d, want mirror of step 0", intervention.Action, intervention.Retries)
+	}
+	if _, ok := intervention.Target.(*InterventionTargetDocker); !ok {
+		t.Errorf("Target is %T, want step 0 docker target", intervention.Target)
+	}
+	if intervention.Steps[1].Action != "exec" || intervention.Steps[1].AfterFailures != 2 {
+		t.Errorf("step 1 = %+v, want exec after 2 failures", intervention.Steps[1])
+	}
+	if intervention.Steps[2].Action != "webhook" || intervention.Steps[2].AfterFailures != 4 {
+		t.Errorf("step 2 = %+v, want webhook after 4 failures", intervention.Steps[2])
+	}
+	if got := intervention.StepList(); len(got) != 3 {
+		t.Errorf("StepList() = %d steps, want 3", len(got))
+	}
+}
+
+// TestIntervention_UnmarshalYAML_ChainBadThresholds tests chain validation
+func TestIntervention_UnmarshalYAML_ChainBadThresholds(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+- action: docker
+  target:
+    container: web
+- action: exec
+  after_failures: 3
+  target:
+    command: systemctl
+- action: webhook
+  after_failures: 2
+  target:
+    url: https://runbook.internal/remediate
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err == nil {
+		t.Error("expected error for non-increasing after_failures")
+	}
+}
+
+// TestIntervention_StepList_SingleForm tests the legacy single-map form
+func TestIntervention_StepList_SingleForm(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+retries: 1
+target:
+  container: web
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	steps := intervention.StepList()
+	if len(steps) != 1 {
+		t.Fatalf("StepList() = %d steps, want 1", len(steps))
+	}
+	if steps[0].Action != "docker" || steps[0].AfterFailures != 0 {
+		t.Errorf("step = %+v, want docker with zero threshold", steps[0])
+	}
+}
+
+// TestIntervention_UnmarshalYAML_Webhook tests webhook intervention parsing
+func TestIntervention_UnmarshalYAML_Webhook(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: webhook
+retries: 2
+target:
+  url: https://runbook.internal/remediate
+  method: PUT
+  headers:
+    X-Team: sre
+  body_template: '{"monitor":"{{.Monitor}}","failures":{{.FailureCount}}}'
+  expected_status: [200, 202]
+  secret_env: RUNBOOK_SECRET
+  timeout: 10s
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if intervention.Action != "webhook" {
+		t.Errorf("Action = %q, want %q", intervention.Action, "webhook")
+	}
+	target, ok := intervention.Target.(*InterventionTargetWebhook)
+	if !ok {
+		t.Fatalf("Target is not *InterventionTargetWebhook, got %T", intervention.Target)
+	}
+	if target.URL != "https://runbook.internal/remediate" {
+		t.Errorf("URL = %q, want runbook URL", target.URL)
+	}
+	if target.Method != "PUT" {
+		t.Errorf("Method = %q, want %q", target.Method, "PUT")
+	}
+	if target.Headers["X-Team"] != "sre" {
+		t.Errorf("Headers = %v, want X-Team=sre", target.Headers)
+	}
+	if target.BodyTemplate != `{"monitor":"{{.Monitor}}","failures":{{.FailureCount}}}` {
+		t.Errorf("BodyTemplate = %q, want templated body", target.BodyTemplate)
+	}
+	if len(target.ExpectedStatus) != 2 || target.ExpectedStatus[1] != 202 {
+		t.Errorf("ExpectedStatus = %v, want [200 202]", target.ExpectedStatus)
+	}
+	if target.SecretEnv != "RUNBOOK_SECRET" {
+		t.Errorf("SecretEnv = %q, want %q", target.SecretEnv, "RUNBOOK_SECRET")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_WebhookMissingURL tests parser validation
+func TestIntervention_UnmarshalYAML_WebhookMissingURL(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: webhook
+target:
+  method: POST
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err == nil {
+		t.Error("expected error for webhook target without url")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_ExecMissingCommand tests parser validation
+func TestIntervention_UnmarshalYAML_ExecMissingCommand(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: exec
+target:
+  args: [restart]
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err == nil {
+		t.Error("expected error for exec target without command")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_DockerStop tests Docker stop intervention
+func TestIntervention_UnmarshalYAML_DockerStop(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+target:
+  type: stop
+  container: stop-container
+  timeout: 10s
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	target, ok := intervention.Target.(*InterventionTargetDocker)
+	if !ok {
+		t.Fatalf("Target is not *InterventionTargetDocker, got %T", intervention.Target)
+	}
+	if target.Type != "stop" {
+		t.Errorf("Type = %q, want %q", target.Type, "stop")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_DockerStart tests Docker start intervention
+func TestIntervention_UnmarshalYAML_DockerStart(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+target:
+  type: start
+  container: start-container
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	target := intervention.Target.(*InterventionTargetDocker)
+	if target.Type != "start" {
+		t.Errorf("Type = %q, want %q", target.Type, "start")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_DockerKill tests Docker kill intervention
+func TestIntervention_UnmarshalYAML_DockerKill(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+target:
+  type: kill
+  container: kill-container
+  signal: SIGTERM
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	target := intervention.Target.(*InterventionTargetDocker)
+	if target.Type != "kill" {
+		t.Errorf("Type = %q, want %q", target.Type, "kill")
+	}
+	if target.Signal != "SIGTERM" {
+		t.Errorf("Signal = %q, want %q", target.Signal, "SIGTERM")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_DockerPause tests Docker pause intervention
+func TestIntervention_UnmarshalYAML_DockerPause(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+target:
+  type: pause
+  container: pause-container
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	target := intervention.Target.(*InterventionTargetDocker)
+	if target.Type != "pause" {
+		t.Errorf("Type = %q, want %q", target.Type, "pause")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_DockerUnpause tests Docker unpause intervention
+func TestIntervention_UnmarshalYAML_DockerUnpause(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+target:
+  type: unpause
+  container: unpause-container
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	target := intervention.Target.(*InterventionTargetDocker)
+	if target.Type != "unpause" {
+		t.Errorf("Type = %q, want %q", target.Type, "unpause")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_DockerScale tests Docker scale intervention
+func TestIntervention_UnmarshalYAML_DockerScale(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+target:
+  type: scale
+  service: my-service
+  replicas: 5
+  timeout: 60s
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	target := intervention.Target.(*InterventionTargetDocker)
+	if target.Type != "scale" {
+		t.Errorf("Type = %q, want %q", target.Type, "scale")
+	}
+	if target.Service != "my-service" {
+		t.Errorf("Service = %q, want %q", target.Service, "my-service")
+	}
+	if target.Replicas != 5 {
+		t.Errorf("Replicas = %d, want 5", target.Replicas)
+	}
+}
+
+// TestIntervention_UnmarshalYAML_UnknownAction tests error on unknown action
+func TestIntervention_UnmarshalYAML_UnknownAction(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: kubernetes
+target:
+  type: restart
+  pod: my-pod
+`
+	var intervention Intervention
+	err := yaml.Unmarshal([]byte(yamlData), &intervention)
+	if err == nil {
+		t.Fatal("Expected error for unknown intervention action, got nil")
+	}
+}
+
+// =============================================================================
+// InterventionTargetDocker Tests
+// =============================================================================
+
+// TestInterventionTargetDocker_Copy tests Copy method
+func TestInterventionTargetDocker_Copy(t *testing.T) {
+	t.Parallel()
+	original := &InterventionTargetDocker{
+		Type:       "restart",
+		Container:  "test-container",
+		Service:    "test-service",
+		Replicas:   3,
+		Signal:     "SIGTERM",
+		DockerHost: "unix:///var/run/docker.sock",
+	}
+
+	copied := original.Copy()
+	copiedDocker, ok := copied.(*InterventionTargetDocker)
+	if !ok {
+		t.Fatalf("Copy returned wrong type: %T", copied)
+	}
+
+	// Modify original
+	original.Container = "modified-container"
+	original.Replicas = 10
+
+	// Copy should be unchanged
+	if copiedDocker.Container != "test-container" {
+		t.Errorf("Container was modified in copy, got %q", copiedDocker.Container)
+	}
+	if copiedDocker.Replicas != 3 {
+		t.Errorf("Replicas was modified in copy, got %d", copiedDocker.Replicas)
+	}
+}
+
+// TestInterventionTargetDocker_GetTargetType tests GetTargetType method
+func TestInterventionTargetDocker_GetTargetType(t *testing.T) {
+	t.Parallel()
+	target := &InterventionTargetDocker{Type: "restart"}
+	if target.GetTargetType() != "restart" {
+		t.Errorf("GetTargetType() = %q, want %q", target.GetTargetType(), "restart")
+	}
+}
+
+// =============================================================================
+// PulseConfig Copy Tests
+// =============================================================================
+
+// TestPulseHTTPConfig_Copy tests HTTP config Copy method
+func TestPulseHTTPConfig_Copy(t *testing.T) {
+	t.Parallel()
+	original := &PulseHTTPConfig{
+		Url:     "http://example.com",
+		Method:  "GET",
+		Retries: 3,
+	}
+
+	copied := original.Copy()
+	copiedHTTP, ok := copied.(*PulseHTTPConfig)
+	if !ok {
+		t.Fatalf("Copy returned wrong type: %T", copied)
+	}
+
+	original.Url = "http://modified.com"
+	if copiedHTTP.Url != "http://example.com" {
+		t.Errorf("URL was modified in copy, got %q", copiedHTTP.Url)
+	}
+}
+
+// TestPulseTCPConfig_Copy tests TCP config Copy method
+func TestPulseTCPConfig_Copy(t *testing.T) {
+	t.Parallel()
+	original := &PulseTCPConfig{
+		Host:    "db.example.com",
+		Port:    5432,
+		Retries: 2,
+	}
+
+	copied := original.Copy()
+	copiedTCP, ok := copied.(*PulseTCPConfig)
+	if !ok {
+		t.Fatalf("Copy returned wrong type: %T", copied)
+	}
+
+	original.Port = 3306
+	if copiedTCP.Port != 5432 {
+		t.Errorf("Port was modified in copy, got %d", copiedTCP.Port)
+	}
+}
+
+// TestPulseICMPConfig_Copy tests ICMP config Copy method
+func TestPulseICMPConfig_Copy(t *testing.T) {
+	t.Parallel()
+	original := &PulseICMPConfig{
+		Host:      "gateway.local",
+		Count:     3,
+		Privilege: true,
+		Retries:   1,
+	}
+
+	copied := original.Copy()
+	copiedICMP, ok := copied.(*PulseICMPConfig)
+	if !ok {
+		t.Fatalf("Copy returned wrong type: %T", copied)
+	}
+
+	original.Count = 5
+	if copiedICMP.Count != 3 {
+		t.Errorf("Count was modified in copy, got %d", copiedICMP.Count)
+	}
+}
+
+// =============================================================================
+// StringList Unmarshaling Tests
+// =============================================================================
+
+// TestStringList_UnmarshalYAML_SingleString tests single string
+func TestStringList_UnmarshalYAML_SingleString(t *testing.T) {
+	t.Parallel()
+	yamlData := `"production"`
+	var sl StringList
+	if err := yaml.Unmarshal([]byte(yamlData), &sl); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(sl) != 1 {
+		t.Fatalf("Expected 1 element, got %d", len(sl))
+	}
+	if sl[0] != "production" {
+		t.Errorf("Element = %q, want %q", sl[0], "production")
+	}
+}
+
+// TestStringList_UnmarshalYAML_MultipleStrings tests list of strings
+func TestStringList_UnmarshalYAML_MultipleStrings(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+- production
+- staging
+- development
+`
+	var sl StringList
+	if err := yaml.Unmarshal([]byte(yamlData), &sl); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(sl) != 3 {
+		t.Fatalf("Expected 3 elements, got %d", len(sl))
+	}
+	expected := []string{"production", "staging", "development"}
+	for i, exp := range expected {
+		if sl[i] != exp {
+			t.Errorf("Element[%d] = %q, want %q", i, sl[i], exp)
+		}
+	}
+}
+
+// =============================================================================
+// HeaderList Tests
+// =============================================================================
+
+// TestHeaderList_UnmarshalYAML_ListForm tests the "Name: value" string form:
+// order preserved, duplicates kept, split on the first colon only.
+func TestHeaderList_UnmarshalYAML_ListForm(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+- "X-Trace: on"
+- "Accept: application/json"
+- "Accept: text/plain"
+- "X-Window: 12:30-13:30"
+`
+	var hl HeaderList
+	if err := yaml.Unmarshal([]byte(yamlData), &hl); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	expected := HeaderList{
+		{Name: "X-Trace", Value: "on"},
+		{Name: "Accept", Value: "application/json"},
+		{Name: "Accept", Value: "text/plain"},
+		{Name: "X-Window", Value: "12:30-13:30"},
+	}
+	if len(hl) != len(expected) {
+		t.Fatalf("Expected %d headers, got %d", len(expected), len(hl))
+	}
+	for i, exp := range expected {
+		if hl[i] != exp {
+			t.Errorf("Header[%d] = %+v, want %+v", i, hl[i], exp)
+		}
+	}
+}
+
+// TestHeaderList_UnmarshalYAML_MapForm tests the mapping form: sorted by
+// name, values never split on colons.
+func TestHeaderList_UnmarshalYAML_MapForm(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+X-Window: 12:30-13:30
+Authorization: Bearer tok-123
+Accept: application/json
+`
+	var hl HeaderList
+	if err := yaml.Unmarshal([]byte(yamlData), &hl); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	expected := HeaderList{
+		{Name: "Accept", Value: "application/json"},
+		{Name: "Authorization", Value: "Bearer tok-123"},
+		{Name: "X-Window", Value: "12:30-13:30"},
+	}
+	if len(hl) != len(expected) {
+		t.Fatalf("Expected %d headers, got %d", len(expected), len(hl))
+	}
+	for i, exp := range expected {
+		if hl[i] != exp {
+			t.Errorf("Header[%d] = %+v, want %+v", i, hl[i], exp)
+		}
+	}
+}
+
+// TestHeaderList_UnmarshalYAML_EquivalentForms tests that a sorted string
+// list and the same headers written as a map normalize identically.
+func TestHeaderList_UnmarshalYAML_EquivalentForms(t *testing.T) {
+	t.Parallel()
+	listForm := `
+- "Accept: application/json"
+- "Authorization: Bearer tok-123"
+`
+	mapForm := `
+Authorization: Bearer tok-123
+Accept: application/json
+`
+	var fromList, fromMap HeaderList
+	if err := yaml.Unmarshal([]byte(listForm), &fromList); err != nil {
+		t.Fatalf("Unmarshal list form failed: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(mapForm), &fromMap); err != nil {
+		t.Fatalf("Unmarshal map form failed: %v", err)
+	}
+	if len(fromList) != len(fromMap) {
+		t.Fatalf("List form gave %d headers, map form %d", len(fromList), len(fromMap))
+	}
+	for i := range fromList {
+		if fromList[i] != fromMap[i] {
+			t.Errorf("Header[%d]: list form %+v, map form %+v", i, fromList[i], fromMap[i])
+		}
+	}
+}
+
+// TestHeaderList_UnmarshalYAML_SingleString tests the bare-string shorthand.
+func TestHeaderList_UnmarshalYAML_SingleString(t *testing.T) {
+	t.Parallel()
+	var hl HeaderList
+	if err := yaml.Unmarshal([]byte(`"Accept: text/plain"`), &hl); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(hl) != 1 || hl[0] != (Header{Name: "Accept", Value: "text/plain"}) {
+		t.Errorf("Headers = %+v, want single Accept header", hl)
+	}
+}
+
+// TestHeaderList_UnmarshalYAML_Malformed tests rejection of entries without
+// a name and of nested values in the map form.
+func TestHeaderList_UnmarshalYAML_Malformed(t *testing.T) {
+	t.Parallel()
+	for _, yamlData := range []string{
+		`["no-colon-here"]`,
+		`[": value-without-name"]`,
+		`"no-colon-here"`,
+		`{Accept: [a, b]}`,
+	} {
+		var hl HeaderList
+		if err := yaml.Unmarshal([]byte(yamlData), &hl); err == nil {
+			t.Errorf("Expected error for %s, got %+v", yamlData, hl)
+		}
+	}
+}
+
+// TestHeaderList_UnmarshalJSON tests that the JSON path accepts the same
+// two shapes as YAML.
+func TestHeaderList_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+	var fromMap HeaderList
+	if err := json.Unmarshal([]byte(`{"X-Window": "12:30-13:30", "Accept": "application/json"}`), &fromMap); err != nil {
+		t.Fatalf("Unmarshal map failed: %v", err)
+	}
+	expected := HeaderList{
+		{Name: "Accept", Value: "application/json"},
+		{Name: "X-Window", Value: "12:30-13:30"},
+	}
+	if len(fromMap) != 2 || fromMap[0] != expected[0] || fromMap[1] != expected[1] {
+		t.Errorf("Headers = %+v, want %+v", fromMap, expected)
+	}
+
+	var fromList HeaderList
+	if err := json.Unmarshal([]byte(`["Accept: application/json", "Accept: text/plain"]`), &fromList); err != nil {
+		t.Fatalf("Unmarshal list failed: %v", err)
+	}
+	if len(fromList) != 2 || fromList[1] != (Header{Name: "Accept", Value: "text/plain"}) {
+		t.Errorf("Headers = %+v, want duplicates kept in order", fromList)
+	}
+
+	if err := json.Unmarshal([]byte(`["no-colon-here"]`), &fromList); err == nil {
+		t.Error("Expected error for malformed list entry")
+	}
+	if err := json.Unmarshal([]byte(`42`), &fromList); err == nil {
+		t.Error("Expected error for non-list, non-map headers")
+	}
+}
+
+// =============================================================================
+// Code Notification Tests
+// =============================================================================
+
+// TestCodes_UnmarshalYAML_Log tests log notification config
+func TestCodes_UnmarshalYAML_Log(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+red:
+  notify: log
+  dispatch: true
+  config:
+    file: /var/log/alerts.log
+`
+	var codes Codes
+	if err := yaml.Unmarshal([]byte(yamlData), &codes); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	redCode, ok := codes["red"]
+	if !ok {
+		t.Fatal("Missing 'red' code config")
+	}
+
+	if redCode.Notify != "log" {
+		t.Errorf("Notify = %q, want %q", redCode.Notify, "log")
+	}
+	if !redCode.Dispatch {
+		t.Error("Dispatch should be true")
+	}
+
+	logConfig, ok := redCode.Config.(*CodeNotificationLog)
+	if !ok {
+		t.Fatalf("Config is not *CodeNotificationLog, got %T", redCode.Config)
+	}
+	if logConfig.File != "/var/log/alerts.log" {
+		t.Errorf("File = %q, want %q", logConfig.File, "/var/log/alerts.log")
+	}
+}
+
+// TestCodes_UnmarshalYAML_Slack tests slack notification config
+func TestCodes_UnmarshalYAML_Slack(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+yellow:
+  notify: slack
+  config:
+    hook: https://hooks.slack.com/services/xxx/yyy/zzz
+`
+	var codes Codes
+	if err := yaml.Unmarshal([]byte(yamlData), &codes); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	yellowCode, ok := codes["yellow"]
+	if !ok {
+		t.Fatal("Missing 'yellow' code config")
+	}
+
+	slackConfig, ok := yellowCode.Config.(*CodeNotificationSlack)
+	if !ok {
+		t.Fatalf("Config is not *CodeNotificationSlack, got %T", yellowCode.Config)
+	}
+	if slackConfig.WebHook != "https://hooks.slack.com/services/xxx/yyy/zzz" {
+		t.Errorf("WebHook = %q", slackConfig.WebHook)
+	}
+}
+
+// TestCodes_UnmarshalYAML_PagerDuty tests pagerduty notification config
+func TestCodes_UnmarshalYAML_PagerDuty(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+red:
+  notify: pagerduty
+  config:
+    url: https://events.pagerduty.com/v2/enqueue
+`
+	var codes Codes
+	if err := yaml.Unmarshal([]byte(yamlData), &codes); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	redCode := codes["red"]
+	pdConfig, ok := redCode.Config.(*CodeNotificationPagerDuty)
+	if !ok {
+		t.Fatalf("Config is not *CodeNotificationPagerDuty, got %T", redCode.Config)
+	}
+	if pdConfig.URL != "https://events.pagerduty.com/v2/enqueue" {
+		t.Errorf("URL = %q", pdConfig.URL)
+	}
+}
+
+// TestCodes_UnmarshalYAML_DefaultDispatch tests dispatch defaults to true
+func TestCodes_UnmarshalYAML_DefaultDispatch(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+green:
+  notify: log
+  config:
+    file: /var/log/recovery.log
+`
+	var codes Codes
+	if err := yaml.Unmarshal([]byte(yamlData), &codes); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	greenCode := codes["green"]
+	if !greenCode.Dispatch {
+		t.Error("Dispatch should default to true when omitted")
+	}
+}
+
+// TestCodes_UnmarshalYAML_UnknownNotifyType tests error on unknown type
+func TestCodes_UnmarshalYAML_UnknownNotifyType(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+red:
+  notify: sms
+  config:
+    phone: +1234567890
+`
+	var codes Codes
+	err := yaml.Unmarshal([]byte(yamlData), &codes)
+	if err == nil {
+		t.Fatal("Expected error for unknown notify type, got nil")
+	}
+}
+
+// =============================================================================
+// Additional Coverage Tests - Copy Methods
+// =============================================================================
+
+// TestCodeNotificationLog_Copy tests log notification Copy method
+func TestCodeNotificationLog_Copy(t *testing.T) {
+	t.Parallel()
+	original := &CodeNotificationLog{File: "/var/log/test.log"}
+	copied := original.Copy()
+
+	copiedLog, ok := copied.(*CodeNotificationLog)
+	if !ok {
+		t.Fatalf("Copy returned wrong type: %T", copied)
+	}
+
+	// Modify original
+	original.File = "/var/log/modified.log"
+
+	// Copy should be unchanged
+	if copiedLog.File != "/var/log/test.log" {
+		t.Errorf("File was modified in copy, got %q", copiedLog.File)
+	}
+}
+
+// TestCodeNotificationPagerDuty_Copy tests PagerDuty notification Copy method
+func TestCodeNotificationPagerDuty_Copy(t *testing.T) {
+	t.Parallel()
+	original := &CodeNotificationPagerDuty{URL: "https://events.pagerduty.com/test"}
+	copied := original.Copy()
+
+	copiedPD, ok := copied.(*CodeNotificationPagerDuty)
+	if !ok {
+		t.Fatalf("Copy returned wrong type: %T", copied)
+	}
+
+	original.URL = "https://modified.com"
+	if copiedPD.URL != "https://events.pagerduty.com/test" {
+		t.Errorf("URL was modified in copy, got %q", copiedPD.URL)
+	}
+}
+
+// TestCodeNotificationSlack_Copy tests Slack notification Copy method
+func TestCodeNotificationSlack_Copy(t *testing.T) {
+	t.Parallel()
+	original := &CodeNotificationSlack{WebHook: "https://hooks.slack.com/test"}
+	copied := original.Copy()
+
+	copiedSlack, ok := copied.(*CodeNotificationSlack)
+	if !ok {
+		t.Fatalf("Copy returned wrong type: %T", copied)
+	}
+
+	original.WebHook = "https://modified.com"
+	if copiedSlack.WebHook != "https://hooks.slack.com/test" {
+		t.Errorf("WebHook was modified in copy, got %q", copiedSlack.WebHook)
+	}
+}
+
+// TestIntervention_Copy tests full Intervention Copy method
+func TestIntervention_Copy(t *testing.T) {
+	t.Parallel()
+	original := &InterventionTargetDocker{
+		Type:       "restart",
+		Container:  "test-container",
+		Service:    "test-service",
+		Replicas:   5,
+		Signal:     "SIGTERM",
+		DockerHost: "unix:///var/run/docker.sock",
+		Timeout:    30 * time.Second,
+	}
+
+	copied := original.Copy()
+	copiedDocker, ok := copied.(*InterventionTargetDocker)
+	if !ok {
+		t.Fatalf("Copy returned wrong type: %T", copied)
+	}
+
+	// Verify all fields are copied
+	if copiedDocker.Type != "restart" {
+		t.Errorf("Type = %q, want %q", copiedDocker.Type, "restart")
+	}
+	if copiedDocker.Container != "test-container" {
+		t.Errorf("Container = %q, want %q", copiedDocker.Container, "test-container")
+	}
+	if copiedDocker.Service != "test-service" {
+		t.Errorf("Service = %q, want %q", copiedDocker.Service, "test-service")
+	}
+	if copiedDocker.Replicas != 5 {
+		t.Errorf("Replicas = %d, want 5", copiedDocker.Replicas)
+	}
+	if copiedDocker.Signal != "SIGTERM" {
+		t.Errorf("Signal = %q, want %q", copiedDocker.Signal, "SIGTERM")
+	}
+	if copiedDocker.DockerHost != "unix:///var/run/docker.sock" {
+		t.Errorf("DockerHost = %q", copiedDocker.DockerHost)
+	}
+
+	// Modify original and verify copy is independent
+	original.Container = "modified"
+	original.Replicas = 10
+	if copiedDocker.Container != "test-container" {
+		t.Error("Container was modified in copy")
+	}
+	if copiedDocker.Replicas != 5 {
+		t.Error("Replicas was modified in copy")
+	}
+}
+
+// =============================================================================
+// Additional Coverage Tests - Edge Cases
+// =============================================================================
+
+// TestPulse_UnmarshalYAML_MinimalHTTP tests minimal HTTP config
+func TestPulse_UnmarshalYAML_MinimalHTTP(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+type: http
+interval: 1s
+timeout: 1s
+config:
+  url: http://localhost
+  method: GET
+`
+	var pulse Pulse
+	if err := yaml.Unmarshal([]byte(yamlData), &pulse); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if pulse.Type != "http" {
+		t.Errorf("Type = %q, want %q", pulse.Type, "http")
+	}
+	if pulse.Interval != 1*time.Second {
+		t.Errorf("Interval = %v, want 1s", pulse.Interval)
+	}
+}
+
+// TestPulse_UnmarshalYAML_WithGroups tests pulse with groups
+func TestPulse_UnmarshalYAML_WithGroups(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+type: http
+interval: 30s
+timeout: 5s
+groups:
+  - production
+  - critical
+config:
+  url: http://example.com
+  method: GET
+`
+	var pulse Pulse
+	if err := yaml.Unmarshal([]byte(yamlData), &pulse); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(pulse.Groups) != 2 {
+		t.Fatalf("Groups length = %d, want 2", len(pulse.Groups))
+	}
+	if pulse.Groups[0] != "production" {
+		t.Errorf("Groups[0] = %q, want %q", pulse.Groups[0], "production")
+	}
+	if pulse.Groups[1] != "critical" {
+		t.Errorf("Groups[1] = %q, want %q", pulse.Groups[1], "critical")
+	}
+}
+
+// TestStringList_UnmarshalYAML_Empty tests empty string list
+func TestStringList_UnmarshalYAML_Empty(t *testing.T) {
+	t.Parallel()
+	yamlData := `[]`
+	var sl StringList
+	if err := yaml.Unmarshal([]byte(yamlData), &sl); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(sl) != 0 {
+		t.Errorf("Expected empty list, got %d elements", len(sl))
+	}
+}
+
+// TestCodes_UnmarshalYAML_MultipleColors tests multiple code colors
+func TestCodes_UnmarshalYAML_MultipleColors(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+red:
+  notify: log
+  dispatch: true
+  config:
+    file: /var/log/red.log
+yellow:
+  notify: slack
+  dispatch: false
+  config:
+    hook: https://hooks.slack.com/yellow
+green:
+  notify: pagerduty
+  config:
+    url: https://events.pagerduty.com/green
+`
+	var codes Codes
+	if err := yaml.Unmarshal([]byte(yamlData), &codes); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(codes) != 3 {
+		t.Fatalf("Expected 3 codes, got %d", len(codes))
+	}
+
+	// Check red
+	redCode, ok := codes["red"]
+	if !ok {
+		t.Fatal("Missing 'red' code")
+	}
+	if !redCode.Dispatch {
+		t.Error("Red dispatch should be true")
+	}
+
+	// Check yellow
+	yellowCode, ok := codes["yellow"]
+	if !ok {
+		t.Fatal("Missing 'yellow' code")
+	}
+	if yellowCode.Dispatch {
+		t.Error("Yellow dispatch should be false")
+	}
+
+	// Check green (dispatch defaults to true)
+	greenCode, ok := codes["green"]
+	if !ok {
+		t.Fatal("Missing 'green' code")
+	}
+	if !greenCode.Dispatch {
+		t.Error("Green dispatch should default to true")
+	}
+}
+
+// TestIntervention_UnmarshalYAML_EmptyType tests empty type defaults to restart
+func TestIntervention_UnmarshalYAML_EmptyType(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+retries: 2
+target:
+  container: default-container
+  timeout: 15s
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	target, ok := intervention.Target.(*InterventionTargetDocker)
+	if !ok {
+		t.Fatalf("Target is not *InterventionTargetDocker, got %T", intervention.Target)
+	}
+
+	// Empty type should be handled by the factory (defaults to restart)
+	if target.Container != "default-container" {
+		t.Errorf("Container = %q, want %q", target.Container, "default-container")
+	}
+}
+
+// =============================================================================
+// Monitor and DurationSeconds Tests
+// =============================================================================
+
+// TestMonitor_UnmarshalYAML_Complete tests full monitor unmarshaling
+func TestMonitor_UnmarshalYAML_Complete(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+name: api-server
+enabled: true
+pulse_check:
+  type: http
+  interval: 30s
+  timeout: 5s
+  config:
+    url: http://localhost:8080/health
+    method: GET
+intervention:
+  action: docker
+  retries: 3
+  target:
+    type: restart
+    container: api-container
+    timeout: 30s
+codes:
+  red:
+    notify: log
+    config:
+      file: /var/log/alerts.log
+`
+	var monitor Monitor
+	if err := yaml.Unmarshal([]byte(yamlData), &monitor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if monitor.Name != "api-server" {
+		t.Errorf("Name = %q, want %q", monitor.Name, "api-server")
+	}
+	if !monitor.Enabled {
+		t.Error("Enabled should be true")
+	}
+	if monitor.Pulse.Type != "http" {
+		t.Errorf("Pulse.Type = %q, want %q", monitor.Pulse.Type, "http")
+	}
+	if monitor.Intervention.Action != "docker" {
+		t.Errorf("Intervention.Action = %q, want %q", monitor.Intervention.Action, "docker")
+	}
+	if _, ok := monitor.Codes["red"]; !ok {
+		t.Error("Missing 'red' code in codes")
+	}
+}
+
+// TestMonitor_UnmarshalYAML_EnabledDefault tests enabled defaults to true
+func TestMonitor_UnmarshalYAML_EnabledDefault(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+name: default-enabled
+pulse_check:
+  type: http
+  interval: 30s
+  timeout: 5s
+  config:
+    url: http://localhost
+    method: GET
+`
+	var monitor Monitor
+	if err := yaml.Unmarshal([]byte(yamlData), &monitor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if !monitor.Enabled {
+		t.Error("Enabled should default to true when not specified")
+	}
+}
+
+// TestMonitor_UnmarshalYAML_ExplicitDisabled tests enabled: false
+func TestMonitor_UnmarshalYAML_ExplicitDisabled(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+name: disabled-monitor
+enabled: false
+pulse_check:
+  type: http
+  interval: 30s
+  timeout: 5s
+  config:
+    url: http://localhost
+    method: GET
+`
+	var monitor Monitor
+	if err := yaml.Unmarshal([]byte(yamlData), &monitor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if monitor.Enabled {
+		t.Error("Enabled should be false when explicitly set")
+	}
+}
+
+// TestMonitor_UnmarshalYAML_Maintenance tests the maintenance block
+func TestMonitor_UnmarshalYAML_Maintenance(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+name: billing-db
+pulse_check:
+  type: http
+  interval: 30s
+  timeout: 5s
+  config:
+    url: http://localhost
+    method: GET
+maintenance:
+  - start: "02:00"
+    end: "04:00"
+    recurrence: daily
+  - start: "Sat 22:00"
+    end: "Sun 02:00"
+    recurrence: weekly
+    timezone: Europe/Berlin
+  - start: "2026-09-12T20:00:00Z"
+    end: "2026-09-12T23:00:00Z"
+`
+	var monitor Monitor
+	if err := yaml.Unmarshal([]byte(yamlData), &monitor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(monitor.Maintenance) != 3 {
+		t.Fatalf("len(Maintenance) = %d, want 3", len(monitor.Maintenance))
+	}
+	if monitor.Maintenance[0].Recurrence != "daily" {
+		t.Errorf("window 0 recurrence = %q, want %q", monitor.Maintenance[0].Recurrence, "daily")
+	}
+	if monitor.Maintenance[1].Timezone != "Europe/Berlin" {
+		t.Errorf("window 1 timezone = %q, want %q", monitor.Maintenance[1].Timezone, "Europe/Berlin")
+	}
+	if monitor.Maintenance[2].Recurrence != "" {
+		t.Errorf("window 2 recurrence = %q, want one-off", monitor.Maintenance[2].Recurrence)
+	}
+}
+
+// TestMonitor_UnmarshalYAML_MaintenanceInvalid tests rejection of bad windows
+func TestMonitor_UnmarshalYAML_MaintenanceInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		window string
+	}{
+		{"missing end", `{start: "02:00", recurrence: daily}`},
+		{"bad recurrence", `{start: "02:00", end: "04:00", recurrence: hourly}`},
+		{"bad timezone", `{start: "02:00", end: "04:00", recurrence: daily, timezone: Mars/Olympus}`},
+	}
+	for _, tc := range tests {
+		yamlData := `
+name: billing-db
+pulse_check:
+  type: http
+  interval: 30s
+  timeout: 5s
+  config:
+    url: http://localhost
+    method: GET
+maintenance:
+  - ` + tc.window + `
+`
+		var monitor Monitor
+		if err := yaml.Unmarshal([]byte(yamlData), &monitor); err == nil {
+			t.Errorf("%s: expected unmarshal error", tc.name)
+		}
+	}
+}
+
+// TestDurationSeconds_UnmarshalYAML tests duration parsing
+func TestDurationSeconds_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		yaml    string
+		want    int
+		wantErr bool
+	}{
+		{name: "seconds", yaml: `"30s"`, want: 30},
+		{name: "minutes", yaml: `"2m"`, want: 120},
+		{name: "hours", yaml: `"1h"`, want: 3600},
+		{name: "milliseconds", yaml: `"500ms"`, want: 0}, // rounds to 0 seconds
+		{name: "complex", yaml: `"1h30m"`, want: 5400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d DurationSeconds
+			err := yaml.Unmarshal([]byte(tt.yaml), &d)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && int(d) != tt.want {
+				t.Errorf("DurationSeconds = %d, want %d", int(d), tt.want)
+			}
+		})
+	}
+}
+
+// TestDurationSeconds_UnmarshalYAML_Invalid tests invalid duration
+func TestDurationSeconds_UnmarshalYAML_Invalid(t *testing.T) {
+	t.Parallel()
+	yamlData := `"invalid"`
+	var d DurationSeconds
+	err := yaml.Unmarshal([]byte(yamlData), &d)
+	if err == nil {
+		t.Fatal("Expected error for invalid duration, got nil")
+	}
+}
+
+// TestManifest_UnmarshalYAML tests manifest with multiple monitors
+func TestManifest_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+monitors:
+  - name: monitor-1
+    pulse_check:
+      type: http
+      interval: 30s
+      timeout: 5s
+      config:
+        url: http://localhost:8080
+        method: GET
+  - name: monitor-2
+    pulse_check:
+      type: tcp
+      interval: 10s
+      timeout: 2s
+      config:
+        host: localhost
+        port: 5432
+`
+	var manifest Manifest
+	if err := yaml.Unmarshal([]byte(yamlData), &manifest); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(manifest.Monitors) != 2 {
+		t.Fatalf("Expected 2 monitors, got %d", len(manifest.Monitors))
+	}
+	if manifest.Monitors[0].Name != "monitor-1" {
+		t.Errorf("Monitors[0].Name = %q, want %q", manifest.Monitors[0].Name, "monitor-1")
+	}
+	if manifest.Monitors[1].Name != "monitor-2" {
+		t.Errorf("Monitors[1].Name = %q, want %q", manifest.Monitors[1].Name, "monitor-2")
+	}
+}
+
+// =============================================================================
+// StatusRanges Tests
+// =============================================================================
+
+// TestParseStatusRanges tests expected_status parsing: singles, ranges, and
+// malformed specs.
+func TestParseStatusRanges(t *testing.T) {
+	t.Parallel()
+
+	ranges, err := ParseStatusRanges("200-204, 301,401")
+	if err != nil {
+		t.Fatalf("ParseStatusRanges failed: %v", err)
+	}
+	want := StatusRanges{{200, 204}, {301, 301}, {401, 401}}
+	if len(ranges) != len(want) {
+		t.Fatalf("Expected %d ranges, got %d", len(want), len(ranges))
+	}
+	for i, span := range want {
+		if ranges[i] != span {
+			t.Errorf("ranges[%d] = %v, want %v", i, ranges[i], span)
+		}
+	}
+
+	if ranges, err := ParseStatusRanges(""); err != nil || ranges != nil {
+		t.Errorf("Empty spec = %v, %v, want nil, nil", ranges, err)
+	}
+
+	for _, bad := range []string{"abc", "300-200", "99", "200-700", "200--204"} {
+		if _, err := ParseStatusRanges(bad); err == nil {
+			t.Errorf("Expected error for spec %q", bad)
+		}
+	}
+}
+
+// TestStatusRanges_Match tests matching, including the 2xx default for an
+// empty set.
+func TestStatusRanges_Match(t *testing.T) {
+	t.Parallel()
+
+	var def StatusRanges
+	if !def.Match(204) || def.Match(301) || def.Match(199) {
+		t.Error("Empty StatusRanges must match exactly 2xx")
+	}
+
+	ranges, _ := ParseStatusRanges("200-204,401")
+	for code, want := range map[int]bool{200: true, 204: true, 205: false, 401: true, 500: false} {
+		if got := ranges.Match(code); got != want {
+			t.Errorf("Match(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+// TestPulse_UnmarshalYAML_UnhealthyAttemptThreshold tests the optional
+// attempt-volume threshold field
+func TestPulse_UnmarshalYAML_UnhealthyAttemptThreshold(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+type: http
+interval: 30s
+timeout: 5s
+unhealthy_threshold: 5
+unhealthy_attempt_threshold: 8
+config:
+  url: http://example.com
+  method: GET
+  retries: 3
+`
+	var pulse Pulse
+	if err := yaml.Unmarshal([]byte(yamlData), &pulse); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if pulse.UnhealthyAttemptThreshold != 8 {
+		t.Errorf("UnhealthyAttemptThreshold = %d, want 8", pulse.UnhealthyAttemptThreshold)
+	}
+
+	// Unset leaves it disabled.
+	var defaulted Pulse
+	if err := yaml.Unmarshal([]byte("type: http\ninterval: 30s\ntimeout: 5s\nconfig:\n  url: http://example.com\n  method: GET\n"), &defaulted); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if defaulted.UnhealthyAttemptThreshold != 0 {
+		t.Errorf("UnhealthyAttemptThreshold = %d, want 0", defaulted.UnhealthyAttemptThreshold)
+	}
+}
+
+// TestMonitor_UnmarshalYAML_DependsOn tests the depends_on monitor name list
+func TestMonitor_UnmarshalYAML_DependsOn(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+name: web-1
+depends_on:
+  - core-router
+  - edge-lb
+pulse_check:
+  type: http
+  interval: 30s
+  timeout: 5s
+  config:
+    url: http://localhost
+    method: GET
+`
+	var monitor Monitor
+	if err := yaml.Unmarshal([]byte(yamlData), &monitor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if len(monitor.DependsOn) != 2 || monitor.DependsOn[0] != "core-router" || monitor.DependsOn[1] != "edge-lb" {
+		t.Errorf("DependsOn = %v, want [core-router edge-lb]", monitor.DependsOn)
+	}
+}
+
+// TestIntervention_UnmarshalYAML_DryRun tests the dry_run flag in both the
+// single-map and chain forms; the chain stamps it onto every step.
+func TestIntervention_UnmarshalYAML_DryRun(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+action: docker
+dry_run: true
+target:
+  type: restart
+  container: my-container
+`
+	var intervention Intervention
+	if err := yaml.Unmarshal([]byte(yamlData), &intervention); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !intervention.DryRun {
+		t.Error("DryRun = false, want true")
+	}
+	if steps := intervention.StepList(); len(steps) != 1 || !steps[0].DryRun {
+		t.Errorf("StepList() = %+v, want one dry-run step", steps)
+	}
+
+	chainData := `
+- action: docker
+  dry_run: true
+  target:
+    type: restart
+    container: web
+- action: exec
+  after_failures: 2
+  target:
+    command: systemctl
+    args: [restart, web.service]
+`
+	var chain Intervention
+	if err := yaml.Unmarshal([]byte(chainData), &chain); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !chain.DryRun {
+		t.Error("chain DryRun = false, want true (read from step 0)")
+	}
+	for n, step := range chain.Steps {
+		if !step.DryRun {
+			t.Errorf("step %d DryRun = false, want true (chain-level flag)", n)
+		}
+	}
+
+	// Unset stays off.
+	var off Intervention
+	if err := yaml.Unmarshal([]byte("action: docker\ntarget:\n  type: restart\n  container: web\n"), &off); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if off.DryRun {
+		t.Error("DryRun = true without dry_run in the manifest")
+	}
+}
+
+// TestMonitor_UnmarshalYAML_NotifyGroups tests the notify_groups list,
+// including the single-string shorthand StringList accepts.
+func TestMonitor_UnmarshalYAML_NotifyGroups(t *testing.T) {
+	t.Parallel()
+	yamlData := `
+name: checkout
+notify_groups:
+  - payments
+  - oncall-eu
+pulse_check:
+  type: http
+  interval: 30s
+  timeout: 5s
+  config:
+    url: http://localhost
+    method: GET
+`
+	var monitor Monitor
+	if err := yaml.Unmarshal([]byte(yamlData), &monitor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(monitor.NotifyGroups) != 2 || monitor.NotifyGroups[0] != "payments" || monitor.NotifyGroups[1] != "oncall-eu" {
+		t.Errorf("NotifyGroups = %v, want [payments oncall-eu]", monitor.NotifyGroups)
+	}
+
+	var single Monitor
+	if err := yaml.Unmarshal([]byte("name: checkout\nnotify_groups: payments\n"), &single); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(single.NotifyGroups) != 1 || single.NotifyGroups[0] != "payments" {
+		t.Errorf("NotifyGroups = %v, want [payments]", single.NotifyGroups)
+	}
+}
+
+// TestMonitor_ResolveNotifyGroups_Unknown tests that a reference to a group
+// the routes block does not define is an error.
+func TestMonitor_ResolveNotifyGroups_Unknown(t *testing.T) {
+	t.Parallel()
+	monitor := Monitor{Name: "checkout", NotifyGroups: StringList{"payments"}}
+	err := monitor.ResolveNotifyGroups(Routes{})
+	if err == nil || err.Error() != `unknown notify group "payments"` {
+		t.Errorf("ResolveNotifyGroups() = %v, want unknown group error", err)
+	}
+}
+
+func TestMonitor_UnmarshalYAML_SLO(t *testing.T) {
+	yamlData := `
+name: api-gw
+pulse_check:
+  type: http
+  url: http://localhost:8080
+  interval: 30s
+slo:
+  availability: 99.9
+  window: 720h
+  latency:
+    threshold: 500ms
+    target: 99
+codes: {}
+`
+	var monitor Monitor
+	if err := yaml.Unmarshal([]byte(yamlData), &monitor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if monitor.SLO == nil {
+		t.Fatal("SLO block not parsed")
+	}
+	if monitor.SLO.Availability != 99.9 || monitor.SLO.Window != "720h" {
+		t.Errorf("SLO = %+v, want availability 99.9 over 720h", monitor.SLO)
+	}
+	if monitor.SLO.Latency == nil || monitor.SLO.Latency.Threshold != "500ms" || monitor.SLO.Latency.Target != 99 {
+		t.Errorf("SLO latency = %+v, want 99%% under 500ms", monitor.SLO.Latency)
+	}
+}
+
+func TestMonitor_UnmarshalYAML_SLOInvalid(t *testing.T) {
+	cases := map[string]string{
+		"availability out of range": "availability: 100\n  window: 720h",
+		"window too short":          "availability: 99.9\n  window: 10m",
+		"unparsable window":         "availability: 99.9\n  window: soon",
+	}
+	for name, slo := range cases {
+		yamlData := "name: api-gw\npulse_check:\n  type: http\n  url: http://localhost:8080\n  interval: 30s\nslo:\n  " + slo + "\ncodes: {}\n"
+		var monitor Monitor
+		if err := yaml.Unmarshal([]byte(yamlData), &monitor); err == nil {
+			t.Errorf("%s: Unmarshal accepted an invalid slo block", name)
+		}
+	}
+}
+
+func TestPulse_UnmarshalYAML_Schedule(t *testing.T) {
+	yamlData := `
+name: partner-api
+pulse_check:
+  type: http
+  url: http://localhost:8080
+  interval: 30s
+  schedule:
+    active_hours: 08:00-20:00
+    days: mon-fri
+    timezone: Europe/Berlin
+codes: {}
+`
+	var monitor Monitor
+	if err := yaml.Unmarshal([]byte(yamlData), &monitor); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	sched := monitor.Pulse.Schedule
+	if sched == nil {
+		t.Fatal("schedule block not parsed")
+	}
+	if sched.ActiveHours != "08:00-20:00" || sched.Days != "mon-fri" || sched.Timezone != "Europe/Berlin" {
+		t.Errorf("schedule = %+v, want 08:00-20:00 mon-fri in Europe/Berlin", sched)
+	}
+	start, end, err := sched.ActiveWindow()
+	if err != nil {
+		t.Fatalf("ActiveWindow failed: %v", err)
+	}
+	if start != 8*time.Hour || end != 20*time.Hour {
+		t.Errorf("window = %v-%v, want 8h-20h", start, end)
+	}
+	mask, err := sched.DayMask()
+	if err != nil {
+		t.Fatalf("DayMask failed: %v", err)
+	}
+	if mask != 0b0111110 {
+		t.Errorf("day mask = %07b, want mon-fri", mask)
+	}
+}
+
+func TestPulse_UnmarshalYAML_ScheduleInvalid(t *testing.T) {
+	cases := map[string]string{
+		"missing end":      "active_hours: 08:00",
+		"bad hour":         "active_hours: 25:00-26:00",
+		"equal start end":  "active_hours: 08:00-08:00",
+		"unknown weekday":  "active_hours: 08:00-20:00\n    days: mon-funday",
+		"unknown timezone": "active_hours: 08:00-20:00\n    timezone: Mars/Olympus",
+	}
+	for name, sched := range cases {
+		yamlData := "name: partner-api\npulse_check:\n  type: http\n  url: http://localhost:8080\n  interval: 30s\n  schedule:\n    " + sched + "\ncodes: {}\n"
+		var monitor Monitor
+		if err := yaml.Unmarshal([]byte(yamlData), &monitor); err == nil {
+			t.Errorf("%s: Unmarshal accepted an invalid schedule block", name)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package loader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"gopkg.in/yaml.v3"
+)
+
+// highHealthyThreshold is the healthy_threshold above which a monitor draws
+// a semantic finding. Recovery needs that many consecutive successes, so
+// triple-digit values keep a recovered monitor out of green for hours.
+const highHealthyThreshold = 100
+
+// semanticIssue is one cross-field contradiction found in a monitor. The
+// field path names the offending field with the manifest's YAML keys so the
+// lint path can report the field's own line when it has the parsed node.
+type semanticIssue struct {
+	fieldPath []string
+	message   string
+}
+
+// semanticRule is one entry of the semantic validation table. Rules run
+// after the per-field validator has passed, so they can assume individual
+// values are well-formed and only look for cross-field contradictions —
+// configurations that parse cleanly but can never behave as intended.
+type semanticRule struct {
+	name  string
+	check func(m *schema.Monitor) []semanticIssue
+}
+
+// semanticRules is the contradiction table shared by the load pipeline and
+// the lint path. Under PipelineConfig.StrictValidation the issues reject the
+// monitor like validation errors; otherwise they surface as load or lint
+// warnings. Add new rules here — each check returns nil when the monitor is
+// fine.
+var semanticRules = []semanticRule{
+	{
+		// A huge healthy_threshold keeps a recovered monitor out of green
+		// for healthy_threshold consecutive checks; nobody plausibly wants
+		// hundreds of confirmations. Negative values already fail the
+		// per-field validator.
+		name: "healthy-threshold",
+		check: func(m *schema.Monitor) []semanticIssue {
+			k := m.Pulse.HealthyThreshold
+			if k <= highHealthyThreshold {
+				return nil
+			}
+			msg := fmt.Sprintf("healthy_threshold=%d requires %d consecutive successes before the monitor reports green", k, k)
+			if m.Pulse.Interval > 0 {
+				msg += fmt.Sprintf(" (at least %v after recovery)", time.Duration(k)*m.Pulse.Interval)
+			}
+			return []semanticIssue{{fieldPath: []string{"pulse_check", "healthy_threshold"}, message: msg}}
+		},
+	},
+	{
+		// timeout > interval already fails the per-field validator, so
+		// equality is the only remaining overlap hazard: a slow check
+		// leaves no slack before the next one is due.
+		name: "check-overlap",
+		check: func(m *schema.Monitor) []semanticIssue {
+			if m.Pulse.Timeout <= 0 || m.Pulse.Timeout != m.Pulse.Interval ||
+				strings.EqualFold(m.Pulse.Type, "heartbeat") {
+				return nil
+			}
+			return []semanticIssue{{
+				fieldPath: []string{"pulse_check", "timeout"},
+				message:   fmt.Sprintf("timeout equals interval (%v), leaving no slack before the next check is due", m.Pulse.Timeout),
+			}}
+		},
+	},
+	{
+		// Intervention escalation is keyed to the pulse unhealthy_threshold,
+		// so an intervention max_failures below it never takes effect — the
+		// author expects a restart after fewer failures than will ever
+		// trigger one.
+		name: "intervention-threshold",
+		check: func(m *schema.Monitor) []semanticIssue {
+			if m.Intervention.Action == "" && len(m.Intervention.Steps) == 0 {
+				return nil
+			}
+			mf, ut := m.Intervention.MaxFailures, m.Pulse.UnhealthyThreshold
+			if mf <= 0 || ut <= 0 || mf >= ut {
+				return nil
+			}
+			return []semanticIssue{{
+				fieldPath: []string{"intervention", "max_failures"},
+				message:   fmt.Sprintf("intervention max_failures (%d) is below the pulse unhealthy_threshold (%d); escalation fires at unhealthy_threshold, so the lower value never takes effect", mf, ut),
+			}}
+		},
+	},
+	{
+		// A dispatching code color whose notify config carries no
+		// destination creates jobs that can never deliver: the failure only
+		// surfaces when the alert fires.
+		name: "dispatch-without-target",
+		check: func(m *schema.Monitor) []semanticIssue {
+			var issues []semanticIssue
+			colors := make([]string, 0, len(m.Codes))
+			for color := range m.Codes {
+				colors = append(colors, color)
+			}
+			sort.Strings(colors)
+			for _, color := range colors {
+				cfg := m.Codes[color]
+				for _, target := range append([]schema.CodeConfig{cfg}, cfg.Extra...) {
+					if msg := dispatchTargetIssue(color, target); msg != "" {
+						issues = append(issues, semanticIssue{
+							fieldPath: []string{"codes", color},
+							message:   msg,
+						})
+					}
+				}
+			}
+			return issues
+		},
+	},
+}
+
+// dispatchTargetIssue reports why one dispatching code target can never
+// deliver, or "" when it can. Log notifications fall back to stdout and
+// email configs validate their recipients at decode time, so only the
+// targets with an optional destination field are checked here.
+func dispatchTargetIssue(color string, cfg schema.CodeConfig) string {
+	if !cfg.Dispatch {
+		return ""
+	}
+	switch strings.ToLower(cfg.Notify) {
+	case "":
+		// Unreachable from manifests (the decoder rejects empty notify) but
+		// code-built monitors can get here.
+		return fmt.Sprintf("code %q has dispatch enabled but no notify target", color)
+	case "slack":
+		if c, ok := cfg.Config.(*schema.CodeNotificationSlack); !ok || c == nil || c.WebHook == "" {
+			return fmt.Sprintf("code %q dispatches to slack but has no hook configured", color)
+		}
+	case "webhook":
+		if c, ok := cfg.Config.(*schema.CodeNotificationWebhook); !ok || c == nil || c.URL == "" {
+			return fmt.Sprintf("code %q dispatches to webhook but has no url configured", color)
+		}
+	case "pagerduty":
+		if c, ok := cfg.Config.(*schema.CodeNotificationPagerDuty); !ok || c == nil ||
+			(c.RoutingKey == "" && c.RoutingKeyEnv == "") {
+			return fmt.Sprintf("code %q dispatches to pagerduty but has no routing_key or routing_key_env configured", color)
+		}
+	}
+	return ""
+}
+
+// checkSemantics runs the semantic rule table against one parsed monitor.
+func checkSemantics(m *schema.Monitor) []semanticIssue {
+	var issues []semanticIssue
+	for _, rule := range semanticRules {
+		issues = append(issues, rule.check(m)...)
+	}
+	return issues
+}
+
+// semanticFindings converts the monitor's semantic issues into lint
+// findings, resolving each issue's field line from the monitor's YAML node;
+// fields the node does not contain (defaults, code-built values) fall back
+// to the monitor's own line.
+func semanticFindings(m *schema.Monitor, node *yaml.Node, severity string) []Finding {
+	issues := checkSemantics(m)
+	if len(issues) == 0 {
+		return nil
+	}
+	findings := make([]Finding, 0, len(issues))
+	for _, issue := range issues {
+		line := nodeFieldLine(node, issue.fieldPath...)
+		if line == 0 && node != nil {
+			line = node.Line
+		}
+		findings = append(findings, Finding{
+			Severity: severity,
+			Monitor:  m.Name,
+			Line:     line,
+			Message:  issue.message,
+		})
+	}
+	return findings
+}
+
+// nodeFieldLine walks mapping keys down the path and returns the line of
+// the value at its end, or 0 when any step is missing.
+func nodeFieldLine(node *yaml.Node, path ...string) int {
+	current := resolvedNode(node)
+	for _, key := range path {
+		if current == nil || current.Kind != yaml.MappingNode {
+			return 0
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(current.Content); i += 2 {
+			if current.Content[i].Value == key {
+				next = resolvedNode(current.Content[i+1])
+				break
+			}
+		}
+		if next == nil {
+			return 0
+		}
+		current = next
+	}
+	if current == nil {
+		return 0
+	}
+	return current.Line
+}
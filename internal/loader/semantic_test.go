@@ -0,0 +1,172 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/entities"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Each fixture exercises exactly one semantic rule; the surrounding fields
+// are kept valid so the only findings are the rule's own.
+
+func TestSemantic_HealthyThresholdUnrealistic(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  - name: slow-recovery
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 30s
+      timeout: 5s
+      healthy_threshold: 500
+      config:
+        url: http://example.com/health
+`)
+	requireFinding(t, findings, SeverityWarning, 8, "healthy_threshold=500")
+}
+
+func TestSemantic_TimeoutEqualsInterval(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  - name: overlap
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 5s
+      config:
+        url: http://example.com/health
+`)
+	requireFinding(t, findings, SeverityWarning, 7, "timeout equals interval")
+}
+
+func TestSemantic_InterventionBelowUnhealthyThreshold(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  - name: eager-restart
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      unhealthy_threshold: 5
+      config:
+        url: http://example.com/health
+    intervention:
+      action: docker
+      max_failures: 2
+      target:
+        type: restart
+        name: eager-restart
+`)
+	requireFinding(t, findings, SeverityWarning, 13, "max_failures (2) is below the pulse unhealthy_threshold (5)")
+}
+
+func TestSemantic_DispatchWithoutTarget(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  - name: silent-alert
+    enabled: true
+    codes:
+      red:
+        notify: slack
+        config: {}
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+`)
+	requireFinding(t, findings, SeverityWarning, 6, `code "red" dispatches to slack but has no hook configured`)
+}
+
+func TestSemantic_CleanManifest(t *testing.T) {
+	findings := lintYAML(t, `monitors:
+  - name: web-1
+    enabled: true
+    codes:
+      red:
+        notify: slack
+        config:
+          hook: http://hooks.example.com/x
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      healthy_threshold: 3
+      unhealthy_threshold: 3
+      config:
+        url: http://example.com/health
+    intervention:
+      action: docker
+      max_failures: 3
+      target:
+        type: restart
+        name: web-1
+`)
+	if len(findings) != 0 {
+		t.Errorf("expected no findings for a clean manifest, got %+v", findings)
+	}
+}
+
+// TestSemantic_StrictValidationRejects loads one contradictory monitor both
+// ways: permissive loads keep it with a warning, StrictValidation rejects it
+// like a validation failure.
+func TestSemantic_StrictValidationRejects(t *testing.T) {
+	yaml := `monitors:
+  - name: overlap
+    enabled: true
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 5s
+      config:
+        url: http://example.com/health
+`
+	tmpFile := filepath.Join(t.TempDir(), "monitors.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yaml), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	load := func(strict bool) *PipelineStats {
+		world := ecs.NewWorld()
+		em := entities.NewEntityManager(&world)
+		config := DefaultPipelineConfig()
+		config.Workers = 2
+		config.StrictValidation = strict
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		stats, err := NewPipeline(&world, em, config).Load(ctx, tmpFile)
+		if err != nil {
+			t.Fatalf("Load (strict=%v) failed: %v", strict, err)
+		}
+		return stats
+	}
+
+	permissive := load(false)
+	if permissive.EntitiesCreated != 1 {
+		t.Errorf("permissive load created %d entities, want 1", permissive.EntitiesCreated)
+	}
+	var warned bool
+	for _, w := range permissive.Warnings {
+		if w.Monitor == "overlap" && strings.Contains(w.Message, "timeout equals interval") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("permissive load warnings = %+v, want a timeout-equals-interval warning", permissive.Warnings)
+	}
+
+	strict := load(true)
+	if strict.EntitiesCreated != 0 {
+		t.Errorf("strict load created %d entities, want 0", strict.EntitiesCreated)
+	}
+	if strict.SkippedMonitors != 1 {
+		t.Errorf("strict load skipped %d monitors, want 1", strict.SkippedMonitors)
+	}
+}
@@ -0,0 +1,263 @@
+// Package pipeline provides a concurrent pipeline for loading and validating
+// monitor configurations with high throughput.
+//
+// The pipeline architecture:
+//   - Stage 1 (Sequential): Read YAML file and decode raw nodes
+//   - Stage 2 (Parallel): Parse and validate monitors (up to 1000 workers)
+//   - Stage 3 (Sequential): Batch collection with deduplication
+//   - Stage 4 (Sequential): Entity creation using Ark batch API
+//
+// Key constraint: Ark ECS world is NOT thread-safe, so entity creation
+// must be sequential.
+package loader
+
+import (
+	"time"
+
+	"cpra/internal/loader/schema"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// ProgressCallback is called periodically during loading to report progress.
+type ProgressCallback func(progress LoadProgress)
+
+// LoadProgress holds progress information during file loading.
+type LoadProgress struct {
+	BytesRead      int64         // Bytes read from file so far
+	TotalBytes     int64         // Total file size (0 if unknown, e.g., gzip)
+	MonitorsParsed int64         // Number of monitors parsed so far
+	Elapsed        time.Duration // Time elapsed since start
+	Stage          string        // Current stage: "reading", "validating", "creating"
+}
+
+// PipelineConfig holds configuration for the concurrent loading pipeline.
+type PipelineConfig struct {
+	// Workers is the number of concurrent workers for parse+validate stage.
+	// Recommended: runtime.NumCPU() * 2 for CPU-bound work, up to 1000.
+	Workers int
+
+	// BatchSize is the number of monitors to batch before entity creation.
+	BatchSize int
+
+	// BufferSize is the size of the file read buffer in bytes.
+	BufferSize int
+
+	// RawChannelSize is the buffer size for the raw YAML node channel.
+	RawChannelSize int
+
+	// ValidatedChannelSize is the buffer size for the validated monitor channel.
+	ValidatedChannelSize int
+
+	// BatchChannelSize is the buffer size for the batch channel.
+	BatchChannelSize int
+
+	// StrictUnknownFields rejects unknown YAML fields when true.
+	StrictUnknownFields bool
+
+	// FailFast stops processing on first validation error when true.
+	// When false, invalid monitors are skipped and logged.
+	FailFast bool
+
+	// StrictValidation upgrades semantic findings — cross-field
+	// contradictions like a timeout equal to the interval or a dispatching
+	// code color without a destination (see semanticRules) — from load
+	// warnings to validation errors: contradictory monitors are rejected
+	// like any other validation failure and honor FailFast.
+	StrictValidation bool
+
+	// ProgressInterval is the interval for progress reporting.
+	ProgressInterval time.Duration
+
+	// ProgressCallback is called periodically to report loading progress.
+	// If nil, no progress is reported.
+	ProgressCallback ProgressCallback
+
+	// StreamingMode enables line-by-line streaming to reduce memory usage.
+	// Required for files with 1M+ monitors to avoid OOM.
+	StreamingMode bool
+
+	// DisableEnvInterpolation turns off ${VAR} / ${VAR:-default} expansion
+	// in scalar string values during parsing. Expansion is on by default;
+	// see interpolateEnv. It applies to YAML manifests only.
+	DisableEnvInterpolation bool
+
+	// Format forces the manifest parser: "yaml", "json" (a single document
+	// matching schema.Manifest), or "ndjson" (one monitor object per line).
+	// Empty selects by file extension; see manifestFormat.
+	Format string
+
+	// MaxGeneratedMonitors caps the total expansion of the manifest's
+	// `generate` section, guarding against a typo'd count allocating a
+	// million entities. Zero uses the default (1,000,000).
+	MaxGeneratedMonitors int
+
+	// LogValidationErrors enables logging of individual validation errors.
+	// When true and Logger is set, validation errors are logged with monitor context.
+	LogValidationErrors bool
+
+	// Logger is used for logging validation errors when LogValidationErrors is true.
+	Logger *zap.SugaredLogger
+
+	// MaxDeduplicationEntries is the maximum number of entries in the deduplication map.
+	// When exceeded, oldest entries are evicted (FIFO). Default: 0 (unbounded).
+	MaxDeduplicationEntries int
+
+	// HighFrequencyBudget caps how many monitors may opt into high_frequency.
+	// The fast path sweeps every such monitor every tick, so the cap keeps it
+	// cheap. Exceeding the budget fails the load. 0 or negative = unlimited.
+	HighFrequencyBudget int
+
+	// MaxLoadWarnings caps how many non-fatal warnings are kept in
+	// PipelineStats.Warnings; warnings past the cap are counted in
+	// WarningsDropped instead of retained, so a manifest full of disabled
+	// monitors cannot balloon the stats. Zero uses the default (100).
+	MaxLoadWarnings int
+
+	// DuplicatePolicy selects how repeated monitor names are handled.
+	// Empty or DuplicatePolicyError keeps the historical behavior: within
+	// one file the first definition wins and the repeat is counted, across
+	// files the load aborts. DuplicatePolicyOverride makes later
+	// definitions replace earlier ones — generated overlays appending to a
+	// base manifest — recording a warning instead of failing.
+	DuplicatePolicy string
+}
+
+// DuplicatePolicy values for PipelineConfig.
+const (
+	DuplicatePolicyError    = "error"
+	DuplicatePolicyOverride = "override-last-wins"
+)
+
+// DefaultPipelineConfig returns optimized default configuration.
+func DefaultPipelineConfig() PipelineConfig {
+	return PipelineConfig{
+		Workers:              1000,
+		BatchSize:            10000,
+		BufferSize:           4 * 1024 * 1024, // 4MB
+		RawChannelSize:       10000,
+		ValidatedChannelSize: 10000,
+		BatchChannelSize:     100,
+		StrictUnknownFields:  false,
+		FailFast:             false,
+		ProgressInterval:     250 * time.Millisecond,
+		StreamingMode:        true, // Enable streaming by default to handle 1M+ monitors
+		HighFrequencyBudget:  256,
+		MaxGeneratedMonitors: 1_000_000,
+		MaxLoadWarnings:      100,
+	}
+}
+
+// RawMonitor holds either a raw YAML node or raw bytes for a monitor before parsing.
+// For streaming mode, RawBytes is set and Node is nil.
+// For traditional mode, Node is set and RawBytes is nil.
+type RawMonitor struct {
+	Node      *yaml.Node
+	RawBytes  []byte        // For streaming mode: raw YAML bytes for this monitor
+	JSONBytes []byte        // For JSON/NDJSON manifests: one monitor object
+	Defaults  *yaml.Node    // Top-level defaults block to merge in; may be nil
+	Routes    schema.Routes // Top-level routes block for notify group resolution; may be nil
+	File      string        // Source file; set on directory loads for duplicate reporting
+	Line      int
+}
+
+// ValidatedMonitor holds a parsed and validated monitor.
+type ValidatedMonitor struct {
+	Monitor schema.Monitor
+	File    string
+	Line    int
+}
+
+// MonitorBatch holds a batch of validated monitors ready for entity creation.
+type MonitorBatch struct {
+	Monitors []schema.Monitor
+	BatchID  int
+}
+
+// PipelineProgress represents the current state of the pipeline.
+type PipelineProgress struct {
+	// Stage identifies the current bottleneck stage
+	Stage string
+
+	// RawParsed is the number of raw YAML nodes read
+	RawParsed int64
+
+	// Validated is the number of monitors validated
+	Validated int64
+
+	// Skipped is the number of monitors skipped due to validation errors
+	Skipped int64
+
+	// Batched is the number of monitors batched
+	Batched int64
+
+	// Created is the number of entities created
+	Created int64
+
+	// Rate is the overall monitors/second throughput
+	Rate float64
+
+	// Elapsed is the total elapsed time
+	Elapsed time.Duration
+}
+
+// LoadWarning is one non-fatal problem noticed while a monitor streamed
+// through the load: the monitor still loads, but the operator probably wants
+// to know. Line is the monitor's line in File; both are zero when the source
+// format cannot supply them (e.g. a single JSON document).
+type LoadWarning struct {
+	Monitor string `json:"monitor,omitempty"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// PipelineStats holds final pipeline statistics.
+type PipelineStats struct {
+	// TotalMonitors is the total number of monitors processed
+	TotalMonitors int64
+
+	// EntitiesCreated is the number of ECS entities created
+	EntitiesCreated int64
+
+	// SkippedMonitors is the number of monitors skipped due to errors
+	SkippedMonitors int64
+
+	// DuplicateMonitors is the number of duplicate monitors skipped
+	DuplicateMonitors int64
+
+	// GeneratedMonitors is the number of monitors expanded from the
+	// manifest's `generate` section, counted separately from literal
+	// entries (which make up the rest of TotalMonitors)
+	GeneratedMonitors int64
+
+	// LoadingTime is the total time to load all monitors
+	LoadingTime time.Duration
+
+	// ParseRate is the average monitors/second during parsing
+	ParseRate float64
+
+	// CreationRate is the average entities/second during creation
+	CreationRate float64
+
+	// PulseRate is the aggregated expected pulse arrival rate (jobs/sec)
+	PulseRate float64
+
+	// PulseTypes counts loaded monitors per pulse type (http, tcp, ...).
+	PulseTypes map[string]int64
+
+	// CodeColors counts how many loaded monitors configure each code color.
+	CodeColors map[string]int64
+
+	// WithInterventions is the number of loaded monitors that configure an
+	// intervention (single action or steps).
+	WithInterventions int64
+
+	// Warnings holds non-fatal problems noticed during the load, capped at
+	// PipelineConfig.MaxLoadWarnings entries.
+	Warnings []LoadWarning
+
+	// WarningsDropped counts warnings past the cap that were not retained.
+	WarningsDropped int64
+}
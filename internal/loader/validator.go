@@ -0,0 +1,533 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"cpra/internal/loader/schema"
+)
+
+// Validation errors
+var (
+	ErrEmptyMonitorName       = errors.New("monitor name cannot be empty")
+	ErrEmptyPulseType         = errors.New("pulse type cannot be empty")
+	ErrInvalidPulseType       = errors.New("invalid pulse type: must be http, tcp, udp, icmp, ntp, dns, grpc, docker, or heartbeat")
+	ErrInvalidURL             = errors.New("invalid URL")
+	ErrMissingURL             = errors.New("HTTP pulse requires URL")
+	ErrMissingHost            = errors.New("TCP/ICMP/DNS pulse requires host")
+	ErrInvalidPort            = errors.New("TCP pulse port must be between 1 and 65535")
+	ErrInvalidInterval        = errors.New("pulse interval must be positive")
+	ErrInvalidTimeout         = errors.New("pulse timeout must be positive")
+	ErrTimeoutExceedsInterval = errors.New("pulse timeout should not exceed interval")
+	ErrInvalidThreshold       = errors.New("threshold must be positive")
+	ErrInvalidGrace           = errors.New("heartbeat grace period must not be negative")
+	ErrMissingNTPServers      = errors.New("NTP pulse requires at least one server")
+	ErrInvalidMaxOffset       = errors.New("NTP max offset must be positive")
+	ErrInvalidCodeColor       = errors.New("invalid code color")
+	ErrInvalidNotifyType      = errors.New("invalid notify type")
+	ErrInvalidDegradedPhase   = errors.New("invalid degraded_phase")
+	ErrDetailedTimingType     = errors.New("detailed_timing is only supported for http and tcp pulses")
+	ErrSubSecondInterval      = errors.New("intervals below one second require high_frequency: true")
+	ErrIntervalBelowFloor     = errors.New("high-frequency interval is below the supported floor")
+	ErrHighFrequencyHeartbeat = errors.New("high_frequency is not supported for push-based heartbeat monitors")
+	ErrMalformedHeader        = errors.New("malformed header: expected \"Name: value\"")
+	ErrInvalidExpectedStatus  = errors.New("invalid expected_status")
+	ErrInvalidBodyRegex       = errors.New("invalid body_regex")
+	ErrInvalidRecordType      = errors.New("invalid record_type: must be A, AAAA, CNAME, TXT, or MX")
+	ErrInvalidAuthType        = errors.New("invalid auth type: must be basic or bearer")
+	ErrMissingAuthCredentials = errors.New("auth block is missing required credentials")
+	ErrInvalidBackoffStrategy = errors.New("invalid retry_backoff strategy: must be exponential, constant, or none")
+	ErrInvalidBackoffDelay    = errors.New("retry_backoff delays must not be negative")
+	ErrBackoffMaxBelowBase    = errors.New("retry_backoff max must not be below base")
+	ErrMissingGRPCTarget      = errors.New("gRPC pulse requires target")
+	ErrInvalidGRPCTarget      = errors.New("invalid gRPC target: must be host:port")
+	ErrInvalidProxy           = errors.New("invalid proxy: must be an absolute http, https, or socks5 URL")
+	ErrInvalidIPFamily        = errors.New("invalid ip_family: must be auto, ipv4, or ipv6")
+	ErrInvalidUDPBytes        = errors.New("invalid udp payload/expect")
+	ErrMissingContainer       = errors.New("docker pulse requires container")
+	ErrInvalidRequireStatus   = errors.New("invalid require_status: must be running or healthy")
+	ErrTooManyLabels          = errors.New("too many labels")
+	ErrInvalidLabelKey        = errors.New("invalid label key: must be alphanumeric with inner '_', '.', '/' or '-'")
+	ErrLabelValueTooLong      = errors.New("label value too long")
+)
+
+// DefaultHighFrequencyFloor is the lowest interval supported by the
+// high-frequency fast path. Below this the 10 TPS tick loop cannot provide
+// meaningful accuracy even on the dedicated path.
+const DefaultHighFrequencyFloor = 100 * time.Millisecond
+
+// ValidCodeColors defines the valid color names for code alerts.
+var ValidCodeColors = map[string]bool{
+	"red": true, "yellow": true, "green": true, "cyan": true,
+	"gray": true, "orange": true, "purple": true, "white": true,
+}
+
+// ValidPulseTypes defines the valid pulse check types.
+var ValidPulseTypes = map[string]bool{
+	"http": true, "tcp": true, "udp": true, "icmp": true, "ntp": true, "dns": true, "grpc": true, "docker": true, "heartbeat": true,
+}
+
+// ValidDNSRecordTypes defines the record types a DNS pulse can resolve.
+var ValidDNSRecordTypes = map[string]bool{
+	"A": true, "AAAA": true, "CNAME": true, "TXT": true, "MX": true,
+}
+
+// ValidNotifyTypes defines the valid notification types.
+var ValidNotifyTypes = map[string]bool{
+	"log": true, "slack": true, "pagerduty": true, "email": true, "webhook": true,
+}
+
+// ValidationRule is the interface for all validation rules.
+type ValidationRule interface {
+	Validate(monitor *schema.Monitor) error
+}
+
+// MonitorValidator holds a collection of validation rules.
+type MonitorValidator struct {
+	rules []ValidationRule
+}
+
+// NewValidator creates a new monitor validator with default rules.
+func NewValidator() *MonitorValidator {
+	return &MonitorValidator{
+		rules: []ValidationRule{
+			&RequiredFieldsRule{},
+			&PulseTypeRule{},
+			&PulseConfigRule{},
+			&IntervalTimeoutRule{},
+			&HighFrequencyRule{Floor: DefaultHighFrequencyFloor},
+			&RetryBackoffRule{},
+			&ThresholdRule{},
+			&DetailedTimingRule{},
+			&CodeColorRule{},
+			&NotifyTypeRule{},
+			&LabelsRule{},
+		},
+	}
+}
+
+// Validate runs all validation rules on a monitor.
+func (v *MonitorValidator) Validate(monitor *schema.Monitor) error {
+	for _, rule := range v.rules {
+		if err := rule.Validate(monitor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateAll runs every rule and collects all failures instead of stopping
+// at the first, so lint paths can report everything wrong with a monitor in
+// one pass. Load paths should keep using Validate.
+func (v *MonitorValidator) ValidateAll(monitor *schema.Monitor) []error {
+	var errs []error
+	for _, rule := range v.rules {
+		if err := rule.Validate(monitor); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// RequiredFieldsRule validates required fields are present.
+type RequiredFieldsRule struct{}
+
+func (r *RequiredFieldsRule) Validate(monitor *schema.Monitor) error {
+	if monitor.Name == "" {
+		return ErrEmptyMonitorName
+	}
+	if monitor.Pulse.Type == "" {
+		return ErrEmptyPulseType
+	}
+	return nil
+}
+
+// PulseTypeRule validates the pulse type is valid.
+type PulseTypeRule struct{}
+
+func (r *PulseTypeRule) Validate(monitor *schema.Monitor) error {
+	if !ValidPulseTypes[strings.ToLower(monitor.Pulse.Type)] {
+		return fmt.Errorf("%w: got %q", ErrInvalidPulseType, monitor.Pulse.Type)
+	}
+	return nil
+}
+
+// PulseConfigRule validates pulse configuration based on type.
+type PulseConfigRule struct{}
+
+func (r *PulseConfigRule) Validate(monitor *schema.Monitor) error {
+	switch strings.ToLower(monitor.Pulse.Type) {
+	case "http":
+		cfg, ok := monitor.Pulse.Config.(*schema.PulseHTTPConfig)
+		if !ok || cfg == nil {
+			return ErrMissingURL
+		}
+		if cfg.Url == "" {
+			return ErrMissingURL
+		}
+		if _, err := url.Parse(cfg.Url); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+		}
+		for _, header := range cfg.Headers {
+			// Headers arrive in canonical Name/Value form; the unmarshalers
+			// reject malformed entries, so only configs built in code can
+			// reach here with an empty name.
+			if strings.TrimSpace(header.Name) == "" {
+				return fmt.Errorf("%w: got %q", ErrMalformedHeader, header.Value)
+			}
+		}
+		if err := validateHTTPAuth(cfg.Auth); err != nil {
+			return err
+		}
+		if _, err := schema.ParseStatusRanges(cfg.ExpectedStatus); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidExpectedStatus, err)
+		}
+		if cfg.BodyRegex != "" {
+			if _, err := regexp.Compile(cfg.BodyRegex); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidBodyRegex, err)
+			}
+		}
+		if cfg.Proxy != "" {
+			u, err := url.Parse(cfg.Proxy)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidProxy, err)
+			}
+			switch u.Scheme {
+			case "http", "https", "socks5":
+				if u.Host == "" {
+					return fmt.Errorf("%w: got %q", ErrInvalidProxy, cfg.Proxy)
+				}
+			default:
+				return fmt.Errorf("%w: got %q", ErrInvalidProxy, cfg.Proxy)
+			}
+		}
+
+	case "tcp":
+		cfg, ok := monitor.Pulse.Config.(*schema.PulseTCPConfig)
+		if !ok || cfg == nil {
+			return ErrMissingHost
+		}
+		if cfg.Host == "" {
+			return ErrMissingHost
+		}
+		if cfg.Port < 1 || cfg.Port > 65535 {
+			return fmt.Errorf("%w: got %d", ErrInvalidPort, cfg.Port)
+		}
+		if err := validateIPFamily(cfg.IPFamily); err != nil {
+			return err
+		}
+
+	case "udp":
+		cfg, ok := monitor.Pulse.Config.(*schema.PulseUDPConfig)
+		if !ok || cfg == nil {
+			return ErrMissingHost
+		}
+		if cfg.Host == "" {
+			return ErrMissingHost
+		}
+		if cfg.Port < 1 || cfg.Port > 65535 {
+			return fmt.Errorf("%w: got %d", ErrInvalidPort, cfg.Port)
+		}
+		if _, err := schema.ParseUDPBytes(cfg.Payload); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidUDPBytes, err)
+		}
+		if _, err := schema.ParseUDPBytes(cfg.Expect); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidUDPBytes, err)
+		}
+
+	case "icmp":
+		cfg, ok := monitor.Pulse.Config.(*schema.PulseICMPConfig)
+		if !ok || cfg == nil {
+			return ErrMissingHost
+		}
+		if cfg.Host == "" {
+			return ErrMissingHost
+		}
+		if err := validateIPFamily(cfg.IPFamily); err != nil {
+			return err
+		}
+
+	case "dns":
+		cfg, ok := monitor.Pulse.Config.(*schema.PulseDNSConfig)
+		if !ok || cfg == nil {
+			return ErrMissingHost
+		}
+		if cfg.Host == "" {
+			return ErrMissingHost
+		}
+		if cfg.RecordType != "" && !ValidDNSRecordTypes[strings.ToUpper(cfg.RecordType)] {
+			return fmt.Errorf("%w: got %q", ErrInvalidRecordType, cfg.RecordType)
+		}
+
+	case "grpc":
+		cfg, ok := monitor.Pulse.Config.(*schema.PulseGRPCConfig)
+		if !ok || cfg == nil || cfg.Target == "" {
+			return ErrMissingGRPCTarget
+		}
+		if host, port, err := net.SplitHostPort(cfg.Target); err != nil || host == "" || port == "" {
+			return fmt.Errorf("%w: got %q", ErrInvalidGRPCTarget, cfg.Target)
+		}
+
+	case "ntp":
+		cfg, ok := monitor.Pulse.Config.(*schema.PulseNTPConfig)
+		if !ok || cfg == nil || len(cfg.Servers) == 0 {
+			return ErrMissingNTPServers
+		}
+		if cfg.MaxOffset <= 0 {
+			return ErrInvalidMaxOffset
+		}
+
+	case "docker":
+		cfg, ok := monitor.Pulse.Config.(*schema.PulseDockerConfig)
+		if !ok || cfg == nil || cfg.Container == "" {
+			return ErrMissingContainer
+		}
+		switch strings.ToLower(cfg.RequireStatus) {
+		case "", "running", "healthy":
+		default:
+			return fmt.Errorf("%w: got %q", ErrInvalidRequireStatus, cfg.RequireStatus)
+		}
+
+	case "heartbeat":
+		if cfg, ok := monitor.Pulse.Config.(*schema.PulseHeartbeatConfig); ok && cfg != nil {
+			if cfg.Grace < 0 {
+				return ErrInvalidGrace
+			}
+		}
+	}
+	return nil
+}
+
+// validateHTTPAuth checks an optional auth block: the scheme must be known
+// and each scheme must have its credentials, inline or via an env var name.
+// Whether a referenced env var is actually set is only known at job creation.
+func validateHTTPAuth(auth *schema.HTTPAuth) error {
+	if auth == nil {
+		return nil
+	}
+	switch strings.ToLower(auth.Type) {
+	case "basic":
+		if auth.Username == "" || (auth.Password == "" && auth.PasswordEnv == "") {
+			return fmt.Errorf("%w: basic auth requires username and password or password_env", ErrMissingAuthCredentials)
+		}
+	case "bearer":
+		if auth.Token == "" && auth.TokenEnv == "" {
+			return fmt.Errorf("%w: bearer auth requires token or token_env", ErrMissingAuthCredentials)
+		}
+	default:
+		return fmt.Errorf("%w: got %q", ErrInvalidAuthType, auth.Type)
+	}
+	return nil
+}
+
+// validateIPFamily checks an ip_family setting on TCP and ICMP pulses.
+func validateIPFamily(family string) error {
+	switch strings.ToLower(family) {
+	case "", "auto", "ipv4", "ipv6":
+		return nil
+	default:
+		return fmt.Errorf("%w: got %q", ErrInvalidIPFamily, family)
+	}
+}
+
+// IntervalTimeoutRule validates interval and timeout values.
+type IntervalTimeoutRule struct{}
+
+func (r *IntervalTimeoutRule) Validate(monitor *schema.Monitor) error {
+	if monitor.Pulse.Interval <= 0 {
+		return ErrInvalidInterval
+	}
+	// Heartbeat monitors are push-based; there is no probe, so no timeout to check.
+	if strings.EqualFold(monitor.Pulse.Type, "heartbeat") {
+		return nil
+	}
+	if monitor.Pulse.Timeout <= 0 {
+		return ErrInvalidTimeout
+	}
+	// CRITICAL: Enforce timeout <= interval to prevent overlapping jobs
+	if monitor.Pulse.Timeout > monitor.Pulse.Interval {
+		return fmt.Errorf("%w: timeout=%v, interval=%v",
+			ErrTimeoutExceedsInterval, monitor.Pulse.Timeout, monitor.Pulse.Interval)
+	}
+	return nil
+}
+
+// HighFrequencyRule validates sub-second intervals. They are only allowed for
+// monitors explicitly marked high_frequency, down to a configurable floor, so
+// accuracy expectations are opted into rather than silently unmet.
+type HighFrequencyRule struct {
+	Floor time.Duration
+}
+
+func (r *HighFrequencyRule) Validate(monitor *schema.Monitor) error {
+	floor := r.Floor
+	if floor <= 0 {
+		floor = DefaultHighFrequencyFloor
+	}
+	if !monitor.Pulse.HighFrequency {
+		if monitor.Pulse.Interval > 0 && monitor.Pulse.Interval < time.Second {
+			return fmt.Errorf("%w: got %v", ErrSubSecondInterval, monitor.Pulse.Interval)
+		}
+		return nil
+	}
+	if strings.EqualFold(monitor.Pulse.Type, "heartbeat") {
+		return ErrHighFrequencyHeartbeat
+	}
+	if monitor.Pulse.Interval < floor {
+		return fmt.Errorf("%w: got %v, floor %v", ErrIntervalBelowFloor, monitor.Pulse.Interval, floor)
+	}
+	return nil
+}
+
+// validBackoffStrategies are the retry_backoff strategy names accepted by the
+// jobs package; the empty string selects the exponential default.
+var validBackoffStrategies = map[string]bool{
+	"": true, "exponential": true, "constant": true, "none": true,
+}
+
+// RetryBackoffRule validates the optional retry_backoff block. A missing
+// block is always valid and keeps the historical backoff behavior.
+type RetryBackoffRule struct{}
+
+func (r *RetryBackoffRule) Validate(monitor *schema.Monitor) error {
+	rb := monitor.Pulse.RetryBackoff
+	if rb == nil {
+		return nil
+	}
+	if !validBackoffStrategies[strings.ToLower(rb.Strategy)] {
+		return fmt.Errorf("%w: got %q", ErrInvalidBackoffStrategy, rb.Strategy)
+	}
+	if rb.Base < 0 || rb.Max < 0 {
+		return fmt.Errorf("%w: base=%v, max=%v", ErrInvalidBackoffDelay, rb.Base, rb.Max)
+	}
+	if rb.Base > 0 && rb.Max > 0 && rb.Max < rb.Base {
+		return fmt.Errorf("%w: base=%v, max=%v", ErrBackoffMaxBelowBase, rb.Base, rb.Max)
+	}
+	return nil
+}
+
+// ThresholdRule validates threshold values.
+type ThresholdRule struct{}
+
+func (r *ThresholdRule) Validate(monitor *schema.Monitor) error {
+	if monitor.Pulse.UnhealthyThreshold < 0 {
+		return fmt.Errorf("%w: unhealthy_threshold must be >= 0", ErrInvalidThreshold)
+	}
+	if monitor.Pulse.HealthyThreshold < 0 {
+		return fmt.Errorf("%w: healthy_threshold must be >= 0", ErrInvalidThreshold)
+	}
+	return nil
+}
+
+// httpDegradedPhases are the degraded_phase names valid for HTTP pulses.
+// Named phases (anything other than "total") require detailed_timing.
+var httpDegradedPhases = map[string]bool{
+	"": true, "total": true, "dns": true, "connect": true,
+	"tls": true, "ttfb": true, "transfer": true,
+}
+
+// tcpDegradedPhases are the degraded_phase names valid for TCP pulses.
+var tcpDegradedPhases = map[string]bool{
+	"": true, "total": true, "connect": true,
+}
+
+// DetailedTimingRule validates detailed_timing and degraded_phase settings.
+type DetailedTimingRule struct{}
+
+func (r *DetailedTimingRule) Validate(monitor *schema.Monitor) error {
+	pulseType := strings.ToLower(monitor.Pulse.Type)
+	if monitor.Pulse.DetailedTiming && pulseType != "http" && pulseType != "tcp" {
+		return fmt.Errorf("%w: got %q", ErrDetailedTimingType, monitor.Pulse.Type)
+	}
+	phase := strings.ToLower(monitor.Pulse.DegradedPhase)
+	var valid map[string]bool
+	switch pulseType {
+	case "http":
+		valid = httpDegradedPhases
+	case "tcp":
+		valid = tcpDegradedPhases
+	default:
+		valid = map[string]bool{"": true, "total": true}
+	}
+	if !valid[phase] {
+		return fmt.Errorf("%w: %q is not valid for %s pulses", ErrInvalidDegradedPhase, monitor.Pulse.DegradedPhase, pulseType)
+	}
+	if phase != "" && phase != "total" && !monitor.Pulse.DetailedTiming {
+		return fmt.Errorf("%w: phase %q requires detailed_timing", ErrInvalidDegradedPhase, monitor.Pulse.DegradedPhase)
+	}
+	return nil
+}
+
+// CodeColorRule validates code color names.
+type CodeColorRule struct{}
+
+func (r *CodeColorRule) Validate(monitor *schema.Monitor) error {
+	for color := range monitor.Codes {
+		if !ValidCodeColors[strings.ToLower(color)] {
+			return fmt.Errorf("%w: got %q", ErrInvalidCodeColor, color)
+		}
+	}
+	return nil
+}
+
+// NotifyTypeRule validates notification types.
+type NotifyTypeRule struct{}
+
+func (r *NotifyTypeRule) Validate(monitor *schema.Monitor) error {
+	for _, cfg := range monitor.Codes {
+		if cfg.Notify != "" && !ValidNotifyTypes[strings.ToLower(cfg.Notify)] {
+			return fmt.Errorf("%w: got %q", ErrInvalidNotifyType, cfg.Notify)
+		}
+	}
+	return nil
+}
+
+// Label bounds. Interned label sets are never garbage collected, so the
+// count and sizes are capped at load time to keep cardinality sane.
+const (
+	MaxLabelsPerMonitor = 32
+	MaxLabelKeyLength   = 63
+	MaxLabelValueLength = 256
+)
+
+// labelKeyPattern is the safe charset for label keys: alphanumeric with
+// inner dots, dashes, underscores and slashes (for prefixed keys like
+// "cpra.io/team").
+var labelKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_./-]*[a-zA-Z0-9])?$`)
+
+// LabelsRule bounds the monitor's label set and restricts keys to a safe
+// charset so labels can pass through notification payloads, file names and
+// metric tags unescaped.
+type LabelsRule struct{}
+
+func (r *LabelsRule) Validate(monitor *schema.Monitor) error {
+	if len(monitor.Labels) > MaxLabelsPerMonitor {
+		return fmt.Errorf("%w: got %d, max %d", ErrTooManyLabels, len(monitor.Labels), MaxLabelsPerMonitor)
+	}
+	for key, value := range monitor.Labels {
+		if key == "" || len(key) > MaxLabelKeyLength || !labelKeyPattern.MatchString(key) {
+			return fmt.Errorf("%w: got %q", ErrInvalidLabelKey, key)
+		}
+		if len(value) > MaxLabelValueLength {
+			return fmt.Errorf("%w: label %q value is %d bytes, max %d", ErrLabelValueTooLong, key, len(value), MaxLabelValueLength)
+		}
+	}
+	return nil
+}
+
+// MinIntervalRule enforces a minimum pulse interval.
+type MinIntervalRule struct {
+	MinInterval time.Duration
+}
+
+func (r *MinIntervalRule) Validate(monitor *schema.Monitor) error {
+	if monitor.Pulse.Interval < r.MinInterval {
+		return fmt.Errorf("pulse interval %v is below minimum %v", monitor.Pulse.Interval, r.MinInterval)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Strict unknown-field checking for monitor entries has to happen on the
+// yaml.Node itself: Monitor and Pulse use custom unmarshalers, so the
+// decoder's KnownFields never sees their keys. The walk below mirrors the
+// decoder's anchor handling - aliases are followed and `<<` merge keys are
+// resolved - so anchored templates (&defaults / <<: *defaults) validate the
+// same way they decode.
+
+// monitorFields are the keys accepted at the top level of a monitor entry.
+var monitorFields = map[string]struct{}{
+	"name":          {},
+	"labels":        {},
+	"enabled":       {},
+	"pulse_check":   {},
+	"intervention":  {},
+	"codes":         {},
+	"notify_groups": {},
+	"slo":           {},
+}
+
+// pulseFields are the keys accepted inside a pulse_check block.
+var pulseFields = map[string]struct{}{
+	"type":                {},
+	"groups":              {},
+	"interval":            {},
+	"timeout":             {},
+	"retries":             {},
+	"max_failures":        {},
+	"unhealthy_threshold": {},
+	"healthy_threshold":   {},
+	"detailed_timing":     {},
+	"high_frequency":      {},
+	"retry_backoff":       {},
+	"schedule":            {},
+	"invert":              {},
+	"degraded_threshold":  {},
+	"degraded_phase":      {},
+	"config":              {},
+}
+
+// validateMonitorFields checks a monitor mapping node for unknown fields.
+// Merge keys are treated as valid and their targets are validated against the
+// same field set; errors found in merged content are attributed to the merge
+// site, with a note pointing at the anchor's definition line.
+func validateMonitorFields(node *yaml.Node) error {
+	node = resolveAlias(node)
+	return validateFields(node, monitorFields, monitorNodeName(node), 0)
+}
+
+// validateFields walks one mapping level against a set of known keys.
+// mergeSite is 0 for directly-written content, or the line of the merge key
+// (or alias reference) that pulled this content in.
+func validateFields(node *yaml.Node, known map[string]struct{}, name string, mergeSite int) error {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		// Wrong shapes produce a decoder error with better context.
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if isMergeKey(key) {
+			site := key.Line
+			if mergeSite > 0 {
+				// Nested merges: keep blaming the outermost merge site,
+				// which is the line the user actually wrote.
+				site = mergeSite
+			}
+			for _, target := range mergeTargets(value) {
+				if err := validateFields(target, known, name, site); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if _, ok := known[key.Value]; !ok {
+			if mergeSite > 0 {
+				return fmt.Errorf("monitor %q: unknown field %q merged at line %d (defined via anchor at line %d)",
+					name, key.Value, mergeSite, key.Line)
+			}
+			return fmt.Errorf("monitor %q: unknown field %q at line %d", name, key.Value, key.Line)
+		}
+
+		// pulse_check is the one strictly-checked nested block; its config
+		// keys vary by pulse type and are left to the per-type decoders.
+		if key.Value == "pulse_check" {
+			sub, site := value, mergeSite
+			if sub.Kind == yaml.AliasNode && site == 0 {
+				site = sub.Line
+			}
+			if err := validateFields(sub, pulseFields, name, site); err != nil {
+				return err
+			}
+		}
+
+		// Header strings inside an http config are shape-checked here so a
+		// typo fails the load with a line number instead of surfacing at
+		// execute time.
+		if key.Value == "config" {
+			if err := validateHeaderEntries(resolveAlias(value), name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// monitorNodeName extracts the monitor's name so field errors can identify
+// the monitor even when most of its content comes from a merged anchor.
+// Explicit keys win over merged ones, mirroring YAML merge semantics.
+func monitorNodeName(node *yaml.Node) string {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return ""
+	}
+	merged := ""
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == "name" {
+			if v := resolveAlias(value); v != nil {
+				return v.Value
+			}
+		}
+		if isMergeKey(key) && merged == "" {
+			for _, target := range mergeTargets(value) {
+				if n := monitorNodeName(target); n != "" {
+					merged = n
+					break
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// validateHeaderEntries checks the `headers` list of an http config block.
+// Each entry must look like "Name: value" with a non-empty name; the value
+// may be empty. The map form has no string shape to check and is left to
+// the decoder, as is a missing or differently-typed node (other pulse types
+// have no headers key at all).
+func validateHeaderEntries(config *yaml.Node, name string) error {
+	if config == nil || config.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(config.Content); i += 2 {
+		if config.Content[i].Value != "headers" {
+			continue
+		}
+		entries := mergeTargets(config.Content[i+1])
+		if v := resolveAlias(config.Content[i+1]); v != nil && v.Kind == yaml.SequenceNode {
+			entries = v.Content
+		}
+		for _, entry := range entries {
+			entry = resolveAlias(entry)
+			if entry == nil || entry.Kind != yaml.ScalarNode {
+				continue
+			}
+			if header, _, ok := strings.Cut(entry.Value, ":"); !ok || strings.TrimSpace(header) == "" {
+				return fmt.Errorf("monitor %q: malformed header %q at line %d (expected \"Name: value\")",
+					name, entry.Value, entry.Line)
+			}
+		}
+	}
+	return nil
+}
+
+// isMergeKey reports whether a key node is the YAML merge key (`<<`).
+func isMergeKey(key *yaml.Node) bool {
+	return key.Tag == "!!merge" || key.Value == "<<"
+}
+
+// mergeTargets returns the mapping(s) a merge key pulls in: a single alias,
+// or a sequence of aliases for multi-template merges.
+func mergeTargets(value *yaml.Node) []*yaml.Node {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		targets := make([]*yaml.Node, 0, len(value.Content))
+		for _, item := range value.Content {
+			targets = append(targets, resolveAlias(item))
+		}
+		return targets
+	default:
+		return []*yaml.Node{resolveAlias(value)}
+	}
+}
+
+// resolveAlias follows alias nodes to the anchored node they reference.
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
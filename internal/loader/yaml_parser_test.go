@@ -0,0 +1,268 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/controller/entities"
+
+	"github.com/mlange-42/ark/ecs"
+	"gopkg.in/yaml.v3"
+)
+
+// loadStrict runs the traditional (node-based) pipeline with strict unknown
+// field checking, as anchor-heavy configs are validated through the node walk.
+func loadStrict(t *testing.T, yamlContent string) *PipelineStats {
+	t.Helper()
+
+	tmpFile := filepath.Join(t.TempDir(), "monitors.yaml")
+	if err := os.WriteFile(tmpFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	world := ecs.NewWorld()
+	em := entities.NewEntityManager(&world)
+
+	config := DefaultPipelineConfig()
+	config.StreamingMode = false
+	config.StrictUnknownFields = true
+	config.Workers = 4
+
+	pipeline := NewPipeline(&world, em, config)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := pipeline.Load(ctx, tmpFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return stats
+}
+
+// TestAnchoredTemplateSharedAcrossMonitors verifies that a template anchor
+// merged into several monitors passes strict field validation.
+func TestAnchoredTemplateSharedAcrossMonitors(t *testing.T) {
+	yaml := `defaults: &defaults
+  enabled: true
+  pulse_check:
+    type: http
+    interval: 5s
+    timeout: 3s
+    config:
+      url: http://example.com/health
+monitors:
+  - <<: *defaults
+    name: web-1
+  - <<: *defaults
+    name: web-2
+`
+	stats := loadStrict(t, yaml)
+	if stats.EntitiesCreated != 2 {
+		t.Errorf("Expected 2 entities, got %d", stats.EntitiesCreated)
+	}
+	if stats.SkippedMonitors != 0 {
+		t.Errorf("Expected 0 skipped, got %d", stats.SkippedMonitors)
+	}
+}
+
+// TestNestedAnchorsInPulseCheck verifies anchors on the pulse_check block
+// itself, both as a direct alias and via a nested merge with an override.
+func TestNestedAnchorsInPulseCheck(t *testing.T) {
+	yaml := `defaults: &http_pulse
+  type: http
+  interval: 5s
+  timeout: 3s
+  config:
+    url: http://example.com/health
+monitors:
+  - name: api-1
+    enabled: true
+    pulse_check: *http_pulse
+  - name: api-2
+    enabled: true
+    pulse_check:
+      <<: *http_pulse
+      interval: 30s
+`
+	stats := loadStrict(t, yaml)
+	if stats.EntitiesCreated != 2 {
+		t.Errorf("Expected 2 entities, got %d", stats.EntitiesCreated)
+	}
+	if stats.SkippedMonitors != 0 {
+		t.Errorf("Expected 0 skipped, got %d", stats.SkippedMonitors)
+	}
+}
+
+// TestDuplicateNamesFromAnchoredTemplates verifies deduplication works on
+// resolved names: two monitors inheriting the template's name collide, an
+// override does not.
+func TestDuplicateNamesFromAnchoredTemplates(t *testing.T) {
+	yaml := `defaults: &defaults
+  name: from-template
+  enabled: true
+  pulse_check:
+    type: http
+    interval: 5s
+    timeout: 3s
+    config:
+      url: http://example.com/health
+monitors:
+  - <<: *defaults
+  - <<: *defaults
+  - <<: *defaults
+    name: override
+`
+	stats := loadStrict(t, yaml)
+	if stats.EntitiesCreated != 2 {
+		t.Errorf("Expected 2 entities, got %d", stats.EntitiesCreated)
+	}
+	if stats.DuplicateMonitors != 1 {
+		t.Errorf("Expected 1 duplicate, got %d", stats.DuplicateMonitors)
+	}
+}
+
+// monitorNodes parses a document and returns the nodes under `monitors`.
+func monitorNodes(t *testing.T, doc string) []*yaml.Node {
+	t.Helper()
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(doc), &root); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	mapping := root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == "monitors" {
+			return mapping.Content[i+1].Content
+		}
+	}
+	t.Fatal("no monitors key in document")
+	return nil
+}
+
+// TestValidateMonitorFields_MergedInvalidField verifies that an invalid field
+// pulled in through an anchor names the monitor using it and attributes the
+// error to the merge site, with a note pointing at the anchor definition.
+func TestValidateMonitorFields_MergedInvalidField(t *testing.T) {
+	doc := `defaults: &defaults
+  enabled: true
+  retrys: 3
+monitors:
+  - <<: *defaults
+    name: web-1
+`
+	nodes := monitorNodes(t, doc)
+	err := validateMonitorFields(nodes[0])
+	if err == nil {
+		t.Fatal("expected error for invalid field in anchored template")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `monitor "web-1"`) {
+		t.Errorf("error must name the monitor using the anchor: %v", err)
+	}
+	if !strings.Contains(msg, `"retrys"`) {
+		t.Errorf("error must name the invalid field: %v", err)
+	}
+	// Merge site is line 5 (`- <<: *defaults`), the anchor defines the bad
+	// field on line 3.
+	if !strings.Contains(msg, "merged at line 5") {
+		t.Errorf("error must point at the merge site: %v", err)
+	}
+	if !strings.Contains(msg, "anchor at line 3") {
+		t.Errorf("error must reference the anchor definition line: %v", err)
+	}
+}
+
+// TestValidateMonitorFields_DirectInvalidField verifies plain (unmerged)
+// unknown fields report their own line without an anchor note.
+func TestValidateMonitorFields_DirectInvalidField(t *testing.T) {
+	doc := `monitors:
+  - name: web-1
+    enable: true
+`
+	nodes := monitorNodes(t, doc)
+	err := validateMonitorFields(nodes[0])
+	if err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `"enable"`) || !strings.Contains(msg, "line 3") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+	if strings.Contains(msg, "anchor") {
+		t.Errorf("direct error must not mention an anchor: %v", err)
+	}
+}
+
+// TestValidateMonitorFields_MergeKeyIsValid verifies the merge key itself is
+// never reported as an unknown field, at the monitor or pulse_check level.
+func TestValidateMonitorFields_MergeKeyIsValid(t *testing.T) {
+	doc := `defaults: &defaults
+  enabled: true
+pulse: &pulse
+  type: http
+  interval: 5s
+monitors:
+  - <<: *defaults
+    name: web-1
+    pulse_check:
+      <<: *pulse
+      timeout: 3s
+`
+	nodes := monitorNodes(t, doc)
+	if err := validateMonitorFields(nodes[0]); err != nil {
+		t.Errorf("merge keys must validate cleanly: %v", err)
+	}
+}
+
+// TestValidateMonitorFields_MalformedHeader verifies that a header string
+// without a "Name:" prefix fails the load with the monitor name and the
+// offending line, and that well-formed headers pass.
+func TestValidateMonitorFields_MalformedHeader(t *testing.T) {
+	doc := `monitors:
+  - name: web-1
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+        headers:
+          - "Accept: application/json"
+          - "Bearer abc123"
+`
+	nodes := monitorNodes(t, doc)
+	err := validateMonitorFields(nodes[0])
+	if err == nil {
+		t.Fatal("expected error for malformed header")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `monitor "web-1"`) || !strings.Contains(msg, `"Bearer abc123"`) {
+		t.Errorf("error must name the monitor and the header: %v", err)
+	}
+	if !strings.Contains(msg, "line 11") {
+		t.Errorf("error must point at the header's line: %v", err)
+	}
+}
+
+// TestValidateMonitorFields_ValidHeaders verifies header strings of the
+// "Name: value" form pass the node walk, including single-string form.
+func TestValidateMonitorFields_ValidHeaders(t *testing.T) {
+	doc := `monitors:
+  - name: web-1
+    pulse_check:
+      type: http
+      interval: 5s
+      timeout: 3s
+      config:
+        url: http://example.com/health
+        headers: "Host: internal.example.com"
+`
+	nodes := monitorNodes(t, doc)
+	if err := validateMonitorFields(nodes[0]); err != nil {
+		t.Errorf("valid headers must pass: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Package metrics provides a small, dependency-free registry that renders
+// gauges and counters in the Prometheus text exposition format. CPRA avoids
+// the full client library: values are mirrored from existing Stats()
+// snapshots at scrape time, so there is nothing to observe incrementally and
+// no reason to pull in its dependency tree.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Family is a named metric with optional labeled samples. A family with a
+// single unlabeled sample renders as a plain metric line.
+type Family struct {
+	name string
+	help string
+	typ  string
+
+	mu      sync.RWMutex
+	samples map[string]float64
+}
+
+// Set records the value for a label set, given as a pre-rendered Prometheus
+// label string such as `queue="pulse"`. Pass "" for an unlabeled metric.
+func (f *Family) Set(labels string, value float64) {
+	f.mu.Lock()
+	f.samples[labels] = value
+	f.mu.Unlock()
+}
+
+// write renders the family in exposition format, samples in sorted order so
+// output is stable across scrapes.
+func (f *Family) write(w io.Writer) {
+	f.mu.RLock()
+	labels := make([]string, 0, len(f.samples))
+	for l := range f.samples {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, f.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+	for _, l := range labels {
+		if l == "" {
+			fmt.Fprintf(w, "%s %v\n", f.name, f.samples[l])
+		} else {
+			fmt.Fprintf(w, "%s{%s} %v\n", f.name, l, f.samples[l])
+		}
+	}
+	f.mu.RUnlock()
+}
+
+// Registry holds metric families in registration order.
+type Registry struct {
+	mu       sync.RWMutex
+	families []*Family
+	byName   map[string]*Family
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]*Family)}
+}
+
+// Gauge registers (or returns the existing) gauge family with the given name.
+func (r *Registry) Gauge(name, help string) *Family {
+	return r.register(name, help, "gauge")
+}
+
+// Counter registers (or returns the existing) counter family. Values are
+// still written with Set: sources expose cumulative totals, not increments.
+func (r *Registry) Counter(name, help string) *Family {
+	return r.register(name, help, "counter")
+}
+
+func (r *Registry) register(name, help, typ string) *Family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.byName[name]; ok {
+		return f
+	}
+	f := &Family{name: name, help: help, typ: typ, samples: make(map[string]float64)}
+	r.families = append(r.families, f)
+	r.byName[name] = f
+	return f
+}
+
+// WritePrometheus renders all families in registration order.
+func (r *Registry) WritePrometheus(w io.Writer) {
+	r.mu.RLock()
+	families := r.families
+	r.mu.RUnlock()
+	for _, f := range families {
+		f.write(w)
+	}
+}
+
+// Handler returns an http.Handler serving the registry in text format.
+// Callers that mirror values from snapshots should refresh them before
+// delegating to this handler.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var buf strings.Builder
+		r.WritePrometheus(&buf)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = io.WriteString(w, buf.String())
+	})
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRegistry_WritePrometheus tests rendering of gauges and counters with
+// and without labels, in stable order.
+func TestRegistry_WritePrometheus(t *testing.T) {
+	t.Parallel()
+	r := NewRegistry()
+	depth := r.Gauge("cpra_queue_depth", "Jobs currently waiting in the queue.")
+	depth.Set(`queue="pulse"`, 7)
+	depth.Set(`queue="code"`, 0)
+	total := r.Counter("cpra_jobs_total", "Jobs processed.")
+	total.Set("", 42)
+
+	var buf strings.Builder
+	r.WritePrometheus(&buf)
+	out := buf.String()
+
+	want := []string{
+		"# HELP cpra_queue_depth Jobs currently waiting in the queue.",
+		"# TYPE cpra_queue_depth gauge",
+		`cpra_queue_depth{queue="code"} 0`,
+		`cpra_queue_depth{queue="pulse"} 7`,
+		"# TYPE cpra_jobs_total counter",
+		"cpra_jobs_total 42",
+	}
+	pos := -1
+	for _, line := range want {
+		idx := strings.Index(out, line)
+		if idx < 0 {
+			t.Fatalf("output missing line %q:\n%s", line, out)
+		}
+		if idx < pos {
+			t.Errorf("line %q out of order", line)
+		}
+		pos = idx
+	}
+}
+
+// TestRegistry_ReregisterReturnsSameFamily tests that registering the same
+// name twice returns one family instead of duplicating output.
+func TestRegistry_ReregisterReturnsSameFamily(t *testing.T) {
+	t.Parallel()
+	r := NewRegistry()
+	first := r.Gauge("cpra_thing", "A thing.")
+	second := r.Gauge("cpra_thing", "A thing.")
+	if first != second {
+		t.Error("expected the same family for repeated registration")
+	}
+	first.Set("", 1)
+
+	var buf strings.Builder
+	r.WritePrometheus(&buf)
+	if strings.Count(buf.String(), "# TYPE cpra_thing gauge") != 1 {
+		t.Errorf("family rendered more than once:\n%s", buf.String())
+	}
+}
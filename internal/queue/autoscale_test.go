@@ -0,0 +1,228 @@
+package queue
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"cpra/internal/clock"
+	"cpra/internal/jobs"
+)
+
+// scriptedStatsQueue is a Queue stub whose Stats are set by the test. The
+// autoscaler only reads Stats, so the remaining methods are inert; the pool
+// is never Started, and cycles are driven through runScaleCycle directly.
+type scriptedStatsQueue struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+func (q *scriptedStatsQueue) setStats(s Stats) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stats = s
+}
+
+func (q *scriptedStatsQueue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
+
+func (q *scriptedStatsQueue) Enqueue(jobs.Job) error { return nil }
+func (q *scriptedStatsQueue) EnqueueBatch(js []interface{}) ([]bool, error) {
+	return make([]bool, len(js)), nil
+}
+func (q *scriptedStatsQueue) EnqueueDelayed(jobs.Job, time.Duration) error { return nil }
+func (q *scriptedStatsQueue) Dequeue() (jobs.Job, error)                   { return nil, nil }
+func (q *scriptedStatsQueue) DequeueBatch(int) ([]jobs.Job, error)         { return nil, nil }
+func (q *scriptedStatsQueue) Close()                                       {}
+func (q *scriptedStatsQueue) Saturation() float64                          { return 0 }
+func (q *scriptedStatsQueue) Notify() <-chan struct{}                      { return make(chan struct{}) }
+
+// newAutoScaleTestPool builds a pool over a scripted queue with cooldowns and
+// warmup collapsed so each runScaleCycle call acts as one adjustment interval.
+func newAutoScaleTestPool(t *testing.T, q Queue, config WorkerPoolConfig) *DynamicWorkerPool {
+	t.Helper()
+	config.AdjustmentInterval = 0 // cycles are driven manually
+	config.WarmupDuration = time.Nanosecond
+	config.ScaleUpCooldown = time.Nanosecond
+	config.ScaleDownCooldown = time.Nanosecond
+	pool, err := NewDynamicWorkerPool(context.Background(), q, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	t.Cleanup(pool.DrainAndStop)
+	return pool
+}
+
+// TestAutoScale_ConvergesOnAnalyticC simulates a queue with known λ and τ and
+// verifies the SLO path converges to the analytic M/M/c worker count for
+// AvgQueueTime ≤ TargetQueueLatency.
+func TestAutoScale_ConvergesOnAnalyticC(t *testing.T) {
+	const lambda = 200.0 // arrivals/sec
+	const tau = 50 * time.Millisecond
+
+	q := &scriptedStatsQueue{}
+	q.setStats(Stats{
+		EnqueueRate:  lambda,
+		QueueDepth:   40,
+		AvgQueueTime: 300 * time.Millisecond, // well over target, no hysteresis hold
+	})
+
+	config := WorkerPoolConfig{
+		MinWorkers:         2,
+		MaxWorkers:         64,
+		TargetQueueLatency: 100 * time.Millisecond,
+	}
+	pool := newAutoScaleTestPool(t, q, config)
+
+	// Seed measured service times so the model sees the known τ. Identical
+	// samples give Cs=0, which defaults to 1.0 - plain M/M/c.
+	for i := 0; i < 50; i++ {
+		pool.metrics.RecordServiceTime(tau)
+	}
+
+	for i := 0; i < 10; i++ {
+		pool.runScaleCycle()
+	}
+
+	// Analytic target: minimal c with total time in system within Wq target
+	// plus one service time, matching what desiredCapacity solves for.
+	wTarget := config.TargetQueueLatency.Seconds() + tau.Seconds()
+	cAnalytic, _, err := FindCForSLO(lambda, tau.Seconds(), wTarget, 1, 1, config.MaxWorkers)
+	if err != nil {
+		t.Fatalf("analytic FindCForSLO failed: %v", err)
+	}
+
+	got := pool.antsPool.Cap()
+	upper := int(math.Ceil(float64(cAnalytic)*1.15)) + 1 // headroom plus rounding slack
+	if got < cAnalytic || got > upper {
+		t.Errorf("pool converged to %d workers, want within [%d, %d] of analytic c=%d",
+			got, cAnalytic, upper, cAnalytic)
+	}
+
+	stats := pool.Stats()
+	if stats.ScaleReason != scaleReasonSLO {
+		t.Errorf("expected scale reason %q, got %q", scaleReasonSLO, stats.ScaleReason)
+	}
+	if stats.DesiredWorkers != got {
+		t.Errorf("DesiredWorkers=%d does not match applied capacity %d", stats.DesiredWorkers, got)
+	}
+	if stats.PredictedWait <= 0 || stats.PredictedWait > config.TargetQueueLatency+tau {
+		t.Errorf("PredictedWait=%v outside (0, %v]", stats.PredictedWait, config.TargetQueueLatency+tau)
+	}
+}
+
+// TestAutoScale_ScaleDownHysteresis verifies a scale-down is held until the
+// measured queue wait has stayed under half of target for the configured
+// number of consecutive intervals, and then goes through.
+func TestAutoScale_ScaleDownHysteresis(t *testing.T) {
+	q := &scriptedStatsQueue{}
+	q.setStats(Stats{
+		EnqueueRate:  1.0,
+		QueueDepth:   0,
+		AvgQueueTime: 10 * time.Millisecond, // under half of the 100ms target
+	})
+
+	config := WorkerPoolConfig{
+		MinWorkers:             2,
+		MaxWorkers:             64,
+		TargetQueueLatency:     100 * time.Millisecond,
+		ScaleDownHoldIntervals: 6,
+	}
+	pool := newAutoScaleTestPool(t, q, config)
+	pool.Tune(16) // pretend a burst scaled us up earlier
+
+	for i := 0; i < 5; i++ {
+		pool.metrics.RecordServiceTime(5 * time.Millisecond)
+	}
+
+	// The first cycles lack rate samples and run the heuristic; once the SLO
+	// path wants fewer workers, the hold must keep capacity in place.
+	for i := 0; i < 4; i++ {
+		pool.runScaleCycle()
+	}
+	if reason := pool.Stats().ScaleReason; reason != scaleReasonHysteresis {
+		t.Errorf("expected scale reason %q during the hold, got %q", scaleReasonHysteresis, reason)
+	}
+	if cap := pool.antsPool.Cap(); cap != 16 {
+		t.Errorf("capacity changed to %d during hysteresis hold, want 16", cap)
+	}
+
+	// Enough low-wait intervals (and long-window samples) accumulate for the
+	// scale-down to be applied.
+	for i := 0; i < 8; i++ {
+		pool.runScaleCycle()
+	}
+	if cap := pool.antsPool.Cap(); cap >= 16 {
+		t.Errorf("expected scale-down after sustained low wait, capacity still %d", cap)
+	}
+}
+
+// TestTuneRamp_StepSequence verifies that a ramped pre-size climbs to the
+// (clamped) target in equal steps at the adjustment interval instead of one
+// jump.
+func TestTuneRamp_StepSequence(t *testing.T) {
+	manual := clock.NewManual(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	config := WorkerPoolConfig{
+		MinWorkers:         4,
+		MaxWorkers:         64,
+		AdjustmentInterval: 5 * time.Second,
+		Clock:              manual,
+	}
+	pool, err := NewDynamicWorkerPool(context.Background(), &scriptedStatsQueue{}, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	t.Cleanup(pool.DrainAndStop)
+
+	waitCap := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if pool.antsPool.Cap() == want {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatalf("capacity = %d, want %d", pool.antsPool.Cap(), want)
+	}
+
+	// Target above MaxWorkers clamps to 64; 30s over 5s intervals is 6
+	// steps of 10 from the starting capacity of 4.
+	pool.TuneRamp(100, 30*time.Second)
+	waitCap(14) // first step applies immediately
+	for i := 2; i <= 6; i++ {
+		manual.Advance(5 * time.Second)
+		waitCap(4 + 60*i/6)
+	}
+}
+
+// TestTuneRamp_ImmediateFallbacks covers the paths that must not ramp: a
+// non-positive duration and a target at or below current capacity.
+func TestTuneRamp_ImmediateFallbacks(t *testing.T) {
+	config := WorkerPoolConfig{
+		MinWorkers:         4,
+		MaxWorkers:         64,
+		AdjustmentInterval: 5 * time.Second,
+	}
+	pool, err := NewDynamicWorkerPool(context.Background(), &scriptedStatsQueue{}, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	t.Cleanup(pool.DrainAndStop)
+
+	pool.TuneRamp(32, 0)
+	if cap := pool.antsPool.Cap(); cap != 32 {
+		t.Fatalf("capacity = %d after zero-duration ramp, want 32", cap)
+	}
+
+	// Shrinking never ramps; Tune clamps to MinWorkers.
+	pool.TuneRamp(1, 30*time.Second)
+	if cap := pool.antsPool.Cap(); cap != 4 {
+		t.Fatalf("capacity = %d after shrinking ramp, want MinWorkers 4", cap)
+	}
+}
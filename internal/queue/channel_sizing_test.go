@@ -0,0 +1,131 @@
+package queue
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+
+	"go.uber.org/zap"
+)
+
+// quickPulseJob completes immediately with a pulse-kind result, so bursts of
+// them exercise the result channels without any per-job latency.
+type quickPulseJob struct {
+	enqueueTime time.Time
+	startTime   time.Time
+}
+
+func (j *quickPulseJob) Execute(_ context.Context) jobs.Result {
+	return jobs.Result{Payload: jobs.Payload{Kind: jobs.KindPulse}}
+}
+func (j *quickPulseJob) Copy() jobs.Job             { cp := *j; return &cp }
+func (j *quickPulseJob) GetEnqueueTime() time.Time  { return j.enqueueTime }
+func (j *quickPulseJob) SetEnqueueTime(t time.Time) { j.enqueueTime = t }
+func (j *quickPulseJob) GetStartTime() time.Time    { return j.startTime }
+func (j *quickPulseJob) SetStartTime(t time.Time)   { j.startTime = t }
+func (j *quickPulseJob) GetEntity() ecs.Entity      { return ecs.Entity{} }
+func (j *quickPulseJob) IsNil() bool                { return j == nil }
+
+// heapAlloc returns the live heap after a forced collection, so construction
+// deltas are not drowned in garbage from earlier tests.
+func heapAlloc() uint64 {
+	runtime.GC()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+// TestWorkerPool_BufferSizesDecoupledFromMaxWorkers constructs a pool with a
+// deliberately huge MaxWorkers but small explicit channel buffers, and
+// asserts construction stays cheap and a burst still routes through the
+// small buffers via the normal backpressure path.
+func TestWorkerPool_BufferSizesDecoupledFromMaxWorkers(t *testing.T) {
+	q, err := NewHybridQueue(HybridQueueConfig{
+		Name:             "sizing-test",
+		RingCapacity:     1024,
+		OverflowCapacity: 1024,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	config := WorkerPoolConfig{
+		MinWorkers:         2,
+		MaxWorkers:         120000,
+		AdjustmentInterval: 0,
+		ResultBatchSize:    8,
+		ResultBatchTimeout: time.Millisecond,
+		ResultChannelDepth: 64,
+		RouterChannelDepth: 4,
+		Kind:               jobs.KindPulse,
+	}
+
+	before := heapAlloc()
+	pool, err := NewDynamicWorkerPool(context.Background(), q, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	after := heapAlloc()
+
+	// A result channel sized to MaxWorkers alone would hold 120000 Result
+	// slots (~15 MB); with buffers decoupled the whole pool must come in
+	// far under that.
+	if after > before && after-before > 4<<20 {
+		t.Errorf("pool construction grew the heap by %d bytes with small buffers; channel sizing is coupled to MaxWorkers", after-before)
+	}
+	if got := cap(pool.GetRouter().PulseResultChan); got != 4 {
+		t.Errorf("router channel capacity = %d, want the configured 4", got)
+	}
+
+	pool.Start()
+	defer pool.DrainAndStop()
+
+	// A burst several times larger than every buffer combined must still be
+	// fully delivered: the router blocks briefly and then spills rather
+	// than dropping.
+	const burst = 500
+	received := make(chan int)
+	go func() {
+		n := 0
+		for batch := range pool.GetRouter().PulseResultChan {
+			n += len(batch)
+			pool.GetRouter().RecycleBatch(batch)
+			if n >= burst {
+				break
+			}
+		}
+		received <- n
+	}()
+	for i := 0; i < burst; i++ {
+		if err := q.Enqueue(&quickPulseJob{}); err != nil {
+			t.Fatalf("enqueue %d failed: %v", i, err)
+		}
+	}
+
+	select {
+	case n := <-received:
+		if n != burst {
+			t.Errorf("received %d results, want %d", n, burst)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("burst did not drain through the small buffers")
+	}
+}
+
+// TestNewResultRouter_DefaultDepth verifies the router falls back to its own
+// throughput-based default instead of inheriting the per-result depth.
+func TestNewResultRouter_DefaultDepth(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	r := NewResultRouter(WorkerPoolConfig{ResultChannelDepth: 120000}, nil, stopCh)
+	if got := cap(r.PulseResultChan); got != defaultRouterChannelDepth {
+		t.Errorf("default router channel capacity = %d, want %d", got, defaultRouterChannelDepth)
+	}
+}
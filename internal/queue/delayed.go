@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"cpra/internal/jobs"
+)
+
+// delayedJob pairs a job with the instant it becomes eligible for dequeue.
+// The sequence number breaks ties so jobs with equal deadlines stay FIFO.
+type delayedJob struct {
+	readyAt time.Time
+	job     jobs.Job
+	seq     uint64
+}
+
+// delayHeap is a min-heap of delayed jobs ordered by readyAt.
+type delayHeap []delayedJob
+
+func (h delayHeap) Len() int { return len(h) }
+
+func (h delayHeap) Less(i, j int) bool {
+	if h[i].readyAt.Equal(h[j].readyAt) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].readyAt.Before(h[j].readyAt)
+}
+
+func (h delayHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *delayHeap) Push(x interface{}) { *h = append(*h, x.(delayedJob)) }
+
+func (h *delayHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = delayedJob{}
+	*h = old[:n-1]
+	return entry
+}
+
+// delayedJobs holds jobs scheduled for future delivery behind a queue's
+// normal lanes. Queues call promote from their dequeue paths so matured jobs
+// re-enter the regular enqueue flow; immature jobs are never exposed to
+// consumers.
+type delayedJobs struct {
+	mu      sync.Mutex
+	heap    delayHeap
+	seq     uint64
+	drained int64
+	now     func() time.Time // nil means time.Now; overridable in tests
+}
+
+func (d *delayedJobs) clock() time.Time {
+	if d.now != nil {
+		return d.now()
+	}
+	return time.Now()
+}
+
+// add schedules a job to become dequeueable after the delay elapses.
+func (d *delayedJobs) add(job jobs.Job, delay time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seq++
+	heap.Push(&d.heap, delayedJob{
+		readyAt: d.clock().Add(delay),
+		job:     job,
+		seq:     d.seq,
+	})
+}
+
+// promote hands matured jobs to enqueue in readyAt order. A job the queue
+// cannot admit right now (e.g. saturation) is put back so a later dequeue
+// pass retries it.
+func (d *delayedJobs) promote(enqueue func(jobs.Job) error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.clock()
+	for len(d.heap) > 0 && !d.heap[0].readyAt.After(now) {
+		entry := heap.Pop(&d.heap).(delayedJob)
+		if err := enqueue(entry.job); err != nil {
+			heap.Push(&d.heap, entry)
+			return
+		}
+	}
+}
+
+// depth reports how many jobs are still waiting to mature.
+func (d *delayedJobs) depth() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.heap)
+}
+
+// drainClosed discards pending jobs when the owning queue closes and records
+// how many never matured; the count surfaces via Stats.DelayedDrained.
+func (d *delayedJobs) drainClosed() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := int64(len(d.heap))
+	d.heap = nil
+	d.drained += n
+	return n
+}
+
+func (d *delayedJobs) drainedCount() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.drained
+}
@@ -0,0 +1,204 @@
+package queue
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// manualClock is a controllable time source for delayed-enqueue tests.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newManualClock(start time.Time) *manualClock {
+	return &manualClock{now: start}
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func newDelayedTestQueue(t *testing.T, clock *manualClock) *HybridQueue {
+	t.Helper()
+	queue, err := NewHybridQueue(HybridQueueConfig{
+		Name:             "delayed",
+		RingCapacity:     64,
+		OverflowCapacity: 16,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+	queue.delayed.now = clock.Now
+	t.Cleanup(queue.Close)
+	return queue
+}
+
+func TestHybridQueueEnqueueDelayed_MaturityOrdering(t *testing.T) {
+	clock := newManualClock(time.Unix(1_000_000, 0))
+	queue := newDelayedTestQueue(t, clock)
+
+	// Delays enqueued out of order mature in readyAt order; the zero delay
+	// goes straight to the ring.
+	if err := queue.EnqueueDelayed(newTestHybridJob(3), 30*time.Millisecond); err != nil {
+		t.Fatalf("EnqueueDelayed failed: %v", err)
+	}
+	if err := queue.EnqueueDelayed(newTestHybridJob(1), 10*time.Millisecond); err != nil {
+		t.Fatalf("EnqueueDelayed failed: %v", err)
+	}
+	if err := queue.EnqueueDelayed(newTestHybridJob(2), 20*time.Millisecond); err != nil {
+		t.Fatalf("EnqueueDelayed failed: %v", err)
+	}
+	if err := queue.EnqueueDelayed(newTestHybridJob(0), 0); err != nil {
+		t.Fatalf("EnqueueDelayed with zero delay failed: %v", err)
+	}
+
+	if got := queue.Stats().DelayedDepth; got != 3 {
+		t.Errorf("DelayedDepth = %d, want 3", got)
+	}
+
+	job, err := queue.Dequeue()
+	if err != nil || job == nil {
+		t.Fatalf("Dequeue = (%v, %v), want immediate job", job, err)
+	}
+	if id := job.(*testHybridJob).id; id != 0 {
+		t.Errorf("first dequeue id = %d, want 0", id)
+	}
+
+	// Nothing has matured yet.
+	if job, err := queue.Dequeue(); err != nil || job != nil {
+		t.Fatalf("Dequeue before maturity = (%v, %v), want (nil, nil)", job, err)
+	}
+
+	clock.Advance(10 * time.Millisecond)
+	job, err = queue.Dequeue()
+	if err != nil || job == nil {
+		t.Fatalf("Dequeue at 10ms = (%v, %v), want matured job", job, err)
+	}
+	if id := job.(*testHybridJob).id; id != 1 {
+		t.Errorf("dequeue at 10ms id = %d, want 1", id)
+	}
+
+	// A batch never returns the still-immature 20ms and 30ms jobs.
+	if batch, err := queue.DequeueBatch(10); err != nil || len(batch) != 0 {
+		t.Fatalf("DequeueBatch before maturity = (%d jobs, %v), want empty", len(batch), err)
+	}
+
+	clock.Advance(25 * time.Millisecond)
+	batch, err := queue.DequeueBatch(10)
+	if err != nil {
+		t.Fatalf("DequeueBatch failed: %v", err)
+	}
+	if len(batch) != 2 {
+		t.Fatalf("DequeueBatch returned %d jobs, want 2", len(batch))
+	}
+	if id := batch[0].(*testHybridJob).id; id != 2 {
+		t.Errorf("batch[0] id = %d, want 2", id)
+	}
+	if id := batch[1].(*testHybridJob).id; id != 3 {
+		t.Errorf("batch[1] id = %d, want 3", id)
+	}
+
+	stats := queue.Stats()
+	if stats.DelayedDepth != 0 {
+		t.Errorf("DelayedDepth after drain = %d, want 0", stats.DelayedDepth)
+	}
+	if stats.DelayedDrained != 0 {
+		t.Errorf("DelayedDrained = %d, want 0", stats.DelayedDrained)
+	}
+}
+
+func TestHybridQueueEnqueueDelayed_EqualDeadlinesStayFIFO(t *testing.T) {
+	clock := newManualClock(time.Unix(1_000_000, 0))
+	queue := newDelayedTestQueue(t, clock)
+
+	for i := 0; i < 5; i++ {
+		if err := queue.EnqueueDelayed(newTestHybridJob(i), 10*time.Millisecond); err != nil {
+			t.Fatalf("EnqueueDelayed failed: %v", err)
+		}
+	}
+	clock.Advance(10 * time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		job, err := queue.Dequeue()
+		if err != nil || job == nil {
+			t.Fatalf("Dequeue %d = (%v, %v), want job", i, job, err)
+		}
+		if id := job.(*testHybridJob).id; id != i {
+			t.Errorf("dequeue %d id = %d, want %d", i, id, i)
+		}
+	}
+}
+
+func TestHybridQueueEnqueueDelayed_CloseWithPending(t *testing.T) {
+	clock := newManualClock(time.Unix(1_000_000, 0))
+	queue := newDelayedTestQueue(t, clock)
+
+	for i := 0; i < 3; i++ {
+		if err := queue.EnqueueDelayed(newTestHybridJob(i), time.Minute); err != nil {
+			t.Fatalf("EnqueueDelayed failed: %v", err)
+		}
+	}
+	queue.Close()
+
+	stats := queue.Stats()
+	if stats.DelayedDrained != 3 {
+		t.Errorf("DelayedDrained = %d, want 3", stats.DelayedDrained)
+	}
+	if stats.DelayedDepth != 0 {
+		t.Errorf("DelayedDepth after close = %d, want 0", stats.DelayedDepth)
+	}
+	if _, err := queue.Dequeue(); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Dequeue after close = %v, want ErrQueueClosed", err)
+	}
+	if err := queue.EnqueueDelayed(newTestHybridJob(9), time.Second); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("EnqueueDelayed after close = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestAdaptiveQueueEnqueueDelayed(t *testing.T) {
+	clock := newManualClock(time.Unix(1_000_000, 0))
+	queue, err := NewAdaptiveQueue(64)
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+	queue.delayed.now = clock.Now
+
+	if err := queue.EnqueueDelayed(newTestHybridJob(1), 10*time.Millisecond); err != nil {
+		t.Fatalf("EnqueueDelayed failed: %v", err)
+	}
+	if job, err := queue.Dequeue(); err != nil || job != nil {
+		t.Fatalf("Dequeue before maturity = (%v, %v), want (nil, nil)", job, err)
+	}
+
+	clock.Advance(10 * time.Millisecond)
+	job, err := queue.Dequeue()
+	if err != nil || job == nil {
+		t.Fatalf("Dequeue after maturity = (%v, %v), want job", job, err)
+	}
+	if id := job.(*testHybridJob).id; id != 1 {
+		t.Errorf("dequeue id = %d, want 1", id)
+	}
+
+	if err := queue.EnqueueDelayed(newTestHybridJob(2), time.Minute); err != nil {
+		t.Fatalf("EnqueueDelayed failed: %v", err)
+	}
+	queue.Close()
+	if got := queue.Stats().DelayedDrained; got != 1 {
+		t.Errorf("DelayedDrained = %d, want 1", got)
+	}
+}
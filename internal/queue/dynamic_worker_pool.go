@@ -0,0 +1,1945 @@
+package queue
+
+import (
+	"context"
+	"cpra/internal/clock"
+	"cpra/internal/jobs"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+)
+
+// batchCollector uses sync.Cond for efficient batch collection with immediate
+// signaling when batch is full. This reduces CPU burn compared to ticker-based polling.
+type batchCollector struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	batch   []jobs.Result
+	maxSize int
+	ready   bool          // true when batch is full or timeout fired
+	closed  bool          // true when collector is shut down
+	timer   *time.Timer   // for timeout-based flushing
+	timeout time.Duration // batch flush timeout
+	onFlush func()        // called after flush signals are sent
+}
+
+// newBatchCollector creates a batch collector with sync.Cond signaling.
+func newBatchCollector(maxSize int, timeout time.Duration) *batchCollector {
+	c := &batchCollector{
+		batch:   make([]jobs.Result, 0, maxSize),
+		maxSize: maxSize,
+		timeout: timeout,
+	}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Add adds a result to the batch. Signals when batch is full.
+func (c *batchCollector) Add(r jobs.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.batch = append(c.batch, r)
+
+	// Start timer on first item
+	if len(c.batch) == 1 && c.timeout > 0 {
+		c.startTimerLocked()
+	}
+
+	// Signal immediately when batch is full
+	if len(c.batch) >= c.maxSize {
+		c.ready = true
+		c.stopTimerLocked()
+		c.cond.Signal()
+	}
+}
+
+// startTimerLocked starts the timeout timer (must hold lock).
+func (c *batchCollector) startTimerLocked() {
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.timer = time.AfterFunc(c.timeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if len(c.batch) > 0 && !c.closed {
+			c.ready = true
+			c.cond.Signal()
+		}
+	})
+}
+
+// stopTimerLocked stops the timeout timer (must hold lock).
+func (c *batchCollector) stopTimerLocked() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
+
+// Wait blocks until a batch is ready (full or timeout). Returns the batch.
+// Returns nil if closed with no pending items.
+func (c *batchCollector) Wait() []jobs.Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Wait for ready signal
+	for !c.ready && !c.closed {
+		c.cond.Wait()
+	}
+
+	// Return nil if closed with empty batch
+	if c.closed && len(c.batch) == 0 {
+		return nil
+	}
+
+	// Swap out the batch
+	result := c.batch
+	c.batch = make([]jobs.Result, 0, c.maxSize)
+	c.ready = false
+	c.stopTimerLocked()
+
+	return result
+}
+
+// Flush forces a flush of any pending items.
+func (c *batchCollector) Flush() []jobs.Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopTimerLocked()
+	result := c.batch
+	c.batch = make([]jobs.Result, 0, c.maxSize)
+	c.ready = false
+	return result
+}
+
+// Close shuts down the collector and wakes any waiting goroutine.
+func (c *batchCollector) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	c.stopTimerLocked()
+	c.cond.Broadcast() // Wake all waiters
+}
+
+// Len returns the current batch size.
+func (c *batchCollector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.batch)
+}
+
+// ResultRouter handles routing of job results to type-specific channels.
+// This enables decoupling result processing from the main worker pool.
+type ResultRouter struct {
+	PulseResultChan        chan []jobs.Result
+	InterventionResultChan chan []jobs.Result
+	CodeResultChan         chan []jobs.Result
+	logger                 *log.Logger
+	config                 WorkerPoolConfig
+	stopCh                 <-chan struct{}
+	closed                 atomic.Bool
+
+	// observer, when set, is invoked with each routed result batch in
+	// addition to the channel sends. It must not block; set it before the
+	// pool starts routing results.
+	observer func(kind string, results []jobs.Result)
+
+	// Overflow buffer for batches that could not be delivered within the
+	// bounded backpressure wait (see sendWithBackpressure). Spilling instead
+	// of blocking keeps workers completing jobs when a result consumer
+	// stalls; a per-kind recovery goroutine re-injects parked batches in
+	// arrival order once the channel has capacity again. All spill state and
+	// all spill sends are guarded by spillMu.
+	spillMu        sync.Mutex
+	spilled        map[string][][]jobs.Result
+	recovering     map[string]bool
+	spilledBatches atomic.Int64
+
+	// batchPool recycles the single-kind batch slices handed to the result
+	// channels. Consumers return finished batches via RecycleBatch; without
+	// recycling the pool degrades to plain allocation. boxPool circulates
+	// the *[]jobs.Result pointer boxes batchPool stores, so re-pooling a
+	// recycled slice does not re-allocate its box.
+	batchPool sync.Pool
+	boxPool   sync.Pool
+}
+
+// defaultResultSpillAfter bounds how long sendWithBackpressure waits on a
+// full result channel before spilling the batch to the overflow buffer.
+const defaultResultSpillAfter = 5 * time.Second
+
+// defaultRouterChannelDepth is the per-channel batch buffer when
+// WorkerPoolConfig.RouterChannelDepth is unset: at the default batch size
+// of 512 it tolerates ~32k undrained results per kind before the router's
+// backpressure wait and spill path take over.
+const defaultRouterChannelDepth = 64
+
+// WorkerPoolStats exposes runtime metrics for the dynamic worker pool.
+type WorkerPoolStats struct {
+	LastScaleTime   time.Time
+	MinWorkers      int
+	MaxWorkers      int
+	CurrentCapacity int
+	RunningWorkers  int
+	WaitingTasks    int
+	TargetWorkers   int
+	TasksSubmitted  int64
+	TasksCompleted  int64
+	ScalingEvents   int64
+	PendingResults  int
+	// SpilledBatches counts result batches the router parked in its overflow
+	// buffer because a result channel stayed full past the bounded
+	// backpressure wait (see WorkerPoolConfig.ResultSpillAfter).
+	SpilledBatches int64
+	// ThrottledJobs counts jobs this pool re-enqueued because their
+	// concurrency group was saturated. Groups snapshots the shared
+	// per-group limiter (in-flight, limit, throttled).
+	ThrottledJobs int64
+	Groups        []jobs.GroupConcurrencyStats
+	// Panics counts job panics recovered in workers; each produced a
+	// synthetic failure result for the job's entity. LastPanic retains the
+	// most recent panic message for diagnostics.
+	Panics    int64
+	LastPanic string
+	// DesiredWorkers is the c chosen by the last autoscale evaluation,
+	// PredictedWait the M/M/c predicted total time in system at that c, and
+	// ScaleReason how the decision was made: "slo" (wait-time SLO via M/M/c),
+	// "heuristic" (depth/rate fallback), or "hysteresis-hold" (a scale-down
+	// deferred until the measured wait stays low long enough).
+	DesiredWorkers int
+	PredictedWait  time.Duration
+	ScaleReason    string
+}
+
+// Scaling decision reasons reported in WorkerPoolStats.ScaleReason and the
+// autoscale log lines.
+const (
+	scaleReasonSLO        = "slo"
+	scaleReasonHeuristic  = "heuristic"
+	scaleReasonHysteresis = "hysteresis-hold"
+)
+
+// NewResultRouter creates a new result router with buffered channels.
+func NewResultRouter(config WorkerPoolConfig, logger *log.Logger, stopCh <-chan struct{}) *ResultRouter {
+	bufferSize := config.RouterChannelDepth
+	if bufferSize <= 0 {
+		bufferSize = defaultRouterChannelDepth
+	}
+	batchSize := config.ResultBatchSize
+	if batchSize <= 0 {
+		batchSize = 256
+	}
+	r := &ResultRouter{
+		PulseResultChan:        make(chan []jobs.Result, bufferSize),
+		InterventionResultChan: make(chan []jobs.Result, bufferSize),
+		CodeResultChan:         make(chan []jobs.Result, bufferSize),
+		config:                 config,
+		logger:                 logger,
+		stopCh:                 stopCh,
+	}
+	r.batchPool.New = func() any {
+		s := make([]jobs.Result, 0, batchSize)
+		return &s
+	}
+	return r
+}
+
+// getBatch returns an empty batch slice from the pool.
+func (r *ResultRouter) getBatch() *[]jobs.Result {
+	ptr := r.batchPool.Get().(*[]jobs.Result)
+	*ptr = (*ptr)[:0]
+	return ptr
+}
+
+// RecycleBatch hands a batch received from one of the result channels back
+// for reuse once the consumer is done with it. Recycling is optional — an
+// unrecycled batch is simply garbage collected — but keeps the result path
+// allocation-free under steady load. The caller must not retain the slice
+// or any element of it afterwards.
+func (r *ResultRouter) RecycleBatch(batch []jobs.Result) {
+	if cap(batch) == 0 {
+		return
+	}
+	box, _ := r.boxPool.Get().(*[]jobs.Result)
+	if box == nil {
+		box = new([]jobs.Result)
+	}
+	*box = batch
+	r.batchPool.Put(box)
+}
+
+// RouteResults takes a batch of mixed results and routes them to appropriate channels.
+func (r *ResultRouter) RouteResults(results []jobs.Result) {
+	if len(results) == 0 || r.closed.Load() {
+		return
+	}
+
+	// Group results by type
+	pulseResults := make([]jobs.Result, 0, len(results))
+	interventionResults := make([]jobs.Result, 0, len(results))
+	codeResults := make([]jobs.Result, 0, len(results))
+
+	for _, result := range results {
+		switch result.Payload.Kind {
+		case jobs.KindPulse:
+			pulseResults = append(pulseResults, result)
+		case jobs.KindIntervention:
+			interventionResults = append(interventionResults, result)
+		case jobs.KindCode:
+			codeResults = append(codeResults, result)
+		default:
+			if r.logger != nil {
+				r.logger.Printf("Unknown job kind in result: %v", result.Payload.Kind)
+			}
+		}
+	}
+
+	// Send to appropriate channels with backpressure logging
+	if len(pulseResults) > 0 {
+		r.notifyObserver("pulse", pulseResults)
+		r.sendWithBackpressure(r.PulseResultChan, pulseResults, "pulse")
+	}
+	if len(interventionResults) > 0 {
+		r.notifyObserver("intervention", interventionResults)
+		r.sendWithBackpressure(r.InterventionResultChan, interventionResults, "intervention")
+	}
+	if len(codeResults) > 0 {
+		r.notifyObserver("code", codeResults)
+		r.sendWithBackpressure(r.CodeResultChan, codeResults, "code")
+	}
+}
+
+// RouteKind sends an already single-kind batch straight to the matching
+// channel. Results pre-grouped at the worker side (see resultBatcher) skip
+// the classification pass and the grouped-slice allocations RouteResults
+// pays for a mixed batch. The channel consumer takes ownership of the slice;
+// RecycleBatch returns it to the pool.
+func (r *ResultRouter) RouteKind(kind jobs.JobKind, results []jobs.Result) {
+	if len(results) == 0 || r.closed.Load() {
+		return
+	}
+	var ch chan []jobs.Result
+	switch kind {
+	case jobs.KindPulse:
+		ch = r.PulseResultChan
+	case jobs.KindIntervention:
+		ch = r.InterventionResultChan
+	case jobs.KindCode:
+		ch = r.CodeResultChan
+	default:
+		if r.logger != nil {
+			r.logger.Printf("Unknown job kind in result batch: %v", kind)
+		}
+		return
+	}
+	label := kind.String()
+	r.notifyObserver(label, results)
+	r.sendWithBackpressure(ch, results, label)
+}
+
+// SetResultObserver installs a non-blocking tap invoked with each routed
+// result batch before it is sent to the type-specific channel. Used for
+// external result fan-out; must be set before the pool starts.
+func (r *ResultRouter) SetResultObserver(obs func(kind string, results []jobs.Result)) {
+	r.observer = obs
+}
+
+func (r *ResultRouter) notifyObserver(kind string, results []jobs.Result) {
+	if r.observer != nil {
+		r.observer(kind, results)
+	}
+}
+
+// sendWithBackpressure attempts to send a batch to a channel with exponential backoff.
+// Uses time.After instead of a ticker to reduce CPU wakeups when channel is full.
+// The wait is bounded by ResultSpillAfter: rather than parking the caller (and
+// eventually every worker) on a stalled consumer, the batch is spilled to the
+// overflow buffer and re-injected later.
+func (r *ResultRouter) sendWithBackpressure(ch chan []jobs.Result, batch []jobs.Result, label string) {
+	if r.closed.Load() {
+		return
+	}
+
+	// Earlier spilled batches must be delivered first to keep results in
+	// arrival order; while any remain, new batches queue up behind them.
+	r.spillMu.Lock()
+	drained := r.flushSpillLocked(ch, label)
+	r.spillMu.Unlock()
+	if !drained {
+		r.spillBatch(ch, batch, label)
+		return
+	}
+
+	// Fast path: try immediate send
+	select {
+	case ch <- batch:
+		return
+	default:
+	}
+
+	// Channel is full - enter backpressure mode with exponential backoff
+	baseBackoff := r.config.ResultBatchTimeout
+	if baseBackoff <= 0 {
+		baseBackoff = 50 * time.Millisecond
+	}
+	spillAfter := r.config.ResultSpillAfter
+	if spillAfter <= 0 {
+		spillAfter = defaultResultSpillAfter
+	}
+
+	deadline := time.Now().Add(spillAfter)
+	backoff := baseBackoff
+
+	for {
+		wait := backoff
+		if remaining := time.Until(deadline); remaining < wait {
+			wait = remaining
+		}
+		if wait <= 0 {
+			break
+		}
+		// Use time.After for each retry instead of continuous ticker
+		// This is more efficient: no timer allocation when channel has space
+		select {
+		case ch <- batch:
+			return
+		case <-r.stopCh:
+			if r.logger != nil {
+				r.logger.Printf("Dropping %s results during shutdown (%d jobs waiting)", label, len(batch))
+			}
+			return
+		case <-time.After(wait):
+			if r.logger != nil {
+				r.logger.Printf("Backpressure: %s results stalled (%d jobs waiting)", label, len(batch))
+			}
+			// Exponential backoff capped at 500ms
+			backoff = backoff * 2
+			if backoff > 500*time.Millisecond {
+				backoff = 500 * time.Millisecond
+			}
+		}
+	}
+
+	// Bounded wait exhausted - spill instead of blocking the worker.
+	r.spillBatch(ch, batch, label)
+}
+
+// spillBatch parks a batch in the overflow buffer and makes sure a recovery
+// goroutine for its kind is retrying delivery.
+func (r *ResultRouter) spillBatch(ch chan []jobs.Result, batch []jobs.Result, label string) {
+	r.spillMu.Lock()
+	if r.spilled == nil {
+		r.spilled = make(map[string][][]jobs.Result)
+		r.recovering = make(map[string]bool)
+	}
+	r.spilled[label] = append(r.spilled[label], batch)
+	parked := len(r.spilled[label])
+	startRecovery := !r.recovering[label]
+	if startRecovery {
+		r.recovering[label] = true
+	}
+	r.spillMu.Unlock()
+
+	r.spilledBatches.Add(1)
+	if r.logger != nil {
+		r.logger.Printf("Backpressure: spilled %s results (%d jobs, %d batches parked)", label, len(batch), parked)
+	}
+	if startRecovery {
+		go r.recoverSpill(ch, label)
+	}
+}
+
+// flushSpillLocked re-injects a kind's parked batches in arrival order for as
+// long as the channel accepts them without blocking. Reports whether the
+// spill is empty. Callers must hold spillMu.
+func (r *ResultRouter) flushSpillLocked(ch chan []jobs.Result, label string) bool {
+	pending := r.spilled[label]
+	for len(pending) > 0 {
+		select {
+		case ch <- pending[0]:
+			pending = pending[1:]
+		default:
+			r.spilled[label] = pending
+			return false
+		}
+	}
+	if r.spilled != nil {
+		delete(r.spilled, label)
+	}
+	return true
+}
+
+// recoverSpill retries delivery of a kind's parked batches until the spill
+// drains or the pool stops. One runs per kind with a non-empty spill.
+func (r *ResultRouter) recoverSpill(ch chan []jobs.Result, label string) {
+	interval := r.config.ResultBatchTimeout
+	if interval <= 0 {
+		interval = 50 * time.Millisecond
+	}
+	for {
+		select {
+		case <-r.stopCh:
+			// Close flushes whatever is still parked.
+			return
+		case <-time.After(interval):
+			r.spillMu.Lock()
+			drained := r.flushSpillLocked(ch, label)
+			if drained {
+				r.recovering[label] = false
+			}
+			r.spillMu.Unlock()
+			if drained {
+				if r.logger != nil {
+					r.logger.Printf("Backpressure: %s spill drained, resuming direct delivery", label)
+				}
+				return
+			}
+		}
+	}
+}
+
+// SpilledBatches reports how many result batches overflowed to the spill
+// buffer because their channel stayed full past the bounded wait.
+func (r *ResultRouter) SpilledBatches() int64 {
+	return r.spilledBatches.Load()
+}
+
+// Close flushes the spill buffer and closes all result channels. Consumers
+// are still draining the buffered channels at this point, so parked batches
+// get a final non-blocking delivery attempt; any that still do not fit are
+// dropped and logged.
+func (r *ResultRouter) Close() {
+	if r.closed.Swap(true) {
+		return
+	}
+	channels := map[string]chan []jobs.Result{
+		"pulse":        r.PulseResultChan,
+		"intervention": r.InterventionResultChan,
+		"code":         r.CodeResultChan,
+	}
+	r.spillMu.Lock()
+	for label, ch := range channels {
+		if r.flushSpillLocked(ch, label) {
+			continue
+		}
+		if r.logger != nil {
+			r.logger.Printf("Dropping %d spilled %s batches at shutdown", len(r.spilled[label]), label)
+		}
+		delete(r.spilled, label)
+	}
+	r.spillMu.Unlock()
+	close(r.PulseResultChan)
+	close(r.InterventionResultChan)
+	close(r.CodeResultChan)
+}
+
+// TapPulseResults creates a tee of the pulse results channel for non-intrusive fan-out.
+// Returns two channels: primary (for main consumer) and tap (for metrics/tracing).
+// Both channels close when done closes or the source channel is exhausted.
+//
+// Example:
+//
+//	done := jobs.Or(ctx.Done(), stopCh)
+//	pulseMain, pulseMetrics := router.TapPulseResults(done)
+//	go consumeResults(pulseMain)      // existing consumer
+//	go sampleMetrics(pulseMetrics)    // lightweight observer
+func (r *ResultRouter) TapPulseResults(done <-chan struct{}) (<-chan []jobs.Result, <-chan []jobs.Result) {
+	return jobs.Tee(done, r.PulseResultChan)
+}
+
+// TapInterventionResults creates a tee of the intervention results channel.
+// See TapPulseResults for usage.
+func (r *ResultRouter) TapInterventionResults(done <-chan struct{}) (<-chan []jobs.Result, <-chan []jobs.Result) {
+	return jobs.Tee(done, r.InterventionResultChan)
+}
+
+// TapCodeResults creates a tee of the code results channel.
+// See TapPulseResults for usage.
+func (r *ResultRouter) TapCodeResults(done <-chan struct{}) (<-chan []jobs.Result, <-chan []jobs.Result) {
+	return jobs.Tee(done, r.CodeResultChan)
+}
+
+// DynamicWorkerPool manages a pool of workers that execute jobs from a queue.
+// It can dynamically adjust the number of workers based on load.
+type DynamicWorkerPool struct {
+	ctx              context.Context
+	queue            Queue
+	// queueMu guards queue. The dispatcher holds the read lock across a full
+	// dequeue-and-submit iteration, so ReplaceQueue (write lock) can only
+	// swap the queue between iterations — never between a dequeue and the
+	// corresponding submits. queueChanged wakes a dispatcher parked on the
+	// old queue's Notify channel after a swap or Resume.
+	queueMu          sync.RWMutex
+	queueChanged     chan struct{}
+	paused           atomic.Bool
+	cancel           context.CancelFunc
+	antsPool         *ants.PoolWithFunc
+	logger           *log.Logger
+	resultChan       chan jobs.Result
+	router           *ResultRouter
+	config           WorkerPoolConfig
+	wg               sync.WaitGroup
+	stopCh           chan struct{}
+	stopOnce         sync.Once
+	resultsClosed    atomic.Bool
+	resultsCloseOnce sync.Once
+	tasksCompleted   atomic.Int64
+	scalingEvents    atomic.Int64
+	lastTarget       atomic.Int64
+	lastScaleTime    atomic.Int64
+	tasksSubmitted   atomic.Int64
+	stopping         atomic.Int32
+	droppedResults   atomic.Int64 // Count of results dropped during shutdown (logged summary)
+	throttledJobs    atomic.Int64 // Count of jobs re-enqueued by group concurrency limits
+	panicCount       atomic.Int64 // Count of job panics recovered in workers
+	lastPanic        atomic.Value // string: message of the most recent job panic
+
+	// M/M/c scaling infrastructure
+	metrics           *ScalingMetrics // Multi-window metrics collector
+	startTime         time.Time       // Pool start time for warmup period
+	lastScaleUpTime   time.Time       // For asymmetric cooldowns
+	lastScaleDownTime time.Time       // For asymmetric cooldowns
+
+	// Last autoscale decision, surfaced via Stats(). The wait is stored in
+	// nanoseconds; the reason holds one of the scaleReason* constants.
+	lastDesiredC    atomic.Int64
+	lastPredictedW  atomic.Int64
+	lastScaleReason atomic.Value
+
+	// lowWaitIntervals counts consecutive adjustment intervals with the
+	// measured queue wait under half of TargetQueueLatency. Only touched by
+	// the autoScale goroutine; scale-down hysteresis gates on it.
+	lowWaitIntervals int
+}
+
+// WorkerPoolConfig holds configuration for the DynamicWorkerPool.
+type WorkerPoolConfig struct {
+	// Name identifies the pool in scaling log lines so autoscale decisions
+	// are attributable when several pools share one process.
+	Name string
+
+	// Kind is the job kind this pool serves (pools are homogeneous). It
+	// stamps the synthetic failure Result built when a job panics, so the
+	// router still delivers it to the right result channel.
+	Kind jobs.JobKind
+
+	// Clock is the time source for the autoscaler's warmup, cooldown, and
+	// adjustment-interval decisions. Nil uses the system clock; tests inject
+	// a manual clock to drive scaling cycles without sleeping.
+	Clock clock.Clock
+
+	MinWorkers         int
+	MaxWorkers         int
+	AdjustmentInterval time.Duration
+	ResultBatchSize    int
+	ResultBatchTimeout time.Duration
+	// ResultChannelDepth buffers individual results between workers and the
+	// batcher. Memory math: each slot holds one jobs.Result (roughly 150
+	// bytes plus payload), so the buffer should be sized by throughput —
+	// a few batches' worth (4x ResultBatchSize covers double-buffering on
+	// both sides) — never by MaxWorkers, which at large worker counts would
+	// reserve megabytes per pool that idle deployments never use. Zero
+	// derives a throughput-based default; see optimalResultChannelDepth.
+	ResultChannelDepth int
+	// RouterChannelDepth buffers routed batches per result channel (pulse,
+	// intervention, code). Each slot is only a slice header, but a parked
+	// batch pins up to ResultBatchSize results, so the depth is the number
+	// of undrained batches tolerated before backpressure and then the spill
+	// path engage — a burst allowance, not a function of worker count.
+	// Zero uses defaultRouterChannelDepth.
+	RouterChannelDepth int
+	// ResultSpillAfter bounds how long result routing waits on a full,
+	// stalled result channel before spilling the batch to an overflow
+	// buffer instead of blocking the worker (default 5s).
+	ResultSpillAfter   time.Duration
+	TargetQueueLatency time.Duration
+
+	// M/M/c scaling parameters
+	// Asymmetric cooldowns - fast up, slow down
+	ScaleUpCooldown   time.Duration // Minimum time between scale-up events (default 30s)
+	ScaleDownCooldown time.Duration // Minimum time between scale-down events (default 120s)
+
+	// Hysteresis thresholds to prevent oscillation
+	ScaleUpThreshold   float64 // Ratio above current to trigger scale-up (default 1.1 = 10% above)
+	ScaleDownThreshold float64 // Ratio below current to trigger scale-down (default 0.8 = 20% below)
+
+	// Warm-up period during which no scaling occurs
+	WarmupDuration time.Duration // Default 60s - allows system to stabilize after startup
+
+	// MinScalingSamples is how many short-window rate samples must exist
+	// before the wait-time SLO path is trusted; below it the depth/rate
+	// heuristic is used instead (default 3).
+	MinScalingSamples int
+	// ScaleDownHoldIntervals is how many consecutive adjustment intervals
+	// the measured queue wait must stay under half of TargetQueueLatency
+	// before a scale-down is applied (default 3).
+	ScaleDownHoldIntervals int
+
+	// Ants-specific options
+	PreAlloc         bool
+	NonBlocking      bool
+	MaxBlockingTasks int
+	ExpiryDuration   time.Duration
+}
+
+// DefaultWorkerPoolConfig returns a default configuration for the worker pool.
+// MaxWorkers is capped based on GOMAXPROCS to prevent over-scheduling in containers.
+func DefaultWorkerPoolConfig() WorkerPoolConfig {
+	// Cap MaxWorkers based on available CPU (respects automaxprocs in containers)
+	cpuCount := runtime.GOMAXPROCS(0)
+	maxWorkers := cpuCount * 256 // 256 workers per core max for I/O-bound workloads
+	if maxWorkers > 8192 {
+		maxWorkers = 8192 // Hard cap for memory safety
+	}
+	if maxWorkers < 64 {
+		maxWorkers = 64 // Minimum for burst handling
+	}
+
+	return WorkerPoolConfig{
+		MinWorkers:         5,
+		MaxWorkers:         maxWorkers,
+		AdjustmentInterval: 5 * time.Second,
+		ResultBatchSize:    512,
+		ResultBatchTimeout: 10 * time.Millisecond,
+		ResultChannelDepth: 2048, // 4x the default batch size
+		RouterChannelDepth: defaultRouterChannelDepth,
+		ResultSpillAfter:   defaultResultSpillAfter,
+		TargetQueueLatency: 100 * time.Millisecond,
+		// M/M/c scaling defaults
+		ScaleUpCooldown:    30 * time.Second,  // React quickly to increased load
+		ScaleDownCooldown:  120 * time.Second, // Be conservative about reducing capacity
+		ScaleUpThreshold:   1.10,              // Scale up when 10% more workers needed
+		ScaleDownThreshold: 0.80,              // Scale down when 20% fewer workers needed
+		WarmupDuration:     60 * time.Second,  // No scaling during first minute
+		// Wait-time SLO scaling defaults
+		MinScalingSamples:      3, // Rate samples needed before trusting M/M/c
+		ScaleDownHoldIntervals: 3, // Intervals of low wait before scaling down
+		// Ants-specific options
+		PreAlloc:         false,
+		NonBlocking:      false,
+		MaxBlockingTasks: 0,
+		ExpiryDuration:   5 * time.Minute,
+	}
+}
+
+// optimalResultChannelDepth calculates the optimal result channel buffer size
+// based on worker count and batch size. This balances memory usage with throughput.
+//
+// The formula considers:
+// - At least 2x batch size to allow double-buffering
+// - Scaled to worker count to handle burst capacity
+// - Capped at maxWorkers to prevent memory waste at low load
+func optimalResultChannelDepth(maxWorkers, minWorkers, batchSize int) int {
+	if batchSize <= 0 {
+		batchSize = 256
+	}
+
+	// Base: 2x batch size for double-buffering
+	depth := batchSize * 2
+
+	// Scale up based on worker count (1 result per worker in flight)
+	// Use sqrt scaling to balance memory vs throughput
+	workerScale := int(math.Sqrt(float64(maxWorkers)))
+	if workerScale > depth {
+		depth = workerScale
+	}
+
+	// Cap at maxWorkers (each worker produces at most 1 result)
+	if depth > maxWorkers {
+		depth = maxWorkers
+	}
+
+	// Minimum of minWorkers * 2 to handle burst at startup
+	if depth < minWorkers*2 {
+		depth = minWorkers * 2
+	}
+
+	// Never smaller than batch size
+	if depth < batchSize {
+		depth = batchSize
+	}
+
+	return depth
+}
+
+// NewDynamicWorkerPool creates a new dynamic worker pool.
+func NewDynamicWorkerPool(ctx context.Context, q Queue, config WorkerPoolConfig, logger *log.Logger) (*DynamicWorkerPool, error) {
+	if config.MinWorkers <= 0 {
+		config.MinWorkers = 1
+	}
+	if config.MaxWorkers < config.MinWorkers {
+		config.MaxWorkers = config.MinWorkers
+	}
+	if config.ResultBatchSize <= 0 {
+		config.ResultBatchSize = 256
+	}
+	// Use dynamic buffer sizing if not explicitly set
+	if config.ResultChannelDepth <= 0 {
+		config.ResultChannelDepth = optimalResultChannelDepth(config.MaxWorkers, config.MinWorkers, config.ResultBatchSize)
+	}
+	if config.RouterChannelDepth <= 0 {
+		config.RouterChannelDepth = defaultRouterChannelDepth
+	}
+	if config.ResultSpillAfter <= 0 {
+		config.ResultSpillAfter = defaultResultSpillAfter
+	}
+	if config.TargetQueueLatency <= 0 {
+		config.TargetQueueLatency = 100 * time.Millisecond
+	}
+	// M/M/c scaling defaults
+	if config.ScaleUpCooldown <= 0 {
+		config.ScaleUpCooldown = 30 * time.Second
+	}
+	if config.ScaleDownCooldown <= 0 {
+		config.ScaleDownCooldown = 120 * time.Second
+	}
+	if config.ScaleUpThreshold <= 1.0 {
+		config.ScaleUpThreshold = 1.10 // 10% above current
+	}
+	if config.ScaleDownThreshold <= 0 || config.ScaleDownThreshold >= 1.0 {
+		config.ScaleDownThreshold = 0.80 // 20% below current
+	}
+	if config.WarmupDuration <= 0 {
+		config.WarmupDuration = 60 * time.Second
+	}
+	if config.MinScalingSamples <= 0 {
+		config.MinScalingSamples = 3
+	}
+	if config.ScaleDownHoldIntervals <= 0 {
+		config.ScaleDownHoldIntervals = 3
+	}
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stopCh := make(chan struct{})
+
+	now := config.Clock.Now()
+	pool := &DynamicWorkerPool{
+		queue:             q,
+		queueChanged:      make(chan struct{}, 1),
+		logger:            logger,
+		config:            config,
+		resultChan:        make(chan jobs.Result, config.ResultChannelDepth),
+		router:            NewResultRouter(config, logger, stopCh),
+		ctx:               ctx,
+		cancel:            cancel,
+		stopCh:            stopCh,
+		metrics:           NewScalingMetrics(DefaultScalingMetricsConfig()),
+		startTime:         now,
+		lastScaleUpTime:   now,
+		lastScaleDownTime: now,
+	}
+	workerFunc := func(job interface{}) {
+		// Track service time for Allen-Cunneen variance calculation
+		serviceStart := time.Now()
+		defer func() {
+			pool.metrics.RecordServiceTime(time.Since(serviceStart))
+		}()
+
+		j, ok := job.(jobs.Job)
+		if !ok {
+			if pool.logger != nil {
+				pool.logger.Printf("Error: Invalid job type in worker pool: %T", job)
+			}
+			return
+		}
+		// Derive a hard deadline from the job's own budget when it exposes
+		// one, so a check that ignores its internal timeouts still cannot
+		// hold the worker. Cancellation on shutdown flows from pool.ctx.
+		jobCtx := pool.ctx
+		if dj, ok := j.(jobs.DeadlineJob); ok {
+			if budget := dj.ExecutionBudget(); budget > 0 {
+				var cancel context.CancelFunc
+				jobCtx, cancel = context.WithTimeout(pool.ctx, budget)
+				defer cancel()
+			}
+		}
+		result := pool.executeJob(jobCtx, j)
+		// Return the group slot acquired by the dispatcher before Invoke.
+		if gj, ok := j.(jobs.GroupedJob); ok {
+			jobs.GetGroupLimiter().Release(gj.ConcurrencyGroup())
+		}
+		pool.deliverResult(result)
+	}
+
+	// Build ants options
+	var antsOptions []ants.Option
+	if pool.logger != nil {
+		antsOptions = append(antsOptions, ants.WithLogger(pool.logger))
+	}
+	// executeJob recovers job panics before they reach ants, so this
+	// handler is a last resort for panics outside job execution (e.g. in
+	// result delivery). Those have no job to attribute, only a log line.
+	antsOptions = append(antsOptions, ants.WithPanicHandler(func(err interface{}) {
+		if pool.logger != nil {
+			pool.logger.Printf("Worker panic: %v", err)
+		}
+	}))
+
+	if config.PreAlloc {
+		antsOptions = append(antsOptions, ants.WithPreAlloc(true))
+	}
+	if config.NonBlocking {
+		antsOptions = append(antsOptions, ants.WithNonblocking(true))
+	}
+	if config.MaxBlockingTasks > 0 {
+		antsOptions = append(antsOptions, ants.WithMaxBlockingTasks(config.MaxBlockingTasks))
+	}
+	if config.ExpiryDuration > 0 {
+		antsOptions = append(antsOptions, ants.WithExpiryDuration(config.ExpiryDuration))
+	}
+
+	antsPool, err := ants.NewPoolWithFunc(config.MaxWorkers, workerFunc, antsOptions...)
+	if err != nil {
+		return nil, err
+	}
+	pool.antsPool = antsPool
+	pool.antsPool.Tune(config.MinWorkers)
+	pool.lastTarget.Store(int64(config.MinWorkers))
+	pool.lastScaleTime.Store(now.UnixNano())
+
+	return pool, nil
+}
+
+// SetContext overrides the worker pool context. Call before Start().
+func (p *DynamicWorkerPool) SetContext(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	// Cancel previous context to avoid leaks; safe before Start.
+	p.cancel()
+	p.ctx, p.cancel = context.WithCancel(ctx)
+}
+
+// Start begins the worker pool's operations.
+func (p *DynamicWorkerPool) Start() {
+	routineCount := 2
+	if p.config.AdjustmentInterval > 0 {
+		routineCount++
+	}
+	p.wg.Add(routineCount)
+	go p.dispatcher()
+	go p.resultProcessor()
+	if p.config.AdjustmentInterval > 0 {
+		go p.autoScale()
+	}
+	if p.logger != nil {
+		p.logger.Println("DynamicWorkerPool started")
+	}
+}
+
+// GetRouter returns the result router for accessing type-specific result channels.
+func (p *DynamicWorkerPool) GetRouter() *ResultRouter {
+	return p.router
+}
+
+// DrainAndStop waits for outstanding tasks to finish before stopping the worker pool.
+func (p *DynamicWorkerPool) DrainAndStop() {
+	if !p.stopping.CompareAndSwap(0, 1) {
+		return
+	}
+
+	startTime := time.Now()
+	pending := p.tasksSubmitted.Load() - p.tasksCompleted.Load()
+
+	if p.logger != nil {
+		p.logger.Printf("Draining DynamicWorkerPool (pending=%d, running=%d)...",
+			pending, p.antsPool.Running())
+	}
+
+	// Signal all goroutines to stop
+	p.stopOnce.Do(func() { close(p.stopCh) })
+	p.cancel()
+
+	// Wait for pending results with timeout
+	drainWindow := p.config.TargetQueueLatency * 5
+	if drainWindow < 2*time.Second {
+		drainWindow = 2 * time.Second
+	}
+	p.waitForResultsUntil(time.Now().Add(drainWindow))
+
+	// Wait for goroutines to exit
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	waitTimeout := p.config.TargetQueueLatency * 2
+	if waitTimeout <= 0 {
+		waitTimeout = 2 * time.Second
+	}
+
+	select {
+	case <-done:
+		if p.logger != nil {
+			finalPending := p.tasksSubmitted.Load() - p.tasksCompleted.Load()
+			p.logger.Printf("Goroutines exited cleanly (remaining_pending=%d)", finalPending)
+		}
+	case <-time.After(waitTimeout):
+		if p.logger != nil {
+			finalPending := p.tasksSubmitted.Load() - p.tasksCompleted.Load()
+			p.logger.Printf("Draining timed out after %v; forcing shutdown (dropping %d pending results)",
+				waitTimeout, finalPending)
+		}
+	}
+
+	// Close result router channels
+	p.router.Close()
+
+	// Release ants pool resources
+	p.antsPool.Release()
+
+	if p.logger != nil {
+		completed := p.tasksCompleted.Load()
+		dropped := p.droppedResults.Load()
+		if dropped > 0 {
+			p.logger.Printf("DynamicWorkerPool stopped in %v (completed=%d, dropped=%d)",
+				time.Since(startTime), completed, dropped)
+		} else {
+			p.logger.Printf("DynamicWorkerPool stopped in %v (completed=%d)",
+				time.Since(startTime), completed)
+		}
+		// Surface group concurrency limiting at shutdown when it fired.
+		if throttled := p.throttledJobs.Load(); throttled > 0 {
+			p.logger.Printf("Group concurrency limits throttled %d jobs", throttled)
+			for _, gs := range jobs.GroupLimiterStats() {
+				p.logger.Printf("Group %q: in_flight=%d, limit=%d, throttled=%d",
+					gs.Group, gs.InFlight, gs.Limit, gs.Throttled)
+			}
+		}
+	}
+}
+
+func (p *DynamicWorkerPool) waitForResultsUntil(deadline time.Time) {
+	for time.Now().Before(deadline) {
+		pending := p.tasksSubmitted.Load() - p.tasksCompleted.Load()
+		if pending <= 0 && len(p.resultChan) == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	p.resultsCloseOnce.Do(func() {
+		p.resultsClosed.Store(true)
+		close(p.resultChan)
+		if pending := p.tasksSubmitted.Load() - p.tasksCompleted.Load(); pending > 0 && p.logger != nil {
+			p.logger.Printf("Dropping %d results that were still pending at shutdown deadline", pending)
+		}
+	})
+}
+
+// dispatcher fetches batches of jobs from the queue and submits them to the ants pool.
+// Each iteration runs under the queue read lock so a concurrent ReplaceQueue
+// cannot swap (and close) the queue while a dequeued batch is being submitted.
+func (p *DynamicWorkerPool) dispatcher() {
+	defer p.wg.Done()
+	for {
+		p.queueMu.RLock()
+		q := p.queue
+
+		// Dispatch is paused; park until Resume (or a swap) wakes us.
+		if p.paused.Load() {
+			p.queueMu.RUnlock()
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-p.stopCh:
+				return
+			case <-p.queueChanged:
+			}
+			continue
+		}
+
+		// 1. Determine batch size
+		batchTarget := p.antsPool.Cap()
+		if batchTarget <= 0 {
+			batchTarget = p.config.MinWorkers
+		}
+		if batchTarget > p.config.ResultBatchSize {
+			batchTarget = p.config.ResultBatchSize
+		}
+		if batchTarget <= 0 {
+			batchTarget = 1
+		}
+
+		// 2. Try to dequeue
+		batch, err := q.DequeueBatch(batchTarget)
+		if err != nil {
+			p.queueMu.RUnlock()
+			if errors.Is(err, ErrQueueClosed) {
+				return
+			}
+			if p.logger != nil {
+				p.logger.Printf("Error dequeuing job batch: %v", err)
+			}
+			// On error, wait a bit to avoid tight loop
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-p.stopCh:
+				return
+			case <-time.After(100 * time.Millisecond):
+				continue
+			}
+		}
+
+		// 3. If batch found, process it
+		if len(batch) > 0 {
+			// Check if we're stopping before processing - avoid invoking on closed pool
+			if p.stopping.Load() != 0 {
+				p.queueMu.RUnlock()
+				return
+			}
+
+			// Record arrival times for Allen-Cunneen variance calculation
+			now := time.Now()
+			var submitted int64
+			for _, job := range batch {
+				// Re-check stopping flag during batch processing to exit quickly
+				if p.stopping.Load() != 0 {
+					break
+				}
+				// Per-group concurrency limit: jobs from a saturated group are
+				// re-enqueued with a small delay instead of dropped, so the
+				// queue keeps draining for other groups.
+				if gj, ok := job.(jobs.GroupedJob); ok {
+					if !jobs.GetGroupLimiter().TryAcquire(gj.ConcurrencyGroup()) {
+						p.throttledJobs.Add(1)
+						p.requeueThrottled(job)
+						continue
+					}
+				}
+				p.metrics.RecordArrival(now)
+				if err := p.antsPool.Invoke(job); err != nil {
+					if gj, ok := job.(jobs.GroupedJob); ok {
+						jobs.GetGroupLimiter().Release(gj.ConcurrencyGroup())
+					}
+					// Don't log "pool closed" errors during shutdown - they're expected
+					if p.stopping.Load() == 0 {
+						p.logger.Printf("Error invoking job: %v", err)
+					}
+					continue
+				}
+				submitted++
+			}
+			if submitted > 0 {
+				p.tasksSubmitted.Add(submitted)
+			}
+			p.queueMu.RUnlock()
+			// Immediately try to get next batch without waiting
+			continue
+		}
+		p.queueMu.RUnlock()
+
+		// 4. If empty, wait for signal
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.stopCh:
+			return
+		case <-q.Notify():
+			// Signal received, loop back to dequeue
+		case <-p.queueChanged:
+			// Queue replaced; re-read the reference.
+		}
+	}
+}
+
+// groupThrottleRequeueDelay is how long a throttled job stays off the
+// queue before the re-enqueue, giving the saturated group's in-flight
+// checks time to finish.
+const groupThrottleRequeueDelay = 25 * time.Millisecond
+
+// requeueThrottled puts a throttled job back on the queue after a short
+// delay, so a saturated group does not spin the dispatcher.
+func (p *DynamicWorkerPool) requeueThrottled(job jobs.Job) {
+	time.AfterFunc(groupThrottleRequeueDelay, func() {
+		if p.stopping.Load() != 0 {
+			return
+		}
+		if err := p.EnqueueActive(job); err != nil && p.logger != nil {
+			p.logger.Printf("Error re-enqueuing throttled job: %v", err)
+		}
+	})
+}
+
+// activeQueue returns the queue the pool currently dispatches from.
+func (p *DynamicWorkerPool) activeQueue() Queue {
+	p.queueMu.RLock()
+	defer p.queueMu.RUnlock()
+	return p.queue
+}
+
+// EnqueueActive enqueues a job on the pool's current queue under the queue
+// read lock, so a concurrent ReplaceQueue cannot close and drain the queue
+// between the reference read and the enqueue.
+func (p *DynamicWorkerPool) EnqueueActive(job jobs.Job) error {
+	p.queueMu.RLock()
+	defer p.queueMu.RUnlock()
+	return p.queue.Enqueue(job)
+}
+
+// ReplaceQueue atomically swaps the pool's queue for a new one without
+// losing work. Taking the write lock is the handshake with the dispatcher:
+// it holds the read lock across a full dequeue-and-submit iteration, so once
+// the write lock is acquired no batch from the old queue is mid-dispatch.
+// The old queue is closed and its remaining jobs are migrated to the new one
+// while the lock is still held, then returned (already closed) so the caller
+// can inspect its final stats.
+func (p *DynamicWorkerPool) ReplaceQueue(q Queue) Queue {
+	if q == nil {
+		return nil
+	}
+
+	batchSize := p.config.ResultBatchSize
+	if batchSize <= 0 {
+		batchSize = 256
+	}
+
+	p.queueMu.Lock()
+	old := p.queue
+	p.queue = q
+	if old != nil {
+		// Close first so racing enqueuers get ErrQueueClosed instead of
+		// landing jobs in a queue nobody will drain, then migrate what is
+		// left. HybridQueue and friends keep dequeuing after Close.
+		old.Close()
+		migrated := 0
+		for {
+			batch, err := old.DequeueBatch(batchSize)
+			for _, job := range batch {
+				if enqErr := q.Enqueue(job); enqErr != nil {
+					if p.logger != nil {
+						p.logger.Printf("Dropping job during queue replacement: %v", enqErr)
+					}
+					continue
+				}
+				migrated++
+			}
+			if err != nil || len(batch) == 0 {
+				break
+			}
+		}
+		if migrated > 0 && p.logger != nil {
+			p.logger.Printf("Queue replaced; migrated %d queued jobs", migrated)
+		}
+	}
+	p.queueMu.Unlock()
+
+	// A dispatcher parked on the old queue's Notify channel must re-read.
+	p.wakeDispatcher()
+	return old
+}
+
+// wakeDispatcher nudges a dispatcher parked in its wait select.
+func (p *DynamicWorkerPool) wakeDispatcher() {
+	select {
+	case p.queueChanged <- struct{}{}:
+	default:
+	}
+}
+
+// resultBatcher accumulates worker results into per-kind batches as they
+// arrive. The worker already tagged every result with its JobKind before it
+// reached the result channel, so grouping costs one array index per result;
+// a flush hands each result channel its single-kind slice directly. This
+// replaces the old scheme of collecting a mixed batch that RouteResults then
+// re-scanned and split into three freshly allocated slices.
+type resultBatcher struct {
+	router *ResultRouter
+	logger *log.Logger
+	kinds  [jobs.KindCode + 1]*[]jobs.Result
+	total  int
+}
+
+func newResultBatcher(router *ResultRouter, logger *log.Logger) *resultBatcher {
+	return &resultBatcher{router: router, logger: logger}
+}
+
+// add appends a result to its kind's batch and returns the total number of
+// results batched since the last flush. Unknown kinds are logged and dropped,
+// matching RouteResults.
+func (b *resultBatcher) add(r jobs.Result) int {
+	kind := r.Payload.Kind
+	if kind == jobs.KindUnknown || int(kind) >= len(b.kinds) {
+		if b.logger != nil {
+			b.logger.Printf("Unknown job kind in result: %v", kind)
+		}
+		return b.total
+	}
+	batch := b.kinds[kind]
+	if batch == nil {
+		batch = b.router.getBatch()
+		b.kinds[kind] = batch
+	}
+	*batch = append(*batch, r)
+	b.total++
+	return b.total
+}
+
+// flush routes every accumulated single-kind batch to its channel. The
+// consumer takes ownership of each sent slice (RecycleBatch returns it to
+// the pool); the batcher starts the next cycle empty.
+func (b *resultBatcher) flush() {
+	for kind, batch := range b.kinds {
+		if batch == nil {
+			continue
+		}
+		b.kinds[kind] = nil
+		if len(*batch) == 0 {
+			b.router.batchPool.Put(batch)
+			continue
+		}
+		b.router.RouteKind(jobs.JobKind(kind), *batch)
+		// The consumer owns the backing array now; only the empty box
+		// goes back for RecycleBatch to refill.
+		*batch = nil
+		b.router.boxPool.Put(batch)
+	}
+	b.total = 0
+}
+
+// resultProcessor collects individual results into per-kind batches and hands
+// them to the router. Uses time.AfterFunc for timeout-based flushing instead
+// of a ticker to avoid CPU burn when idle.
+func (p *DynamicWorkerPool) resultProcessor() {
+	defer p.wg.Done()
+
+	batcher := newResultBatcher(p.router, p.logger)
+	var flushTimer *time.Timer
+	flushCh := make(chan struct{}, 1) // Buffered to avoid blocking timer
+
+	// Helper to start/reset the flush timer
+	startTimer := func() {
+		if flushTimer != nil {
+			flushTimer.Stop()
+		}
+		flushTimer = time.AfterFunc(p.config.ResultBatchTimeout, func() {
+			select {
+			case flushCh <- struct{}{}:
+			default: // Already pending
+			}
+		})
+	}
+
+	// Helper to stop timer
+	stopTimer := func() {
+		if flushTimer != nil {
+			flushTimer.Stop()
+			flushTimer = nil
+		}
+	}
+	defer stopTimer()
+
+	draining := false
+	for {
+		select {
+		case <-p.stopCh:
+			draining = true
+		case <-p.ctx.Done():
+			draining = true
+		case result, ok := <-p.resultChan:
+			if !ok { // resultChan was closed
+				stopTimer()
+				batcher.flush()
+				return
+			}
+			p.tasksCompleted.Add(1)
+
+			// Start timer on first item (efficient: no timer when empty)
+			if batcher.add(result) == 1 {
+				startTimer()
+			}
+
+			// Flush immediately when batch is full
+			if batcher.total >= p.config.ResultBatchSize {
+				stopTimer()
+				batcher.flush()
+			}
+		case <-flushCh:
+			// Timer fired - flush partial batch
+			batcher.flush()
+		}
+
+		if draining {
+			stopTimer()
+			if batcher.total > 0 && len(p.resultChan) == 0 {
+				batcher.flush()
+				return
+			}
+			if batcher.total == 0 && len(p.resultChan) == 0 {
+				return
+			}
+		}
+	}
+}
+
+// executeJob runs the job, converting a panic into a synthetic failure
+// Result for the job's entity. Without this a panicked job produced only a
+// log line: the monitor stayed pending until a watchdog noticed, and
+// nothing recorded which monitor's job crashed. The recovered panic value
+// and stack are logged, counted, and retained for Stats.
+func (p *DynamicWorkerPool) executeJob(ctx context.Context, j jobs.Job) (res jobs.Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg := fmt.Sprintf("%v", r)
+			p.panicCount.Add(1)
+			p.lastPanic.Store(msg)
+			if p.logger != nil {
+				p.logger.Printf("Worker panic recovered, failing entity %d: %v\n%s",
+					j.GetEntity().ID(), r, debug.Stack())
+			}
+			res = jobs.Result{
+				Ent:     j.GetEntity(),
+				Err:     fmt.Errorf("%w: %s", jobs.ErrJobPanicked, msg),
+				Payload: jobs.Payload{Kind: p.config.Kind},
+			}
+			res.Payload.SetExtra("panic", msg)
+		}
+	}()
+	return j.Execute(ctx)
+}
+
+// deliverResult attempts to send a result without blocking shutdown.
+// Results are dropped when the pool is stopping to avoid deadlocks.
+// Logging is rate-limited to avoid spam during shutdown with many in-flight jobs.
+func (p *DynamicWorkerPool) deliverResult(result jobs.Result) {
+	backoff := p.config.ResultBatchTimeout
+	if backoff <= 0 {
+		backoff = 10 * time.Millisecond
+	}
+
+	attempts := 0
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	for {
+		if p.resultsClosed.Load() {
+			p.tasksCompleted.Add(1)
+			return
+		}
+
+		select {
+		case p.resultChan <- result:
+			return
+		default:
+		}
+
+		// Wait with reusable timer to avoid per-loop allocations
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(backoff)
+
+		select {
+		case p.resultChan <- result:
+			return
+		case <-p.stopCh:
+			// Rate-limited logging: only log the first drop to avoid spam
+			dropped := p.droppedResults.Add(1)
+			if p.logger != nil && dropped == 1 {
+				p.logger.Printf("Dropping results during shutdown (result channel stalled)")
+			}
+			p.tasksCompleted.Add(1)
+			return
+		case <-timer.C:
+			if p.stopping.Load() == 0 {
+				continue
+			}
+			attempts++
+		}
+
+		if attempts >= 5 {
+			// Rate-limited logging for timeout drops
+			dropped := p.droppedResults.Add(1)
+			if p.logger != nil && dropped == 1 {
+				p.logger.Printf("Dropping results during shutdown (send timeout)")
+			}
+			p.tasksCompleted.Add(1)
+			return
+		}
+	}
+}
+
+// autoScale periodically tunes the ants pool capacity using M/M/c queueing theory.
+// Implements hysteresis and asymmetric cooldowns to prevent oscillation.
+func (p *DynamicWorkerPool) autoScale() {
+	defer p.wg.Done()
+
+	ticker := p.config.Clock.NewTicker(p.config.AdjustmentInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.stopCh:
+			return
+		case <-ticker.C():
+			p.runScaleCycle()
+		}
+	}
+}
+
+// runScaleCycle performs one autoscale evaluation: it records metrics,
+// computes the desired capacity, and applies the change if cooldowns,
+// thresholds, and the wait-time hysteresis allow it. Called from the
+// autoScale goroutine on every adjustment interval.
+func (p *DynamicWorkerPool) runScaleCycle() {
+	// Skip scaling during warmup period
+	if p.config.Clock.Now().Sub(p.startTime) < p.config.WarmupDuration {
+		return
+	}
+
+	stats := p.activeQueue().Stats()
+
+	// Record metrics to rolling windows
+	p.metrics.Record(stats)
+	p.metrics.RecordUtilization(p.antsPool.Running(), p.antsPool.Cap())
+
+	// Track how long the measured queue wait has stayed comfortably under
+	// target; scale-down requires a sustained run of low-wait intervals.
+	if stats.AvgQueueTime < p.config.TargetQueueLatency/2 {
+		p.lowWaitIntervals++
+	} else {
+		p.lowWaitIntervals = 0
+	}
+
+	desired, predictedW, reason := p.desiredCapacity(stats)
+	p.recordScaleDecision(desired, predictedW, reason)
+	current := p.antsPool.Cap()
+
+	// No change needed
+	if desired == current {
+		return
+	}
+
+	now := p.config.Clock.Now()
+
+	if desired > current {
+		// SCALE UP: Check hysteresis threshold
+		ratio := float64(desired) / float64(current)
+		if ratio < p.config.ScaleUpThreshold {
+			// Change too small - skip to prevent oscillation
+			return
+		}
+
+		// Check cooldown period
+		if now.Sub(p.lastScaleUpTime) < p.config.ScaleUpCooldown {
+			return
+		}
+
+		// Apply scale up
+		p.antsPool.Tune(desired)
+		p.lastScaleUpTime = now
+		p.lastTarget.Store(int64(desired))
+		p.lastScaleTime.Store(now.UnixNano())
+		p.scalingEvents.Add(1)
+
+		if p.logger != nil {
+			p.logger.Printf("Scaled UP %s pool: %d → %d (reason=%s, predicted_w=%v, wait=%v, queue=%d)",
+				p.name(), current, desired, reason, secondsToDuration(predictedW), stats.AvgQueueTime, stats.QueueDepth)
+		}
+	} else {
+		// SCALE DOWN: More conservative - check hysteresis threshold
+		ratio := float64(desired) / float64(current)
+		if ratio > p.config.ScaleDownThreshold {
+			// Change too small - skip to prevent oscillation
+			return
+		}
+
+		// Wait-time hysteresis: only shed capacity once the measured wait
+		// has stayed under half of target for enough consecutive intervals.
+		if p.lowWaitIntervals < p.config.ScaleDownHoldIntervals {
+			p.recordScaleDecision(desired, predictedW, scaleReasonHysteresis)
+			return
+		}
+
+		// Use long window to ensure sustained low utilization
+		longMetrics := p.metrics.GetLongWindowMetrics()
+		if longMetrics.SampleCount < 10 {
+			// Not enough history - don't scale down yet
+			return
+		}
+		if longMetrics.AvgUtilization > 0.25 {
+			// Still moderately utilized over long term - don't scale down
+			return
+		}
+		if longMetrics.AvgQueueDepth > float64(p.config.MinWorkers) {
+			// Queue not consistently empty - don't scale down
+			return
+		}
+
+		// Check longer cooldown period for scale down
+		if now.Sub(p.lastScaleDownTime) < p.config.ScaleDownCooldown {
+			return
+		}
+
+		// Apply scale down
+		p.antsPool.Tune(desired)
+		p.lastScaleDownTime = now
+		p.lastTarget.Store(int64(desired))
+		p.lastScaleTime.Store(now.UnixNano())
+		p.scalingEvents.Add(1)
+
+		if p.logger != nil {
+			p.logger.Printf("Scaled DOWN %s pool: %d → %d (reason=%s, predicted_w=%v, wait=%v, longUtil=%.2f)",
+				p.name(), current, desired, reason, secondsToDuration(predictedW), stats.AvgQueueTime, longMetrics.AvgUtilization)
+		}
+	}
+}
+
+// recordScaleDecision stores the latest autoscale decision for Stats().
+func (p *DynamicWorkerPool) recordScaleDecision(desired int, predictedW float64, reason string) {
+	p.lastDesiredC.Store(int64(desired))
+	p.lastPredictedW.Store(int64(predictedW * float64(time.Second)))
+	p.lastScaleReason.Store(reason)
+}
+
+// secondsToDuration converts a wait time in seconds (as returned by the
+// M/M/c helpers) to a time.Duration for logging and stats.
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// desiredCapacity computes the target worker count, the M/M/c predicted
+// total time in system at that count (seconds, 0 when unavailable), and the
+// reason the decision path was taken ("slo" or "heuristic").
+//
+// The primary path targets the measured queue wait: it finds the minimal c
+// for which the predicted time in system stays within TargetQueueLatency
+// plus one service time, i.e. AvgQueueTime ≤ TargetQueueLatency. The
+// depth/rate heuristic is used only when the rate windows are too noisy
+// (fewer than MinScalingSamples samples) or the M/M/c model has no inputs.
+func (p *DynamicWorkerPool) desiredCapacity(stats Stats) (int, float64, string) {
+	current := p.antsPool.Cap()
+	if current <= 0 {
+		current = p.config.MinWorkers
+	}
+
+	minWorkers := p.config.MinWorkers
+	maxWorkers := p.config.MaxWorkers
+	if maxWorkers < minWorkers {
+		maxWorkers = minWorkers
+	}
+
+	// 1. Get observed arrival rate from short window (reacts to spikes)
+	short := p.metrics.GetShortWindowMetrics()
+	lambda := short.AvgEnqueueRate
+	if lambda <= 0 {
+		lambda = stats.EnqueueRate
+	}
+	if lambda <= 0 {
+		// No load - return minimum
+		return minWorkers, 0, scaleReasonHeuristic
+	}
+
+	// 2. Too few rate samples to trust the model - use the heuristic
+	if short.SampleCount < p.config.MinScalingSamples {
+		return p.littleLawFallback(lambda, stats, current), 0, scaleReasonHeuristic
+	}
+
+	// 3. Estimate service time (τ) from observed throughput
+	tau := p.estimateServiceTime(stats, current)
+	if tau <= 0 {
+		// No throughput data yet - use heuristic from Little's Law fallback
+		return p.littleLawFallback(lambda, stats, current), 0, scaleReasonHeuristic
+	}
+
+	// 4. Get variability coefficients for Allen-Cunneen
+	ca, cs := p.metrics.GetVariabilityCoefficients()
+	// Defaults already set to 1.0 by GetVariabilityCoefficients() if insufficient data
+
+	// 5. Calculate optimal worker count using M/M/c + Allen-Cunneen.
+	// TargetQueueLatency bounds the time spent waiting in queue (Wq), so
+	// the total-time target passed to the solver adds one service time.
+	wTarget := p.config.TargetQueueLatency.Seconds() + tau
+	cTheory, predictedW, err := FindCForSLO(lambda, tau, wTarget, ca, cs, maxWorkers)
+
+	// 6. Fallback if M/M/c calculation fails (e.g., unstable system)
+	if err != nil {
+		if p.logger != nil {
+			p.logger.Printf("M/M/c calculation failed (λ=%.2f, τ=%.3fs): %v; using Little's Law fallback",
+				lambda, tau, err)
+		}
+		return p.littleLawFallback(lambda, stats, current), 0, scaleReasonHeuristic
+	}
+
+	// 7. Add 15% headroom for safety margin and clamp
+	desired := clamp(int(math.Ceil(float64(cTheory)*1.15)), minWorkers, maxWorkers)
+	if desired != cTheory {
+		// Report the prediction for the capacity actually requested.
+		if _, w, werr := MmcWait(lambda, 1.0/tau, desired, ca, cs); werr == nil {
+			predictedW = w
+		}
+	}
+	return desired, predictedW, scaleReasonSLO
+}
+
+// estimateServiceTime estimates the average service time per job from observed metrics.
+// It prefers actual measured service times when available, falling back to throughput-based
+// estimation only when insufficient measurement data exists.
+func (p *DynamicWorkerPool) estimateServiceTime(stats Stats, currentWorkers int) float64 {
+	// Prefer actual measured service times (recorded in workerFunc)
+	measuredTau := p.metrics.GetAverageServiceTime()
+	if measuredTau > 0 {
+		// Sanity bounds: tau should be between 1ms and 30s
+		if measuredTau < 0.001 {
+			measuredTau = 0.001
+		}
+		if measuredTau > 30.0 {
+			measuredTau = 30.0
+		}
+		return measuredTau
+	}
+
+	// Fallback: estimate from throughput if no measured data yet
+	if currentWorkers <= 0 || stats.DequeueRate <= 0 {
+		return 0
+	}
+
+	running := p.antsPool.Running()
+	if running <= 0 {
+		running = 1
+	}
+
+	// Service time = active workers / throughput
+	// This is only used during initial warmup before we have measurements
+	tau := float64(running) / stats.DequeueRate
+
+	// Conservative bounds for fallback estimation
+	if tau < 0.001 {
+		tau = 0.001
+	}
+	if tau > 1.0 {
+		// Cap fallback at 1s - if jobs really take longer, measurements will show it
+		tau = 1.0
+	}
+	return tau
+}
+
+// littleLawFallback uses Little's Law (L = λW) as a fallback scaling heuristic.
+func (p *DynamicWorkerPool) littleLawFallback(lambda float64, stats Stats, current int) int {
+	minWorkers := p.config.MinWorkers
+	maxWorkers := p.config.MaxWorkers
+	targetLatency := p.config.TargetQueueLatency
+	if targetLatency <= 0 {
+		targetLatency = 100 * time.Millisecond
+	}
+
+	desired := current
+
+	// Estimate per-worker throughput
+	perWorker := 0.0
+	if current > 0 && stats.DequeueRate > 0 {
+		perWorker = stats.DequeueRate / float64(current)
+	}
+	if perWorker > 0 && lambda > 0 {
+		desired = int(math.Ceil(lambda / perWorker))
+	}
+
+	// Enforce latency budget: target queue depth = λ * W_target
+	targetDepth := lambda * targetLatency.Seconds()
+	if targetDepth < float64(minWorkers) {
+		targetDepth = float64(minWorkers)
+	}
+
+	depth := float64(stats.QueueDepth)
+	if depth > targetDepth && targetDepth > 0 {
+		// Queue building up - scale up proportionally
+		scale := depth / targetDepth
+		desired = int(math.Ceil(float64(desired) * scale))
+	}
+
+	return clamp(desired, minWorkers, maxWorkers)
+}
+
+// clamp constrains a value between min and max bounds.
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// Stats returns runtime statistics for the worker pool.
+func (p *DynamicWorkerPool) Stats() WorkerPoolStats {
+	return WorkerPoolStats{
+		MinWorkers:      p.config.MinWorkers,
+		MaxWorkers:      p.config.MaxWorkers,
+		CurrentCapacity: p.antsPool.Cap(),
+		RunningWorkers:  p.antsPool.Running(),
+		WaitingTasks:    p.antsPool.Waiting(),
+		TargetWorkers:   int(p.lastTarget.Load()),
+		TasksSubmitted:  p.tasksSubmitted.Load(),
+		TasksCompleted:  p.tasksCompleted.Load(),
+		ScalingEvents:   p.scalingEvents.Load(),
+		LastScaleTime:   time.Unix(0, p.lastScaleTime.Load()),
+		PendingResults:  len(p.resultChan),
+		SpilledBatches:  p.router.SpilledBatches(),
+		ThrottledJobs:   p.throttledJobs.Load(),
+		Groups:          jobs.GroupLimiterStats(),
+		Panics:          p.panicCount.Load(),
+		LastPanic:       p.lastPanicMessage(),
+		DesiredWorkers:  int(p.lastDesiredC.Load()),
+		PredictedWait:   time.Duration(p.lastPredictedW.Load()),
+		ScaleReason:     p.scaleReason(),
+	}
+}
+
+// lastPanicMessage returns the most recent recovered panic message, or the
+// empty string before the first one.
+func (p *DynamicWorkerPool) lastPanicMessage() string {
+	if m, ok := p.lastPanic.Load().(string); ok {
+		return m
+	}
+	return ""
+}
+
+// scaleReason returns the reason recorded by the last autoscale evaluation,
+// or the empty string before the first one runs.
+func (p *DynamicWorkerPool) scaleReason() string {
+	if r, ok := p.lastScaleReason.Load().(string); ok {
+		return r
+	}
+	return ""
+}
+
+// name returns the configured pool name for log attribution, defaulting to
+// "worker" for pools created without one.
+func (p *DynamicWorkerPool) name() string {
+	if p.config.Name != "" {
+		return p.config.Name
+	}
+	return "worker"
+}
+
+// Tune adjusts the worker pool capacity to the specified number of workers.
+// This is used for initial pre-sizing based on M/M/c calculations.
+// The capacity is clamped between MinWorkers and MaxWorkers.
+func (p *DynamicWorkerPool) Tune(capacity int) {
+	if capacity < p.config.MinWorkers {
+		capacity = p.config.MinWorkers
+	}
+	if capacity > p.config.MaxWorkers {
+		capacity = p.config.MaxWorkers
+	}
+	current := p.antsPool.Cap()
+	if capacity != current {
+		p.antsPool.Tune(capacity)
+		p.lastTarget.Store(int64(capacity))
+		p.lastScaleTime.Store(p.config.Clock.Now().UnixNano())
+		p.scalingEvents.Add(1)
+		if p.logger != nil {
+			p.logger.Printf("Pre-sized worker pool from %d to %d workers", current, capacity)
+		}
+	}
+}
+
+// TuneRamp raises the pool capacity to target in equal steps spread over
+// the given duration instead of one jump, so a freshly pre-sized pool does
+// not slam downstream targets with thousands of simultaneous checks. The
+// first step applies immediately; the rest follow at the pool's adjustment
+// interval on its own goroutine, stopping early on shutdown. Steps never
+// shrink capacity, so an autoscaler decision that already climbed past the
+// next step is left alone. Shrinking targets and non-positive durations
+// fall back to an immediate Tune.
+func (p *DynamicWorkerPool) TuneRamp(target int, over time.Duration) {
+	if target > p.config.MaxWorkers {
+		target = p.config.MaxWorkers
+	}
+	current := p.antsPool.Cap()
+	if over <= 0 || target <= current {
+		p.Tune(target)
+		return
+	}
+	interval := p.config.AdjustmentInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	steps := int(over / interval)
+	if steps < 1 {
+		steps = 1
+	}
+
+	// First step now; the remainder on the ramp goroutine. The ticker is
+	// created before the goroutine starts so no step interval can elapse
+	// unobserved between TuneRamp returning and the ramp running.
+	p.Tune(current + (target-current)/steps)
+	if steps == 1 {
+		return
+	}
+	go p.ramp(p.config.Clock.NewTicker(interval), current, target, steps)
+}
+
+// ramp applies steps 2..steps of a TuneRamp schedule.
+func (p *DynamicWorkerPool) ramp(ticker clock.Ticker, from, target, steps int) {
+	defer ticker.Stop()
+	for i := 2; i <= steps; i++ {
+		select {
+		case <-ticker.C():
+		case <-p.stopCh:
+			return
+		}
+		next := from + (target-from)*i/steps
+		if p.antsPool.Cap() >= next {
+			continue
+		}
+		p.Tune(next)
+	}
+}
+
+// Pause stops the dispatcher from picking up new jobs and waits until the
+// iteration in progress has finished submitting. Workers keep executing
+// already-submitted jobs; call Resume to continue dispatching.
+func (p *DynamicWorkerPool) Pause() {
+	if p.paused.Swap(true) {
+		return
+	}
+	// Barrier: the dispatcher holds the read lock across an iteration, so
+	// holding the write lock means it has finished the current one and the
+	// next will observe the pause flag.
+	p.queueMu.Lock()
+	p.queueMu.Unlock() //nolint:staticcheck // empty section is the handshake
+	if p.logger != nil {
+		p.logger.Println("DynamicWorkerPool paused")
+	}
+}
+
+// Resume resumes worker pool processing after a pause.
+func (p *DynamicWorkerPool) Resume() {
+	if !p.paused.Swap(false) {
+		return
+	}
+	p.wakeDispatcher()
+	if p.logger != nil {
+		p.logger.Println("DynamicWorkerPool resumed")
+	}
+}
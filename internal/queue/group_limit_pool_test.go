@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+
+	"go.uber.org/zap"
+)
+
+// groupedTestJob implements jobs.GroupedJob and records the per-group
+// concurrency it observed while executing.
+type groupedTestJob struct {
+	enqueueTime time.Time
+	startTime   time.Time
+	group       string
+	tracker     *groupConcurrencyTracker
+}
+
+// groupConcurrencyTracker records the maximum in-flight executions seen
+// per group across a pool run.
+type groupConcurrencyTracker struct {
+	mu      sync.Mutex
+	current map[string]int
+	max     map[string]int
+}
+
+func newGroupConcurrencyTracker() *groupConcurrencyTracker {
+	return &groupConcurrencyTracker{
+		current: make(map[string]int),
+		max:     make(map[string]int),
+	}
+}
+
+func (t *groupConcurrencyTracker) enter(group string) {
+	t.mu.Lock()
+	t.current[group]++
+	if t.current[group] > t.max[group] {
+		t.max[group] = t.current[group]
+	}
+	t.mu.Unlock()
+}
+
+func (t *groupConcurrencyTracker) leave(group string) {
+	t.mu.Lock()
+	t.current[group]--
+	t.mu.Unlock()
+}
+
+func (t *groupConcurrencyTracker) maxFor(group string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.max[group]
+}
+
+func (j *groupedTestJob) Execute(_ context.Context) jobs.Result {
+	j.tracker.enter(j.group)
+	// Hold the slot long enough that over-admission would be observable.
+	time.Sleep(10 * time.Millisecond)
+	j.tracker.leave(j.group)
+	return jobs.Result{Payload: jobs.Payload{Kind: jobs.KindPulse}}
+}
+func (j *groupedTestJob) Copy() jobs.Job             { cp := *j; return &cp }
+func (j *groupedTestJob) GetEnqueueTime() time.Time  { return j.enqueueTime }
+func (j *groupedTestJob) SetEnqueueTime(t time.Time) { j.enqueueTime = t }
+func (j *groupedTestJob) GetStartTime() time.Time    { return j.startTime }
+func (j *groupedTestJob) SetStartTime(t time.Time)   { j.startTime = t }
+func (j *groupedTestJob) GetEntity() ecs.Entity      { return ecs.Entity{} }
+func (j *groupedTestJob) IsNil() bool                { return j == nil }
+func (j *groupedTestJob) ConcurrencyGroup() string   { return j.group }
+
+// TestDynamicWorkerPool_GroupConcurrencyLimits runs two groups with
+// different limits through a real pool and asserts the maximum observed
+// concurrency per group never exceeds its limit. Not parallel: the group
+// limiter is global.
+func TestDynamicWorkerPool_GroupConcurrencyLimits(t *testing.T) {
+	jobs.SetGroupLimits(map[string]int{
+		"payments-api": 2,
+		"search":       5,
+	})
+	defer jobs.SetGroupLimits(nil)
+
+	q, err := NewHybridQueue(HybridQueueConfig{
+		Name:             "group-limit-test",
+		RingCapacity:     256,
+		OverflowCapacity: 256,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+
+	config := WorkerPoolConfig{
+		MinWorkers:         16,
+		MaxWorkers:         32,
+		AdjustmentInterval: 0, // No auto-scaling during the test
+		ResultBatchSize:    16,
+		ResultBatchTimeout: 5 * time.Millisecond,
+	}
+	pool, err := NewDynamicWorkerPool(context.Background(), q, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	pool.Start()
+
+	const jobsPerGroup = 30
+	tracker := newGroupConcurrencyTracker()
+	for i := 0; i < jobsPerGroup; i++ {
+		for _, group := range []string{"payments-api", "search"} {
+			if err := q.Enqueue(&groupedTestJob{group: group, tracker: tracker}); err != nil {
+				t.Fatalf("enqueue failed: %v", err)
+			}
+		}
+	}
+
+	// Drain results until every job (including re-enqueued throttled ones)
+	// has executed.
+	var received atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for batch := range pool.GetRouter().PulseResultChan {
+			if received.Add(int64(len(batch))) >= 2*jobsPerGroup {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("timed out: received %d of %d results", received.Load(), 2*jobsPerGroup)
+	}
+	pool.DrainAndStop()
+
+	if max := tracker.maxFor("payments-api"); max > 2 {
+		t.Errorf("payments-api max concurrency = %d, exceeds limit 2", max)
+	}
+	if max := tracker.maxFor("search"); max > 5 {
+		t.Errorf("search max concurrency = %d, exceeds limit 5", max)
+	}
+
+	stats := pool.Stats()
+	// 60 short jobs against limits of 2 and 5 with 16+ workers must throttle.
+	if stats.ThrottledJobs == 0 {
+		t.Error("expected ThrottledJobs > 0")
+	}
+	if len(stats.Groups) != 2 {
+		t.Errorf("Groups stats length = %d, want 2", len(stats.Groups))
+	}
+	t.Logf("throttled=%d, payments-api max=%d, search max=%d",
+		stats.ThrottledJobs, tracker.maxFor("payments-api"), tracker.maxFor("search"))
+}
@@ -0,0 +1,975 @@
+package queue
+
+import (
+	"cpra/internal/jobs"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mlange-42/ark/ecs"
+	"github.com/puzpuzpuz/xsync/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRingCapacity     = 1 << 17 // 131,072
+	defaultOverflowCapacity = 1 << 15 // 32,768
+	defaultSoftWatermark    = 0.90
+	defaultHardWatermark    = 1.0
+	defaultPriorityRatio    = 4
+)
+
+// Priority identifies the dequeue lane for a job when priority lanes are
+// enabled (see HybridQueueConfig.Classify).
+type Priority int
+
+const (
+	// PriorityNormal jobs drain after the high lane, subject to the
+	// starvation guard.
+	PriorityNormal Priority = iota
+	// PriorityHigh jobs preempt normal work.
+	PriorityHigh
+)
+
+// DefaultPriorityClassifier routes routine pulse checks to the normal lane
+// and everything else (interventions, code alerts) to the high lane, so
+// urgent work preempts a pulse backlog when queues are shared.
+func DefaultPriorityClassifier(job jobs.Job) Priority {
+	switch job.(type) {
+	case *jobs.PulseHTTPJob, *jobs.PulseTCPJob, *jobs.PulseICMPJob,
+		*jobs.PulseNTPJob, *jobs.PulseDNSJob, *jobs.PulseGRPCJob:
+		return PriorityNormal
+	}
+	return PriorityHigh
+}
+
+// DefaultPulseConflateKey extracts the conflation key from the standard
+// pulse job types. Jobs it does not recognise, or that carry the zero
+// entity (not tied to a monitor), never conflate.
+func DefaultPulseConflateKey(job jobs.Job) (ecs.Entity, bool) {
+	var ent ecs.Entity
+	switch j := job.(type) {
+	case *jobs.PulseHTTPJob:
+		ent = j.Entity
+	case *jobs.PulseTCPJob:
+		ent = j.Entity
+	case *jobs.PulseICMPJob:
+		ent = j.Entity
+	case *jobs.PulseNTPJob:
+		ent = j.Entity
+	case *jobs.PulseDNSJob:
+		ent = j.Entity
+	case *jobs.PulseGRPCJob:
+		ent = j.Entity
+	default:
+		return ecs.Entity{}, false
+	}
+	if ent == (ecs.Entity{}) {
+		return ecs.Entity{}, false
+	}
+	return ent, true
+}
+
+// DropPolicy defines how the hybrid queue behaves once both the ring and overflow paths are saturated.
+type DropPolicy int
+
+const (
+	// DropPolicyReject rejects new jobs when the queue is full.
+	DropPolicyReject DropPolicy = iota
+	// DropPolicyDropNewest drops the just-arrived job when capacity is exceeded.
+	DropPolicyDropNewest
+	// DropPolicyDropOldest evicts the oldest overflow job to admit a new job.
+	DropPolicyDropOldest
+)
+
+func (p DropPolicy) String() string {
+	switch p {
+	case DropPolicyReject:
+		return "reject"
+	case DropPolicyDropNewest:
+		return "drop_newest"
+	case DropPolicyDropOldest:
+		return "drop_oldest"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseDropPolicy maps a policy's configuration spelling ("reject",
+// "drop_newest", "drop_oldest") back to its value.
+func ParseDropPolicy(name string) (DropPolicy, error) {
+	switch name {
+	case "reject":
+		return DropPolicyReject, nil
+	case "drop_newest":
+		return DropPolicyDropNewest, nil
+	case "drop_oldest":
+		return DropPolicyDropOldest, nil
+	default:
+		return DropPolicyReject, fmt.Errorf("unknown drop policy %q (want reject, drop_newest, or drop_oldest)", name)
+	}
+}
+
+// HybridQueueConfig controls the behaviour of a HybridQueue instance.
+type HybridQueueConfig struct {
+	Logger           *zap.Logger
+	Name             string
+	RingCapacity     int
+	OverflowCapacity int
+	SoftWatermark    float64
+	HardWatermark    float64
+	DropPolicy       DropPolicy
+
+	// Classify enables priority lanes: jobs it maps to PriorityHigh land in
+	// a dedicated ring that drains before normal work. Nil (the default)
+	// keeps the single-lane behaviour, so existing callers are unaffected.
+	Classify func(jobs.Job) Priority
+	// PriorityRatio is the starvation guard: at most this many consecutive
+	// high-lane dequeues before a waiting normal job is served. Zero or
+	// negative uses the default of 4.
+	PriorityRatio int
+
+	// ConflateKey enables duplicate conflation: when it maps a job to an
+	// entity that already has a job in the queue, the enqueue is skipped and
+	// counted in Stats.Conflated instead of queueing redundant work. Nil
+	// (the default) disables conflation; intervention and code queues must
+	// leave it nil, since every one of their jobs carries distinct intent.
+	ConflateKey func(jobs.Job) (ecs.Entity, bool)
+
+	// LatencyWindow is the decay window for the wait-time percentiles in
+	// Stats: samples older than two windows no longer influence them.
+	// Zero or negative uses the default of 5 minutes.
+	LatencyWindow time.Duration
+}
+
+// DefaultHybridQueueConfig returns the recommended production defaults.
+func DefaultHybridQueueConfig() HybridQueueConfig {
+	return HybridQueueConfig{
+		Name:             "hybrid",
+		RingCapacity:     defaultRingCapacity,
+		OverflowCapacity: defaultOverflowCapacity,
+		SoftWatermark:    defaultSoftWatermark,
+		HardWatermark:    defaultHardWatermark,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+		LatencyWindow:    defaultLatencyWindow,
+	}
+}
+
+// HybridQueue combines a lock-free xsync ring buffer with a mutex-protected overflow slice.
+// The ring handles steady-state work while the overflow absorbs bursts before optional dropping.
+type HybridQueue struct {
+	ring              *xsync.MPMCQueue[jobs.Job]
+	highRing          *xsync.MPMCQueue[jobs.Job] // nil unless priority lanes are enabled
+	logger            *zap.Logger
+	signal            chan struct{}
+	overflow          []jobs.Job
+	cfg               HybridQueueConfig
+	softOverflowLimit int
+	hardOverflowLimit int
+	startNano         atomic.Int64
+	ringDepth         atomic.Int64
+	highDepth         atomic.Int64
+	highDequeued      atomic.Int64
+	normalDequeued    atomic.Int64
+	highStreak        atomic.Int64
+	overflowDepth     atomic.Int64
+	enqueuedCount     atomic.Int64
+	dequeuedCount     atomic.Int64
+	droppedCount      atomic.Int64
+	overflowEvents    atomic.Uint64
+	totalQueueWait    atomic.Int64
+	maxQueueWait      atomic.Int64
+	// waitHist is the windowed histogram behind the wait percentiles in
+	// Stats; see HybridQueueConfig.LatencyWindow for the decay window.
+	waitHist            *latencyHistogram
+	lastEnqueueNano     atomic.Int64
+	lastDequeueNano     atomic.Int64
+	mu                  sync.Mutex
+	delayed             delayedJobs
+	inQueue             *xsync.Map[ecs.Entity, struct{}] // nil unless conflation is enabled
+	conflatedCount      atomic.Int64
+	closed              atomic.Bool
+	softOverflowAlerted atomic.Bool
+	hardOverflowAlerted atomic.Bool
+	ringSaturated       atomic.Bool
+}
+
+// NewHybridQueue builds a HybridQueue using the supplied configuration.
+func NewHybridQueue(config HybridQueueConfig) (*HybridQueue, error) {
+	cfg := normalizeHybridConfig(config)
+
+	if cfg.RingCapacity <= 0 {
+		return nil, errors.New("hybrid queue: ring capacity must be positive")
+	}
+	if cfg.OverflowCapacity < 0 {
+		return nil, errors.New("hybrid queue: overflow capacity cannot be negative")
+	}
+
+	queue := &HybridQueue{
+		ring:     xsync.NewMPMCQueue[jobs.Job](cfg.RingCapacity),
+		cfg:      cfg,
+		logger:   cfg.Logger,
+		signal:   make(chan struct{}, 1),
+		waitHist: newLatencyHistogram(cfg.LatencyWindow),
+	}
+	if cfg.Classify != nil {
+		queue.highRing = xsync.NewMPMCQueue[jobs.Job](cfg.RingCapacity)
+	}
+	if cfg.ConflateKey != nil {
+		queue.inQueue = xsync.NewMap[ecs.Entity, struct{}]()
+	}
+	if cfg.OverflowCapacity > 0 {
+		queue.overflow = make([]jobs.Job, 0, cfg.OverflowCapacity)
+	}
+	queue.softOverflowLimit = computeWatermarkLimit(cfg.OverflowCapacity, cfg.SoftWatermark)
+	queue.hardOverflowLimit = computeWatermarkLimit(cfg.OverflowCapacity, cfg.HardWatermark)
+	if queue.hardOverflowLimit == 0 && cfg.OverflowCapacity > 0 {
+		queue.hardOverflowLimit = cfg.OverflowCapacity
+	}
+	if queue.hardOverflowLimit > 0 && queue.softOverflowLimit > queue.hardOverflowLimit {
+		queue.softOverflowLimit = queue.hardOverflowLimit
+	}
+	queue.startNano.Store(time.Now().UnixNano())
+
+	return queue, nil
+}
+
+// Enqueue adds a single job to the queue, preferring the lock-free ring fast
+// path. The queue takes ownership of a private copy; the caller's instance
+// is never stamped or retained, so shared prototypes are safe to pass.
+func (q *HybridQueue) Enqueue(job jobs.Job) error {
+	_, _, err := q.enqueue(job)
+	return err
+}
+
+// enqueue admits a job and reports the instance actually inserted along
+// with the overflow job a DropPolicyDropOldest eviction displaced, if any,
+// so EnqueueBatch can keep per-item acceptance accurate.
+func (q *HybridQueue) enqueue(job jobs.Job) (jobs.Job, jobs.Job, error) {
+	if q.closed.Load() {
+		return nil, nil, ErrQueueClosed
+	}
+
+	now := time.Now()
+
+	// Ownership: the scheduler hands us the shared prototype stored in
+	// JobStorage and re-enqueues it every cycle. Stamp a private copy so a
+	// concurrent enqueue of the same monitor's job (watchdog reschedule
+	// racing the normal path) never mutates one struct from two goroutines.
+	if !isNilJob(job) {
+		job = job.Copy()
+		job.SetEnqueueTime(now)
+	}
+
+	// Conflation: at most one queued job per entity. A duplicate reports
+	// success without enqueueing — the entity's work is already queued.
+	tracked, conflated := q.markQueued(job)
+	if conflated {
+		q.conflatedCount.Add(1)
+		return nil, nil, nil
+	}
+
+	// High-lane jobs get their own ring; when it overflows they share the
+	// overflow path, which drains before both rings anyway.
+	if q.highRing != nil && q.cfg.Classify(job) == PriorityHigh {
+		if q.highRing.TryEnqueue(job) {
+			q.highDepth.Add(1)
+			q.recordEnqueue(now)
+			q.notify()
+			return job, nil, nil
+		}
+		evicted, err := q.enqueueOverflow(job, now)
+		if err != nil {
+			if tracked {
+				q.unmarkQueued(job)
+			}
+			return nil, nil, err
+		}
+		if evicted != nil {
+			q.unmarkQueued(evicted)
+		}
+		q.recordEnqueue(now)
+		q.notify()
+		return job, evicted, nil
+	}
+
+	if q.ring.TryEnqueue(job) {
+		q.ringDepth.Add(1)
+		q.recordEnqueue(now)
+		q.notify()
+		return job, nil, nil
+	}
+
+	q.markRingSaturated()
+	evicted, err := q.enqueueOverflow(job, now)
+	if err != nil {
+		if tracked {
+			q.unmarkQueued(job)
+		}
+		return nil, nil, err
+	}
+	if evicted != nil {
+		q.unmarkQueued(evicted)
+	}
+	q.recordEnqueue(now)
+	q.notify()
+	return job, evicted, nil
+}
+
+// markQueued registers a job's entity in the conflation set. It reports
+// whether the entity is now tracked by this job, and whether the job was
+// conflated because the entity already had a job queued.
+func (q *HybridQueue) markQueued(job jobs.Job) (tracked, conflated bool) {
+	if q.inQueue == nil || isNilJob(job) {
+		return false, false
+	}
+	ent, ok := q.cfg.ConflateKey(job)
+	if !ok {
+		return false, false
+	}
+	if _, loaded := q.inQueue.LoadOrStore(ent, struct{}{}); loaded {
+		return false, true
+	}
+	return true, false
+}
+
+// unmarkQueued removes a departing job's entity from the conflation set, so
+// the next pulse for that entity can be queued again.
+func (q *HybridQueue) unmarkQueued(job jobs.Job) {
+	if q.inQueue == nil || isNilJob(job) {
+		return
+	}
+	if ent, ok := q.cfg.ConflateKey(job); ok {
+		q.inQueue.Delete(ent)
+	}
+}
+
+// EnqueueDelayed schedules a job that matures after delay; matured jobs are
+// promoted onto the normal enqueue path by Dequeue and DequeueBatch.
+func (q *HybridQueue) EnqueueDelayed(job jobs.Job, delay time.Duration) error {
+	if q.closed.Load() {
+		return ErrQueueClosed
+	}
+	if delay <= 0 {
+		return q.Enqueue(job)
+	}
+	q.delayed.add(job, delay)
+	return nil
+}
+
+// promoteDelayed moves matured delayed jobs into the regular lanes ahead of a
+// dequeue pass, so consumers never see immature items.
+func (q *HybridQueue) promoteDelayed() {
+	q.delayed.promote(q.Enqueue)
+}
+
+// EnqueueBatch inserts a slice of jobs in FIFO order and reports per-item
+// acceptance. With DropPolicyDropOldest an earlier item of the same batch can
+// be evicted again by a later one, so the accepted set is not a prefix; its
+// bit is cleared when that happens.
+func (q *HybridQueue) EnqueueBatch(items []interface{}) ([]bool, error) {
+	accepted := make([]bool, len(items))
+	index := make(map[jobs.Job]int, len(items))
+	var firstErr error
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		job, ok := item.(jobs.Job)
+		if !ok {
+			return accepted, errors.New("hybrid queue: invalid job type in batch")
+		}
+		inserted, evicted, err := q.enqueue(job)
+		if err != nil {
+			if errors.Is(err, ErrQueueClosed) {
+				return accepted, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		accepted[i] = true
+		// Key by the inserted instance: enqueue stamps a private copy, and
+		// a DropOldest eviction reports that copy, not the caller's job.
+		if inserted != nil {
+			index[inserted] = i
+		}
+		if evicted != nil {
+			if j, ok := index[evicted]; ok {
+				accepted[j] = false
+			}
+		}
+	}
+	return accepted, firstErr
+}
+
+// tryDequeueNormal drains the normal lane: overflow before the ring to
+// control burst memory.
+func (q *HybridQueue) tryDequeueNormal() (jobs.Job, bool) {
+	if job, ok := q.tryDequeueOverflow(); ok {
+		return job, true
+	}
+	job, ok := q.ring.TryDequeue()
+	if !ok {
+		return nil, false
+	}
+	q.ringDepth.Add(-1)
+	q.resetRingSaturation(q.ringDepth.Load())
+	return job, true
+}
+
+// tryDequeuePriority drains the high lane first, yielding to a waiting
+// normal job after PriorityRatio consecutive high dequeues so sustained
+// high load cannot starve routine work. The streak counter is approximate
+// under concurrent dequeuers, which is fine for a fairness heuristic.
+func (q *HybridQueue) tryDequeuePriority() (jobs.Job, bool) {
+	if q.highStreak.Load() >= int64(q.cfg.PriorityRatio) {
+		if job, ok := q.tryDequeueNormal(); ok {
+			q.highStreak.Store(0)
+			q.normalDequeued.Add(1)
+			return job, true
+		}
+	}
+	if job, ok := q.highRing.TryDequeue(); ok {
+		q.highDepth.Add(-1)
+		q.highStreak.Add(1)
+		q.highDequeued.Add(1)
+		return job, true
+	}
+	if job, ok := q.tryDequeueNormal(); ok {
+		q.highStreak.Store(0)
+		q.normalDequeued.Add(1)
+		return job, true
+	}
+	return nil, false
+}
+
+// Dequeue removes and returns a job, draining overflow before the ring to control burst memory.
+func (q *HybridQueue) Dequeue() (jobs.Job, error) {
+	q.promoteDelayed()
+
+	if q.highRing != nil {
+		job, ok := q.tryDequeuePriority()
+		if !ok {
+			if q.closed.Load() && q.isEmpty() {
+				return nil, ErrQueueClosed
+			}
+			return nil, nil
+		}
+		q.recordDequeue(job, time.Now())
+		return job, nil
+	}
+
+	if job, ok := q.tryDequeueOverflow(); ok {
+		now := time.Now()
+		q.recordDequeue(job, now)
+		return job, nil
+	}
+
+	job, ok := q.ring.TryDequeue()
+	if !ok {
+		if q.closed.Load() && q.isEmpty() {
+			return nil, ErrQueueClosed
+		}
+		return nil, nil
+	}
+
+	q.ringDepth.Add(-1)
+	now := time.Now()
+	q.recordDequeue(job, now)
+	q.resetRingSaturation(q.ringDepth.Load())
+
+	return job, nil
+}
+
+// DequeueBatch drains up to maxSize items, prioritising overflow jobs first.
+func (q *HybridQueue) DequeueBatch(maxSize int) ([]jobs.Job, error) {
+	if maxSize <= 0 {
+		return nil, nil
+	}
+
+	q.promoteDelayed()
+
+	result := make([]jobs.Job, 0, maxSize)
+
+	if q.highRing != nil {
+		// Per-item lane selection keeps the starvation guard exact within
+		// a batch, at the cost of item-wise overflow locking.
+		for len(result) < maxSize {
+			job, ok := q.tryDequeuePriority()
+			if !ok {
+				break
+			}
+			result = append(result, job)
+		}
+	} else {
+		if drained := q.drainOverflow(maxSize); len(drained) > 0 {
+			result = append(result, drained...)
+		}
+
+		remaining := maxSize - len(result)
+		for i := 0; i < remaining; i++ {
+			job, ok := q.ring.TryDequeue()
+			if !ok {
+				break
+			}
+			q.ringDepth.Add(-1)
+			result = append(result, job)
+		}
+	}
+
+	if len(result) == 0 {
+		if q.closed.Load() && q.isEmpty() {
+			return nil, ErrQueueClosed
+		}
+		return nil, nil
+	}
+
+	now := time.Now()
+	q.recordBatchDequeue(result, now)
+	q.resetRingSaturation(q.ringDepth.Load())
+
+	return result, nil
+}
+
+// Close prevents new jobs from being enqueued.
+func (q *HybridQueue) Close() {
+	if q.closed.CompareAndSwap(false, true) {
+		if drained := q.delayed.drainClosed(); drained > 0 {
+			q.logger.Warn("hybrid queue closed with immature delayed jobs",
+				zap.String("queue", q.cfg.Name),
+				zap.Int64("delayed_drained", drained))
+		}
+		q.logger.Info("hybrid queue closed", zap.String("queue", q.cfg.Name))
+	}
+}
+
+// Stats returns observable metrics for monitoring.
+func (q *HybridQueue) Stats() Stats {
+	enqueued := q.enqueuedCount.Load()
+	dequeued := q.dequeuedCount.Load()
+	dropped := q.droppedCount.Load()
+
+	depth := q.ringDepth.Load() + q.highDepth.Load() + q.overflowDepth.Load()
+	if depth < 0 {
+		depth = 0
+	}
+
+	elapsed := time.Since(time.Unix(0, q.startNano.Load()))
+	if elapsed <= 0 {
+		elapsed = time.Millisecond
+	}
+
+	var avgWait time.Duration
+	if dequeued > 0 {
+		avgWait = time.Duration(q.totalQueueWait.Load() / dequeued)
+	}
+
+	maxWait := time.Duration(q.maxQueueWait.Load())
+	waitPct := q.waitHist.Percentiles()
+
+	return Stats{
+		QueueDepth:           int(depth),
+		HighPriorityDepth:    int(q.highDepth.Load()),
+		HighPriorityDequeued: q.highDequeued.Load(),
+		NormalDequeued:       q.normalDequeued.Load(),
+		DelayedDepth:         q.delayed.depth(),
+		DelayedDrained:       q.delayed.drainedCount(),
+		Conflated:            q.conflatedCount.Load(),
+		Capacity:             q.cfg.RingCapacity + q.cfg.OverflowCapacity,
+		Enqueued:             enqueued,
+		Dequeued:             dequeued,
+		Dropped:              dropped,
+		MaxQueueTime:         maxWait,
+		AvgQueueTime:         avgWait,
+		P50QueueTime:         waitPct.p50,
+		P90QueueTime:         waitPct.p90,
+		P99QueueTime:         waitPct.p99,
+		P999QueueTime:        waitPct.p999,
+		MaxJobLatency:        maxWait,
+		AvgJobLatency:        avgWait,
+		EnqueueRate:          float64(enqueued) / elapsed.Seconds(),
+		DequeueRate:          float64(dequeued) / elapsed.Seconds(),
+		LastEnqueue:          time.Unix(0, q.lastEnqueueNano.Load()),
+		LastDequeue:          time.Unix(0, q.lastDequeueNano.Load()),
+		SampleWindow:         elapsed,
+	}
+}
+
+// Saturation reports the combined ring+overflow fill fraction in [0, 1].
+func (q *HybridQueue) Saturation() float64 {
+	capacity := q.cfg.RingCapacity + q.cfg.OverflowCapacity
+	if capacity <= 0 {
+		return 0
+	}
+	depth := q.ringDepth.Load() + q.highDepth.Load() + q.overflowDepth.Load()
+	if depth <= 0 {
+		return 0
+	}
+	sat := float64(depth) / float64(capacity)
+	if sat > 1 {
+		sat = 1
+	}
+	return sat
+}
+
+func (q *HybridQueue) Notify() <-chan struct{} {
+	return q.signal
+}
+
+func (q *HybridQueue) notify() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// enqueueOverflow admits a job to the overflow slice; the first return value
+// is the job a DropPolicyDropOldest eviction displaced, if any.
+func (q *HybridQueue) enqueueOverflow(job jobs.Job, now time.Time) (jobs.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.enqueueOverflowLocked(job, now)
+}
+
+func (q *HybridQueue) enqueueOverflowLocked(job jobs.Job, now time.Time) (jobs.Job, error) {
+	if q.cfg.OverflowCapacity == 0 {
+		return q.handleDropLocked(job, now, len(q.overflow), "overflow_disabled")
+	}
+
+	currentDepth := len(q.overflow)
+	nextDepth := currentDepth + 1
+
+	if q.hardOverflowLimit > 0 && nextDepth > q.hardOverflowLimit {
+		return q.handleDropLocked(job, now, currentDepth, "hard_watermark_exceeded")
+	}
+	if q.cfg.OverflowCapacity > 0 && nextDepth > q.cfg.OverflowCapacity {
+		return q.handleDropLocked(job, now, currentDepth, "overflow_capacity_exceeded")
+	}
+
+	q.overflow = append(q.overflow, job)
+	newDepth := len(q.overflow)
+	q.overflowDepth.Store(int64(newDepth))
+	q.overflowEvents.Add(1)
+	q.evaluateOverflowWatermarksLocked(newDepth)
+	return nil, nil
+}
+
+func (q *HybridQueue) handleDropLocked(job jobs.Job, now time.Time, currentDepth int, reason string) (jobs.Job, error) {
+	q.droppedCount.Add(1)
+
+	fields := []zap.Field{
+		zap.String("queue", q.cfg.Name),
+		zap.String("policy", q.cfg.DropPolicy.String()),
+		zap.String("reason", reason),
+		zap.Int("overflow_capacity", q.cfg.OverflowCapacity),
+		zap.Int("overflow_depth", currentDepth),
+	}
+
+	switch q.cfg.DropPolicy {
+	case DropPolicyDropOldest:
+		if currentDepth == 0 {
+			q.logger.Warn("hybrid queue has no overflow items to drop; rejecting newest job", fields...)
+			return nil, ErrQueueFull
+		}
+		evicted := q.overflow[0]
+		q.overflow[0] = nil
+		copy(q.overflow, q.overflow[1:])
+		q.overflow = q.overflow[:currentDepth-1]
+		q.overflowDepth.Store(int64(len(q.overflow)))
+		q.evaluateOverflowWatermarksLocked(len(q.overflow))
+		q.logger.Warn("hybrid queue dropped oldest overflow job to admit new work", fields...)
+		q.overflow = append(q.overflow, job)
+		q.overflowDepth.Store(int64(len(q.overflow)))
+		q.overflowEvents.Add(1)
+		q.evaluateOverflowWatermarksLocked(len(q.overflow))
+		return evicted, nil
+	case DropPolicyDropNewest:
+		q.logger.Warn("hybrid queue dropping newest job due to saturation", fields...)
+		return nil, ErrQueueFull
+	case DropPolicyReject:
+		q.logger.Error("hybrid queue rejecting job due to saturation", fields...)
+		return nil, ErrQueueFull
+	default:
+		q.logger.Warn("hybrid queue encountered unknown drop policy; rejecting job", fields...)
+		return nil, ErrQueueFull
+	}
+}
+
+func (q *HybridQueue) markRingSaturated() {
+	if q.ringSaturated.CompareAndSwap(false, true) {
+		q.logger.Warn("hybrid queue ring saturated; routing to overflow",
+			zap.String("queue", q.cfg.Name),
+			zap.Int("capacity", q.cfg.RingCapacity))
+	}
+}
+
+func (q *HybridQueue) resetRingSaturation(depth int64) {
+	if !q.ringSaturated.Load() || q.cfg.RingCapacity == 0 {
+		return
+	}
+	threshold := int64(float64(q.cfg.RingCapacity) * 0.8)
+	if depth < threshold {
+		if q.ringSaturated.CompareAndSwap(true, false) {
+			q.logger.Info("hybrid queue ring recovered below saturation threshold",
+				zap.String("queue", q.cfg.Name),
+				zap.Int64("depth", depth))
+		}
+	}
+}
+
+func (q *HybridQueue) tryDequeueOverflow() (jobs.Job, bool) {
+	if q.cfg.OverflowCapacity == 0 {
+		return nil, false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.overflow) == 0 {
+		q.overflowDepth.Store(0)
+		q.evaluateOverflowWatermarksLocked(0)
+		return nil, false
+	}
+
+	job := q.overflow[0]
+	q.overflow[0] = nil
+	copy(q.overflow, q.overflow[1:])
+	q.overflow = q.overflow[:len(q.overflow)-1]
+
+	depth := len(q.overflow)
+	q.overflowDepth.Store(int64(depth))
+	q.evaluateOverflowWatermarksLocked(depth)
+
+	return job, true
+}
+
+func (q *HybridQueue) drainOverflow(limit int) []jobs.Job {
+	if limit <= 0 || q.cfg.OverflowCapacity == 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	available := len(q.overflow)
+	if available == 0 {
+		q.overflowDepth.Store(0)
+		q.evaluateOverflowWatermarksLocked(0)
+		return nil
+	}
+	if limit > available {
+		limit = available
+	}
+
+	out := make([]jobs.Job, limit)
+	copy(out, q.overflow[:limit])
+
+	for i := 0; i < limit; i++ {
+		q.overflow[i] = nil
+	}
+	copy(q.overflow, q.overflow[limit:])
+	q.overflow = q.overflow[:available-limit]
+
+	depth := len(q.overflow)
+	q.overflowDepth.Store(int64(depth))
+	q.evaluateOverflowWatermarksLocked(depth)
+
+	return out
+}
+
+func (q *HybridQueue) evaluateOverflowWatermarksLocked(depth int) {
+	if q.cfg.OverflowCapacity <= 0 {
+		return
+	}
+
+	ratio := 0.0
+	if q.cfg.OverflowCapacity > 0 {
+		ratio = float64(depth) / float64(q.cfg.OverflowCapacity)
+	}
+
+	if q.cfg.SoftWatermark > 0 {
+		if ratio >= q.cfg.SoftWatermark {
+			if q.softOverflowAlerted.CompareAndSwap(false, true) {
+				q.logger.Warn("hybrid queue overflow above soft watermark",
+					zap.String("queue", q.cfg.Name),
+					zap.Float64("ratio", ratio),
+					zap.Int("overflow_depth", depth),
+					zap.Float64("soft_watermark", q.cfg.SoftWatermark))
+			}
+		} else if q.softOverflowAlerted.Load() && ratio < q.cfg.SoftWatermark {
+			if q.softOverflowAlerted.CompareAndSwap(true, false) {
+				q.logger.Info("hybrid queue overflow recovered below soft watermark",
+					zap.String("queue", q.cfg.Name),
+					zap.Float64("ratio", ratio),
+					zap.Int("overflow_depth", depth))
+			}
+		}
+	}
+
+	if q.cfg.HardWatermark > 0 {
+		if ratio >= q.cfg.HardWatermark {
+			if q.hardOverflowAlerted.CompareAndSwap(false, true) {
+				q.logger.Error("hybrid queue overflow above hard watermark",
+					zap.String("queue", q.cfg.Name),
+					zap.Float64("ratio", ratio),
+					zap.Int("overflow_depth", depth),
+					zap.Float64("hard_watermark", q.cfg.HardWatermark))
+			}
+		} else if q.hardOverflowAlerted.Load() && ratio < q.cfg.HardWatermark {
+			if q.hardOverflowAlerted.CompareAndSwap(true, false) {
+				q.logger.Info("hybrid queue overflow recovered below hard watermark",
+					zap.String("queue", q.cfg.Name),
+					zap.Float64("ratio", ratio),
+					zap.Int("overflow_depth", depth))
+			}
+		}
+	}
+}
+
+func (q *HybridQueue) recordEnqueue(now time.Time) {
+	q.enqueuedCount.Add(1)
+	q.lastEnqueueNano.Store(now.UnixNano())
+}
+
+func (q *HybridQueue) recordDequeue(job jobs.Job, now time.Time) {
+	q.unmarkQueued(job)
+	if !isNilJob(job) {
+		enqueueTime := job.GetEnqueueTime()
+		if !enqueueTime.IsZero() {
+			wait := now.Sub(enqueueTime)
+			if wait > 0 {
+				q.totalQueueWait.Add(int64(wait))
+				q.updateMaxQueueWait(int64(wait))
+				q.waitHist.Record(wait)
+			}
+		}
+	}
+	q.dequeuedCount.Add(1)
+	q.lastDequeueNano.Store(now.UnixNano())
+}
+
+func (q *HybridQueue) recordBatchDequeue(batch []jobs.Job, now time.Time) {
+	if len(batch) == 0 {
+		return
+	}
+
+	var total int64
+	var maxWait int64
+	for _, job := range batch {
+		q.unmarkQueued(job)
+		if isNilJob(job) {
+			continue
+		}
+		enqueueTime := job.GetEnqueueTime()
+		if enqueueTime.IsZero() {
+			continue
+		}
+		wait := now.Sub(enqueueTime)
+		if wait <= 0 {
+			continue
+		}
+		w := int64(wait)
+		total += w
+		if w > maxWait {
+			maxWait = w
+		}
+		q.waitHist.Record(wait)
+	}
+	if total > 0 {
+		q.totalQueueWait.Add(total)
+		q.updateMaxQueueWait(maxWait)
+	}
+	q.dequeuedCount.Add(int64(len(batch)))
+	q.lastDequeueNano.Store(now.UnixNano())
+}
+
+func (q *HybridQueue) updateMaxQueueWait(candidate int64) {
+	for {
+		current := q.maxQueueWait.Load()
+		if candidate <= current {
+			return
+		}
+		if q.maxQueueWait.CompareAndSwap(current, candidate) {
+			return
+		}
+	}
+}
+
+func (q *HybridQueue) isEmpty() bool {
+	return q.ringDepth.Load() == 0 && q.highDepth.Load() == 0 && q.overflowDepth.Load() == 0
+}
+
+func normalizeHybridConfig(cfg HybridQueueConfig) HybridQueueConfig {
+	defaults := DefaultHybridQueueConfig()
+
+	if cfg.Name == "" {
+		cfg.Name = defaults.Name
+	}
+	if cfg.RingCapacity <= 0 {
+		cfg.RingCapacity = defaults.RingCapacity
+	}
+	if cfg.OverflowCapacity < 0 {
+		cfg.OverflowCapacity = 0
+	}
+	if cfg.OverflowCapacity == 0 {
+		cfg.SoftWatermark = 0
+		cfg.HardWatermark = 0
+	} else {
+		if cfg.SoftWatermark <= 0 {
+			cfg.SoftWatermark = defaults.SoftWatermark
+		} else {
+			cfg.SoftWatermark = clamp01(cfg.SoftWatermark)
+		}
+		if cfg.HardWatermark <= 0 {
+			cfg.HardWatermark = defaults.HardWatermark
+		} else {
+			cfg.HardWatermark = clamp01(cfg.HardWatermark)
+		}
+		if cfg.HardWatermark < cfg.SoftWatermark {
+			cfg.HardWatermark = cfg.SoftWatermark
+		}
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = defaults.Logger
+	}
+	if cfg.Classify != nil && cfg.PriorityRatio <= 0 {
+		cfg.PriorityRatio = defaultPriorityRatio
+	}
+	if cfg.LatencyWindow <= 0 {
+		cfg.LatencyWindow = defaults.LatencyWindow
+	}
+
+	return cfg
+}
+
+func computeWatermarkLimit(capacity int, ratio float64) int {
+	if capacity <= 0 || ratio <= 0 {
+		return 0
+	}
+	limit := int(math.Ceil(float64(capacity) * ratio))
+	switch {
+	case limit <= 0:
+		return 1
+	case limit > capacity:
+		return capacity
+	default:
+		return limit
+	}
+}
+
+func clamp01(v float64) float64 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 1:
+		return 1
+	default:
+		return v
+	}
+}
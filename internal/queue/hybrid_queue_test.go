@@ -0,0 +1,763 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+
+	"go.uber.org/zap"
+)
+
+type testHybridJob struct {
+	enqueueTime time.Time
+	startTime   time.Time
+	id          int
+}
+
+func newTestHybridJob(id int) *testHybridJob {
+	return &testHybridJob{id: id}
+}
+
+func (j *testHybridJob) Execute(_ context.Context) jobs.Result { return jobs.Result{} }
+func (j *testHybridJob) Copy() jobs.Job                        { copy := *j; return &copy }
+func (j *testHybridJob) GetEnqueueTime() time.Time             { return j.enqueueTime }
+func (j *testHybridJob) SetEnqueueTime(t time.Time)            { j.enqueueTime = t }
+func (j *testHybridJob) GetStartTime() time.Time               { return j.startTime }
+func (j *testHybridJob) SetStartTime(t time.Time)              { j.startTime = t }
+func (j *testHybridJob) GetEntity() ecs.Entity                 { return ecs.Entity{} }
+func (j *testHybridJob) IsNil() bool                           { return false }
+
+func TestHybridQueueFastPath(t *testing.T) {
+	cfg := HybridQueueConfig{
+		Name:             "fast",
+		RingCapacity:     8,
+		OverflowCapacity: 4,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	}
+	queue, err := NewHybridQueue(cfg)
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	t.Cleanup(queue.Close)
+
+	for i := 0; i < 4; i++ {
+		if err := queue.Enqueue(newTestHybridJob(i)); err != nil {
+			t.Fatalf("enqueue failed: %v", err)
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		job, err := queue.Dequeue()
+		if err != nil {
+			t.Fatalf("dequeue failed: %v", err)
+		}
+		if job == nil {
+			t.Fatalf("expected job, got nil at index %d", i)
+		}
+		if got := job.(*testHybridJob).id; got != i {
+			t.Fatalf("expected id %d, got %d", i, got)
+		}
+	}
+
+	stats := queue.Stats()
+	if stats.QueueDepth != 0 {
+		t.Fatalf("expected empty queue, depth=%d", stats.QueueDepth)
+	}
+}
+
+func TestHybridQueueOverflowDrainOrder(t *testing.T) {
+	cfg := HybridQueueConfig{
+		Name:             "overflow",
+		RingCapacity:     2,
+		OverflowCapacity: 4,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	}
+	queue, err := NewHybridQueue(cfg)
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	t.Cleanup(queue.Close)
+
+	for i := 0; i < 6; i++ {
+		if err := queue.Enqueue(newTestHybridJob(i)); err != nil {
+			t.Fatalf("enqueue failed: %v", err)
+		}
+	}
+
+	expected := []int{2, 3, 4, 5, 0, 1}
+	for idx, want := range expected {
+		job, err := queue.Dequeue()
+		if err != nil {
+			t.Fatalf("dequeue failed: %v", err)
+		}
+		if job == nil {
+			t.Fatalf("expected job at position %d", idx)
+		}
+		if got := job.(*testHybridJob).id; got != want {
+			t.Fatalf("unexpected dequeue order at %d: want %d, got %d", idx, want, got)
+		}
+	}
+}
+
+func TestHybridQueueDropPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		expectedFront []int
+		policy        DropPolicy
+		expectedDrop  int64
+		expectErr     bool
+	}{
+		{
+			name:          "reject",
+			policy:        DropPolicyReject,
+			expectErr:     true,
+			expectedFront: []int{1, 0},
+			expectedDrop:  1,
+		},
+		{
+			name:          "drop_newest",
+			policy:        DropPolicyDropNewest,
+			expectErr:     true,
+			expectedFront: []int{1, 0},
+			expectedDrop:  1,
+		},
+		{
+			name:          "drop_oldest",
+			policy:        DropPolicyDropOldest,
+			expectErr:     false,
+			expectedFront: []int{2, 0},
+			expectedDrop:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := HybridQueueConfig{
+				Name:             tt.name,
+				RingCapacity:     1,
+				OverflowCapacity: 1,
+				DropPolicy:       tt.policy,
+				Logger:           zap.NewNop(),
+			}
+			queue, err := NewHybridQueue(cfg)
+			if err != nil {
+				t.Fatalf("failed to create hybrid queue: %v", err)
+			}
+			t.Cleanup(queue.Close)
+
+			if err := queue.Enqueue(newTestHybridJob(0)); err != nil {
+				t.Fatalf("enqueue 0 failed: %v", err)
+			}
+			if err := queue.Enqueue(newTestHybridJob(1)); err != nil {
+				t.Fatalf("enqueue 1 failed: %v", err)
+			}
+
+			err = queue.Enqueue(newTestHybridJob(2))
+			if tt.expectErr {
+				if !errors.Is(err, ErrQueueFull) {
+					t.Fatalf("expected ErrQueueFull, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected enqueue error: %v", err)
+			}
+
+			gotOrder := make([]int, 0, len(tt.expectedFront))
+			for range tt.expectedFront {
+				job, err := queue.Dequeue()
+				if err != nil {
+					t.Fatalf("dequeue failed: %v", err)
+				}
+				if job == nil {
+					t.Fatalf("expected job during drain")
+				}
+				gotOrder = append(gotOrder, job.(*testHybridJob).id)
+			}
+			if len(gotOrder) != len(tt.expectedFront) {
+				t.Fatalf("drain mismatch, expected %d items got %d", len(tt.expectedFront), len(gotOrder))
+			}
+			for i, want := range tt.expectedFront {
+				if gotOrder[i] != want {
+					t.Fatalf("unexpected order[%d]: want %d, got %d", i, want, gotOrder[i])
+				}
+			}
+
+			stats := queue.Stats()
+			if stats.Dropped != tt.expectedDrop {
+				t.Fatalf("unexpected drop count: want %d got %d", tt.expectedDrop, stats.Dropped)
+			}
+		})
+	}
+}
+
+func TestHybridQueueBatchOperations(t *testing.T) {
+	cfg := HybridQueueConfig{
+		Name:             "batch",
+		RingCapacity:     4,
+		OverflowCapacity: 4,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	}
+	queue, err := NewHybridQueue(cfg)
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	t.Cleanup(queue.Close)
+
+	batch := []interface{}{newTestHybridJob(0), newTestHybridJob(1), newTestHybridJob(2)}
+	if _, err := queue.EnqueueBatch(batch); err != nil {
+		t.Fatalf("enqueue batch failed: %v", err)
+	}
+
+	jobs, err := queue.DequeueBatch(3)
+	if err != nil {
+		t.Fatalf("dequeue batch failed: %v", err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("expected 3 jobs, got %d", len(jobs))
+	}
+}
+
+func TestHybridQueueConcurrentAccess(t *testing.T) {
+	cfg := HybridQueueConfig{
+		Name:             "concurrency",
+		RingCapacity:     64,
+		OverflowCapacity: 64,
+		DropPolicy:       DropPolicyDropNewest,
+		Logger:           zap.NewNop(),
+	}
+	queue, err := NewHybridQueue(cfg)
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	t.Cleanup(queue.Close)
+
+	const (
+		producers    = 4
+		consumers    = 3
+		perProducer  = 100
+		total        = producers * perProducer
+		retryBackoff = 50 * time.Microsecond
+	)
+
+	var produced atomic.Int64
+	var produceWG sync.WaitGroup
+	produceWG.Add(producers)
+	for p := 0; p < producers; p++ {
+		go func(offset int) {
+			defer produceWG.Done()
+			for i := 0; i < perProducer; i++ {
+				job := newTestHybridJob(offset*perProducer + i)
+				for {
+					if err := queue.Enqueue(job); err != nil {
+						if errors.Is(err, ErrQueueFull) {
+							time.Sleep(retryBackoff)
+							continue
+						}
+						if errors.Is(err, ErrQueueClosed) {
+							return
+						}
+						t.Errorf("unexpected enqueue error: %v", err)
+						return
+					}
+					produced.Add(1)
+					break
+				}
+			}
+		}(p)
+	}
+
+	var consumed atomic.Int64
+	var consumeWG sync.WaitGroup
+	consumeWG.Add(consumers)
+	for c := 0; c < consumers; c++ {
+		go func() {
+			defer consumeWG.Done()
+			for {
+				if consumed.Load() >= int64(total) {
+					return
+				}
+				job, err := queue.Dequeue()
+				if err != nil {
+					if errors.Is(err, ErrQueueClosed) {
+						return
+					}
+					t.Errorf("unexpected dequeue error: %v", err)
+					return
+				}
+				if job == nil {
+					time.Sleep(retryBackoff)
+					continue
+				}
+				consumed.Add(1)
+			}
+		}()
+	}
+
+	produceWG.Wait()
+	for consumed.Load() < int64(total) {
+		jobs, err := queue.DequeueBatch(16)
+		if err != nil {
+			if errors.Is(err, ErrQueueClosed) {
+				break
+			}
+			t.Fatalf("unexpected batch dequeue error: %v", err)
+		}
+		if len(jobs) == 0 {
+			time.Sleep(retryBackoff)
+			continue
+		}
+		consumed.Add(int64(len(jobs)))
+	}
+
+	queue.Close()
+	consumeWG.Wait()
+
+	if produced.Load() != int64(total) {
+		t.Fatalf("expected produced %d, got %d", total, produced.Load())
+	}
+	if consumed.Load() != int64(total) {
+		t.Fatalf("expected consumed %d, got %d", total, consumed.Load())
+	}
+}
+
+// priorityTestJob marks testHybridJob instances for the high lane.
+type priorityTestJob struct {
+	testHybridJob
+	high bool
+}
+
+// Copy must return the full type: the embedded Copy would drop the high
+// flag, and the queue classifies the private copy it stamps.
+func (j *priorityTestJob) Copy() jobs.Job { copy := *j; return &copy }
+
+func testPriorityClassifier(j jobs.Job) Priority {
+	if pj, ok := j.(*priorityTestJob); ok && pj.high {
+		return PriorityHigh
+	}
+	return PriorityNormal
+}
+
+func newPriorityQueue(t *testing.T, ratio int) *HybridQueue {
+	t.Helper()
+	queue, err := NewHybridQueue(HybridQueueConfig{
+		Name:             "priority",
+		RingCapacity:     256,
+		OverflowCapacity: 64,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+		Classify:         testPriorityClassifier,
+		PriorityRatio:    ratio,
+	})
+	if err != nil {
+		t.Fatalf("failed to create priority queue: %v", err)
+	}
+	t.Cleanup(queue.Close)
+	return queue
+}
+
+func TestHybridQueuePriorityOrdering(t *testing.T) {
+	queue := newPriorityQueue(t, 0) // 0 uses the default 4:1 ratio
+
+	enqueue := func(id int, high bool) {
+		t.Helper()
+		job := &priorityTestJob{high: high}
+		job.id = id
+		if err := queue.Enqueue(job); err != nil {
+			t.Fatalf("enqueue %d failed: %v", id, err)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		enqueue(i, false)
+	}
+	for i := 100; i < 108; i++ {
+		enqueue(i, true)
+	}
+	enqueue(4, false)
+	enqueue(5, false)
+
+	// High drains first; after four consecutive high dequeues the guard
+	// serves one waiting normal job.
+	want := []int{100, 101, 102, 103, 0, 104, 105, 106, 107, 1, 2, 3, 4, 5}
+	for i, wantID := range want {
+		job, err := queue.Dequeue()
+		if err != nil {
+			t.Fatalf("dequeue %d failed: %v", i, err)
+		}
+		pj, ok := job.(*priorityTestJob)
+		if !ok {
+			t.Fatalf("dequeue %d: unexpected job type %T", i, job)
+		}
+		if pj.id != wantID {
+			t.Errorf("dequeue %d: id = %d, want %d", i, pj.id, wantID)
+		}
+	}
+
+	stats := queue.Stats()
+	if stats.HighPriorityDequeued != 8 {
+		t.Errorf("HighPriorityDequeued = %d, want 8", stats.HighPriorityDequeued)
+	}
+	if stats.NormalDequeued != 6 {
+		t.Errorf("NormalDequeued = %d, want 6", stats.NormalDequeued)
+	}
+	if stats.QueueDepth != 0 || stats.HighPriorityDepth != 0 {
+		t.Errorf("depth = %d/%d, want 0/0", stats.QueueDepth, stats.HighPriorityDepth)
+	}
+}
+
+func TestHybridQueuePriorityStarvationGuard(t *testing.T) {
+	queue := newPriorityQueue(t, 4)
+
+	const highCount = 100
+	const normalCount = 10
+	for i := 0; i < normalCount; i++ {
+		job := &priorityTestJob{}
+		job.id = i
+		if err := queue.Enqueue(job); err != nil {
+			t.Fatalf("enqueue normal failed: %v", err)
+		}
+	}
+	for i := 0; i < highCount; i++ {
+		job := &priorityTestJob{high: true}
+		job.id = 1000 + i
+		if err := queue.Enqueue(job); err != nil {
+			t.Fatalf("enqueue high failed: %v", err)
+		}
+	}
+
+	// Under sustained high load a 4:1 ratio serves every waiting normal job
+	// within the first normalCount*5 dequeues; exercise the batch path.
+	drained := 0
+	normals := 0
+	for drained < normalCount*5 {
+		batch, err := queue.DequeueBatch(7)
+		if err != nil {
+			t.Fatalf("batch dequeue failed: %v", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		for _, job := range batch {
+			if pj := job.(*priorityTestJob); !pj.high {
+				normals++
+			}
+			drained++
+		}
+	}
+	if normals != normalCount {
+		t.Errorf("normal jobs served in first %d dequeues = %d, want %d", drained, normals, normalCount)
+	}
+
+	stats := queue.Stats()
+	if stats.HighPriorityDepth != highCount-(drained-normals) {
+		t.Errorf("HighPriorityDepth = %d, want %d", stats.HighPriorityDepth, highCount-(drained-normals))
+	}
+}
+
+func TestHybridQueueDefaultPriorityClassifier(t *testing.T) {
+	if got := DefaultPriorityClassifier(&jobs.PulseHTTPJob{}); got != PriorityNormal {
+		t.Errorf("pulse job priority = %v, want PriorityNormal", got)
+	}
+	if got := DefaultPriorityClassifier(newTestHybridJob(1)); got != PriorityHigh {
+		t.Errorf("non-pulse job priority = %v, want PriorityHigh", got)
+	}
+}
+
+func TestHybridQueueEnqueueBatchAcceptance_DropOldest(t *testing.T) {
+	queue, err := NewHybridQueue(HybridQueueConfig{
+		Name:             "drop-oldest",
+		RingCapacity:     2,
+		OverflowCapacity: 2,
+		DropPolicy:       DropPolicyDropOldest,
+		Logger:           zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+	defer queue.Close()
+
+	// Eight jobs into a capacity of four: the ring takes 0 and 1, the
+	// overflow takes 2 and 3, then each later job evicts the oldest overflow
+	// entry. Only 0, 1, 6 and 7 remain queued afterwards.
+	batch := make([]interface{}, 8)
+	for i := range batch {
+		batch[i] = newTestHybridJob(i)
+	}
+	accepted, err := queue.EnqueueBatch(batch)
+	if err != nil {
+		t.Fatalf("EnqueueBatch failed: %v", err)
+	}
+
+	wantAccepted := map[int]bool{0: true, 1: true, 6: true, 7: true}
+	for i := range batch {
+		if accepted[i] != wantAccepted[i] {
+			t.Errorf("accepted[%d] = %v, want %v", i, accepted[i], wantAccepted[i])
+		}
+	}
+
+	// The acceptance bitmap must match what is actually dequeueable.
+	queued := make(map[int]bool)
+	for {
+		job, err := queue.Dequeue()
+		if err != nil {
+			t.Fatalf("Dequeue failed: %v", err)
+		}
+		if job == nil {
+			break
+		}
+		queued[job.(*testHybridJob).id] = true
+	}
+	for i := range batch {
+		if accepted[i] != queued[i] {
+			t.Errorf("job %d: accepted = %v but queued = %v", i, accepted[i], queued[i])
+		}
+	}
+}
+
+// conflateTestJob carries an entity so conflation tests can control the key.
+type conflateTestJob struct {
+	testHybridJob
+	ent ecs.Entity
+}
+
+// Copy must return the full type so the queue's private copy still carries
+// the conflation key.
+func (j *conflateTestJob) Copy() jobs.Job { copy := *j; return &copy }
+
+func testConflateKey(job jobs.Job) (ecs.Entity, bool) {
+	if j, ok := job.(*conflateTestJob); ok {
+		return j.ent, true
+	}
+	return ecs.Entity{}, false
+}
+
+func newConflateQueue(t *testing.T, ringCapacity, overflowCapacity int, policy DropPolicy) *HybridQueue {
+	t.Helper()
+	queue, err := NewHybridQueue(HybridQueueConfig{
+		Name:             "conflate",
+		RingCapacity:     ringCapacity,
+		OverflowCapacity: overflowCapacity,
+		DropPolicy:       policy,
+		ConflateKey:      testConflateKey,
+		Logger:           zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	t.Cleanup(queue.Close)
+	return queue
+}
+
+func TestHybridQueueConflation(t *testing.T) {
+	queue := newConflateQueue(t, 16, 4, DropPolicyReject)
+
+	world := ecs.NewWorld()
+	ent := world.NewEntity()
+
+	// Ten jobs for the same entity: one is queued, nine are conflated, and
+	// every enqueue reports success.
+	for i := 0; i < 10; i++ {
+		if err := queue.Enqueue(&conflateTestJob{testHybridJob: testHybridJob{id: i}, ent: ent}); err != nil {
+			t.Fatalf("enqueue %d failed: %v", i, err)
+		}
+	}
+
+	stats := queue.Stats()
+	if stats.QueueDepth != 1 {
+		t.Fatalf("expected depth 1 after conflation, got %d", stats.QueueDepth)
+	}
+	if stats.Conflated != 9 {
+		t.Fatalf("expected 9 conflations, got %d", stats.Conflated)
+	}
+
+	// Drain: exactly one job comes out.
+	executed := 0
+	for {
+		job, err := queue.Dequeue()
+		if err != nil {
+			t.Fatalf("dequeue failed: %v", err)
+		}
+		if job == nil {
+			break
+		}
+		executed++
+	}
+	if executed != 1 {
+		t.Fatalf("expected exactly 1 execution, got %d", executed)
+	}
+
+	// The dequeue released the entity, so the next pulse queues again.
+	if err := queue.Enqueue(&conflateTestJob{testHybridJob: testHybridJob{id: 10}, ent: ent}); err != nil {
+		t.Fatalf("re-enqueue after drain failed: %v", err)
+	}
+	stats = queue.Stats()
+	if stats.QueueDepth != 1 {
+		t.Fatalf("expected depth 1 after re-enqueue, got %d", stats.QueueDepth)
+	}
+	if stats.Conflated != 9 {
+		t.Fatalf("re-enqueue after drain must not conflate, got %d", stats.Conflated)
+	}
+}
+
+func TestHybridQueueConflationBatchDequeueReleases(t *testing.T) {
+	queue := newConflateQueue(t, 16, 4, DropPolicyReject)
+
+	world := ecs.NewWorld()
+	ents := make([]ecs.Entity, 3)
+	for i := range ents {
+		ents[i] = world.NewEntity()
+		if err := queue.Enqueue(&conflateTestJob{testHybridJob: testHybridJob{id: i}, ent: ents[i]}); err != nil {
+			t.Fatalf("enqueue %d failed: %v", i, err)
+		}
+	}
+
+	batch, err := queue.DequeueBatch(8)
+	if err != nil {
+		t.Fatalf("dequeue batch failed: %v", err)
+	}
+	if len(batch) != 3 {
+		t.Fatalf("expected 3 jobs, got %d", len(batch))
+	}
+
+	// Batch dequeue must release every entity for re-enqueue.
+	for i, ent := range ents {
+		if err := queue.Enqueue(&conflateTestJob{testHybridJob: testHybridJob{id: 10 + i}, ent: ent}); err != nil {
+			t.Fatalf("re-enqueue %d failed: %v", i, err)
+		}
+	}
+	if got := queue.Stats().Conflated; got != 0 {
+		t.Fatalf("expected no conflations after batch dequeue released entities, got %d", got)
+	}
+}
+
+func TestHybridQueueConflationDropOldestReleasesEvicted(t *testing.T) {
+	queue := newConflateQueue(t, 2, 2, DropPolicyDropOldest)
+
+	world := ecs.NewWorld()
+	ents := make([]ecs.Entity, 5)
+	for i := range ents {
+		ents[i] = world.NewEntity()
+		if err := queue.Enqueue(&conflateTestJob{testHybridJob: testHybridJob{id: i}, ent: ents[i]}); err != nil {
+			t.Fatalf("enqueue %d failed: %v", i, err)
+		}
+	}
+
+	// Ring holds 0,1; overflow held 2,3 and evicted 2 to admit 4. The
+	// evicted entity must be released so its next pulse queues normally.
+	if err := queue.Enqueue(&conflateTestJob{testHybridJob: testHybridJob{id: 5}, ent: ents[2]}); err != nil {
+		t.Fatalf("re-enqueue of evicted entity failed: %v", err)
+	}
+	if got := queue.Stats().Conflated; got != 0 {
+		t.Fatalf("evicted entity must not conflate on re-enqueue, got %d", got)
+	}
+}
+
+func TestDefaultPulseConflateKey(t *testing.T) {
+	world := ecs.NewWorld()
+	ent := world.NewEntity()
+
+	if got, ok := DefaultPulseConflateKey(&jobs.PulseHTTPJob{Entity: ent}); !ok || got != ent {
+		t.Fatalf("expected pulse HTTP job to conflate on its entity, got (%v, %t)", got, ok)
+	}
+	if _, ok := DefaultPulseConflateKey(newTestHybridJob(1)); ok {
+		t.Fatal("unrecognised job types must not conflate")
+	}
+	if _, ok := DefaultPulseConflateKey(&jobs.PulseTCPJob{}); ok {
+		t.Fatal("jobs carrying the zero entity must not conflate")
+	}
+}
+
+// TestHybridQueuePrototypeEnqueueRace enqueues one shared prototype from many
+// goroutines while a consumer drains. The queue must stamp private copies —
+// the prototype itself stays untouched and every dequeued copy carries its
+// own enqueue time. Run with -race to catch regressions in the ownership rule.
+func TestHybridQueuePrototypeEnqueueRace(t *testing.T) {
+	cfg := HybridQueueConfig{
+		Name:             "proto-race",
+		RingCapacity:     64,
+		OverflowCapacity: 1024,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	}
+	queue, err := NewHybridQueue(cfg)
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	t.Cleanup(queue.Close)
+
+	prototype := newTestHybridJob(42)
+
+	const producers = 16
+	const perProducer = 50
+	var enqueued atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < producers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perProducer; j++ {
+				if err := queue.Enqueue(prototype); err == nil {
+					enqueued.Add(1)
+				}
+			}
+		}()
+	}
+
+	var dequeued atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		deadline := time.After(5 * time.Second)
+		for {
+			job, err := queue.Dequeue()
+			if err != nil {
+				return
+			}
+			if job == nil {
+				select {
+				case <-deadline:
+					return
+				default:
+					time.Sleep(time.Millisecond)
+					continue
+				}
+			}
+			if job == jobs.Job(prototype) {
+				t.Error("dequeued the prototype instance, expected a private copy")
+				return
+			}
+			if job.GetEnqueueTime().IsZero() {
+				t.Error("dequeued copy has no enqueue time")
+				return
+			}
+			if job.(*testHybridJob).id != 42 {
+				t.Errorf("dequeued copy id = %d, want 42", job.(*testHybridJob).id)
+				return
+			}
+			if dequeued.Add(1) == producers*perProducer {
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	<-done
+
+	if enqueued.Load() != producers*perProducer {
+		t.Errorf("enqueued %d of %d jobs", enqueued.Load(), producers*perProducer)
+	}
+	if dequeued.Load() != enqueued.Load() {
+		t.Errorf("dequeued %d of %d enqueued jobs", dequeued.Load(), enqueued.Load())
+	}
+	if !prototype.GetEnqueueTime().IsZero() {
+		t.Error("prototype was stamped; queues must only stamp their private copy")
+	}
+}
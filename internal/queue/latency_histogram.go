@@ -0,0 +1,146 @@
+package queue
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// defaultLatencyWindow bounds how long old wait samples influence the
+// percentiles reported in Stats.
+const defaultLatencyWindow = 5 * time.Minute
+
+// Log-linear bucket layout: waits are bucketed in microseconds, with each
+// power-of-two range split into histSubBuckets linear sub-buckets. That
+// bounds the relative error of a reported percentile by 1/histSubBuckets
+// (6.25%) while keeping the bucket count small enough to scan on every
+// Stats call. 512 buckets cover waits up to several hours; longer clamp
+// into the top bucket.
+const (
+	histSubBits     = 4
+	histSubBuckets  = 1 << histSubBits
+	histBucketCount = 512
+)
+
+// histBucketIndex maps a wait to its bucket.
+func histBucketIndex(wait time.Duration) int {
+	us := uint64(wait / time.Microsecond)
+	if us < histSubBuckets {
+		return int(us)
+	}
+	exp := bits.Len64(us) - 1
+	sub := int((us >> uint(exp-histSubBits)) & (histSubBuckets - 1))
+	idx := (exp-histSubBits+1)*histSubBuckets + sub
+	if idx >= histBucketCount {
+		idx = histBucketCount - 1
+	}
+	return idx
+}
+
+// histBucketValue returns a bucket's upper bound, which is what percentile
+// queries report.
+func histBucketValue(idx int) time.Duration {
+	if idx < histSubBuckets {
+		return time.Duration(idx) * time.Microsecond
+	}
+	exp := idx/histSubBuckets + histSubBits - 1
+	sub := idx % histSubBuckets
+	us := uint64(histSubBuckets+sub+1) << uint(exp-histSubBits)
+	return time.Duration(us) * time.Microsecond
+}
+
+// latencyHistogram is a lock-free, windowed histogram of queue wait times.
+// Recorders increment per-bucket counters in the active phase; every window
+// the phases rotate and the stale phase is cleared, so percentile queries
+// (which merge both phases) never reflect samples older than two windows.
+// Clearing races benignly with concurrent recording — a handful of samples
+// may be lost at a rotation boundary, which is acceptable for an
+// observability figure and keeps the hot dequeue path contention-free.
+type latencyHistogram struct {
+	window     time.Duration
+	lastRotate atomic.Int64
+	active     atomic.Int32
+	counts     [2][histBucketCount]atomic.Int64
+}
+
+// newLatencyHistogram builds a histogram with the given decay window; a
+// non-positive window uses defaultLatencyWindow.
+func newLatencyHistogram(window time.Duration) *latencyHistogram {
+	if window <= 0 {
+		window = defaultLatencyWindow
+	}
+	h := &latencyHistogram{window: window}
+	h.lastRotate.Store(time.Now().UnixNano())
+	return h
+}
+
+// Record adds one wait measurement.
+func (h *latencyHistogram) Record(wait time.Duration) {
+	if wait < 0 {
+		return
+	}
+	h.maybeRotate(time.Now())
+	h.counts[h.active.Load()][histBucketIndex(wait)].Add(1)
+}
+
+// maybeRotate swaps phases once per window; the CAS elects a single rotator.
+func (h *latencyHistogram) maybeRotate(now time.Time) {
+	last := h.lastRotate.Load()
+	if now.UnixNano()-last < int64(h.window) {
+		return
+	}
+	if !h.lastRotate.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	// The phase being activated holds samples from two windows ago.
+	next := 1 - h.active.Load()
+	for i := range h.counts[next] {
+		h.counts[next][i].Store(0)
+	}
+	h.active.Store(next)
+}
+
+// queueWaitPercentiles carries the percentile figures surfaced in Stats.
+type queueWaitPercentiles struct {
+	p50, p90, p99, p999 time.Duration
+}
+
+// Percentiles reports wait percentiles over the last one-to-two windows.
+func (h *latencyHistogram) Percentiles() queueWaitPercentiles {
+	h.maybeRotate(time.Now())
+
+	var counts [histBucketCount]int64
+	var total int64
+	for phase := 0; phase < 2; phase++ {
+		for i := range counts {
+			c := h.counts[phase][i].Load()
+			counts[i] += c
+			total += c
+		}
+	}
+	if total == 0 {
+		return queueWaitPercentiles{}
+	}
+
+	quantile := func(p float64) time.Duration {
+		target := int64(math.Ceil(p * float64(total)))
+		if target < 1 {
+			target = 1
+		}
+		var cum int64
+		for i := range counts {
+			cum += counts[i]
+			if cum >= target {
+				return histBucketValue(i)
+			}
+		}
+		return histBucketValue(histBucketCount - 1)
+	}
+	return queueWaitPercentiles{
+		p50:  quantile(0.50),
+		p90:  quantile(0.90),
+		p99:  quantile(0.99),
+		p999: quantile(0.999),
+	}
+}
@@ -0,0 +1,146 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// assertWithinBucketError checks that got is the bucket upper bound for want:
+// at least want, and no more than one sub-bucket width (1/16 ≈ 6.25%) above.
+func assertWithinBucketError(t *testing.T, name string, got, want time.Duration) {
+	t.Helper()
+	if got < want {
+		t.Errorf("%s = %v, below exact value %v", name, got, want)
+	}
+	limit := want + want/histSubBuckets + time.Microsecond
+	if got > limit {
+		t.Errorf("%s = %v, exceeds bucket error bound %v for %v", name, got, limit, want)
+	}
+}
+
+func TestLatencyHistogram_PercentileAccuracy(t *testing.T) {
+	h := newLatencyHistogram(time.Hour)
+
+	// 899 × 1ms, 90 × 100ms, 10 × 1s, 1 × 10s: p50 lands in the 1ms bucket,
+	// p90 in 100ms, p99 and p999 in 1s.
+	for i := 0; i < 899; i++ {
+		h.Record(time.Millisecond)
+	}
+	for i := 0; i < 90; i++ {
+		h.Record(100 * time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		h.Record(time.Second)
+	}
+	h.Record(10 * time.Second)
+
+	pct := h.Percentiles()
+	assertWithinBucketError(t, "p50", pct.p50, time.Millisecond)
+	assertWithinBucketError(t, "p90", pct.p90, 100*time.Millisecond)
+	assertWithinBucketError(t, "p99", pct.p99, time.Second)
+	assertWithinBucketError(t, "p999", pct.p999, time.Second)
+}
+
+func TestLatencyHistogram_SubMicrosecondAndNegative(t *testing.T) {
+	h := newLatencyHistogram(time.Hour)
+	h.Record(-time.Second) // ignored
+	h.Record(500 * time.Nanosecond)
+	pct := h.Percentiles()
+	if pct.p50 != 0 {
+		t.Errorf("sub-microsecond wait should land in the zero bucket, got p50=%v", pct.p50)
+	}
+	if pct.p999 != 0 {
+		t.Errorf("negative wait should be ignored, got p999=%v", pct.p999)
+	}
+}
+
+func TestLatencyHistogram_WindowDecay(t *testing.T) {
+	h := newLatencyHistogram(30 * time.Millisecond)
+	for i := 0; i < 100; i++ {
+		h.Record(5 * time.Millisecond)
+	}
+
+	if pct := h.Percentiles(); pct.p50 == 0 {
+		t.Fatal("expected non-zero percentiles right after recording")
+	}
+
+	// After one window the samples rotate into the inactive phase but still
+	// count; after a second window they are cleared.
+	time.Sleep(35 * time.Millisecond)
+	if pct := h.Percentiles(); pct.p50 == 0 {
+		t.Fatal("expected samples to survive one rotation")
+	}
+
+	time.Sleep(35 * time.Millisecond)
+	if pct := h.Percentiles(); pct.p50 != 0 {
+		t.Fatalf("expected samples to decay after two windows, got p50=%v", pct.p50)
+	}
+}
+
+func TestHistBucketRoundTrip(t *testing.T) {
+	// The reported value (bucket upper bound) must never undershoot the
+	// sample and never overshoot by more than one sub-bucket width.
+	for _, wait := range []time.Duration{
+		time.Microsecond,
+		15 * time.Microsecond,
+		16 * time.Microsecond,
+		100 * time.Microsecond,
+		time.Millisecond,
+		42 * time.Millisecond,
+		time.Second,
+		time.Minute,
+		time.Hour,
+	} {
+		got := histBucketValue(histBucketIndex(wait))
+		assertWithinBucketError(t, wait.String(), got, wait)
+	}
+	// Beyond the top bucket everything clamps rather than panicking.
+	if idx := histBucketIndex(1000 * time.Hour); idx != histBucketCount-1 {
+		t.Errorf("expected clamp to top bucket, got index %d", idx)
+	}
+}
+
+func BenchmarkLatencyHistogramRecord(b *testing.B) {
+	h := newLatencyHistogram(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			h.Record(3 * time.Millisecond)
+		}
+	})
+}
+
+// BenchmarkHybridQueueDequeueBatch measures the enqueue+dequeue round trip
+// that now records into the histogram; compare against a run with the
+// q.waitHist.Record calls stubbed out to confirm the <5% overhead budget.
+func BenchmarkHybridQueueDequeueBatch(b *testing.B) {
+	cfg := HybridQueueConfig{
+		Name:             "bench",
+		RingCapacity:     1024,
+		OverflowCapacity: 1024,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	}
+	q, err := NewHybridQueue(cfg)
+	if err != nil {
+		b.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	defer q.Close()
+
+	const batch = 64
+	jobsBatch := make([]interface{}, batch)
+	for i := range jobsBatch {
+		jobsBatch[i] = newTestHybridJob(i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := q.EnqueueBatch(jobsBatch); err != nil {
+			b.Fatalf("enqueue batch failed: %v", err)
+		}
+		if _, err := q.DequeueBatch(batch); err != nil {
+			b.Fatalf("dequeue batch failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+
+	"go.uber.org/zap"
+)
+
+// panickingTestJob crashes in Execute, standing in for a driver bug or a
+// poisonous config that a worker must survive.
+type panickingTestJob struct {
+	enqueueTime time.Time
+	startTime   time.Time
+	entity      ecs.Entity
+	message     string
+}
+
+func (j *panickingTestJob) Execute(_ context.Context) jobs.Result { panic(j.message) }
+func (j *panickingTestJob) Copy() jobs.Job                        { cp := *j; return &cp }
+func (j *panickingTestJob) GetEnqueueTime() time.Time             { return j.enqueueTime }
+func (j *panickingTestJob) SetEnqueueTime(t time.Time)            { j.enqueueTime = t }
+func (j *panickingTestJob) GetStartTime() time.Time               { return j.startTime }
+func (j *panickingTestJob) SetStartTime(t time.Time)              { j.startTime = t }
+func (j *panickingTestJob) GetEntity() ecs.Entity                 { return j.entity }
+func (j *panickingTestJob) IsNil() bool                           { return j == nil }
+
+// TestDynamicWorkerPool_PanicProducesFailureResult verifies a panicking job
+// does not kill its worker: the pool recovers, synthesizes a failure Result
+// for the job's entity with the panic message attached, and delivers it on
+// the normal result path so the monitor fails deterministically.
+func TestDynamicWorkerPool_PanicProducesFailureResult(t *testing.T) {
+	q, err := NewHybridQueue(HybridQueueConfig{
+		Name:             "panic-test",
+		RingCapacity:     64,
+		OverflowCapacity: 64,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	config := WorkerPoolConfig{
+		MinWorkers:         2,
+		MaxWorkers:         4,
+		AdjustmentInterval: 0,
+		ResultBatchSize:    1,
+		ResultBatchTimeout: 5 * time.Millisecond,
+		Kind:               jobs.KindPulse,
+	}
+	pool, err := NewDynamicWorkerPool(context.Background(), q, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	pool.Start()
+	defer pool.DrainAndStop()
+
+	world := ecs.NewWorld()
+	ent := world.NewEntity()
+
+	results := make(chan jobs.Result, 4)
+	go func() {
+		for batch := range pool.GetRouter().PulseResultChan {
+			for _, r := range batch {
+				results <- r
+			}
+		}
+	}()
+
+	if err := q.Enqueue(&panickingTestJob{entity: ent, message: "boom: nil map write"}); err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+
+	var got jobs.Result
+	select {
+	case got = <-results:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no result arrived for the panicked job")
+	}
+
+	if got.Entity() != ent {
+		t.Errorf("Result entity = %v, want the job's entity %v", got.Entity(), ent)
+	}
+	if !errors.Is(got.Error(), jobs.ErrJobPanicked) {
+		t.Errorf("Result error = %v, want ErrJobPanicked", got.Error())
+	}
+	if msg, _ := got.Payload.Extra("panic").(string); msg != "boom: nil map write" {
+		t.Errorf("Payload panic extra = %q, want the panic message", msg)
+	}
+
+	stats := pool.Stats()
+	if stats.Panics != 1 {
+		t.Errorf("Stats().Panics = %d, want 1", stats.Panics)
+	}
+	if stats.LastPanic != "boom: nil map write" {
+		t.Errorf("Stats().LastPanic = %q, want the panic message", stats.LastPanic)
+	}
+
+	// The worker that recovered must still be able to run the next job.
+	if err := q.Enqueue(&panickingTestJob{entity: ent, message: "boom again"}); err != nil {
+		t.Fatalf("second enqueue failed: %v", err)
+	}
+	select {
+	case <-results:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no result arrived after the first panic; worker did not survive")
+	}
+	if n := pool.Stats().Panics; n != 2 {
+		t.Errorf("Stats().Panics after second panic = %d, want 2", n)
+	}
+}
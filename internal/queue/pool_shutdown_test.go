@@ -0,0 +1,139 @@
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+
+	"go.uber.org/zap"
+)
+
+// blockingTestJob parks in Execute until its context is cancelled or a long
+// fallback timer fires, mimicking a check stuck in a retry sleep.
+type blockingTestJob struct {
+	enqueueTime time.Time
+	startTime   time.Time
+	budget      time.Duration // reported via ExecutionBudget when > 0
+	started     chan struct{}
+	blockFor    time.Duration
+}
+
+func (j *blockingTestJob) Execute(ctx context.Context) jobs.Result {
+	if j.started != nil {
+		close(j.started)
+	}
+	select {
+	case <-ctx.Done():
+		return jobs.Result{Err: ctx.Err(), Payload: jobs.Payload{Kind: jobs.KindPulse}}
+	case <-time.After(j.blockFor):
+		return jobs.Result{Payload: jobs.Payload{Kind: jobs.KindPulse}}
+	}
+}
+func (j *blockingTestJob) Copy() jobs.Job             { cp := *j; return &cp }
+func (j *blockingTestJob) GetEnqueueTime() time.Time  { return j.enqueueTime }
+func (j *blockingTestJob) SetEnqueueTime(t time.Time) { j.enqueueTime = t }
+func (j *blockingTestJob) GetStartTime() time.Time    { return j.startTime }
+func (j *blockingTestJob) SetStartTime(t time.Time)   { j.startTime = t }
+func (j *blockingTestJob) GetEntity() ecs.Entity      { return ecs.Entity{} }
+func (j *blockingTestJob) IsNil() bool                { return j == nil }
+func (j *blockingTestJob) ExecutionBudget() time.Duration {
+	return j.budget
+}
+
+func newShutdownTestPool(t *testing.T) (*DynamicWorkerPool, Queue) {
+	t.Helper()
+	q, err := NewHybridQueue(HybridQueueConfig{
+		Name:             "shutdown-test",
+		RingCapacity:     64,
+		OverflowCapacity: 64,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	config := WorkerPoolConfig{
+		MinWorkers:         4,
+		MaxWorkers:         8,
+		AdjustmentInterval: 0, // No auto-scaling during the test
+		ResultBatchSize:    4,
+		ResultBatchTimeout: 5 * time.Millisecond,
+	}
+	pool, err := NewDynamicWorkerPool(context.Background(), q, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	return pool, q
+}
+
+// TestDynamicWorkerPool_ShutdownCancelsInFlightJobs verifies that
+// DrainAndStop cancels the context seen by running jobs, so in-flight
+// sleeps and retries abort promptly instead of running to completion.
+func TestDynamicWorkerPool_ShutdownCancelsInFlightJobs(t *testing.T) {
+	pool, q := newShutdownTestPool(t)
+	pool.Start()
+
+	started := make(chan struct{})
+	if err := q.Enqueue(&blockingTestJob{started: started, blockFor: 30 * time.Second}); err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job never started executing")
+	}
+
+	cancelTime := time.Now()
+	pool.DrainAndStop()
+	elapsed := time.Since(cancelTime)
+
+	// The job blocks for 30s unless cancellation reaches it; shutdown must
+	// not wait that out. The bound is loose for CI scheduling noise.
+	if elapsed > 2*time.Second {
+		t.Errorf("Shutdown took %v with an in-flight job; cancellation did not propagate", elapsed)
+	}
+}
+
+// TestDynamicWorkerPool_JobDeadlineEnforced verifies the worker derives a
+// deadline from the job's ExecutionBudget, bounding a job that ignores its
+// own internal timeouts.
+func TestDynamicWorkerPool_JobDeadlineEnforced(t *testing.T) {
+	pool, q := newShutdownTestPool(t)
+	pool.Start()
+	defer pool.DrainAndStop()
+
+	var gotErr atomic.Value
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for batch := range pool.GetRouter().PulseResultChan {
+			for _, r := range batch {
+				gotErr.Store(r.Err)
+			}
+			return
+		}
+	}()
+
+	start := time.Now()
+	if err := q.Enqueue(&blockingTestJob{budget: 50 * time.Millisecond, blockFor: 30 * time.Second}); err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job result never arrived; deadline was not enforced")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Job ran %v against a 50ms budget", elapsed)
+	}
+	if err, _ := gotErr.Load().(error); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded from the budget, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newReplaceTestQueue(t *testing.T, name string) Queue {
+	t.Helper()
+	q, err := NewHybridQueue(HybridQueueConfig{
+		Name:             name,
+		RingCapacity:     1024,
+		OverflowCapacity: 4096,
+		DropPolicy:       DropPolicyReject,
+		Logger:           zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create hybrid queue: %v", err)
+	}
+	return q
+}
+
+// TestDynamicWorkerPool_ReplaceQueueUnderLoad hammers ReplaceQueue while jobs
+// flow and verifies no work is lost or double-processed: every successfully
+// enqueued job is submitted exactly once and produces exactly one result.
+// Run with the race detector to exercise the dispatcher handshake.
+func TestDynamicWorkerPool_ReplaceQueueUnderLoad(t *testing.T) {
+	config := WorkerPoolConfig{
+		MinWorkers:         4,
+		MaxWorkers:         8,
+		AdjustmentInterval: 0, // No auto-scaling during the test
+		ResultBatchSize:    16,
+		ResultBatchTimeout: time.Millisecond,
+	}
+	pool, err := NewDynamicWorkerPool(context.Background(), newReplaceTestQueue(t, "replace-0"), config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	pool.Start()
+
+	var received atomic.Int64
+	recvDone := make(chan struct{})
+	go func() {
+		defer close(recvDone)
+		for batch := range pool.GetRouter().PulseResultChan {
+			received.Add(int64(len(batch)))
+		}
+	}()
+
+	// Replacer: keep swapping in fresh queues while the producer runs.
+	stopReplace := make(chan struct{})
+	var replaceWG sync.WaitGroup
+	replaceWG.Add(1)
+	go func() {
+		defer replaceWG.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-stopReplace:
+				return
+			default:
+			}
+			pool.ReplaceQueue(newReplaceTestQueue(t, fmt.Sprintf("replace-%d", i)))
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	const total = 2000
+	var enqueued, dropped int64
+	for i := 0; i < total; i++ {
+		if err := pool.EnqueueActive(&blockingTestJob{}); err != nil {
+			dropped++ // explicitly rejected; must not be counted as submitted
+			continue
+		}
+		enqueued++
+	}
+	close(stopReplace)
+	replaceWG.Wait()
+
+	// Every accepted job must surface as a result despite the swaps.
+	deadline := time.Now().Add(10 * time.Second)
+	for received.Load() < enqueued {
+		if time.Now().After(deadline) {
+			t.Fatalf("received %d of %d results after queue replacements (dropped=%d)",
+				received.Load(), enqueued, dropped)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := pool.tasksSubmitted.Load(); got != enqueued {
+		t.Errorf("tasksSubmitted=%d, want %d (total=%d dropped=%d)", got, enqueued, total, dropped)
+	}
+	if got := received.Load(); got != enqueued {
+		t.Errorf("received %d results, want exactly %d", got, enqueued)
+	}
+
+	pool.DrainAndStop()
+	<-recvDone
+}
+
+// TestDynamicWorkerPool_PauseBlocksDispatch verifies Pause keeps queued jobs
+// off the workers until Resume.
+func TestDynamicWorkerPool_PauseBlocksDispatch(t *testing.T) {
+	config := WorkerPoolConfig{
+		MinWorkers:         2,
+		MaxWorkers:         4,
+		AdjustmentInterval: 0,
+		ResultBatchSize:    4,
+		ResultBatchTimeout: time.Millisecond,
+	}
+	q := newReplaceTestQueue(t, "pause-test")
+	pool, err := NewDynamicWorkerPool(context.Background(), q, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create worker pool: %v", err)
+	}
+	pool.Start()
+	defer pool.DrainAndStop()
+
+	pool.Pause()
+	for i := 0; i < 8; i++ {
+		if err := q.Enqueue(&blockingTestJob{}); err != nil {
+			t.Fatalf("enqueue failed: %v", err)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := pool.tasksSubmitted.Load(); got != 0 {
+		t.Fatalf("paused pool submitted %d jobs", got)
+	}
+
+	pool.Resume()
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.tasksSubmitted.Load() < 8 {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d of 8 jobs submitted after Resume", pool.tasksSubmitted.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,351 @@
+package queue
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+)
+
+func newTestRouter(depth int) (*ResultRouter, chan struct{}) {
+	stopCh := make(chan struct{})
+	config := DefaultWorkerPoolConfig()
+	config.RouterChannelDepth = depth
+	return NewResultRouter(config, nil, stopCh), stopCh
+}
+
+// TestRouteResults_SplitsByKind verifies that a mixed batch lands on the
+// channel matching each result's payload kind, and that unknown kinds are
+// dropped instead of blocking the router.
+func TestRouteResults_SplitsByKind(t *testing.T) {
+	router, stopCh := newTestRouter(8)
+	defer close(stopCh)
+
+	batch := []jobs.Result{
+		{Payload: jobs.Payload{Kind: jobs.KindPulse}},
+		{Payload: jobs.Payload{Kind: jobs.KindIntervention}},
+		{Payload: jobs.Payload{Kind: jobs.KindPulse}},
+		{Payload: jobs.Payload{Kind: jobs.KindCode}},
+		{Payload: jobs.Payload{Kind: jobs.KindPulse}},
+		{Payload: jobs.Payload{}}, // unknown: logged and dropped
+	}
+	router.RouteResults(batch)
+
+	pulse := <-router.PulseResultChan
+	intervention := <-router.InterventionResultChan
+	code := <-router.CodeResultChan
+
+	if len(pulse) != 3 {
+		t.Errorf("pulse channel received %d results, want 3", len(pulse))
+	}
+	if len(intervention) != 1 {
+		t.Errorf("intervention channel received %d results, want 1", len(intervention))
+	}
+	if len(code) != 1 {
+		t.Errorf("code channel received %d results, want 1", len(code))
+	}
+	for _, r := range pulse {
+		if r.Payload.Kind != jobs.KindPulse {
+			t.Errorf("pulse channel carries kind %v", r.Payload.Kind)
+		}
+	}
+}
+
+// TestRouteResults_SpillsAndRecoversInOrder stalls the pulse result channel
+// by never consuming it, verifies routing keeps returning promptly (workers
+// are not parked on the stalled consumer), then drains the channel and checks
+// every spilled batch is re-injected exactly once, in arrival order.
+func TestRouteResults_SpillsAndRecoversInOrder(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	config := DefaultWorkerPoolConfig()
+	config.RouterChannelDepth = 1
+	config.ResultBatchTimeout = time.Millisecond
+	config.ResultSpillAfter = 10 * time.Millisecond
+	router := NewResultRouter(config, nil, stopCh)
+
+	// Batches are numbered via Duration so order survives the spill.
+	batch := func(i int) []jobs.Result {
+		return []jobs.Result{{Payload: jobs.Payload{Kind: jobs.KindPulse}, Duration: time.Duration(i)}}
+	}
+
+	const n = 6
+	routed := make(chan struct{})
+	go func() {
+		defer close(routed)
+		for i := 0; i < n; i++ {
+			router.RouteResults(batch(i))
+		}
+	}()
+	select {
+	case <-routed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RouteResults parked on a stalled result channel")
+	}
+	if router.SpilledBatches() == 0 {
+		t.Fatal("no batches spilled despite a full channel")
+	}
+
+	// Un-stall the consumer: the recovery path must deliver the parked
+	// batches behind the one already in the channel, in order.
+	got := make([]int, 0, n)
+	deadline := time.After(5 * time.Second)
+	for len(got) < n {
+		select {
+		case b := <-router.PulseResultChan:
+			got = append(got, int(b[0].Duration))
+		case <-deadline:
+			t.Fatalf("received %v, want %d batches before the deadline", got, n)
+		}
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("batches delivered out of order: %v", got)
+		}
+	}
+}
+
+// TestResultRouter_CloseFlushesSpill checks that parked batches get a final
+// delivery attempt into the buffered channels before they close.
+func TestResultRouter_CloseFlushesSpill(t *testing.T) {
+	stopCh := make(chan struct{})
+	config := DefaultWorkerPoolConfig()
+	config.RouterChannelDepth = 4
+	config.ResultBatchTimeout = time.Millisecond
+	config.ResultSpillAfter = time.Millisecond
+	router := NewResultRouter(config, nil, stopCh)
+
+	// Park two batches directly, as if a consumer stall had spilled them.
+	router.spillBatch(router.PulseResultChan, []jobs.Result{{Payload: jobs.Payload{Kind: jobs.KindPulse}}}, "pulse")
+	router.spillBatch(router.PulseResultChan, []jobs.Result{{Payload: jobs.Payload{Kind: jobs.KindPulse}}}, "pulse")
+
+	close(stopCh)
+	router.Close()
+
+	delivered := 0
+	for range router.PulseResultChan {
+		delivered++
+	}
+	if delivered != 2 {
+		t.Fatalf("flushed %d spilled batches at shutdown, want 2", delivered)
+	}
+}
+
+// drainRouter consumes all three result channels until stopCh closes, so
+// benchmarks measure routing rather than channel backpressure.
+func drainRouter(router *ResultRouter, stopCh chan struct{}, wg *sync.WaitGroup) {
+	drain := func(ch chan []jobs.Result) {
+		defer wg.Done()
+		for {
+			select {
+			case <-ch:
+			case <-stopCh:
+				return
+			}
+		}
+	}
+	wg.Add(3)
+	go drain(router.PulseResultChan)
+	go drain(router.InterventionResultChan)
+	go drain(router.CodeResultChan)
+}
+
+// mixedResults builds n results with the rough production kind mix:
+// mostly pulses, a few interventions and code alerts.
+func mixedResults(n int) []jobs.Result {
+	results := make([]jobs.Result, n)
+	for i := range results {
+		switch i % 10 {
+		case 8:
+			results[i].Payload = jobs.GetInterventionDockerPayload()
+		case 9:
+			results[i].Payload = jobs.GetCodeSlackPayload()
+			results[i].Payload.Color = "red"
+		default:
+			results[i].Payload = jobs.GetPulseHTTPPayload()
+		}
+	}
+	return results
+}
+
+// BenchmarkRouteResults routes 100k mixed results per iteration with the
+// typed payload; only the per-kind batch slices allocate (~300 allocs per
+// 100k results routed). Compare against BenchmarkRouteResultsLegacyMaps,
+// which adds back the per-result map the old payload representation paid
+// for (~2 allocs per result).
+func BenchmarkRouteResults(b *testing.B) {
+	router, stopCh := newTestRouter(64)
+	var wg sync.WaitGroup
+	drainRouter(router, stopCh, &wg)
+
+	results := mixedResults(100_000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for start := 0; start < len(results); start += 1000 {
+			router.RouteResults(results[start : start+1000])
+		}
+	}
+	b.StopTimer()
+	close(stopCh)
+	wg.Wait()
+}
+
+// BenchmarkRouteResultsLegacyMaps is the same workload, but materializes
+// each payload's legacy map form first — the cost every routed result paid
+// when Result.Payload was a map[string]interface{}.
+func BenchmarkRouteResultsLegacyMaps(b *testing.B) {
+	router, stopCh := newTestRouter(64)
+	var wg sync.WaitGroup
+	drainRouter(router, stopCh, &wg)
+
+	results := mixedResults(100_000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range results {
+			_ = results[j].Payload.Map()
+		}
+		for start := 0; start < len(results); start += 1000 {
+			router.RouteResults(results[start : start+1000])
+		}
+	}
+	b.StopTimer()
+	close(stopCh)
+	wg.Wait()
+}
+
+// TestResultBatcher_GroupsAndFlushes feeds a mixed stream through the
+// pre-grouping fast path and verifies each kind lands whole on its channel,
+// with unknown kinds dropped, matching RouteResults semantics.
+func TestResultBatcher_GroupsAndFlushes(t *testing.T) {
+	router, stopCh := newTestRouter(8)
+	defer close(stopCh)
+
+	batcher := newResultBatcher(router, nil)
+	stream := []jobs.Result{
+		{Payload: jobs.Payload{Kind: jobs.KindPulse}},
+		{Payload: jobs.Payload{Kind: jobs.KindIntervention}},
+		{Payload: jobs.Payload{Kind: jobs.KindPulse}},
+		{Payload: jobs.Payload{Kind: jobs.KindCode}},
+		{Payload: jobs.Payload{Kind: jobs.KindPulse}},
+		{Payload: jobs.Payload{}}, // unknown: logged and dropped
+	}
+	for _, r := range stream {
+		batcher.add(r)
+	}
+	if batcher.total != 5 {
+		t.Errorf("batcher total = %d, want 5 (unknown kind dropped)", batcher.total)
+	}
+	batcher.flush()
+	if batcher.total != 0 {
+		t.Errorf("batcher total = %d after flush, want 0", batcher.total)
+	}
+
+	pulse := <-router.PulseResultChan
+	intervention := <-router.InterventionResultChan
+	code := <-router.CodeResultChan
+
+	if len(pulse) != 3 {
+		t.Errorf("pulse channel received %d results, want 3", len(pulse))
+	}
+	if len(intervention) != 1 {
+		t.Errorf("intervention channel received %d results, want 1", len(intervention))
+	}
+	if len(code) != 1 {
+		t.Errorf("code channel received %d results, want 1", len(code))
+	}
+	for _, r := range pulse {
+		if r.Payload.Kind != jobs.KindPulse {
+			t.Errorf("pulse channel carries kind %v", r.Payload.Kind)
+		}
+	}
+}
+
+// TestResultBatcher_SteadyStateAllocs drives full add/flush/recycle cycles
+// and asserts the path is allocation-free: batch slices and their pointer
+// boxes both circulate through the router's pools. A small tolerance covers
+// the race detector's instrumentation allocations.
+func TestResultBatcher_SteadyStateAllocs(t *testing.T) {
+	router, stopCh := newTestRouter(8)
+	defer close(stopCh)
+
+	batcher := newResultBatcher(router, nil)
+	results := mixedResults(512)
+	cycle := func() {
+		for _, r := range results {
+			batcher.add(r)
+		}
+		batcher.flush()
+		for drained := false; !drained; {
+			select {
+			case b := <-router.PulseResultChan:
+				router.RecycleBatch(b)
+			case b := <-router.InterventionResultChan:
+				router.RecycleBatch(b)
+			case b := <-router.CodeResultChan:
+				router.RecycleBatch(b)
+			default:
+				drained = true
+			}
+		}
+	}
+
+	// Warm the pool so the measured runs only exercise reuse.
+	for i := 0; i < 4; i++ {
+		cycle()
+	}
+	if avg := testing.AllocsPerRun(100, cycle); avg > 3 {
+		t.Errorf("steady-state cycle allocates %.1f times for 512 results, want ~0", avg)
+	}
+}
+
+// drainRouterRecycling consumes all three result channels and hands every
+// batch back to the router's pool, the way a cooperating consumer would.
+func drainRouterRecycling(router *ResultRouter, stopCh chan struct{}, wg *sync.WaitGroup) {
+	drain := func(ch chan []jobs.Result) {
+		defer wg.Done()
+		for {
+			select {
+			case b := <-ch:
+				router.RecycleBatch(b)
+			case <-stopCh:
+				return
+			}
+		}
+	}
+	wg.Add(3)
+	go drain(router.PulseResultChan)
+	go drain(router.InterventionResultChan)
+	go drain(router.CodeResultChan)
+}
+
+// BenchmarkResultBatcher pushes one million pre-tagged results per iteration
+// through the pre-grouping fast path with consumers recycling batches, the
+// configuration the resultProcessor runs in production. Compare against
+// BenchmarkRouteResults, which pays the classification pass and three
+// grouped-slice allocations per mixed batch.
+func BenchmarkResultBatcher(b *testing.B) {
+	router, stopCh := newTestRouter(64)
+	var wg sync.WaitGroup
+	drainRouterRecycling(router, stopCh, &wg)
+
+	batcher := newResultBatcher(router, nil)
+	results := mixedResults(100_000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for pass := 0; pass < 10; pass++ { // 1M results per iteration
+			for _, r := range results {
+				if batcher.add(r) >= 512 {
+					batcher.flush()
+				}
+			}
+		}
+		batcher.flush()
+	}
+	b.StopTimer()
+	close(stopCh)
+	wg.Wait()
+}
@@ -0,0 +1,215 @@
+package sinks
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cpra/internal/interning"
+	"cpra/internal/jobs"
+)
+
+// DefaultMaxFileBytes rotates the NDJSON file when it would exceed this
+// size, unless the config overrides it.
+const DefaultMaxFileBytes = 64 << 20
+
+// NDJSONConfig configures a rotating NDJSON file sink.
+type NDJSONConfig struct {
+	// Path is the file results are appended to, one JSON object per line.
+	Path string
+	// MaxBytes rotates the file before a write would push it past this
+	// size; zero or negative uses DefaultMaxFileBytes.
+	MaxBytes int64
+	// MaxAge rotates the file once it has been open this long regardless
+	// of size, so quiet fleets still produce bounded, time-aligned files.
+	// Zero disables age-based rotation.
+	MaxAge time.Duration
+	// Buffer is the batch queue depth between the result routers and the
+	// writer goroutine; zero uses the package default.
+	Buffer int
+}
+
+// ndjsonRecord is the per-result line format. Monitor and labels are
+// resolved from the entity when the controller has installed a resolver.
+type ndjsonRecord struct {
+	Time       string           `json:"time"`
+	Monitor    string           `json:"monitor,omitempty"`
+	EntityID   uint32           `json:"entity_id"`
+	Kind       string           `json:"kind"`
+	Driver     string           `json:"driver,omitempty"`
+	Color      string           `json:"color,omitempty"`
+	Success    bool             `json:"success"`
+	Error      string           `json:"error,omitempty"`
+	DurationMS int64            `json:"duration_ms,omitempty"`
+	Attempts   int              `json:"attempts,omitempty"`
+	Labels     interning.Labels `json:"labels,omitempty"`
+}
+
+// NDJSONSink appends one JSON line per result to a file, rotating by size
+// and optionally by age. Writes happen on a dedicated goroutine fed by a
+// bounded queue; Close flushes and closes the current file.
+type NDJSONSink struct {
+	pump
+	cfg NDJSONConfig
+
+	// Writer-goroutine state; never touched from Accept.
+	file     *os.File
+	w        *bufio.Writer
+	size     int64
+	openedAt time.Time
+}
+
+// NewNDJSONSink creates the sink and starts its writer. The file is opened
+// lazily on the first write, so a sink that never sees results leaves no
+// empty file behind.
+func NewNDJSONSink(cfg NDJSONConfig) (*NDJSONSink, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("ndjson sink path is required")
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultMaxFileBytes
+	}
+	s := &NDJSONSink{pump: newPump(cfg.Buffer), cfg: cfg}
+	go s.run()
+	return s, nil
+}
+
+// Accept implements ResultSink.
+func (s *NDJSONSink) Accept(batch []jobs.Result) { s.accept(batch) }
+
+// Close implements ResultSink: it stops accepting, drains the queue, and
+// flushes and closes the file.
+func (s *NDJSONSink) Close() error {
+	s.pump.close()
+	return nil
+}
+
+// run drains the queue until Close, then performs a final drain so every
+// batch accepted before Close reaches the file.
+func (s *NDJSONSink) run() {
+	defer func() {
+		s.closeFile()
+		close(s.flushed)
+	}()
+	for {
+		select {
+		case batch := <-s.batches:
+			s.write(batch)
+		case <-s.done:
+			for {
+				select {
+				case batch := <-s.batches:
+					s.write(batch)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write appends one line per result, rotating beforehand when the next
+// line would exceed the size limit or the file has outlived MaxAge.
+func (s *NDJSONSink) write(batch []jobs.Result) {
+	for i := range batch {
+		line, err := json.Marshal(s.record(&batch[i]))
+		if err != nil {
+			s.dropped.Add(1)
+			continue
+		}
+		line = append(line, '\n')
+		if err := s.ensureFile(int64(len(line))); err != nil {
+			s.dropped.Add(1)
+			continue
+		}
+		if _, err := s.w.Write(line); err != nil {
+			s.dropped.Add(1)
+			s.closeFile()
+			continue
+		}
+		s.size += int64(len(line))
+	}
+	if s.w != nil {
+		_ = s.w.Flush()
+	}
+}
+
+// record builds the line for one result.
+func (s *NDJSONSink) record(r *jobs.Result) ndjsonRecord {
+	rec := ndjsonRecord{
+		Time:     time.Now().UTC().Format(time.RFC3339Nano),
+		EntityID: uint32(r.Ent.ID()),
+		Kind:     r.Payload.Kind.String(),
+		Driver:   r.Payload.Driver,
+		Color:    r.Payload.Color,
+		Success:  r.Err == nil,
+		Attempts: r.Payload.Attempts,
+	}
+	if r.Err != nil {
+		rec.Error = r.Err.Error()
+	}
+	if r.Duration > 0 {
+		rec.DurationMS = r.Duration.Milliseconds()
+	}
+	if name, labels, ok := s.lookup(r.Ent); ok {
+		rec.Monitor = name
+		rec.Labels = labels
+	}
+	return rec
+}
+
+// ensureFile opens the file if needed and rotates when the pending write
+// would exceed the size limit or the file is older than MaxAge.
+func (s *NDJSONSink) ensureFile(pending int64) error {
+	if s.file != nil {
+		overSize := s.size > 0 && s.size+pending > s.cfg.MaxBytes
+		overAge := s.cfg.MaxAge > 0 && time.Since(s.openedAt) >= s.cfg.MaxAge
+		if overSize || overAge {
+			s.rotate()
+		}
+	}
+	if s.file != nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.cfg.Path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.cfg.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	s.file = f
+	s.w = bufio.NewWriter(f)
+	s.size = info.Size()
+	s.openedAt = time.Now()
+	return nil
+}
+
+// rotate closes the current file and renames it aside with a timestamp
+// suffix; the next write reopens the configured path fresh.
+func (s *NDJSONSink) rotate() {
+	s.closeFile()
+	rotated := s.cfg.Path + "." + time.Now().UTC().Format("20060102T150405.000000000")
+	_ = os.Rename(s.cfg.Path, rotated)
+}
+
+// closeFile flushes and closes the current file, if open.
+func (s *NDJSONSink) closeFile() {
+	if s.w != nil {
+		_ = s.w.Flush()
+		s.w = nil
+	}
+	if s.file != nil {
+		_ = s.file.Close()
+		s.file = nil
+	}
+	s.size = 0
+}
@@ -0,0 +1,122 @@
+package sinks
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cpra/internal/interning"
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// testResolver resolves every entity to the same monitor name and labels.
+func testResolver(name string, labels map[string]string) MonitorResolver {
+	interned := interning.InternLabels(labels)
+	return func(_ ecs.Entity) (string, interning.Labels, bool) {
+		return name, interned, true
+	}
+}
+
+func TestNDJSONSink_WritesWellFormedLines(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+	sink, err := NewNDJSONSink(NDJSONConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewNDJSONSink failed: %v", err)
+	}
+	sink.SetResolver(testResolver("web-1", map[string]string{"team": "payments"}))
+
+	world := ecs.NewWorld()
+	ent := world.NewEntity()
+	sink.Accept([]jobs.Result{
+		{Ent: ent, Payload: jobs.Payload{Kind: jobs.KindPulse, Driver: "http"}, Duration: 12 * time.Millisecond},
+		{Ent: ent, Err: errors.New("connection refused"), Payload: jobs.Payload{Kind: jobs.KindPulse, Driver: "http"}},
+		{Ent: ent, Payload: jobs.Payload{Kind: jobs.KindCode, Color: "red"}},
+	})
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open output: %v", err)
+	}
+	defer f.Close()
+
+	var lines []ndjsonRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec ndjsonRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("malformed line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, rec)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+
+	if !lines[0].Success || lines[0].DurationMS != 12 || lines[0].Driver != "http" {
+		t.Errorf("success line = %+v", lines[0])
+	}
+	if lines[1].Success || lines[1].Error != "connection refused" {
+		t.Errorf("failure line = %+v", lines[1])
+	}
+	if lines[2].Kind != "code" || lines[2].Color != "red" {
+		t.Errorf("code line = %+v", lines[2])
+	}
+	for i, rec := range lines {
+		if rec.Monitor != "web-1" {
+			t.Errorf("line %d monitor = %q, want web-1", i, rec.Monitor)
+		}
+		if v, ok := rec.Labels.Get("team"); !ok || v != "payments" {
+			t.Errorf("line %d labels = %v, want team=payments", i, rec.Labels)
+		}
+	}
+}
+
+func TestNDJSONSink_RotatesAtSizeLimit(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.ndjson")
+	// Each line is well over 100 bytes, so a 256-byte cap forces a
+	// rotation within a handful of results.
+	sink, err := NewNDJSONSink(NDJSONConfig{Path: path, MaxBytes: 256})
+	if err != nil {
+		t.Fatalf("NewNDJSONSink failed: %v", err)
+	}
+
+	batch := make([]jobs.Result, 8)
+	for i := range batch {
+		batch[i] = jobs.Result{Payload: jobs.Payload{Kind: jobs.KindPulse, Driver: "http"}}
+	}
+	sink.Accept(batch)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	rotated, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("glob failed: %v", err)
+	}
+	if len(rotated) == 0 {
+		t.Fatal("expected at least one rotated file")
+	}
+	// Every rotated file and the live file must stay near the cap, and no
+	// line may be split across files.
+	for _, name := range append(rotated, path) {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if len(data) == 0 || data[len(data)-1] != '\n' {
+			t.Errorf("%s does not end on a line boundary", name)
+		}
+	}
+}
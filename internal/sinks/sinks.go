@@ -0,0 +1,118 @@
+// Package sinks streams raw job results to time-series friendly outputs —
+// a rotating NDJSON file or a statsd/UDP endpoint — so operators can feed
+// every check outcome into their metrics pipeline instead of only seeing
+// alerts on transitions. The controller taps each result router and hands
+// batches to the configured sinks; every sink owns a bounded queue drained
+// by a dedicated writer goroutine, so accepting a batch never delays
+// applying results to the ECS world. When a sink's queue is full the batch
+// is dropped and its results counted — sinks are an observability feed,
+// not a delivery guarantee.
+package sinks
+
+import (
+	"sync/atomic"
+
+	"cpra/internal/interning"
+	"cpra/internal/jobs"
+
+	"github.com/mlange-42/ark/ecs"
+)
+
+// ResultSink receives routed result batches from the controller.
+type ResultSink interface {
+	// Accept observes one result batch. It must never block, and the slice
+	// is only valid for the duration of the call — the router recycles
+	// batches — so implementations copy what they keep.
+	Accept(batch []jobs.Result)
+	// Dropped returns how many results were discarded on a full queue.
+	Dropped() uint64
+	// Close flushes buffered output and releases resources.
+	Close() error
+}
+
+// MonitorResolver maps a result's entity to its monitor name and labels.
+// Implementations must be safe to call from sink goroutines.
+type MonitorResolver func(ent ecs.Entity) (name string, labels interning.Labels, ok bool)
+
+// ResolverAware is implemented by sinks that tag their output with monitor
+// names; the controller installs its entity index before starting. Sinks
+// without a resolver emit entity IDs only.
+type ResolverAware interface {
+	SetResolver(MonitorResolver)
+}
+
+// defaultQueueDepth is the per-sink batch queue between the result routers
+// and the writer goroutine, used when a sink config leaves Buffer unset.
+const defaultQueueDepth = 256
+
+// pump is the accept-side machinery shared by the built-in sinks: a bounded
+// batch queue, drop accounting, and the close/flush handshake. The owning
+// sink drains p.batches in its writer goroutine.
+type pump struct {
+	batches chan []jobs.Result
+	done    chan struct{} // closed by Close to stop accepting
+	flushed chan struct{} // closed by the writer once drained
+	dropped atomic.Uint64
+	resolve atomic.Pointer[MonitorResolver]
+}
+
+func newPump(buffer int) pump {
+	if buffer <= 0 {
+		buffer = defaultQueueDepth
+	}
+	return pump{
+		batches: make(chan []jobs.Result, buffer),
+		done:    make(chan struct{}),
+		flushed: make(chan struct{}),
+	}
+}
+
+// accept copies the batch and queues it without blocking; a full queue
+// drops the batch and counts its results.
+func (p *pump) accept(batch []jobs.Result) {
+	if len(batch) == 0 {
+		return
+	}
+	select {
+	case <-p.done:
+		p.dropped.Add(uint64(len(batch)))
+		return
+	default:
+	}
+	copied := make([]jobs.Result, len(batch))
+	copy(copied, batch)
+	select {
+	case p.batches <- copied:
+	default:
+		p.dropped.Add(uint64(len(batch)))
+	}
+}
+
+// SetResolver implements ResolverAware for the owning sink.
+func (p *pump) SetResolver(r MonitorResolver) {
+	p.resolve.Store(&r)
+}
+
+// lookup resolves the entity through the installed resolver, if any.
+func (p *pump) lookup(ent ecs.Entity) (string, interning.Labels, bool) {
+	r := p.resolve.Load()
+	if r == nil {
+		return "", nil, false
+	}
+	return (*r)(ent)
+}
+
+// Dropped returns how many results were discarded on a full queue.
+func (p *pump) Dropped() uint64 { return p.dropped.Load() }
+
+// close stops accepting and waits for the writer to drain.
+func (p *pump) close() (alreadyClosed bool) {
+	select {
+	case <-p.done:
+		return true
+	default:
+	}
+	close(p.done)
+	<-p.flushed
+	return false
+}
@@ -0,0 +1,143 @@
+package sinks
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"cpra/internal/jobs"
+)
+
+// DefaultStatsdPrefix namespaces the emitted metrics when the config does
+// not override it.
+const DefaultStatsdPrefix = "cpra"
+
+// StatsdConfig configures a statsd/UDP metrics sink.
+type StatsdConfig struct {
+	// Addr is the host:port of the UDP statsd listener.
+	Addr string
+	// Prefix namespaces the metric names; empty uses DefaultStatsdPrefix.
+	Prefix string
+	// Buffer is the batch queue depth between the result routers and the
+	// writer goroutine; zero uses the package default.
+	Buffer int
+}
+
+// StatsdSink emits one datagram per result with per-monitor success and
+// latency metrics in dogstatsd format, tagging each metric with the result
+// kind, the monitor name, and the monitor's labels:
+//
+//	cpra.check.success:1|c|#kind:pulse,monitor:web-1,team:payments
+//	cpra.check.latency:12|ms|#kind:pulse,monitor:web-1,team:payments
+//
+// Delivery is fire-and-forget UDP; send errors count as drops.
+type StatsdSink struct {
+	pump
+	prefix string
+	conn   net.Conn
+}
+
+// NewStatsdSink dials the UDP endpoint and starts the writer.
+func NewStatsdSink(cfg StatsdConfig) (*StatsdSink, error) {
+	if cfg.Addr == "" {
+		return nil, fmt.Errorf("statsd sink address is required")
+	}
+	conn, err := net.Dial("udp", cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial statsd endpoint: %w", err)
+	}
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = DefaultStatsdPrefix
+	}
+	s := &StatsdSink{pump: newPump(cfg.Buffer), prefix: prefix, conn: conn}
+	go s.run()
+	return s, nil
+}
+
+// Accept implements ResultSink.
+func (s *StatsdSink) Accept(batch []jobs.Result) { s.accept(batch) }
+
+// Close implements ResultSink: it stops accepting, drains the queue, and
+// closes the socket.
+func (s *StatsdSink) Close() error {
+	if s.pump.close() {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+// run drains the queue until Close, then performs a final drain.
+func (s *StatsdSink) run() {
+	defer close(s.flushed)
+	for {
+		select {
+		case batch := <-s.batches:
+			s.send(batch)
+		case <-s.done:
+			for {
+				select {
+				case batch := <-s.batches:
+					s.send(batch)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send emits one datagram per result; the success counter and latency
+// timer share a packet, newline-separated per the statsd wire format.
+func (s *StatsdSink) send(batch []jobs.Result) {
+	var b strings.Builder
+	for i := range batch {
+		b.Reset()
+		s.writeMetrics(&b, &batch[i])
+		if _, err := s.conn.Write([]byte(b.String())); err != nil {
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// writeMetrics renders the metrics for one result into b.
+func (s *StatsdSink) writeMetrics(b *strings.Builder, r *jobs.Result) {
+	tags := s.tags(r)
+
+	b.WriteString(s.prefix)
+	if r.Err == nil {
+		b.WriteString(".check.success:1|c")
+	} else {
+		b.WriteString(".check.failure:1|c")
+	}
+	b.WriteString(tags)
+
+	if r.Duration > 0 {
+		b.WriteByte('\n')
+		b.WriteString(s.prefix)
+		b.WriteString(".check.latency:")
+		b.WriteString(strconv.FormatInt(r.Duration.Milliseconds(), 10))
+		b.WriteString("|ms")
+		b.WriteString(tags)
+	}
+}
+
+// tags renders the dogstatsd tag suffix: result kind, monitor name when a
+// resolver is installed, then the monitor's labels.
+func (s *StatsdSink) tags(r *jobs.Result) string {
+	var b strings.Builder
+	b.WriteString("|#kind:")
+	b.WriteString(r.Payload.Kind.String())
+	if name, labels, ok := s.lookup(r.Ent); ok {
+		b.WriteString(",monitor:")
+		b.WriteString(name)
+		for _, l := range labels {
+			b.WriteByte(',')
+			b.WriteString(l.Key)
+			b.WriteByte(':')
+			b.WriteString(l.Value)
+		}
+	}
+	return b.String()
+}
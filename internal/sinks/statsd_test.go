@@ -0,0 +1,62 @@
+package sinks
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"cpra/internal/jobs"
+)
+
+func TestStatsdSink_EmitsDatagrams(t *testing.T) {
+	t.Parallel()
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	sink, err := NewStatsdSink(StatsdConfig{Addr: listener.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("NewStatsdSink failed: %v", err)
+	}
+	sink.SetResolver(testResolver("web-1", map[string]string{"team": "payments"}))
+
+	sink.Accept([]jobs.Result{
+		{Payload: jobs.Payload{Kind: jobs.KindPulse}, Duration: 42 * time.Millisecond},
+		{Err: errors.New("boom"), Payload: jobs.Payload{Kind: jobs.KindPulse}},
+	})
+
+	var metrics []string
+	buf := make([]byte, 1500)
+	for len(metrics) < 3 {
+		_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := listener.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("expected 3 metrics, got %v after: %v", metrics, err)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(buf[:n])), "\n") {
+			metrics = append(metrics, line)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	wantTags := "|#kind:pulse,monitor:web-1,team:payments"
+	want := []string{
+		"cpra.check.success:1|c" + wantTags,
+		"cpra.check.latency:42|ms" + wantTags,
+		"cpra.check.failure:1|c" + wantTags,
+	}
+	if len(metrics) != len(want) {
+		t.Fatalf("got %d metrics %v, want %d", len(metrics), metrics, len(want))
+	}
+	for i, m := range metrics {
+		if m != want[i] {
+			t.Errorf("metric %d = %q, want %q", i, m, want[i])
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package tracing sets up the optional OpenTelemetry trace pipeline. CPRA
+// emits spans for a sampled (or per-monitor flagged) subset of pulse checks,
+// covering the full lifecycle: dispatch, queue wait, each execute attempt,
+// and result application. Tracing is off unless an exporter endpoint is
+// configured; the hot paths then pay only nil checks.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Config configures the trace pipeline.
+type Config struct {
+	// Endpoint is the OTLP/gRPC collector address (host:port). Empty
+	// disables tracing entirely.
+	Endpoint string
+
+	// SampleRate traces this fraction of pulse dispatches for monitors
+	// without an explicit `trace: true`; zero traces only flagged monitors.
+	// Flagged monitors are always traced.
+	SampleRate float64
+
+	// Insecure dials the collector without TLS — the usual setup for a
+	// localhost collector sidecar.
+	Insecure bool
+}
+
+// Setup builds a tracer provider exporting to the configured endpoint. The
+// exporter dials lazily, so an unreachable collector does not block startup;
+// spans are dropped until it connects. The caller owns the provider's
+// Shutdown.
+func Setup(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("cpra")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	// Sampling is decided per dispatch in the pulse system (flag or rate),
+	// so every span that reaches the SDK is kept.
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(5*time.Second)),
+		sdktrace.WithResource(res),
+	), nil
+}